@@ -7,24 +7,42 @@ import (
 	"log"
 	"net/http"
 	"os"
-	"path/filepath"
+	"skoola/internal/access"
+	"skoola/internal/alumni"
+	"skoola/internal/audit"
 	"skoola/internal/auth"
 	"skoola/internal/connection"
+	"skoola/internal/deskripsi"
 	"skoola/internal/ekstrakurikuler"
 	"skoola/internal/foundation"
 	"skoola/internal/jabatan"
+	"skoola/internal/jadwal"
 	"skoola/internal/jenisujian"
 	"skoola/internal/jenjang"
+	"skoola/internal/jurnalmengajar"
+	"skoola/internal/kalender"
 	"skoola/internal/kelompokmapel"
+	"skoola/internal/kenaikan"
+	"skoola/internal/kktp"
+	"skoola/internal/kuncinilai"
 	"skoola/internal/kurikulum"
+	"skoola/internal/leger"
 	"skoola/internal/matapelajaran"
+	"skoola/internal/migration"
+	"skoola/internal/mutasi"
+	"skoola/internal/nilaiakhir"
 	"skoola/internal/papersize"
 	"skoola/internal/pembelajaran"
+	"skoola/internal/pengumuman"
 	"skoola/internal/penilaian"
 	"skoola/internal/penilaiansumatif"
+	"skoola/internal/portal"
 	"skoola/internal/presensi"
 	"skoola/internal/prestasi"
 	"skoola/internal/profile"
+	"skoola/internal/rapor"
+	"skoola/internal/rekappresensi"
+	"skoola/internal/role"
 	"skoola/internal/rombel"
 	"skoola/internal/student"
 	"skoola/internal/tahunajaran"
@@ -32,6 +50,8 @@ import (
 	"skoola/internal/tenant"
 	"skoola/internal/tingkatan"
 	"skoola/internal/ujianmaster"
+	"skoola/internal/walimurid"
+	"skoola/pkg/mailer"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -42,30 +62,6 @@ import (
 	_ "github.com/lib/pq"
 )
 
-func runPublicMigrations(db *sql.DB) error {
-	log.Println("Memeriksa dan menjalankan migrasi untuk skema public...")
-
-	publicMigrations := []string{
-		"./db/migrations/005_add_foundations.sql",
-	}
-
-	for _, path := range publicMigrations {
-		absPath, err := filepath.Abs(path)
-		if err != nil {
-			return fmt.Errorf("gagal mendapatkan path absolut untuk %s: %w", path, err)
-		}
-		migrationSQL, err := os.ReadFile(absPath)
-		if err != nil {
-			return fmt.Errorf("gagal membaca file migrasi %s: %w", path, err)
-		}
-		if _, err := db.ExecContext(context.Background(), string(migrationSQL)); err != nil {
-			log.Printf("Info: Gagal menjalankan migrasi %s, mungkin sudah ada: %v\n", path, err)
-		}
-	}
-	log.Println("Pemeriksaan migrasi public selesai.")
-	return nil
-}
-
 func main() {
 	err := godotenv.Load()
 	if err != nil {
@@ -91,12 +87,45 @@ func main() {
 	}
 	fmt.Println("Berhasil terhubung ke database!")
 
-	if err := runPublicMigrations(db); err != nil {
+	migrationService := migration.NewService(migration.NewRepository(db), db, migration.DefaultDir)
+	log.Println("Memeriksa dan menjalankan migrasi untuk skema public...")
+	if _, err := migrationService.RunPublic(context.Background()); err != nil {
 		log.Fatalf("Gagal menjalankan migrasi public: %v", err)
 	}
+	log.Println("Pemeriksaan migrasi public selesai.")
 
 	validate := validator.New()
 
+	// Mailer: MAIL_DRIVER=smtp untuk produksi, selain itu email ditulis ke file (pengembangan lokal).
+	mailFrom := os.Getenv("MAIL_FROM")
+	if mailFrom == "" {
+		mailFrom = "Skoola <no-reply@skoola.local>"
+	}
+	var mail mailer.Mailer
+	if os.Getenv("MAIL_DRIVER") == "smtp" {
+		var err error
+		mail, err = mailer.NewSMTPMailer(mailer.SMTPConfig{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     os.Getenv("SMTP_PORT"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     mailFrom,
+		})
+		if err != nil {
+			log.Fatal("Konfigurasi MAIL_FROM tidak valid:", err)
+		}
+	} else {
+		mailDir := os.Getenv("MAIL_FILE_DIR")
+		if mailDir == "" {
+			mailDir = "tmp/mail"
+		}
+		mail = mailer.NewFileMailer(mailDir, mailFrom)
+	}
+	passwordResetURL := os.Getenv("PASSWORD_RESET_URL")
+	if passwordResetURL == "" {
+		passwordResetURL = "http://localhost:5173/reset-password"
+	}
+
 	// Repositories
 	naunganRepo := foundation.NewRepository(db)
 	teacherRepo := teacher.NewRepository(db)
@@ -104,6 +133,7 @@ func main() {
 	tenantRepo := tenant.NewRepository(db)
 	profileRepo := profile.NewRepository(db)
 	studentHistoryRepo := student.NewHistoryRepository(db)
+	studentAccountRepo := student.NewAccountRepository(db)
 	jenjangRepo := jenjang.NewRepository(db)
 	jabatanRepo := jabatan.NewRepository(db)
 	tingkatanRepo := tingkatan.NewRepository(db)
@@ -113,22 +143,49 @@ func main() {
 	rombelRepo := rombel.NewRepository(db)
 	pembelajaranRepo := pembelajaran.NewRepository(db)
 	penilaianRepo := penilaian.NewRepository(db)
+	remedialRepo := penilaian.NewRemedialRepository(db)
+	kktpRepo := kktp.NewRepository(db)
+	kunciNilaiRepo := kuncinilai.NewRepository(db)
 	kelompokMapelRepo := kelompokmapel.NewRepository(db)
 	jenisUjianRepo := jenisujian.NewRepository(db)
 	penilaianSumatifRepo := penilaiansumatif.NewRepository(db)
+	kalenderRepo := kalender.NewRepository(db)
 	presensiRepo := presensi.NewRepository(db)
+	presensiJamRepo := presensi.NewJamRepository(db)
+	rekapPresensiRepo := rekappresensi.NewRepository(db)
+	jurnalMengajarRepo := jurnalmengajar.NewRepository(db)
+	jadwalRepo := jadwal.NewRepository(db)
 	ekstrakurikulerRepo := ekstrakurikuler.NewRepository(db)
 	prestasiRepo := prestasi.NewRepository(db)
 	ujianMasterRepo := ujianmaster.NewRepository(db)
 	paperSizeRepo := papersize.NewRepository(db)
+	sessionRepo := auth.NewRepository(db)
+	loginAttemptRepo := auth.NewAttemptRepository(db)
+	roleRepo := role.NewRepository(db)
+	accessRepo := access.NewRepository(db)
+	auditRepo := audit.NewRepository(db)
+	pengumumanRepo := pengumuman.NewRepository(db)
+	waliMuridRepo := walimurid.NewRepository(db)
+	portalRepo := portal.NewRepository(db)
+	nilaiAkhirRepo := nilaiakhir.NewRepository(db)
+	raporRepo := rapor.NewRepository(db)
+	deskripsiRepo := deskripsi.NewRepository(db)
+	legerRepo := leger.NewRepository(db)
+	kenaikanRepo := kenaikan.NewRepository(db)
+	tahunAjaranCloneRepo := tahunajaran.NewCloneRepository(db)
+	mutasiRepo := mutasi.NewRepository(db)
+	alumniRepo := alumni.NewRepository(db)
 
 	// Services
-	authService := auth.NewService(teacherRepo, tenantRepo, jwtSecret)
+	accessService := access.NewService(accessRepo)
+	auditService := audit.NewService(auditRepo)
+	authService := auth.NewService(sessionRepo, loginAttemptRepo, teacherRepo, tenantRepo, mail, validate, jwtSecret, passwordResetURL)
 	naunganService := foundation.NewService(naunganRepo, validate)
-	teacherService := teacher.NewService(teacherRepo, tahunAjaranRepo, validate, db)
-	studentService := student.NewService(studentRepo, studentHistoryRepo, validate, db)
-	studentHistoryService := student.NewHistoryService(studentHistoryRepo, validate)
-	tenantService := tenant.NewService(tenantRepo, teacherRepo, validate, db)
+	teacherService := teacher.NewService(teacherRepo, tahunAjaranRepo, sessionRepo, auditService, validate, db)
+	studentService := student.NewService(studentRepo, studentHistoryRepo, auditService, validate, db)
+	studentHistoryService := student.NewHistoryService(studentHistoryRepo, auditService, validate)
+	studentAccountService := student.NewAccountService(studentAccountRepo, auditService, validate)
+	tenantService := tenant.NewService(tenantRepo, teacherRepo, sessionRepo, migrationService, validate, db)
 	profileService := profile.NewService(profileRepo, validate)
 	jenjangService := jenjang.NewService(jenjangRepo, validate)
 	jabatanService := jabatan.NewService(jabatanRepo, validate)
@@ -137,44 +194,86 @@ func main() {
 	mataPelajaranService := matapelajaran.NewService(mataPelajaranRepo, validate)
 	kelompokMapelService := kelompokmapel.NewService(kelompokMapelRepo, mataPelajaranRepo, validate)
 	kurikulumService := kurikulum.NewService(kurikulumRepo, validate)
-	rombelService := rombel.NewService(rombelRepo, validate)
-	pembelajaranService := pembelajaran.NewService(pembelajaranRepo, validate)
-	penilaianService := penilaian.NewService(penilaianRepo, validate)
+	rombelService := rombel.NewService(rombelRepo, auditService, validate)
+	pembelajaranService := pembelajaran.NewService(pembelajaranRepo, accessService, validate)
+	kktpService := kktp.NewService(kktpRepo, validate)
+	kunciNilaiService := kuncinilai.NewService(kunciNilaiRepo, accessRepo, accessService, auditService, validate)
+	penilaianService := penilaian.NewService(penilaianRepo, kktpService, kunciNilaiService, accessService, auditService, validate)
 	jenisUjianService := jenisujian.NewService(jenisUjianRepo, validate)
-	penilaianSumatifService := penilaiansumatif.NewService(penilaianSumatifRepo, validate)
-	presensiService := presensi.NewService(presensiRepo, validate)
+	penilaianSumatifService := penilaiansumatif.NewService(penilaianSumatifRepo, kunciNilaiService, accessService, validate)
+	kalenderService := kalender.NewService(kalenderRepo, auditService, validate)
+	presensiService := presensi.NewService(presensiRepo, kalenderService, auditService, validate)
+	presensiJamService := presensi.NewJamService(presensiJamRepo, kalenderService, accessService, auditService, validate)
+	rekapPresensiService := rekappresensi.NewService(rekapPresensiRepo, kalenderService, profileRepo, paperSizeRepo, accessService)
+	jurnalMengajarService := jurnalmengajar.NewService(jurnalMengajarRepo, kalenderService, accessService, auditService, validate)
+	jadwalService := jadwal.NewService(jadwalRepo, profileRepo, paperSizeRepo, auditService, validate)
 	ekstrakurikulerService := ekstrakurikuler.NewService(ekstrakurikulerRepo, validate)
 	prestasiService := prestasi.NewService(prestasiRepo, validate)
-	ujianMasterService := ujianmaster.NewService(ujianMasterRepo, rombelService)
+	ujianMasterService := ujianmaster.NewService(ujianMasterRepo, rombelService, auditService)
 	paperSizeService := papersize.NewService(paperSizeRepo, validate)
+	roleService := role.NewService(roleRepo, validate)
+	pengumumanService := pengumuman.NewService(pengumumanRepo, auditService, validate)
+	waliMuridService := walimurid.NewService(waliMuridRepo, auditService, validate)
+	portalService := portal.NewService(portalRepo, tahunAjaranRepo)
+	nilaiAkhirService := nilaiakhir.NewService(nilaiAkhirRepo, kktpService, accessService, auditService, validate)
+	deskripsiService := deskripsi.NewService(deskripsiRepo, kunciNilaiService, accessService, auditService, validate)
+	raporService := rapor.NewService(raporRepo, nilaiAkhirService, deskripsiService, profileRepo, paperSizeRepo, accessService)
+	legerService := leger.NewService(legerRepo, nilaiAkhirService, accessService)
+	kenaikanService := kenaikan.NewService(kenaikanRepo, auditService, validate)
+	tahunAjaranCloneService := tahunajaran.NewCloneService(tahunAjaranCloneRepo, auditService)
+	mutasiService := mutasi.NewService(mutasiRepo, profileRepo, auditService, validate)
+	alumniService := alumni.NewService(alumniRepo, nilaiAkhirService, auditService, validate)
 
 	// Handlers
 	authHandler := auth.NewHandler(authService)
-	authMiddleware := auth.NewMiddleware(jwtSecret)
+	authMiddleware := auth.NewMiddleware(jwtSecret, sessionRepo, roleRepo)
 	naunganHandler := foundation.NewHandler(naunganService)
 	teacherHandler := teacher.NewHandler(teacherService)
 	studentHandler := student.NewHandler(studentService)
 	studentHistoryHandler := student.NewHistoryHandler(studentHistoryService)
+	studentAccountHandler := student.NewAccountHandler(studentAccountService)
 	tenantHandler := tenant.NewHandler(tenantService)
 	profileHandler := profile.NewHandler(profileService)
 	jenjangHandler := jenjang.NewHandler(jenjangService)
 	jabatanHandler := jabatan.NewHandler(jabatanService)
 	tingkatanHandler := tingkatan.NewHandler(tingkatanService)
 	tahunAjaranHandler := tahunajaran.NewHandler(tahunAjaranService)
+	tahunAjaranCloneHandler := tahunajaran.NewCloneHandler(tahunAjaranCloneService)
 	mataPelajaranHandler := matapelajaran.NewHandler(mataPelajaranService)
 	kelompokMapelHandler := kelompokmapel.NewHandler(kelompokMapelService)
 	kurikulumHandler := kurikulum.NewHandler(kurikulumService)
 	rombelHandler := rombel.NewHandler(rombelService)
 	pembelajaranHandler := pembelajaran.NewHandler(pembelajaranService)
 	penilaianHandler := penilaian.NewHandler(penilaianService)
+	remedialService := penilaian.NewRemedialService(remedialRepo, kunciNilaiService, accessService, auditService, validate)
+	remedialHandler := penilaian.NewRemedialHandler(remedialService)
+	kktpHandler := kktp.NewHandler(kktpService)
+	kunciNilaiHandler := kuncinilai.NewHandler(kunciNilaiService)
 	jenisUjianHandler := jenisujian.NewHandler(jenisUjianService)
 	penilaianSumatifHandler := penilaiansumatif.NewHandler(penilaianSumatifService)
+	kalenderHandler := kalender.NewHandler(kalenderService)
 	presensiHandler := presensi.NewHandler(presensiService)
+	presensiJamHandler := presensi.NewJamHandler(presensiJamService)
+	rekapPresensiHandler := rekappresensi.NewHandler(rekapPresensiService)
+	jurnalMengajarHandler := jurnalmengajar.NewHandler(jurnalMengajarService)
+	jadwalHandler := jadwal.NewHandler(jadwalService)
 	connectionHandler := connection.NewHandler()
 	ekstrakurikulerHandler := ekstrakurikuler.NewHandler(ekstrakurikulerService)
 	prestasiHandler := prestasi.NewHandler(prestasiService)
 	ujianMasterHandler := ujianmaster.NewHandler(ujianMasterService)
 	paperSizeHandler := papersize.NewHandler(paperSizeService)
+	roleHandler := role.NewHandler(roleService)
+	auditHandler := audit.NewHandler(auditService)
+	pengumumanHandler := pengumuman.NewHandler(pengumumanService)
+	waliMuridHandler := walimurid.NewHandler(waliMuridService)
+	portalHandler := portal.NewHandler(portalService, studentService, waliMuridService, pengumumanService)
+	nilaiAkhirHandler := nilaiakhir.NewHandler(nilaiAkhirService)
+	raporHandler := rapor.NewHandler(raporService)
+	deskripsiHandler := deskripsi.NewHandler(deskripsiService)
+	legerHandler := leger.NewHandler(legerService)
+	kenaikanHandler := kenaikan.NewHandler(kenaikanService)
+	mutasiHandler := mutasi.NewHandler(mutasiService)
+	alumniHandler := alumni.NewHandler(alumniService)
 
 	r := chi.NewRouter()
 
@@ -190,6 +289,10 @@ func main() {
 	r.Use(middleware.Timeout(60 * time.Second))
 
 	r.Post("/login", authHandler.Login)
+	r.Post("/auth/refresh", authHandler.Refresh)
+	r.Post("/auth/logout", authHandler.Logout)
+	r.Post("/auth/forgot-password", authHandler.ForgotPassword)
+	r.Post("/auth/reset-password", authHandler.ResetPassword)
 	r.With(authMiddleware.AuthMiddleware, auth.AuthorizeSuperadmin).Post("/tenants/register", tenantHandler.Register)
 
 	r.Get("/livez", connectionHandler.Livez)
@@ -198,12 +301,24 @@ func main() {
 	r.Route("/", func(r chi.Router) {
 		r.Use(authMiddleware.AuthMiddleware)
 
+		r.Put("/me/password", authHandler.ChangePassword)
+		r.Get("/me/permissions", authHandler.GetMyPermissions)
+
+		r.Route("/locked-accounts", func(r chi.Router) {
+			r.With(auth.Require(auth.PermAkunManage)).Get("/", authHandler.GetLockedAccounts)
+			r.With(auth.Require(auth.PermAkunManage)).Post("/unlock", authHandler.UnlockAccount)
+		})
+
+		r.With(auth.Require(auth.PermAuditRead)).Get("/audit", auditHandler.GetAll)
+
 		r.Route("/naungan", func(r chi.Router) {
 			r.With(auth.AuthorizeSuperadmin).Get("/", naunganHandler.GetAll)
 			r.With(auth.AuthorizeSuperadmin).Get("/{naunganID}", naunganHandler.GetByID)
 			r.With(auth.AuthorizeSuperadmin).Post("/", naunganHandler.Create)
 			r.With(auth.AuthorizeSuperadmin).Put("/{naunganID}", naunganHandler.Update)
 			r.With(auth.AuthorizeSuperadmin).Delete("/{naunganID}", naunganHandler.Delete)
+			r.With(auth.AuthorizeSuperadmin).Get("/{naunganID}/alumni", alumniHandler.GetRekapNaungan)
+			r.With(auth.AuthorizeSuperadmin).Get("/{naunganID}/alumni/export", alumniHandler.ExportNaungan)
 		})
 
 		r.Route("/tenants", func(r chi.Router) {
@@ -220,226 +335,385 @@ func main() {
 			r.With(auth.Authorize("teacher")).Get("/me/classes", teacherHandler.GetMyKelas)
 			r.With(auth.Authorize("admin")).Get("/admin/details", teacherHandler.GetAdminDetails)
 			r.Route("/history", func(r chi.Router) {
-				r.With(auth.Authorize("admin")).Post("/{teacherID}", teacherHandler.CreateHistory)
-				r.With(auth.Authorize("admin")).Get("/{teacherID}", teacherHandler.GetHistoryByTeacherID)
-				r.With(auth.Authorize("admin")).Put("/{historyID}", teacherHandler.UpdateHistory)
-				r.With(auth.Authorize("admin")).Delete("/{historyID}", teacherHandler.DeleteHistory)
+				r.With(auth.Require(auth.PermGuruManage)).Post("/{teacherID}", teacherHandler.CreateHistory)
+				r.With(auth.Require(auth.PermGuruManage)).Get("/{teacherID}", teacherHandler.GetHistoryByTeacherID)
+				r.With(auth.Require(auth.PermGuruManage)).Put("/{historyID}", teacherHandler.UpdateHistory)
+				r.With(auth.Require(auth.PermGuruManage)).Delete("/{historyID}", teacherHandler.DeleteHistory)
 			})
-			r.With(auth.Authorize("admin", "teacher")).Get("/", teacherHandler.GetAll)
-			r.With(auth.Authorize("admin", "teacher")).Get("/{teacherID}", teacherHandler.GetByID)
-			r.With(auth.Authorize("admin")).Post("/", teacherHandler.Create)
-			r.With(auth.Authorize("admin")).Put("/{teacherID}", teacherHandler.Update)
-			r.With(auth.Authorize("admin")).Delete("/{teacherID}", teacherHandler.Delete)
+			r.With(auth.Require(auth.PermGuruRead)).Get("/", teacherHandler.GetAll)
+			r.With(auth.Require(auth.PermGuruRead)).Get("/{teacherID}", teacherHandler.GetByID)
+			r.With(auth.Require(auth.PermGuruRead)).Get("/{teacherID}/jabatan", teacherHandler.GetJabatan)
+			r.With(auth.Require(auth.PermRoleManage)).Put("/{teacherID}/jabatan", teacherHandler.SetJabatan)
+			r.With(auth.Require(auth.PermGuruManage)).Post("/", teacherHandler.Create)
+			r.With(auth.Require(auth.PermGuruManage)).Put("/{teacherID}", teacherHandler.Update)
+			r.With(auth.Require(auth.PermGuruManage)).Delete("/{teacherID}", teacherHandler.Delete)
 		})
 
 		r.Route("/students", func(r chi.Router) {
-			r.With(auth.Authorize("admin")).Get("/available", studentHandler.GetAvailableStudents)
-			r.With(auth.Authorize("admin")).Get("/import/template", studentHandler.GenerateTemplate)
-			r.With(auth.Authorize("admin")).Post("/import", studentHandler.ImportStudents)
+			r.With(auth.Require(auth.PermSiswaManage)).Get("/available", studentHandler.GetAvailableStudents)
+			r.With(auth.Require(auth.PermSiswaManage)).Get("/import/template", studentHandler.GenerateTemplate)
+			r.With(auth.Require(auth.PermSiswaManage)).Post("/import", studentHandler.ImportStudents)
+			r.With(auth.Require(auth.PermSiswaManage)).Get("/accounts", studentAccountHandler.GetAccounts)
+			r.With(auth.Require(auth.PermSiswaManage)).Post("/accounts/generate", studentAccountHandler.GenerateAccounts)
+
+			// Portal siswa: hanya data milik siswa yang login.
+			r.Route("/me", func(r chi.Router) {
+				r.Use(auth.Authorize(student.RoleSiswa))
+				r.Get("/", portalHandler.GetMe)
+				r.Get("/nilai", portalHandler.GetNilai)
+				r.Get("/presensi", portalHandler.GetPresensi)
+				r.Get("/prestasi", portalHandler.GetPrestasi)
+				r.Get("/ekstrakurikuler", portalHandler.GetEkstrakurikuler)
+				r.Get("/kartu-ujian", portalHandler.GetKartuUjian)
+				r.Get("/pengumuman", portalHandler.GetPengumumanSiswa)
+			})
 
 			r.Route("/history", func(r chi.Router) {
-				r.With(auth.Authorize("admin")).Get("/{studentID}", studentHistoryHandler.GetByStudentID)
-				r.With(auth.Authorize("admin")).Post("/{studentID}", studentHistoryHandler.Create)
-				r.With(auth.Authorize("admin")).Put("/{historyID}", studentHistoryHandler.Update)
-				r.With(auth.Authorize("admin")).Delete("/{historyID}", studentHistoryHandler.Delete)
+				r.With(auth.Require(auth.PermSiswaManage)).Get("/{studentID}", studentHistoryHandler.GetByStudentID)
+				r.With(auth.Require(auth.PermSiswaManage)).Post("/{studentID}", studentHistoryHandler.Create)
+				r.With(auth.Require(auth.PermSiswaManage)).Put("/{historyID}", studentHistoryHandler.Update)
+				r.With(auth.Require(auth.PermSiswaManage)).Delete("/{historyID}", studentHistoryHandler.Delete)
 			})
-			r.With(auth.Authorize("admin", "teacher")).Get("/", studentHandler.GetAll)
-			r.With(auth.Authorize("admin", "teacher")).Get("/{studentID}", studentHandler.GetByID)
-			r.With(auth.Authorize("admin")).Post("/", studentHandler.Create)
-			r.With(auth.Authorize("admin")).Put("/{studentID}", studentHandler.Update)
-			r.With(auth.Authorize("admin")).Delete("/{studentID}", studentHandler.Delete)
+			r.With(auth.Require(auth.PermSiswaRead)).Get("/", studentHandler.GetAll)
+			r.With(auth.Require(auth.PermSiswaRead)).Get("/{studentID}", studentHandler.GetByID)
+			r.With(auth.Require(auth.PermSiswaManage)).Post("/", studentHandler.Create)
+			r.With(auth.Require(auth.PermSiswaManage)).Put("/{studentID}", studentHandler.Update)
+			r.With(auth.Require(auth.PermSiswaManage)).Delete("/{studentID}", studentHandler.Delete)
+			r.With(auth.Require(auth.PermSiswaManage)).Delete("/{studentID}/account", studentAccountHandler.DeleteAccount)
+		})
+
+		r.Route("/wali-murid", func(r chi.Router) {
+			// Portal wali murid: hanya data anak dari wali yang login.
+			r.Route("/me", func(r chi.Router) {
+				r.Use(auth.Authorize(walimurid.RoleWaliMurid))
+				r.Get("/", portalHandler.GetWaliMe)
+				r.Get("/pengumuman", portalHandler.GetPengumumanWali)
+				r.Get("/anak/{studentID}/nilai", portalHandler.GetAnakNilai)
+				r.Get("/anak/{studentID}/presensi", portalHandler.GetAnakPresensi)
+				r.Get("/anak/{studentID}/prestasi", portalHandler.GetAnakPrestasi)
+			})
+			r.With(auth.Require(auth.PermWaliMuridManage)).Get("/", waliMuridHandler.GetAll)
+			r.With(auth.Require(auth.PermWaliMuridManage)).Post("/", waliMuridHandler.Create)
+			r.With(auth.Require(auth.PermWaliMuridManage)).Post("/generate", waliMuridHandler.GenerateFromSiswa)
+			r.With(auth.Require(auth.PermWaliMuridManage)).Get("/{id}", waliMuridHandler.GetByID)
+			r.With(auth.Require(auth.PermWaliMuridManage)).Put("/{id}", waliMuridHandler.Update)
+			r.With(auth.Require(auth.PermWaliMuridManage)).Delete("/{id}", waliMuridHandler.Delete)
+			r.With(auth.Require(auth.PermWaliMuridManage)).Post("/{id}/reset-password", waliMuridHandler.ResetPassword)
+			r.With(auth.Require(auth.PermWaliMuridManage)).Post("/{id}/siswa", waliMuridHandler.LinkSiswa)
+			r.With(auth.Require(auth.PermWaliMuridManage)).Delete("/{id}/siswa/{studentID}", waliMuridHandler.UnlinkSiswa)
+		})
+
+		r.Route("/pengumuman", func(r chi.Router) {
+			r.With(auth.Require(auth.PermPengumumanManage)).Get("/", pengumumanHandler.GetAll)
+			r.With(auth.Require(auth.PermPengumumanManage)).Post("/", pengumumanHandler.Create)
+			r.With(auth.Require(auth.PermPengumumanManage)).Put("/{id}", pengumumanHandler.Update)
+			r.With(auth.Require(auth.PermPengumumanManage)).Delete("/{id}", pengumumanHandler.Delete)
+		})
+
+		r.Route("/rapor", func(r chi.Router) {
+			r.With(auth.Require(auth.PermRaporRead)).Get("/anggota/{anggotaKelasID}", raporHandler.GetRapor)
+			r.With(auth.Require(auth.PermRaporRead)).Get("/anggota/{anggotaKelasID}/pdf", raporHandler.GetRaporPDF)
+			r.With(auth.Require(auth.PermRaporRead)).Get("/kelas/{kelasID}", raporHandler.GetKelasRapor)
 		})
 
 		r.Route("/profile", func(r chi.Router) {
-			r.With(auth.Authorize("admin")).Get("/", profileHandler.GetProfile)
-			r.With(auth.Authorize("admin")).Put("/", profileHandler.UpdateProfile)
+			r.With(auth.Require(auth.PermProfilManage)).Get("/", profileHandler.GetProfile)
+			r.With(auth.Require(auth.PermProfilManage)).Put("/", profileHandler.UpdateProfile)
 		})
 
 		r.Route("/jenjang", func(r chi.Router) {
-			r.With(auth.Authorize("admin")).Get("/", jenjangHandler.GetAll)
-			r.With(auth.Authorize("admin")).Post("/", jenjangHandler.Create)
-			r.With(auth.Authorize("admin")).Get("/{id}", jenjangHandler.GetByID)
-			r.With(auth.Authorize("admin")).Put("/{id}", jenjangHandler.Update)
-			r.With(auth.Authorize("admin")).Delete("/{id}", jenjangHandler.Delete)
+			r.With(auth.Require(auth.PermMasterDataManage)).Get("/", jenjangHandler.GetAll)
+			r.With(auth.Require(auth.PermMasterDataManage)).Post("/", jenjangHandler.Create)
+			r.With(auth.Require(auth.PermMasterDataManage)).Get("/{id}", jenjangHandler.GetByID)
+			r.With(auth.Require(auth.PermMasterDataManage)).Put("/{id}", jenjangHandler.Update)
+			r.With(auth.Require(auth.PermMasterDataManage)).Delete("/{id}", jenjangHandler.Delete)
 		})
 
 		r.Route("/ekstrakurikuler", func(r chi.Router) {
-			r.With(auth.Authorize("admin")).Get("/", ekstrakurikulerHandler.GetAll)
-			r.With(auth.Authorize("admin")).Post("/", ekstrakurikulerHandler.Create)
-			r.With(auth.Authorize("admin")).Put("/{id}", ekstrakurikulerHandler.Update)
-			r.With(auth.Authorize("admin")).Delete("/{id}", ekstrakurikulerHandler.Delete)
-			r.With(auth.Authorize("admin")).Get("/sesi", ekstrakurikulerHandler.GetSesi)
-			r.With(auth.Authorize("admin")).Put("/sesi/{sesiId}", ekstrakurikulerHandler.UpdateSesiDetail)
-			r.With(auth.Authorize("admin")).Get("/sesi/{sesiId}/anggota", ekstrakurikulerHandler.GetAnggota)
-			r.With(auth.Authorize("admin")).Post("/sesi/{sesiId}/anggota", ekstrakurikulerHandler.AddAnggota)
-			r.With(auth.Authorize("admin")).Delete("/anggota/{anggotaId}", ekstrakurikulerHandler.RemoveAnggota)
+			r.With(auth.Require(auth.PermEkstrakurikulerManage)).Get("/", ekstrakurikulerHandler.GetAll)
+			r.With(auth.Require(auth.PermEkstrakurikulerManage)).Post("/", ekstrakurikulerHandler.Create)
+			r.With(auth.Require(auth.PermEkstrakurikulerManage)).Put("/{id}", ekstrakurikulerHandler.Update)
+			r.With(auth.Require(auth.PermEkstrakurikulerManage)).Delete("/{id}", ekstrakurikulerHandler.Delete)
+			r.With(auth.Require(auth.PermEkstrakurikulerManage)).Get("/sesi", ekstrakurikulerHandler.GetSesi)
+			r.With(auth.Require(auth.PermEkstrakurikulerManage)).Put("/sesi/{sesiId}", ekstrakurikulerHandler.UpdateSesiDetail)
+			r.With(auth.Require(auth.PermEkstrakurikulerManage)).Get("/sesi/{sesiId}/anggota", ekstrakurikulerHandler.GetAnggota)
+			r.With(auth.Require(auth.PermEkstrakurikulerManage)).Post("/sesi/{sesiId}/anggota", ekstrakurikulerHandler.AddAnggota)
+			r.With(auth.Require(auth.PermEkstrakurikulerManage)).Delete("/anggota/{anggotaId}", ekstrakurikulerHandler.RemoveAnggota)
 		})
 
 		r.Route("/jabatan", func(r chi.Router) {
-			r.With(auth.Authorize("admin")).Get("/", jabatanHandler.GetAll)
-			r.With(auth.Authorize("admin")).Post("/", jabatanHandler.Create)
-			r.With(auth.Authorize("admin")).Get("/{id}", jabatanHandler.GetByID)
-			r.With(auth.Authorize("admin")).Put("/{id}", jabatanHandler.Update)
-			r.With(auth.Authorize("admin")).Delete("/{id}", jabatanHandler.Delete)
+			r.With(auth.Require(auth.PermMasterDataManage)).Get("/", jabatanHandler.GetAll)
+			r.With(auth.Require(auth.PermMasterDataManage)).Post("/", jabatanHandler.Create)
+			r.With(auth.Require(auth.PermMasterDataManage)).Get("/{id}", jabatanHandler.GetByID)
+			r.With(auth.Require(auth.PermMasterDataManage)).Put("/{id}", jabatanHandler.Update)
+			r.With(auth.Require(auth.PermMasterDataManage)).Delete("/{id}", jabatanHandler.Delete)
+		})
+
+		r.Route("/roles", func(r chi.Router) {
+			r.With(auth.Require(auth.PermRoleManage)).Get("/permissions", roleHandler.GetPermissionCatalog)
+			r.With(auth.Require(auth.PermRoleManage)).Get("/", roleHandler.GetAll)
+			r.With(auth.Require(auth.PermRoleManage)).Post("/", roleHandler.Create)
+			r.With(auth.Require(auth.PermRoleManage)).Get("/{id}", roleHandler.GetByID)
+			r.With(auth.Require(auth.PermRoleManage)).Put("/{id}", roleHandler.Update)
+			r.With(auth.Require(auth.PermRoleManage)).Delete("/{id}", roleHandler.Delete)
 		})
 
 		r.Route("/tingkatan", func(r chi.Router) {
-			r.With(auth.Authorize("admin")).Get("/", tingkatanHandler.GetAll)
-			r.With(auth.Authorize("admin")).Post("/", tingkatanHandler.Create)
-			r.With(auth.Authorize("admin")).Get("/{id}", tingkatanHandler.GetByID)
-			r.With(auth.Authorize("admin")).Put("/{id}", tingkatanHandler.Update)
-			r.With(auth.Authorize("admin")).Delete("/{id}", tingkatanHandler.Delete)
+			r.With(auth.Require(auth.PermMasterDataManage)).Get("/", tingkatanHandler.GetAll)
+			r.With(auth.Require(auth.PermMasterDataManage)).Post("/", tingkatanHandler.Create)
+			r.With(auth.Require(auth.PermMasterDataManage)).Get("/{id}", tingkatanHandler.GetByID)
+			r.With(auth.Require(auth.PermMasterDataManage)).Put("/{id}", tingkatanHandler.Update)
+			r.With(auth.Require(auth.PermMasterDataManage)).Delete("/{id}", tingkatanHandler.Delete)
 		})
 
 		r.Route("/tahun-ajaran", func(r chi.Router) {
-			// PERBAIKAN: Teacher boleh melihat data tahun ajaran
-			r.With(auth.Authorize("admin", "teacher")).Get("/", tahunAjaranHandler.GetAll)
-			r.With(auth.Authorize("admin")).Post("/", tahunAjaranHandler.Create)
-			r.With(auth.Authorize("admin", "teacher")).Get("/{id}", tahunAjaranHandler.GetByID)
-			r.With(auth.Authorize("admin")).Put("/{id}", tahunAjaranHandler.Update)
-			r.With(auth.Authorize("admin")).Delete("/{id}", tahunAjaranHandler.Delete)
+			r.With(auth.Require(auth.PermTahunAjaranRead)).Get("/", tahunAjaranHandler.GetAll)
+			r.With(auth.Require(auth.PermTahunAjaranManage)).Post("/", tahunAjaranHandler.Create)
+			r.With(auth.Require(auth.PermTahunAjaranRead)).Get("/{id}", tahunAjaranHandler.GetByID)
+			r.With(auth.Require(auth.PermTahunAjaranManage)).Put("/{id}", tahunAjaranHandler.Update)
+			r.With(auth.Require(auth.PermTahunAjaranManage)).Delete("/{id}", tahunAjaranHandler.Delete)
+			r.With(auth.Require(auth.PermTahunAjaranManage)).Post("/{id}/clone-from/{sourceID}", tahunAjaranCloneHandler.CloneFrom)
+
+			r.With(auth.Require(auth.PermTahunAjaranRead)).Get("/{id}/kalender", kalenderHandler.GetKalender)
+			r.With(auth.Require(auth.PermTahunAjaranManage)).Put("/{id}/kalender/pengaturan", kalenderHandler.UpdatePengaturan)
+			r.With(auth.Require(auth.PermTahunAjaranRead)).Get("/{id}/kalender/hari-efektif", kalenderHandler.GetHariEfektif)
+			r.With(auth.Require(auth.PermTahunAjaranManage)).Post("/{id}/kalender/agenda", kalenderHandler.CreateAgenda)
+			r.With(auth.Require(auth.PermTahunAjaranManage)).Put("/{id}/kalender/agenda/{agendaID}", kalenderHandler.UpdateAgenda)
+			r.With(auth.Require(auth.PermTahunAjaranManage)).Delete("/{id}/kalender/agenda/{agendaID}", kalenderHandler.DeleteAgenda)
+			r.With(auth.Require(auth.PermTahunAjaranRead)).Get("/{id}/kalender/ics", kalenderHandler.ExportICS)
+			r.With(auth.Require(auth.PermTahunAjaranManage)).Post("/{id}/kalender/ics", kalenderHandler.ImportICS)
 		})
 
 		r.Route("/mata-pelajaran", func(r chi.Router) {
-			r.With(auth.Authorize("admin")).Put("/reorder", mataPelajaranHandler.UpdateUrutan)
-			r.With(auth.Authorize("admin")).Get("/", kelompokMapelHandler.GetAll)
-			r.With(auth.Authorize("admin")).Get("/taught", mataPelajaranHandler.GetAllTaught)
-			r.With(auth.Authorize("admin")).Post("/", mataPelajaranHandler.Create)
+			r.With(auth.Require(auth.PermMapelManage)).Put("/reorder", mataPelajaranHandler.UpdateUrutan)
+			r.With(auth.Require(auth.PermMapelManage)).Get("/", kelompokMapelHandler.GetAll)
+			r.With(auth.Require(auth.PermMapelManage)).Get("/taught", mataPelajaranHandler.GetAllTaught)
+			r.With(auth.Require(auth.PermMapelManage)).Post("/", mataPelajaranHandler.Create)
 
-			// PERBAIKAN UTAMA: Teacher boleh melihat detail mata pelajaran (untuk header panel)
-			r.With(auth.Authorize("admin", "teacher")).Get("/{id}", mataPelajaranHandler.GetByID)
+			r.With(auth.Require(auth.PermMapelRead)).Get("/{id}", mataPelajaranHandler.GetByID)
 
-			r.With(auth.Authorize("admin")).Put("/{id}", mataPelajaranHandler.Update)
-			r.With(auth.Authorize("admin")).Delete("/{id}", mataPelajaranHandler.Delete)
+			r.With(auth.Require(auth.PermMapelManage)).Put("/{id}", mataPelajaranHandler.Update)
+			r.With(auth.Require(auth.PermMapelManage)).Delete("/{id}", mataPelajaranHandler.Delete)
 		})
 
 		r.Route("/kelompok-mapel", func(r chi.Router) {
-			r.With(auth.Authorize("admin")).Get("/", kelompokMapelHandler.GetAll)
-			r.With(auth.Authorize("admin")).Post("/", kelompokMapelHandler.Create)
-			r.With(auth.Authorize("admin")).Put("/{id}", kelompokMapelHandler.Update)
-			r.With(auth.Authorize("admin")).Delete("/{id}", kelompokMapelHandler.Delete)
+			r.With(auth.Require(auth.PermMapelManage)).Get("/", kelompokMapelHandler.GetAll)
+			r.With(auth.Require(auth.PermMapelManage)).Post("/", kelompokMapelHandler.Create)
+			r.With(auth.Require(auth.PermMapelManage)).Put("/{id}", kelompokMapelHandler.Update)
+			r.With(auth.Require(auth.PermMapelManage)).Delete("/{id}", kelompokMapelHandler.Delete)
 		})
 
 		r.Route("/kurikulum", func(r chi.Router) {
-			// PERBAIKAN UTAMA: Teacher boleh melihat kurikulum
-			r.With(auth.Authorize("admin", "teacher")).Get("/", kurikulumHandler.GetAll)
-			r.With(auth.Authorize("admin", "teacher")).Get("/by-tahun-ajaran", kurikulumHandler.GetByTahunAjaran)
-
-			r.With(auth.Authorize("admin")).Post("/", kurikulumHandler.Create)
-			r.With(auth.Authorize("admin")).Put("/{id}", kurikulumHandler.Update)
-			r.With(auth.Authorize("admin")).Delete("/{id}", kurikulumHandler.Delete)
-			r.With(auth.Authorize("admin")).Post("/add-to-tahun-ajaran", kurikulumHandler.AddKurikulumToTahunAjaran)
-			r.With(auth.Authorize("admin")).Get("/fase", kurikulumHandler.GetAllFase)
-			r.With(auth.Authorize("admin")).Post("/fase", kurikulumHandler.CreateFase)
-			r.With(auth.Authorize("admin")).Get("/tingkatan", kurikulumHandler.GetAllTingkatan)
-			r.With(auth.Authorize("admin")).Get("/pemetaan", kurikulumHandler.GetFaseTingkatan)
-			r.With(auth.Authorize("admin")).Post("/pemetaan", kurikulumHandler.CreatePemetaan)
-			r.With(auth.Authorize("admin")).Delete("/pemetaan/ta/{tahunAjaranID}/k/{kurikulumID}/t/{tingkatanID}", kurikulumHandler.DeletePemetaan)
+			r.With(auth.Require(auth.PermKurikulumRead)).Get("/", kurikulumHandler.GetAll)
+			r.With(auth.Require(auth.PermKurikulumRead)).Get("/by-tahun-ajaran", kurikulumHandler.GetByTahunAjaran)
+
+			r.With(auth.Require(auth.PermKurikulumManage)).Post("/", kurikulumHandler.Create)
+			r.With(auth.Require(auth.PermKurikulumManage)).Put("/{id}", kurikulumHandler.Update)
+			r.With(auth.Require(auth.PermKurikulumManage)).Delete("/{id}", kurikulumHandler.Delete)
+			r.With(auth.Require(auth.PermKurikulumManage)).Post("/add-to-tahun-ajaran", kurikulumHandler.AddKurikulumToTahunAjaran)
+			r.With(auth.Require(auth.PermKurikulumManage)).Get("/fase", kurikulumHandler.GetAllFase)
+			r.With(auth.Require(auth.PermKurikulumManage)).Post("/fase", kurikulumHandler.CreateFase)
+			r.With(auth.Require(auth.PermKurikulumManage)).Get("/tingkatan", kurikulumHandler.GetAllTingkatan)
+			r.With(auth.Require(auth.PermKurikulumManage)).Get("/pemetaan", kurikulumHandler.GetFaseTingkatan)
+			r.With(auth.Require(auth.PermKurikulumManage)).Post("/pemetaan", kurikulumHandler.CreatePemetaan)
+			r.With(auth.Require(auth.PermKurikulumManage)).Delete("/pemetaan/ta/{tahunAjaranID}/k/{kurikulumID}/t/{tingkatanID}", kurikulumHandler.DeletePemetaan)
 		})
 
 		r.Route("/rombel", func(r chi.Router) {
-			r.With(auth.Authorize("admin")).Get("/", rombelHandler.GetAllKelasByTahunAjaran)
-			r.With(auth.Authorize("admin")).Post("/", rombelHandler.CreateKelas)
-			r.With(auth.Authorize("admin", "teacher")).Get("/{kelasID}", rombelHandler.GetKelasByID)
-			r.With(auth.Authorize("admin")).Put("/{kelasID}", rombelHandler.UpdateKelas)
-			r.With(auth.Authorize("admin")).Delete("/{kelasID}", rombelHandler.DeleteKelas)
-			r.With(auth.Authorize("admin", "teacher")).Get("/{kelasID}/anggota", rombelHandler.GetAllAnggotaByKelas)
-			r.With(auth.Authorize("admin")).Post("/{kelasID}/anggota", rombelHandler.AddAnggotaKelas)
-			r.With(auth.Authorize("admin")).Delete("/anggota/{anggotaID}", rombelHandler.RemoveAnggotaKelas)
-			r.With(auth.Authorize("admin")).Put("/anggota/reorder", rombelHandler.UpdateAnggotaKelasUrutan)
-			r.With(auth.Authorize("admin", "teacher")).Get("/{kelasID}/pengajar", rombelHandler.GetAllPengajarByKelas)
-			r.With(auth.Authorize("admin")).Post("/{kelasID}/pengajar", rombelHandler.CreatePengajarKelas)
-			r.With(auth.Authorize("admin")).Delete("/pengajar/{pengajarID}", rombelHandler.RemovePengajarKelas)
+			r.With(auth.Require(auth.PermRombelManage)).Get("/", rombelHandler.GetAllKelasByTahunAjaran)
+			r.With(auth.Require(auth.PermRombelManage)).Post("/", rombelHandler.CreateKelas)
+			r.With(auth.Require(auth.PermRombelRead)).Get("/{kelasID}", rombelHandler.GetKelasByID)
+			r.With(auth.Require(auth.PermRombelManage)).Put("/{kelasID}", rombelHandler.UpdateKelas)
+			r.With(auth.Require(auth.PermRombelManage)).Delete("/{kelasID}", rombelHandler.DeleteKelas)
+			r.With(auth.Require(auth.PermRombelRead)).Get("/{kelasID}/anggota", rombelHandler.GetAllAnggotaByKelas)
+			r.With(auth.Require(auth.PermRombelManage)).Post("/{kelasID}/anggota", rombelHandler.AddAnggotaKelas)
+			r.With(auth.Require(auth.PermRombelManage)).Delete("/anggota/{anggotaID}", rombelHandler.RemoveAnggotaKelas)
+			r.With(auth.Require(auth.PermRombelManage)).Put("/anggota/reorder", rombelHandler.UpdateAnggotaKelasUrutan)
+			r.With(auth.Require(auth.PermRombelRead)).Get("/{kelasID}/pengajar", rombelHandler.GetAllPengajarByKelas)
+			r.With(auth.Require(auth.PermRaporRead)).Get("/{kelasID}/leger", legerHandler.ExportLeger)
+			r.With(auth.Require(auth.PermRombelManage)).Post("/{kelasID}/pengajar", rombelHandler.CreatePengajarKelas)
+			r.With(auth.Require(auth.PermRombelManage)).Delete("/pengajar/{pengajarID}", rombelHandler.RemovePengajarKelas)
+		})
+
+		r.Route("/mutasi", func(r chi.Router) {
+			r.With(auth.Require(auth.PermSiswaRead)).Get("/", mutasiHandler.GetLaporan)
+			r.With(auth.Require(auth.PermSiswaManage)).Post("/masuk", mutasiHandler.Masuk)
+			r.With(auth.Require(auth.PermSiswaManage)).Post("/keluar", mutasiHandler.Keluar)
+			r.With(auth.Require(auth.PermSiswaRead)).Get("/{id}", mutasiHandler.GetByID)
+			r.With(auth.Require(auth.PermSiswaRead)).Get("/{id}/surat", mutasiHandler.GetSurat)
+		})
+
+		r.Route("/alumni", func(r chi.Router) {
+			r.With(auth.Require(auth.PermSiswaRead)).Get("/", alumniHandler.GetAll)
+			r.With(auth.Require(auth.PermSiswaRead)).Get("/export", alumniHandler.Export)
+			r.With(auth.Require(auth.PermSiswaManage)).Post("/sinkron-nilai", alumniHandler.Sinkron)
+			r.With(auth.Require(auth.PermSiswaRead)).Get("/{studentID}", alumniHandler.GetByID)
+			r.With(auth.Require(auth.PermSiswaManage)).Put("/{studentID}", alumniHandler.Update)
+		})
+
+		r.Route("/kenaikan-kelas", func(r chi.Router) {
+			r.With(auth.Require(auth.PermRombelManage)).Get("/usulan", kenaikanHandler.GetUsulan)
+			r.With(auth.Require(auth.PermRombelManage)).Post("/proses", kenaikanHandler.Proses)
 		})
 
 		r.Route("/pembelajaran", func(r chi.Router) {
-			r.With(auth.Authorize("admin", "teacher")).Get("/rencana/by-pengajar/{pengajarKelasID}", pembelajaranHandler.GetAllRencanaPembelajaran)
-			r.With(auth.Authorize("admin", "teacher")).Put("/rencana/reorder", pembelajaranHandler.UpdateRencanaUrutan)
-			r.With(auth.Authorize("admin", "teacher")).Post("/materi", pembelajaranHandler.CreateMateri)
-			r.With(auth.Authorize("admin", "teacher")).Put("/materi/{materiID}", pembelajaranHandler.UpdateMateri)
-			r.With(auth.Authorize("admin", "teacher")).Delete("/materi/{materiID}", pembelajaranHandler.DeleteMateri)
-			r.With(auth.Authorize("admin", "teacher")).Post("/ujian/bulk", pembelajaranHandler.CreateBulkUjian)
-			r.With(auth.Authorize("admin", "teacher")).Get("/ujian/monitoring/by-tahun-ajaran/{tahunAjaranID}", pembelajaranHandler.GetAllUjianMonitoringByTahunAjaran)
-			r.With(auth.Authorize("admin", "teacher")).Post("/ujian", pembelajaranHandler.CreateUjian)
-			r.With(auth.Authorize("admin", "teacher")).Put("/ujian/{id}", pembelajaranHandler.UpdateUjian)
-			r.With(auth.Authorize("admin", "teacher")).Delete("/ujian/{id}", pembelajaranHandler.DeleteUjian)
-			r.With(auth.Authorize("admin", "teacher")).Post("/tujuan", pembelajaranHandler.CreateTujuan)
-			r.With(auth.Authorize("admin", "teacher")).Put("/tujuan/{tujuanID}", pembelajaranHandler.UpdateTujuan)
-			r.With(auth.Authorize("admin", "teacher")).Delete("/tujuan/{tujuanID}", pembelajaranHandler.DeleteTujuan)
-			r.With(auth.Authorize("admin", "teacher")).Put("/tujuan/reorder", pembelajaranHandler.UpdateUrutanTujuan)
+			r.With(auth.Require(auth.PermPembelajaranRead)).Get("/rencana/by-pengajar/{pengajarKelasID}", pembelajaranHandler.GetAllRencanaPembelajaran)
+			r.With(auth.Require(auth.PermPembelajaranWrite)).Put("/rencana/reorder", pembelajaranHandler.UpdateRencanaUrutan)
+			r.With(auth.Require(auth.PermPembelajaranWrite)).Post("/materi", pembelajaranHandler.CreateMateri)
+			r.With(auth.Require(auth.PermPembelajaranWrite)).Put("/materi/{materiID}", pembelajaranHandler.UpdateMateri)
+			r.With(auth.Require(auth.PermPembelajaranWrite)).Delete("/materi/{materiID}", pembelajaranHandler.DeleteMateri)
+			r.With(auth.Require(auth.PermPembelajaranWrite)).Post("/ujian/bulk", pembelajaranHandler.CreateBulkUjian)
+			r.With(auth.Require(auth.PermPembelajaranRead)).Get("/ujian/monitoring/by-tahun-ajaran/{tahunAjaranID}", pembelajaranHandler.GetAllUjianMonitoringByTahunAjaran)
+			r.With(auth.Require(auth.PermPembelajaranWrite)).Post("/ujian", pembelajaranHandler.CreateUjian)
+			r.With(auth.Require(auth.PermPembelajaranWrite)).Put("/ujian/{id}", pembelajaranHandler.UpdateUjian)
+			r.With(auth.Require(auth.PermPembelajaranWrite)).Delete("/ujian/{id}", pembelajaranHandler.DeleteUjian)
+			r.With(auth.Require(auth.PermPembelajaranWrite)).Post("/tujuan", pembelajaranHandler.CreateTujuan)
+			r.With(auth.Require(auth.PermPembelajaranWrite)).Put("/tujuan/{tujuanID}", pembelajaranHandler.UpdateTujuan)
+			r.With(auth.Require(auth.PermPembelajaranWrite)).Delete("/tujuan/{tujuanID}", pembelajaranHandler.DeleteTujuan)
+			r.With(auth.Require(auth.PermPembelajaranWrite)).Put("/tujuan/reorder", pembelajaranHandler.UpdateUrutanTujuan)
 		})
 
 		r.Route("/penilaian", func(r chi.Router) {
-			r.With(auth.Authorize("admin", "teacher")).Get("/kelas/{kelasID}/pengajar/{pengajarKelasID}", penilaianHandler.GetPenilaianLengkap)
-			r.With(auth.Authorize("admin", "teacher")).Post("/batch-upsert", penilaianHandler.UpsertNilaiBulk)
+			r.With(auth.Require(auth.PermPenilaianRead)).Get("/kelas/{kelasID}/pengajar/{pengajarKelasID}", penilaianHandler.GetPenilaianLengkap)
+			r.With(auth.Require(auth.PermPenilaianWrite)).Post("/batch-upsert", penilaianHandler.UpsertNilaiBulk)
+			r.With(auth.Require(auth.PermPenilaianRead)).Get("/remedial/kelas/{kelasID}/pengajar/{pengajarKelasID}", remedialHandler.GetByPengajar)
+			r.With(auth.Require(auth.PermPenilaianWrite)).Post("/remedial", remedialHandler.Upsert)
+			r.With(auth.Require(auth.PermPenilaianWrite)).Delete("/remedial/{id}", remedialHandler.Delete)
+		})
+
+		r.Route("/kktp", func(r chi.Router) {
+			r.With(auth.Require(auth.PermPenilaianRead)).Get("/", kktpHandler.GetAll)
+			r.With(auth.Require(auth.PermMasterDataManage)).Post("/", kktpHandler.Create)
+			r.With(auth.Require(auth.PermPenilaianRead)).Get("/{id}", kktpHandler.GetByID)
+			r.With(auth.Require(auth.PermMasterDataManage)).Put("/{id}", kktpHandler.Update)
+			r.With(auth.Require(auth.PermMasterDataManage)).Delete("/{id}", kktpHandler.Delete)
+		})
+
+		r.Route("/nilai-akhir", func(r chi.Router) {
+			r.With(auth.Require(auth.PermPenilaianRead)).Get("/aturan/{tahunAjaranID}", nilaiAkhirHandler.GetAturan)
+			r.With(auth.Require(auth.PermMasterDataManage)).Put("/aturan/{tahunAjaranID}", nilaiAkhirHandler.UpsertAturan)
+			r.With(auth.Require(auth.PermMasterDataManage)).Delete("/aturan/{tahunAjaranID}", nilaiAkhirHandler.DeleteAturan)
+			r.With(auth.Require(auth.PermPenilaianRead)).Get("/pengajar/{pengajarKelasID}", nilaiAkhirHandler.GetNilaiAkhirMapel)
+		})
+
+		r.Route("/deskripsi-capaian", func(r chi.Router) {
+			r.With(auth.Require(auth.PermPenilaianRead)).Get("/pengaturan", deskripsiHandler.GetPengaturan)
+			r.With(auth.Require(auth.PermMasterDataManage)).Put("/pengaturan", deskripsiHandler.UpdatePengaturan)
+			r.With(auth.Require(auth.PermPenilaianRead)).Get("/kelas/{kelasID}/pengajar/{pengajarKelasID}", deskripsiHandler.GetByPengajar)
+			r.With(auth.Require(auth.PermPenilaianWrite)).Put("/anggota/{anggotaKelasID}/pengajar/{pengajarKelasID}", deskripsiHandler.Simpan)
+			r.With(auth.Require(auth.PermPenilaianWrite)).Delete("/anggota/{anggotaKelasID}/pengajar/{pengajarKelasID}", deskripsiHandler.Reset)
+		})
+
+		r.Route("/kunci-nilai", func(r chi.Router) {
+			r.With(auth.Require(auth.PermPenilaianRead)).Get("/tahun-ajaran/{tahunAjaranID}", kunciNilaiHandler.GetStatus)
+			r.With(auth.Authorize("admin")).Post("/tahun-ajaran/{tahunAjaranID}/kunci", kunciNilaiHandler.KunciTahunAjaran)
+			r.With(auth.Authorize("admin")).Post("/tahun-ajaran/{tahunAjaranID}/buka", kunciNilaiHandler.BukaTahunAjaran)
+			r.With(auth.Require(auth.PermPenilaianWrite)).Post("/pengajar/{pengajarKelasID}/kunci", kunciNilaiHandler.KunciPengajar)
+			r.With(auth.Authorize("admin")).Post("/pengajar/{pengajarKelasID}/buka", kunciNilaiHandler.BukaPengajar)
 		})
 
 		r.Route("/penilaian-sumatif", func(r chi.Router) {
-			r.With(auth.Authorize("admin", "teacher")).Get("/", penilaianSumatifHandler.GetByTujuanPembelajaranID)
-			r.With(auth.Authorize("admin", "teacher")).Post("/", penilaianSumatifHandler.Create)
-			r.With(auth.Authorize("admin", "teacher")).Put("/{id}", penilaianSumatifHandler.Update)
-			r.With(auth.Authorize("admin", "teacher")).Delete("/{id}", penilaianSumatifHandler.Delete)
+			r.With(auth.Require(auth.PermPenilaianRead)).Get("/", penilaianSumatifHandler.GetByTujuanPembelajaranID)
+			r.With(auth.Require(auth.PermPenilaianWrite)).Post("/", penilaianSumatifHandler.Create)
+			r.With(auth.Require(auth.PermPenilaianWrite)).Put("/{id}", penilaianSumatifHandler.Update)
+			r.With(auth.Require(auth.PermPenilaianWrite)).Delete("/{id}", penilaianSumatifHandler.Delete)
 		})
 
 		r.Route("/jenis-ujian", func(r chi.Router) {
-			// PERBAIKAN: Teacher boleh melihat jenis ujian
-			r.With(auth.Authorize("admin", "teacher")).Get("/", jenisUjianHandler.GetAll)
-			r.With(auth.Authorize("admin")).Post("/", jenisUjianHandler.Create)
-			r.With(auth.Authorize("admin")).Get("/{id}", jenisUjianHandler.GetByID)
-			r.With(auth.Authorize("admin")).Put("/{id}", jenisUjianHandler.Update)
-			r.With(auth.Authorize("admin")).Delete("/{id}", jenisUjianHandler.Delete)
+			r.With(auth.Require(auth.PermJenisUjianRead)).Get("/", jenisUjianHandler.GetAll)
+			r.With(auth.Require(auth.PermMasterDataManage)).Post("/", jenisUjianHandler.Create)
+			r.With(auth.Require(auth.PermMasterDataManage)).Get("/{id}", jenisUjianHandler.GetByID)
+			r.With(auth.Require(auth.PermMasterDataManage)).Put("/{id}", jenisUjianHandler.Update)
+			r.With(auth.Require(auth.PermMasterDataManage)).Delete("/{id}", jenisUjianHandler.Delete)
 		})
 
 		r.Route("/paper-size", func(r chi.Router) {
-			r.With(auth.Authorize("admin")).Get("/", paperSizeHandler.GetAll)
-			r.With(auth.Authorize("admin")).Post("/", paperSizeHandler.Create)
-			r.With(auth.Authorize("admin")).Put("/{id}", paperSizeHandler.Update)
-			r.With(auth.Authorize("admin")).Delete("/{id}", paperSizeHandler.Delete)
+			r.With(auth.Require(auth.PermMasterDataManage)).Get("/", paperSizeHandler.GetAll)
+			r.With(auth.Require(auth.PermMasterDataManage)).Post("/", paperSizeHandler.Create)
+			r.With(auth.Require(auth.PermMasterDataManage)).Put("/{id}", paperSizeHandler.Update)
+			r.With(auth.Require(auth.PermMasterDataManage)).Delete("/{id}", paperSizeHandler.Delete)
 		})
 
 		r.Route("/ujian-master", func(r chi.Router) {
-			r.With(auth.Authorize("admin")).Post("/", ujianMasterHandler.Create)
-			r.With(auth.Authorize("admin")).Get("/tahun-ajaran/{taID}", ujianMasterHandler.GetAllByTA)
-			r.With(auth.Authorize("admin")).Get("/{id}", ujianMasterHandler.GetByID)
-			r.With(auth.Authorize("admin")).Put("/{id}", ujianMasterHandler.Update)
-			r.With(auth.Authorize("admin")).Delete("/{id}", ujianMasterHandler.Delete)
-			r.With(auth.Authorize("admin")).Post("/{id}/assign-kelas", ujianMasterHandler.AssignKelas)
-			r.With(auth.Authorize("admin")).Get("/{id}/peserta", ujianMasterHandler.GetPesertaUjian)
-			r.With(auth.Authorize("admin")).Post("/{id}/peserta", ujianMasterHandler.AddPesertaFromKelas)
-			r.With(auth.Authorize("admin")).Delete("/{id}/peserta/kelas/{kelasID}", ujianMasterHandler.DeletePesertaFromKelas)
-			r.With(auth.Authorize("admin")).Post("/{id}/generate-nomor-ujian", ujianMasterHandler.GenerateNomorUjian)
-
-			r.With(auth.Authorize("admin")).Get("/{id}/export-excel", ujianMasterHandler.ExportPesertaToExcel)
-			r.With(auth.Authorize("admin")).Post("/{id}/import-excel", ujianMasterHandler.ImportPesertaFromExcel)
-
-			r.With(auth.Authorize("admin")).Get("/{ujianMasterID}/kartu-ujian/filters", ujianMasterHandler.GetKartuUjianFilters)
-			r.With(auth.Authorize("admin")).Get("/{ujianMasterID}/kartu-ujian", ujianMasterHandler.GetKartuUjianData)
-			r.With(auth.Authorize("admin")).Post("/{ujianMasterID}/kartu-ujian/export-pdf", ujianMasterHandler.GenerateKartuUjianPDF)
-
-			r.With(auth.Authorize("admin")).Get("/ruangan", ujianMasterHandler.GetAllRuangan)
-			r.With(auth.Authorize("admin")).Post("/ruangan", ujianMasterHandler.CreateRuangan)
-			r.With(auth.Authorize("admin")).Put("/ruangan/{ruanganID}", ujianMasterHandler.UpdateRuangan)
-			r.With(auth.Authorize("admin")).Delete("/ruangan/{ruanganID}", ujianMasterHandler.DeleteRuangan)
-
-			r.With(auth.Authorize("admin")).Post("/{id}/alokasi-ruangan", ujianMasterHandler.AssignRuangan)
-			r.With(auth.Authorize("admin")).Get("/{id}/alokasi-ruangan", ujianMasterHandler.GetAlokasiRuangan)
-			r.With(auth.Authorize("admin")).Delete("/{id}/alokasi-ruangan/{alokasiRuanganID}", ujianMasterHandler.RemoveAlokasiRuangan)
-
-			r.With(auth.Authorize("admin")).Get("/{id}/alokasi-kursi", ujianMasterHandler.GetAlokasiKursi)
-			r.With(auth.Authorize("admin")).Post("/{id}/alokasi-kursi/manual", ujianMasterHandler.UpdateSeating)
-			r.With(auth.Authorize("admin")).Post("/{id}/alokasi-kursi/smart", ujianMasterHandler.DistributeSmart)
+			r.With(auth.Require(auth.PermUjianManage)).Post("/", ujianMasterHandler.Create)
+			r.With(auth.Require(auth.PermUjianManage)).Get("/tahun-ajaran/{taID}", ujianMasterHandler.GetAllByTA)
+			r.With(auth.Require(auth.PermUjianManage)).Get("/{id}", ujianMasterHandler.GetByID)
+			r.With(auth.Require(auth.PermUjianManage)).Put("/{id}", ujianMasterHandler.Update)
+			r.With(auth.Require(auth.PermUjianManage)).Delete("/{id}", ujianMasterHandler.Delete)
+			r.With(auth.Require(auth.PermUjianManage)).Post("/{id}/assign-kelas", ujianMasterHandler.AssignKelas)
+			r.With(auth.Require(auth.PermUjianManage)).Get("/{id}/peserta", ujianMasterHandler.GetPesertaUjian)
+			r.With(auth.Require(auth.PermUjianManage)).Post("/{id}/peserta", ujianMasterHandler.AddPesertaFromKelas)
+			r.With(auth.Require(auth.PermUjianManage)).Delete("/{id}/peserta/kelas/{kelasID}", ujianMasterHandler.DeletePesertaFromKelas)
+			r.With(auth.Require(auth.PermUjianManage)).Post("/{id}/generate-nomor-ujian", ujianMasterHandler.GenerateNomorUjian)
+
+			r.With(auth.Require(auth.PermUjianManage)).Get("/{id}/export-excel", ujianMasterHandler.ExportPesertaToExcel)
+			r.With(auth.Require(auth.PermUjianManage)).Post("/{id}/import-excel", ujianMasterHandler.ImportPesertaFromExcel)
+
+			r.With(auth.Require(auth.PermUjianManage)).Get("/{ujianMasterID}/kartu-ujian/filters", ujianMasterHandler.GetKartuUjianFilters)
+			r.With(auth.Require(auth.PermUjianManage)).Get("/{ujianMasterID}/kartu-ujian", ujianMasterHandler.GetKartuUjianData)
+			r.With(auth.Require(auth.PermUjianManage)).Post("/{ujianMasterID}/kartu-ujian/export-pdf", ujianMasterHandler.GenerateKartuUjianPDF)
+
+			r.With(auth.Require(auth.PermUjianManage)).Get("/ruangan", ujianMasterHandler.GetAllRuangan)
+			r.With(auth.Require(auth.PermUjianManage)).Post("/ruangan", ujianMasterHandler.CreateRuangan)
+			r.With(auth.Require(auth.PermUjianManage)).Put("/ruangan/{ruanganID}", ujianMasterHandler.UpdateRuangan)
+			r.With(auth.Require(auth.PermUjianManage)).Delete("/ruangan/{ruanganID}", ujianMasterHandler.DeleteRuangan)
+
+			r.With(auth.Require(auth.PermUjianManage)).Post("/{id}/alokasi-ruangan", ujianMasterHandler.AssignRuangan)
+			r.With(auth.Require(auth.PermUjianManage)).Get("/{id}/alokasi-ruangan", ujianMasterHandler.GetAlokasiRuangan)
+			r.With(auth.Require(auth.PermUjianManage)).Delete("/{id}/alokasi-ruangan/{alokasiRuanganID}", ujianMasterHandler.RemoveAlokasiRuangan)
+
+			r.With(auth.Require(auth.PermUjianManage)).Get("/{id}/alokasi-kursi", ujianMasterHandler.GetAlokasiKursi)
+			r.With(auth.Require(auth.PermUjianManage)).Post("/{id}/alokasi-kursi/manual", ujianMasterHandler.UpdateSeating)
+			r.With(auth.Require(auth.PermUjianManage)).Post("/{id}/alokasi-kursi/smart", ujianMasterHandler.DistributeSmart)
 		})
 
 		r.Route("/presensi", func(r chi.Router) {
-			r.With(auth.Authorize("admin")).Get("/kelas/{kelasID}", presensiHandler.GetPresensi)
-			r.With(auth.Authorize("admin")).Post("/", presensiHandler.UpsertPresensi)
-			r.With(auth.Authorize("admin")).Delete("/", presensiHandler.DeletePresensi)
+			r.With(auth.Require(auth.PermPresensiRead)).Get("/kelas/{kelasID}", presensiHandler.GetPresensi)
+			r.With(auth.Require(auth.PermPresensiWrite)).Post("/", presensiHandler.UpsertPresensi)
+			r.With(auth.Require(auth.PermPresensiWrite)).Delete("/", presensiHandler.DeletePresensi)
+
+			// Presensi per jam dicatat guru mapel; kepemilikan pengajar_kelas diperiksa di service.
+			r.With(auth.RequireAny(auth.PermPresensiRead, auth.PermPresensiJamWrite)).Get("/jam/pengajar/{pengajarKelasID}", presensiJamHandler.GetByPengajar)
+			r.With(auth.Require(auth.PermPresensiRead)).Get("/jam/kelas/{kelasID}", presensiJamHandler.GetByKelas)
+			r.With(auth.Require(auth.PermPresensiJamWrite)).Post("/jam", presensiJamHandler.Upsert)
+			r.With(auth.Require(auth.PermPresensiJamWrite)).Delete("/jam", presensiJamHandler.Delete)
+		})
+
+		r.Route("/rekap-presensi", func(r chi.Router) {
+			r.With(auth.Require(auth.PermPresensiRead)).Get("/siswa/{studentID}", rekapPresensiHandler.GetRekapSiswa)
+			r.With(auth.Require(auth.PermPresensiRead)).Get("/kelas/{kelasID}", rekapPresensiHandler.GetRekapKelas)
+			r.With(auth.Require(auth.PermPresensiRead)).Get("/tingkatan/{tingkatanID}", rekapPresensiHandler.GetRekapTingkatan)
+			r.With(auth.Require(auth.PermPresensiRead)).Get("/sekolah", rekapPresensiHandler.GetRekapSekolah)
+		})
+
+		r.Route("/jurnal-mengajar", func(r chi.Router) {
+			r.With(auth.Require(auth.PermJurnalRekap)).Get("/rekap", jurnalMengajarHandler.GetRekapBulanan)
+			r.With(auth.Require(auth.PermJurnalRead)).Get("/pengajar/{pengajarKelasID}", jurnalMengajarHandler.GetByPengajar)
+			r.With(auth.Require(auth.PermJurnalRead)).Get("/pengajar/{pengajarKelasID}/perbandingan", jurnalMengajarHandler.GetPerbandingan)
+			r.With(auth.Require(auth.PermJurnalWrite)).Post("/", jurnalMengajarHandler.Create)
+			r.With(auth.Require(auth.PermJurnalRead)).Get("/{id}", jurnalMengajarHandler.GetByID)
+			r.With(auth.Require(auth.PermJurnalWrite)).Put("/{id}", jurnalMengajarHandler.Update)
+			r.With(auth.Require(auth.PermJurnalWrite)).Delete("/{id}", jurnalMengajarHandler.Delete)
+		})
+
+		r.Route("/jadwal", func(r chi.Router) {
+			r.With(auth.Require(auth.PermJadwalRead)).Get("/tahun-ajaran/{tahunAjaranID}/pengaturan", jadwalHandler.GetPengaturan)
+			r.With(auth.Require(auth.PermJadwalManage)).Put("/tahun-ajaran/{tahunAjaranID}/jam", jadwalHandler.SimpanJam)
+			r.With(auth.Require(auth.PermJadwalManage)).Put("/tahun-ajaran/{tahunAjaranID}/beban", jadwalHandler.SimpanBeban)
+			r.With(auth.Require(auth.PermJadwalManage)).Post("/tahun-ajaran/{tahunAjaranID}/guru-tidak-tersedia", jadwalHandler.CreateGuruTidakTersedia)
+			r.With(auth.Require(auth.PermJadwalManage)).Delete("/tahun-ajaran/{tahunAjaranID}/guru-tidak-tersedia/{id}", jadwalHandler.DeleteGuruTidakTersedia)
+			r.With(auth.Require(auth.PermJadwalManage)).Post("/tahun-ajaran/{tahunAjaranID}/generate", jadwalHandler.Generate)
+			r.With(auth.Require(auth.PermJadwalRead)).Get("/kelas/{kelasID}", jadwalHandler.GetJadwalKelas)
+			r.With(auth.Require(auth.PermJadwalRead)).Get("/guru/{teacherID}", jadwalHandler.GetJadwalGuru)
+			r.With(auth.Require(auth.PermJadwalManage)).Post("/", jadwalHandler.Create)
+			r.With(auth.Require(auth.PermJadwalManage)).Put("/{id}", jadwalHandler.Update)
+			r.With(auth.Require(auth.PermJadwalManage)).Delete("/{id}", jadwalHandler.Delete)
 		})
 
 		r.Route("/prestasi", func(r chi.Router) {
-			r.With(auth.Authorize("admin")).Get("/", prestasiHandler.GetAllByTahunAjaran)
-			r.With(auth.Authorize("admin")).Post("/", prestasiHandler.Create)
-			r.With(auth.Authorize("admin")).Delete("/{id}", prestasiHandler.Delete)
+			r.With(auth.Require(auth.PermPrestasiRead)).Get("/", prestasiHandler.GetAllByTahunAjaran)
+			r.With(auth.Require(auth.PermPrestasiWrite)).Post("/", prestasiHandler.Create)
+			r.With(auth.Require(auth.PermPrestasiWrite)).Delete("/{id}", prestasiHandler.Delete)
 		})
 	})
 
@@ -1,4 +1,5 @@
 -- Create enums in public schema
+-- scope: public
 SET search_path TO public;
 
 DO $$ 
@@ -1,4 +1,5 @@
 -- file: backend/db/migrations/005_add_foundations.sql
+-- scope: public
 
 -- 1. Buat tabel baru untuk menyimpan data naungan di skema public.
 CREATE TABLE IF NOT EXISTS public.naungan (
@@ -0,0 +1,18 @@
+-- file: backend/db/migrations/036_add_refresh_tokens.sql
+
+-- Refresh token disimpan per skema tenant. Yang disimpan hanya hash SHA-256-nya,
+-- bukan token aslinya. Satu sesi login (session_id) dapat memiliki banyak baris
+-- karena setiap refresh merotasi token lama menjadi token baru.
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    replaced_by UUID,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_session_id ON refresh_tokens(session_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens(user_id);
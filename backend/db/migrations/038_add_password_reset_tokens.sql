@@ -0,0 +1,14 @@
+-- file: backend/db/migrations/038_add_password_reset_tokens.sql
+
+-- Token reset password (lupa password) per skema tenant. Hanya hash SHA-256
+-- yang disimpan. Token bersifat sekali pakai (used_at) dan memiliki masa berlaku.
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user_id ON password_reset_tokens(user_id);
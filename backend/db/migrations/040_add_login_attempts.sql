@@ -0,0 +1,37 @@
+-- file: backend/db/migrations/040_add_login_attempts.sql
+-- scope: public
+
+-- Penghitung percobaan login gagal per akun (skema + email). schema_name kosong
+-- berarti akun superadmin. Disimpan di public agar percobaan dengan ID Sekolah
+-- yang salah pun tetap tercatat lewat penghitung per IP.
+CREATE TABLE IF NOT EXISTS public.login_attempts (
+    schema_name VARCHAR(100) NOT NULL DEFAULT '',
+    email VARCHAR(255) NOT NULL,
+    failed_count INT NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMPTZ,
+    locked_until TIMESTAMPTZ,
+    PRIMARY KEY (schema_name, email)
+);
+
+-- Penghitung percobaan login gagal per alamat IP.
+CREATE TABLE IF NOT EXISTS public.login_ip_attempts (
+    ip_address VARCHAR(64) PRIMARY KEY,
+    failed_count INT NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMPTZ,
+    locked_until TIMESTAMPTZ
+);
+
+-- Riwayat penguncian dan pembukaan kunci akun/IP.
+CREATE TABLE IF NOT EXISTS public.login_lockout_audit (
+    id BIGSERIAL PRIMARY KEY,
+    action VARCHAR(20) NOT NULL,
+    schema_name VARCHAR(100),
+    email VARCHAR(255),
+    ip_address VARCHAR(64),
+    failed_count INT,
+    locked_until TIMESTAMPTZ,
+    actor_user_id UUID,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_lockout_audit_schema_name ON public.login_lockout_audit(schema_name);
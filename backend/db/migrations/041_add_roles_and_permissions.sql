@@ -0,0 +1,60 @@
+-- file: backend/db/migrations/041_add_roles_and_permissions.sql
+
+-- 1. Role per sekolah. Role sistem memiliki kode yang sama dengan users.role
+--    ('teacher') dan tidak dapat dihapus. Role admin tidak disimpan karena
+--    selalu memiliki semua permission.
+CREATE TABLE IF NOT EXISTS roles (
+    id SERIAL PRIMARY KEY,
+    nama_role VARCHAR(100) NOT NULL UNIQUE,
+    deskripsi TEXT,
+    kode VARCHAR(50) UNIQUE,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- 2. Permission yang dimiliki setiap role (nama permission dari katalog aplikasi).
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id INTEGER NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    permission VARCHAR(100) NOT NULL,
+    PRIMARY KEY (role_id, permission)
+);
+
+-- 3. Role yang otomatis didapat oleh pemegang suatu jabatan.
+CREATE TABLE IF NOT EXISTS jabatan_roles (
+    jabatan_id INTEGER NOT NULL REFERENCES jabatan(id) ON DELETE CASCADE,
+    role_id INTEGER NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    PRIMARY KEY (jabatan_id, role_id)
+);
+
+-- 4. Jabatan yang dipegang oleh guru.
+CREATE TABLE IF NOT EXISTS teacher_jabatan (
+    teacher_id UUID NOT NULL REFERENCES teachers(id) ON DELETE CASCADE,
+    jabatan_id INTEGER NOT NULL REFERENCES jabatan(id) ON DELETE CASCADE,
+    PRIMARY KEY (teacher_id, jabatan_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_teacher_jabatan_jabatan_id ON teacher_jabatan(jabatan_id);
+
+-- 5. Role sistem "Guru" dengan hak akses yang sebelumnya ditulis langsung di route.
+INSERT INTO roles (nama_role, deskripsi, kode)
+VALUES ('Guru', 'Hak akses dasar untuk semua akun guru', 'teacher')
+ON CONFLICT (kode) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT r.id, p.permission
+FROM roles r
+CROSS JOIN (VALUES
+    ('guru:read'),
+    ('siswa:read'),
+    ('tahun-ajaran:read'),
+    ('mapel:read'),
+    ('kurikulum:read'),
+    ('rombel:read'),
+    ('pembelajaran:read'),
+    ('pembelajaran:write'),
+    ('penilaian:read'),
+    ('penilaian:write'),
+    ('jenis-ujian:read')
+) AS p(permission)
+WHERE r.kode = 'teacher'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,18 @@
+-- file: backend/db/migrations/042_add_audit_logs.sql
+
+-- Jejak perubahan data per sekolah. user_id tidak diberi foreign key agar
+-- catatan tetap ada walaupun akun pelakunya sudah dihapus.
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID,
+    action VARCHAR(50) NOT NULL,
+    entity VARCHAR(100) NOT NULL,
+    entity_id VARCHAR(255) NOT NULL,
+    before_data JSONB,
+    after_data JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_entity ON audit_logs(entity, entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_user_id ON audit_logs(user_id);
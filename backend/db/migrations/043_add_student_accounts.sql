@@ -0,0 +1,5 @@
+-- file: backend/db/migrations/043_add_student_accounts.sql
+
+-- Akun login portal siswa. Akun siswa disimpan di tabel users dengan role
+-- 'siswa' dan dihubungkan ke data siswa melalui students.user_id.
+ALTER TABLE students ADD COLUMN IF NOT EXISTS user_id UUID UNIQUE REFERENCES users(id) ON DELETE SET NULL;
@@ -0,0 +1,15 @@
+-- file: backend/db/migrations/044_add_pengumuman.sql
+
+-- Pengumuman sekolah. target menentukan siapa yang dapat membacanya di portal:
+-- 'SEMUA' (siswa dan wali murid), 'SISWA', atau 'WALI'.
+CREATE TABLE IF NOT EXISTS pengumuman (
+    id UUID PRIMARY KEY,
+    judul VARCHAR(255) NOT NULL,
+    isi TEXT NOT NULL,
+    target VARCHAR(10) NOT NULL DEFAULT 'SEMUA' CHECK (target IN ('SEMUA', 'SISWA', 'WALI')),
+    tanggal_terbit TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_pengumuman_tanggal_terbit ON pengumuman(tanggal_terbit DESC);
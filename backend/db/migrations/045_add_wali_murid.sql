@@ -0,0 +1,25 @@
+-- file: backend/db/migrations/045_add_wali_murid.sql
+
+-- 1. Wali murid (orang tua/wali) dengan akun login sendiri. Akun disimpan di
+--    tabel users dengan role 'wali_murid'.
+CREATE TABLE IF NOT EXISTS wali_murid (
+    id UUID PRIMARY KEY,
+    user_id UUID UNIQUE REFERENCES users(id) ON DELETE SET NULL,
+    nama_lengkap VARCHAR(255) NOT NULL,
+    nomor_telepon VARCHAR(30),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Nomor telepon dipakai untuk mengenali wali yang sama pada beberapa siswa (kakak-adik).
+CREATE UNIQUE INDEX IF NOT EXISTS idx_wali_murid_nomor_telepon ON wali_murid(nomor_telepon) WHERE nomor_telepon IS NOT NULL;
+
+-- 2. Relasi wali murid dengan siswa (many-to-many).
+CREATE TABLE IF NOT EXISTS wali_murid_siswa (
+    wali_murid_id UUID NOT NULL REFERENCES wali_murid(id) ON DELETE CASCADE,
+    student_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    hubungan VARCHAR(10) NOT NULL DEFAULT 'WALI' CHECK (hubungan IN ('AYAH', 'IBU', 'WALI')),
+    PRIMARY KEY (wali_murid_id, student_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_wali_murid_siswa_student_id ON wali_murid_siswa(student_id);
@@ -0,0 +1,9 @@
+-- file: backend/db/migrations/046_add_rapor_permission.sql
+
+-- Guru boleh mencetak rapor. Pembatasan ke kelas yang diwalikan tetap
+-- dilakukan oleh aturan akses baris (wali kelas).
+INSERT INTO role_permissions (role_id, permission)
+SELECT r.id, 'rapor:read'
+FROM roles r
+WHERE r.kode = 'teacher'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,23 @@
+-- file: backend/db/migrations/047_add_aturan_nilai.sql
+
+-- 1. Aturan perhitungan nilai akhir per tahun ajaran. bobot_formatif adalah bobot
+--    rata-rata nilai TP; sisanya dibagi ke jenis ujian di tabel bobot_jenis_ujian.
+--    kebijakan_nilai_kosong: 'NOL' (nilai kosong dihitung 0), 'ABAIKAN' (nilai kosong
+--    dilewati), atau 'BLOKIR' (nilai akhir tidak dihitung selama ada nilai kosong).
+CREATE TABLE IF NOT EXISTS aturan_nilai (
+    id SERIAL PRIMARY KEY,
+    tahun_ajaran_id UUID NOT NULL UNIQUE REFERENCES tahun_ajaran(id) ON DELETE CASCADE,
+    bobot_formatif NUMERIC(5,2) NOT NULL DEFAULT 0,
+    kebijakan_nilai_kosong VARCHAR(10) NOT NULL DEFAULT 'ABAIKAN'
+        CHECK (kebijakan_nilai_kosong IN ('NOL', 'ABAIKAN', 'BLOKIR')),
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- 2. Bobot setiap jenis ujian (misalnya STS dan SAS) pada aturan nilai.
+CREATE TABLE IF NOT EXISTS bobot_jenis_ujian (
+    aturan_nilai_id INTEGER NOT NULL REFERENCES aturan_nilai(id) ON DELETE CASCADE,
+    jenis_ujian_id INTEGER NOT NULL REFERENCES jenis_ujian(id) ON DELETE CASCADE,
+    bobot NUMERIC(5,2) NOT NULL CHECK (bobot >= 0),
+    PRIMARY KEY (aturan_nilai_id, jenis_ujian_id)
+);
@@ -0,0 +1,45 @@
+-- file: backend/db/migrations/048_add_kktp_and_remedial.sql
+
+-- 1. Kriteria ketercapaian tujuan pembelajaran (KKTP/KKM). Cakupan dapat berupa satu TP,
+--    atau kombinasi mapel dan/atau tingkatan. Urutan prioritas: TP, mapel + tingkatan,
+--    mapel saja, lalu tingkatan saja.
+CREATE TABLE IF NOT EXISTS kktp (
+    id SERIAL PRIMARY KEY,
+    mata_pelajaran_id UUID REFERENCES mata_pelajaran(id) ON DELETE CASCADE,
+    tingkatan_id INTEGER REFERENCES tingkatan(id) ON DELETE CASCADE,
+    tujuan_pembelajaran_id INTEGER REFERENCES tujuan_pembelajaran(id) ON DELETE CASCADE,
+    nilai_minimal NUMERIC(5,2) NOT NULL CHECK (nilai_minimal >= 0 AND nilai_minimal <= 100),
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    CONSTRAINT kktp_cakupan_check CHECK (
+        (tujuan_pembelajaran_id IS NOT NULL AND mata_pelajaran_id IS NULL AND tingkatan_id IS NULL)
+        OR (tujuan_pembelajaran_id IS NULL AND (mata_pelajaran_id IS NOT NULL OR tingkatan_id IS NOT NULL))
+    )
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_kktp_cakupan ON kktp (
+    COALESCE(mata_pelajaran_id::text, ''), COALESCE(tingkatan_id, 0), COALESCE(tujuan_pembelajaran_id, 0)
+);
+
+-- 2. Remedial untuk satu nilai formatif (TP) atau satu nilai sumatif. nilai_asli menyimpan
+--    nilai sebelum remedial; kolom nilai di penilaian/nilai_sumatif_siswa selalu berisi
+--    nilai yang dipakai (nilai_dipakai), sehingga perhitungan lain tidak perlu berubah.
+CREATE TABLE IF NOT EXISTS remedial (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    anggota_kelas_id UUID NOT NULL REFERENCES anggota_kelas(id) ON DELETE CASCADE,
+    tujuan_pembelajaran_id INTEGER REFERENCES tujuan_pembelajaran(id) ON DELETE CASCADE,
+    penilaian_sumatif_id UUID REFERENCES penilaian_sumatif(id) ON DELETE CASCADE,
+    nilai_asli NUMERIC(5,2) NOT NULL,
+    nilai_remedial NUMERIC(5,2) NOT NULL,
+    nilai_dipakai VARCHAR(10) NOT NULL DEFAULT 'REMEDIAL' CHECK (nilai_dipakai IN ('ASLI', 'REMEDIAL')),
+    tanggal DATE NOT NULL DEFAULT CURRENT_DATE,
+    catatan TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    CONSTRAINT remedial_sumber_check CHECK ((tujuan_pembelajaran_id IS NULL) <> (penilaian_sumatif_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_remedial_tp ON remedial (anggota_kelas_id, tujuan_pembelajaran_id)
+    WHERE tujuan_pembelajaran_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_remedial_sumatif ON remedial (anggota_kelas_id, penilaian_sumatif_id)
+    WHERE penilaian_sumatif_id IS NOT NULL;
@@ -0,0 +1,23 @@
+-- file: backend/db/migrations/049_add_kunci_nilai.sql
+
+-- Status finalisasi nilai. Baris dengan pengajar_kelas_id NULL berlaku untuk seluruh
+-- tahun ajaran; baris per pengajar_kelas menimpanya. Baris tidak dihapus saat kunci
+-- dibuka (terkunci = FALSE) agar perubahan setelah finalisasi dapat ditandai.
+CREATE TABLE IF NOT EXISTS kunci_nilai (
+    id SERIAL PRIMARY KEY,
+    tahun_ajaran_id UUID NOT NULL REFERENCES tahun_ajaran(id) ON DELETE CASCADE,
+    pengajar_kelas_id UUID REFERENCES pengajar_kelas(id) ON DELETE CASCADE,
+    terkunci BOOLEAN NOT NULL DEFAULT TRUE,
+    dikunci_oleh UUID,
+    dikunci_pada TIMESTAMPTZ,
+    dibuka_oleh UUID,
+    dibuka_pada TIMESTAMPTZ,
+    alasan_buka TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_kunci_nilai_tahun_ajaran ON kunci_nilai (tahun_ajaran_id)
+    WHERE pengajar_kelas_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_kunci_nilai_pengajar_kelas ON kunci_nilai (pengajar_kelas_id)
+    WHERE pengajar_kelas_id IS NOT NULL;
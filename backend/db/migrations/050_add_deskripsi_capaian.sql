@@ -0,0 +1,27 @@
+-- file: backend/db/migrations/050_add_deskripsi_capaian.sql
+
+-- 1. Pengaturan generator deskripsi capaian rapor. Setiap tenant (schema) hanya punya
+--    satu baris. Template boleh memakai {nama}, {mapel}, dan {tp}.
+--    TP tertinggi disebut dengan template_tinggi jika nilainya >= ambang_tinggi;
+--    TP terendah disebut dengan template_rendah jika nilainya < ambang_rendah.
+CREATE TABLE IF NOT EXISTS pengaturan_deskripsi (
+    id INTEGER PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    template_tinggi TEXT NOT NULL,
+    template_rendah TEXT NOT NULL,
+    ambang_tinggi NUMERIC(5,2) NOT NULL,
+    ambang_rendah NUMERIC(5,2) NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    CONSTRAINT chk_pengaturan_deskripsi_ambang CHECK (ambang_rendah <= ambang_tinggi)
+);
+
+-- 2. Deskripsi capaian yang ditulis ulang oleh guru. Jika tidak ada baris untuk
+--    siswa dan mapel, rapor memakai deskripsi hasil generator.
+CREATE TABLE IF NOT EXISTS deskripsi_capaian (
+    anggota_kelas_id UUID NOT NULL REFERENCES anggota_kelas(id) ON DELETE CASCADE,
+    pengajar_kelas_id UUID NOT NULL REFERENCES pengajar_kelas(id) ON DELETE CASCADE,
+    deskripsi TEXT NOT NULL,
+    diubah_oleh UUID,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (anggota_kelas_id, pengajar_kelas_id)
+);
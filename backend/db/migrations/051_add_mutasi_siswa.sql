@@ -0,0 +1,28 @@
+-- file: backend/db/migrations/051_add_mutasi_siswa.sql
+
+-- 1. Keanggotaan kelas yang ditutup karena siswa pindah/keluar. Baris tidak dihapus
+--    agar nilai dan presensi yang sudah tercatat tetap utuh.
+ALTER TABLE "anggota_kelas" ADD COLUMN IF NOT EXISTS "tanggal_keluar" DATE;
+
+-- 2. Mutasi siswa masuk (dari sekolah lain) dan keluar (pindah sekolah/keluar).
+--    kelas_id adalah rombel tempat siswa ditempatkan (masuk) atau ditinggalkan (keluar).
+CREATE TABLE IF NOT EXISTS mutasi_siswa (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    student_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    jenis VARCHAR(10) NOT NULL CHECK (jenis IN ('MASUK', 'KELUAR')),
+    tanggal DATE NOT NULL,
+    nomor_surat VARCHAR(100),
+    nama_sekolah VARCHAR(255),
+    npsn_sekolah VARCHAR(20),
+    alamat_sekolah TEXT,
+    alasan TEXT,
+    kelas_id UUID REFERENCES kelas(id) ON DELETE SET NULL,
+    riwayat_akademik_id UUID REFERENCES riwayat_akademik(id) ON DELETE SET NULL,
+    dicatat_oleh UUID,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_mutasi_siswa_nomor_surat ON mutasi_siswa (nomor_surat) WHERE nomor_surat IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_mutasi_siswa_tanggal ON mutasi_siswa (tanggal);
+CREATE INDEX IF NOT EXISTS idx_mutasi_siswa_student ON mutasi_siswa (student_id);
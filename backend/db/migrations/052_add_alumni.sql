@@ -0,0 +1,28 @@
+-- file: backend/db/migrations/052_add_alumni.sql
+
+-- Data alumni melengkapi siswa yang status terakhirnya 'Lulus' di riwayat_akademik.
+-- Baris bersifat opsional: siswa lulus tanpa baris di sini tetap tampil sebagai alumni,
+-- dengan tahun lulus diambil dari tanggal riwayat kelulusannya.
+-- kelas_terakhir, rata_rata_nilai, dan jumlah_mapel adalah ringkasan nilai akhir di
+-- kelas terakhir siswa yang diisi saat sinkronisasi.
+CREATE TABLE IF NOT EXISTS alumni (
+    student_id UUID PRIMARY KEY REFERENCES students(id) ON DELETE CASCADE,
+    tahun_lulus INTEGER,
+    nomor_ijazah VARCHAR(100),
+    tanggal_ijazah DATE,
+    kelas_terakhir VARCHAR(100),
+    rata_rata_nilai NUMERIC(5,2),
+    jumlah_mapel INTEGER,
+    kegiatan VARCHAR(20) CHECK (kegiatan IN ('STUDI', 'BEKERJA', 'WIRAUSAHA', 'LAINNYA')),
+    institusi_studi VARCHAR(255),
+    program_studi VARCHAR(255),
+    tempat_kerja VARCHAR(255),
+    jabatan VARCHAR(255),
+    kontak VARCHAR(100),
+    catatan TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_alumni_nomor_ijazah ON alumni (nomor_ijazah) WHERE nomor_ijazah IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_alumni_tahun_lulus ON alumni (tahun_lulus);
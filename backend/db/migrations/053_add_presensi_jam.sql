@@ -0,0 +1,32 @@
+-- file: backend/db/migrations/053_add_presensi_jam.sql
+
+-- 1. Asal baris presensi harian. 'HARIAN' diisi langsung (wali kelas/admin), 'JAM' adalah
+--    rekap otomatis dari presensi per jam pelajaran dan boleh ditimpa ulang oleh rekap.
+ALTER TABLE "presensi" ADD COLUMN IF NOT EXISTS "sumber" VARCHAR(10) NOT NULL DEFAULT 'HARIAN'
+    CHECK ("sumber" IN ('HARIAN', 'JAM'));
+
+-- 2. Presensi per jam pelajaran, dicatat oleh guru mapel (pengajar_kelas) yang mengajar
+--    pada jam tersebut. Satu siswa hanya punya satu status per jam per tanggal.
+CREATE TABLE IF NOT EXISTS presensi_jam (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    anggota_kelas_id UUID NOT NULL REFERENCES anggota_kelas(id) ON DELETE CASCADE,
+    pengajar_kelas_id UUID NOT NULL REFERENCES pengajar_kelas(id) ON DELETE CASCADE,
+    tanggal DATE NOT NULL,
+    jam_ke SMALLINT NOT NULL CHECK (jam_ke BETWEEN 1 AND 20),
+    status public.status_presensi_enum NOT NULL,
+    catatan TEXT,
+    dicatat_oleh UUID,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    CONSTRAINT presensi_jam_anggota_tanggal_jam_unique UNIQUE (anggota_kelas_id, tanggal, jam_ke)
+);
+
+CREATE INDEX IF NOT EXISTS idx_presensi_jam_pengajar_tanggal ON presensi_jam(pengajar_kelas_id, tanggal);
+
+-- 3. Guru mapel mencatat presensi per jam untuk kelas yang diampunya. Pembatasan ke
+--    pengajar_kelas miliknya tetap dilakukan oleh aturan akses baris.
+INSERT INTO role_permissions (role_id, permission)
+SELECT r.id, 'presensi-jam:write'
+FROM roles r
+WHERE r.kode = 'teacher'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,33 @@
+-- file: backend/db/migrations/054_add_kalender_akademik.sql
+
+-- 1. Pengaturan kalender per tahun ajaran (semester): rentang tanggal semester dan jumlah
+--    hari sekolah dalam seminggu (5 = Senin-Jumat, 6 = Senin-Sabtu).
+CREATE TABLE IF NOT EXISTS kalender_pengaturan (
+    tahun_ajaran_id UUID PRIMARY KEY REFERENCES tahun_ajaran(id) ON DELETE CASCADE,
+    tanggal_mulai DATE,
+    tanggal_selesai DATE,
+    hari_sekolah_per_minggu SMALLINT NOT NULL DEFAULT 6 CHECK (hari_sekolah_per_minggu IN (5, 6)),
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    CHECK (tanggal_selesai IS NULL OR tanggal_mulai IS NULL OR tanggal_selesai >= tanggal_mulai)
+);
+
+-- 2. Agenda kalender akademik. Hanya jenis 'LIBUR' yang mengurangi hari efektif; minggu
+--    ujian dan kegiatan tetap dihitung sebagai hari sekolah. uid dipakai untuk impor ulang
+--    berkas iCalendar (.ics) tanpa menggandakan agenda.
+CREATE TABLE IF NOT EXISTS kalender_agenda (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tahun_ajaran_id UUID NOT NULL REFERENCES tahun_ajaran(id) ON DELETE CASCADE,
+    judul VARCHAR(255) NOT NULL,
+    jenis VARCHAR(20) NOT NULL CHECK (jenis IN ('LIBUR', 'UJIAN', 'KEGIATAN')),
+    tanggal_mulai DATE NOT NULL,
+    tanggal_selesai DATE NOT NULL,
+    keterangan TEXT,
+    uid VARCHAR(255),
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    CHECK (tanggal_selesai >= tanggal_mulai)
+);
+
+CREATE INDEX IF NOT EXISTS idx_kalender_agenda_tahun_ajaran ON kalender_agenda(tahun_ajaran_id, tanggal_mulai);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_kalender_agenda_uid ON kalender_agenda(tahun_ajaran_id, uid) WHERE uid IS NOT NULL;
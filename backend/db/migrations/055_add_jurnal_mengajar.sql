@@ -0,0 +1,55 @@
+-- file: backend/db/migrations/055_add_jurnal_mengajar.sql
+
+-- 1. Jurnal mengajar: satu baris untuk satu pertemuan seorang pengajar mapel di kelasnya.
+--    status_kehadiran adalah kehadiran guru pada pertemuan tersebut:
+--    HADIR (mengajar), TUGAS (tidak hadir, meninggalkan tugas), IZIN, SAKIT, atau DINAS.
+--    materi_lain diisi jika materi yang disampaikan tidak ada di rencana pembelajaran.
+CREATE TABLE IF NOT EXISTS jurnal_mengajar (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    pengajar_kelas_id UUID NOT NULL REFERENCES pengajar_kelas(id) ON DELETE CASCADE,
+    tanggal DATE NOT NULL,
+    jam_ke_mulai SMALLINT NOT NULL CHECK (jam_ke_mulai BETWEEN 1 AND 20),
+    jam_ke_selesai SMALLINT NOT NULL CHECK (jam_ke_selesai BETWEEN 1 AND 20),
+    status_kehadiran VARCHAR(10) NOT NULL DEFAULT 'HADIR'
+        CHECK (status_kehadiran IN ('HADIR', 'TUGAS', 'IZIN', 'SAKIT', 'DINAS')),
+    materi_pembelajaran_id INTEGER REFERENCES materi_pembelajaran(id) ON DELETE SET NULL,
+    materi_lain TEXT,
+    catatan TEXT,
+    dicatat_oleh UUID,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    CONSTRAINT jurnal_mengajar_jam_check CHECK (jam_ke_mulai <= jam_ke_selesai),
+    CONSTRAINT jurnal_mengajar_unique UNIQUE (pengajar_kelas_id, tanggal, jam_ke_mulai)
+);
+
+CREATE INDEX IF NOT EXISTS idx_jurnal_mengajar_tanggal ON jurnal_mengajar(tanggal);
+
+-- 2. Tujuan pembelajaran yang dibahas pada satu pertemuan.
+CREATE TABLE IF NOT EXISTS jurnal_mengajar_tujuan (
+    jurnal_mengajar_id UUID NOT NULL REFERENCES jurnal_mengajar(id) ON DELETE CASCADE,
+    tujuan_pembelajaran_id INTEGER NOT NULL REFERENCES tujuan_pembelajaran(id) ON DELETE CASCADE,
+    PRIMARY KEY (jurnal_mengajar_id, tujuan_pembelajaran_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_jurnal_mengajar_tujuan_tp_id ON jurnal_mengajar_tujuan(tujuan_pembelajaran_id);
+
+-- 3. Siswa yang tidak hadir pada satu pertemuan.
+CREATE TABLE IF NOT EXISTS jurnal_mengajar_absen (
+    jurnal_mengajar_id UUID NOT NULL REFERENCES jurnal_mengajar(id) ON DELETE CASCADE,
+    anggota_kelas_id UUID NOT NULL REFERENCES anggota_kelas(id) ON DELETE CASCADE,
+    status CHAR(1) NOT NULL CHECK (status IN ('S', 'I', 'A')),
+    catatan TEXT,
+    PRIMARY KEY (jurnal_mengajar_id, anggota_kelas_id)
+);
+
+-- 4. Guru boleh mengisi dan melihat jurnalnya sendiri. Rekap bulanan seluruh guru
+--    (jurnal:rekap) hanya diberikan lewat role yang diatur admin.
+INSERT INTO role_permissions (role_id, permission)
+SELECT r.id, p.permission
+FROM roles r
+CROSS JOIN (VALUES
+    ('jurnal:read'),
+    ('jurnal:write')
+) AS p(permission)
+WHERE r.kode = 'teacher'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,64 @@
+-- file: backend/db/migrations/056_add_jadwal_pelajaran.sql
+
+-- 1. Definisi jam pelajaran per hari dalam satu tahun ajaran. hari 1 = Senin s.d. 7 = Minggu.
+--    Jam istirahat ikut didefinisikan agar urutan jam_ke utuh, tetapi tidak diisi pelajaran.
+CREATE TABLE IF NOT EXISTS jadwal_jam (
+    id SERIAL PRIMARY KEY,
+    tahun_ajaran_id UUID NOT NULL REFERENCES tahun_ajaran(id) ON DELETE CASCADE,
+    hari SMALLINT NOT NULL CHECK (hari BETWEEN 1 AND 7),
+    jam_ke SMALLINT NOT NULL CHECK (jam_ke BETWEEN 1 AND 20),
+    jam_mulai TIME NOT NULL,
+    jam_selesai TIME NOT NULL,
+    istirahat BOOLEAN NOT NULL DEFAULT FALSE,
+    CONSTRAINT jadwal_jam_waktu_check CHECK (jam_mulai < jam_selesai),
+    CONSTRAINT jadwal_jam_unique UNIQUE (tahun_ajaran_id, hari, jam_ke)
+);
+
+-- 2. Beban jam pelajaran per minggu untuk setiap mapel di satu tingkatan.
+--    maks_jam_per_hari membatasi jam mapel yang sama dalam satu hari (NULL = tanpa batas).
+CREATE TABLE IF NOT EXISTS jadwal_beban (
+    id SERIAL PRIMARY KEY,
+    tahun_ajaran_id UUID NOT NULL REFERENCES tahun_ajaran(id) ON DELETE CASCADE,
+    tingkatan_id INTEGER NOT NULL REFERENCES tingkatan(id) ON DELETE CASCADE,
+    mata_pelajaran_id UUID NOT NULL REFERENCES mata_pelajaran(id) ON DELETE CASCADE,
+    jam_per_minggu SMALLINT NOT NULL CHECK (jam_per_minggu BETWEEN 1 AND 60),
+    maks_jam_per_hari SMALLINT CHECK (maks_jam_per_hari BETWEEN 1 AND 20),
+    CONSTRAINT jadwal_beban_unique UNIQUE (tahun_ajaran_id, tingkatan_id, mata_pelajaran_id)
+);
+
+-- 3. Waktu ketika guru tidak dapat mengajar. jam_ke NULL berarti sepanjang hari.
+CREATE TABLE IF NOT EXISTS guru_tidak_tersedia (
+    id SERIAL PRIMARY KEY,
+    tahun_ajaran_id UUID NOT NULL REFERENCES tahun_ajaran(id) ON DELETE CASCADE,
+    teacher_id UUID NOT NULL REFERENCES teachers(id) ON DELETE CASCADE,
+    hari SMALLINT NOT NULL CHECK (hari BETWEEN 1 AND 7),
+    jam_ke SMALLINT CHECK (jam_ke BETWEEN 1 AND 20),
+    keterangan TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_guru_tidak_tersedia_ta_teacher ON guru_tidak_tersedia(tahun_ajaran_id, teacher_id);
+
+-- 4. Jadwal pelajaran mingguan. kelas_id disalin dari pengajar_kelas agar satu kelas tidak
+--    dapat terisi dua pelajaran pada jam yang sama. Baris terkunci (hasil edit manual)
+--    dipertahankan saat jadwal dibuat ulang secara otomatis.
+CREATE TABLE IF NOT EXISTS jadwal_pelajaran (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    pengajar_kelas_id UUID NOT NULL REFERENCES pengajar_kelas(id) ON DELETE CASCADE,
+    kelas_id UUID NOT NULL REFERENCES kelas(id) ON DELETE CASCADE,
+    hari SMALLINT NOT NULL CHECK (hari BETWEEN 1 AND 7),
+    jam_ke SMALLINT NOT NULL CHECK (jam_ke BETWEEN 1 AND 20),
+    terkunci BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    CONSTRAINT jadwal_pelajaran_kelas_unique UNIQUE (kelas_id, hari, jam_ke)
+);
+
+CREATE INDEX IF NOT EXISTS idx_jadwal_pelajaran_pengajar_kelas_id ON jadwal_pelajaran(pengajar_kelas_id);
+
+-- 5. Guru boleh melihat jadwal.
+INSERT INTO role_permissions (role_id, permission)
+SELECT r.id, 'jadwal:read'
+FROM roles r
+WHERE r.kode = 'teacher'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,5 @@
+-- file: backend/db/migrations/057_add_template_cukup_deskripsi.sql
+
+-- 1. Template untuk TP tertinggi ketika tidak ada TP yang mencapai ambang_tinggi maupun di
+--    bawah ambang_rendah. String kosong berarti generator memakai kalimat bawaan.
+ALTER TABLE pengaturan_deskripsi ADD COLUMN IF NOT EXISTS template_cukup TEXT NOT NULL DEFAULT '';
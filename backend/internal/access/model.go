@@ -0,0 +1,43 @@
+// file: backend/internal/access/model.go
+package access
+
+// Actor adalah user yang sedang melakukan request.
+type Actor struct {
+	UserID string
+	Role   string
+}
+
+// IsAdmin bernilai true untuk admin sekolah yang boleh mengakses semua kelas.
+func (a Actor) IsAdmin() bool {
+	return a.Role == "admin"
+}
+
+// Target berisi data yang disentuh oleh sebuah request. Setiap referensi akan
+// ditelusuri sampai ke baris pengajar_kelas atau kelas pemiliknya.
+type Target struct {
+	PengajarKelasIDs    []string
+	MateriIDs           []int
+	TujuanIDs           []int
+	UjianIDs            []int
+	PenilaianSumatifIDs []string
+	KelasIDs            []string
+	AnggotaKelasIDs     []string
+}
+
+// PengajarKelasScope adalah pemilik dari satu baris pengajar_kelas. Ref adalah referensi
+// target yang menghasilkan baris ini, misalnya "materi:12" (lihat pengajarKelasRefs).
+type PengajarKelasScope struct {
+	Ref             string
+	PengajarKelasID string
+	KelasID         string
+	TeacherUserID   string
+	WaliUserID      *string
+}
+
+// KelasScope adalah wali kelas dari satu kelas. Ref adalah referensi target yang
+// menghasilkan baris ini (lihat kelasRefs).
+type KelasScope struct {
+	Ref        string
+	KelasID    string
+	WaliUserID *string
+}
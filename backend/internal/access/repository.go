@@ -0,0 +1,137 @@
+// file: backend/internal/access/repository.go
+package access
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"skoola/pkg/database"
+
+	"github.com/lib/pq"
+)
+
+// Repository mendefinisikan interface untuk menelusuri pemilik data pembelajaran dan nilai.
+type Repository interface {
+	GetPengajarKelasScopes(ctx context.Context, schemaName string, target Target) ([]PengajarKelasScope, error)
+	GetKelasScopes(ctx context.Context, schemaName string, kelasIDs []string, anggotaKelasIDs []string) ([]KelasScope, error)
+}
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewRepository membuat instance baru dari postgresRepository.
+func NewRepository(db *sql.DB) Repository {
+	return &postgresRepository{db: db}
+}
+
+// GetPengajarKelasScopes mengembalikan pengajar dan wali kelas dari setiap pengajar_kelas
+// yang dirujuk target, baik langsung maupun lewat materi, tujuan, ujian, atau penilaian sumatif.
+// Referensi yang tidak ditemukan tidak menghasilkan baris.
+func (r *postgresRepository) GetPengajarKelasScopes(ctx context.Context, schemaName string, target Target) ([]PengajarKelasScope, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		WITH target (ref, pengajar_kelas_id) AS (
+			SELECT 'pengajar_kelas:' || t.ref_id, t.ref_id::uuid
+			FROM unnest($1::text[]) AS t(ref_id)
+			UNION
+			SELECT 'materi:' || t.ref_id, m.pengajar_kelas_id
+			FROM unnest($2::int[]) AS t(ref_id)
+			JOIN materi_pembelajaran m ON m.id = t.ref_id
+			UNION
+			SELECT 'tujuan:' || t.ref_id, m.pengajar_kelas_id
+			FROM unnest($3::int[]) AS t(ref_id)
+			JOIN tujuan_pembelajaran tp ON tp.id = t.ref_id
+			JOIN materi_pembelajaran m ON tp.materi_pembelajaran_id = m.id
+			UNION
+			SELECT 'ujian:' || t.ref_id, u.pengajar_kelas_id
+			FROM unnest($4::int[]) AS t(ref_id)
+			JOIN ujian u ON u.id = t.ref_id
+			UNION
+			SELECT 'penilaian_sumatif:' || t.ref_id, COALESCE(m.pengajar_kelas_id, u.pengajar_kelas_id)
+			FROM unnest($5::text[]) AS t(ref_id)
+			JOIN penilaian_sumatif ps ON ps.id = t.ref_id::uuid
+			LEFT JOIN tujuan_pembelajaran tp ON ps.tujuan_pembelajaran_id = tp.id
+			LEFT JOIN materi_pembelajaran m ON tp.materi_pembelajaran_id = m.id
+			LEFT JOIN ujian u ON ps.ujian_id = u.id
+		)
+		SELECT target.ref, pk.id, pk.kelas_id, guru.user_id, wali.user_id
+		FROM target
+		JOIN pengajar_kelas pk ON target.pengajar_kelas_id = pk.id
+		JOIN teachers guru ON pk.teacher_id = guru.id
+		JOIN kelas k ON pk.kelas_id = k.id
+		LEFT JOIN teachers wali ON k.wali_kelas_id = wali.id
+	`
+	rows, err := tx.QueryContext(ctx, query,
+		pq.Array(target.PengajarKelasIDs),
+		pq.Array(target.MateriIDs),
+		pq.Array(target.TujuanIDs),
+		pq.Array(target.UjianIDs),
+		pq.Array(target.PenilaianSumatifIDs),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menelusuri pemilik pengajar kelas: %w", err)
+	}
+	defer rows.Close()
+
+	var scopes []PengajarKelasScope
+	for rows.Next() {
+		var s PengajarKelasScope
+		if err := rows.Scan(&s.Ref, &s.PengajarKelasID, &s.KelasID, &s.TeacherUserID, &s.WaliUserID); err != nil {
+			return nil, fmt.Errorf("gagal memindai data pemilik pengajar kelas: %w", err)
+		}
+		scopes = append(scopes, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return scopes, tx.Commit()
+}
+
+// GetKelasScopes mengembalikan wali dari setiap kelas yang dirujuk langsung
+// atau lewat anggota kelas. Referensi yang tidak ditemukan tidak menghasilkan baris.
+func (r *postgresRepository) GetKelasScopes(ctx context.Context, schemaName string, kelasIDs []string, anggotaKelasIDs []string) ([]KelasScope, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		WITH target (ref, kelas_id) AS (
+			SELECT 'kelas:' || t.ref_id, t.ref_id::uuid
+			FROM unnest($1::text[]) AS t(ref_id)
+			UNION
+			SELECT 'anggota_kelas:' || t.ref_id, ak.kelas_id
+			FROM unnest($2::text[]) AS t(ref_id)
+			JOIN anggota_kelas ak ON ak.id = t.ref_id::uuid
+		)
+		SELECT target.ref, k.id, wali.user_id
+		FROM target
+		JOIN kelas k ON target.kelas_id = k.id
+		LEFT JOIN teachers wali ON k.wali_kelas_id = wali.id
+	`
+	rows, err := tx.QueryContext(ctx, query, pq.Array(kelasIDs), pq.Array(anggotaKelasIDs))
+	if err != nil {
+		return nil, fmt.Errorf("gagal menelusuri wali kelas: %w", err)
+	}
+	defer rows.Close()
+
+	var scopes []KelasScope
+	for rows.Next() {
+		var s KelasScope
+		if err := rows.Scan(&s.Ref, &s.KelasID, &s.WaliUserID); err != nil {
+			return nil, fmt.Errorf("gagal memindai data wali kelas: %w", err)
+		}
+		scopes = append(scopes, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return scopes, tx.Commit()
+}
@@ -0,0 +1,136 @@
+// file: backend/internal/access/service.go
+package access
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"skoola/internal/middleware"
+)
+
+// ErrForbidden dikembalikan jika user tidak berhak mengakses kelas atau mapel yang diminta.
+var ErrForbidden = errors.New("anda tidak memiliki akses ke kelas atau mata pelajaran ini")
+
+// Service mendefinisikan pemeriksaan akses per baris untuk data pembelajaran dan nilai.
+type Service interface {
+	// AuthorizeWrite memastikan actor adalah pengajar dari semua pengajar_kelas yang
+	// dirujuk target, dan semua kelas atau anggota kelas di target berada di kelas tersebut.
+	AuthorizeWrite(ctx context.Context, schemaName string, actor Actor, target Target) error
+	// AuthorizeRead sama seperti AuthorizeWrite, tetapi wali kelas boleh membaca
+	// semua data di kelasnya.
+	AuthorizeRead(ctx context.Context, schemaName string, actor Actor, target Target) error
+}
+
+type service struct {
+	repo Repository
+}
+
+// NewService membuat instance baru dari service.
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+// ActorFromContext mengambil user yang sedang login dari konteks yang diisi oleh AuthMiddleware.
+func ActorFromContext(ctx context.Context) Actor {
+	userID, _ := ctx.Value(middleware.UserIDKey).(string)
+	role, _ := ctx.Value(middleware.UserRoleKey).(string)
+	return Actor{UserID: userID, Role: role}
+}
+
+func (s *service) AuthorizeWrite(ctx context.Context, schemaName string, actor Actor, target Target) error {
+	return s.authorize(ctx, schemaName, actor, target, false)
+}
+
+func (s *service) AuthorizeRead(ctx context.Context, schemaName string, actor Actor, target Target) error {
+	return s.authorize(ctx, schemaName, actor, target, true)
+}
+
+func (s *service) authorize(ctx context.Context, schemaName string, actor Actor, target Target, allowWali bool) error {
+	if actor.IsAdmin() {
+		return nil
+	}
+	if actor.UserID == "" {
+		return ErrForbidden
+	}
+
+	pengajarScopes, err := s.repo.GetPengajarKelasScopes(ctx, schemaName, target)
+	if err != nil {
+		return err
+	}
+
+	// Kelas tempat actor mengajar, diambil dari pengajar_kelas yang lolos pemeriksaan.
+	ownedKelas := make(map[string]bool)
+	ditemukan := make(map[string]bool)
+	for _, ps := range pengajarScopes {
+		isWali := allowWali && ps.WaliUserID != nil && *ps.WaliUserID == actor.UserID
+		if ps.TeacherUserID != actor.UserID && !isWali {
+			return ErrForbidden
+		}
+		ownedKelas[ps.KelasID] = true
+		ditemukan[ps.Ref] = true
+	}
+	// Referensi yang tidak mengarah ke pengajar_kelas mana pun tidak bisa dibuktikan
+	// miliknya, sehingga ditolak.
+	for _, ref := range pengajarKelasRefs(target) {
+		if !ditemukan[ref] {
+			return ErrForbidden
+		}
+	}
+
+	if len(target.KelasIDs) == 0 && len(target.AnggotaKelasIDs) == 0 {
+		return nil
+	}
+	kelasScopes, err := s.repo.GetKelasScopes(ctx, schemaName, target.KelasIDs, target.AnggotaKelasIDs)
+	if err != nil {
+		return err
+	}
+	ditemukan = make(map[string]bool)
+	for _, ks := range kelasScopes {
+		isWali := allowWali && ks.WaliUserID != nil && *ks.WaliUserID == actor.UserID
+		if !ownedKelas[ks.KelasID] && !isWali {
+			return ErrForbidden
+		}
+		ditemukan[ks.Ref] = true
+	}
+	for _, ref := range kelasRefs(target) {
+		if !ditemukan[ref] {
+			return ErrForbidden
+		}
+	}
+	return nil
+}
+
+// pengajarKelasRefs menyusun referensi target yang harus ditemukan pemiliknya oleh
+// GetPengajarKelasScopes. Formatnya sama dengan kolom ref pada query repository.
+func pengajarKelasRefs(target Target) []string {
+	var refs []string
+	for _, id := range target.PengajarKelasIDs {
+		refs = append(refs, "pengajar_kelas:"+id)
+	}
+	for _, id := range target.MateriIDs {
+		refs = append(refs, fmt.Sprintf("materi:%d", id))
+	}
+	for _, id := range target.TujuanIDs {
+		refs = append(refs, fmt.Sprintf("tujuan:%d", id))
+	}
+	for _, id := range target.UjianIDs {
+		refs = append(refs, fmt.Sprintf("ujian:%d", id))
+	}
+	for _, id := range target.PenilaianSumatifIDs {
+		refs = append(refs, "penilaian_sumatif:"+id)
+	}
+	return refs
+}
+
+// kelasRefs menyusun referensi kelas dan anggota kelas yang harus ditemukan oleh
+// GetKelasScopes.
+func kelasRefs(target Target) []string {
+	var refs []string
+	for _, id := range target.KelasIDs {
+		refs = append(refs, "kelas:"+id)
+	}
+	for _, id := range target.AnggotaKelasIDs {
+		refs = append(refs, "anggota_kelas:"+id)
+	}
+	return refs
+}
@@ -0,0 +1,88 @@
+// file: backend/internal/access/service_test.go
+package access
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// stubRepository meniru repository: hanya referensi yang ada di peta yang ditemukan.
+type stubRepository struct {
+	pengajar map[string]PengajarKelasScope
+	kelas    map[string]KelasScope
+}
+
+func (r *stubRepository) GetPengajarKelasScopes(ctx context.Context, schemaName string, target Target) ([]PengajarKelasScope, error) {
+	var list []PengajarKelasScope
+	for _, ref := range pengajarKelasRefs(target) {
+		if s, ok := r.pengajar[ref]; ok {
+			s.Ref = ref
+			list = append(list, s)
+		}
+	}
+	return list, nil
+}
+
+func (r *stubRepository) GetKelasScopes(ctx context.Context, schemaName string, kelasIDs []string, anggotaKelasIDs []string) ([]KelasScope, error) {
+	var list []KelasScope
+	for _, ref := range kelasRefs(Target{KelasIDs: kelasIDs, AnggotaKelasIDs: anggotaKelasIDs}) {
+		if s, ok := r.kelas[ref]; ok {
+			s.Ref = ref
+			list = append(list, s)
+		}
+	}
+	return list, nil
+}
+
+func TestAuthorize(t *testing.T) {
+	wali := "user-wali"
+	repo := &stubRepository{
+		pengajar: map[string]PengajarKelasScope{
+			"pengajar_kelas:pk-budi": {PengajarKelasID: "pk-budi", KelasID: "7A", TeacherUserID: "user-budi", WaliUserID: &wali},
+			"materi:1":               {PengajarKelasID: "pk-budi", KelasID: "7A", TeacherUserID: "user-budi", WaliUserID: &wali},
+		},
+		kelas: map[string]KelasScope{
+			"kelas:7A":          {KelasID: "7A", WaliUserID: &wali},
+			"anggota_kelas:ak1": {KelasID: "7A", WaliUserID: &wali},
+		},
+	}
+	s := NewService(repo)
+	budi := Actor{UserID: "user-budi", Role: "teacher"}
+
+	tests := []struct {
+		name    string
+		actor   Actor
+		target  Target
+		read    bool
+		wantErr bool
+	}{
+		{name: "pengajar mapel", actor: budi, target: Target{PengajarKelasIDs: []string{"pk-budi"}, KelasIDs: []string{"7A"}}},
+		{name: "pengajar lewat materi dan anggota kelas", actor: budi, target: Target{MateriIDs: []int{1}, AnggotaKelasIDs: []string{"ak1"}}},
+		{name: "guru lain", actor: Actor{UserID: "user-sari"}, target: Target{PengajarKelasIDs: []string{"pk-budi"}}, wantErr: true},
+		{name: "wali kelas boleh membaca", actor: Actor{UserID: wali}, target: Target{PengajarKelasIDs: []string{"pk-budi"}}, read: true},
+		{name: "wali kelas tidak boleh menulis", actor: Actor{UserID: wali}, target: Target{PengajarKelasIDs: []string{"pk-budi"}}, wantErr: true},
+		{name: "pengajar kelas tidak ditemukan", actor: budi, target: Target{PengajarKelasIDs: []string{"pk-hilang"}}, wantErr: true},
+		{name: "materi tidak ditemukan bersama yang ditemukan", actor: budi, target: Target{PengajarKelasIDs: []string{"pk-budi"}, MateriIDs: []int{99}}, wantErr: true},
+		{name: "kelas tidak ditemukan", actor: budi, target: Target{PengajarKelasIDs: []string{"pk-budi"}, KelasIDs: []string{"9Z"}}, wantErr: true},
+		{name: "anggota kelas tidak ditemukan", actor: Actor{UserID: wali}, target: Target{AnggotaKelasIDs: []string{"ak-hilang"}}, read: true, wantErr: true},
+		{name: "admin tidak diperiksa", actor: Actor{UserID: "user-admin", Role: "admin"}, target: Target{PengajarKelasIDs: []string{"pk-hilang"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			if tt.read {
+				err = s.AuthorizeRead(context.Background(), "tenant", tt.actor, tt.target)
+			} else {
+				err = s.AuthorizeWrite(context.Background(), "tenant", tt.actor, tt.target)
+			}
+			if tt.wantErr && !errors.Is(err, ErrForbidden) {
+				t.Fatalf("error = %v, ingin ErrForbidden", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("error = %v, ingin nil", err)
+			}
+		})
+	}
+}
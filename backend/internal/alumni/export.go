@@ -0,0 +1,102 @@
+// file: backend/internal/alumni/export.go
+package alumni
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	sheetAlumni = "Alumni"
+	barisKepala = 3
+)
+
+// renderExcel menulis daftar alumni ke satu sheet. Kolom Sekolah hanya ditambahkan pada
+// rekap naungan.
+func renderExcel(judul string, list []AlumniSekolah, denganSekolah bool) ([]byte, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	index, err := f.NewSheet(sheetAlumni)
+	if err != nil {
+		return nil, err
+	}
+
+	kepala := []string{"No"}
+	if denganSekolah {
+		kepala = append(kepala, "Sekolah")
+	}
+	kepala = append(kepala, "NIS", "NISN", "Nama Lengkap", "Jenis Kelamin", "Tahun Lulus", "Kelas Terakhir",
+		"Nomor Ijazah", "Tanggal Ijazah", "Rata-rata Nilai", "Kegiatan", "Institusi Studi", "Program Studi",
+		"Tempat Kerja", "Jabatan", "Kontak")
+
+	akhir, _ := excelize.CoordinatesToCellName(len(kepala), 1)
+	f.MergeCell(sheetAlumni, "A1", akhir)
+	f.SetCellValue(sheetAlumni, "A1", judul)
+	for i, h := range kepala {
+		cell, _ := excelize.CoordinatesToCellName(i+1, barisKepala)
+		f.SetCellValue(sheetAlumni, cell, h)
+	}
+
+	for i, a := range list {
+		row := []interface{}{i + 1}
+		if denganSekolah {
+			row = append(row, a.NamaSekolah)
+		}
+		var tanggalIjazah, rataRata interface{}
+		if a.TanggalIjazah != nil {
+			tanggalIjazah = a.TanggalIjazah.Format("2006-01-02")
+		}
+		if a.RataRataNilai != nil {
+			rataRata = *a.RataRataNilai
+		}
+		row = append(row, derefString(a.NIS), derefString(a.NISN), a.NamaLengkap, derefString(a.JenisKelamin),
+			a.TahunLulus, derefString(a.KelasTerakhir), derefString(a.NomorIjazah), tanggalIjazah, rataRata,
+			derefString(a.Kegiatan), derefString(a.InstitusiStudi), derefString(a.ProgramStudi),
+			derefString(a.TempatKerja), derefString(a.Jabatan), derefString(a.Kontak))
+		cell, _ := excelize.CoordinatesToCellName(1, barisKepala+1+i)
+		if err := f.SetSheetRow(sheetAlumni, cell, &row); err != nil {
+			return nil, err
+		}
+	}
+
+	style, _ := f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true},
+		Fill:      excelize.Fill{Type: "pattern", Color: []string{"#E6E6E6"}, Pattern: 1},
+		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center", WrapText: true},
+	})
+	awal, _ := excelize.CoordinatesToCellName(1, barisKepala)
+	ujung, _ := excelize.CoordinatesToCellName(len(kepala), barisKepala)
+	f.SetCellStyle(sheetAlumni, awal, ujung, style)
+	f.SetCellStyle(sheetAlumni, "A1", "A1", style)
+	// Kolom Nama Lengkap bergeser satu jika ada kolom Sekolah.
+	kolomNama, _ := excelize.ColumnNumberToName(4)
+	if denganSekolah {
+		kolomNama, _ = excelize.ColumnNumberToName(5)
+	}
+	f.SetColWidth(sheetAlumni, kolomNama, kolomNama, 30)
+	f.SetPanes(sheetAlumni, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      barisKepala,
+		TopLeftCell: fmt.Sprintf("A%d", barisKepala+1),
+		ActivePane:  "bottomLeft",
+	})
+
+	f.SetActiveSheet(index)
+	f.DeleteSheet("Sheet1")
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, fmt.Errorf("gagal menulis file excel: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
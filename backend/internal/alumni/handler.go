@@ -0,0 +1,176 @@
+// file: backend/internal/alumni/handler.go
+package alumni
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"skoola/internal/middleware"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const contentTypeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{service: s}
+}
+
+// filterFromRequest membaca ?q=...&tahun_lulus=...&kegiatan=...
+func filterFromRequest(r *http.Request) (Filter, error) {
+	q := r.URL.Query()
+	filter := Filter{Q: q.Get("q"), Kegiatan: strings.ToUpper(q.Get("kegiatan"))}
+	if v := q.Get("tahun_lulus"); v != "" {
+		tahun, err := strconv.Atoi(v)
+		if err != nil {
+			return filter, fmt.Errorf("%w: tahun_lulus harus berupa angka", ErrValidation)
+		}
+		filter.TahunLulus = tahun
+	}
+	return filter, nil
+}
+
+// GetAll adalah handler untuk GET /alumni.
+func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	filter, err := filterFromRequest(r)
+	if err != nil {
+		writeError(w, "", err)
+		return
+	}
+
+	list, err := h.service.GetAll(r.Context(), schemaName, filter)
+	if err != nil {
+		writeError(w, "Gagal mengambil data alumni: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// Export adalah handler untuk GET /alumni/export.
+func (h *Handler) Export(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	filter, err := filterFromRequest(r)
+	if err != nil {
+		writeError(w, "", err)
+		return
+	}
+
+	content, err := h.service.Export(r.Context(), schemaName, filter)
+	if err != nil {
+		writeError(w, "Gagal mengekspor data alumni: ", err)
+		return
+	}
+	writeFile(w, fmt.Sprintf("alumni_%s.xlsx", time.Now().Format("20060102")), content)
+}
+
+// GetByID adalah handler untuk GET /alumni/{studentID}.
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	a, err := h.service.GetByID(r.Context(), schemaName, chi.URLParam(r, "studentID"))
+	if err != nil {
+		writeError(w, "Gagal mengambil data alumni: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(a)
+}
+
+// Update adalah handler untuk PUT /alumni/{studentID}.
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	var input UpdateAlumniInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	a, err := h.service.Update(r.Context(), schemaName, chi.URLParam(r, "studentID"), input)
+	if err != nil {
+		writeError(w, "Gagal menyimpan data alumni: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(a)
+}
+
+// Sinkron adalah handler untuk POST /alumni/sinkron-nilai.
+func (h *Handler) Sinkron(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	hasil, err := h.service.Sinkron(r.Context(), schemaName)
+	if err != nil {
+		writeError(w, "Gagal menyinkronkan nilai alumni: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hasil)
+}
+
+// GetRekapNaungan adalah handler untuk GET /naungan/{naunganID}/alumni.
+func (h *Handler) GetRekapNaungan(w http.ResponseWriter, r *http.Request) {
+	filter, err := filterFromRequest(r)
+	if err != nil {
+		writeError(w, "", err)
+		return
+	}
+
+	rekap, err := h.service.GetRekapNaungan(r.Context(), chi.URLParam(r, "naunganID"), filter)
+	if err != nil {
+		writeError(w, "Gagal mengambil rekap alumni naungan: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rekap)
+}
+
+// ExportNaungan adalah handler untuk GET /naungan/{naunganID}/alumni/export.
+func (h *Handler) ExportNaungan(w http.ResponseWriter, r *http.Request) {
+	filter, err := filterFromRequest(r)
+	if err != nil {
+		writeError(w, "", err)
+		return
+	}
+
+	content, err := h.service.ExportNaungan(r.Context(), chi.URLParam(r, "naunganID"), filter)
+	if err != nil {
+		writeError(w, "Gagal mengekspor alumni naungan: ", err)
+		return
+	}
+	writeFile(w, fmt.Sprintf("alumni_naungan_%s.xlsx", time.Now().Format("20060102")), content)
+}
+
+func writeFile(w http.ResponseWriter, filename string, content []byte) {
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	w.Header().Set("Content-Type", contentTypeXLSX)
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	w.Write(content)
+}
+
+func writeError(w http.ResponseWriter, prefix string, err error) {
+	switch {
+	case errors.Is(err, ErrValidation):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Data tidak ditemukan", http.StatusNotFound)
+	default:
+		http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,112 @@
+// file: backend/internal/alumni/model.go
+package alumni
+
+import "time"
+
+// Kegiatan alumni setelah lulus.
+const (
+	KegiatanStudi     = "STUDI"
+	KegiatanBekerja   = "BEKERJA"
+	KegiatanWirausaha = "WIRAUSAHA"
+	KegiatanLainnya   = "LAINNYA"
+)
+
+// Alumni adalah siswa berstatus 'Lulus' beserta data kelulusan dan kegiatannya.
+type Alumni struct {
+	StudentID      string     `json:"student_id"`
+	NamaLengkap    string     `json:"nama_lengkap"`
+	NIS            *string    `json:"nis"`
+	NISN           *string    `json:"nisn"`
+	JenisKelamin   *string    `json:"jenis_kelamin"`
+	TanggalLulus   time.Time  `json:"tanggal_lulus"`
+	TahunLulus     int        `json:"tahun_lulus"`
+	KelasTerakhir  *string    `json:"kelas_terakhir"`
+	NomorIjazah    *string    `json:"nomor_ijazah"`
+	TanggalIjazah  *time.Time `json:"tanggal_ijazah"`
+	RataRataNilai  *float64   `json:"rata_rata_nilai"`
+	JumlahMapel    *int       `json:"jumlah_mapel"`
+	Kegiatan       *string    `json:"kegiatan"`
+	InstitusiStudi *string    `json:"institusi_studi"`
+	ProgramStudi   *string    `json:"program_studi"`
+	TempatKerja    *string    `json:"tempat_kerja"`
+	Jabatan        *string    `json:"jabatan"`
+	Kontak         *string    `json:"kontak"`
+	Catatan        *string    `json:"catatan"`
+}
+
+// Filter adalah parameter pencarian alumni. Q dicocokkan dengan nama, NIS, NISN, dan
+// nomor ijazah.
+type Filter struct {
+	Q          string
+	TahunLulus int
+	Kegiatan   string
+}
+
+// UpdateAlumniInput adalah DTO untuk melengkapi data alumni. RataRataNilai hanya perlu
+// diisi untuk alumni yang nilainya tidak tercatat di sistem.
+type UpdateAlumniInput struct {
+	TahunLulus     *int     `json:"tahun_lulus" validate:"omitempty,min=1900,max=2100"`
+	NomorIjazah    string   `json:"nomor_ijazah" validate:"omitempty,max=100"`
+	TanggalIjazah  string   `json:"tanggal_ijazah" validate:"omitempty,datetime=2006-01-02"`
+	RataRataNilai  *float64 `json:"rata_rata_nilai" validate:"omitempty,min=0,max=100"`
+	Kegiatan       string   `json:"kegiatan" validate:"omitempty,oneof=STUDI BEKERJA WIRAUSAHA LAINNYA"`
+	InstitusiStudi string   `json:"institusi_studi" validate:"omitempty,max=255"`
+	ProgramStudi   string   `json:"program_studi" validate:"omitempty,max=255"`
+	TempatKerja    string   `json:"tempat_kerja" validate:"omitempty,max=255"`
+	Jabatan        string   `json:"jabatan" validate:"omitempty,max=255"`
+	Kontak         string   `json:"kontak" validate:"omitempty,max=100"`
+	Catatan        string   `json:"catatan"`
+}
+
+// HasilSinkron adalah jumlah alumni yang ringkasan nilainya diperbarui.
+type HasilSinkron struct {
+	Diperbarui int `json:"diperbarui"`
+	TanpaNilai int `json:"tanpa_nilai"`
+}
+
+// AlumniSekolah adalah alumni pada rekap naungan, lengkap dengan asal sekolahnya.
+type AlumniSekolah struct {
+	Alumni
+	SchemaName  string `json:"schema_name"`
+	NamaSekolah string `json:"nama_sekolah"`
+}
+
+// RekapSekolah adalah jumlah alumni satu sekolah pada rekap naungan.
+type RekapSekolah struct {
+	SchemaName  string         `json:"schema_name"`
+	NamaSekolah string         `json:"nama_sekolah"`
+	Jumlah      int            `json:"jumlah"`
+	PerTahun    map[int]int    `json:"per_tahun"`
+	PerKegiatan map[string]int `json:"per_kegiatan"`
+}
+
+// RekapNaungan menggabungkan alumni semua sekolah di bawah satu naungan.
+type RekapNaungan struct {
+	NaunganID   string          `json:"naungan_id"`
+	NamaNaungan string          `json:"nama_naungan"`
+	Jumlah      int             `json:"jumlah"`
+	Sekolah     []RekapSekolah  `json:"sekolah"`
+	Data        []AlumniSekolah `json:"data"`
+}
+
+// sekolah adalah tenant di bawah naungan.
+type sekolah struct {
+	SchemaName  string
+	NamaSekolah string
+}
+
+// kelasTerakhir adalah keanggotaan kelas terakhir seorang alumni.
+type kelasTerakhir struct {
+	StudentID      string
+	AnggotaKelasID string
+	KelasID        string
+	NamaKelas      string
+}
+
+// ringkasanNilai adalah hasil perhitungan nilai akhir alumni di kelas terakhirnya.
+type ringkasanNilai struct {
+	StudentID     string
+	KelasTerakhir string
+	RataRata      *float64
+	JumlahMapel   int
+}
@@ -0,0 +1,256 @@
+// file: backend/internal/alumni/repository.go
+package alumni
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"skoola/pkg/database"
+	"strings"
+)
+
+// Repository mendefinisikan interface untuk interaksi database.
+type Repository interface {
+	GetAll(ctx context.Context, schemaName string, filter Filter) ([]Alumni, error)
+	GetByID(ctx context.Context, schemaName string, studentID string) (*Alumni, error)
+	Upsert(ctx context.Context, schemaName string, studentID string, input UpdateAlumniInput) error
+	// GetKelasTerakhir mengembalikan kelas terakhir alumni yang ringkasan nilainya belum terisi.
+	GetKelasTerakhir(ctx context.Context, schemaName string) ([]kelasTerakhir, error)
+	SimpanRingkasan(ctx context.Context, schemaName string, list []ringkasanNilai) error
+	// GetNaungan mengembalikan nama naungan dan sekolah di bawahnya dari skema public.
+	GetNaungan(ctx context.Context, naunganID string) (string, []sekolah, error)
+}
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewRepository membuat instance baru dari postgresRepository.
+func NewRepository(db *sql.DB) Repository {
+	return &postgresRepository{db: db}
+}
+
+// selectAlumni mengambil siswa yang status terakhirnya 'Lulus'. Tahun lulus memakai isian
+// admin, atau tahun pada riwayat kelulusan jika belum diisi.
+const selectAlumni = `
+	SELECT * FROM (
+		SELECT s.id, s.nama_lengkap, s.nis, s.nisn, s.jenis_kelamin::text, st.tanggal_kejadian,
+			COALESCE(a.tahun_lulus, EXTRACT(YEAR FROM st.tanggal_kejadian)::int) AS tahun_lulus,
+			a.kelas_terakhir, a.nomor_ijazah, a.tanggal_ijazah, a.rata_rata_nilai, a.jumlah_mapel,
+			a.kegiatan, a.institusi_studi, a.program_studi, a.tempat_kerja, a.jabatan, a.kontak, a.catatan
+		FROM students s
+		JOIN (
+			SELECT DISTINCT ON (student_id) student_id, status, tanggal_kejadian
+			FROM riwayat_akademik
+			ORDER BY student_id, tanggal_kejadian DESC, created_at DESC
+		) st ON st.student_id = s.id AND st.status = 'Lulus'
+		LEFT JOIN alumni a ON a.student_id = s.id
+	) x
+`
+
+func scanAlumni(scanner interface{ Scan(...interface{}) error }, a *Alumni) error {
+	return scanner.Scan(&a.StudentID, &a.NamaLengkap, &a.NIS, &a.NISN, &a.JenisKelamin, &a.TanggalLulus, &a.TahunLulus,
+		&a.KelasTerakhir, &a.NomorIjazah, &a.TanggalIjazah, &a.RataRataNilai, &a.JumlahMapel,
+		&a.Kegiatan, &a.InstitusiStudi, &a.ProgramStudi, &a.TempatKerja, &a.Jabatan, &a.Kontak, &a.Catatan)
+}
+
+func (r *postgresRepository) GetAll(ctx context.Context, schemaName string, filter Filter) ([]Alumni, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var where []string
+	var args []interface{}
+	if q := strings.TrimSpace(filter.Q); q != "" {
+		args = append(args, "%"+q+"%")
+		n := len(args)
+		where = append(where, fmt.Sprintf("(x.nama_lengkap ILIKE $%d OR x.nis ILIKE $%d OR x.nisn ILIKE $%d OR x.nomor_ijazah ILIKE $%d)", n, n, n, n))
+	}
+	if filter.TahunLulus != 0 {
+		args = append(args, filter.TahunLulus)
+		where = append(where, fmt.Sprintf("x.tahun_lulus = $%d", len(args)))
+	}
+	if filter.Kegiatan != "" {
+		args = append(args, filter.Kegiatan)
+		where = append(where, fmt.Sprintf("x.kegiatan = $%d", len(args)))
+	}
+	query := selectAlumni
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY x.tahun_lulus DESC, x.nama_lengkap ASC"
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil data alumni: %w", err)
+	}
+	defer rows.Close()
+
+	list := []Alumni{}
+	for rows.Next() {
+		var a Alumni
+		if err := scanAlumni(rows, &a); err != nil {
+			return nil, fmt.Errorf("gagal memindai data alumni: %w", err)
+		}
+		list = append(list, a)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+func (r *postgresRepository) GetByID(ctx context.Context, schemaName string, studentID string) (*Alumni, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var a Alumni
+	if err := scanAlumni(tx.QueryRowContext(ctx, selectAlumni+" WHERE x.id = $1", studentID), &a); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("gagal mengambil data alumni: %w", err)
+	}
+	return &a, tx.Commit()
+}
+
+func (r *postgresRepository) Upsert(ctx context.Context, schemaName string, studentID string, input UpdateAlumniInput) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO alumni (student_id, tahun_lulus, nomor_ijazah, tanggal_ijazah, rata_rata_nilai, kegiatan,
+			institusi_studi, program_studi, tempat_kerja, jabatan, kontak, catatan)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (student_id) DO UPDATE SET
+			tahun_lulus = EXCLUDED.tahun_lulus,
+			nomor_ijazah = EXCLUDED.nomor_ijazah,
+			tanggal_ijazah = EXCLUDED.tanggal_ijazah,
+			rata_rata_nilai = COALESCE(EXCLUDED.rata_rata_nilai, alumni.rata_rata_nilai),
+			kegiatan = EXCLUDED.kegiatan,
+			institusi_studi = EXCLUDED.institusi_studi,
+			program_studi = EXCLUDED.program_studi,
+			tempat_kerja = EXCLUDED.tempat_kerja,
+			jabatan = EXCLUDED.jabatan,
+			kontak = EXCLUDED.kontak,
+			catatan = EXCLUDED.catatan,
+			updated_at = NOW()
+	`
+	_, err = tx.ExecContext(ctx, query, studentID, input.TahunLulus, nullString(input.NomorIjazah), nullString(input.TanggalIjazah),
+		input.RataRataNilai, nullString(input.Kegiatan), nullString(input.InstitusiStudi), nullString(input.ProgramStudi),
+		nullString(input.TempatKerja), nullString(input.Jabatan), nullString(input.Kontak), nullString(input.Catatan))
+	if err != nil {
+		return fmt.Errorf("gagal menyimpan data alumni: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (r *postgresRepository) GetKelasTerakhir(ctx context.Context, schemaName string) ([]kelasTerakhir, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT DISTINCT ON (ak.student_id) ak.student_id, ak.id, k.id, k.nama_kelas
+		FROM anggota_kelas ak
+		JOIN kelas k ON ak.kelas_id = k.id
+		JOIN tahun_ajaran ta ON k.tahun_ajaran_id = ta.id
+		LEFT JOIN alumni a ON a.student_id = ak.student_id
+		WHERE a.rata_rata_nilai IS NULL
+			AND ak.student_id IN (
+				SELECT student_id FROM (
+					SELECT DISTINCT ON (student_id) student_id, status
+					FROM riwayat_akademik
+					ORDER BY student_id, tanggal_kejadian DESC, created_at DESC
+				) st WHERE st.status = 'Lulus'
+			)
+		ORDER BY ak.student_id, ta.nama_tahun_ajaran DESC, ta.semester DESC, ta.created_at DESC
+	`
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil kelas terakhir alumni: %w", err)
+	}
+	defer rows.Close()
+
+	var list []kelasTerakhir
+	for rows.Next() {
+		var k kelasTerakhir
+		if err := rows.Scan(&k.StudentID, &k.AnggotaKelasID, &k.KelasID, &k.NamaKelas); err != nil {
+			return nil, fmt.Errorf("gagal memindai kelas terakhir alumni: %w", err)
+		}
+		list = append(list, k)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+func (r *postgresRepository) SimpanRingkasan(ctx context.Context, schemaName string, list []ringkasanNilai) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO alumni (student_id, kelas_terakhir, rata_rata_nilai, jumlah_mapel)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (student_id) DO UPDATE SET
+			kelas_terakhir = EXCLUDED.kelas_terakhir,
+			rata_rata_nilai = EXCLUDED.rata_rata_nilai,
+			jumlah_mapel = EXCLUDED.jumlah_mapel,
+			updated_at = NOW()
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, n := range list {
+		if _, err := stmt.ExecContext(ctx, n.StudentID, n.KelasTerakhir, n.RataRata, n.JumlahMapel); err != nil {
+			return fmt.Errorf("gagal menyimpan ringkasan nilai alumni: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *postgresRepository) GetNaungan(ctx context.Context, naunganID string) (string, []sekolah, error) {
+	var nama string
+	if err := r.db.QueryRowContext(ctx, "SELECT nama_naungan FROM public.naungan WHERE id = $1", naunganID).Scan(&nama); err != nil {
+		return "", nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, "SELECT schema_name, nama_sekolah FROM public.tenants WHERE naungan_id = $1 ORDER BY nama_sekolah ASC", naunganID)
+	if err != nil {
+		return "", nil, fmt.Errorf("gagal mengambil sekolah naungan: %w", err)
+	}
+	defer rows.Close()
+
+	var list []sekolah
+	for rows.Next() {
+		var s sekolah
+		if err := rows.Scan(&s.SchemaName, &s.NamaSekolah); err != nil {
+			return "", nil, fmt.Errorf("gagal memindai sekolah naungan: %w", err)
+		}
+		list = append(list, s)
+	}
+	return nama, list, rows.Err()
+}
+
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
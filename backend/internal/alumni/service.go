@@ -0,0 +1,199 @@
+// file: backend/internal/alumni/service.go
+package alumni
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"skoola/internal/audit"
+	"skoola/internal/nilaiakhir"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+var ErrValidation = errors.New("validation failed")
+
+// Service mendefinisikan logika bisnis data alumni.
+type Service interface {
+	GetAll(ctx context.Context, schemaName string, filter Filter) ([]Alumni, error)
+	GetByID(ctx context.Context, schemaName string, studentID string) (*Alumni, error)
+	Update(ctx context.Context, schemaName string, studentID string, input UpdateAlumniInput) (*Alumni, error)
+	// Sinkron mengisi ringkasan nilai alumni dari nilai akhir di kelas terakhirnya.
+	Sinkron(ctx context.Context, schemaName string) (*HasilSinkron, error)
+	Export(ctx context.Context, schemaName string, filter Filter) ([]byte, error)
+	GetRekapNaungan(ctx context.Context, naunganID string, filter Filter) (*RekapNaungan, error)
+	ExportNaungan(ctx context.Context, naunganID string, filter Filter) ([]byte, error)
+}
+
+type service struct {
+	repo       Repository
+	nilaiAkhir nilaiakhir.Service
+	audit      audit.Recorder
+	validate   *validator.Validate
+}
+
+// NewService membuat instance baru dari service alumni.
+func NewService(repo Repository, nilaiAkhirService nilaiakhir.Service, auditLog audit.Recorder, validate *validator.Validate) Service {
+	return &service{repo: repo, nilaiAkhir: nilaiAkhirService, audit: auditLog, validate: validate}
+}
+
+func (s *service) GetAll(ctx context.Context, schemaName string, filter Filter) ([]Alumni, error) {
+	if err := validateFilter(filter); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAll(ctx, schemaName, filter)
+}
+
+func (s *service) GetByID(ctx context.Context, schemaName string, studentID string) (*Alumni, error) {
+	if _, err := uuid.Parse(studentID); err != nil {
+		return nil, fmt.Errorf("%w: student_id tidak valid", ErrValidation)
+	}
+	a, err := s.repo.GetByID(ctx, schemaName, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, sql.ErrNoRows
+	}
+	return a, nil
+}
+
+func (s *service) Update(ctx context.Context, schemaName string, studentID string, input UpdateAlumniInput) (*Alumni, error) {
+	if err := s.validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	before, err := s.GetByID(ctx, schemaName, studentID)
+	if err != nil {
+		return nil, err
+	}
+	input.NomorIjazah = strings.TrimSpace(input.NomorIjazah)
+	if err := s.repo.Upsert(ctx, schemaName, studentID, input); err != nil {
+		return nil, err
+	}
+	after, err := s.GetByID(ctx, schemaName, studentID)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionUpdate, Entity: "alumni", EntityID: studentID, Before: before, After: after})
+	return after, nil
+}
+
+func (s *service) Sinkron(ctx context.Context, schemaName string) (*HasilSinkron, error) {
+	list, err := s.repo.GetKelasTerakhir(ctx, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	perKelas := make(map[string][]kelasTerakhir)
+	var urutanKelas []string
+	for _, k := range list {
+		if _, ok := perKelas[k.KelasID]; !ok {
+			urutanKelas = append(urutanKelas, k.KelasID)
+		}
+		perKelas[k.KelasID] = append(perKelas[k.KelasID], k)
+	}
+
+	hasil := &HasilSinkron{}
+	var ringkasan []ringkasanNilai
+	for _, kelasID := range urutanKelas {
+		nilai, err := s.nilaiAkhir.HitungKelas(ctx, schemaName, kelasID)
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range perKelas[kelasID] {
+			var total float64
+			var jumlah int
+			for _, perSiswa := range nilai {
+				if n, ok := perSiswa[k.AnggotaKelasID]; ok && n.NilaiAkhir != nil {
+					total += *n.NilaiAkhir
+					jumlah++
+				}
+			}
+			r := ringkasanNilai{StudentID: k.StudentID, KelasTerakhir: k.NamaKelas, JumlahMapel: jumlah}
+			if jumlah > 0 {
+				rata := math.Round(total/float64(jumlah)*100) / 100
+				r.RataRata = &rata
+				hasil.Diperbarui++
+			} else {
+				hasil.TanpaNilai++
+			}
+			ringkasan = append(ringkasan, r)
+		}
+	}
+
+	if len(ringkasan) > 0 {
+		if err := s.repo.SimpanRingkasan(ctx, schemaName, ringkasan); err != nil {
+			return nil, err
+		}
+	}
+	return hasil, nil
+}
+
+func (s *service) Export(ctx context.Context, schemaName string, filter Filter) ([]byte, error) {
+	list, err := s.GetAll(ctx, schemaName, filter)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]AlumniSekolah, 0, len(list))
+	for _, a := range list {
+		data = append(data, AlumniSekolah{Alumni: a})
+	}
+	return renderExcel("DATA ALUMNI", data, false)
+}
+
+func (s *service) GetRekapNaungan(ctx context.Context, naunganID string, filter Filter) (*RekapNaungan, error) {
+	if _, err := uuid.Parse(naunganID); err != nil {
+		return nil, fmt.Errorf("%w: naungan_id tidak valid", ErrValidation)
+	}
+	if err := validateFilter(filter); err != nil {
+		return nil, err
+	}
+	nama, daftarSekolah, err := s.repo.GetNaungan(ctx, naunganID)
+	if err != nil {
+		return nil, err
+	}
+
+	rekap := &RekapNaungan{NaunganID: naunganID, NamaNaungan: nama, Sekolah: []RekapSekolah{}, Data: []AlumniSekolah{}}
+	for _, sk := range daftarSekolah {
+		list, err := s.repo.GetAll(ctx, sk.SchemaName, filter)
+		if err != nil {
+			return nil, fmt.Errorf("gagal mengambil alumni %s: %w", sk.NamaSekolah, err)
+		}
+		rs := RekapSekolah{SchemaName: sk.SchemaName, NamaSekolah: sk.NamaSekolah, Jumlah: len(list), PerTahun: map[int]int{}, PerKegiatan: map[string]int{}}
+		for _, a := range list {
+			rs.PerTahun[a.TahunLulus]++
+			kegiatan := "BELUM_DIISI"
+			if a.Kegiatan != nil {
+				kegiatan = *a.Kegiatan
+			}
+			rs.PerKegiatan[kegiatan]++
+			rekap.Data = append(rekap.Data, AlumniSekolah{Alumni: a, SchemaName: sk.SchemaName, NamaSekolah: sk.NamaSekolah})
+		}
+		rekap.Jumlah += rs.Jumlah
+		rekap.Sekolah = append(rekap.Sekolah, rs)
+	}
+	return rekap, nil
+}
+
+func (s *service) ExportNaungan(ctx context.Context, naunganID string, filter Filter) ([]byte, error) {
+	rekap, err := s.GetRekapNaungan(ctx, naunganID, filter)
+	if err != nil {
+		return nil, err
+	}
+	return renderExcel("DATA ALUMNI "+strings.ToUpper(rekap.NamaNaungan), rekap.Data, true)
+}
+
+func validateFilter(filter Filter) error {
+	if filter.TahunLulus != 0 && (filter.TahunLulus < 1900 || filter.TahunLulus > 2100) {
+		return fmt.Errorf("%w: tahun_lulus tidak valid", ErrValidation)
+	}
+	switch filter.Kegiatan {
+	case "", KegiatanStudi, KegiatanBekerja, KegiatanWirausaha, KegiatanLainnya:
+		return nil
+	}
+	return fmt.Errorf("%w: kegiatan harus STUDI, BEKERJA, WIRAUSAHA, atau LAINNYA", ErrValidation)
+}
@@ -0,0 +1,80 @@
+// file: backend/internal/audit/handler.go
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"skoola/internal/middleware"
+	"strconv"
+	"time"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{service: s}
+}
+
+// GetAll adalah handler untuk endpoint GET /audit.
+// Filter: user_id, entity, entity_id, from dan to (YYYY-MM-DD, inklusif), limit, offset.
+func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	query := r.URL.Query()
+
+	filter := Filter{
+		UserID:   query.Get("user_id"),
+		Entity:   query.Get("entity"),
+		EntityID: query.Get("entity_id"),
+	}
+
+	if from := query.Get("from"); from != "" {
+		t, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			http.Error(w, "Parameter 'from' harus berformat YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		filter.From = &t
+	}
+	if to := query.Get("to"); to != "" {
+		t, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			http.Error(w, "Parameter 'to' harus berformat YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		// Tanggal akhir inklusif: ambil semua catatan sebelum hari berikutnya.
+		end := t.AddDate(0, 0, 1)
+		filter.To = &end
+	}
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			http.Error(w, "Parameter 'limit' tidak valid", http.StatusBadRequest)
+			return
+		}
+		filter.Limit = n
+	}
+	if offset := query.Get("offset"); offset != "" {
+		n, err := strconv.Atoi(offset)
+		if err != nil {
+			http.Error(w, "Parameter 'offset' tidak valid", http.StatusBadRequest)
+			return
+		}
+		filter.Offset = n
+	}
+
+	logs, err := h.service.GetAll(r.Context(), schemaName, filter)
+	if err != nil {
+		if errors.Is(err, ErrValidation) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Gagal mengambil audit log: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(logs)
+}
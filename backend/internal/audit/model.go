@@ -0,0 +1,50 @@
+// file: backend/internal/audit/model.go
+package audit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Jenis aksi yang dicatat di audit log.
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+	ActionLock   = "lock"
+	ActionUnlock = "unlock"
+)
+
+// Log merepresentasikan satu baris dari tabel 'audit_logs'.
+type Log struct {
+	ID        int64           `json:"id"`
+	UserID    *string         `json:"user_id"`
+	UserEmail *string         `json:"user_email,omitempty"` // Untuk join
+	Action    string          `json:"action"`
+	Entity    string          `json:"entity"`
+	EntityID  string          `json:"entity_id"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// Entry adalah satu perubahan yang akan dicatat. Before kosong untuk aksi create
+// dan After kosong untuk aksi delete.
+type Entry struct {
+	Action   string
+	Entity   string
+	EntityID string
+	Before   interface{}
+	After    interface{}
+}
+
+// Filter adalah parameter pencarian untuk endpoint GET /audit.
+type Filter struct {
+	UserID   string
+	Entity   string
+	EntityID string
+	From     *time.Time
+	To       *time.Time
+	Limit    int
+	Offset   int
+}
@@ -0,0 +1,103 @@
+// file: backend/internal/audit/repository.go
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"skoola/pkg/database"
+)
+
+// Repository mendefinisikan interface untuk penyimpanan audit log.
+type Repository interface {
+	Create(ctx context.Context, schemaName string, logs []Log) error
+	GetAll(ctx context.Context, schemaName string, filter Filter) ([]Log, error)
+}
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewRepository membuat instance baru dari postgresRepository.
+func NewRepository(db *sql.DB) Repository {
+	return &postgresRepository{db: db}
+}
+
+// Create menyimpan beberapa audit log sekaligus dalam satu transaksi.
+func (r *postgresRepository) Create(ctx context.Context, schemaName string, logs []Log) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO audit_logs (user_id, action, entity, entity_id, before_data, after_data)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`)
+	if err != nil {
+		return fmt.Errorf("gagal mempersiapkan statement audit log: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, l := range logs {
+		if _, err := stmt.ExecContext(ctx, l.UserID, l.Action, l.Entity, l.EntityID, nullJSON(l.Before), nullJSON(l.After)); err != nil {
+			return fmt.Errorf("gagal menyimpan audit log: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *postgresRepository) GetAll(ctx context.Context, schemaName string, filter Filter) ([]Log, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT a.id, a.user_id, u.email, a.action, a.entity, a.entity_id, a.before_data, a.after_data, a.created_at
+		FROM audit_logs a
+		LEFT JOIN users u ON a.user_id = u.id
+		WHERE ($1 = '' OR a.user_id::text = $1)
+		  AND ($2 = '' OR a.entity = $2)
+		  AND ($3 = '' OR a.entity_id = $3)
+		  AND ($4::timestamptz IS NULL OR a.created_at >= $4)
+		  AND ($5::timestamptz IS NULL OR a.created_at < $5)
+		ORDER BY a.created_at DESC, a.id DESC
+		LIMIT $6 OFFSET $7
+	`
+	rows, err := tx.QueryContext(ctx, query, filter.UserID, filter.Entity, filter.EntityID, filter.From, filter.To, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil audit log: %w", err)
+	}
+	defer rows.Close()
+
+	logs := []Log{}
+	for rows.Next() {
+		var l Log
+		var before, after []byte
+		if err := rows.Scan(&l.ID, &l.UserID, &l.UserEmail, &l.Action, &l.Entity, &l.EntityID, &before, &after, &l.CreatedAt); err != nil {
+			return nil, fmt.Errorf("gagal memindai data audit log: %w", err)
+		}
+		if before != nil {
+			l.Before = before
+		}
+		if after != nil {
+			l.After = after
+		}
+		logs = append(logs, l)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return logs, tx.Commit()
+}
+
+// nullJSON mengubah JSON kosong menjadi NULL di database.
+func nullJSON(data []byte) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
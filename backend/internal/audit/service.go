@@ -0,0 +1,101 @@
+// file: backend/internal/audit/service.go
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"skoola/internal/middleware"
+)
+
+var ErrValidation = errors.New("validation failed")
+
+const (
+	defaultLimit = 100
+	maxLimit     = 500
+)
+
+// Recorder dipakai oleh service lain untuk mencatat setiap perubahan data.
+type Recorder interface {
+	// Record mencatat satu atau beberapa perubahan atas nama user yang sedang login.
+	// Kegagalan pencatatan hanya ditulis ke log agar tidak membatalkan perubahan
+	// yang sudah tersimpan.
+	Record(ctx context.Context, schemaName string, entries ...Entry)
+}
+
+// Service mendefinisikan interface untuk logika bisnis audit log.
+type Service interface {
+	Recorder
+	GetAll(ctx context.Context, schemaName string, filter Filter) ([]Log, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+// NewService membuat instance baru dari service audit.
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) Record(ctx context.Context, schemaName string, entries ...Entry) {
+	if len(entries) == 0 {
+		return
+	}
+
+	var userID *string
+	if id, ok := ctx.Value(middleware.UserIDKey).(string); ok && id != "" {
+		userID = &id
+	}
+
+	logs := make([]Log, len(entries))
+	for i, e := range entries {
+		logs[i] = Log{
+			UserID:   userID,
+			Action:   e.Action,
+			Entity:   e.Entity,
+			EntityID: e.EntityID,
+			Before:   toJSON(e.Before),
+			After:    toJSON(e.After),
+		}
+	}
+
+	if err := s.repo.Create(ctx, schemaName, logs); err != nil {
+		log.Printf("Gagal mencatat %d audit log (%s %s) pada skema %s: %v", len(logs), entries[0].Action, entries[0].Entity, schemaName, err)
+	}
+}
+
+func (s *service) GetAll(ctx context.Context, schemaName string, filter Filter) ([]Log, error) {
+	if filter.From != nil && filter.To != nil && !filter.To.After(*filter.From) {
+		return nil, fmt.Errorf("%w: tanggal akhir tidak boleh sebelum tanggal awal", ErrValidation)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultLimit
+	}
+	if filter.Limit > maxLimit {
+		filter.Limit = maxLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return s.repo.GetAll(ctx, schemaName, filter)
+}
+
+// toJSON mengubah data menjadi JSON. Nilai nil menghasilkan slice kosong
+// sehingga disimpan sebagai NULL.
+func toJSON(v interface{}) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("Gagal mengubah data audit menjadi JSON: %v", err)
+		return nil
+	}
+	if string(data) == "null" {
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,223 @@
+// file: backend/internal/auth/attempt_repository.go
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// AttemptRepository mendefinisikan interface untuk penghitung percobaan login gagal.
+// Semua data berada di skema public.
+type AttemptRepository interface {
+	GetAccount(ctx context.Context, schemaName string, email string) (*LoginAttempt, error)
+	GetIP(ctx context.Context, ip string) (*LoginAttempt, error)
+	RecordAccountFailure(ctx context.Context, schemaName string, email string, window time.Duration) (*LoginAttempt, error)
+	RecordIPFailure(ctx context.Context, ip string, window time.Duration) (*LoginAttempt, error)
+	LockAccount(ctx context.Context, schemaName string, email string, ip string, failedCount int, until time.Time) error
+	LockIP(ctx context.Context, ip string, failedCount int, until time.Time) error
+	ResetAccount(ctx context.Context, schemaName string, email string) error
+	GetLockedAccounts(ctx context.Context, schemaName string) ([]LoginAttempt, error)
+	UnlockAccount(ctx context.Context, schemaName string, email string, actorUserID string) error
+}
+
+type attemptRepository struct {
+	db *sql.DB
+}
+
+// NewAttemptRepository membuat instance baru dari attemptRepository.
+func NewAttemptRepository(db *sql.DB) AttemptRepository {
+	return &attemptRepository{db: db}
+}
+
+func (r *attemptRepository) GetAccount(ctx context.Context, schemaName string, email string) (*LoginAttempt, error) {
+	query := `
+		SELECT schema_name, email, failed_count, last_failed_at, locked_until
+		FROM public.login_attempts
+		WHERE schema_name = $1 AND email = $2
+	`
+	var a LoginAttempt
+	err := r.db.QueryRowContext(ctx, query, schemaName, email).Scan(&a.SchemaName, &a.Email, &a.FailedCount, &a.LastFailedAt, &a.LockedUntil)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("gagal memindai data percobaan login akun: %w", err)
+	}
+	return &a, nil
+}
+
+func (r *attemptRepository) GetIP(ctx context.Context, ip string) (*LoginAttempt, error) {
+	query := `SELECT ip_address, failed_count, last_failed_at, locked_until FROM public.login_ip_attempts WHERE ip_address = $1`
+	var a LoginAttempt
+	err := r.db.QueryRowContext(ctx, query, ip).Scan(&a.IPAddress, &a.FailedCount, &a.LastFailedAt, &a.LockedUntil)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("gagal memindai data percobaan login IP: %w", err)
+	}
+	return &a, nil
+}
+
+// RecordAccountFailure menambah penghitung gagal akun. Penghitung dimulai ulang dari 1
+// jika kegagalan terakhir sudah lebih lama dari window.
+func (r *attemptRepository) RecordAccountFailure(ctx context.Context, schemaName string, email string, window time.Duration) (*LoginAttempt, error) {
+	query := `
+		INSERT INTO public.login_attempts AS la (schema_name, email, failed_count, last_failed_at)
+		VALUES ($1, $2, 1, NOW())
+		ON CONFLICT (schema_name, email) DO UPDATE SET
+			failed_count = CASE
+				WHEN la.last_failed_at IS NULL OR la.last_failed_at < NOW() - make_interval(secs => $3) THEN 1
+				ELSE la.failed_count + 1
+			END,
+			last_failed_at = NOW()
+		RETURNING schema_name, email, failed_count, last_failed_at, locked_until
+	`
+	var a LoginAttempt
+	err := r.db.QueryRowContext(ctx, query, schemaName, email, window.Seconds()).Scan(&a.SchemaName, &a.Email, &a.FailedCount, &a.LastFailedAt, &a.LockedUntil)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mencatat percobaan login akun: %w", err)
+	}
+	return &a, nil
+}
+
+// RecordIPFailure menambah penghitung gagal untuk satu alamat IP dengan aturan window yang sama.
+func (r *attemptRepository) RecordIPFailure(ctx context.Context, ip string, window time.Duration) (*LoginAttempt, error) {
+	query := `
+		INSERT INTO public.login_ip_attempts AS li (ip_address, failed_count, last_failed_at)
+		VALUES ($1, 1, NOW())
+		ON CONFLICT (ip_address) DO UPDATE SET
+			failed_count = CASE
+				WHEN li.last_failed_at IS NULL OR li.last_failed_at < NOW() - make_interval(secs => $2) THEN 1
+				ELSE li.failed_count + 1
+			END,
+			last_failed_at = NOW()
+		RETURNING ip_address, failed_count, last_failed_at, locked_until
+	`
+	var a LoginAttempt
+	err := r.db.QueryRowContext(ctx, query, ip, window.Seconds()).Scan(&a.IPAddress, &a.FailedCount, &a.LastFailedAt, &a.LockedUntil)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mencatat percobaan login IP: %w", err)
+	}
+	return &a, nil
+}
+
+// LockAccount mengunci akun sampai waktu tertentu dan mencatatnya di audit penguncian.
+func (r *attemptRepository) LockAccount(ctx context.Context, schemaName string, email string, ip string, failedCount int, until time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("gagal memulai transaksi: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE public.login_attempts SET locked_until = $1 WHERE schema_name = $2 AND email = $3`
+	if _, err := tx.ExecContext(ctx, query, until, schemaName, email); err != nil {
+		return fmt.Errorf("gagal mengunci akun: %w", err)
+	}
+
+	auditQuery := `
+		INSERT INTO public.login_lockout_audit (action, schema_name, email, ip_address, failed_count, locked_until)
+		VALUES ('lock_account', $1, $2, $3, $4, $5)
+	`
+	if _, err := tx.ExecContext(ctx, auditQuery, schemaName, email, ip, failedCount, until); err != nil {
+		return fmt.Errorf("gagal mencatat audit penguncian akun: %w", err)
+	}
+	return tx.Commit()
+}
+
+// LockIP mengunci alamat IP sampai waktu tertentu dan mencatatnya di audit penguncian.
+func (r *attemptRepository) LockIP(ctx context.Context, ip string, failedCount int, until time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("gagal memulai transaksi: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE public.login_ip_attempts SET locked_until = $1 WHERE ip_address = $2`
+	if _, err := tx.ExecContext(ctx, query, until, ip); err != nil {
+		return fmt.Errorf("gagal mengunci IP: %w", err)
+	}
+
+	auditQuery := `
+		INSERT INTO public.login_lockout_audit (action, ip_address, failed_count, locked_until)
+		VALUES ('lock_ip', $1, $2, $3)
+	`
+	if _, err := tx.ExecContext(ctx, auditQuery, ip, failedCount, until); err != nil {
+		return fmt.Errorf("gagal mencatat audit penguncian IP: %w", err)
+	}
+	return tx.Commit()
+}
+
+// ResetAccount menghapus penghitung gagal akun setelah login berhasil.
+func (r *attemptRepository) ResetAccount(ctx context.Context, schemaName string, email string) error {
+	query := `DELETE FROM public.login_attempts WHERE schema_name = $1 AND email = $2`
+	if _, err := r.db.ExecContext(ctx, query, schemaName, email); err != nil {
+		return fmt.Errorf("gagal mereset percobaan login akun: %w", err)
+	}
+	return nil
+}
+
+// GetLockedAccounts mengambil semua akun di satu sekolah yang saat ini terkunci.
+func (r *attemptRepository) GetLockedAccounts(ctx context.Context, schemaName string) ([]LoginAttempt, error) {
+	query := `
+		SELECT schema_name, email, failed_count, last_failed_at, locked_until
+		FROM public.login_attempts
+		WHERE schema_name = $1 AND locked_until > NOW()
+		ORDER BY locked_until DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query get locked accounts: %w", err)
+	}
+	defer rows.Close()
+
+	var list []LoginAttempt
+	for rows.Next() {
+		var a LoginAttempt
+		if err := rows.Scan(&a.SchemaName, &a.Email, &a.FailedCount, &a.LastFailedAt, &a.LockedUntil); err != nil {
+			return nil, fmt.Errorf("gagal memindai data akun terkunci: %w", err)
+		}
+		list = append(list, a)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("terjadi error saat iterasi baris data akun terkunci: %w", err)
+	}
+	return list, nil
+}
+
+// UnlockAccount membuka kunci akun, mereset penghitungnya, dan mencatat siapa yang membukanya.
+// Mengembalikan sql.ErrNoRows jika akun tidak sedang terkunci.
+func (r *attemptRepository) UnlockAccount(ctx context.Context, schemaName string, email string, actorUserID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("gagal memulai transaksi: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE public.login_attempts SET failed_count = 0, locked_until = NULL
+		WHERE schema_name = $1 AND email = $2 AND locked_until > NOW()
+	`
+	result, err := tx.ExecContext(ctx, query, schemaName, email)
+	if err != nil {
+		return fmt.Errorf("gagal membuka kunci akun: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("gagal memeriksa baris yang terpengaruh: %w", err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	auditQuery := `
+		INSERT INTO public.login_lockout_audit (action, schema_name, email, actor_user_id)
+		VALUES ('unlock_account', $1, $2, $3)
+	`
+	if _, err := tx.ExecContext(ctx, auditQuery, schemaName, email, actorUserID); err != nil {
+		return fmt.Errorf("gagal mencatat audit pembukaan kunci: %w", err)
+	}
+	return tx.Commit()
+}
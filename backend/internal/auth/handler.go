@@ -2,9 +2,13 @@
 package auth
 
 import (
+	"database/sql"
 	"encoding/json"
 	"errors"
+	"net"
 	"net/http"
+	"skoola/internal/middleware"
+	"strconv"
 )
 
 // Handler menangani request HTTP untuk otentikasi.
@@ -29,8 +33,15 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := h.service.Login(r.Context(), schemaName, input)
+	pair, err := h.service.Login(r.Context(), schemaName, clientIP(r), input)
 	if err != nil {
+		var lockedErr *LockedError
+		if errors.As(err, &lockedErr) {
+			w.Header().Set("Retry-After", strconv.Itoa(lockedErr.retrySeconds()))
+			http.Error(w, "Terlalu banyak percobaan login gagal. Silakan coba lagi dalam "+strconv.Itoa(lockedErr.retrySeconds())+" detik.", http.StatusTooManyRequests)
+			return
+		}
+
 		// --- PERUBAHAN DI SINI ---
 		// Tangani error spesifik untuk ID Sekolah yang salah
 		if errors.Is(err, ErrInvalidTenantID) {
@@ -51,5 +62,236 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"token": token})
+	json.NewEncoder(w).Encode(pair)
+}
+
+// Refresh adalah handler untuk endpoint POST /auth/refresh.
+func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Header.Get("X-Tenant-ID")
+
+	var input RefreshInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	pair, err := h.service.Refresh(r.Context(), schemaName, input)
+	if err != nil {
+		if errors.Is(err, ErrInvalidTenantID) {
+			http.Error(w, "ID Sekolah yang Anda masukkan tidak ditemukan.", http.StatusUnauthorized)
+			return
+		}
+		if errors.Is(err, ErrInvalidRefreshToken) {
+			http.Error(w, "Sesi telah berakhir, silakan login kembali.", http.StatusUnauthorized)
+			return
+		}
+		http.Error(w, "Terjadi kesalahan internal: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(pair)
+}
+
+// Logout adalah handler untuk endpoint POST /auth/logout.
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Header.Get("X-Tenant-ID")
+
+	var input RefreshInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Logout(r.Context(), schemaName, input); err != nil {
+		if errors.Is(err, ErrInvalidTenantID) {
+			http.Error(w, "ID Sekolah yang Anda masukkan tidak ditemukan.", http.StatusUnauthorized)
+			return
+		}
+		if errors.Is(err, ErrInvalidRefreshToken) {
+			http.Error(w, "Refresh token wajib diisi.", http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Terjadi kesalahan internal: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Logout berhasil"})
+}
+
+// ChangePassword adalah handler untuk endpoint PUT /me/password.
+func (h *Handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "Gagal mengidentifikasi user dari token", http.StatusUnauthorized)
+		return
+	}
+	// Superadmin tidak memiliki skema tenant, sehingga nilainya boleh kosong.
+	schemaName, _ := r.Context().Value(middleware.SchemaNameKey).(string)
+	sessionID, _ := r.Context().Value(middleware.SessionIDKey).(string)
+
+	var input ChangePasswordInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.ChangePassword(r.Context(), schemaName, userID, sessionID, input); err != nil {
+		if errors.Is(err, ErrValidation) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, ErrWrongPassword) {
+			http.Error(w, "Password lama salah.", http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, ErrUserNotFound) {
+			http.Error(w, "User tidak ditemukan", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Gagal mengubah password: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Password berhasil diubah"})
+}
+
+// GetMyPermissions adalah handler untuk endpoint GET /me/permissions.
+// Frontend memakainya untuk menentukan menu yang ditampilkan.
+func (h *Handler) GetMyPermissions(w http.ResponseWriter, r *http.Request) {
+	perms, _ := r.Context().Value(middleware.PermissionsKey).(map[string]bool)
+
+	list := make([]string, 0, len(perms))
+	for _, p := range AllPermissions {
+		if perms[p.Name] {
+			list = append(list, p.Name)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string][]string{"permissions": list})
+}
+
+// ForgotPassword adalah handler untuk endpoint POST /auth/forgot-password.
+func (h *Handler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Header.Get("X-Tenant-ID")
+
+	var input ForgotPasswordInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.ForgotPassword(r.Context(), schemaName, input); err != nil {
+		if errors.Is(err, ErrValidation) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, ErrInvalidTenantID) {
+			http.Error(w, "ID Sekolah yang Anda masukkan tidak ditemukan.", http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Terjadi kesalahan internal: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Respons selalu sama, baik email terdaftar maupun tidak.
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Jika email terdaftar, link reset password telah dikirim"})
+}
+
+// ResetPassword adalah handler untuk endpoint POST /auth/reset-password.
+func (h *Handler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Header.Get("X-Tenant-ID")
+
+	var input ResetPasswordInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.ResetPassword(r.Context(), schemaName, input); err != nil {
+		if errors.Is(err, ErrValidation) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, ErrInvalidTenantID) {
+			http.Error(w, "ID Sekolah yang Anda masukkan tidak ditemukan.", http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, ErrInvalidResetToken) {
+			http.Error(w, "Link reset password tidak valid atau sudah kedaluwarsa.", http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Terjadi kesalahan internal: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Password berhasil direset, silakan login kembali"})
+}
+
+// GetLockedAccounts adalah handler untuk endpoint GET /locked-accounts.
+func (h *Handler) GetLockedAccounts(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	list, err := h.service.GetLockedAccounts(r.Context(), schemaName)
+	if err != nil {
+		http.Error(w, "Gagal mengambil data akun terkunci: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if list == nil {
+		list = []LoginAttempt{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(list)
+}
+
+// UnlockAccount adalah handler untuk endpoint POST /locked-accounts/unlock.
+func (h *Handler) UnlockAccount(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var input UnlockAccountInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.UnlockAccount(r.Context(), schemaName, userID, input); err != nil {
+		if errors.Is(err, ErrValidation) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Akun tidak sedang terkunci", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Gagal membuka kunci akun: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Kunci akun berhasil dibuka"})
+}
+
+// clientIP mengambil alamat IP klien. RemoteAddr sudah diisi ulang oleh middleware.RealIP
+// dari header X-Forwarded-For/X-Real-IP jika aplikasi berada di belakang proxy.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
 }
@@ -0,0 +1,163 @@
+// file: backend/internal/auth/lockout.go
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// ErrTooManyAttempts dikembalikan (dibungkus LockedError) jika login ditolak karena
+// terlalu banyak percobaan gagal.
+var ErrTooManyAttempts = errors.New("terlalu banyak percobaan login gagal")
+
+// LockedError menyertakan berapa lama klien harus menunggu sebelum mencoba lagi.
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%s, coba lagi dalam %d detik", ErrTooManyAttempts.Error(), e.retrySeconds())
+}
+
+func (e *LockedError) Unwrap() error {
+	return ErrTooManyAttempts
+}
+
+func (e *LockedError) retrySeconds() int {
+	return int((e.RetryAfter + time.Second - 1) / time.Second)
+}
+
+// throttlePolicy mengatur jeda bertahap dan penguncian untuk satu jenis penghitung.
+// Setelah delayAfter kegagalan, percobaan berikutnya harus menunggu 1, 2, 4, ... detik
+// (maksimal maxDelay). Setelah maxFailures kegagalan dalam window, penghitung dikunci
+// selama lockFor.
+type throttlePolicy struct {
+	window      time.Duration
+	delayAfter  int
+	maxDelay    time.Duration
+	maxFailures int
+	lockFor     time.Duration
+}
+
+var (
+	accountPolicy = throttlePolicy{
+		window:      15 * time.Minute,
+		delayAfter:  3,
+		maxDelay:    30 * time.Second,
+		maxFailures: 8,
+		lockFor:     15 * time.Minute,
+	}
+	ipPolicy = throttlePolicy{
+		window:      15 * time.Minute,
+		delayAfter:  10,
+		maxDelay:    30 * time.Second,
+		maxFailures: 50,
+		lockFor:     30 * time.Minute,
+	}
+)
+
+// retryAfter menghitung sisa waktu tunggu untuk penghitung a. Nol berarti boleh mencoba.
+func (p throttlePolicy) retryAfter(a *LoginAttempt, now time.Time) time.Duration {
+	if a == nil {
+		return 0
+	}
+	if a.LockedUntil != nil && now.Before(*a.LockedUntil) {
+		return a.LockedUntil.Sub(now)
+	}
+	if a.LastFailedAt == nil || now.Sub(*a.LastFailedAt) > p.window || a.FailedCount < p.delayAfter {
+		return 0
+	}
+
+	delay := p.maxDelay
+	if exp := a.FailedCount - p.delayAfter; exp < 16 {
+		delay = min(time.Second<<exp, p.maxDelay)
+	}
+	if next := a.LastFailedAt.Add(delay); now.Before(next) {
+		return next.Sub(now)
+	}
+	return 0
+}
+
+// checkLoginAllowed menolak login jika IP atau akun sedang dalam masa jeda atau terkunci.
+func (s *service) checkLoginAllowed(ctx context.Context, schemaName, email, clientIP string) error {
+	now := time.Now()
+
+	ipAttempt, err := s.attempts.GetIP(ctx, clientIP)
+	if err != nil {
+		return err
+	}
+	if wait := ipPolicy.retryAfter(ipAttempt, now); wait > 0 {
+		return &LockedError{RetryAfter: wait}
+	}
+
+	account, err := s.attempts.GetAccount(ctx, schemaName, normalizeEmail(email))
+	if err != nil {
+		return err
+	}
+	if wait := accountPolicy.retryAfter(account, now); wait > 0 {
+		return &LockedError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// recordLoginFailure mencatat login gagal untuk IP dan, jika email diisi, untuk akun.
+// Penghitung yang melewati batas langsung dikunci. Error hanya dicatat ke log agar
+// respons login tetap "email atau password salah".
+func (s *service) recordLoginFailure(ctx context.Context, schemaName, email, clientIP string) {
+	now := time.Now()
+
+	ipAttempt, err := s.attempts.RecordIPFailure(ctx, clientIP, ipPolicy.window)
+	if err != nil {
+		log.Printf("ERROR saat mencatat percobaan login IP: %v", err)
+	} else if ipAttempt.FailedCount >= ipPolicy.maxFailures {
+		until := now.Add(ipPolicy.lockFor)
+		if err := s.attempts.LockIP(ctx, clientIP, ipAttempt.FailedCount, until); err != nil {
+			log.Printf("ERROR saat mengunci IP: %v", err)
+		} else {
+			log.Printf("PERINGATAN: IP %s dikunci sampai %s setelah %d percobaan gagal", clientIP, until.Format(time.RFC3339), ipAttempt.FailedCount)
+		}
+	}
+
+	if email == "" {
+		return
+	}
+	email = normalizeEmail(email)
+	account, err := s.attempts.RecordAccountFailure(ctx, schemaName, email, accountPolicy.window)
+	if err != nil {
+		log.Printf("ERROR saat mencatat percobaan login akun: %v", err)
+		return
+	}
+	if account.FailedCount >= accountPolicy.maxFailures {
+		until := now.Add(accountPolicy.lockFor)
+		if err := s.attempts.LockAccount(ctx, schemaName, email, clientIP, account.FailedCount, until); err != nil {
+			log.Printf("ERROR saat mengunci akun: %v", err)
+			return
+		}
+		log.Printf("PERINGATAN: Akun %s (skema '%s') dikunci sampai %s setelah %d percobaan gagal", email, schemaName, until.Format(time.RFC3339), account.FailedCount)
+	}
+}
+
+// GetLockedAccounts mengambil akun yang sedang terkunci di sekolah admin.
+func (s *service) GetLockedAccounts(ctx context.Context, schemaName string) ([]LoginAttempt, error) {
+	list, err := s.attempts.GetLockedAccounts(ctx, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil data akun terkunci di service: %w", err)
+	}
+	return list, nil
+}
+
+// UnlockAccount membuka kunci akun di sekolah admin sebelum masa kuncinya habis.
+func (s *service) UnlockAccount(ctx context.Context, schemaName string, actorUserID string, input UnlockAccountInput) error {
+	if err := s.validate.Struct(input); err != nil {
+		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	return s.attempts.UnlockAccount(ctx, schemaName, normalizeEmail(input.Email), actorUserID)
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
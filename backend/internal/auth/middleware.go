@@ -15,13 +15,23 @@ import (
 
 // Middleware struct untuk menampung dependensi seperti kunci rahasia.
 type Middleware struct {
-	jwtSecret []byte
+	jwtSecret   []byte
+	sessions    Repository
+	permissions PermissionResolver
+}
+
+// PermissionResolver mengambil permission milik user tenant.
+// Diimplementasikan oleh role.Repository.
+type PermissionResolver interface {
+	GetUserPermissions(ctx context.Context, schemaName string, userID string) ([]string, error)
 }
 
 // NewMiddleware membuat instance baru dari auth middleware.
-func NewMiddleware(jwtSecret string) *Middleware {
+func NewMiddleware(jwtSecret string, sessions Repository, permissions PermissionResolver) *Middleware {
 	return &Middleware{
-		jwtSecret: []byte(jwtSecret),
+		jwtSecret:   []byte(jwtSecret),
+		sessions:    sessions,
+		permissions: permissions,
 	}
 }
 
@@ -59,11 +69,39 @@ func (m *Middleware) AuthMiddleware(next http.Handler) http.Handler {
 
 		// 5. Cek apakah token valid dan ambil claims-nya
 		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
+			// 6. Pastikan sesi pemilik token belum dicabut (logout, reset password, guru dihapus)
+			sessionID, _ := claims["sid"].(string)
+			if sessionID == "" {
+				http.Error(w, "Token tidak valid", http.StatusUnauthorized)
+				return
+			}
+			schemaName, _ := claims["sch"].(string)
+			active, err := m.sessions.IsSessionActive(r.Context(), schemaName, sessionID)
+			if err != nil {
+				http.Error(w, "Gagal memeriksa sesi: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if !active {
+				http.Error(w, "Sesi telah berakhir, silakan login kembali", http.StatusUnauthorized)
+				return
+			}
+
+			// 7. Muat permission user untuk dipakai oleh auth.Require
+			userID, _ := claims["sub"].(string)
+			role, _ := claims["role"].(string)
+			perms, err := m.loadPermissions(r.Context(), schemaName, userID, role)
+			if err != nil {
+				http.Error(w, "Gagal memuat hak akses: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+
 			ctx := r.Context()
 			// 3. GUNAKAN KUNCI DARI PAKET middleware
 			ctx = context.WithValue(ctx, middleware.UserIDKey, claims["sub"])
 			ctx = context.WithValue(ctx, middleware.UserRoleKey, claims["role"])
 			ctx = context.WithValue(ctx, middleware.SchemaNameKey, claims["sch"])
+			ctx = context.WithValue(ctx, middleware.SessionIDKey, sessionID)
+			ctx = context.WithValue(ctx, middleware.PermissionsKey, perms)
 
 			next.ServeHTTP(w, r.WithContext(ctx))
 		} else {
@@ -72,6 +110,27 @@ func (m *Middleware) AuthMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// loadPermissions menentukan permission user: admin selalu mendapat semua permission,
+// superadmin tidak memakai permission tenant, role lain dibaca dari skema tenant.
+func (m *Middleware) loadPermissions(ctx context.Context, schemaName, userID, role string) (map[string]bool, error) {
+	switch {
+	case role == "admin":
+		return allPermissionSet(), nil
+	case schemaName == "":
+		return map[string]bool{}, nil
+	}
+
+	list, err := m.permissions.GetUserPermissions(ctx, schemaName, userID)
+	if err != nil {
+		return nil, err
+	}
+	perms := make(map[string]bool, len(list))
+	for _, p := range list {
+		perms[p] = true
+	}
+	return perms, nil
+}
+
 // Authorize adalah fungsi tingkat tinggi yang membuat middleware otorisasi.
 func Authorize(allowedRoles ...string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -0,0 +1,72 @@
+// file: backend/internal/auth/model.go
+package auth
+
+import "time"
+
+// RefreshToken merepresentasikan satu baris dari tabel 'refresh_tokens'.
+// Token asli tidak pernah disimpan, hanya hash SHA-256-nya.
+type RefreshToken struct {
+	ID         string     `json:"id"`
+	SessionID  string     `json:"session_id"`
+	UserID     string     `json:"user_id"`
+	UserRole   string     `json:"user_role"`
+	TokenHash  string     `json:"-"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	ReplacedBy *string    `json:"replaced_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// TokenPair adalah hasil login atau refresh: access token (JWT) dan refresh token baru.
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// RefreshInput adalah payload untuk endpoint refresh dan logout.
+type RefreshInput struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// PasswordResetToken merepresentasikan satu baris dari tabel 'password_reset_tokens'.
+type PasswordResetToken struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ChangePasswordInput adalah payload untuk endpoint PUT /me/password.
+type ChangePasswordInput struct {
+	OldPassword string `json:"old_password" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,min=8"`
+}
+
+// ForgotPasswordInput adalah payload untuk meminta email reset password.
+type ForgotPasswordInput struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ResetPasswordInput adalah payload untuk mengatur password baru memakai token dari email.
+type ResetPasswordInput struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,min=8"`
+}
+
+// LoginAttempt adalah penghitung percobaan login gagal untuk satu akun atau satu IP.
+type LoginAttempt struct {
+	SchemaName   string     `json:"schema_name,omitempty"`
+	Email        string     `json:"email,omitempty"`
+	IPAddress    string     `json:"ip_address,omitempty"`
+	FailedCount  int        `json:"failed_count"`
+	LastFailedAt *time.Time `json:"last_failed_at"`
+	LockedUntil  *time.Time `json:"locked_until"`
+}
+
+// UnlockAccountInput adalah payload untuk membuka kunci akun yang terkunci.
+type UnlockAccountInput struct {
+	Email string `json:"email" validate:"required,email"`
+}
@@ -0,0 +1,154 @@
+// file: backend/internal/auth/permission.go
+package auth
+
+import (
+	"net/http"
+	"skoola/internal/middleware"
+)
+
+// Daftar permission bernama yang dipakai oleh route. Role "admin" selalu memiliki
+// semua permission; role lain mendapatkannya dari tabel roles di skema tenant.
+const (
+	PermGuruRead              = "guru:read"
+	PermGuruManage            = "guru:manage"
+	PermSiswaRead             = "siswa:read"
+	PermSiswaManage           = "siswa:manage"
+	PermProfilManage          = "profil:manage"
+	PermMasterDataManage      = "master-data:manage"
+	PermTahunAjaranRead       = "tahun-ajaran:read"
+	PermTahunAjaranManage     = "tahun-ajaran:manage"
+	PermMapelRead             = "mapel:read"
+	PermMapelManage           = "mapel:manage"
+	PermKurikulumRead         = "kurikulum:read"
+	PermKurikulumManage       = "kurikulum:manage"
+	PermRombelRead            = "rombel:read"
+	PermRombelManage          = "rombel:manage"
+	PermPembelajaranRead      = "pembelajaran:read"
+	PermPembelajaranWrite     = "pembelajaran:write"
+	PermPenilaianRead         = "penilaian:read"
+	PermPenilaianWrite        = "penilaian:write"
+	PermJenisUjianRead        = "jenis-ujian:read"
+	PermUjianManage           = "ujian:manage"
+	PermPresensiRead          = "presensi:read"
+	PermPresensiWrite         = "presensi:write"
+	PermPresensiJamWrite      = "presensi-jam:write"
+	PermJurnalRead            = "jurnal:read"
+	PermJurnalWrite           = "jurnal:write"
+	PermJurnalRekap           = "jurnal:rekap"
+	PermJadwalRead            = "jadwal:read"
+	PermJadwalManage          = "jadwal:manage"
+	PermPrestasiRead          = "prestasi:read"
+	PermPrestasiWrite         = "prestasi:write"
+	PermEkstrakurikulerManage = "ekstrakurikuler:manage"
+	PermAkunManage            = "akun:manage"
+	PermRoleManage            = "role:manage"
+	PermAuditRead             = "audit:read"
+	PermWaliMuridManage       = "wali-murid:manage"
+	PermPengumumanManage      = "pengumuman:manage"
+	PermRaporRead             = "rapor:read"
+)
+
+// Permission adalah satu entri katalog permission beserta keterangannya.
+type Permission struct {
+	Name      string `json:"name"`
+	Deskripsi string `json:"deskripsi"`
+}
+
+// AllPermissions adalah katalog lengkap permission yang dapat diberikan ke role.
+var AllPermissions = []Permission{
+	{PermGuruRead, "Melihat data guru"},
+	{PermGuruManage, "Menambah, mengubah, dan menghapus data guru serta riwayat kepegawaian"},
+	{PermSiswaRead, "Melihat data siswa"},
+	{PermSiswaManage, "Menambah, mengubah, menghapus, dan mengimpor data siswa"},
+	{PermProfilManage, "Mengelola profil sekolah"},
+	{PermMasterDataManage, "Mengelola data master (jenjang, jabatan, tingkatan, jenis ujian, ukuran kertas)"},
+	{PermTahunAjaranRead, "Melihat tahun ajaran"},
+	{PermTahunAjaranManage, "Mengelola tahun ajaran"},
+	{PermMapelRead, "Melihat detail mata pelajaran"},
+	{PermMapelManage, "Mengelola mata pelajaran dan kelompok mata pelajaran"},
+	{PermKurikulumRead, "Melihat kurikulum"},
+	{PermKurikulumManage, "Mengelola kurikulum, fase, dan pemetaan"},
+	{PermRombelRead, "Melihat detail kelas, anggota, dan pengajar"},
+	{PermRombelManage, "Mengelola rombongan belajar"},
+	{PermPembelajaranRead, "Melihat rencana pembelajaran dan monitoring ujian"},
+	{PermPembelajaranWrite, "Mengelola materi, tujuan pembelajaran, dan ujian"},
+	{PermPenilaianRead, "Melihat nilai"},
+	{PermPenilaianWrite, "Menginput nilai dan penilaian sumatif"},
+	{PermJenisUjianRead, "Melihat jenis ujian"},
+	{PermUjianManage, "Mengelola ujian master, peserta, ruangan, dan kartu ujian"},
+	{PermPresensiRead, "Melihat presensi"},
+	{PermPresensiWrite, "Menginput dan menghapus presensi"},
+	{PermPresensiJamWrite, "Mencatat presensi per jam pelajaran pada mapel yang diampu"},
+	{PermJurnalRead, "Melihat jurnal mengajar dan perbandingan rencana pembelajaran"},
+	{PermJurnalWrite, "Mengisi jurnal mengajar"},
+	{PermJurnalRekap, "Melihat rekap bulanan jurnal mengajar seluruh guru"},
+	{PermJadwalRead, "Melihat dan mencetak jadwal pelajaran"},
+	{PermJadwalManage, "Mengatur jam pelajaran, beban mapel, ketersediaan guru, dan menyusun jadwal"},
+	{PermPrestasiRead, "Melihat prestasi siswa"},
+	{PermPrestasiWrite, "Menambah dan menghapus prestasi siswa"},
+	{PermEkstrakurikulerManage, "Mengelola ekstrakurikuler dan anggotanya"},
+	{PermAkunManage, "Melihat dan membuka akun yang terkunci"},
+	{PermRoleManage, "Mengelola role dan permission"},
+	{PermAuditRead, "Melihat jejak perubahan data (audit log)"},
+	{PermWaliMuridManage, "Mengelola wali murid, akun, dan hubungannya dengan siswa"},
+	{PermPengumumanManage, "Mengelola pengumuman sekolah"},
+	{PermRaporRead, "Melihat dan mencetak rapor siswa"},
+}
+
+// IsValidPermission memeriksa apakah nama permission ada di katalog.
+func IsValidPermission(name string) bool {
+	for _, p := range AllPermissions {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// allPermissionSet dipakai untuk role admin.
+func allPermissionSet() map[string]bool {
+	set := make(map[string]bool, len(AllPermissions))
+	for _, p := range AllPermissions {
+		set[p.Name] = true
+	}
+	return set
+}
+
+// Require adalah middleware yang hanya mengizinkan user yang memiliki permission perm.
+// Permission user dimuat oleh AuthMiddleware ke dalam konteks.
+func Require(perm string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			perms, ok := r.Context().Value(middleware.PermissionsKey).(map[string]bool)
+			if !ok {
+				http.Error(w, "Permission pengguna tidak ditemukan", http.StatusInternalServerError)
+				return
+			}
+			if !perms[perm] {
+				http.Error(w, "Anda tidak memiliki hak akses untuk sumber daya ini", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireAny seperti Require, tetapi cukup salah satu permission yang dimiliki user.
+func RequireAny(perms ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			owned, ok := r.Context().Value(middleware.PermissionsKey).(map[string]bool)
+			if !ok {
+				http.Error(w, "Permission pengguna tidak ditemukan", http.StatusInternalServerError)
+				return
+			}
+			for _, perm := range perms {
+				if owned[perm] {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			http.Error(w, "Anda tidak memiliki hak akses untuk sumber daya ini", http.StatusForbidden)
+		})
+	}
+}
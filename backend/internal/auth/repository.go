@@ -0,0 +1,242 @@
+// file: backend/internal/auth/repository.go
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"skoola/pkg/database"
+)
+
+// Repository mendefinisikan interface untuk penyimpanan refresh token dan sesi login.
+// schemaName kosong berarti sesi milik superadmin yang disimpan di skema public.
+type Repository interface {
+	Create(ctx context.Context, schemaName string, token *RefreshToken) error
+	GetByHash(ctx context.Context, schemaName string, tokenHash string) (*RefreshToken, error)
+	Rotate(ctx context.Context, schemaName string, oldID string, next *RefreshToken) error
+	RevokeSession(ctx context.Context, schemaName string, sessionID string) error
+	RevokeAllForUser(ctx context.Context, schemaName string, userID string) error
+	RevokeOtherSessions(ctx context.Context, schemaName string, userID string, keepSessionID string) error
+	IsSessionActive(ctx context.Context, schemaName string, sessionID string) (bool, error)
+	CreateResetToken(ctx context.Context, schemaName string, token *PasswordResetToken) error
+	ResetPassword(ctx context.Context, schemaName string, tokenHash string, passwordHash string) (string, error)
+}
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewRepository membuat instance baru dari postgresRepository.
+func NewRepository(db *sql.DB) Repository {
+	return &postgresRepository{db: db}
+}
+
+// sessionSchema mengembalikan skema tempat sesi disimpan.
+func sessionSchema(schemaName string) string {
+	if schemaName == "" {
+		return "public"
+	}
+	return schemaName
+}
+
+func (r *postgresRepository) Create(ctx context.Context, schemaName string, t *RefreshToken) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, sessionSchema(schemaName))
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertRefreshToken(ctx, tx, t); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *postgresRepository) GetByHash(ctx context.Context, schemaName string, tokenHash string) (*RefreshToken, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, sessionSchema(schemaName))
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT rt.id, rt.session_id, rt.user_id, u.role, rt.token_hash, rt.expires_at, rt.revoked_at, rt.replaced_by, rt.created_at
+		FROM refresh_tokens rt
+		JOIN users u ON u.id = rt.user_id
+		WHERE rt.token_hash = $1
+	`
+	var t RefreshToken
+	err = tx.QueryRowContext(ctx, query, tokenHash).Scan(
+		&t.ID, &t.SessionID, &t.UserID, &t.UserRole, &t.TokenHash, &t.ExpiresAt, &t.RevokedAt, &t.ReplacedBy, &t.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("gagal memindai data refresh token: %w", err)
+	}
+	return &t, tx.Commit()
+}
+
+// Rotate mencabut token lama dan menyimpan penggantinya dalam satu transaksi.
+// Mengembalikan sql.ErrNoRows jika token lama sudah dicabut oleh request lain.
+func (r *postgresRepository) Rotate(ctx context.Context, schemaName string, oldID string, next *RefreshToken) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, sessionSchema(schemaName))
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE refresh_tokens SET revoked_at = NOW(), replaced_by = $1 WHERE id = $2 AND revoked_at IS NULL`
+	result, err := tx.ExecContext(ctx, query, next.ID, oldID)
+	if err != nil {
+		return fmt.Errorf("gagal mencabut refresh token lama: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("gagal memeriksa baris yang terpengaruh: %w", err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err := insertRefreshToken(ctx, tx, next); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *postgresRepository) RevokeSession(ctx context.Context, schemaName string, sessionID string) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, sessionSchema(schemaName))
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE refresh_tokens SET revoked_at = NOW() WHERE session_id = $1 AND revoked_at IS NULL`
+	if _, err := tx.ExecContext(ctx, query, sessionID); err != nil {
+		return fmt.Errorf("gagal mencabut sesi: %w", err)
+	}
+	return tx.Commit()
+}
+
+// RevokeAllForUser mencabut semua sesi aktif milik seorang user.
+func (r *postgresRepository) RevokeAllForUser(ctx context.Context, schemaName string, userID string) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, sessionSchema(schemaName))
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`
+	if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+		return fmt.Errorf("gagal mencabut sesi user: %w", err)
+	}
+	return tx.Commit()
+}
+
+// RevokeOtherSessions mencabut semua sesi aktif milik user kecuali sesi yang sedang dipakai.
+func (r *postgresRepository) RevokeOtherSessions(ctx context.Context, schemaName string, userID string, keepSessionID string) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, sessionSchema(schemaName))
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND session_id <> $2 AND revoked_at IS NULL`
+	if _, err := tx.ExecContext(ctx, query, userID, keepSessionID); err != nil {
+		return fmt.Errorf("gagal mencabut sesi lain milik user: %w", err)
+	}
+	return tx.Commit()
+}
+
+// IsSessionActive bernilai true jika sesi masih memiliki refresh token yang belum dicabut dan belum kedaluwarsa.
+func (r *postgresRepository) IsSessionActive(ctx context.Context, schemaName string, sessionID string) (bool, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, sessionSchema(schemaName))
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT EXISTS(SELECT 1 FROM refresh_tokens WHERE session_id = $1 AND revoked_at IS NULL AND expires_at > NOW())`
+	var active bool
+	if err := tx.QueryRowContext(ctx, query, sessionID).Scan(&active); err != nil {
+		return false, fmt.Errorf("gagal memeriksa status sesi: %w", err)
+	}
+	return active, tx.Commit()
+}
+
+// CreateResetToken menyimpan token reset password baru. Token lama milik user yang
+// belum dipakai ikut dinonaktifkan sehingga hanya link terakhir yang berlaku.
+func (r *postgresRepository) CreateResetToken(ctx context.Context, schemaName string, t *PasswordResetToken) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, sessionSchema(schemaName))
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	invalidateQuery := `UPDATE password_reset_tokens SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL`
+	if _, err := tx.ExecContext(ctx, invalidateQuery, t.UserID); err != nil {
+		return fmt.Errorf("gagal menonaktifkan token reset lama: %w", err)
+	}
+
+	query := `
+		INSERT INTO password_reset_tokens (id, user_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at
+	`
+	if err := tx.QueryRowContext(ctx, query, t.ID, t.UserID, t.TokenHash, t.ExpiresAt).Scan(&t.CreatedAt); err != nil {
+		return fmt.Errorf("gagal menyimpan token reset password: %w", err)
+	}
+	return tx.Commit()
+}
+
+// ResetPassword memakai token reset dan mengganti password user dalam satu transaksi,
+// lalu mengembalikan ID user tersebut. Mengembalikan sql.ErrNoRows jika token tidak
+// ditemukan, sudah dipakai, atau sudah kedaluwarsa.
+func (r *postgresRepository) ResetPassword(ctx context.Context, schemaName string, tokenHash string, passwordHash string) (string, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, sessionSchema(schemaName))
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	consumeQuery := `
+		UPDATE password_reset_tokens SET used_at = NOW()
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING user_id
+	`
+	var userID string
+	if err := tx.QueryRowContext(ctx, consumeQuery, tokenHash).Scan(&userID); err != nil {
+		if err == sql.ErrNoRows {
+			return "", sql.ErrNoRows
+		}
+		return "", fmt.Errorf("gagal memakai token reset password: %w", err)
+	}
+
+	updateQuery := `UPDATE users SET password_hash = $1, updated_at = NOW() WHERE id = $2`
+	result, err := tx.ExecContext(ctx, updateQuery, passwordHash, userID)
+	if err != nil {
+		return "", fmt.Errorf("gagal mengeksekusi query update password: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return "", fmt.Errorf("gagal memeriksa baris yang terpengaruh: %w", err)
+	}
+	if rowsAffected == 0 {
+		return "", sql.ErrNoRows
+	}
+	return userID, tx.Commit()
+}
+
+func insertRefreshToken(ctx context.Context, tx *sql.Tx, t *RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (id, session_id, user_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at
+	`
+	if err := tx.QueryRowContext(ctx, query, t.ID, t.SessionID, t.UserID, t.TokenHash, t.ExpiresAt).Scan(&t.CreatedAt); err != nil {
+		return fmt.Errorf("gagal menyimpan refresh token: %w", err)
+	}
+	return nil
+}
@@ -3,26 +3,55 @@ package auth
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"log"
+	"net/url"
 	"skoola/internal/teacher"
 	"skoola/internal/tenant" // <-- 1. IMPOR PAKET TENANT
+	"skoola/pkg/mailer"
 	"time"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	// AccessTokenTTL adalah masa berlaku JWT yang dikirim di header Authorization.
+	AccessTokenTTL = time.Hour
+	// RefreshTokenTTL adalah masa berlaku refresh token sejak terakhir kali dirotasi.
+	RefreshTokenTTL = 30 * 24 * time.Hour
+	// PasswordResetTTL adalah masa berlaku link reset password yang dikirim lewat email.
+	PasswordResetTTL = time.Hour
+)
+
 var (
 	ErrUserNotFound       = errors.New("user tidak ditemukan")
 	ErrInvalidCredentials = errors.New("email atau password salah")
 	// --- 2. TAMBAHKAN ERROR BARU ---
-	ErrInvalidTenantID = errors.New("ID Sekolah tidak valid atau tidak ditemukan")
+	ErrInvalidTenantID     = errors.New("ID Sekolah tidak valid atau tidak ditemukan")
+	ErrInvalidRefreshToken = errors.New("refresh token tidak valid atau sudah kedaluwarsa")
+	ErrInvalidResetToken   = errors.New("token reset password tidak valid atau sudah kedaluwarsa")
+	ErrWrongPassword       = errors.New("password lama salah")
+	ErrValidation          = errors.New("validation failed")
 )
 
 type Service interface {
-	Login(ctx context.Context, schemaName string, input LoginInput) (string, error)
+	Login(ctx context.Context, schemaName string, clientIP string, input LoginInput) (*TokenPair, error)
+	Refresh(ctx context.Context, schemaName string, input RefreshInput) (*TokenPair, error)
+	Logout(ctx context.Context, schemaName string, input RefreshInput) error
+	ChangePassword(ctx context.Context, schemaName string, userID string, sessionID string, input ChangePasswordInput) error
+	ForgotPassword(ctx context.Context, schemaName string, input ForgotPasswordInput) error
+	ResetPassword(ctx context.Context, schemaName string, input ResetPasswordInput) error
+	GetLockedAccounts(ctx context.Context, schemaName string) ([]LoginAttempt, error)
+	UnlockAccount(ctx context.Context, schemaName string, actorUserID string, input UnlockAccountInput) error
 }
 
 type LoginInput struct {
@@ -31,34 +60,51 @@ type LoginInput struct {
 }
 
 type service struct {
+	repo        Repository
+	attempts    AttemptRepository
 	teacherRepo teacher.Repository
 	tenantRepo  tenant.Repository // <-- 3. TAMBAHKAN TENANT REPO
+	mailer      mailer.Mailer
+	validate    *validator.Validate
 	jwtSecret   []byte
+	resetURL    string
 }
 
 // --- 4. PERBARUI FUNGSI NewService ---
-func NewService(teacherRepo teacher.Repository, tenantRepo tenant.Repository, jwtSecret string) Service {
+// resetURL adalah alamat halaman frontend untuk mengatur password baru; token dan
+// ID sekolah ditambahkan sebagai query string pada link di email.
+func NewService(repo Repository, attempts AttemptRepository, teacherRepo teacher.Repository, tenantRepo tenant.Repository, mail mailer.Mailer, validate *validator.Validate, jwtSecret string, resetURL string) Service {
 	return &service{
+		repo:        repo,
+		attempts:    attempts,
 		teacherRepo: teacherRepo,
 		tenantRepo:  tenantRepo, // Tambahkan ini
+		mailer:      mail,
+		validate:    validate,
 		jwtSecret:   []byte(jwtSecret),
+		resetURL:    resetURL,
 	}
 }
 
 // --- 5. PERBARUI LOGIKA FUNGSI LOGIN ---
-func (s *service) Login(ctx context.Context, schemaName string, input LoginInput) (string, error) {
+func (s *service) Login(ctx context.Context, schemaName string, clientIP string, input LoginInput) (*TokenPair, error) {
 	var user *teacher.User
 	var err error
 
 	log.Printf("--- PROSES LOGIN DIMULAI UNTUK EMAIL: %s ---", input.Email)
 
+	if err := s.checkLoginAllowed(ctx, schemaName, input.Email, clientIP); err != nil {
+		log.Printf("HASIL: Login ditolak sementara: %v", err)
+		return nil, err
+	}
+
 	if schemaName == "" {
 		// Logika untuk Superadmin (tidak berubah)
 		log.Printf("Mencari user di public.users (Login Superadmin)...")
 		user, err = s.teacherRepo.GetPublicUserByEmail(ctx, input.Email)
 		if err != nil {
 			log.Printf("ERROR saat mencari public user: %v", err)
-			return "", fmt.Errorf("error saat mencari public user: %w", err)
+			return nil, fmt.Errorf("error saat mencari public user: %w", err)
 		}
 	} else {
 		// Logika untuk Admin Sekolah (DENGAN PERUBAHAN)
@@ -66,11 +112,12 @@ func (s *service) Login(ctx context.Context, schemaName string, input LoginInput
 		exists, err := s.tenantRepo.CheckSchemaExists(ctx, schemaName)
 		if err != nil {
 			log.Printf("ERROR saat validasi schema: %v", err)
-			return "", fmt.Errorf("error saat validasi schema: %w", err)
+			return nil, fmt.Errorf("error saat validasi schema: %w", err)
 		}
 		if !exists {
 			log.Printf("HASIL: ID Sekolah '%s' TIDAK DITEMUKAN.", schemaName)
-			return "", ErrInvalidTenantID
+			s.recordLoginFailure(ctx, "", "", clientIP)
+			return nil, ErrInvalidTenantID
 		}
 		log.Printf("HASIL: ID Sekolah valid. Melanjutkan pencarian user...")
 
@@ -78,13 +125,14 @@ func (s *service) Login(ctx context.Context, schemaName string, input LoginInput
 		user, err = s.teacherRepo.GetByEmail(ctx, schemaName, input.Email)
 		if err != nil {
 			log.Printf("ERROR saat mencari user tenant: %v", err)
-			return "", fmt.Errorf("error saat mencari user tenant: %w", err)
+			return nil, fmt.Errorf("error saat mencari user tenant: %w", err)
 		}
 	}
 
 	if user == nil {
 		log.Printf("HASIL: User dengan email '%s' TIDAK DITEMUKAN.", input.Email)
-		return "", ErrInvalidCredentials // Diubah agar lebih konsisten
+		s.recordLoginFailure(ctx, schemaName, input.Email, clientIP)
+		return nil, ErrInvalidCredentials // Diubah agar lebih konsisten
 	}
 	log.Printf("HASIL: User ditemukan. ID: %s, Role: %s", user.ID, user.Role)
 
@@ -92,28 +140,317 @@ func (s *service) Login(ctx context.Context, schemaName string, input LoginInput
 	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(input.Password))
 	if err != nil {
 		log.Printf("HASIL: Perbandingan password GAGAL.")
-		return "", ErrInvalidCredentials
+		s.recordLoginFailure(ctx, schemaName, input.Email, clientIP)
+		return nil, ErrInvalidCredentials
 	}
 	log.Printf("HASIL: Perbandingan password BERHASIL.")
 
+	if err := s.attempts.ResetAccount(ctx, schemaName, normalizeEmail(input.Email)); err != nil {
+		log.Printf("ERROR saat mereset percobaan login: %v", err)
+	}
+
+	pair, err := s.startSession(ctx, schemaName, user.ID, user.Role)
+	if err != nil {
+		log.Printf("ERROR saat membuat sesi login: %v", err)
+		return nil, err
+	}
+
+	log.Printf("--- PROSES LOGIN BERHASIL ---")
+	return pair, nil
+}
+
+// Refresh menukar refresh token yang masih berlaku dengan pasangan token baru.
+// Refresh token lama langsung dicabut (rotasi). Jika token yang sudah dicabut
+// dipakai lagi, seluruh sesi dianggap bocor dan ikut dicabut.
+func (s *service) Refresh(ctx context.Context, schemaName string, input RefreshInput) (*TokenPair, error) {
+	if input.RefreshToken == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err := s.checkSchema(ctx, schemaName); err != nil {
+		return nil, err
+	}
+
+	stored, err := s.repo.GetByHash(ctx, schemaName, hashToken(input.RefreshToken))
+	if err != nil {
+		return nil, fmt.Errorf("gagal mencari refresh token: %w", err)
+	}
+	if stored == nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if stored.RevokedAt != nil {
+		log.Printf("PERINGATAN: refresh token yang sudah dicabut dipakai ulang, sesi %s dicabut", stored.SessionID)
+		if err := s.repo.RevokeSession(ctx, schemaName, stored.SessionID); err != nil {
+			return nil, fmt.Errorf("gagal mencabut sesi: %w", err)
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	rawToken, next, err := newRefreshToken(stored.SessionID, stored.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Rotate(ctx, schemaName, stored.ID, next); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Token sudah dirotasi oleh request lain di antara GetByHash dan Rotate.
+			if err := s.repo.RevokeSession(ctx, schemaName, stored.SessionID); err != nil {
+				return nil, fmt.Errorf("gagal mencabut sesi: %w", err)
+			}
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, fmt.Errorf("gagal merotasi refresh token: %w", err)
+	}
+
+	accessToken, err := s.signAccessToken(schemaName, stored.UserID, stored.UserRole, stored.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: rawToken,
+		ExpiresIn:    int64(AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+// Logout mencabut sesi milik refresh token. Token yang tidak dikenal diabaikan
+// agar logout dapat dipanggil berulang kali.
+func (s *service) Logout(ctx context.Context, schemaName string, input RefreshInput) error {
+	if input.RefreshToken == "" {
+		return ErrInvalidRefreshToken
+	}
+	if err := s.checkSchema(ctx, schemaName); err != nil {
+		return err
+	}
+
+	stored, err := s.repo.GetByHash(ctx, schemaName, hashToken(input.RefreshToken))
+	if err != nil {
+		return fmt.Errorf("gagal mencari refresh token: %w", err)
+	}
+	if stored == nil {
+		return nil
+	}
+	return s.repo.RevokeSession(ctx, schemaName, stored.SessionID)
+}
+
+// ChangePassword mengganti password user yang sedang login setelah mencocokkan password lama.
+// Sesi lain milik user dicabut, sedangkan sesi yang dipakai untuk request ini tetap berlaku.
+func (s *service) ChangePassword(ctx context.Context, schemaName string, userID string, sessionID string, input ChangePasswordInput) error {
+	if err := s.validate.Struct(input); err != nil {
+		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+
+	user, err := s.teacherRepo.GetUserByID(ctx, sessionSchema(schemaName), userID)
+	if err != nil {
+		return fmt.Errorf("gagal mencari user: %w", err)
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(input.OldPassword)); err != nil {
+		return ErrWrongPassword
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.NewPassword), 10)
+	if err != nil {
+		return fmt.Errorf("gagal melakukan hash password baru: %w", err)
+	}
+	if err := s.teacherRepo.UpdateUserPassword(ctx, sessionSchema(schemaName), user.ID, string(hashedPassword)); err != nil {
+		return fmt.Errorf("gagal memperbarui password: %w", err)
+	}
+	if err := s.repo.RevokeOtherSessions(ctx, schemaName, user.ID, sessionID); err != nil {
+		return fmt.Errorf("gagal mencabut sesi lain: %w", err)
+	}
+	return nil
+}
+
+// ForgotPassword mengirim link reset password ke email user. Email yang tidak terdaftar
+// tidak menghasilkan error agar endpoint ini tidak bisa dipakai untuk menebak akun.
+func (s *service) ForgotPassword(ctx context.Context, schemaName string, input ForgotPasswordInput) error {
+	if err := s.validate.Struct(input); err != nil {
+		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	if err := s.checkSchema(ctx, schemaName); err != nil {
+		return err
+	}
+
+	var user *teacher.User
+	var err error
+	if schemaName == "" {
+		user, err = s.teacherRepo.GetPublicUserByEmail(ctx, input.Email)
+	} else {
+		user, err = s.teacherRepo.GetByEmail(ctx, schemaName, input.Email)
+	}
+	if err != nil {
+		return fmt.Errorf("gagal mencari user: %w", err)
+	}
+	if user == nil {
+		log.Printf("Permintaan reset password untuk email yang tidak terdaftar: %s", input.Email)
+		return nil
+	}
+
+	rawToken, err := randomToken()
+	if err != nil {
+		return err
+	}
+	resetToken := &PasswordResetToken{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		TokenHash: hashToken(rawToken),
+		ExpiresAt: time.Now().Add(PasswordResetTTL),
+	}
+	if err := s.repo.CreateResetToken(ctx, schemaName, resetToken); err != nil {
+		return err
+	}
+
+	link, err := s.buildResetLink(schemaName, rawToken)
+	if err != nil {
+		return err
+	}
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Reset Password Akun Skoola",
+		Body: fmt.Sprintf(
+			"Halo,\n\nKami menerima permintaan untuk mengatur ulang password akun Skoola Anda.\n"+
+				"Buka link berikut untuk membuat password baru:\n\n%s\n\n"+
+				"Link ini hanya dapat digunakan satu kali dan berlaku selama %d menit.\n"+
+				"Jika Anda tidak merasa meminta reset password, abaikan email ini.\n",
+			link, int(PasswordResetTTL.Minutes()),
+		),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("gagal mengirim email reset password: %w", err)
+	}
+	return nil
+}
+
+// ResetPassword mengatur password baru memakai token dari email, lalu mencabut semua sesi user.
+func (s *service) ResetPassword(ctx context.Context, schemaName string, input ResetPasswordInput) error {
+	if err := s.validate.Struct(input); err != nil {
+		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	if err := s.checkSchema(ctx, schemaName); err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.NewPassword), 10)
+	if err != nil {
+		return fmt.Errorf("gagal melakukan hash password baru: %w", err)
+	}
+	userID, err := s.repo.ResetPassword(ctx, schemaName, hashToken(input.Token), string(hashedPassword))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+	if err := s.repo.RevokeAllForUser(ctx, schemaName, userID); err != nil {
+		return fmt.Errorf("gagal mencabut sesi user: %w", err)
+	}
+	return nil
+}
+
+func (s *service) buildResetLink(schemaName, rawToken string) (string, error) {
+	u, err := url.Parse(s.resetURL)
+	if err != nil {
+		return "", fmt.Errorf("URL reset password tidak valid: %w", err)
+	}
+	q := u.Query()
+	q.Set("token", rawToken)
+	if schemaName != "" {
+		q.Set("tenant", schemaName)
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// checkSchema memastikan skema tenant terdaftar. Skema kosong berarti superadmin.
+func (s *service) checkSchema(ctx context.Context, schemaName string) error {
+	if schemaName == "" {
+		return nil
+	}
+	exists, err := s.tenantRepo.CheckSchemaExists(ctx, schemaName)
+	if err != nil {
+		return fmt.Errorf("error saat validasi schema: %w", err)
+	}
+	if !exists {
+		return ErrInvalidTenantID
+	}
+	return nil
+}
+
+// startSession membuat sesi baru beserta refresh token pertamanya.
+func (s *service) startSession(ctx context.Context, schemaName, userID, role string) (*TokenPair, error) {
+	sessionID := uuid.New().String()
+	rawToken, token, err := newRefreshToken(sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, schemaName, token); err != nil {
+		return nil, fmt.Errorf("gagal menyimpan sesi login: %w", err)
+	}
+
+	accessToken, err := s.signAccessToken(schemaName, userID, role, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: rawToken,
+		ExpiresIn:    int64(AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+func (s *service) signAccessToken(schemaName, userID, role, sessionID string) (string, error) {
+	now := time.Now()
 	claims := jwt.MapClaims{
-		"sub":  user.ID,
-		"role": user.Role,
-		"exp":  time.Now().Add(time.Hour * 24).Unix(),
-		"iat":  time.Now().Unix(),
+		"sub":  userID,
+		"role": role,
+		"sid":  sessionID,
+		"exp":  now.Add(AccessTokenTTL).Unix(),
+		"iat":  now.Unix(),
 	}
 
-	if user.Role != "superadmin" {
+	if role != "superadmin" {
 		claims["sch"] = schemaName
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, err := token.SignedString(s.jwtSecret)
 	if err != nil {
-		log.Printf("ERROR saat membuat token JWT: %v", err)
 		return "", fmt.Errorf("gagal membuat token: %w", err)
 	}
-
-	log.Printf("--- PROSES LOGIN BERHASIL ---")
 	return tokenString, nil
 }
+
+// newRefreshToken membuat refresh token acak. Nilai asli dikembalikan ke klien,
+// sedangkan yang disimpan hanya hash-nya.
+func newRefreshToken(sessionID, userID string) (string, *RefreshToken, error) {
+	rawToken, err := randomToken()
+	if err != nil {
+		return "", nil, err
+	}
+	return rawToken, &RefreshToken{
+		ID:        uuid.New().String(),
+		SessionID: sessionID,
+		UserID:    userID,
+		TokenHash: hashToken(rawToken),
+		ExpiresAt: time.Now().Add(RefreshTokenTTL),
+	}, nil
+}
+
+// randomToken membuat string acak 256-bit yang aman dipakai di URL.
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("gagal membuat token acak: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashToken(rawToken string) string {
+	sum := sha256.Sum256([]byte(rawToken))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,119 @@
+// file: backend/internal/deskripsi/handler.go
+package deskripsi
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"skoola/internal/access"
+	"skoola/internal/kuncinilai"
+	"skoola/internal/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{service: s}
+}
+
+// GetPengaturan adalah handler untuk GET /deskripsi-capaian/pengaturan.
+func (h *Handler) GetPengaturan(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	pengaturan, err := h.service.GetPengaturan(r.Context(), schemaName)
+	if err != nil {
+		writeError(w, "Gagal mengambil pengaturan deskripsi: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pengaturan)
+}
+
+// UpdatePengaturan adalah handler untuk PUT /deskripsi-capaian/pengaturan.
+func (h *Handler) UpdatePengaturan(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	var input UpdatePengaturanInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	pengaturan, err := h.service.UpdatePengaturan(r.Context(), schemaName, input)
+	if err != nil {
+		writeError(w, "Gagal menyimpan pengaturan deskripsi: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pengaturan)
+}
+
+// GetByPengajar adalah handler untuk GET /deskripsi-capaian/kelas/{kelasID}/pengajar/{pengajarKelasID}.
+func (h *Handler) GetByPengajar(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	kelasID := chi.URLParam(r, "kelasID")
+	pengajarKelasID := chi.URLParam(r, "pengajarKelasID")
+
+	list, err := h.service.GetByPengajar(r.Context(), schemaName, access.ActorFromContext(r.Context()), kelasID, pengajarKelasID)
+	if err != nil {
+		writeError(w, "Gagal mengambil deskripsi capaian: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// Simpan adalah handler untuk PUT /deskripsi-capaian/anggota/{anggotaKelasID}/pengajar/{pengajarKelasID}.
+func (h *Handler) Simpan(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	anggotaKelasID := chi.URLParam(r, "anggotaKelasID")
+	pengajarKelasID := chi.URLParam(r, "pengajarKelasID")
+
+	var input SimpanDeskripsiInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Simpan(r.Context(), schemaName, access.ActorFromContext(r.Context()), anggotaKelasID, pengajarKelasID, input); err != nil {
+		writeError(w, "Gagal menyimpan deskripsi capaian: ", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Reset adalah handler untuk DELETE /deskripsi-capaian/anggota/{anggotaKelasID}/pengajar/{pengajarKelasID}.
+func (h *Handler) Reset(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	anggotaKelasID := chi.URLParam(r, "anggotaKelasID")
+	pengajarKelasID := chi.URLParam(r, "pengajarKelasID")
+
+	if err := h.service.Reset(r.Context(), schemaName, access.ActorFromContext(r.Context()), anggotaKelasID, pengajarKelasID); err != nil {
+		writeError(w, "Gagal menghapus deskripsi capaian: ", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeError(w http.ResponseWriter, prefix string, err error) {
+	switch {
+	case errors.Is(err, access.ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, ErrValidation):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, kuncinilai.ErrLocked):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Data tidak ditemukan", http.StatusNotFound)
+	default:
+		http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+	}
+}
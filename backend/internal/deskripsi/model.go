@@ -0,0 +1,78 @@
+// file: backend/internal/deskripsi/model.go
+package deskripsi
+
+import "time"
+
+// Placeholder yang dapat dipakai di template deskripsi.
+const (
+	PlaceholderNama  = "{nama}"
+	PlaceholderMapel = "{mapel}"
+	PlaceholderTP    = "{tp}"
+)
+
+// Pengaturan merepresentasikan data dari tabel 'pengaturan_deskripsi'.
+// UpdatedAt nil berarti tenant masih memakai pengaturan bawaan. TemplateCukup kosong
+// berarti generator memakai kalimat "cukup" bawaan.
+type Pengaturan struct {
+	TemplateTinggi string     `json:"template_tinggi"`
+	TemplateRendah string     `json:"template_rendah"`
+	TemplateCukup  string     `json:"template_cukup"`
+	AmbangTinggi   float64    `json:"ambang_tinggi"`
+	AmbangRendah   float64    `json:"ambang_rendah"`
+	UpdatedAt      *time.Time `json:"updated_at"`
+}
+
+// UpdatePengaturanInput adalah DTO untuk mengubah pengaturan generator deskripsi.
+type UpdatePengaturanInput struct {
+	TemplateTinggi string  `json:"template_tinggi" validate:"required,max=500"`
+	TemplateRendah string  `json:"template_rendah" validate:"required,max=500"`
+	TemplateCukup  string  `json:"template_cukup" validate:"max=500"`
+	AmbangTinggi   float64 `json:"ambang_tinggi" validate:"min=0,max=100"`
+	AmbangRendah   float64 `json:"ambang_rendah" validate:"min=0,max=100"`
+}
+
+// CapaianTP adalah nilai formatif satu siswa pada satu tujuan pembelajaran.
+type CapaianTP struct {
+	TujuanPembelajaranID int     `json:"tujuan_pembelajaran_id"`
+	DeskripsiTujuan      string  `json:"deskripsi_tujuan"`
+	Nilai                float64 `json:"nilai"`
+}
+
+// DeskripsiSiswa adalah deskripsi capaian satu siswa pada satu mapel. Deskripsi adalah
+// teks yang dicetak di rapor: tulisan guru jika Manual, selain itu DeskripsiOtomatis.
+type DeskripsiSiswa struct {
+	AnggotaKelasID    string     `json:"anggota_kelas_id"`
+	NamaSiswa         string     `json:"nama_siswa"`
+	NIS               *string    `json:"nis"`
+	TPTertinggi       *CapaianTP `json:"tp_tertinggi"`
+	TPTerendah        *CapaianTP `json:"tp_terendah"`
+	DeskripsiOtomatis string     `json:"deskripsi_otomatis"`
+	Deskripsi         string     `json:"deskripsi"`
+	Manual            bool       `json:"manual"`
+	DiubahPada        *time.Time `json:"diubah_pada"`
+}
+
+// SimpanDeskripsiInput adalah DTO untuk menyimpan deskripsi tulisan guru.
+type SimpanDeskripsiInput struct {
+	Deskripsi string `json:"deskripsi" validate:"required,max=2000"`
+}
+
+// siswaKelas adalah anggota kelas yang dibuatkan deskripsinya.
+type siswaKelas struct {
+	AnggotaKelasID string
+	NamaSiswa      string
+	NIS            *string
+}
+
+// nilaiTP adalah satu nilai formatif yang sudah terisi di kelas.
+type nilaiTP struct {
+	PengajarKelasID string
+	AnggotaKelasID  string
+	Capaian         CapaianTP
+}
+
+// deskripsiManual adalah satu baris dari tabel 'deskripsi_capaian'.
+type deskripsiManual struct {
+	Deskripsi string
+	UpdatedAt time.Time
+}
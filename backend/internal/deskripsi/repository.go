@@ -0,0 +1,310 @@
+// file: backend/internal/deskripsi/repository.go
+package deskripsi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"skoola/pkg/database"
+)
+
+// Repository mendefinisikan interface untuk interaksi database.
+type Repository interface {
+	GetPengaturan(ctx context.Context, schemaName string) (*Pengaturan, error)
+	UpsertPengaturan(ctx context.Context, schemaName string, input UpdatePengaturanInput) error
+	GetPengajar(ctx context.Context, schemaName string, pengajarKelasID string) (kelasID string, namaMapel string, err error)
+	IsAnggotaPengajar(ctx context.Context, schemaName string, anggotaKelasID string, pengajarKelasID string) (bool, error)
+	// GetSiswa mengembalikan anggota kelas yang belum keluar per akhir semester (tanggal
+	// selesai kalender akademik, atau hari ini jika belum diisi).
+	GetSiswa(ctx context.Context, schemaName string, kelasID string) ([]siswaKelas, error)
+	GetMapel(ctx context.Context, schemaName string, kelasID string) (map[string]string, error)
+	GetNilaiTP(ctx context.Context, schemaName string, kelasID string, pengajarKelasID string) ([]nilaiTP, error)
+	GetManual(ctx context.Context, schemaName string, kelasID string, pengajarKelasID string) (map[string]map[string]deskripsiManual, error)
+	SaveManual(ctx context.Context, schemaName string, anggotaKelasID string, pengajarKelasID string, deskripsi string, userID string) error
+	DeleteManual(ctx context.Context, schemaName string, anggotaKelasID string, pengajarKelasID string) error
+}
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewRepository membuat instance baru dari postgresRepository.
+func NewRepository(db *sql.DB) Repository {
+	return &postgresRepository{db: db}
+}
+
+// GetPengaturan mengambil pengaturan generator. Hasilnya nil jika belum pernah diatur.
+func (r *postgresRepository) GetPengaturan(ctx context.Context, schemaName string) (*Pengaturan, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT template_tinggi, template_rendah, template_cukup, ambang_tinggi::float8, ambang_rendah::float8, updated_at
+		FROM pengaturan_deskripsi
+		WHERE id = 1
+	`
+	var p Pengaturan
+	err = tx.QueryRowContext(ctx, query).Scan(&p.TemplateTinggi, &p.TemplateRendah, &p.TemplateCukup, &p.AmbangTinggi, &p.AmbangRendah, &p.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("gagal mengambil pengaturan deskripsi: %w", err)
+	}
+	return &p, tx.Commit()
+}
+
+func (r *postgresRepository) UpsertPengaturan(ctx context.Context, schemaName string, input UpdatePengaturanInput) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO pengaturan_deskripsi (id, template_tinggi, template_rendah, template_cukup, ambang_tinggi, ambang_rendah)
+		VALUES (1, $1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO UPDATE SET
+			template_tinggi = EXCLUDED.template_tinggi,
+			template_rendah = EXCLUDED.template_rendah,
+			template_cukup = EXCLUDED.template_cukup,
+			ambang_tinggi = EXCLUDED.ambang_tinggi,
+			ambang_rendah = EXCLUDED.ambang_rendah,
+			updated_at = NOW()
+	`
+	if _, err := tx.ExecContext(ctx, query, input.TemplateTinggi, input.TemplateRendah, input.TemplateCukup, input.AmbangTinggi, input.AmbangRendah); err != nil {
+		return fmt.Errorf("gagal menyimpan pengaturan deskripsi: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (r *postgresRepository) GetPengajar(ctx context.Context, schemaName string, pengajarKelasID string) (string, string, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return "", "", err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT pk.kelas_id, mp.nama_mapel
+		FROM pengajar_kelas pk
+		JOIN mata_pelajaran mp ON pk.mata_pelajaran_id = mp.id
+		WHERE pk.id = $1
+	`
+	var kelasID, namaMapel string
+	if err := tx.QueryRowContext(ctx, query, pengajarKelasID).Scan(&kelasID, &namaMapel); err != nil {
+		return "", "", err
+	}
+	return kelasID, namaMapel, tx.Commit()
+}
+
+// IsAnggotaPengajar memastikan anggota kelas berada di kelas tempat mapel tersebut diajarkan.
+func (r *postgresRepository) IsAnggotaPengajar(ctx context.Context, schemaName string, anggotaKelasID string, pengajarKelasID string) (bool, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM anggota_kelas ak
+			JOIN pengajar_kelas pk ON pk.kelas_id = ak.kelas_id
+			WHERE ak.id = $1 AND pk.id = $2
+		)
+	`
+	var exists bool
+	if err := tx.QueryRowContext(ctx, query, anggotaKelasID, pengajarKelasID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("gagal memeriksa anggota kelas: %w", err)
+	}
+	return exists, tx.Commit()
+}
+
+func (r *postgresRepository) GetSiswa(ctx context.Context, schemaName string, kelasID string) ([]siswaKelas, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT ak.id, s.nama_lengkap, s.nis
+		FROM anggota_kelas ak
+		JOIN students s ON ak.student_id = s.id
+		JOIN kelas k ON ak.kelas_id = k.id
+		LEFT JOIN kalender_pengaturan kp ON kp.tahun_ajaran_id = k.tahun_ajaran_id
+		WHERE ak.kelas_id = $1 AND (ak.tanggal_keluar IS NULL OR ak.tanggal_keluar > COALESCE(kp.tanggal_selesai, CURRENT_DATE))
+		ORDER BY ak.urutan ASC, s.nama_lengkap ASC
+	`
+	rows, err := tx.QueryContext(ctx, query, kelasID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil siswa kelas: %w", err)
+	}
+	defer rows.Close()
+
+	var list []siswaKelas
+	for rows.Next() {
+		var s siswaKelas
+		if err := rows.Scan(&s.AnggotaKelasID, &s.NamaSiswa, &s.NIS); err != nil {
+			return nil, fmt.Errorf("gagal memindai siswa kelas: %w", err)
+		}
+		list = append(list, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+// GetMapel mengambil nama mapel setiap pengajar kelas di kelas.
+// Hasilnya map[pengajar_kelas_id]nama_mapel.
+func (r *postgresRepository) GetMapel(ctx context.Context, schemaName string, kelasID string) (map[string]string, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT pk.id, mp.nama_mapel
+		FROM pengajar_kelas pk
+		JOIN mata_pelajaran mp ON pk.mata_pelajaran_id = mp.id
+		WHERE pk.kelas_id = $1
+	`
+	rows, err := tx.QueryContext(ctx, query, kelasID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil mapel kelas: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[string]string)
+	for rows.Next() {
+		var id, nama string
+		if err := rows.Scan(&id, &nama); err != nil {
+			return nil, fmt.Errorf("gagal memindai mapel kelas: %w", err)
+		}
+		result[id] = nama
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, tx.Commit()
+}
+
+// GetNilaiTP mengambil nilai formatif yang sudah terisi di kelas, diurutkan sesuai
+// urutan materi dan TP. pengajarKelasID kosong berarti semua mapel di kelas.
+func (r *postgresRepository) GetNilaiTP(ctx context.Context, schemaName string, kelasID string, pengajarKelasID string) ([]nilaiTP, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT m.pengajar_kelas_id, p.anggota_kelas_id, tp.id, tp.deskripsi_tujuan, p.nilai::float8
+		FROM penilaian p
+		JOIN tujuan_pembelajaran tp ON p.tujuan_pembelajaran_id = tp.id
+		JOIN materi_pembelajaran m ON tp.materi_pembelajaran_id = m.id
+		JOIN pengajar_kelas pk ON m.pengajar_kelas_id = pk.id
+		WHERE pk.kelas_id = $1 AND ($2 = '' OR pk.id::text = $2) AND p.nilai IS NOT NULL
+		ORDER BY m.urutan ASC, tp.urutan ASC, tp.id ASC
+	`
+	rows, err := tx.QueryContext(ctx, query, kelasID, pengajarKelasID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil nilai TP: %w", err)
+	}
+	defer rows.Close()
+
+	var list []nilaiTP
+	for rows.Next() {
+		var n nilaiTP
+		if err := rows.Scan(&n.PengajarKelasID, &n.AnggotaKelasID, &n.Capaian.TujuanPembelajaranID, &n.Capaian.DeskripsiTujuan, &n.Capaian.Nilai); err != nil {
+			return nil, fmt.Errorf("gagal memindai nilai TP: %w", err)
+		}
+		list = append(list, n)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+// GetManual mengambil deskripsi tulisan guru di kelas.
+// Hasilnya map[pengajar_kelas_id]map[anggota_kelas_id]deskripsiManual.
+func (r *postgresRepository) GetManual(ctx context.Context, schemaName string, kelasID string, pengajarKelasID string) (map[string]map[string]deskripsiManual, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT dc.pengajar_kelas_id, dc.anggota_kelas_id, dc.deskripsi, dc.updated_at
+		FROM deskripsi_capaian dc
+		JOIN pengajar_kelas pk ON dc.pengajar_kelas_id = pk.id
+		WHERE pk.kelas_id = $1 AND ($2 = '' OR pk.id::text = $2)
+	`
+	rows, err := tx.QueryContext(ctx, query, kelasID, pengajarKelasID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil deskripsi capaian: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[string]map[string]deskripsiManual)
+	for rows.Next() {
+		var pkID, anggotaID string
+		var d deskripsiManual
+		if err := rows.Scan(&pkID, &anggotaID, &d.Deskripsi, &d.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("gagal memindai deskripsi capaian: %w", err)
+		}
+		if result[pkID] == nil {
+			result[pkID] = make(map[string]deskripsiManual)
+		}
+		result[pkID][anggotaID] = d
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, tx.Commit()
+}
+
+func (r *postgresRepository) SaveManual(ctx context.Context, schemaName string, anggotaKelasID string, pengajarKelasID string, deskripsi string, userID string) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO deskripsi_capaian (anggota_kelas_id, pengajar_kelas_id, deskripsi, diubah_oleh)
+		VALUES ($1, $2, $3, NULLIF($4, '')::uuid)
+		ON CONFLICT (anggota_kelas_id, pengajar_kelas_id) DO UPDATE SET
+			deskripsi = EXCLUDED.deskripsi,
+			diubah_oleh = EXCLUDED.diubah_oleh,
+			updated_at = NOW()
+	`
+	if _, err := tx.ExecContext(ctx, query, anggotaKelasID, pengajarKelasID, deskripsi, userID); err != nil {
+		return fmt.Errorf("gagal menyimpan deskripsi capaian: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (r *postgresRepository) DeleteManual(ctx context.Context, schemaName string, anggotaKelasID string, pengajarKelasID string) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM deskripsi_capaian WHERE anggota_kelas_id = $1 AND pengajar_kelas_id = $2`, anggotaKelasID, pengajarKelasID)
+	if err != nil {
+		return fmt.Errorf("gagal menghapus deskripsi capaian: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
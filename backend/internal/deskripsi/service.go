@@ -0,0 +1,352 @@
+// file: backend/internal/deskripsi/service.go
+package deskripsi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"skoola/internal/access"
+	"skoola/internal/audit"
+	"skoola/internal/kuncinilai"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+var ErrValidation = errors.New("validation failed")
+
+// pengaturanBawaan dipakai selama tenant belum mengatur generator deskripsi.
+var pengaturanBawaan = Pengaturan{
+	TemplateTinggi: "Ananda {nama} menunjukkan penguasaan yang baik dalam {tp}.",
+	TemplateRendah: "Ananda {nama} perlu bimbingan dalam {tp}.",
+	TemplateCukup:  templateCukup,
+	AmbangTinggi:   80,
+	AmbangRendah:   70,
+}
+
+// templateCukup dipakai jika tidak ada TP yang mencapai ambang tinggi maupun di bawah
+// ambang rendah, selama tenant belum mengisi template_cukup sendiri.
+const templateCukup = "Ananda {nama} menunjukkan penguasaan yang cukup dalam {tp}."
+
+// Service mendefinisikan logika bisnis deskripsi capaian rapor.
+type Service interface {
+	GetPengaturan(ctx context.Context, schemaName string) (*Pengaturan, error)
+	UpdatePengaturan(ctx context.Context, schemaName string, input UpdatePengaturanInput) (*Pengaturan, error)
+	GetByPengajar(ctx context.Context, schemaName string, actor access.Actor, kelasID string, pengajarKelasID string) ([]DeskripsiSiswa, error)
+	Simpan(ctx context.Context, schemaName string, actor access.Actor, anggotaKelasID string, pengajarKelasID string, input SimpanDeskripsiInput) error
+	Reset(ctx context.Context, schemaName string, actor access.Actor, anggotaKelasID string, pengajarKelasID string) error
+	// DeskripsiKelas menyusun deskripsi semua mapel di kelas tanpa memeriksa akses;
+	// pemanggil (misalnya rapor) wajib memeriksa akses sendiri.
+	// Hasilnya map[pengajar_kelas_id]map[anggota_kelas_id]deskripsi.
+	DeskripsiKelas(ctx context.Context, schemaName string, kelasID string) (map[string]map[string]string, error)
+}
+
+type service struct {
+	repo     Repository
+	kunci    kuncinilai.Service
+	access   access.Service
+	audit    audit.Recorder
+	validate *validator.Validate
+}
+
+// NewService membuat instance baru dari service deskripsi capaian.
+func NewService(repo Repository, kunciService kuncinilai.Service, accessService access.Service, auditLog audit.Recorder, validate *validator.Validate) Service {
+	return &service{repo: repo, kunci: kunciService, access: accessService, audit: auditLog, validate: validate}
+}
+
+func (s *service) GetPengaturan(ctx context.Context, schemaName string) (*Pengaturan, error) {
+	p, err := s.repo.GetPengaturan(ctx, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		bawaan := pengaturanBawaan
+		return &bawaan, nil
+	}
+	return p, nil
+}
+
+func (s *service) UpdatePengaturan(ctx context.Context, schemaName string, input UpdatePengaturanInput) (*Pengaturan, error) {
+	if err := s.validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	if input.AmbangRendah > input.AmbangTinggi {
+		return nil, fmt.Errorf("%w: ambang_rendah tidak boleh lebih besar dari ambang_tinggi", ErrValidation)
+	}
+	input.TemplateCukup = strings.TrimSpace(input.TemplateCukup)
+	if !strings.Contains(input.TemplateTinggi, PlaceholderTP) || !strings.Contains(input.TemplateRendah, PlaceholderTP) ||
+		(input.TemplateCukup != "" && !strings.Contains(input.TemplateCukup, PlaceholderTP)) {
+		return nil, fmt.Errorf("%w: setiap template harus memuat %s", ErrValidation, PlaceholderTP)
+	}
+
+	before, err := s.GetPengaturan(ctx, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpsertPengaturan(ctx, schemaName, input); err != nil {
+		return nil, err
+	}
+	after, err := s.GetPengaturan(ctx, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionUpdate, Entity: "pengaturan_deskripsi", EntityID: "1", Before: before, After: after})
+	return after, nil
+}
+
+func (s *service) GetByPengajar(ctx context.Context, schemaName string, actor access.Actor, kelasID string, pengajarKelasID string) ([]DeskripsiSiswa, error) {
+	if _, err := uuid.Parse(pengajarKelasID); err != nil {
+		return nil, fmt.Errorf("%w: pengajar_kelas_id tidak valid", ErrValidation)
+	}
+	// Pengajar mapel dan wali kelas boleh melihat deskripsi kelas ini.
+	target := access.Target{PengajarKelasIDs: []string{pengajarKelasID}, KelasIDs: []string{kelasID}}
+	if err := s.access.AuthorizeRead(ctx, schemaName, actor, target); err != nil {
+		return nil, err
+	}
+
+	pengajarKelas, namaMapel, err := s.repo.GetPengajar(ctx, schemaName, pengajarKelasID)
+	if err != nil {
+		return nil, err
+	}
+	if pengajarKelas != kelasID {
+		return nil, fmt.Errorf("%w: mapel tidak diajarkan di kelas ini", ErrValidation)
+	}
+
+	pengaturan, err := s.GetPengaturan(ctx, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	siswa, err := s.repo.GetSiswa(ctx, schemaName, kelasID)
+	if err != nil {
+		return nil, err
+	}
+	nilai, err := s.repo.GetNilaiTP(ctx, schemaName, kelasID, pengajarKelasID)
+	if err != nil {
+		return nil, err
+	}
+	manual, err := s.repo.GetManual(ctx, schemaName, kelasID, pengajarKelasID)
+	if err != nil {
+		return nil, err
+	}
+
+	perSiswa := kelompokkanNilai(nilai)[pengajarKelasID]
+	list := make([]DeskripsiSiswa, 0, len(siswa))
+	for _, sw := range siswa {
+		d := DeskripsiSiswa{AnggotaKelasID: sw.AnggotaKelasID, NamaSiswa: sw.NamaSiswa, NIS: sw.NIS}
+		d.TPTertinggi, d.TPTerendah, d.DeskripsiOtomatis = susun(*pengaturan, sw.NamaSiswa, namaMapel, perSiswa[sw.AnggotaKelasID])
+		d.Deskripsi = d.DeskripsiOtomatis
+		if m, ok := manual[pengajarKelasID][sw.AnggotaKelasID]; ok {
+			updatedAt := m.UpdatedAt
+			d.Deskripsi = m.Deskripsi
+			d.Manual = true
+			d.DiubahPada = &updatedAt
+		}
+		list = append(list, d)
+	}
+	return list, nil
+}
+
+// Simpan menyimpan deskripsi tulisan guru yang menggantikan hasil generator.
+func (s *service) Simpan(ctx context.Context, schemaName string, actor access.Actor, anggotaKelasID string, pengajarKelasID string, input SimpanDeskripsiInput) error {
+	input.Deskripsi = strings.TrimSpace(input.Deskripsi)
+	if err := s.validate.Struct(input); err != nil {
+		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	pascaKunci, err := s.authorizeWrite(ctx, schemaName, actor, anggotaKelasID, pengajarKelasID)
+	if err != nil {
+		return err
+	}
+
+	before, err := s.getManual(ctx, schemaName, anggotaKelasID, pengajarKelasID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.SaveManual(ctx, schemaName, anggotaKelasID, pengajarKelasID, input.Deskripsi, actor.UserID); err != nil {
+		return err
+	}
+
+	entityID := anggotaKelasID + ":" + pengajarKelasID
+	entry := audit.Entry{Action: audit.ActionUpdate, Entity: "deskripsi_capaian", EntityID: entityID, Before: before, After: input}
+	if before == nil {
+		entry = audit.Entry{Action: audit.ActionCreate, Entity: "deskripsi_capaian", EntityID: entityID, After: input}
+	}
+	s.audit.Record(ctx, schemaName, entry)
+	s.kunci.RecordPascaKunci(ctx, schemaName, pascaKunci, entry)
+	return nil
+}
+
+// Reset menghapus deskripsi tulisan guru sehingga rapor kembali memakai hasil generator.
+func (s *service) Reset(ctx context.Context, schemaName string, actor access.Actor, anggotaKelasID string, pengajarKelasID string) error {
+	pascaKunci, err := s.authorizeWrite(ctx, schemaName, actor, anggotaKelasID, pengajarKelasID)
+	if err != nil {
+		return err
+	}
+	before, err := s.getManual(ctx, schemaName, anggotaKelasID, pengajarKelasID)
+	if err != nil {
+		return err
+	}
+	if before == nil {
+		return sql.ErrNoRows
+	}
+	if err := s.repo.DeleteManual(ctx, schemaName, anggotaKelasID, pengajarKelasID); err != nil {
+		return err
+	}
+
+	entry := audit.Entry{Action: audit.ActionDelete, Entity: "deskripsi_capaian", EntityID: anggotaKelasID + ":" + pengajarKelasID, Before: before}
+	s.audit.Record(ctx, schemaName, entry)
+	s.kunci.RecordPascaKunci(ctx, schemaName, pascaKunci, entry)
+	return nil
+}
+
+func (s *service) DeskripsiKelas(ctx context.Context, schemaName string, kelasID string) (map[string]map[string]string, error) {
+	pengaturan, err := s.GetPengaturan(ctx, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	siswa, err := s.repo.GetSiswa(ctx, schemaName, kelasID)
+	if err != nil {
+		return nil, err
+	}
+	mapel, err := s.repo.GetMapel(ctx, schemaName, kelasID)
+	if err != nil {
+		return nil, err
+	}
+	nilai, err := s.repo.GetNilaiTP(ctx, schemaName, kelasID, "")
+	if err != nil {
+		return nil, err
+	}
+	manual, err := s.repo.GetManual(ctx, schemaName, kelasID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	perMapel := kelompokkanNilai(nilai)
+	result := make(map[string]map[string]string, len(mapel))
+	for pkID, namaMapel := range mapel {
+		result[pkID] = make(map[string]string, len(siswa))
+		for _, sw := range siswa {
+			if m, ok := manual[pkID][sw.AnggotaKelasID]; ok {
+				result[pkID][sw.AnggotaKelasID] = m.Deskripsi
+				continue
+			}
+			_, _, teks := susun(*pengaturan, sw.NamaSiswa, namaMapel, perMapel[pkID][sw.AnggotaKelasID])
+			result[pkID][sw.AnggotaKelasID] = teks
+		}
+	}
+	return result, nil
+}
+
+// authorizeWrite memakai aturan yang sama dengan pengisian nilai dan menolak perubahan
+// jika nilai mapel sudah dikunci. Hasilnya adalah pengajar kelas yang dibuka kembali
+// setelah finalisasi.
+func (s *service) authorizeWrite(ctx context.Context, schemaName string, actor access.Actor, anggotaKelasID string, pengajarKelasID string) ([]string, error) {
+	if _, err := uuid.Parse(anggotaKelasID); err != nil {
+		return nil, fmt.Errorf("%w: anggota_kelas_id tidak valid", ErrValidation)
+	}
+	if _, err := uuid.Parse(pengajarKelasID); err != nil {
+		return nil, fmt.Errorf("%w: pengajar_kelas_id tidak valid", ErrValidation)
+	}
+	target := access.Target{PengajarKelasIDs: []string{pengajarKelasID}, AnggotaKelasIDs: []string{anggotaKelasID}}
+	if err := s.access.AuthorizeWrite(ctx, schemaName, actor, target); err != nil {
+		return nil, err
+	}
+	ok, err := s.repo.IsAnggotaPengajar(ctx, schemaName, anggotaKelasID, pengajarKelasID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: siswa bukan anggota kelas tempat mapel ini diajarkan", ErrValidation)
+	}
+	return s.kunci.EnsureUnlocked(ctx, schemaName, target)
+}
+
+func (s *service) getManual(ctx context.Context, schemaName string, anggotaKelasID string, pengajarKelasID string) (*SimpanDeskripsiInput, error) {
+	kelasID, _, err := s.repo.GetPengajar(ctx, schemaName, pengajarKelasID)
+	if err != nil {
+		return nil, err
+	}
+	manual, err := s.repo.GetManual(ctx, schemaName, kelasID, pengajarKelasID)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := manual[pengajarKelasID][anggotaKelasID]
+	if !ok {
+		return nil, nil
+	}
+	return &SimpanDeskripsiInput{Deskripsi: m.Deskripsi}, nil
+}
+
+// kelompokkanNilai mengelompokkan nilai TP menjadi map[pengajar_kelas_id]map[anggota_kelas_id][]CapaianTP
+// dengan urutan TP dari repository tetap terjaga.
+func kelompokkanNilai(list []nilaiTP) map[string]map[string][]CapaianTP {
+	result := make(map[string]map[string][]CapaianTP)
+	for _, n := range list {
+		if result[n.PengajarKelasID] == nil {
+			result[n.PengajarKelasID] = make(map[string][]CapaianTP)
+		}
+		result[n.PengajarKelasID][n.AnggotaKelasID] = append(result[n.PengajarKelasID][n.AnggotaKelasID], n.Capaian)
+	}
+	return result
+}
+
+// susun membuat deskripsi capaian dari nilai TP seorang siswa. TP tertinggi disebut
+// dengan template tinggi jika mencapai ambang tinggi, dan TP terendah dengan template
+// rendah jika di bawah ambang rendah (juga jika hanya ada satu TP). Jika keduanya tidak
+// terpenuhi, TP tertinggi disebut dengan template cukup (templateCukup jika kosong). Nilai yang sama diurutkan sesuai
+// urutan TP.
+func susun(p Pengaturan, namaSiswa string, namaMapel string, capaian []CapaianTP) (*CapaianTP, *CapaianTP, string) {
+	if len(capaian) == 0 {
+		return nil, nil, ""
+	}
+	urut := make([]CapaianTP, len(capaian))
+	copy(urut, capaian)
+	sort.SliceStable(urut, func(i, j int) bool { return urut[i].Nilai > urut[j].Nilai })
+
+	var tinggi, rendah *CapaianTP
+	if urut[0].Nilai >= p.AmbangTinggi {
+		tinggi = &urut[0]
+	}
+	if last := urut[len(urut)-1]; last.Nilai < p.AmbangRendah && (len(urut) > 1 || tinggi == nil) {
+		rendah = &urut[len(urut)-1]
+	}
+	if tinggi == nil && rendah == nil {
+		cukup := p.TemplateCukup
+		if cukup == "" {
+			cukup = templateCukup
+		}
+		return nil, nil, isiTemplate(cukup, namaSiswa, namaMapel, urut[0].DeskripsiTujuan)
+	}
+
+	var kalimat []string
+	if tinggi != nil {
+		kalimat = append(kalimat, isiTemplate(p.TemplateTinggi, namaSiswa, namaMapel, tinggi.DeskripsiTujuan))
+	}
+	if rendah != nil {
+		kalimat = append(kalimat, isiTemplate(p.TemplateRendah, namaSiswa, namaMapel, rendah.DeskripsiTujuan))
+	}
+	return tinggi, rendah, strings.Join(kalimat, " ")
+}
+
+func isiTemplate(template string, namaSiswa string, namaMapel string, tujuan string) string {
+	return strings.NewReplacer(
+		PlaceholderNama, namaSiswa,
+		PlaceholderMapel, namaMapel,
+		PlaceholderTP, frasaTujuan(tujuan),
+	).Replace(template)
+}
+
+// frasaTujuan menyesuaikan deskripsi TP agar dapat disisipkan di tengah kalimat:
+// huruf pertama dikecilkan dan titik di akhir dibuang.
+func frasaTujuan(tujuan string) string {
+	tujuan = strings.TrimRight(strings.TrimSpace(tujuan), ".")
+	r, size := utf8.DecodeRuneInString(tujuan)
+	if r == utf8.RuneError {
+		return tujuan
+	}
+	return string(unicode.ToLower(r)) + tujuan[size:]
+}
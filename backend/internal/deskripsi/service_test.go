@@ -0,0 +1,152 @@
+// file: backend/internal/deskripsi/service_test.go
+package deskripsi
+
+import "testing"
+
+func TestSusun(t *testing.T) {
+	tp := func(id int, nilai float64) CapaianTP {
+		deskripsi := map[int]string{
+			1: "Menjelaskan siklus air.",
+			2: "Mengidentifikasi sumber energi",
+			3: "Menyusun laporan pengamatan.",
+		}[id]
+		return CapaianTP{TujuanPembelajaranID: id, DeskripsiTujuan: deskripsi, Nilai: nilai}
+	}
+	const (
+		tinggi1 = "Ananda Sari menunjukkan penguasaan yang baik dalam menjelaskan siklus air."
+		rendah1 = "Ananda Sari perlu bimbingan dalam menjelaskan siklus air."
+		cukup1  = "Ananda Sari menunjukkan penguasaan yang cukup dalam menjelaskan siklus air."
+	)
+
+	tests := []struct {
+		name       string
+		capaian    []CapaianTP
+		wantTinggi int // 0 berarti nil
+		wantRendah int
+		wantTeks   string
+	}{
+		{
+			name:    "tanpa TP",
+			capaian: nil,
+		},
+		{
+			name:       "satu TP tinggi",
+			capaian:    []CapaianTP{tp(1, 90)},
+			wantTinggi: 1,
+			wantTeks:   tinggi1,
+		},
+		{
+			name:       "satu TP tepat di ambang tinggi",
+			capaian:    []CapaianTP{tp(1, 80)},
+			wantTinggi: 1,
+			wantTeks:   tinggi1,
+		},
+		{
+			name:       "satu TP rendah memakai template rendah",
+			capaian:    []CapaianTP{tp(1, 60)},
+			wantRendah: 1,
+			wantTeks:   rendah1,
+		},
+		{
+			name:     "satu TP di antara kedua ambang",
+			capaian:  []CapaianTP{tp(1, 75)},
+			wantTeks: cukup1,
+		},
+		{
+			name:     "satu TP tepat di ambang rendah",
+			capaian:  []CapaianTP{tp(1, 70)},
+			wantTeks: cukup1,
+		},
+		{
+			name:       "TP tertinggi dan terendah",
+			capaian:    []CapaianTP{tp(2, 65), tp(1, 88), tp(3, 75)},
+			wantTinggi: 1,
+			wantRendah: 2,
+			wantTeks:   tinggi1 + " Ananda Sari perlu bimbingan dalam mengidentifikasi sumber energi.",
+		},
+		{
+			name:       "semua TP tinggi hanya menyebut yang tertinggi",
+			capaian:    []CapaianTP{tp(2, 85), tp(1, 95)},
+			wantTinggi: 1,
+			wantTeks:   tinggi1,
+		},
+		{
+			name:       "semua TP rendah hanya menyebut yang terendah",
+			capaian:    []CapaianTP{tp(1, 50), tp(2, 65)},
+			wantRendah: 1,
+			wantTeks:   rendah1,
+		},
+		{
+			name:       "nilai tertinggi sama memilih TP pertama",
+			capaian:    []CapaianTP{tp(1, 90), tp(2, 90), tp(3, 60)},
+			wantTinggi: 1,
+			wantRendah: 3,
+			wantTeks:   tinggi1 + " Ananda Sari perlu bimbingan dalam menyusun laporan pengamatan.",
+		},
+		{
+			name:       "nilai terendah sama memilih TP terakhir",
+			capaian:    []CapaianTP{tp(1, 90), tp(2, 60), tp(3, 60)},
+			wantTinggi: 1,
+			wantRendah: 3,
+			wantTeks:   tinggi1 + " Ananda Sari perlu bimbingan dalam menyusun laporan pengamatan.",
+		},
+		{
+			name:     "semua TP di antara kedua ambang",
+			capaian:  []CapaianTP{tp(2, 72), tp(1, 78)},
+			wantTeks: cukup1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tinggi, rendah, teks := susun(pengaturanBawaan, "Sari", "IPA", tt.capaian)
+			if id := idTP(tinggi); id != tt.wantTinggi {
+				t.Errorf("tinggi = TP %d, ingin TP %d", id, tt.wantTinggi)
+			}
+			if id := idTP(rendah); id != tt.wantRendah {
+				t.Errorf("rendah = TP %d, ingin TP %d", id, tt.wantRendah)
+			}
+			if teks != tt.wantTeks {
+				t.Errorf("deskripsi = %q, ingin %q", teks, tt.wantTeks)
+			}
+		})
+	}
+}
+
+func TestSusunTemplateCukup(t *testing.T) {
+	capaian := []CapaianTP{{TujuanPembelajaranID: 1, DeskripsiTujuan: "Menjelaskan siklus air.", Nilai: 75}}
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{
+			name:     "template dari pengaturan",
+			template: "{nama} cukup menguasai {mapel}, terutama {tp}.",
+			want:     "Sari cukup menguasai IPA, terutama menjelaskan siklus air.",
+		},
+		{
+			name: "kolom kosong memakai kalimat bawaan",
+			want: "Ananda Sari menunjukkan penguasaan yang cukup dalam menjelaskan siklus air.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := pengaturanBawaan
+			p.TemplateCukup = tt.template
+			_, _, teks := susun(p, "Sari", "IPA", capaian)
+			if teks != tt.want {
+				t.Errorf("deskripsi = %q, ingin %q", teks, tt.want)
+			}
+		})
+	}
+}
+
+func idTP(c *CapaianTP) int {
+	if c == nil {
+		return 0
+	}
+	return c.TujuanPembelajaranID
+}
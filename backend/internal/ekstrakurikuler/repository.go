@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"skoola/pkg/database"
 	"strings"
 )
 
@@ -33,30 +34,29 @@ func NewRepository(db *sql.DB) Repository {
 	return &postgresRepository{db: db}
 }
 
-func (r *postgresRepository) setSchema(ctx context.Context, schemaName string) error {
-	_, err := r.db.ExecContext(ctx, fmt.Sprintf("SET search_path TO %q", schemaName))
-	return err
-}
-
 // --- Implementasi Master Ekstrakurikuler ---
 func (r *postgresRepository) Create(ctx context.Context, schemaName string, input UpsertEkstrakurikulerInput) (*Ekstrakurikuler, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 	query := `INSERT INTO ekstrakurikuler (nama_kegiatan, deskripsi) VALUES ($1, $2) RETURNING id, nama_kegiatan, deskripsi, created_at, updated_at`
-	row := r.db.QueryRowContext(ctx, query, input.NamaKegiatan, input.Deskripsi)
+	row := tx.QueryRowContext(ctx, query, input.NamaKegiatan, input.Deskripsi)
 	var ekskul Ekstrakurikuler
 	if err := row.Scan(&ekskul.ID, &ekskul.NamaKegiatan, &ekskul.Deskripsi, &ekskul.CreatedAt, &ekskul.UpdatedAt); err != nil {
 		return nil, err
 	}
-	return &ekskul, nil
+	return &ekskul, tx.Commit()
 }
 
 // FIX: Implementasi GetAll dengan JOIN
 func (r *postgresRepository) GetAll(ctx context.Context, schemaName string, tahunAjaranID string) ([]Ekstrakurikuler, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `
 		SELECT 
@@ -68,7 +68,7 @@ func (r *postgresRepository) GetAll(ctx context.Context, schemaName string, tahu
 		LEFT JOIN teachers t ON es.pembina_id = t.id
 		ORDER BY e.nama_kegiatan ASC
 	`
-	rows, err := r.db.QueryContext(ctx, query, tahunAjaranID)
+	rows, err := tx.QueryContext(ctx, query, tahunAjaranID)
 	if err != nil {
 		return nil, err
 	}
@@ -99,15 +99,17 @@ func (r *postgresRepository) GetAll(ctx context.Context, schemaName string, tahu
 
 		list = append(list, e)
 	}
-	return list, nil
+	return list, tx.Commit()
 }
 
 func (r *postgresRepository) Update(ctx context.Context, schemaName string, id int, input UpsertEkstrakurikulerInput) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 	query := `UPDATE ekstrakurikuler SET nama_kegiatan = $1, deskripsi = $2, updated_at = NOW() WHERE id = $3`
-	result, err := r.db.ExecContext(ctx, query, input.NamaKegiatan, input.Deskripsi, id)
+	result, err := tx.ExecContext(ctx, query, input.NamaKegiatan, input.Deskripsi, id)
 	if err != nil {
 		return err
 	}
@@ -115,14 +117,16 @@ func (r *postgresRepository) Update(ctx context.Context, schemaName string, id i
 	if rowsAffected == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+	return tx.Commit()
 }
 func (r *postgresRepository) Delete(ctx context.Context, schemaName string, id int) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 	query := `DELETE FROM ekstrakurikuler WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := tx.ExecContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -130,14 +134,16 @@ func (r *postgresRepository) Delete(ctx context.Context, schemaName string, id i
 	if rowsAffected == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+	return tx.Commit()
 }
 
 // --- Implementasi Sesi (Tetap sama, sudah benar dari sebelumnya) ---
 func (r *postgresRepository) GetOrCreateSesi(ctx context.Context, schemaName string, ekskulID int, tahunAjaranID string) (*EkstrakurikulerSesi, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `
 		SELECT 
@@ -148,9 +154,9 @@ func (r *postgresRepository) GetOrCreateSesi(ctx context.Context, schemaName str
 		LEFT JOIN teachers t ON es.pembina_id = t.id
 		WHERE es.ekstrakurikuler_id = $1 AND es.tahun_ajaran_id = $2
 	`
-	row := r.db.QueryRowContext(ctx, query, ekskulID, tahunAjaranID)
+	row := tx.QueryRowContext(ctx, query, ekskulID, tahunAjaranID)
 	var sesi EkstrakurikulerSesi
-	err := row.Scan(&sesi.ID, &sesi.EkstrakurikulerID, &sesi.TahunAjaranID, &sesi.PembinaID, &sesi.NamaPembina, &sesi.JumlahAnggota)
+	err = row.Scan(&sesi.ID, &sesi.EkstrakurikulerID, &sesi.TahunAjaranID, &sesi.PembinaID, &sesi.NamaPembina, &sesi.JumlahAnggota)
 
 	if err == sql.ErrNoRows {
 		insertQuery := `
@@ -158,26 +164,28 @@ func (r *postgresRepository) GetOrCreateSesi(ctx context.Context, schemaName str
 			VALUES ($1, $2)
 			RETURNING id, ekstrakurikuler_id, tahun_ajaran_id, pembina_id
 		`
-		insertRow := r.db.QueryRowContext(ctx, insertQuery, ekskulID, tahunAjaranID)
+		insertRow := tx.QueryRowContext(ctx, insertQuery, ekskulID, tahunAjaranID)
 		if err := insertRow.Scan(&sesi.ID, &sesi.EkstrakurikulerID, &sesi.TahunAjaranID, &sesi.PembinaID); err != nil {
 			return nil, err
 		}
 		sesi.JumlahAnggota = 0
-		return &sesi, nil
+		return &sesi, tx.Commit()
 	} else if err != nil {
 		return nil, err
 	}
 
-	return &sesi, nil
+	return &sesi, tx.Commit()
 }
 
 func (r *postgresRepository) UpdateSesiDetail(ctx context.Context, schemaName string, sesiID int, input UpdateSesiDetailInput) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
 	query := `UPDATE ekstrakurikuler_sesi SET pembina_id = $1, updated_at = NOW() WHERE id = $2`
-	result, err := r.db.ExecContext(ctx, query, input.PembinaID, sesiID)
+	result, err := tx.ExecContext(ctx, query, input.PembinaID, sesiID)
 	if err != nil {
 		return err
 	}
@@ -186,14 +194,16 @@ func (r *postgresRepository) UpdateSesiDetail(ctx context.Context, schemaName st
 	if rowsAffected == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+	return tx.Commit()
 }
 
 // --- Implementasi Anggota (Tetap sama) ---
 func (r *postgresRepository) GetAnggotaBySesiID(ctx context.Context, schemaName string, sesiID int) ([]EkstrakurikulerAnggota, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `
         SELECT 
@@ -204,7 +214,7 @@ func (r *postgresRepository) GetAnggotaBySesiID(ctx context.Context, schemaName
         WHERE ea.sesi_id = $1
         ORDER BY s.nama_lengkap
     `
-	rows, err := r.db.QueryContext(ctx, query, sesiID)
+	rows, err := tx.QueryContext(ctx, query, sesiID)
 	if err != nil {
 		return nil, err
 	}
@@ -218,13 +228,15 @@ func (r *postgresRepository) GetAnggotaBySesiID(ctx context.Context, schemaName
 		}
 		anggota = append(anggota, a)
 	}
-	return anggota, nil
+	return anggota, tx.Commit()
 }
 
 func (r *postgresRepository) AddAnggota(ctx context.Context, schemaName string, sesiID int, studentIDs []string) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
 	// Menggunakan ON CONFLICT DO NOTHING untuk menghindari error jika siswa sudah ada
 	var valueStrings []string
@@ -242,17 +254,22 @@ func (r *postgresRepository) AddAnggota(ctx context.Context, schemaName string,
         ON CONFLICT (sesi_id, student_id) DO NOTHING
     `, strings.Join(valueStrings, ","))
 
-	_, err := r.db.ExecContext(ctx, query, valueArgs...)
-	return err
+	_, err = tx.ExecContext(ctx, query, valueArgs...)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (r *postgresRepository) RemoveAnggota(ctx context.Context, schemaName string, anggotaID int) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
 	query := `DELETE FROM ekstrakurikuler_anggota WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, anggotaID)
+	result, err := tx.ExecContext(ctx, query, anggotaID)
 	if err != nil {
 		return err
 	}
@@ -261,5 +278,5 @@ func (r *postgresRepository) RemoveAnggota(ctx context.Context, schemaName strin
 	if rowsAffected == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+	return tx.Commit()
 }
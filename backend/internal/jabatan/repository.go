@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"skoola/pkg/database"
 )
 
 // Repository mendefinisikan interface untuk interaksi database.
@@ -26,29 +27,31 @@ func NewRepository(db *sql.DB) Repository {
 }
 
 func (r *postgresRepository) Create(ctx context.Context, schemaName string, input UpsertJabatanInput) (*Jabatan, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `INSERT INTO jabatan (nama_jabatan) VALUES ($1) RETURNING id, nama_jabatan, created_at, updated_at`
-	row := r.db.QueryRowContext(ctx, query, input.NamaJabatan)
+	row := tx.QueryRowContext(ctx, query, input.NamaJabatan)
 
 	var j Jabatan
 	if err := row.Scan(&j.ID, &j.NamaJabatan, &j.CreatedAt, &j.UpdatedAt); err != nil {
 		return nil, fmt.Errorf("gagal memindai data jabatan setelah dibuat: %w", err)
 	}
-	return &j, nil
+	return &j, tx.Commit()
 }
 
 func (r *postgresRepository) GetAll(ctx context.Context, schemaName string) ([]Jabatan, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `SELECT id, nama_jabatan, created_at, updated_at FROM jabatan ORDER BY nama_jabatan ASC`
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := tx.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("gagal query get all jabatan: %w", err)
 	}
@@ -62,37 +65,42 @@ func (r *postgresRepository) GetAll(ctx context.Context, schemaName string) ([]J
 		}
 		jabatanList = append(jabatanList, j)
 	}
-	return jabatanList, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return jabatanList, tx.Commit()
 }
 
 func (r *postgresRepository) GetByID(ctx context.Context, schemaName string, id int) (*Jabatan, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `SELECT id, nama_jabatan, created_at, updated_at FROM jabatan WHERE id = $1`
-	row := r.db.QueryRowContext(ctx, query, id)
+	row := tx.QueryRowContext(ctx, query, id)
 
 	var j Jabatan
-	err := row.Scan(&j.ID, &j.NamaJabatan, &j.CreatedAt, &j.UpdatedAt)
+	err = row.Scan(&j.ID, &j.NamaJabatan, &j.CreatedAt, &j.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // Data tidak ditemukan, bukan error
 		}
 		return nil, fmt.Errorf("gagal memindai data jabatan by id: %w", err)
 	}
-	return &j, nil
+	return &j, tx.Commit()
 }
 
 func (r *postgresRepository) Update(ctx context.Context, schemaName string, id int, input UpsertJabatanInput) error {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 
 	query := `UPDATE jabatan SET nama_jabatan = $1, updated_at = NOW() WHERE id = $2`
-	result, err := r.db.ExecContext(ctx, query, input.NamaJabatan, id)
+	result, err := tx.ExecContext(ctx, query, input.NamaJabatan, id)
 	if err != nil {
 		return fmt.Errorf("gagal mengeksekusi query update: %w", err)
 	}
@@ -104,17 +112,18 @@ func (r *postgresRepository) Update(ctx context.Context, schemaName string, id i
 	if rowsAffected == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+	return tx.Commit()
 }
 
 func (r *postgresRepository) Delete(ctx context.Context, schemaName string, id int) error {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 
 	query := `DELETE FROM jabatan WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := tx.ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("gagal mengeksekusi query delete: %w", err)
 	}
@@ -126,5 +135,5 @@ func (r *postgresRepository) Delete(ctx context.Context, schemaName string, id i
 	if rowsAffected == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+	return tx.Commit()
 }
@@ -0,0 +1,243 @@
+// file: backend/internal/jadwal/handler.go
+package jadwal
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"skoola/internal/middleware"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{service: s}
+}
+
+// GetPengaturan adalah handler untuk GET /jadwal/tahun-ajaran/{tahunAjaranID}/pengaturan.
+func (h *Handler) GetPengaturan(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	pengaturan, err := h.service.GetPengaturan(r.Context(), schemaName, chi.URLParam(r, "tahunAjaranID"))
+	if err != nil {
+		writeError(w, "Gagal mengambil pengaturan jadwal: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pengaturan)
+}
+
+// SimpanJam adalah handler untuk PUT /jadwal/tahun-ajaran/{tahunAjaranID}/jam.
+func (h *Handler) SimpanJam(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	var input SimpanJamInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	jam, err := h.service.SimpanJam(r.Context(), schemaName, chi.URLParam(r, "tahunAjaranID"), input)
+	if err != nil {
+		writeError(w, "Gagal menyimpan jam pelajaran: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(jam)
+}
+
+// SimpanBeban adalah handler untuk PUT /jadwal/tahun-ajaran/{tahunAjaranID}/beban.
+func (h *Handler) SimpanBeban(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	var input SimpanBebanInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	beban, err := h.service.SimpanBeban(r.Context(), schemaName, chi.URLParam(r, "tahunAjaranID"), input)
+	if err != nil {
+		writeError(w, "Gagal menyimpan beban mapel: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(beban)
+}
+
+// CreateGuruTidakTersedia adalah handler untuk POST /jadwal/tahun-ajaran/{tahunAjaranID}/guru-tidak-tersedia.
+func (h *Handler) CreateGuruTidakTersedia(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	var input GuruTidakTersediaInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	g, err := h.service.CreateGuruTidakTersedia(r.Context(), schemaName, chi.URLParam(r, "tahunAjaranID"), input)
+	if err != nil {
+		writeError(w, "Gagal menyimpan ketidaktersediaan guru: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(g)
+}
+
+// DeleteGuruTidakTersedia adalah handler untuk DELETE /jadwal/tahun-ajaran/{tahunAjaranID}/guru-tidak-tersedia/{id}.
+func (h *Handler) DeleteGuruTidakTersedia(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "ID tidak valid", http.StatusBadRequest)
+		return
+	}
+	if err := h.service.DeleteGuruTidakTersedia(r.Context(), schemaName, chi.URLParam(r, "tahunAjaranID"), id); err != nil {
+		writeError(w, "Gagal menghapus ketidaktersediaan guru: ", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Generate adalah handler untuk POST /jadwal/tahun-ajaran/{tahunAjaranID}/generate.
+func (h *Handler) Generate(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	var input GenerateInput
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+			return
+		}
+	}
+
+	hasil, err := h.service.Generate(r.Context(), schemaName, chi.URLParam(r, "tahunAjaranID"), input)
+	if err != nil {
+		writeError(w, "Gagal menyusun jadwal: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hasil)
+}
+
+// GetJadwalKelas adalah handler untuk GET /jadwal/kelas/{kelasID}. Jadwal dikirim sebagai
+// file jika ?format=pdf diisi.
+func (h *Handler) GetJadwalKelas(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	jadwal, err := h.service.GetJadwalKelas(r.Context(), schemaName, chi.URLParam(r, "kelasID"))
+	h.writeMingguan(w, r, schemaName, jadwal, err)
+}
+
+// GetJadwalGuru adalah handler untuk GET /jadwal/guru/{teacherID}?tahun_ajaran_id=...
+// Jadwal dikirim sebagai file jika ?format=pdf diisi.
+func (h *Handler) GetJadwalGuru(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	jadwal, err := h.service.GetJadwalGuru(r.Context(), schemaName, chi.URLParam(r, "teacherID"), r.URL.Query().Get("tahun_ajaran_id"))
+	h.writeMingguan(w, r, schemaName, jadwal, err)
+}
+
+func (h *Handler) writeMingguan(w http.ResponseWriter, r *http.Request, schemaName string, jadwal *JadwalMingguan, err error) {
+	if err != nil {
+		writeError(w, "Gagal mengambil jadwal pelajaran: ", err)
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(jadwal)
+		return
+	}
+	if format != FormatPDF {
+		http.Error(w, "format harus 'pdf'", http.StatusBadRequest)
+		return
+	}
+
+	content, filename, err := h.service.ExportPDF(r.Context(), schemaName, jadwal, r.URL.Query().Get("paper_size_id"))
+	if err != nil {
+		writeError(w, "Gagal mengekspor jadwal pelajaran: ", err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	w.Write(content)
+}
+
+// Create adalah handler untuk POST /jadwal.
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	var input UpsertJadwalInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	jadwal, err := h.service.Create(r.Context(), schemaName, input)
+	if err != nil {
+		writeError(w, "Gagal menyimpan jadwal pelajaran: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(jadwal)
+}
+
+// Update adalah handler untuk PUT /jadwal/{id}.
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	var input UpsertJadwalInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	jadwal, err := h.service.Update(r.Context(), schemaName, chi.URLParam(r, "id"), input)
+	if err != nil {
+		writeError(w, "Gagal memperbarui jadwal pelajaran: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(jadwal)
+}
+
+// Delete adalah handler untuk DELETE /jadwal/{id}.
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	if err := h.service.Delete(r.Context(), schemaName, chi.URLParam(r, "id")); err != nil {
+		writeError(w, "Gagal menghapus jadwal pelajaran: ", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeError(w http.ResponseWriter, prefix string, err error) {
+	switch {
+	case errors.Is(err, ErrValidation):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Data tidak ditemukan", http.StatusNotFound)
+	default:
+		http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,181 @@
+// file: backend/internal/jadwal/model.go
+package jadwal
+
+// Cakupan tampilan jadwal mingguan.
+const (
+	CakupanKelas = "KELAS"
+	CakupanGuru  = "GURU"
+)
+
+// FormatPDF adalah format ekspor jadwal.
+const FormatPDF = "pdf"
+
+// namaHari memetakan nomor hari (1 = Senin) ke namanya.
+var namaHari = [...]string{"", "Senin", "Selasa", "Rabu", "Kamis", "Jumat", "Sabtu", "Minggu"}
+
+// JamPelajaran adalah definisi satu jam pelajaran pada satu hari. Jam istirahat tidak
+// diisi pelajaran.
+type JamPelajaran struct {
+	ID            int    `json:"id"`
+	TahunAjaranID string `json:"tahun_ajaran_id"`
+	Hari          int    `json:"hari"`
+	NamaHari      string `json:"nama_hari"`
+	JamKe         int    `json:"jam_ke"`
+	JamMulai      string `json:"jam_mulai"`   // HH:MM
+	JamSelesai    string `json:"jam_selesai"` // HH:MM
+	Istirahat     bool   `json:"istirahat"`
+}
+
+// JamPelajaranInput adalah satu item SimpanJamInput.
+type JamPelajaranInput struct {
+	Hari       int    `json:"hari" validate:"required,min=1,max=7"`
+	JamKe      int    `json:"jam_ke" validate:"required,min=1,max=20"`
+	JamMulai   string `json:"jam_mulai" validate:"required,datetime=15:04"`
+	JamSelesai string `json:"jam_selesai" validate:"required,datetime=15:04"`
+	Istirahat  bool   `json:"istirahat"`
+}
+
+// SimpanJamInput menggantikan seluruh definisi jam pelajaran satu tahun ajaran.
+type SimpanJamInput struct {
+	Jam []JamPelajaranInput `json:"jam" validate:"required,dive"`
+}
+
+// BebanMapel adalah jumlah jam per minggu satu mapel di satu tingkatan.
+type BebanMapel struct {
+	ID              int    `json:"id"`
+	TahunAjaranID   string `json:"tahun_ajaran_id"`
+	TingkatanID     int    `json:"tingkatan_id"`
+	NamaTingkatan   string `json:"nama_tingkatan"`
+	MataPelajaranID string `json:"mata_pelajaran_id"`
+	NamaMapel       string `json:"nama_mapel"`
+	JamPerMinggu    int    `json:"jam_per_minggu"`
+	MaksJamPerHari  *int   `json:"maks_jam_per_hari"`
+}
+
+// BebanMapelInput adalah satu item SimpanBebanInput. MaksJamPerHari kosong berarti tanpa batas.
+type BebanMapelInput struct {
+	TingkatanID     int    `json:"tingkatan_id" validate:"required"`
+	MataPelajaranID string `json:"mata_pelajaran_id" validate:"required,uuid"`
+	JamPerMinggu    int    `json:"jam_per_minggu" validate:"required,min=1,max=60"`
+	MaksJamPerHari  *int   `json:"maks_jam_per_hari" validate:"omitempty,min=1,max=20"`
+}
+
+// SimpanBebanInput menggantikan seluruh beban mapel satu tahun ajaran.
+type SimpanBebanInput struct {
+	Beban []BebanMapelInput `json:"beban" validate:"dive"`
+}
+
+// GuruTidakTersedia adalah waktu ketika seorang guru tidak dapat mengajar. JamKe nil
+// berarti sepanjang hari.
+type GuruTidakTersedia struct {
+	ID            int     `json:"id"`
+	TahunAjaranID string  `json:"tahun_ajaran_id"`
+	TeacherID     string  `json:"teacher_id"`
+	NamaGuru      string  `json:"nama_guru"`
+	Hari          int     `json:"hari"`
+	NamaHari      string  `json:"nama_hari"`
+	JamKe         *int    `json:"jam_ke"`
+	Keterangan    *string `json:"keterangan"`
+}
+
+// GuruTidakTersediaInput adalah DTO untuk mencatat ketidaktersediaan guru.
+type GuruTidakTersediaInput struct {
+	TeacherID  string `json:"teacher_id" validate:"required,uuid"`
+	Hari       int    `json:"hari" validate:"required,min=1,max=7"`
+	JamKe      *int   `json:"jam_ke" validate:"omitempty,min=1,max=20"`
+	Keterangan string `json:"keterangan"`
+}
+
+// Pengaturan adalah seluruh masukan penyusunan jadwal satu tahun ajaran.
+type Pengaturan struct {
+	Jam               []JamPelajaran      `json:"jam"`
+	Beban             []BebanMapel        `json:"beban"`
+	GuruTidakTersedia []GuruTidakTersedia `json:"guru_tidak_tersedia"`
+}
+
+// JadwalPelajaran adalah satu jam pelajaran terjadwal. Baris terkunci dipertahankan saat
+// jadwal disusun ulang.
+type JadwalPelajaran struct {
+	ID              string `json:"id"`
+	PengajarKelasID string `json:"pengajar_kelas_id"`
+	KelasID         string `json:"kelas_id"`
+	NamaKelas       string `json:"nama_kelas"`
+	TeacherID       string `json:"teacher_id"`
+	NamaGuru        string `json:"nama_guru"`
+	MataPelajaranID string `json:"mata_pelajaran_id"`
+	NamaMapel       string `json:"nama_mapel"`
+	Hari            int    `json:"hari"`
+	NamaHari        string `json:"nama_hari"`
+	JamKe           int    `json:"jam_ke"`
+	JamMulai        string `json:"jam_mulai"`
+	JamSelesai      string `json:"jam_selesai"`
+	Terkunci        bool   `json:"terkunci"`
+}
+
+// UpsertJadwalInput adalah DTO untuk menempatkan atau memindahkan satu jam pelajaran secara
+// manual. Terkunci bawaannya true agar hasil edit manual tidak tertimpa penyusunan otomatis.
+type UpsertJadwalInput struct {
+	PengajarKelasID string `json:"pengajar_kelas_id" validate:"required,uuid"`
+	Hari            int    `json:"hari" validate:"required,min=1,max=7"`
+	JamKe           int    `json:"jam_ke" validate:"required,min=1,max=20"`
+	Terkunci        *bool  `json:"terkunci"`
+}
+
+// JadwalMingguan adalah jadwal satu kelas atau satu guru dalam satu tahun ajaran.
+type JadwalMingguan struct {
+	Cakupan       string            `json:"cakupan"`
+	ID            string            `json:"id"`
+	Nama          string            `json:"nama"`
+	TahunAjaranID string            `json:"tahun_ajaran_id"`
+	TahunAjaran   string            `json:"tahun_ajaran"`
+	Jam           []JamPelajaran    `json:"jam"`
+	Jadwal        []JadwalPelajaran `json:"jadwal"`
+}
+
+// GenerateInput mengatur penyusunan jadwal otomatis. Selama DryRun hasilnya hanya
+// dikembalikan sebagai pratinjau tanpa disimpan.
+type GenerateInput struct {
+	DryRun bool `json:"dry_run"`
+}
+
+// HasilGenerate adalah ringkasan penyusunan jadwal otomatis.
+type HasilGenerate struct {
+	DryRun        bool              `json:"dry_run"`
+	Lengkap       bool              `json:"lengkap"`
+	Ditempatkan   int               `json:"ditempatkan"`
+	Dipertahankan int               `json:"dipertahankan"`
+	Kekurangan    []Kekurangan      `json:"kekurangan"`
+	Pesan         []string          `json:"pesan,omitempty"`
+	Jadwal        []JadwalPelajaran `json:"jadwal"`
+}
+
+// Kekurangan adalah pengajar kelas yang jam mingguannya tidak dapat dijadwalkan seluruhnya.
+type Kekurangan struct {
+	PengajarKelasID string `json:"pengajar_kelas_id"`
+	NamaKelas       string `json:"nama_kelas"`
+	NamaMapel       string `json:"nama_mapel"`
+	NamaGuru        string `json:"nama_guru"`
+	JamPerMinggu    int    `json:"jam_per_minggu"`
+	Terjadwal       int    `json:"terjadwal"`
+}
+
+// pengajarBeban adalah satu pengajar_kelas beserta beban mapelnya di tingkatan kelas tersebut.
+// JamPerMinggu nil berarti beban mapel belum diatur.
+type pengajarBeban struct {
+	PengajarKelasID string
+	TahunAjaranID   string
+	KelasID         string
+	NamaKelas       string
+	TeacherID       string
+	NamaGuru        string
+	MataPelajaranID string
+	NamaMapel       string
+	JamPerMinggu    *int
+	MaksJamPerHari  *int
+}
+
+// kelasInfo adalah nama dan tahun ajaran satu kelas.
+type kelasInfo struct {
+	Nama          string
+	TahunAjaranID string
+}
@@ -0,0 +1,207 @@
+// file: backend/internal/jadwal/pdf.go
+package jadwal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"skoola/internal/papersize"
+	"skoola/internal/profile"
+	"sort"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+const (
+	lebarKolomJam = 14.0
+	tinggiSel     = 12.0
+)
+
+// toMM mengubah ukuran dari satuan paper_size ke milimeter.
+func toMM(v float64, satuan string) float64 {
+	switch satuan {
+	case "cm":
+		return v * 10
+	case "in":
+		return v * 25.4
+	default:
+		return v
+	}
+}
+
+// newPDF membuat dokumen lanskap sesuai ukuran kertas. Nil berarti A4 dengan margin 15 mm.
+func newPDF(paper *papersize.PaperSize) *gofpdf.Fpdf {
+	width, height := 210.0, 297.0
+	top, bottom, left, right := 15.0, 15.0, 15.0, 15.0
+	if paper != nil {
+		width, height = toMM(paper.Lebar, paper.Satuan), toMM(paper.Panjang, paper.Satuan)
+		top, bottom = toMM(paper.MarginAtas, paper.Satuan), toMM(paper.MarginBawah, paper.Satuan)
+		left, right = toMM(paper.MarginKiri, paper.Satuan), toMM(paper.MarginKanan, paper.Satuan)
+	}
+	if width < height {
+		width, height = height, width
+	}
+
+	pdf := gofpdf.NewCustom(&gofpdf.InitType{
+		OrientationStr: "L",
+		UnitStr:        "mm",
+		Size:           gofpdf.SizeType{Wd: width, Ht: height},
+	})
+	pdf.SetMargins(left, top, right)
+	pdf.SetAutoPageBreak(false, bottom)
+	return pdf
+}
+
+// renderPDF mencetak jadwal mingguan sebagai tabel: hari sebagai kolom dan jam ke sebagai
+// baris. Sel jadwal kelas berisi mapel dan guru, sel jadwal guru berisi mapel dan kelas.
+func renderPDF(paper *papersize.PaperSize, sekolah *profile.ProfilSekolah, j *JadwalMingguan) ([]byte, error) {
+	pdf := newPDF(paper)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+	left, _, right, bottom := pdf.GetMargins()
+	pageWidth, pageHeight := pdf.GetPageSize()
+	contentWidth := pageWidth - left - right
+
+	// 1. Kop sekolah dan judul
+	pdf.SetFont("Helvetica", "B", 13)
+	pdf.CellFormat(contentWidth, 6, tr(strings.ToUpper(sekolah.NamaSekolah)), "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	if sekolah.Alamat != nil && *sekolah.Alamat != "" {
+		pdf.MultiCell(contentWidth, 4.5, tr(*sekolah.Alamat), "", "C", false)
+	}
+	y := pdf.GetY() + 1
+	pdf.SetLineWidth(0.6)
+	pdf.Line(left, y, left+contentWidth, y)
+	pdf.SetLineWidth(0.2)
+	pdf.Ln(4)
+
+	judul := "JADWAL PELAJARAN KELAS " + strings.ToUpper(j.Nama)
+	if j.Cakupan == CakupanGuru {
+		judul = "JADWAL MENGAJAR " + strings.ToUpper(j.Nama)
+	}
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(contentWidth, 6, tr(judul), "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.CellFormat(contentWidth, 4.5, tr("Tahun Ajaran "+j.TahunAjaran), "", 1, "C", false, 0, "")
+	pdf.Ln(3)
+
+	// 2. Susun grid hari x jam ke
+	jam := map[slot]JamPelajaran{}
+	adaHari := map[int]bool{}
+	adaJamKe := map[int]bool{}
+	for _, jp := range j.Jam {
+		jam[slot{Hari: jp.Hari, JamKe: jp.JamKe}] = jp
+		adaHari[jp.Hari] = true
+		adaJamKe[jp.JamKe] = true
+	}
+	isi := map[slot]JadwalPelajaran{}
+	for _, jp := range j.Jadwal {
+		isi[slot{Hari: jp.Hari, JamKe: jp.JamKe}] = jp
+		adaHari[jp.Hari] = true
+		adaJamKe[jp.JamKe] = true
+	}
+	hari := kunciUrut(adaHari)
+	jamKe := kunciUrut(adaJamKe)
+	if len(hari) == 0 {
+		pdf.SetFont("Helvetica", "I", 9)
+		pdf.CellFormat(contentWidth, 6, "Jadwal belum disusun.", "", 1, "C", false, 0, "")
+		return output(pdf)
+	}
+
+	lebarHari := (contentWidth - lebarKolomJam) / float64(len(hari))
+	kepala := func() {
+		pdf.SetFont("Helvetica", "B", 8)
+		pdf.SetFillColor(230, 230, 230)
+		pdf.CellFormat(lebarKolomJam, 6, "Jam", "1", 0, "C", true, 0, "")
+		for _, h := range hari {
+			pdf.CellFormat(lebarHari, 6, namaHari[h], "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	kepala()
+
+	for _, ke := range jamKe {
+		if pdf.GetY()+tinggiSel > pageHeight-bottom {
+			pdf.AddPage()
+			kepala()
+		}
+		x, y := pdf.GetX(), pdf.GetY()
+		pdf.SetFont("Helvetica", "B", 8)
+		pdf.CellFormat(lebarKolomJam, tinggiSel, fmt.Sprintf("%d", ke), "1", 0, "C", false, 0, "")
+		for i, h := range hari {
+			sx := x + lebarKolomJam + float64(i)*lebarHari
+			sl := slot{Hari: h, JamKe: ke}
+			jp, terdefinisi := jam[sl]
+			baris := []string{}
+			if terdefinisi {
+				baris = append(baris, jp.JamMulai+" - "+jp.JamSelesai)
+			}
+			isiSel := false
+			switch e, ok := isi[sl]; {
+			case ok && j.Cakupan == CakupanGuru:
+				baris, isiSel = append(baris, e.NamaMapel, e.NamaKelas), true
+			case ok:
+				baris, isiSel = append(baris, e.NamaMapel, e.NamaGuru), true
+			case terdefinisi && jp.Istirahat:
+				baris = append(baris, "Istirahat")
+			}
+			sel(pdf, tr, sx, y, lebarHari, baris, !terdefinisi || (jp.Istirahat && !isiSel))
+		}
+		pdf.SetXY(x, y+tinggiSel)
+	}
+
+	pdf.SetFont("Helvetica", "I", 8)
+	pdf.Ln(2)
+	pdf.CellFormat(contentWidth, 4, tr(fmt.Sprintf("%d jam pelajaran per minggu.", len(j.Jadwal))), "", 1, "L", false, 0, "")
+	return output(pdf)
+}
+
+// sel menggambar satu sel grid setinggi tinggiSel. Baris pertama (waktu) dicetak kecil,
+// baris selanjutnya dipotong agar muat lebar sel.
+func sel(pdf *gofpdf.Fpdf, tr func(string) string, x, y, w float64, baris []string, abu bool) {
+	gayaRect := "D"
+	if abu {
+		pdf.SetFillColor(240, 240, 240)
+		gayaRect = "FD"
+	}
+	pdf.Rect(x, y, w, tinggiSel, gayaRect)
+	for i, teks := range baris {
+		ukuran, gaya := 8.0, ""
+		if i == 0 {
+			ukuran = 6
+		} else if i == 1 {
+			gaya = "B"
+		}
+		pdf.SetFont("Helvetica", gaya, ukuran)
+		teks = tr(teks)
+		for len(teks) > 1 && pdf.GetStringWidth(teks) > w-2 {
+			teks = teks[:len(teks)-1]
+		}
+		pdf.SetXY(x, y+0.5+float64(i)*3.6)
+		pdf.CellFormat(w, 3.6, teks, "", 0, "C", false, 0, "")
+	}
+}
+
+func kunciUrut(m map[int]bool) []int {
+	list := make([]int, 0, len(m))
+	for k := range m {
+		list = append(list, k)
+	}
+	sort.Ints(list)
+	return list
+}
+
+func output(pdf *gofpdf.Fpdf) ([]byte, error) {
+	if pdf.Err() {
+		return nil, fmt.Errorf("gagal merender jadwal pelajaran: %w", pdf.Error())
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("gagal menghasilkan file PDF: %w", err)
+	}
+	if buf.Len() == 0 {
+		return nil, errors.New("output PDF kosong")
+	}
+	return buf.Bytes(), nil
+}
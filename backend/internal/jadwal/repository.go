@@ -0,0 +1,563 @@
+// file: backend/internal/jadwal/repository.go
+package jadwal
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"skoola/pkg/database"
+)
+
+// Repository mendefinisikan interface untuk interaksi database jadwal pelajaran.
+type Repository interface {
+	// Fungsi GetNama*, GetKelasInfo, GetTahunAjaranAktif, dan GetPengajar mengembalikan nil
+	// jika data tidak ditemukan.
+	GetNamaTahunAjaran(ctx context.Context, schemaName string, tahunAjaranID string) (*string, error)
+	GetTahunAjaranAktif(ctx context.Context, schemaName string) (*string, error)
+	GetKelasInfo(ctx context.Context, schemaName string, kelasID string) (*kelasInfo, error)
+	GetNamaGuru(ctx context.Context, schemaName string, teacherID string) (*string, error)
+	GetPengajar(ctx context.Context, schemaName string, pengajarKelasID string) (*pengajarBeban, error)
+
+	GetJam(ctx context.Context, schemaName string, tahunAjaranID string) ([]JamPelajaran, error)
+	// ReplaceJam menggantikan definisi jam pelajaran dan menghapus jadwal yang jamnya tidak
+	// lagi ada atau kini menjadi jam istirahat. Mengembalikan jumlah jadwal yang terhapus.
+	ReplaceJam(ctx context.Context, schemaName string, tahunAjaranID string, jam []JamPelajaranInput) (int, error)
+	GetBeban(ctx context.Context, schemaName string, tahunAjaranID string) ([]BebanMapel, error)
+	ReplaceBeban(ctx context.Context, schemaName string, tahunAjaranID string, beban []BebanMapelInput) error
+	GetGuruTidakTersedia(ctx context.Context, schemaName string, tahunAjaranID string) ([]GuruTidakTersedia, error)
+	CreateGuruTidakTersedia(ctx context.Context, schemaName string, tahunAjaranID string, input GuruTidakTersediaInput) (int, error)
+	DeleteGuruTidakTersedia(ctx context.Context, schemaName string, tahunAjaranID string, id int) error
+
+	// GetPengajarBeban mengembalikan semua pengajar kelas di tahun ajaran beserta bebannya,
+	// diurutkan per kelas lalu mapel.
+	GetPengajarBeban(ctx context.Context, schemaName string, tahunAjaranID string) ([]pengajarBeban, error)
+	// GetJadwal mengembalikan jadwal satu tahun ajaran. kelasID dan teacherID boleh kosong.
+	GetJadwal(ctx context.Context, schemaName string, tahunAjaranID string, kelasID string, teacherID string) ([]JadwalPelajaran, error)
+	// GetJadwalByID mengembalikan nil jika jadwal tidak ditemukan.
+	GetJadwalByID(ctx context.Context, schemaName string, id string) (*JadwalPelajaran, error)
+	CreateJadwal(ctx context.Context, schemaName string, pengajar pengajarBeban, hari int, jamKe int, terkunci bool) (string, error)
+	UpdateJadwal(ctx context.Context, schemaName string, id string, pengajar pengajarBeban, hari int, jamKe int, terkunci bool) error
+	DeleteJadwal(ctx context.Context, schemaName string, id string) error
+	// SimpanHasil menghapus jadwal yang tidak terkunci di tahun ajaran lalu menyimpan hasil
+	// penyusunan otomatis, dalam satu transaksi.
+	SimpanHasil(ctx context.Context, schemaName string, tahunAjaranID string, hasil []penempatan) error
+}
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewRepository membuat instance baru dari postgresRepository.
+func NewRepository(db *sql.DB) Repository {
+	return &postgresRepository{db: db}
+}
+
+func (r *postgresRepository) GetNamaTahunAjaran(ctx context.Context, schemaName string, tahunAjaranID string) (*string, error) {
+	query := "SELECT nama_tahun_ajaran || ' Semester ' || semester::text FROM tahun_ajaran WHERE id = $1"
+	return r.getString(ctx, schemaName, query, tahunAjaranID)
+}
+
+func (r *postgresRepository) GetTahunAjaranAktif(ctx context.Context, schemaName string) (*string, error) {
+	query := "SELECT id::text FROM tahun_ajaran WHERE status = 'Aktif' ORDER BY created_at DESC LIMIT 1"
+	return r.getString(ctx, schemaName, query)
+}
+
+func (r *postgresRepository) GetNamaGuru(ctx context.Context, schemaName string, teacherID string) (*string, error) {
+	return r.getString(ctx, schemaName, "SELECT nama_lengkap FROM teachers WHERE id = $1", teacherID)
+}
+
+func (r *postgresRepository) getString(ctx context.Context, schemaName string, query string, args ...interface{}) (*string, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var v string
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&v); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("gagal mengambil data: %w", err)
+	}
+	return &v, tx.Commit()
+}
+
+func (r *postgresRepository) GetKelasInfo(ctx context.Context, schemaName string, kelasID string) (*kelasInfo, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var info kelasInfo
+	query := "SELECT nama_kelas, tahun_ajaran_id FROM kelas WHERE id = $1"
+	if err := tx.QueryRowContext(ctx, query, kelasID).Scan(&info.Nama, &info.TahunAjaranID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("gagal mengambil data kelas: %w", err)
+	}
+	return &info, tx.Commit()
+}
+
+const selectPengajarBeban = `
+	SELECT pk.id, k.tahun_ajaran_id, k.id, k.nama_kelas, t.id, t.nama_lengkap, mp.id, mp.nama_mapel,
+		b.jam_per_minggu, b.maks_jam_per_hari
+	FROM pengajar_kelas pk
+	JOIN kelas k ON pk.kelas_id = k.id
+	JOIN teachers t ON pk.teacher_id = t.id
+	JOIN mata_pelajaran mp ON pk.mata_pelajaran_id = mp.id
+	LEFT JOIN jadwal_beban b ON b.tahun_ajaran_id = k.tahun_ajaran_id
+		AND b.tingkatan_id = k.tingkatan_id AND b.mata_pelajaran_id = pk.mata_pelajaran_id
+`
+
+func scanPengajarBeban(row interface{ Scan(...interface{}) error }) (*pengajarBeban, error) {
+	var p pengajarBeban
+	var jam, maks sql.NullInt64
+	if err := row.Scan(&p.PengajarKelasID, &p.TahunAjaranID, &p.KelasID, &p.NamaKelas, &p.TeacherID, &p.NamaGuru,
+		&p.MataPelajaranID, &p.NamaMapel, &jam, &maks); err != nil {
+		return nil, err
+	}
+	if jam.Valid {
+		v := int(jam.Int64)
+		p.JamPerMinggu = &v
+	}
+	if maks.Valid {
+		v := int(maks.Int64)
+		p.MaksJamPerHari = &v
+	}
+	return &p, nil
+}
+
+func (r *postgresRepository) GetPengajar(ctx context.Context, schemaName string, pengajarKelasID string) (*pengajarBeban, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	p, err := scanPengajarBeban(tx.QueryRowContext(ctx, selectPengajarBeban+" WHERE pk.id = $1", pengajarKelasID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("gagal mengambil data pengajar kelas: %w", err)
+	}
+	return p, tx.Commit()
+}
+
+func (r *postgresRepository) GetPengajarBeban(ctx context.Context, schemaName string, tahunAjaranID string) ([]pengajarBeban, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := selectPengajarBeban + `
+		WHERE k.tahun_ajaran_id = $1
+		ORDER BY k.nama_kelas ASC, mp.nama_mapel ASC, t.nama_lengkap ASC
+	`
+	rows, err := tx.QueryContext(ctx, query, tahunAjaranID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil pengajar kelas: %w", err)
+	}
+	defer rows.Close()
+
+	var list []pengajarBeban
+	for rows.Next() {
+		p, err := scanPengajarBeban(rows)
+		if err != nil {
+			return nil, fmt.Errorf("gagal memindai pengajar kelas: %w", err)
+		}
+		list = append(list, *p)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+func (r *postgresRepository) GetJam(ctx context.Context, schemaName string, tahunAjaranID string) ([]JamPelajaran, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT id, tahun_ajaran_id, hari, jam_ke, to_char(jam_mulai, 'HH24:MI'), to_char(jam_selesai, 'HH24:MI'), istirahat
+		FROM jadwal_jam
+		WHERE tahun_ajaran_id = $1
+		ORDER BY hari ASC, jam_ke ASC
+	`
+	rows, err := tx.QueryContext(ctx, query, tahunAjaranID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil jam pelajaran: %w", err)
+	}
+	defer rows.Close()
+
+	list := []JamPelajaran{}
+	for rows.Next() {
+		var j JamPelajaran
+		if err := rows.Scan(&j.ID, &j.TahunAjaranID, &j.Hari, &j.JamKe, &j.JamMulai, &j.JamSelesai, &j.Istirahat); err != nil {
+			return nil, fmt.Errorf("gagal memindai jam pelajaran: %w", err)
+		}
+		j.NamaHari = namaHari[j.Hari]
+		list = append(list, j)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+func (r *postgresRepository) ReplaceJam(ctx context.Context, schemaName string, tahunAjaranID string, jam []JamPelajaranInput) (int, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM jadwal_jam WHERE tahun_ajaran_id = $1", tahunAjaranID); err != nil {
+		return 0, fmt.Errorf("gagal menghapus jam pelajaran lama: %w", err)
+	}
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO jadwal_jam (tahun_ajaran_id, hari, jam_ke, jam_mulai, jam_selesai, istirahat)
+		VALUES ($1, $2, $3, $4::time, $5::time, $6)
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("gagal mempersiapkan statement jam pelajaran: %w", err)
+	}
+	defer stmt.Close()
+	for _, j := range jam {
+		if _, err := stmt.ExecContext(ctx, tahunAjaranID, j.Hari, j.JamKe, j.JamMulai, j.JamSelesai, j.Istirahat); err != nil {
+			return 0, fmt.Errorf("gagal menyimpan jam ke-%d hari %s: %w", j.JamKe, namaHari[j.Hari], err)
+		}
+	}
+
+	query := `
+		DELETE FROM jadwal_pelajaran jp
+		USING kelas k
+		WHERE jp.kelas_id = k.id AND k.tahun_ajaran_id = $1
+			AND NOT EXISTS (
+				SELECT 1 FROM jadwal_jam jj
+				WHERE jj.tahun_ajaran_id = $1 AND jj.hari = jp.hari AND jj.jam_ke = jp.jam_ke AND NOT jj.istirahat
+			)
+	`
+	res, err := tx.ExecContext(ctx, query, tahunAjaranID)
+	if err != nil {
+		return 0, fmt.Errorf("gagal menghapus jadwal pada jam yang dihapus: %w", err)
+	}
+	dihapus, _ := res.RowsAffected()
+	return int(dihapus), tx.Commit()
+}
+
+func (r *postgresRepository) GetBeban(ctx context.Context, schemaName string, tahunAjaranID string) ([]BebanMapel, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT b.id, b.tahun_ajaran_id, b.tingkatan_id, t.nama_tingkatan, b.mata_pelajaran_id, mp.nama_mapel,
+			b.jam_per_minggu, b.maks_jam_per_hari
+		FROM jadwal_beban b
+		JOIN tingkatan t ON b.tingkatan_id = t.id
+		JOIN mata_pelajaran mp ON b.mata_pelajaran_id = mp.id
+		WHERE b.tahun_ajaran_id = $1
+		ORDER BY t.urutan ASC NULLS LAST, t.nama_tingkatan ASC, mp.nama_mapel ASC
+	`
+	rows, err := tx.QueryContext(ctx, query, tahunAjaranID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil beban mapel: %w", err)
+	}
+	defer rows.Close()
+
+	list := []BebanMapel{}
+	for rows.Next() {
+		var b BebanMapel
+		var maks sql.NullInt64
+		if err := rows.Scan(&b.ID, &b.TahunAjaranID, &b.TingkatanID, &b.NamaTingkatan, &b.MataPelajaranID, &b.NamaMapel,
+			&b.JamPerMinggu, &maks); err != nil {
+			return nil, fmt.Errorf("gagal memindai beban mapel: %w", err)
+		}
+		if maks.Valid {
+			v := int(maks.Int64)
+			b.MaksJamPerHari = &v
+		}
+		list = append(list, b)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+func (r *postgresRepository) ReplaceBeban(ctx context.Context, schemaName string, tahunAjaranID string, beban []BebanMapelInput) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM jadwal_beban WHERE tahun_ajaran_id = $1", tahunAjaranID); err != nil {
+		return fmt.Errorf("gagal menghapus beban mapel lama: %w", err)
+	}
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO jadwal_beban (tahun_ajaran_id, tingkatan_id, mata_pelajaran_id, jam_per_minggu, maks_jam_per_hari)
+		VALUES ($1, $2, $3, $4, $5)
+	`)
+	if err != nil {
+		return fmt.Errorf("gagal mempersiapkan statement beban mapel: %w", err)
+	}
+	defer stmt.Close()
+	for _, b := range beban {
+		if _, err := stmt.ExecContext(ctx, tahunAjaranID, b.TingkatanID, b.MataPelajaranID, b.JamPerMinggu, b.MaksJamPerHari); err != nil {
+			return fmt.Errorf("gagal menyimpan beban mapel: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *postgresRepository) GetGuruTidakTersedia(ctx context.Context, schemaName string, tahunAjaranID string) ([]GuruTidakTersedia, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT g.id, g.tahun_ajaran_id, g.teacher_id, t.nama_lengkap, g.hari, g.jam_ke, g.keterangan
+		FROM guru_tidak_tersedia g
+		JOIN teachers t ON g.teacher_id = t.id
+		WHERE g.tahun_ajaran_id = $1
+		ORDER BY t.nama_lengkap ASC, g.hari ASC, g.jam_ke ASC NULLS FIRST
+	`
+	rows, err := tx.QueryContext(ctx, query, tahunAjaranID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil ketidaktersediaan guru: %w", err)
+	}
+	defer rows.Close()
+
+	list := []GuruTidakTersedia{}
+	for rows.Next() {
+		var g GuruTidakTersedia
+		var jamKe sql.NullInt64
+		if err := rows.Scan(&g.ID, &g.TahunAjaranID, &g.TeacherID, &g.NamaGuru, &g.Hari, &jamKe, &g.Keterangan); err != nil {
+			return nil, fmt.Errorf("gagal memindai ketidaktersediaan guru: %w", err)
+		}
+		if jamKe.Valid {
+			v := int(jamKe.Int64)
+			g.JamKe = &v
+		}
+		g.NamaHari = namaHari[g.Hari]
+		list = append(list, g)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+func (r *postgresRepository) CreateGuruTidakTersedia(ctx context.Context, schemaName string, tahunAjaranID string, input GuruTidakTersediaInput) (int, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var keterangan *string
+	if input.Keterangan != "" {
+		keterangan = &input.Keterangan
+	}
+	query := `
+		INSERT INTO guru_tidak_tersedia (tahun_ajaran_id, teacher_id, hari, jam_ke, keterangan)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+	var id int
+	if err := tx.QueryRowContext(ctx, query, tahunAjaranID, input.TeacherID, input.Hari, input.JamKe, keterangan).Scan(&id); err != nil {
+		return 0, fmt.Errorf("gagal menyimpan ketidaktersediaan guru: %w", err)
+	}
+	return id, tx.Commit()
+}
+
+func (r *postgresRepository) DeleteGuruTidakTersedia(ctx context.Context, schemaName string, tahunAjaranID string, id int) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, "DELETE FROM guru_tidak_tersedia WHERE id = $1 AND tahun_ajaran_id = $2", id, tahunAjaranID)
+	if err != nil {
+		return fmt.Errorf("gagal menghapus ketidaktersediaan guru: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
+const selectJadwal = `
+	SELECT jp.id, jp.pengajar_kelas_id, jp.kelas_id, k.nama_kelas, t.id, t.nama_lengkap, mp.id, mp.nama_mapel,
+		jp.hari, jp.jam_ke, COALESCE(to_char(jj.jam_mulai, 'HH24:MI'), ''), COALESCE(to_char(jj.jam_selesai, 'HH24:MI'), ''),
+		jp.terkunci
+	FROM jadwal_pelajaran jp
+	JOIN pengajar_kelas pk ON jp.pengajar_kelas_id = pk.id
+	JOIN kelas k ON jp.kelas_id = k.id
+	JOIN teachers t ON pk.teacher_id = t.id
+	JOIN mata_pelajaran mp ON pk.mata_pelajaran_id = mp.id
+	LEFT JOIN jadwal_jam jj ON jj.tahun_ajaran_id = k.tahun_ajaran_id AND jj.hari = jp.hari AND jj.jam_ke = jp.jam_ke
+`
+
+func scanJadwal(row interface{ Scan(...interface{}) error }) (*JadwalPelajaran, error) {
+	var j JadwalPelajaran
+	if err := row.Scan(&j.ID, &j.PengajarKelasID, &j.KelasID, &j.NamaKelas, &j.TeacherID, &j.NamaGuru, &j.MataPelajaranID,
+		&j.NamaMapel, &j.Hari, &j.JamKe, &j.JamMulai, &j.JamSelesai, &j.Terkunci); err != nil {
+		return nil, err
+	}
+	j.NamaHari = namaHari[j.Hari]
+	return &j, nil
+}
+
+func (r *postgresRepository) GetJadwal(ctx context.Context, schemaName string, tahunAjaranID string, kelasID string, teacherID string) ([]JadwalPelajaran, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := selectJadwal + `
+		WHERE k.tahun_ajaran_id = $1
+			AND ($2 = '' OR jp.kelas_id::text = $2)
+			AND ($3 = '' OR pk.teacher_id::text = $3)
+		ORDER BY jp.hari ASC, jp.jam_ke ASC, k.nama_kelas ASC
+	`
+	rows, err := tx.QueryContext(ctx, query, tahunAjaranID, kelasID, teacherID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil jadwal pelajaran: %w", err)
+	}
+	defer rows.Close()
+
+	list := []JadwalPelajaran{}
+	for rows.Next() {
+		j, err := scanJadwal(rows)
+		if err != nil {
+			return nil, fmt.Errorf("gagal memindai jadwal pelajaran: %w", err)
+		}
+		list = append(list, *j)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+func (r *postgresRepository) GetJadwalByID(ctx context.Context, schemaName string, id string) (*JadwalPelajaran, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	j, err := scanJadwal(tx.QueryRowContext(ctx, selectJadwal+" WHERE jp.id = $1", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("gagal mengambil jadwal pelajaran: %w", err)
+	}
+	return j, tx.Commit()
+}
+
+func (r *postgresRepository) CreateJadwal(ctx context.Context, schemaName string, pengajar pengajarBeban, hari int, jamKe int, terkunci bool) (string, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO jadwal_pelajaran (pengajar_kelas_id, kelas_id, hari, jam_ke, terkunci)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+	var id string
+	if err := tx.QueryRowContext(ctx, query, pengajar.PengajarKelasID, pengajar.KelasID, hari, jamKe, terkunci).Scan(&id); err != nil {
+		return "", fmt.Errorf("gagal menyimpan jadwal pelajaran: %w", err)
+	}
+	return id, tx.Commit()
+}
+
+func (r *postgresRepository) UpdateJadwal(ctx context.Context, schemaName string, id string, pengajar pengajarBeban, hari int, jamKe int, terkunci bool) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE jadwal_pelajaran
+		SET pengajar_kelas_id = $1, kelas_id = $2, hari = $3, jam_ke = $4, terkunci = $5, updated_at = NOW()
+		WHERE id = $6
+	`
+	res, err := tx.ExecContext(ctx, query, pengajar.PengajarKelasID, pengajar.KelasID, hari, jamKe, terkunci, id)
+	if err != nil {
+		return fmt.Errorf("gagal memperbarui jadwal pelajaran: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
+func (r *postgresRepository) DeleteJadwal(ctx context.Context, schemaName string, id string) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, "DELETE FROM jadwal_pelajaran WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("gagal menghapus jadwal pelajaran: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
+func (r *postgresRepository) SimpanHasil(ctx context.Context, schemaName string, tahunAjaranID string, hasil []penempatan) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		DELETE FROM jadwal_pelajaran jp
+		USING kelas k
+		WHERE jp.kelas_id = k.id AND k.tahun_ajaran_id = $1 AND NOT jp.terkunci
+	`
+	if _, err := tx.ExecContext(ctx, query, tahunAjaranID); err != nil {
+		return fmt.Errorf("gagal menghapus jadwal lama: %w", err)
+	}
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO jadwal_pelajaran (pengajar_kelas_id, kelas_id, hari, jam_ke, terkunci)
+		VALUES ($1, $2, $3, $4, FALSE)
+	`)
+	if err != nil {
+		return fmt.Errorf("gagal mempersiapkan statement jadwal: %w", err)
+	}
+	defer stmt.Close()
+	for _, p := range hasil {
+		if _, err := stmt.ExecContext(ctx, p.PengajarKelasID, p.KelasID, p.Hari, p.JamKe); err != nil {
+			return fmt.Errorf("gagal menyimpan jadwal pelajaran: %w", err)
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,605 @@
+// file: backend/internal/jadwal/service.go
+package jadwal
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"skoola/internal/audit"
+	"skoola/internal/papersize"
+	"skoola/internal/profile"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+var ErrValidation = errors.New("validation failed")
+
+// Service mendefinisikan logika bisnis jadwal pelajaran.
+type Service interface {
+	// GetPengaturan mengembalikan jam pelajaran, beban mapel, dan ketidaktersediaan guru.
+	GetPengaturan(ctx context.Context, schemaName string, tahunAjaranID string) (*Pengaturan, error)
+	// SimpanJam menggantikan seluruh jam pelajaran. Jadwal pada jam yang dihapus atau
+	// dijadikan istirahat ikut terhapus.
+	SimpanJam(ctx context.Context, schemaName string, tahunAjaranID string, input SimpanJamInput) ([]JamPelajaran, error)
+	SimpanBeban(ctx context.Context, schemaName string, tahunAjaranID string, input SimpanBebanInput) ([]BebanMapel, error)
+	CreateGuruTidakTersedia(ctx context.Context, schemaName string, tahunAjaranID string, input GuruTidakTersediaInput) (*GuruTidakTersedia, error)
+	DeleteGuruTidakTersedia(ctx context.Context, schemaName string, tahunAjaranID string, id int) error
+
+	GetJadwalKelas(ctx context.Context, schemaName string, kelasID string) (*JadwalMingguan, error)
+	// GetJadwalGuru memakai tahun ajaran aktif jika tahunAjaranID kosong.
+	GetJadwalGuru(ctx context.Context, schemaName string, teacherID string, tahunAjaranID string) (*JadwalMingguan, error)
+	// Generate menyusun jadwal seluruh kelas tanpa bentrok guru maupun kelas. Jadwal terkunci
+	// dipertahankan, sisanya disusun ulang.
+	Generate(ctx context.Context, schemaName string, tahunAjaranID string, input GenerateInput) (*HasilGenerate, error)
+	Create(ctx context.Context, schemaName string, input UpsertJadwalInput) (*JadwalPelajaran, error)
+	Update(ctx context.Context, schemaName string, id string, input UpsertJadwalInput) (*JadwalPelajaran, error)
+	Delete(ctx context.Context, schemaName string, id string) error
+	// ExportPDF merender jadwal mingguan dan mengembalikan isi serta nama filenya.
+	ExportPDF(ctx context.Context, schemaName string, jadwal *JadwalMingguan, paperSizeID string) ([]byte, string, error)
+}
+
+type service struct {
+	repo          Repository
+	profileRepo   profile.Repository
+	paperSizeRepo papersize.Repository
+	audit         audit.Recorder
+	validate      *validator.Validate
+}
+
+// NewService membuat instance baru dari service jadwal pelajaran.
+func NewService(repo Repository, profileRepo profile.Repository, paperSizeRepo papersize.Repository, auditLog audit.Recorder, validate *validator.Validate) Service {
+	return &service{repo: repo, profileRepo: profileRepo, paperSizeRepo: paperSizeRepo, audit: auditLog, validate: validate}
+}
+
+func (s *service) GetPengaturan(ctx context.Context, schemaName string, tahunAjaranID string) (*Pengaturan, error) {
+	if _, err := s.getTahunAjaran(ctx, schemaName, tahunAjaranID); err != nil {
+		return nil, err
+	}
+	return s.pengaturan(ctx, schemaName, tahunAjaranID)
+}
+
+func (s *service) pengaturan(ctx context.Context, schemaName string, tahunAjaranID string) (*Pengaturan, error) {
+	jam, err := s.repo.GetJam(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+	beban, err := s.repo.GetBeban(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+	tidakTersedia, err := s.repo.GetGuruTidakTersedia(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+	return &Pengaturan{Jam: jam, Beban: beban, GuruTidakTersedia: tidakTersedia}, nil
+}
+
+func (s *service) SimpanJam(ctx context.Context, schemaName string, tahunAjaranID string, input SimpanJamInput) ([]JamPelajaran, error) {
+	if err := s.validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	if _, err := s.getTahunAjaran(ctx, schemaName, tahunAjaranID); err != nil {
+		return nil, err
+	}
+	if err := validateJam(input.Jam); err != nil {
+		return nil, err
+	}
+	before, err := s.repo.GetJam(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+	dihapus, err := s.repo.ReplaceJam(ctx, schemaName, tahunAjaranID, input.Jam)
+	if err != nil {
+		return nil, err
+	}
+	after, err := s.repo.GetJam(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionUpdate, Entity: "jadwal_jam", EntityID: tahunAjaranID, Before: before,
+		After: map[string]interface{}{"jam": after, "jadwal_dihapus": dihapus}})
+	return after, nil
+}
+
+// validateJam memastikan jam_ke unik per hari, jam mulai sebelum jam selesai, dan jam-jam
+// dalam satu hari tidak saling tumpang tindih. Waktu dinormalisasi ke HH:MM agar dapat
+// dibandingkan sebagai teks.
+func validateJam(jam []JamPelajaranInput) error {
+	for i := range jam {
+		mulai, _ := time.Parse("15:04", jam[i].JamMulai)
+		selesai, _ := time.Parse("15:04", jam[i].JamSelesai)
+		jam[i].JamMulai, jam[i].JamSelesai = mulai.Format("15:04"), selesai.Format("15:04")
+	}
+	urut := append([]JamPelajaranInput(nil), jam...)
+	sort.Slice(urut, func(a, b int) bool {
+		if urut[a].Hari != urut[b].Hari {
+			return urut[a].Hari < urut[b].Hari
+		}
+		return urut[a].JamKe < urut[b].JamKe
+	})
+	for i, j := range urut {
+		if j.JamMulai >= j.JamSelesai {
+			return fmt.Errorf("%w: jam ke-%d hari %s harus selesai setelah dimulai", ErrValidation, j.JamKe, namaHari[j.Hari])
+		}
+		if i == 0 || urut[i-1].Hari != j.Hari {
+			continue
+		}
+		sebelum := urut[i-1]
+		if sebelum.JamKe == j.JamKe {
+			return fmt.Errorf("%w: jam ke-%d hari %s didefinisikan lebih dari sekali", ErrValidation, j.JamKe, namaHari[j.Hari])
+		}
+		if j.JamMulai < sebelum.JamSelesai {
+			return fmt.Errorf("%w: jam ke-%d hari %s tumpang tindih dengan jam ke-%d", ErrValidation, j.JamKe, namaHari[j.Hari], sebelum.JamKe)
+		}
+	}
+	return nil
+}
+
+func (s *service) SimpanBeban(ctx context.Context, schemaName string, tahunAjaranID string, input SimpanBebanInput) ([]BebanMapel, error) {
+	if err := s.validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	if _, err := s.getTahunAjaran(ctx, schemaName, tahunAjaranID); err != nil {
+		return nil, err
+	}
+	ada := map[string]bool{}
+	for _, b := range input.Beban {
+		kunci := fmt.Sprintf("%d|%s", b.TingkatanID, b.MataPelajaranID)
+		if ada[kunci] {
+			return nil, fmt.Errorf("%w: beban mapel %s untuk tingkatan %d diisi lebih dari sekali", ErrValidation, b.MataPelajaranID, b.TingkatanID)
+		}
+		ada[kunci] = true
+		if b.MaksJamPerHari != nil && *b.MaksJamPerHari > b.JamPerMinggu {
+			return nil, fmt.Errorf("%w: maks_jam_per_hari tidak boleh melebihi jam_per_minggu", ErrValidation)
+		}
+	}
+	before, err := s.repo.GetBeban(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplaceBeban(ctx, schemaName, tahunAjaranID, input.Beban); err != nil {
+		return nil, err
+	}
+	after, err := s.repo.GetBeban(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionUpdate, Entity: "jadwal_beban", EntityID: tahunAjaranID, Before: before, After: after})
+	return after, nil
+}
+
+func (s *service) CreateGuruTidakTersedia(ctx context.Context, schemaName string, tahunAjaranID string, input GuruTidakTersediaInput) (*GuruTidakTersedia, error) {
+	if err := s.validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	if _, err := s.getTahunAjaran(ctx, schemaName, tahunAjaranID); err != nil {
+		return nil, err
+	}
+	nama, err := s.repo.GetNamaGuru(ctx, schemaName, input.TeacherID)
+	if err != nil {
+		return nil, err
+	}
+	if nama == nil {
+		return nil, fmt.Errorf("%w: guru tidak ditemukan", ErrValidation)
+	}
+
+	id, err := s.repo.CreateGuruTidakTersedia(ctx, schemaName, tahunAjaranID, input)
+	if err != nil {
+		return nil, err
+	}
+	g := &GuruTidakTersedia{ID: id, TahunAjaranID: tahunAjaranID, TeacherID: input.TeacherID, NamaGuru: *nama,
+		Hari: input.Hari, NamaHari: namaHari[input.Hari], JamKe: input.JamKe}
+	if input.Keterangan != "" {
+		g.Keterangan = &input.Keterangan
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionCreate, Entity: "guru_tidak_tersedia", EntityID: fmt.Sprintf("%d", id), After: g})
+	return g, nil
+}
+
+func (s *service) DeleteGuruTidakTersedia(ctx context.Context, schemaName string, tahunAjaranID string, id int) error {
+	if _, err := s.getTahunAjaran(ctx, schemaName, tahunAjaranID); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteGuruTidakTersedia(ctx, schemaName, tahunAjaranID, id); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionDelete, Entity: "guru_tidak_tersedia", EntityID: fmt.Sprintf("%d", id)})
+	return nil
+}
+
+func (s *service) GetJadwalKelas(ctx context.Context, schemaName string, kelasID string) (*JadwalMingguan, error) {
+	if _, err := uuid.Parse(kelasID); err != nil {
+		return nil, fmt.Errorf("%w: kelas_id tidak valid", ErrValidation)
+	}
+	kelas, err := s.repo.GetKelasInfo(ctx, schemaName, kelasID)
+	if err != nil {
+		return nil, err
+	}
+	if kelas == nil {
+		return nil, sql.ErrNoRows
+	}
+	return s.mingguan(ctx, schemaName, CakupanKelas, kelasID, kelas.Nama, kelas.TahunAjaranID, kelasID, "")
+}
+
+func (s *service) GetJadwalGuru(ctx context.Context, schemaName string, teacherID string, tahunAjaranID string) (*JadwalMingguan, error) {
+	if _, err := uuid.Parse(teacherID); err != nil {
+		return nil, fmt.Errorf("%w: teacher_id tidak valid", ErrValidation)
+	}
+	nama, err := s.repo.GetNamaGuru(ctx, schemaName, teacherID)
+	if err != nil {
+		return nil, err
+	}
+	if nama == nil {
+		return nil, sql.ErrNoRows
+	}
+	if tahunAjaranID == "" {
+		aktif, err := s.repo.GetTahunAjaranAktif(ctx, schemaName)
+		if err != nil {
+			return nil, err
+		}
+		if aktif == nil {
+			return nil, fmt.Errorf("%w: tidak ada tahun ajaran aktif, isi tahun_ajaran_id", ErrValidation)
+		}
+		tahunAjaranID = *aktif
+	}
+	return s.mingguan(ctx, schemaName, CakupanGuru, teacherID, *nama, tahunAjaranID, "", teacherID)
+}
+
+func (s *service) mingguan(ctx context.Context, schemaName string, cakupan string, id string, nama string, tahunAjaranID string, kelasID string, teacherID string) (*JadwalMingguan, error) {
+	namaTA, err := s.getTahunAjaran(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+	jam, err := s.repo.GetJam(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+	jadwal, err := s.repo.GetJadwal(ctx, schemaName, tahunAjaranID, kelasID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+	return &JadwalMingguan{Cakupan: cakupan, ID: id, Nama: nama, TahunAjaranID: tahunAjaranID, TahunAjaran: namaTA, Jam: jam, Jadwal: jadwal}, nil
+}
+
+func (s *service) Generate(ctx context.Context, schemaName string, tahunAjaranID string, input GenerateInput) (*HasilGenerate, error) {
+	if _, err := s.getTahunAjaran(ctx, schemaName, tahunAjaranID); err != nil {
+		return nil, err
+	}
+	pengaturan, err := s.pengaturan(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+	p := newPapan(pengaturan.Jam, pengaturan.GuruTidakTersedia)
+	if len(p.slot) == 0 {
+		return nil, fmt.Errorf("%w: jam pelajaran tahun ajaran ini belum diatur", ErrValidation)
+	}
+	pengajar, err := s.repo.GetPengajarBeban(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+	jadwal, err := s.repo.GetJadwal(ctx, schemaName, tahunAjaranID, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	hasil := &HasilGenerate{DryRun: input.DryRun, Kekurangan: []Kekurangan{}}
+
+	// 1. Satu kebutuhan per pengajar kelas yang bebannya sudah diatur. Jika satu mapel di satu
+	//    kelas diajar beberapa guru, bebannya hanya dijadwalkan untuk guru pertama.
+	var list []*kebutuhan
+	perPengajar := map[string]*kebutuhan{}
+	mapelKelas := map[string]string{}
+	for _, pb := range pengajar {
+		if pb.JamPerMinggu == nil {
+			hasil.Pesan = append(hasil.Pesan, fmt.Sprintf("Beban %s untuk tingkatan kelas %s belum diatur, tidak dijadwalkan", pb.NamaMapel, pb.NamaKelas))
+			continue
+		}
+		kunci := pb.KelasID + "|" + pb.MataPelajaranID
+		if guru, ok := mapelKelas[kunci]; ok {
+			hasil.Pesan = append(hasil.Pesan, fmt.Sprintf("%s di kelas %s diajar lebih dari satu guru, beban dijadwalkan untuk %s", pb.NamaMapel, pb.NamaKelas, guru))
+			continue
+		}
+		mapelKelas[kunci] = pb.NamaGuru
+		k := &kebutuhan{pengajar: pb, sisa: *pb.JamPerMinggu, perHari: map[int]int{}}
+		if pb.MaksJamPerHari != nil {
+			k.maks = *pb.MaksJamPerHari
+		}
+		list = append(list, k)
+		perPengajar[pb.PengajarKelasID] = k
+	}
+
+	// 2. Jadwal terkunci dipasang lebih dulu dan mengurangi sisa jam pengajarnya.
+	var dipertahankan []JadwalPelajaran
+	for _, j := range jadwal {
+		if !j.Terkunci {
+			continue
+		}
+		dipertahankan = append(dipertahankan, j)
+		if i, ok := p.indeks[slot{Hari: j.Hari, JamKe: j.JamKe}]; ok {
+			p.set(j.KelasID, j.TeacherID, i, true)
+		}
+		if k, ok := perPengajar[j.PengajarKelasID]; ok {
+			k.perHari[j.Hari]++
+			if k.sisa > 0 {
+				k.sisa--
+			}
+		}
+	}
+	hasil.Dipertahankan = len(dipertahankan)
+
+	// 3. Susun sisa jam.
+	sv := &penyusun{papan: p, kebutuhan: list}
+	sv.susun()
+
+	waktu := map[slot]JamPelajaran{}
+	for _, j := range pengaturan.Jam {
+		waktu[slot{Hari: j.Hari, JamKe: j.JamKe}] = j
+	}
+	var penempatanBaru []penempatan
+	pratinjau := append([]JadwalPelajaran{}, dipertahankan...)
+	for _, k := range list {
+		pb := k.pengajar
+		for _, i := range k.hasil {
+			sl := p.slot[i]
+			penempatanBaru = append(penempatanBaru, penempatan{PengajarKelasID: pb.PengajarKelasID, KelasID: pb.KelasID, Hari: sl.Hari, JamKe: sl.JamKe})
+			pratinjau = append(pratinjau, JadwalPelajaran{PengajarKelasID: pb.PengajarKelasID, KelasID: pb.KelasID, NamaKelas: pb.NamaKelas,
+				TeacherID: pb.TeacherID, NamaGuru: pb.NamaGuru, MataPelajaranID: pb.MataPelajaranID, NamaMapel: pb.NamaMapel,
+				Hari: sl.Hari, NamaHari: namaHari[sl.Hari], JamKe: sl.JamKe, JamMulai: waktu[sl].JamMulai, JamSelesai: waktu[sl].JamSelesai})
+		}
+		if k.sisa > 0 {
+			hasil.Kekurangan = append(hasil.Kekurangan, Kekurangan{PengajarKelasID: pb.PengajarKelasID, NamaKelas: pb.NamaKelas,
+				NamaMapel: pb.NamaMapel, NamaGuru: pb.NamaGuru, JamPerMinggu: *pb.JamPerMinggu, Terjadwal: *pb.JamPerMinggu - k.sisa})
+		}
+	}
+	hasil.Ditempatkan = len(penempatanBaru)
+	hasil.Lengkap = len(hasil.Kekurangan) == 0
+
+	if input.DryRun {
+		sort.SliceStable(pratinjau, func(a, b int) bool {
+			if pratinjau[a].Hari != pratinjau[b].Hari {
+				return pratinjau[a].Hari < pratinjau[b].Hari
+			}
+			if pratinjau[a].JamKe != pratinjau[b].JamKe {
+				return pratinjau[a].JamKe < pratinjau[b].JamKe
+			}
+			return pratinjau[a].NamaKelas < pratinjau[b].NamaKelas
+		})
+		hasil.Jadwal = pratinjau
+		return hasil, nil
+	}
+
+	if err := s.repo.SimpanHasil(ctx, schemaName, tahunAjaranID, penempatanBaru); err != nil {
+		return nil, err
+	}
+	if hasil.Jadwal, err = s.repo.GetJadwal(ctx, schemaName, tahunAjaranID, "", ""); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionCreate, Entity: "jadwal_generate", EntityID: tahunAjaranID,
+		After: map[string]interface{}{"ditempatkan": hasil.Ditempatkan, "dipertahankan": hasil.Dipertahankan, "kekurangan": hasil.Kekurangan}})
+	return hasil, nil
+}
+
+func (s *service) Create(ctx context.Context, schemaName string, input UpsertJadwalInput) (*JadwalPelajaran, error) {
+	pengajar, err := s.validateManual(ctx, schemaName, "", input)
+	if err != nil {
+		return nil, err
+	}
+	id, err := s.repo.CreateJadwal(ctx, schemaName, *pengajar, input.Hari, input.JamKe, terkunci(input))
+	if err != nil {
+		return nil, err
+	}
+	jadwal, err := s.get(ctx, schemaName, id)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionCreate, Entity: "jadwal_pelajaran", EntityID: id, After: jadwal})
+	return jadwal, nil
+}
+
+func (s *service) Update(ctx context.Context, schemaName string, id string, input UpsertJadwalInput) (*JadwalPelajaran, error) {
+	before, err := s.get(ctx, schemaName, id)
+	if err != nil {
+		return nil, err
+	}
+	pengajar, err := s.validateManual(ctx, schemaName, id, input)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateJadwal(ctx, schemaName, id, *pengajar, input.Hari, input.JamKe, terkunci(input)); err != nil {
+		return nil, err
+	}
+	jadwal, err := s.get(ctx, schemaName, id)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionUpdate, Entity: "jadwal_pelajaran", EntityID: id, Before: before, After: jadwal})
+	return jadwal, nil
+}
+
+func (s *service) Delete(ctx context.Context, schemaName string, id string) error {
+	before, err := s.get(ctx, schemaName, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteJadwal(ctx, schemaName, id); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionDelete, Entity: "jadwal_pelajaran", EntityID: id, Before: before})
+	return nil
+}
+
+func terkunci(input UpsertJadwalInput) bool {
+	return input.Terkunci == nil || *input.Terkunci
+}
+
+// validateManual memeriksa penempatan manual: jamnya terdefinisi dan bukan istirahat, guru
+// tersedia, kelas dan guru tidak bentrok, serta beban mingguan dan harian tidak terlampaui.
+// id adalah jadwal yang sedang diubah (kosong saat membuat) dan tidak dihitung sebagai bentrok.
+func (s *service) validateManual(ctx context.Context, schemaName string, id string, input UpsertJadwalInput) (*pengajarBeban, error) {
+	if err := s.validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	pengajar, err := s.repo.GetPengajar(ctx, schemaName, input.PengajarKelasID)
+	if err != nil {
+		return nil, err
+	}
+	if pengajar == nil {
+		return nil, fmt.Errorf("%w: pengajar kelas tidak ditemukan", ErrValidation)
+	}
+	if pengajar.JamPerMinggu == nil {
+		return nil, fmt.Errorf("%w: beban %s untuk tingkatan kelas %s belum diatur", ErrValidation, pengajar.NamaMapel, pengajar.NamaKelas)
+	}
+	waktu := fmt.Sprintf("hari %s jam ke-%d", namaHari[input.Hari], input.JamKe)
+
+	pengaturan, err := s.pengaturan(ctx, schemaName, pengajar.TahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+	var jam *JamPelajaran
+	for i := range pengaturan.Jam {
+		if pengaturan.Jam[i].Hari == input.Hari && pengaturan.Jam[i].JamKe == input.JamKe {
+			jam = &pengaturan.Jam[i]
+		}
+	}
+	if jam == nil {
+		return nil, fmt.Errorf("%w: %s belum didefinisikan", ErrValidation, waktu)
+	}
+	if jam.Istirahat {
+		return nil, fmt.Errorf("%w: %s adalah jam istirahat", ErrValidation, waktu)
+	}
+	for _, g := range pengaturan.GuruTidakTersedia {
+		if g.TeacherID == pengajar.TeacherID && g.Hari == input.Hari && (g.JamKe == nil || *g.JamKe == input.JamKe) {
+			return nil, fmt.Errorf("%w: %s tidak tersedia pada %s", ErrValidation, pengajar.NamaGuru, waktu)
+		}
+	}
+
+	jadwal, err := s.repo.GetJadwal(ctx, schemaName, pengajar.TahunAjaranID, "", "")
+	if err != nil {
+		return nil, err
+	}
+	perMinggu, perHari := 0, 0
+	for _, j := range jadwal {
+		if j.ID == id {
+			continue
+		}
+		if j.Hari == input.Hari && j.JamKe == input.JamKe {
+			if j.KelasID == pengajar.KelasID {
+				return nil, fmt.Errorf("%w: kelas %s sudah terisi %s pada %s", ErrValidation, j.NamaKelas, j.NamaMapel, waktu)
+			}
+			if j.TeacherID == pengajar.TeacherID {
+				return nil, fmt.Errorf("%w: %s sudah mengajar di kelas %s pada %s", ErrValidation, j.NamaGuru, j.NamaKelas, waktu)
+			}
+		}
+		if j.PengajarKelasID == pengajar.PengajarKelasID {
+			perMinggu++
+			if j.Hari == input.Hari {
+				perHari++
+			}
+		}
+	}
+	if perMinggu >= *pengajar.JamPerMinggu {
+		return nil, fmt.Errorf("%w: %s di kelas %s sudah mencapai %d jam per minggu", ErrValidation, pengajar.NamaMapel, pengajar.NamaKelas, *pengajar.JamPerMinggu)
+	}
+	if pengajar.MaksJamPerHari != nil && perHari >= *pengajar.MaksJamPerHari {
+		return nil, fmt.Errorf("%w: %s di kelas %s sudah mencapai %d jam pada hari %s", ErrValidation, pengajar.NamaMapel, pengajar.NamaKelas,
+			*pengajar.MaksJamPerHari, namaHari[input.Hari])
+	}
+	return pengajar, nil
+}
+
+func (s *service) ExportPDF(ctx context.Context, schemaName string, jadwal *JadwalMingguan, paperSizeID string) ([]byte, string, error) {
+	paper, err := s.resolvePaper(ctx, schemaName, paperSizeID)
+	if err != nil {
+		return nil, "", err
+	}
+	sekolah, err := s.profileRepo.GetProfile(ctx, schemaName)
+	if err != nil {
+		return nil, "", err
+	}
+	if sekolah == nil {
+		sekolah = &profile.ProfilSekolah{}
+	}
+	content, err := renderPDF(paper, sekolah, jadwal)
+	if err != nil {
+		return nil, "", err
+	}
+	filename := fmt.Sprintf("jadwal_%s_%s.%s", fileSafe(strings.ToLower(jadwal.Nama)), time.Now().Format("20060102"), FormatPDF)
+	return content, filename, nil
+}
+
+// getTahunAjaran memvalidasi tahun ajaran dan mengembalikan namanya.
+func (s *service) getTahunAjaran(ctx context.Context, schemaName string, tahunAjaranID string) (string, error) {
+	if _, err := uuid.Parse(tahunAjaranID); err != nil {
+		return "", fmt.Errorf("%w: tahun_ajaran_id tidak valid", ErrValidation)
+	}
+	nama, err := s.repo.GetNamaTahunAjaran(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return "", err
+	}
+	if nama == nil {
+		return "", sql.ErrNoRows
+	}
+	return *nama, nil
+}
+
+func (s *service) get(ctx context.Context, schemaName string, id string) (*JadwalPelajaran, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, sql.ErrNoRows
+	}
+	jadwal, err := s.repo.GetJadwalByID(ctx, schemaName, id)
+	if err != nil {
+		return nil, err
+	}
+	if jadwal == nil {
+		return nil, sql.ErrNoRows
+	}
+	return jadwal, nil
+}
+
+// resolvePaper memakai ukuran kertas yang diminta, atau A4 (atau ukuran pertama yang
+// tersedia) dari pengaturan ukuran kertas sekolah. Nil berarti memakai A4 bawaan.
+func (s *service) resolvePaper(ctx context.Context, schemaName string, paperSizeID string) (*papersize.PaperSize, error) {
+	if paperSizeID != "" {
+		p, err := s.paperSizeRepo.GetByID(ctx, schemaName, paperSizeID)
+		if err != nil {
+			return nil, err
+		}
+		if p == nil {
+			return nil, fmt.Errorf("%w: ukuran kertas tidak ditemukan", ErrValidation)
+		}
+		return p, nil
+	}
+
+	list, err := s.paperSizeRepo.GetAll(ctx, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		if strings.EqualFold(list[i].NamaKertas, "A4") {
+			return &list[i], nil
+		}
+	}
+	if len(list) > 0 {
+		return &list[0], nil
+	}
+	return nil, nil
+}
+
+// fileSafe mengubah nama kelas atau guru menjadi nama file yang aman.
+func fileSafe(name string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
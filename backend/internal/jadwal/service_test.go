@@ -0,0 +1,219 @@
+// file: backend/internal/jadwal/service_test.go
+package jadwal
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// stubRepository hanya mengisi method yang dipakai Generate saat DryRun. Method lain akan
+// panic karena Repository tertanam bernilai nil.
+type stubRepository struct {
+	Repository
+	jam           []JamPelajaran
+	tidakTersedia []GuruTidakTersedia
+	pengajar      []pengajarBeban
+	jadwal        []JadwalPelajaran
+}
+
+func (r *stubRepository) GetNamaTahunAjaran(ctx context.Context, schemaName string, tahunAjaranID string) (*string, error) {
+	nama := "2026/2027 Ganjil"
+	return &nama, nil
+}
+
+func (r *stubRepository) GetJam(ctx context.Context, schemaName string, tahunAjaranID string) ([]JamPelajaran, error) {
+	return r.jam, nil
+}
+
+func (r *stubRepository) GetBeban(ctx context.Context, schemaName string, tahunAjaranID string) ([]BebanMapel, error) {
+	return nil, nil
+}
+
+func (r *stubRepository) GetGuruTidakTersedia(ctx context.Context, schemaName string, tahunAjaranID string) ([]GuruTidakTersedia, error) {
+	return r.tidakTersedia, nil
+}
+
+func (r *stubRepository) GetPengajarBeban(ctx context.Context, schemaName string, tahunAjaranID string) ([]pengajarBeban, error) {
+	return r.pengajar, nil
+}
+
+func (r *stubRepository) GetJadwal(ctx context.Context, schemaName string, tahunAjaranID string, kelasID string, teacherID string) ([]JadwalPelajaran, error) {
+	return r.jadwal, nil
+}
+
+func TestGenerateKekurangan(t *testing.T) {
+	const taID = "6f1c2d3e-4a5b-4c6d-8e7f-9a0b1c2d3e4f"
+	n := func(v int) *int { return &v }
+	jam := []JamPelajaran{
+		{Hari: 1, JamKe: 1}, {Hari: 1, JamKe: 2}, {Hari: 1, JamKe: 3, Istirahat: true},
+		{Hari: 2, JamKe: 1}, {Hari: 2, JamKe: 2},
+	}
+	mtkX := pengajarBeban{PengajarKelasID: "pk-mtk-x", KelasID: "X", NamaKelas: "7A", TeacherID: "budi", NamaGuru: "Budi",
+		MataPelajaranID: "mtk", NamaMapel: "Matematika", JamPerMinggu: n(3)}
+	mtkY := pengajarBeban{PengajarKelasID: "pk-mtk-y", KelasID: "Y", NamaKelas: "7B", TeacherID: "budi", NamaGuru: "Budi",
+		MataPelajaranID: "mtk", NamaMapel: "Matematika", JamPerMinggu: n(3)}
+	ipaX := pengajarBeban{PengajarKelasID: "pk-ipa-x", KelasID: "X", NamaKelas: "7A", TeacherID: "sari", NamaGuru: "Sari",
+		MataPelajaranID: "ipa", NamaMapel: "IPA", JamPerMinggu: n(1)}
+	seniX := pengajarBeban{PengajarKelasID: "pk-seni-x", KelasID: "X", NamaKelas: "7A", TeacherID: "dewi", NamaGuru: "Dewi",
+		MataPelajaranID: "seni", NamaMapel: "Seni Budaya"}
+
+	tests := []struct {
+		name              string
+		repo              *stubRepository
+		wantLengkap       bool
+		wantDitempatkan   int
+		wantDipertahankan int
+		wantKurang        map[string]int // pengajar kelas -> jam yang terjadwal
+		wantPesan         int
+	}{
+		{
+			name:            "semua jam terjadwal",
+			repo:            &stubRepository{jam: jam, pengajar: []pengajarBeban{mtkX, ipaX}},
+			wantLengkap:     true,
+			wantDitempatkan: 4,
+		},
+		{
+			name:            "guru mengajar lebih banyak jam dari yang tersedia",
+			repo:            &stubRepository{jam: jam, pengajar: []pengajarBeban{mtkX, mtkY}},
+			wantLengkap:     false,
+			wantDitempatkan: 4,
+			wantKurang:      map[string]int{"pk-mtk-x": 2, "pk-mtk-y": 2},
+		},
+		{
+			name: "guru tidak tersedia menyisakan kekurangan",
+			repo: &stubRepository{jam: jam, pengajar: []pengajarBeban{mtkX},
+				tidakTersedia: []GuruTidakTersedia{{TeacherID: "budi", Hari: 2}}},
+			wantLengkap:     false,
+			wantDitempatkan: 2,
+			wantKurang:      map[string]int{"pk-mtk-x": 2},
+		},
+		{
+			name: "jadwal terkunci mengurangi sisa jam dan slot",
+			repo: &stubRepository{jam: jam, pengajar: []pengajarBeban{mtkX, ipaX},
+				jadwal: []JadwalPelajaran{
+					{PengajarKelasID: "pk-ipa-x", KelasID: "X", TeacherID: "sari", Hari: 1, JamKe: 1, Terkunci: true},
+					{PengajarKelasID: "pk-ipa-x", KelasID: "X", TeacherID: "sari", Hari: 1, JamKe: 2, Terkunci: true},
+				}},
+			wantLengkap:       false,
+			wantDitempatkan:   2,
+			wantDipertahankan: 2,
+			wantKurang:        map[string]int{"pk-mtk-x": 2},
+		},
+		{
+			name:            "beban yang belum diatur tidak dijadwalkan",
+			repo:            &stubRepository{jam: jam, pengajar: []pengajarBeban{ipaX, seniX}},
+			wantLengkap:     true,
+			wantDitempatkan: 1,
+			wantPesan:       1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{repo: tt.repo}
+			hasil, err := s.Generate(context.Background(), "tenant", taID, GenerateInput{DryRun: true})
+			if err != nil {
+				t.Fatalf("Generate error: %v", err)
+			}
+			if hasil.Lengkap != tt.wantLengkap {
+				t.Errorf("Lengkap = %v, ingin %v", hasil.Lengkap, tt.wantLengkap)
+			}
+			if hasil.Ditempatkan != tt.wantDitempatkan {
+				t.Errorf("Ditempatkan = %d, ingin %d", hasil.Ditempatkan, tt.wantDitempatkan)
+			}
+			if hasil.Dipertahankan != tt.wantDipertahankan {
+				t.Errorf("Dipertahankan = %d, ingin %d", hasil.Dipertahankan, tt.wantDipertahankan)
+			}
+			if len(hasil.Pesan) != tt.wantPesan {
+				t.Errorf("Pesan = %v, ingin %d pesan", hasil.Pesan, tt.wantPesan)
+			}
+			if len(hasil.Kekurangan) != len(tt.wantKurang) {
+				t.Fatalf("Kekurangan = %+v, ingin %d pengajar", hasil.Kekurangan, len(tt.wantKurang))
+			}
+			for _, k := range hasil.Kekurangan {
+				want, ok := tt.wantKurang[k.PengajarKelasID]
+				if !ok {
+					t.Errorf("kekurangan tidak terduga: %+v", k)
+					continue
+				}
+				if k.Terjadwal != want || k.JamPerMinggu != 3 {
+					t.Errorf("%s terjadwal %d dari %d, ingin %d dari 3", k.PengajarKelasID, k.Terjadwal, k.JamPerMinggu, want)
+				}
+			}
+			if got := len(hasil.Jadwal); got != tt.wantDitempatkan+tt.wantDipertahankan {
+				t.Errorf("pratinjau berisi %d jadwal, ingin %d", got, tt.wantDitempatkan+tt.wantDipertahankan)
+			}
+		})
+	}
+}
+
+func TestGenerateTanpaJamPelajaran(t *testing.T) {
+	s := &service{repo: &stubRepository{jam: []JamPelajaran{{Hari: 1, JamKe: 1, Istirahat: true}}}}
+	_, err := s.Generate(context.Background(), "tenant", "6f1c2d3e-4a5b-4c6d-8e7f-9a0b1c2d3e4f", GenerateInput{DryRun: true})
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("error = %v, ingin ErrValidation", err)
+	}
+}
+
+func TestValidateJam(t *testing.T) {
+	tests := []struct {
+		name    string
+		jam     []JamPelajaranInput
+		wantErr bool
+	}{
+		{
+			name: "jam berurutan",
+			jam: []JamPelajaranInput{
+				{Hari: 1, JamKe: 1, JamMulai: "07:00", JamSelesai: "07:40"},
+				{Hari: 1, JamKe: 2, JamMulai: "07:40", JamSelesai: "08:20"},
+				{Hari: 2, JamKe: 1, JamMulai: "07:00", JamSelesai: "07:40"},
+			},
+		},
+		{
+			name: "jam tanpa nol di depan dinormalisasi",
+			jam: []JamPelajaranInput{
+				{Hari: 1, JamKe: 1, JamMulai: "7:00", JamSelesai: "9:40"},
+				{Hari: 1, JamKe: 2, JamMulai: "9:40", JamSelesai: "10:20"},
+			},
+		},
+		{
+			name:    "selesai sebelum mulai",
+			jam:     []JamPelajaranInput{{Hari: 1, JamKe: 1, JamMulai: "08:00", JamSelesai: "07:40"}},
+			wantErr: true,
+		},
+		{
+			name: "jam ke ganda di hari yang sama",
+			jam: []JamPelajaranInput{
+				{Hari: 1, JamKe: 1, JamMulai: "07:00", JamSelesai: "07:40"},
+				{Hari: 1, JamKe: 1, JamMulai: "07:40", JamSelesai: "08:20"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "jam tumpang tindih",
+			jam: []JamPelajaranInput{
+				{Hari: 1, JamKe: 2, JamMulai: "07:30", JamSelesai: "08:10"},
+				{Hari: 1, JamKe: 1, JamMulai: "07:00", JamSelesai: "07:40"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateJam(tt.jam)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("error = %v, ingin error %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrValidation) {
+				t.Errorf("error = %v, ingin ErrValidation", err)
+			}
+			for _, j := range tt.jam {
+				if err == nil && (len(j.JamMulai) != 5 || len(j.JamSelesai) != 5) {
+					t.Errorf("jam ke-%d tidak dinormalisasi ke HH:MM: %s - %s", j.JamKe, j.JamMulai, j.JamSelesai)
+				}
+			}
+		})
+	}
+}
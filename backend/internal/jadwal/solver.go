@@ -0,0 +1,227 @@
+// file: backend/internal/jadwal/solver.go
+package jadwal
+
+import "sort"
+
+// batasLangkah membatasi jumlah langkah pencarian runut-balik. Jika batas tercapai, penyusun
+// memakai hasil parsial terbaik lalu melengkapinya secara greedy.
+const batasLangkah = 20000
+
+// penempatan adalah satu jam pelajaran hasil penyusunan otomatis.
+type penempatan struct {
+	PengajarKelasID string
+	KelasID         string
+	Hari            int
+	JamKe           int
+}
+
+type slot struct {
+	Hari  int
+	JamKe int
+}
+
+// papan mencatat jam yang sudah terisi per kelas dan per guru, serta jam ketika guru tidak
+// tersedia. Hanya jam yang bukan istirahat yang menjadi slot.
+type papan struct {
+	slot          []slot
+	indeks        map[slot]int
+	kelas         map[string][]bool
+	guru          map[string][]bool
+	tidakTersedia map[string][]bool
+}
+
+func newPapan(jam []JamPelajaran, tidakTersedia []GuruTidakTersedia) *papan {
+	p := &papan{
+		indeks:        map[slot]int{},
+		kelas:         map[string][]bool{},
+		guru:          map[string][]bool{},
+		tidakTersedia: map[string][]bool{},
+	}
+	for _, j := range jam {
+		if j.Istirahat {
+			continue
+		}
+		s := slot{Hari: j.Hari, JamKe: j.JamKe}
+		p.indeks[s] = len(p.slot)
+		p.slot = append(p.slot, s)
+	}
+	for _, g := range tidakTersedia {
+		blok := p.baris(p.tidakTersedia, g.TeacherID)
+		for i, s := range p.slot {
+			if s.Hari == g.Hari && (g.JamKe == nil || *g.JamKe == s.JamKe) {
+				blok[i] = true
+			}
+		}
+	}
+	return p
+}
+
+func (p *papan) baris(m map[string][]bool, id string) []bool {
+	b, ok := m[id]
+	if !ok {
+		b = make([]bool, len(p.slot))
+		m[id] = b
+	}
+	return b
+}
+
+func (p *papan) set(kelasID string, teacherID string, i int, v bool) {
+	p.baris(p.kelas, kelasID)[i] = v
+	p.baris(p.guru, teacherID)[i] = v
+}
+
+// kebutuhan adalah sisa jam satu pengajar kelas yang masih harus ditempatkan.
+type kebutuhan struct {
+	pengajar pengajarBeban
+	sisa     int
+	maks     int // 0 berarti tanpa batas per hari
+	perHari  map[int]int
+	hasil    []int
+}
+
+func (k *kebutuhan) bolehDi(hari int) bool {
+	return k.maks == 0 || k.perHari[hari] < k.maks
+}
+
+type langkah struct {
+	k *kebutuhan
+	i int
+}
+
+// penyusun menempatkan kebutuhan ke papan dengan runut-balik. Setiap langkah memilih
+// kebutuhan dengan ruang gerak tersempit (jumlah slot yang mungkin dikurangi sisa jam),
+// dan memprioritaskan hari yang jam mapelnya masih paling sedikit agar jam tersebar.
+type penyusun struct {
+	papan     *papan
+	kebutuhan []*kebutuhan
+	langkah   int
+	jejak     []langkah
+	terbaik   []langkah
+}
+
+func (s *penyusun) domain(k *kebutuhan) []int {
+	p := s.papan
+	kelas := p.baris(p.kelas, k.pengajar.KelasID)
+	guru := p.baris(p.guru, k.pengajar.TeacherID)
+	blok := p.baris(p.tidakTersedia, k.pengajar.TeacherID)
+	var list []int
+	for i, sl := range p.slot {
+		if !kelas[i] && !guru[i] && !blok[i] && k.bolehDi(sl.Hari) {
+			list = append(list, i)
+		}
+	}
+	return list
+}
+
+func (s *penyusun) urutkan(k *kebutuhan, dom []int) {
+	sort.SliceStable(dom, func(a, b int) bool {
+		return k.perHari[s.papan.slot[dom[a]].Hari] < k.perHari[s.papan.slot[dom[b]].Hari]
+	})
+}
+
+// pilih mengembalikan kebutuhan tersempit beserta domainnya. Nil berarti semua terpenuhi.
+func (s *penyusun) pilih() (*kebutuhan, []int) {
+	var pilihan *kebutuhan
+	var domPilihan []int
+	for _, k := range s.kebutuhan {
+		if k.sisa == 0 {
+			continue
+		}
+		dom := s.domain(k)
+		if pilihan == nil || len(dom)-k.sisa < len(domPilihan)-pilihan.sisa {
+			pilihan, domPilihan = k, dom
+		}
+	}
+	return pilihan, domPilihan
+}
+
+func (s *penyusun) tempatkan(k *kebutuhan, i int) {
+	s.papan.set(k.pengajar.KelasID, k.pengajar.TeacherID, i, true)
+	k.perHari[s.papan.slot[i].Hari]++
+	k.sisa--
+	k.hasil = append(k.hasil, i)
+	s.jejak = append(s.jejak, langkah{k: k, i: i})
+}
+
+func (s *penyusun) lepas() {
+	l := s.jejak[len(s.jejak)-1]
+	s.jejak = s.jejak[:len(s.jejak)-1]
+	s.papan.set(l.k.pengajar.KelasID, l.k.pengajar.TeacherID, l.i, false)
+	l.k.perHari[s.papan.slot[l.i].Hari]--
+	l.k.sisa++
+	l.k.hasil = l.k.hasil[:len(l.k.hasil)-1]
+}
+
+func (s *penyusun) cari() bool {
+	if s.langkah >= batasLangkah {
+		s.catatTerbaik()
+		return false
+	}
+	s.langkah++
+
+	k, dom := s.pilih()
+	if k == nil {
+		return true
+	}
+	if len(dom) < k.sisa {
+		s.catatTerbaik()
+		return false
+	}
+	s.urutkan(k, dom)
+	for _, i := range dom {
+		s.tempatkan(k, i)
+		if s.cari() {
+			return true
+		}
+		s.lepas()
+	}
+	return false
+}
+
+func (s *penyusun) catatTerbaik() {
+	if len(s.jejak) > len(s.terbaik) {
+		s.terbaik = append(s.terbaik[:0], s.jejak...)
+	}
+}
+
+// susun menjalankan pencarian. Jika tidak ditemukan jadwal lengkap, hasil parsial terbaik
+// dipasang ulang lalu sisa jam ditempatkan sebisanya.
+func (s *penyusun) susun() {
+	if s.cari() {
+		return
+	}
+	terbaik := s.terbaik
+	for len(s.jejak) > 0 {
+		s.lepas()
+	}
+	for _, l := range terbaik {
+		s.tempatkan(l.k, l.i)
+	}
+	for {
+		k, dom := s.pilihGreedy()
+		if k == nil {
+			return
+		}
+		s.urutkan(k, dom)
+		s.tempatkan(k, dom[0])
+	}
+}
+
+// pilihGreedy seperti pilih, tetapi melewati kebutuhan yang tidak lagi punya slot.
+func (s *penyusun) pilihGreedy() (*kebutuhan, []int) {
+	var pilihan *kebutuhan
+	var domPilihan []int
+	for _, k := range s.kebutuhan {
+		if k.sisa == 0 {
+			continue
+		}
+		dom := s.domain(k)
+		if len(dom) == 0 {
+			continue
+		}
+		if pilihan == nil || len(dom)-k.sisa < len(domPilihan)-pilihan.sisa {
+			pilihan, domPilihan = k, dom
+		}
+	}
+	return pilihan, domPilihan
+}
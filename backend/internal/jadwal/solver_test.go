@@ -0,0 +1,188 @@
+// file: backend/internal/jadwal/solver_test.go
+package jadwal
+
+import "testing"
+
+func TestPenyusun(t *testing.T) {
+	// jamMingguan membuat jam pelajaran hari 1..hari dengan jamKe 1..perHari. Jam ke yang
+	// disebut di istirahat menjadi jam istirahat di setiap hari.
+	jamMingguan := func(hari, perHari int, istirahat ...int) []JamPelajaran {
+		var list []JamPelajaran
+		for h := 1; h <= hari; h++ {
+			for ke := 1; ke <= perHari; ke++ {
+				j := JamPelajaran{Hari: h, JamKe: ke}
+				for _, i := range istirahat {
+					j.Istirahat = j.Istirahat || i == ke
+				}
+				list = append(list, j)
+			}
+		}
+		return list
+	}
+	ajar := func(id, kelas, guru string, jam, maks int) beban {
+		return beban{pengajar: pengajarBeban{PengajarKelasID: id, KelasID: kelas, TeacherID: guru}, jam: jam, maks: maks}
+	}
+	jamKe := func(v int) *int { return &v }
+
+	tests := []struct {
+		name          string
+		jam           []JamPelajaran
+		tidakTersedia []GuruTidakTersedia
+		beban         []beban
+		wantKurang    int            // jumlah jam yang tidak dapat dijadwalkan
+		wantSisa      map[string]int // sisa jam per pengajar kelas, jika sudah pasti
+	}{
+		{
+			name: "jadwal lengkap tanpa bentrok",
+			jam:  jamMingguan(5, 2),
+			beban: []beban{
+				ajar("x-mtk", "X", "budi", 4, 0),
+				ajar("y-mtk", "Y", "budi", 4, 0),
+				ajar("x-ipa", "X", "sari", 3, 0),
+				ajar("y-ipa", "Y", "sari", 6, 0),
+			},
+		},
+		{
+			name: "jam istirahat tidak dipakai",
+			jam:  jamMingguan(2, 3, 2),
+			beban: []beban{
+				ajar("x-mtk", "X", "budi", 4, 0),
+				ajar("x-ipa", "X", "sari", 1, 0),
+			},
+			wantKurang: 1,
+		},
+		{
+			name: "beban guru melebihi jam yang ada",
+			jam:  jamMingguan(5, 2),
+			beban: []beban{
+				ajar("x-mtk", "X", "budi", 6, 0),
+				ajar("y-mtk", "Y", "budi", 6, 0),
+			},
+			wantKurang: 2,
+		},
+		{
+			name:          "guru tidak tersedia sehari penuh",
+			jam:           jamMingguan(3, 2),
+			tidakTersedia: []GuruTidakTersedia{{TeacherID: "budi", Hari: 1}},
+			beban:         []beban{ajar("x-mtk", "X", "budi", 5, 0)},
+			wantKurang:    1,
+			wantSisa:      map[string]int{"x-mtk": 1},
+		},
+		{
+			name:          "guru tidak tersedia satu jam",
+			jam:           jamMingguan(2, 2),
+			tidakTersedia: []GuruTidakTersedia{{TeacherID: "budi", Hari: 2, JamKe: jamKe(1)}},
+			beban: []beban{
+				ajar("x-mtk", "X", "budi", 3, 0),
+				ajar("x-ipa", "X", "sari", 1, 0),
+			},
+		},
+		{
+			name:       "maksimal jam per hari membatasi penempatan",
+			jam:        jamMingguan(3, 3),
+			beban:      []beban{ajar("x-mtk", "X", "budi", 5, 1)},
+			wantKurang: 2,
+			wantSisa:   map[string]int{"x-mtk": 2},
+		},
+		{
+			name: "runut-balik menemukan susunan yang tidak ditemukan greedy",
+			// Sari hanya bisa mengajar di jam ke-1 hari 1, sehingga Budi harus memberi jalan.
+			jam: jamMingguan(1, 2),
+			tidakTersedia: []GuruTidakTersedia{
+				{TeacherID: "sari", Hari: 1, JamKe: jamKe(2)},
+			},
+			beban: []beban{
+				ajar("x-mtk", "X", "budi", 1, 0),
+				ajar("x-ipa", "X", "sari", 1, 0),
+			},
+		},
+		{
+			name: "kelas penuh menyisakan kekurangan",
+			jam:  jamMingguan(2, 2),
+			beban: []beban{
+				ajar("x-mtk", "X", "budi", 3, 0),
+				ajar("x-ipa", "X", "sari", 2, 0),
+			},
+			wantKurang: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPapan(tt.jam, tt.tidakTersedia)
+			var list []*kebutuhan
+			for _, b := range tt.beban {
+				list = append(list, &kebutuhan{pengajar: b.pengajar, sisa: b.jam, maks: b.maks, perHari: map[int]int{}})
+			}
+			sv := &penyusun{papan: p, kebutuhan: list}
+			sv.susun()
+
+			periksaPapan(t, p, tt.jam, tt.tidakTersedia, tt.beban, list)
+
+			var kurang int
+			for _, k := range list {
+				kurang += k.sisa
+				if want, ok := tt.wantSisa[k.pengajar.PengajarKelasID]; ok && k.sisa != want {
+					t.Errorf("sisa %s = %d, ingin %d", k.pengajar.PengajarKelasID, k.sisa, want)
+				}
+			}
+			if kurang != tt.wantKurang {
+				t.Errorf("jam tidak terjadwal = %d, ingin %d", kurang, tt.wantKurang)
+			}
+		})
+	}
+}
+
+// beban adalah masukan satu pengajar kelas pada TestPenyusun.
+type beban struct {
+	pengajar pengajarBeban
+	jam      int
+	maks     int
+}
+
+// periksaPapan memastikan hasil penyusunan tidak bentrok per kelas maupun per guru, tidak
+// memakai jam istirahat atau jam guru tidak tersedia, dan mematuhi batas jam per hari.
+func periksaPapan(t *testing.T, p *papan, jam []JamPelajaran, tidakTersedia []GuruTidakTersedia, bebanList []beban, list []*kebutuhan) {
+	t.Helper()
+	istirahat := map[slot]bool{}
+	for _, j := range jam {
+		istirahat[slot{Hari: j.Hari, JamKe: j.JamKe}] = j.Istirahat
+	}
+	type terisi struct {
+		id string
+		sl slot
+	}
+	kelas := map[terisi]bool{}
+	guru := map[terisi]bool{}
+	for n, k := range list {
+		if got := len(k.hasil) + k.sisa; got != bebanList[n].jam {
+			t.Errorf("%s: terjadwal %d + sisa %d tidak sama dengan beban %d", k.pengajar.PengajarKelasID, len(k.hasil), k.sisa, bebanList[n].jam)
+		}
+		perHari := map[int]int{}
+		for _, i := range k.hasil {
+			sl := p.slot[i]
+			if istirahat[sl] {
+				t.Errorf("%s ditempatkan di jam istirahat %+v", k.pengajar.PengajarKelasID, sl)
+			}
+			for _, g := range tidakTersedia {
+				if g.TeacherID == k.pengajar.TeacherID && g.Hari == sl.Hari && (g.JamKe == nil || *g.JamKe == sl.JamKe) {
+					t.Errorf("%s ditempatkan saat guru tidak tersedia %+v", k.pengajar.PengajarKelasID, sl)
+				}
+			}
+			kk, kg := terisi{k.pengajar.KelasID, sl}, terisi{k.pengajar.TeacherID, sl}
+			if kelas[kk] {
+				t.Errorf("kelas %s bentrok di %+v", k.pengajar.KelasID, sl)
+			}
+			if guru[kg] {
+				t.Errorf("guru %s bentrok di %+v", k.pengajar.TeacherID, sl)
+			}
+			kelas[kk], guru[kg] = true, true
+			perHari[sl.Hari]++
+		}
+		for h, n := range perHari {
+			if k.maks > 0 && n > k.maks {
+				t.Errorf("%s: %d jam di hari %d, maksimal %d", k.pengajar.PengajarKelasID, n, h, k.maks)
+			}
+		}
+	}
+}
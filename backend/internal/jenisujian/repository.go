@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"skoola/pkg/database"
 )
 
 // Repository mendefinisikan interface untuk interaksi database.
@@ -26,29 +27,31 @@ func NewRepository(db *sql.DB) Repository {
 }
 
 func (r *postgresRepository) Create(ctx context.Context, schemaName string, input UpsertJenisUjianInput) (*JenisUjian, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `INSERT INTO jenis_ujian (kode_ujian, nama_ujian) VALUES ($1, $2) RETURNING id, kode_ujian, nama_ujian, created_at, updated_at`
-	row := r.db.QueryRowContext(ctx, query, input.KodeUjian, input.NamaUjian)
+	row := tx.QueryRowContext(ctx, query, input.KodeUjian, input.NamaUjian)
 
 	var j JenisUjian
 	if err := row.Scan(&j.ID, &j.KodeUjian, &j.NamaUjian, &j.CreatedAt, &j.UpdatedAt); err != nil {
 		return nil, fmt.Errorf("gagal memindai data jenis ujian setelah dibuat: %w", err)
 	}
-	return &j, nil
+	return &j, tx.Commit()
 }
 
 func (r *postgresRepository) GetAll(ctx context.Context, schemaName string) ([]JenisUjian, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `SELECT id, kode_ujian, nama_ujian, created_at, updated_at FROM jenis_ujian ORDER BY nama_ujian ASC`
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := tx.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("gagal query get all jenis ujian: %w", err)
 	}
@@ -62,37 +65,42 @@ func (r *postgresRepository) GetAll(ctx context.Context, schemaName string) ([]J
 		}
 		list = append(list, j)
 	}
-	return list, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
 }
 
 func (r *postgresRepository) GetByID(ctx context.Context, schemaName string, id int) (*JenisUjian, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `SELECT id, kode_ujian, nama_ujian, created_at, updated_at FROM jenis_ujian WHERE id = $1`
-	row := r.db.QueryRowContext(ctx, query, id)
+	row := tx.QueryRowContext(ctx, query, id)
 
 	var j JenisUjian
-	err := row.Scan(&j.ID, &j.KodeUjian, &j.NamaUjian, &j.CreatedAt, &j.UpdatedAt)
+	err = row.Scan(&j.ID, &j.KodeUjian, &j.NamaUjian, &j.CreatedAt, &j.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
 		return nil, fmt.Errorf("gagal memindai data jenis ujian by id: %w", err)
 	}
-	return &j, nil
+	return &j, tx.Commit()
 }
 
 func (r *postgresRepository) Update(ctx context.Context, schemaName string, id int, input UpsertJenisUjianInput) error {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 
 	query := `UPDATE jenis_ujian SET kode_ujian = $1, nama_ujian = $2, updated_at = NOW() WHERE id = $3`
-	result, err := r.db.ExecContext(ctx, query, input.KodeUjian, input.NamaUjian, id)
+	result, err := tx.ExecContext(ctx, query, input.KodeUjian, input.NamaUjian, id)
 	if err != nil {
 		return fmt.Errorf("gagal mengeksekusi query update: %w", err)
 	}
@@ -104,17 +112,18 @@ func (r *postgresRepository) Update(ctx context.Context, schemaName string, id i
 	if rowsAffected == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+	return tx.Commit()
 }
 
 func (r *postgresRepository) Delete(ctx context.Context, schemaName string, id int) error {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 
 	query := `DELETE FROM jenis_ujian WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := tx.ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("gagal mengeksekusi query delete: %w", err)
 	}
@@ -126,5 +135,5 @@ func (r *postgresRepository) Delete(ctx context.Context, schemaName string, id i
 	if rowsAffected == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+	return tx.Commit()
 }
@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"skoola/pkg/database"
 )
 
 // Repository mendefinisikan interface untuk interaksi database.
@@ -26,29 +27,31 @@ func NewRepository(db *sql.DB) Repository {
 }
 
 func (r *postgresRepository) Create(ctx context.Context, schemaName string, input UpsertJenjangInput) (*JenjangPendidikan, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `INSERT INTO jenjang_pendidikan (nama_jenjang) VALUES ($1) RETURNING id, nama_jenjang, created_at, updated_at`
-	row := r.db.QueryRowContext(ctx, query, input.NamaJenjang)
+	row := tx.QueryRowContext(ctx, query, input.NamaJenjang)
 
 	var jenjang JenjangPendidikan
 	if err := row.Scan(&jenjang.ID, &jenjang.NamaJenjang, &jenjang.CreatedAt, &jenjang.UpdatedAt); err != nil {
 		return nil, fmt.Errorf("gagal memindai data jenjang setelah dibuat: %w", err)
 	}
-	return &jenjang, nil
+	return &jenjang, tx.Commit()
 }
 
 func (r *postgresRepository) GetAll(ctx context.Context, schemaName string) ([]JenjangPendidikan, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `SELECT id, nama_jenjang, created_at, updated_at FROM jenjang_pendidikan ORDER BY nama_jenjang ASC`
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := tx.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("gagal query get all jenjang: %w", err)
 	}
@@ -62,37 +65,42 @@ func (r *postgresRepository) GetAll(ctx context.Context, schemaName string) ([]J
 		}
 		jenjangList = append(jenjangList, j)
 	}
-	return jenjangList, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return jenjangList, tx.Commit()
 }
 
 func (r *postgresRepository) GetByID(ctx context.Context, schemaName string, id int) (*JenjangPendidikan, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `SELECT id, nama_jenjang, created_at, updated_at FROM jenjang_pendidikan WHERE id = $1`
-	row := r.db.QueryRowContext(ctx, query, id)
+	row := tx.QueryRowContext(ctx, query, id)
 
 	var j JenjangPendidikan
-	err := row.Scan(&j.ID, &j.NamaJenjang, &j.CreatedAt, &j.UpdatedAt)
+	err = row.Scan(&j.ID, &j.NamaJenjang, &j.CreatedAt, &j.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // Data tidak ditemukan, bukan error
 		}
 		return nil, fmt.Errorf("gagal memindai data jenjang by id: %w", err)
 	}
-	return &j, nil
+	return &j, tx.Commit()
 }
 
 func (r *postgresRepository) Update(ctx context.Context, schemaName string, id int, input UpsertJenjangInput) error {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 
 	query := `UPDATE jenjang_pendidikan SET nama_jenjang = $1, updated_at = NOW() WHERE id = $2`
-	result, err := r.db.ExecContext(ctx, query, input.NamaJenjang, id)
+	result, err := tx.ExecContext(ctx, query, input.NamaJenjang, id)
 	if err != nil {
 		return fmt.Errorf("gagal mengeksekusi query update: %w", err)
 	}
@@ -104,17 +112,18 @@ func (r *postgresRepository) Update(ctx context.Context, schemaName string, id i
 	if rowsAffected == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+	return tx.Commit()
 }
 
 func (r *postgresRepository) Delete(ctx context.Context, schemaName string, id int) error {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 
 	query := `DELETE FROM jenjang_pendidikan WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := tx.ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("gagal mengeksekusi query delete: %w", err)
 	}
@@ -126,5 +135,5 @@ func (r *postgresRepository) Delete(ctx context.Context, schemaName string, id i
 	if rowsAffected == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+	return tx.Commit()
 }
@@ -0,0 +1,104 @@
+// file: backend/internal/jurnalmengajar/export.go
+package jurnalmengajar
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	sheetGuru     = "Rekap Guru"
+	sheetPengajar = "Rincian per Kelas"
+)
+
+// renderExcel menulis rekap per guru ke satu sheet dan rinciannya per kelas dan mapel ke
+// sheet kedua. Kolom jam dipisah per status kehadiran untuk keperluan penggajian.
+func renderExcel(rekap *RekapBulanan) ([]byte, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	kepalaStyle, _ := f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true},
+		Fill:      excelize.Fill{Type: "pattern", Color: []string{"#E6E6E6"}, Pattern: 1},
+		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center", WrapText: true},
+	})
+	judul := "REKAP JURNAL MENGAJAR BULAN " + rekap.Bulan
+
+	index, err := f.NewSheet(sheetGuru)
+	if err != nil {
+		return nil, err
+	}
+	kepala := []string{"No", "Nama Guru", "NIP/NUPTK", "Pertemuan", "Jumlah Jam", "Jam Hadir", "Jam Tugas",
+		"Jam Izin", "Jam Sakit", "Jam Dinas", "% Hadir"}
+	baris := tulisJudul(f, sheetGuru, judul, kepala, kepalaStyle)
+	for i, g := range rekap.Guru {
+		nip := ""
+		if g.NIPNUPTK != nil {
+			nip = *g.NIPNUPTK
+		}
+		row := []interface{}{i + 1, g.NamaGuru, nip, g.JumlahPertemuan, g.JumlahJam, g.Jam.Hadir, g.Jam.Tugas,
+			g.Jam.Izin, g.Jam.Sakit, g.Jam.Dinas, g.PersentaseHadir}
+		cell, _ := excelize.CoordinatesToCellName(1, baris+i)
+		if err := f.SetSheetRow(sheetGuru, cell, &row); err != nil {
+			return nil, err
+		}
+	}
+	f.SetColWidth(sheetGuru, "B", "B", 30)
+	f.SetColWidth(sheetGuru, "C", "C", 20)
+
+	if _, err := f.NewSheet(sheetPengajar); err != nil {
+		return nil, err
+	}
+	kepala = []string{"No", "Nama Guru", "Kelas", "Mata Pelajaran", "Pertemuan", "Jumlah Jam", "Jam Hadir",
+		"Jam Tugas", "Jam Izin", "Jam Sakit", "Jam Dinas"}
+	baris = tulisJudul(f, sheetPengajar, judul, kepala, kepalaStyle)
+	no := 0
+	for _, g := range rekap.Guru {
+		for _, p := range g.Pengajar {
+			no++
+			row := []interface{}{no, g.NamaGuru, p.NamaKelas, p.NamaMapel, p.JumlahPertemuan, p.JumlahJam, p.Jam.Hadir,
+				p.Jam.Tugas, p.Jam.Izin, p.Jam.Sakit, p.Jam.Dinas}
+			cell, _ := excelize.CoordinatesToCellName(1, baris+no-1)
+			if err := f.SetSheetRow(sheetPengajar, cell, &row); err != nil {
+				return nil, err
+			}
+		}
+	}
+	f.SetColWidth(sheetPengajar, "B", "B", 30)
+	f.SetColWidth(sheetPengajar, "C", "D", 20)
+
+	f.SetActiveSheet(index)
+	f.DeleteSheet("Sheet1")
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, fmt.Errorf("gagal menulis file excel: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// tulisJudul menulis judul dan baris kepala, lalu mengembalikan nomor baris data pertama.
+func tulisJudul(f *excelize.File, sheet string, judul string, kepala []string, style int) int {
+	akhir, _ := excelize.CoordinatesToCellName(len(kepala), 1)
+	f.MergeCell(sheet, "A1", akhir)
+	f.SetCellValue(sheet, "A1", judul)
+	f.SetCellStyle(sheet, "A1", "A1", style)
+
+	barisKepala := 3
+	for i, h := range kepala {
+		cell, _ := excelize.CoordinatesToCellName(i+1, barisKepala)
+		f.SetCellValue(sheet, cell, h)
+	}
+	awal, _ := excelize.CoordinatesToCellName(1, barisKepala)
+	ujung, _ := excelize.CoordinatesToCellName(len(kepala), barisKepala)
+	f.SetCellStyle(sheet, awal, ujung, style)
+	f.SetPanes(sheet, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      barisKepala,
+		TopLeftCell: fmt.Sprintf("A%d", barisKepala+1),
+		ActivePane:  "bottomLeft",
+	})
+	return barisKepala + 1
+}
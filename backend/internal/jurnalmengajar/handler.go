@@ -0,0 +1,160 @@
+// file: backend/internal/jurnalmengajar/handler.go
+package jurnalmengajar
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"skoola/internal/access"
+	"skoola/internal/middleware"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{service: s}
+}
+
+// GetByPengajar adalah handler untuk GET /jurnal-mengajar/pengajar/{pengajarKelasID}?dari=...&sampai=...
+func (h *Handler) GetByPengajar(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	q := r.URL.Query()
+
+	list, err := h.service.GetByPengajar(r.Context(), schemaName, access.ActorFromContext(r.Context()), chi.URLParam(r, "pengajarKelasID"), q.Get("dari"), q.Get("sampai"))
+	if err != nil {
+		writeError(w, "Gagal mengambil jurnal mengajar: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// GetPerbandingan adalah handler untuk GET /jurnal-mengajar/pengajar/{pengajarKelasID}/perbandingan.
+func (h *Handler) GetPerbandingan(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	hasil, err := h.service.GetPerbandingan(r.Context(), schemaName, access.ActorFromContext(r.Context()), chi.URLParam(r, "pengajarKelasID"))
+	if err != nil {
+		writeError(w, "Gagal membandingkan rencana pembelajaran: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hasil)
+}
+
+// GetByID adalah handler untuk GET /jurnal-mengajar/{id}.
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	jurnal, err := h.service.GetByID(r.Context(), schemaName, access.ActorFromContext(r.Context()), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, "Gagal mengambil jurnal mengajar: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(jurnal)
+}
+
+// Create adalah handler untuk POST /jurnal-mengajar.
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	var input UpsertJurnalInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	jurnal, err := h.service.Create(r.Context(), schemaName, access.ActorFromContext(r.Context()), input)
+	if err != nil {
+		writeError(w, "Gagal menyimpan jurnal mengajar: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(jurnal)
+}
+
+// Update adalah handler untuk PUT /jurnal-mengajar/{id}.
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	var input UpsertJurnalInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	jurnal, err := h.service.Update(r.Context(), schemaName, access.ActorFromContext(r.Context()), chi.URLParam(r, "id"), input)
+	if err != nil {
+		writeError(w, "Gagal memperbarui jurnal mengajar: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(jurnal)
+}
+
+// Delete adalah handler untuk DELETE /jurnal-mengajar/{id}.
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	if err := h.service.Delete(r.Context(), schemaName, access.ActorFromContext(r.Context()), chi.URLParam(r, "id")); err != nil {
+		writeError(w, "Gagal menghapus jurnal mengajar: ", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetRekapBulanan adalah handler untuk GET /jurnal-mengajar/rekap?bulan=YYYY-MM&teacher_id=...
+// Rekap dikirim sebagai file jika ?format=xlsx diisi.
+func (h *Handler) GetRekapBulanan(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	q := r.URL.Query()
+
+	rekap, err := h.service.GetRekapBulanan(r.Context(), schemaName, q.Get("bulan"), q.Get("teacher_id"))
+	if err != nil {
+		writeError(w, "Gagal mengambil rekap jurnal mengajar: ", err)
+		return
+	}
+	format := q.Get("format")
+	if format == "" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(rekap)
+		return
+	}
+
+	content, filename, err := h.service.ExportRekap(rekap, format)
+	if err != nil {
+		writeError(w, "Gagal mengekspor rekap jurnal mengajar: ", err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	w.Write(content)
+}
+
+func writeError(w http.ResponseWriter, prefix string, err error) {
+	switch {
+	case errors.Is(err, access.ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, ErrValidation):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Data tidak ditemukan", http.StatusNotFound)
+	default:
+		http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+	}
+}
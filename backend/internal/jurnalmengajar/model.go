@@ -0,0 +1,176 @@
+// file: backend/internal/jurnalmengajar/model.go
+package jurnalmengajar
+
+import "time"
+
+// Status kehadiran guru pada satu pertemuan.
+const (
+	StatusHadir = "HADIR"
+	StatusTugas = "TUGAS" // guru tidak hadir tetapi meninggalkan tugas
+	StatusIzin  = "IZIN"
+	StatusSakit = "SAKIT"
+	StatusDinas = "DINAS"
+)
+
+// FormatXLSX adalah satu-satunya format ekspor rekap jurnal.
+const FormatXLSX = "xlsx"
+
+// JurnalMengajar adalah catatan satu pertemuan seorang pengajar mapel di kelasnya.
+type JurnalMengajar struct {
+	ID                   string         `json:"id"`
+	PengajarKelasID      string         `json:"pengajar_kelas_id"`
+	NamaKelas            string         `json:"nama_kelas"`
+	NamaMapel            string         `json:"nama_mapel"`
+	NamaGuru             string         `json:"nama_guru"`
+	Tanggal              time.Time      `json:"tanggal"`
+	JamKeMulai           int            `json:"jam_ke_mulai"`
+	JamKeSelesai         int            `json:"jam_ke_selesai"`
+	StatusKehadiran      string         `json:"status_kehadiran"`
+	MateriPembelajaranID *int           `json:"materi_pembelajaran_id"`
+	NamaMateri           *string        `json:"nama_materi"`
+	MateriLain           *string        `json:"materi_lain"`
+	Catatan              *string        `json:"catatan"`
+	Tujuan               []TujuanJurnal `json:"tujuan_pembelajaran"`
+	SiswaAbsen           []SiswaAbsen   `json:"siswa_absen"`
+	DicatatOleh          *string        `json:"dicatat_oleh"`
+	CreatedAt            time.Time      `json:"created_at"`
+	UpdatedAt            time.Time      `json:"updated_at"`
+}
+
+// TujuanJurnal adalah tujuan pembelajaran yang dibahas pada satu pertemuan.
+type TujuanJurnal struct {
+	TujuanPembelajaranID int    `json:"tujuan_pembelajaran_id"`
+	DeskripsiTujuan      string `json:"deskripsi_tujuan"`
+}
+
+// SiswaAbsen adalah siswa yang tidak hadir pada satu pertemuan.
+type SiswaAbsen struct {
+	AnggotaKelasID string  `json:"anggota_kelas_id"`
+	NamaSiswa      string  `json:"nama_siswa"`
+	Status         string  `json:"status"`
+	Catatan        *string `json:"catatan"`
+}
+
+// UpsertJurnalInput adalah DTO untuk membuat atau memperbarui jurnal. JamKeSelesai boleh
+// kosong untuk pertemuan satu jam, dan StatusKehadiran bawaannya HADIR. Materi, tujuan, dan
+// siswa absen hanya boleh diisi jika pertemuan berlangsung (HADIR atau TUGAS).
+type UpsertJurnalInput struct {
+	PengajarKelasID       string            `json:"pengajar_kelas_id" validate:"required,uuid"`
+	Tanggal               string            `json:"tanggal" validate:"required,datetime=2006-01-02"`
+	JamKeMulai            int               `json:"jam_ke_mulai" validate:"required,min=1,max=20"`
+	JamKeSelesai          int               `json:"jam_ke_selesai" validate:"omitempty,min=1,max=20"`
+	StatusKehadiran       string            `json:"status_kehadiran" validate:"omitempty,oneof=HADIR TUGAS IZIN SAKIT DINAS"`
+	MateriPembelajaranID  *int              `json:"materi_pembelajaran_id"`
+	TujuanPembelajaranIDs []int             `json:"tujuan_pembelajaran_ids"`
+	MateriLain            *string           `json:"materi_lain"`
+	Catatan               *string           `json:"catatan"`
+	SiswaAbsen            []SiswaAbsenInput `json:"siswa_absen" validate:"dive"`
+}
+
+// SiswaAbsenInput adalah item siswa absen pada UpsertJurnalInput.
+type SiswaAbsenInput struct {
+	AnggotaKelasID string  `json:"anggota_kelas_id" validate:"required,uuid"`
+	Status         string  `json:"status" validate:"required,oneof=S I A"`
+	Catatan        *string `json:"catatan"`
+}
+
+// Perbandingan membandingkan rencana pembelajaran satu pengajar kelas dengan materi dan
+// tujuan pembelajaran yang tercatat di jurnal.
+type Perbandingan struct {
+	PengajarKelasID  string            `json:"pengajar_kelas_id"`
+	NamaKelas        string            `json:"nama_kelas"`
+	NamaMapel        string            `json:"nama_mapel"`
+	JumlahPertemuan  int               `json:"jumlah_pertemuan"`
+	JumlahTujuan     int               `json:"jumlah_tujuan"`
+	TujuanTerlaksana int               `json:"tujuan_terlaksana"`
+	Persentase       float64           `json:"persentase"`
+	Materi           []MateriRealisasi `json:"materi"`
+	// DiLuarRencana adalah pertemuan yang mengisi materi_lain tanpa materi dari rencana.
+	DiLuarRencana []PertemuanLain `json:"di_luar_rencana"`
+}
+
+// MateriRealisasi adalah satu materi rencana beserta pertemuan yang membahasnya.
+type MateriRealisasi struct {
+	MateriPembelajaranID int               `json:"materi_pembelajaran_id"`
+	NamaMateri           string            `json:"nama_materi"`
+	Urutan               int               `json:"urutan"`
+	JumlahPertemuan      int               `json:"jumlah_pertemuan"`
+	PertamaDiajarkan     *time.Time        `json:"pertama_diajarkan"`
+	TerakhirDiajarkan    *time.Time        `json:"terakhir_diajarkan"`
+	Tujuan               []TujuanRealisasi `json:"tujuan_pembelajaran"`
+}
+
+// TujuanRealisasi adalah satu tujuan pembelajaran rencana beserta realisasinya.
+type TujuanRealisasi struct {
+	TujuanPembelajaranID int        `json:"tujuan_pembelajaran_id"`
+	DeskripsiTujuan      string     `json:"deskripsi_tujuan"`
+	Urutan               int        `json:"urutan"`
+	JumlahPertemuan      int        `json:"jumlah_pertemuan"`
+	TerakhirDiajarkan    *time.Time `json:"terakhir_diajarkan"`
+	Terlaksana           bool       `json:"terlaksana"`
+}
+
+// PertemuanLain adalah pertemuan yang materinya tidak ada di rencana pembelajaran.
+type PertemuanLain struct {
+	JurnalMengajarID string    `json:"jurnal_mengajar_id"`
+	Tanggal          time.Time `json:"tanggal"`
+	MateriLain       string    `json:"materi_lain"`
+}
+
+// JamKehadiran adalah banyaknya jam pelajaran per status kehadiran guru.
+type JamKehadiran struct {
+	Hadir int `json:"hadir"`
+	Tugas int `json:"tugas"`
+	Izin  int `json:"izin"`
+	Sakit int `json:"sakit"`
+	Dinas int `json:"dinas"`
+}
+
+// RekapBulanan adalah rekap jurnal seluruh guru (atau satu guru) dalam satu bulan.
+type RekapBulanan struct {
+	Bulan string      `json:"bulan"` // YYYY-MM
+	Guru  []RekapGuru `json:"guru"`
+}
+
+// RekapGuru adalah rekap jurnal satu guru. PersentaseHadir adalah jam HADIR dibagi JumlahJam.
+type RekapGuru struct {
+	TeacherID       string          `json:"teacher_id"`
+	NamaGuru        string          `json:"nama_guru"`
+	NIPNUPTK        *string         `json:"nip_nuptk"`
+	JumlahPertemuan int             `json:"jumlah_pertemuan"`
+	JumlahJam       int             `json:"jumlah_jam"`
+	Jam             JamKehadiran    `json:"jam"`
+	PersentaseHadir float64         `json:"persentase_hadir"`
+	Pengajar        []RekapPengajar `json:"pengajar"`
+}
+
+// RekapPengajar adalah rekap jurnal satu guru di satu kelas dan mapel.
+type RekapPengajar struct {
+	PengajarKelasID string       `json:"pengajar_kelas_id"`
+	NamaKelas       string       `json:"nama_kelas"`
+	NamaMapel       string       `json:"nama_mapel"`
+	JumlahPertemuan int          `json:"jumlah_pertemuan"`
+	JumlahJam       int          `json:"jumlah_jam"`
+	Jam             JamKehadiran `json:"jam"`
+}
+
+// pengajarInfo adalah kelas, mapel, dan guru pemilik satu pengajar_kelas.
+type pengajarInfo struct {
+	KelasID   string
+	TeacherID string
+	NamaKelas string
+	NamaMapel string
+}
+
+// barisRekap adalah jumlah pertemuan dan jam per pengajar kelas dan status kehadiran.
+type barisRekap struct {
+	TeacherID       string
+	NamaGuru        string
+	NIPNUPTK        *string
+	PengajarKelasID string
+	NamaKelas       string
+	NamaMapel       string
+	Status          string
+	JumlahPertemuan int
+	JumlahJam       int
+}
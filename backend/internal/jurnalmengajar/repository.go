@@ -0,0 +1,581 @@
+// file: backend/internal/jurnalmengajar/repository.go
+package jurnalmengajar
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"skoola/pkg/database"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Repository mendefinisikan interface untuk interaksi database jurnal mengajar.
+type Repository interface {
+	// GetPengajarInfo mengembalikan nil jika pengajar_kelas tidak ditemukan.
+	GetPengajarInfo(ctx context.Context, schemaName string, pengajarKelasID string) (*pengajarInfo, error)
+	// GetByPengajar mengembalikan jurnal satu pengajar kelas, diurutkan per tanggal dan jam.
+	// dari dan sampai (inklusif) boleh nil.
+	GetByPengajar(ctx context.Context, schemaName string, pengajarKelasID string, dari *time.Time, sampai *time.Time) ([]JurnalMengajar, error)
+	// GetByID mengembalikan nil jika jurnal tidak ditemukan.
+	GetByID(ctx context.Context, schemaName string, id string) (*JurnalMengajar, error)
+	// IsJamBentrok memeriksa apakah rentang jam tersebut sudah terisi jurnal lain di kelas
+	// yang sama atau milik guru yang sama pada tanggal tersebut. excludeID boleh kosong.
+	IsJamBentrok(ctx context.Context, schemaName string, info pengajarInfo, tanggal time.Time, jamMulai int, jamSelesai int, excludeID string) (bool, error)
+	// IsRencanaValid memeriksa bahwa materi dan semua tujuan pembelajaran adalah milik
+	// pengajar kelas tersebut, dan jika materi diisi, semua tujuan berada di materi itu.
+	IsRencanaValid(ctx context.Context, schemaName string, pengajarKelasID string, materiID *int, tujuanIDs []int) (bool, error)
+	// IsAnggotaKelas memeriksa bahwa semua anggota kelas berada di kelas tersebut.
+	IsAnggotaKelas(ctx context.Context, schemaName string, kelasID string, anggotaKelasIDs []string) (bool, error)
+	Create(ctx context.Context, schemaName string, input UpsertJurnalInput, tanggal time.Time, dicatatOleh string) (string, error)
+	Update(ctx context.Context, schemaName string, id string, input UpsertJurnalInput, tanggal time.Time) error
+	Delete(ctx context.Context, schemaName string, id string) error
+	// GetRencana mengembalikan materi dan tujuan pembelajaran satu pengajar kelas sesuai
+	// urutan rencana, beserta jumlah pertemuan yang membahasnya.
+	GetRencana(ctx context.Context, schemaName string, pengajarKelasID string) ([]MateriRealisasi, error)
+	GetDiLuarRencana(ctx context.Context, schemaName string, pengajarKelasID string) ([]PertemuanLain, error)
+	CountPertemuan(ctx context.Context, schemaName string, pengajarKelasID string) (int, error)
+	// GetRekap menghitung pertemuan dan jam per pengajar kelas dan status kehadiran pada
+	// rentang [dari, sampai]. teacherID boleh kosong untuk seluruh guru.
+	GetRekap(ctx context.Context, schemaName string, dari time.Time, sampai time.Time, teacherID string) ([]barisRekap, error)
+}
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewRepository membuat instance baru dari postgresRepository.
+func NewRepository(db *sql.DB) Repository {
+	return &postgresRepository{db: db}
+}
+
+const selectJurnal = `
+	SELECT j.id, j.pengajar_kelas_id, k.nama_kelas, mp.nama_mapel, t.nama_lengkap, j.tanggal,
+		j.jam_ke_mulai, j.jam_ke_selesai, j.status_kehadiran, j.materi_pembelajaran_id, m.nama_materi,
+		j.materi_lain, j.catatan, j.dicatat_oleh, j.created_at, j.updated_at
+	FROM jurnal_mengajar j
+	JOIN pengajar_kelas pk ON j.pengajar_kelas_id = pk.id
+	JOIN kelas k ON pk.kelas_id = k.id
+	JOIN mata_pelajaran mp ON pk.mata_pelajaran_id = mp.id
+	JOIN teachers t ON pk.teacher_id = t.id
+	LEFT JOIN materi_pembelajaran m ON j.materi_pembelajaran_id = m.id
+`
+
+func (r *postgresRepository) GetPengajarInfo(ctx context.Context, schemaName string, pengajarKelasID string) (*pengajarInfo, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var info pengajarInfo
+	query := `
+		SELECT pk.kelas_id, pk.teacher_id, k.nama_kelas, mp.nama_mapel
+		FROM pengajar_kelas pk
+		JOIN kelas k ON pk.kelas_id = k.id
+		JOIN mata_pelajaran mp ON pk.mata_pelajaran_id = mp.id
+		WHERE pk.id = $1
+	`
+	if err := tx.QueryRowContext(ctx, query, pengajarKelasID).Scan(&info.KelasID, &info.TeacherID, &info.NamaKelas, &info.NamaMapel); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("gagal mengambil data pengajar kelas: %w", err)
+	}
+	return &info, tx.Commit()
+}
+
+func (r *postgresRepository) GetByPengajar(ctx context.Context, schemaName string, pengajarKelasID string, dari *time.Time, sampai *time.Time) ([]JurnalMengajar, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := selectJurnal + `
+		WHERE j.pengajar_kelas_id = $1
+			AND ($2::date IS NULL OR j.tanggal >= $2::date)
+			AND ($3::date IS NULL OR j.tanggal <= $3::date)
+		ORDER BY j.tanggal ASC, j.jam_ke_mulai ASC
+	`
+	rows, err := tx.QueryContext(ctx, query, pengajarKelasID, dari, sampai)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil jurnal mengajar: %w", err)
+	}
+	list := []JurnalMengajar{}
+	for rows.Next() {
+		j, err := scanJurnal(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		list = append(list, *j)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := loadDetail(ctx, tx, list); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+func (r *postgresRepository) GetByID(ctx context.Context, schemaName string, id string) (*JurnalMengajar, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	j, err := scanJurnal(tx.QueryRowContext(ctx, selectJurnal+" WHERE j.id = $1", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	list := []JurnalMengajar{*j}
+	if err := loadDetail(ctx, tx, list); err != nil {
+		return nil, err
+	}
+	return &list[0], tx.Commit()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanJurnal(row scanner) (*JurnalMengajar, error) {
+	var j JurnalMengajar
+	err := row.Scan(&j.ID, &j.PengajarKelasID, &j.NamaKelas, &j.NamaMapel, &j.NamaGuru, &j.Tanggal,
+		&j.JamKeMulai, &j.JamKeSelesai, &j.StatusKehadiran, &j.MateriPembelajaranID, &j.NamaMateri,
+		&j.MateriLain, &j.Catatan, &j.DicatatOleh, &j.CreatedAt, &j.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gagal memindai jurnal mengajar: %w", err)
+	}
+	j.Tujuan = []TujuanJurnal{}
+	j.SiswaAbsen = []SiswaAbsen{}
+	return &j, nil
+}
+
+// loadDetail mengisi tujuan pembelajaran dan siswa absen untuk setiap jurnal di list.
+func loadDetail(ctx context.Context, tx *sql.Tx, list []JurnalMengajar) error {
+	if len(list) == 0 {
+		return nil
+	}
+	index := make(map[string]int, len(list))
+	ids := make([]string, len(list))
+	for i, j := range list {
+		index[j.ID] = i
+		ids[i] = j.ID
+	}
+
+	query := `
+		SELECT jt.jurnal_mengajar_id, tp.id, tp.deskripsi_tujuan
+		FROM jurnal_mengajar_tujuan jt
+		JOIN tujuan_pembelajaran tp ON jt.tujuan_pembelajaran_id = tp.id
+		WHERE jt.jurnal_mengajar_id = ANY($1)
+		ORDER BY tp.urutan ASC, tp.id ASC
+	`
+	rows, err := tx.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("gagal mengambil tujuan pembelajaran jurnal: %w", err)
+	}
+	for rows.Next() {
+		var jurnalID string
+		var t TujuanJurnal
+		if err := rows.Scan(&jurnalID, &t.TujuanPembelajaranID, &t.DeskripsiTujuan); err != nil {
+			rows.Close()
+			return fmt.Errorf("gagal memindai tujuan pembelajaran jurnal: %w", err)
+		}
+		j := &list[index[jurnalID]]
+		j.Tujuan = append(j.Tujuan, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	query = `
+		SELECT ja.jurnal_mengajar_id, ja.anggota_kelas_id, s.nama_lengkap, ja.status, ja.catatan
+		FROM jurnal_mengajar_absen ja
+		JOIN anggota_kelas ak ON ja.anggota_kelas_id = ak.id
+		JOIN students s ON ak.student_id = s.id
+		WHERE ja.jurnal_mengajar_id = ANY($1)
+		ORDER BY ak.urutan ASC, s.nama_lengkap ASC
+	`
+	rows, err = tx.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("gagal mengambil siswa absen jurnal: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var jurnalID string
+		var a SiswaAbsen
+		if err := rows.Scan(&jurnalID, &a.AnggotaKelasID, &a.NamaSiswa, &a.Status, &a.Catatan); err != nil {
+			return fmt.Errorf("gagal memindai siswa absen jurnal: %w", err)
+		}
+		j := &list[index[jurnalID]]
+		j.SiswaAbsen = append(j.SiswaAbsen, a)
+	}
+	return rows.Err()
+}
+
+func (r *postgresRepository) IsJamBentrok(ctx context.Context, schemaName string, info pengajarInfo, tanggal time.Time, jamMulai int, jamSelesai int, excludeID string) (bool, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM jurnal_mengajar j
+			JOIN pengajar_kelas pk ON j.pengajar_kelas_id = pk.id
+			WHERE (pk.kelas_id = $1 OR pk.teacher_id = $2)
+				AND j.tanggal = $3
+				AND j.jam_ke_mulai <= $5 AND j.jam_ke_selesai >= $4
+				AND ($6 = '' OR j.id::text <> $6)
+		)
+	`
+	var bentrok bool
+	if err := tx.QueryRowContext(ctx, query, info.KelasID, info.TeacherID, tanggal, jamMulai, jamSelesai, excludeID).Scan(&bentrok); err != nil {
+		return false, fmt.Errorf("gagal memeriksa jam pelajaran: %w", err)
+	}
+	return bentrok, tx.Commit()
+}
+
+func (r *postgresRepository) IsRencanaValid(ctx context.Context, schemaName string, pengajarKelasID string, materiID *int, tujuanIDs []int) (bool, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if materiID != nil {
+		var ada bool
+		query := "SELECT EXISTS (SELECT 1 FROM materi_pembelajaran WHERE id = $1 AND pengajar_kelas_id = $2)"
+		if err := tx.QueryRowContext(ctx, query, *materiID, pengajarKelasID).Scan(&ada); err != nil {
+			return false, fmt.Errorf("gagal memeriksa materi pembelajaran: %w", err)
+		}
+		if !ada {
+			return false, tx.Commit()
+		}
+	}
+	if len(tujuanIDs) > 0 {
+		var jumlah int
+		query := `
+			SELECT COUNT(DISTINCT tp.id)
+			FROM tujuan_pembelajaran tp
+			JOIN materi_pembelajaran m ON tp.materi_pembelajaran_id = m.id
+			WHERE tp.id = ANY($1) AND m.pengajar_kelas_id = $2 AND ($3::int IS NULL OR m.id = $3::int)
+		`
+		if err := tx.QueryRowContext(ctx, query, pq.Array(tujuanIDs), pengajarKelasID, materiID).Scan(&jumlah); err != nil {
+			return false, fmt.Errorf("gagal memeriksa tujuan pembelajaran: %w", err)
+		}
+		if jumlah != len(uniqueInts(tujuanIDs)) {
+			return false, tx.Commit()
+		}
+	}
+	return true, tx.Commit()
+}
+
+func (r *postgresRepository) IsAnggotaKelas(ctx context.Context, schemaName string, kelasID string, anggotaKelasIDs []string) (bool, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var jumlah int
+	query := "SELECT COUNT(DISTINCT id) FROM anggota_kelas WHERE id = ANY($1) AND kelas_id = $2"
+	if err := tx.QueryRowContext(ctx, query, pq.Array(anggotaKelasIDs), kelasID).Scan(&jumlah); err != nil {
+		return false, fmt.Errorf("gagal memeriksa anggota kelas: %w", err)
+	}
+	return jumlah == len(anggotaKelasIDs), tx.Commit()
+}
+
+func (r *postgresRepository) Create(ctx context.Context, schemaName string, input UpsertJurnalInput, tanggal time.Time, dicatatOleh string) (string, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	var oleh *string
+	if dicatatOleh != "" {
+		oleh = &dicatatOleh
+	}
+	query := `
+		INSERT INTO jurnal_mengajar (pengajar_kelas_id, tanggal, jam_ke_mulai, jam_ke_selesai, status_kehadiran,
+			materi_pembelajaran_id, materi_lain, catatan, dicatat_oleh)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`
+	var id string
+	err = tx.QueryRowContext(ctx, query, input.PengajarKelasID, tanggal, input.JamKeMulai, input.JamKeSelesai,
+		input.StatusKehadiran, input.MateriPembelajaranID, input.MateriLain, input.Catatan, oleh).Scan(&id)
+	if err != nil {
+		return "", fmt.Errorf("gagal menyimpan jurnal mengajar: %w", err)
+	}
+	if err := simpanDetail(ctx, tx, id, input); err != nil {
+		return "", err
+	}
+	return id, tx.Commit()
+}
+
+func (r *postgresRepository) Update(ctx context.Context, schemaName string, id string, input UpsertJurnalInput, tanggal time.Time) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE jurnal_mengajar
+		SET tanggal = $1, jam_ke_mulai = $2, jam_ke_selesai = $3, status_kehadiran = $4,
+			materi_pembelajaran_id = $5, materi_lain = $6, catatan = $7, updated_at = NOW()
+		WHERE id = $8
+	`
+	res, err := tx.ExecContext(ctx, query, tanggal, input.JamKeMulai, input.JamKeSelesai, input.StatusKehadiran,
+		input.MateriPembelajaranID, input.MateriLain, input.Catatan, id)
+	if err != nil {
+		return fmt.Errorf("gagal memperbarui jurnal mengajar: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM jurnal_mengajar_tujuan WHERE jurnal_mengajar_id = $1", id); err != nil {
+		return fmt.Errorf("gagal menghapus tujuan pembelajaran jurnal: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM jurnal_mengajar_absen WHERE jurnal_mengajar_id = $1", id); err != nil {
+		return fmt.Errorf("gagal menghapus siswa absen jurnal: %w", err)
+	}
+	if err := simpanDetail(ctx, tx, id, input); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// simpanDetail menulis tujuan pembelajaran dan siswa absen satu jurnal.
+func simpanDetail(ctx context.Context, tx *sql.Tx, id string, input UpsertJurnalInput) error {
+	if len(input.TujuanPembelajaranIDs) > 0 {
+		query := `
+			INSERT INTO jurnal_mengajar_tujuan (jurnal_mengajar_id, tujuan_pembelajaran_id)
+			SELECT $1, unnest($2::int[])
+			ON CONFLICT DO NOTHING
+		`
+		if _, err := tx.ExecContext(ctx, query, id, pq.Array(input.TujuanPembelajaranIDs)); err != nil {
+			return fmt.Errorf("gagal menyimpan tujuan pembelajaran jurnal: %w", err)
+		}
+	}
+	if len(input.SiswaAbsen) == 0 {
+		return nil
+	}
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO jurnal_mengajar_absen (jurnal_mengajar_id, anggota_kelas_id, status, catatan)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (jurnal_mengajar_id, anggota_kelas_id) DO UPDATE SET status = EXCLUDED.status, catatan = EXCLUDED.catatan
+	`)
+	if err != nil {
+		return fmt.Errorf("gagal mempersiapkan statement siswa absen: %w", err)
+	}
+	defer stmt.Close()
+	for _, a := range input.SiswaAbsen {
+		if _, err := stmt.ExecContext(ctx, id, a.AnggotaKelasID, a.Status, a.Catatan); err != nil {
+			return fmt.Errorf("gagal menyimpan siswa absen %s: %w", a.AnggotaKelasID, err)
+		}
+	}
+	return nil
+}
+
+func (r *postgresRepository) Delete(ctx context.Context, schemaName string, id string) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, "DELETE FROM jurnal_mengajar WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("gagal menghapus jurnal mengajar: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
+func (r *postgresRepository) GetRencana(ctx context.Context, schemaName string, pengajarKelasID string) ([]MateriRealisasi, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT m.id, m.nama_materi, m.urutan, COUNT(j.id), MIN(j.tanggal), MAX(j.tanggal)
+		FROM materi_pembelajaran m
+		LEFT JOIN jurnal_mengajar j ON j.materi_pembelajaran_id = m.id
+		WHERE m.pengajar_kelas_id = $1
+		GROUP BY m.id
+		ORDER BY m.urutan ASC, m.id ASC
+	`
+	rows, err := tx.QueryContext(ctx, query, pengajarKelasID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil rencana pembelajaran: %w", err)
+	}
+	list := []MateriRealisasi{}
+	index := make(map[int]int)
+	for rows.Next() {
+		var m MateriRealisasi
+		if err := rows.Scan(&m.MateriPembelajaranID, &m.NamaMateri, &m.Urutan, &m.JumlahPertemuan, &m.PertamaDiajarkan, &m.TerakhirDiajarkan); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("gagal memindai rencana pembelajaran: %w", err)
+		}
+		m.Tujuan = []TujuanRealisasi{}
+		index[m.MateriPembelajaranID] = len(list)
+		list = append(list, m)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	query = `
+		SELECT tp.materi_pembelajaran_id, tp.id, tp.deskripsi_tujuan, tp.urutan, COUNT(jt.jurnal_mengajar_id), MAX(j.tanggal)
+		FROM tujuan_pembelajaran tp
+		JOIN materi_pembelajaran m ON tp.materi_pembelajaran_id = m.id
+		LEFT JOIN jurnal_mengajar_tujuan jt ON jt.tujuan_pembelajaran_id = tp.id
+		LEFT JOIN jurnal_mengajar j ON jt.jurnal_mengajar_id = j.id
+		WHERE m.pengajar_kelas_id = $1
+		GROUP BY tp.id
+		ORDER BY tp.urutan ASC, tp.id ASC
+	`
+	rows, err = tx.QueryContext(ctx, query, pengajarKelasID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil tujuan pembelajaran: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var materiID int
+		var t TujuanRealisasi
+		if err := rows.Scan(&materiID, &t.TujuanPembelajaranID, &t.DeskripsiTujuan, &t.Urutan, &t.JumlahPertemuan, &t.TerakhirDiajarkan); err != nil {
+			return nil, fmt.Errorf("gagal memindai tujuan pembelajaran: %w", err)
+		}
+		t.Terlaksana = t.JumlahPertemuan > 0
+		m := &list[index[materiID]]
+		m.Tujuan = append(m.Tujuan, t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+func (r *postgresRepository) GetDiLuarRencana(ctx context.Context, schemaName string, pengajarKelasID string) ([]PertemuanLain, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT id, tanggal, materi_lain
+		FROM jurnal_mengajar
+		WHERE pengajar_kelas_id = $1 AND materi_pembelajaran_id IS NULL AND COALESCE(materi_lain, '') <> ''
+		ORDER BY tanggal ASC, jam_ke_mulai ASC
+	`
+	rows, err := tx.QueryContext(ctx, query, pengajarKelasID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil pertemuan di luar rencana: %w", err)
+	}
+	defer rows.Close()
+
+	list := []PertemuanLain{}
+	for rows.Next() {
+		var p PertemuanLain
+		if err := rows.Scan(&p.JurnalMengajarID, &p.Tanggal, &p.MateriLain); err != nil {
+			return nil, fmt.Errorf("gagal memindai pertemuan di luar rencana: %w", err)
+		}
+		list = append(list, p)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+func (r *postgresRepository) CountPertemuan(ctx context.Context, schemaName string, pengajarKelasID string) (int, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var jumlah int
+	query := "SELECT COUNT(*) FROM jurnal_mengajar WHERE pengajar_kelas_id = $1 AND status_kehadiran IN ('HADIR', 'TUGAS')"
+	if err := tx.QueryRowContext(ctx, query, pengajarKelasID).Scan(&jumlah); err != nil {
+		return 0, fmt.Errorf("gagal menghitung pertemuan: %w", err)
+	}
+	return jumlah, tx.Commit()
+}
+
+func (r *postgresRepository) GetRekap(ctx context.Context, schemaName string, dari time.Time, sampai time.Time, teacherID string) ([]barisRekap, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT t.id, t.nama_lengkap, t.nip_nuptk, pk.id, k.nama_kelas, mp.nama_mapel, j.status_kehadiran,
+			COUNT(j.id), SUM(j.jam_ke_selesai - j.jam_ke_mulai + 1)
+		FROM jurnal_mengajar j
+		JOIN pengajar_kelas pk ON j.pengajar_kelas_id = pk.id
+		JOIN teachers t ON pk.teacher_id = t.id
+		JOIN kelas k ON pk.kelas_id = k.id
+		JOIN mata_pelajaran mp ON pk.mata_pelajaran_id = mp.id
+		WHERE j.tanggal BETWEEN $1 AND $2 AND ($3 = '' OR t.id::text = $3)
+		GROUP BY t.id, pk.id, k.id, mp.id, j.status_kehadiran
+		ORDER BY t.nama_lengkap ASC, k.nama_kelas ASC, mp.nama_mapel ASC
+	`
+	rows, err := tx.QueryContext(ctx, query, dari, sampai, teacherID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menghitung rekap jurnal mengajar: %w", err)
+	}
+	defer rows.Close()
+
+	var list []barisRekap
+	for rows.Next() {
+		var b barisRekap
+		if err := rows.Scan(&b.TeacherID, &b.NamaGuru, &b.NIPNUPTK, &b.PengajarKelasID, &b.NamaKelas, &b.NamaMapel,
+			&b.Status, &b.JumlahPertemuan, &b.JumlahJam); err != nil {
+			return nil, fmt.Errorf("gagal memindai rekap jurnal mengajar: %w", err)
+		}
+		list = append(list, b)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+func uniqueInts(list []int) []int {
+	seen := make(map[int]bool, len(list))
+	var result []int
+	for _, v := range list {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,383 @@
+// file: backend/internal/jurnalmengajar/service.go
+package jurnalmengajar
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"skoola/internal/access"
+	"skoola/internal/audit"
+	"skoola/internal/kalender"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+var ErrValidation = errors.New("validation failed")
+
+// Service mendefinisikan logika bisnis jurnal mengajar.
+type Service interface {
+	GetByPengajar(ctx context.Context, schemaName string, actor access.Actor, pengajarKelasID string, dari string, sampai string) ([]JurnalMengajar, error)
+	GetByID(ctx context.Context, schemaName string, actor access.Actor, id string) (*JurnalMengajar, error)
+	Create(ctx context.Context, schemaName string, actor access.Actor, input UpsertJurnalInput) (*JurnalMengajar, error)
+	Update(ctx context.Context, schemaName string, actor access.Actor, id string, input UpsertJurnalInput) (*JurnalMengajar, error)
+	Delete(ctx context.Context, schemaName string, actor access.Actor, id string) error
+	// GetPerbandingan membandingkan rencana pembelajaran dengan materi yang tercatat di jurnal.
+	GetPerbandingan(ctx context.Context, schemaName string, actor access.Actor, pengajarKelasID string) (*Perbandingan, error)
+	// GetRekapBulanan merekap jurnal per guru untuk bulan YYYY-MM (bawaan: bulan ini).
+	// Pembatasan aksesnya dilakukan di route dengan permission jurnal:rekap.
+	GetRekapBulanan(ctx context.Context, schemaName string, bulan string, teacherID string) (*RekapBulanan, error)
+	// ExportRekap merender rekap bulanan ke xlsx dan mengembalikan isi serta nama filenya.
+	ExportRekap(rekap *RekapBulanan, format string) ([]byte, string, error)
+}
+
+type service struct {
+	repo     Repository
+	kalender kalender.Service
+	access   access.Service
+	audit    audit.Recorder
+	validate *validator.Validate
+}
+
+// NewService membuat instance baru dari service jurnal mengajar.
+func NewService(repo Repository, kalenderService kalender.Service, accessService access.Service, auditLog audit.Recorder, validate *validator.Validate) Service {
+	return &service{repo: repo, kalender: kalenderService, access: accessService, audit: auditLog, validate: validate}
+}
+
+func (s *service) GetByPengajar(ctx context.Context, schemaName string, actor access.Actor, pengajarKelasID string, dari string, sampai string) ([]JurnalMengajar, error) {
+	if _, err := s.getPengajar(ctx, schemaName, pengajarKelasID); err != nil {
+		return nil, err
+	}
+	if err := s.access.AuthorizeRead(ctx, schemaName, actor, access.Target{PengajarKelasIDs: []string{pengajarKelasID}}); err != nil {
+		return nil, err
+	}
+	d, err := parseTanggalOpsional("dari", dari)
+	if err != nil {
+		return nil, err
+	}
+	sp, err := parseTanggalOpsional("sampai", sampai)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetByPengajar(ctx, schemaName, pengajarKelasID, d, sp)
+}
+
+func (s *service) GetByID(ctx context.Context, schemaName string, actor access.Actor, id string) (*JurnalMengajar, error) {
+	jurnal, err := s.get(ctx, schemaName, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.access.AuthorizeRead(ctx, schemaName, actor, access.Target{PengajarKelasIDs: []string{jurnal.PengajarKelasID}}); err != nil {
+		return nil, err
+	}
+	return jurnal, nil
+}
+
+func (s *service) Create(ctx context.Context, schemaName string, actor access.Actor, input UpsertJurnalInput) (*JurnalMengajar, error) {
+	tanggal, err := s.validateInput(ctx, schemaName, actor, "", &input)
+	if err != nil {
+		return nil, err
+	}
+	id, err := s.repo.Create(ctx, schemaName, input, tanggal, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	jurnal, err := s.repo.GetByID(ctx, schemaName, id)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionCreate, Entity: "jurnal_mengajar", EntityID: id, After: jurnal})
+	return jurnal, nil
+}
+
+func (s *service) Update(ctx context.Context, schemaName string, actor access.Actor, id string, input UpsertJurnalInput) (*JurnalMengajar, error) {
+	before, err := s.get(ctx, schemaName, id)
+	if err != nil {
+		return nil, err
+	}
+	if input.PengajarKelasID != before.PengajarKelasID {
+		return nil, fmt.Errorf("%w: pengajar kelas jurnal tidak dapat diubah", ErrValidation)
+	}
+	tanggal, err := s.validateInput(ctx, schemaName, actor, id, &input)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, schemaName, id, input, tanggal); err != nil {
+		return nil, err
+	}
+	jurnal, err := s.repo.GetByID(ctx, schemaName, id)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionUpdate, Entity: "jurnal_mengajar", EntityID: id, Before: before, After: jurnal})
+	return jurnal, nil
+}
+
+func (s *service) Delete(ctx context.Context, schemaName string, actor access.Actor, id string) error {
+	before, err := s.get(ctx, schemaName, id)
+	if err != nil {
+		return err
+	}
+	if err := s.access.AuthorizeWrite(ctx, schemaName, actor, access.Target{PengajarKelasIDs: []string{before.PengajarKelasID}}); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, schemaName, id); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionDelete, Entity: "jurnal_mengajar", EntityID: id, Before: before})
+	return nil
+}
+
+// validateInput memeriksa input jurnal, melengkapi nilai bawaannya, dan mengembalikan tanggalnya.
+// excludeID adalah jurnal yang sedang diperbarui, kosong saat membuat jurnal baru.
+func (s *service) validateInput(ctx context.Context, schemaName string, actor access.Actor, excludeID string, input *UpsertJurnalInput) (time.Time, error) {
+	if err := s.validate.Struct(input); err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	tanggal, _ := time.Parse("2006-01-02", input.Tanggal)
+	if input.JamKeSelesai == 0 {
+		input.JamKeSelesai = input.JamKeMulai
+	}
+	if input.JamKeSelesai < input.JamKeMulai {
+		return time.Time{}, fmt.Errorf("%w: jam_ke_selesai tidak boleh lebih kecil dari jam_ke_mulai", ErrValidation)
+	}
+	if input.StatusKehadiran == "" {
+		input.StatusKehadiran = StatusHadir
+	}
+	input.MateriLain = trimOpsional(input.MateriLain)
+	input.Catatan = trimOpsional(input.Catatan)
+
+	// Jika guru tidak hadir dan tidak meninggalkan tugas, tidak ada pembelajaran yang dicatat.
+	berlangsung := input.StatusKehadiran == StatusHadir || input.StatusKehadiran == StatusTugas
+	if !berlangsung && (input.MateriPembelajaranID != nil || len(input.TujuanPembelajaranIDs) > 0 || input.MateriLain != nil || len(input.SiswaAbsen) > 0) {
+		return time.Time{}, fmt.Errorf("%w: materi, tujuan pembelajaran, dan siswa absen hanya diisi jika status kehadiran HADIR atau TUGAS", ErrValidation)
+	}
+
+	anggotaIDs := make([]string, len(input.SiswaAbsen))
+	seen := make(map[string]bool, len(input.SiswaAbsen))
+	for i, a := range input.SiswaAbsen {
+		if seen[a.AnggotaKelasID] {
+			return time.Time{}, fmt.Errorf("%w: siswa %s tercantum lebih dari sekali", ErrValidation, a.AnggotaKelasID)
+		}
+		seen[a.AnggotaKelasID] = true
+		anggotaIDs[i] = a.AnggotaKelasID
+	}
+
+	info, err := s.getPengajar(ctx, schemaName, input.PengajarKelasID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	target := access.Target{PengajarKelasIDs: []string{input.PengajarKelasID}, KelasIDs: []string{info.KelasID}, AnggotaKelasIDs: anggotaIDs}
+	if input.MateriPembelajaranID != nil {
+		target.MateriIDs = []int{*input.MateriPembelajaranID}
+	}
+	target.TujuanIDs = input.TujuanPembelajaranIDs
+	if err := s.access.AuthorizeWrite(ctx, schemaName, actor, target); err != nil {
+		return time.Time{}, err
+	}
+
+	if err := s.kalender.CekHariSekolah(ctx, schemaName, info.KelasID, tanggal); err != nil {
+		if errors.Is(err, kalender.ErrBukanHariSekolah) {
+			return time.Time{}, fmt.Errorf("%w: %v", ErrValidation, err)
+		}
+		return time.Time{}, err
+	}
+	valid, err := s.repo.IsRencanaValid(ctx, schemaName, input.PengajarKelasID, input.MateriPembelajaranID, input.TujuanPembelajaranIDs)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !valid {
+		return time.Time{}, fmt.Errorf("%w: materi atau tujuan pembelajaran bukan bagian dari rencana pembelajaran mapel ini", ErrValidation)
+	}
+	if len(anggotaIDs) > 0 {
+		valid, err := s.repo.IsAnggotaKelas(ctx, schemaName, info.KelasID, anggotaIDs)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if !valid {
+			return time.Time{}, fmt.Errorf("%w: siswa absen harus anggota kelas ini", ErrValidation)
+		}
+	}
+	bentrok, err := s.repo.IsJamBentrok(ctx, schemaName, *info, tanggal, input.JamKeMulai, input.JamKeSelesai, excludeID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if bentrok {
+		return time.Time{}, fmt.Errorf("%w: jam ke-%d s.d. %d pada tanggal %s sudah terisi jurnal lain di kelas ini atau milik guru yang sama",
+			ErrValidation, input.JamKeMulai, input.JamKeSelesai, input.Tanggal)
+	}
+	return tanggal, nil
+}
+
+func (s *service) GetPerbandingan(ctx context.Context, schemaName string, actor access.Actor, pengajarKelasID string) (*Perbandingan, error) {
+	info, err := s.getPengajar(ctx, schemaName, pengajarKelasID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.access.AuthorizeRead(ctx, schemaName, actor, access.Target{PengajarKelasIDs: []string{pengajarKelasID}}); err != nil {
+		return nil, err
+	}
+
+	materi, err := s.repo.GetRencana(ctx, schemaName, pengajarKelasID)
+	if err != nil {
+		return nil, err
+	}
+	lain, err := s.repo.GetDiLuarRencana(ctx, schemaName, pengajarKelasID)
+	if err != nil {
+		return nil, err
+	}
+	pertemuan, err := s.repo.CountPertemuan(ctx, schemaName, pengajarKelasID)
+	if err != nil {
+		return nil, err
+	}
+
+	hasil := &Perbandingan{PengajarKelasID: pengajarKelasID, NamaKelas: info.NamaKelas, NamaMapel: info.NamaMapel,
+		JumlahPertemuan: pertemuan, Materi: materi, DiLuarRencana: lain}
+	for _, m := range materi {
+		for _, t := range m.Tujuan {
+			hasil.JumlahTujuan++
+			if t.Terlaksana {
+				hasil.TujuanTerlaksana++
+			}
+		}
+	}
+	hasil.Persentase = persen(hasil.TujuanTerlaksana, hasil.JumlahTujuan)
+	return hasil, nil
+}
+
+func (s *service) GetRekapBulanan(ctx context.Context, schemaName string, bulan string, teacherID string) (*RekapBulanan, error) {
+	if bulan == "" {
+		bulan = time.Now().Format("2006-01")
+	}
+	awal, err := time.Parse("2006-01", bulan)
+	if err != nil {
+		return nil, fmt.Errorf("%w: bulan harus berformat YYYY-MM", ErrValidation)
+	}
+	if teacherID != "" {
+		if _, err := uuid.Parse(teacherID); err != nil {
+			return nil, fmt.Errorf("%w: teacher_id tidak valid", ErrValidation)
+		}
+	}
+
+	rows, err := s.repo.GetRekap(ctx, schemaName, awal, awal.AddDate(0, 1, -1), teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	rekap := &RekapBulanan{Bulan: bulan, Guru: []RekapGuru{}}
+	perGuru := make(map[string]int)
+	perPengajar := make(map[string]int)
+	for _, b := range rows {
+		gi, ok := perGuru[b.TeacherID]
+		if !ok {
+			gi = len(rekap.Guru)
+			perGuru[b.TeacherID] = gi
+			rekap.Guru = append(rekap.Guru, RekapGuru{TeacherID: b.TeacherID, NamaGuru: b.NamaGuru, NIPNUPTK: b.NIPNUPTK, Pengajar: []RekapPengajar{}})
+		}
+		g := &rekap.Guru[gi]
+		pi, ok := perPengajar[b.PengajarKelasID]
+		if !ok {
+			pi = len(g.Pengajar)
+			perPengajar[b.PengajarKelasID] = pi
+			g.Pengajar = append(g.Pengajar, RekapPengajar{PengajarKelasID: b.PengajarKelasID, NamaKelas: b.NamaKelas, NamaMapel: b.NamaMapel})
+		}
+		p := &g.Pengajar[pi]
+
+		p.JumlahPertemuan += b.JumlahPertemuan
+		p.JumlahJam += b.JumlahJam
+		tambahJam(&p.Jam, b.Status, b.JumlahJam)
+		g.JumlahPertemuan += b.JumlahPertemuan
+		g.JumlahJam += b.JumlahJam
+		tambahJam(&g.Jam, b.Status, b.JumlahJam)
+	}
+	for i := range rekap.Guru {
+		g := &rekap.Guru[i]
+		g.PersentaseHadir = persen(g.Jam.Hadir, g.JumlahJam)
+	}
+	return rekap, nil
+}
+
+func (s *service) ExportRekap(rekap *RekapBulanan, format string) ([]byte, string, error) {
+	if format != FormatXLSX {
+		return nil, "", fmt.Errorf("%w: format harus 'xlsx'", ErrValidation)
+	}
+	content, err := renderExcel(rekap)
+	return content, fmt.Sprintf("rekap_jurnal_mengajar_%s.xlsx", rekap.Bulan), err
+}
+
+func (s *service) get(ctx context.Context, schemaName string, id string) (*JurnalMengajar, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, fmt.Errorf("%w: id jurnal tidak valid", ErrValidation)
+	}
+	jurnal, err := s.repo.GetByID(ctx, schemaName, id)
+	if err != nil {
+		return nil, err
+	}
+	if jurnal == nil {
+		return nil, sql.ErrNoRows
+	}
+	return jurnal, nil
+}
+
+func (s *service) getPengajar(ctx context.Context, schemaName string, pengajarKelasID string) (*pengajarInfo, error) {
+	if _, err := uuid.Parse(pengajarKelasID); err != nil {
+		return nil, fmt.Errorf("%w: pengajar_kelas_id tidak valid", ErrValidation)
+	}
+	info, err := s.repo.GetPengajarInfo(ctx, schemaName, pengajarKelasID)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, sql.ErrNoRows
+	}
+	return info, nil
+}
+
+func tambahJam(j *JamKehadiran, status string, jam int) {
+	switch status {
+	case StatusHadir:
+		j.Hadir += jam
+	case StatusTugas:
+		j.Tugas += jam
+	case StatusIzin:
+		j.Izin += jam
+	case StatusSakit:
+		j.Sakit += jam
+	case StatusDinas:
+		j.Dinas += jam
+	}
+}
+
+// persen mengembalikan n/total dalam persen dengan dua angka desimal.
+func persen(n int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(n)*10000/float64(total)) / 100
+}
+
+func parseTanggalOpsional(nama string, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s harus berformat YYYY-MM-DD", ErrValidation, nama)
+	}
+	return &t, nil
+}
+
+func trimOpsional(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	v := strings.TrimSpace(*s)
+	if v == "" {
+		return nil
+	}
+	return &v
+}
@@ -0,0 +1,189 @@
+// file: backend/internal/kalender/handler.go
+package kalender
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"skoola/internal/middleware"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{service: s}
+}
+
+// GetKalender adalah handler untuk GET /tahun-ajaran/{id}/kalender?dari=...&sampai=...
+func (h *Handler) GetKalender(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	q := r.URL.Query()
+
+	kalender, err := h.service.GetKalender(r.Context(), schemaName, chi.URLParam(r, "id"), q.Get("dari"), q.Get("sampai"))
+	if err != nil {
+		writeError(w, "Gagal mengambil kalender akademik: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(kalender)
+}
+
+// UpdatePengaturan adalah handler untuk PUT /tahun-ajaran/{id}/kalender/pengaturan.
+func (h *Handler) UpdatePengaturan(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	var input UpdatePengaturanInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	pengaturan, err := h.service.UpdatePengaturan(r.Context(), schemaName, chi.URLParam(r, "id"), input)
+	if err != nil {
+		writeError(w, "Gagal menyimpan pengaturan kalender: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pengaturan)
+}
+
+// GetHariEfektif adalah handler untuk GET /tahun-ajaran/{id}/kalender/hari-efektif?dari=...&sampai=...
+func (h *Handler) GetHariEfektif(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	q := r.URL.Query()
+
+	hasil, err := h.service.GetHariEfektif(r.Context(), schemaName, chi.URLParam(r, "id"), q.Get("dari"), q.Get("sampai"))
+	if err != nil {
+		writeError(w, "Gagal menghitung hari efektif: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hasil)
+}
+
+// CreateAgenda adalah handler untuk POST /tahun-ajaran/{id}/kalender/agenda.
+func (h *Handler) CreateAgenda(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	var input UpsertAgendaInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	agenda, err := h.service.CreateAgenda(r.Context(), schemaName, chi.URLParam(r, "id"), input)
+	if err != nil {
+		writeError(w, "Gagal membuat agenda: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(agenda)
+}
+
+// UpdateAgenda adalah handler untuk PUT /tahun-ajaran/{id}/kalender/agenda/{agendaID}.
+func (h *Handler) UpdateAgenda(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	var input UpsertAgendaInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	agenda, err := h.service.UpdateAgenda(r.Context(), schemaName, chi.URLParam(r, "id"), chi.URLParam(r, "agendaID"), input)
+	if err != nil {
+		writeError(w, "Gagal memperbarui agenda: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(agenda)
+}
+
+// DeleteAgenda adalah handler untuk DELETE /tahun-ajaran/{id}/kalender/agenda/{agendaID}.
+func (h *Handler) DeleteAgenda(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	if err := h.service.DeleteAgenda(r.Context(), schemaName, chi.URLParam(r, "id"), chi.URLParam(r, "agendaID")); err != nil {
+		writeError(w, "Gagal menghapus agenda: ", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ImportICS adalah handler untuk POST /tahun-ajaran/{id}/kalender/ics?jenis=LIBUR|UJIAN|KEGIATAN.
+// Berkas dikirim sebagai multipart dengan field "file", atau langsung sebagai body text/calendar.
+// jenis dipakai untuk agenda yang CATEGORIES-nya tidak dikenali.
+func (h *Handler) ImportICS(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	var src io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(10 << 20); err != nil { // 10 MB max
+			http.Error(w, "File terlalu besar", http.StatusBadRequest)
+			return
+		}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Gagal mendapatkan file dari request: %v", err), http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		src = file
+	}
+	content, err := io.ReadAll(io.LimitReader(src, 10<<20))
+	if err != nil {
+		http.Error(w, "Gagal membaca file: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	hasil, err := h.service.ImportICS(r.Context(), schemaName, chi.URLParam(r, "id"), content, r.URL.Query().Get("jenis"))
+	if err != nil {
+		writeError(w, "Gagal mengimpor kalender: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hasil)
+}
+
+// ExportICS adalah handler untuk GET /tahun-ajaran/{id}/kalender/ics.
+func (h *Handler) ExportICS(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	content, filename, err := h.service.ExportICS(r.Context(), schemaName, chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, "Gagal mengekspor kalender: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	w.Write(content)
+}
+
+func writeError(w http.ResponseWriter, prefix string, err error) {
+	switch {
+	case errors.Is(err, ErrValidation):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Data tidak ditemukan", http.StatusNotFound)
+	default:
+		http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+	}
+}
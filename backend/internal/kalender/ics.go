@@ -0,0 +1,230 @@
+// file: backend/internal/kalender/ics.go
+package kalender
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const formatTanggalICS = "20060102"
+
+// eventICS adalah satu VEVENT hasil pembacaan berkas iCalendar. Selesai inklusif.
+type eventICS struct {
+	UID        string
+	Judul      string
+	Keterangan string
+	Kategori   []string
+	Mulai      time.Time
+	Selesai    time.Time
+}
+
+// parseICS membaca VEVENT dari berkas iCalendar (RFC 5545). Hanya tanggal yang dipakai;
+// jam dan zona waktu diabaikan karena agenda kalender akademik berlaku sepanjang hari.
+func parseICS(content []byte) ([]eventICS, []string, error) {
+	lines, err := unfoldICS(content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var events []eventICS
+	var pesan []string
+	var cur *eventICS
+	var akhirEksklusif bool
+	for i, line := range lines {
+		name, params, value := splitPropertyICS(line)
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			cur = &eventICS{}
+			akhirEksklusif = false
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if cur == nil {
+				continue
+			}
+			if cur.Mulai.IsZero() {
+				pesan = append(pesan, fmt.Sprintf("baris %d: agenda %q tanpa DTSTART dilewati", i+1, cur.Judul))
+				cur = nil
+				continue
+			}
+			if cur.Selesai.IsZero() {
+				cur.Selesai = cur.Mulai
+			} else if akhirEksklusif && cur.Selesai.After(cur.Mulai) {
+				// DTEND bersifat eksklusif: agenda 17-18 Agustus ditulis sampai 19 Agustus.
+				cur.Selesai = cur.Selesai.AddDate(0, 0, -1)
+			}
+			if cur.Selesai.Before(cur.Mulai) {
+				cur.Selesai = cur.Mulai
+			}
+			events = append(events, *cur)
+			cur = nil
+		case cur == nil:
+			continue
+		case name == "UID":
+			cur.UID = value
+		case name == "SUMMARY":
+			cur.Judul = unescapeICS(value)
+		case name == "DESCRIPTION":
+			cur.Keterangan = unescapeICS(value)
+		case name == "CATEGORIES":
+			for _, k := range strings.Split(value, ",") {
+				cur.Kategori = append(cur.Kategori, strings.ToUpper(strings.TrimSpace(unescapeICS(k))))
+			}
+		case name == "DTSTART" || name == "DTEND":
+			t, err := parseTanggalICS(value)
+			if err != nil {
+				pesan = append(pesan, fmt.Sprintf("baris %d: %s tidak valid", i+1, name))
+				continue
+			}
+			if name == "DTSTART" {
+				cur.Mulai = t
+			} else {
+				cur.Selesai = t
+				// Agenda sepanjang hari, atau yang berakhir tepat tengah malam, berakhir sehari sebelumnya.
+				akhirEksklusif = strings.Contains(strings.ToUpper(params), "VALUE=DATE") || len(value) == 8 ||
+					strings.HasPrefix(value[8:], "T000000")
+			}
+		}
+	}
+	return events, pesan, nil
+}
+
+// unfoldICS menggabungkan baris lanjutan (diawali spasi atau tab) dengan baris sebelumnya.
+func unfoldICS(content []byte) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("gagal membaca berkas ics: %w", err)
+	}
+	return lines, nil
+}
+
+// splitPropertyICS memecah "DTSTART;VALUE=DATE:20250817" menjadi nama, parameter, dan nilai.
+func splitPropertyICS(line string) (string, string, string) {
+	colon := strings.Index(line, ":")
+	if colon < 0 {
+		return strings.ToUpper(line), "", ""
+	}
+	name, value := line[:colon], line[colon+1:]
+	params := ""
+	if semi := strings.Index(name, ";"); semi >= 0 {
+		name, params = name[:semi], name[semi+1:]
+	}
+	return strings.ToUpper(name), params, value
+}
+
+func parseTanggalICS(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("tanggal terlalu pendek")
+	}
+	return time.Parse(formatTanggalICS, value[:8])
+}
+
+func unescapeICS(s string) string {
+	r := strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+	return r.Replace(s)
+}
+
+func escapeICS(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+// jenisDariKategori memetakan CATEGORIES ke jenis agenda; nilai bawaan dipakai jika tidak
+// ada kategori yang dikenali.
+func jenisDariKategori(kategori []string, bawaan string) string {
+	for _, k := range kategori {
+		switch k {
+		case JenisLibur, "HOLIDAY", "HOLIDAYS", "HARI LIBUR":
+			return JenisLibur
+		case JenisUjian, "EXAM", "EXAMS", "UJIAN SEKOLAH":
+			return JenisUjian
+		case JenisKegiatan, "EVENT", "EVENTS":
+			return JenisKegiatan
+		}
+	}
+	return bawaan
+}
+
+// judulAgenda merapikan SUMMARY agar muat di kolom judul (255 karakter). Judul dipotong
+// per rune agar karakter UTF-8 tidak terbelah.
+func judulAgenda(summary string) string {
+	judul := strings.TrimSpace(summary)
+	if judul == "" {
+		return "(Tanpa judul)"
+	}
+	if r := []rune(judul); len(r) > 255 {
+		judul = string(r[:255])
+	}
+	return judul
+}
+
+// uidAgenda memakai UID dari berkas, atau membuat UID tetap dari judul dan tanggal agar
+// impor ulang berkas yang sama tidak menggandakan agenda.
+func uidAgenda(e eventICS) string {
+	if e.UID != "" {
+		return e.UID
+	}
+	sum := sha1.Sum([]byte(e.Judul + "|" + e.Mulai.Format(formatTanggalICS) + "|" + e.Selesai.Format(formatTanggalICS)))
+	return hex.EncodeToString(sum[:]) + "@skoola"
+}
+
+// renderICS menulis agenda sebagai VCALENDAR dengan agenda sepanjang hari.
+func renderICS(namaKalender string, list []Agenda, now time.Time) []byte {
+	var b bytes.Buffer
+	tulis := func(line string) {
+		// Baris lebih dari 75 oktet dilipat sesuai RFC 5545.
+		for len(line) > 75 {
+			potong := 75
+			for potong > 0 && line[potong]&0xC0 == 0x80 {
+				potong--
+			}
+			b.WriteString(line[:potong] + "\r\n")
+			line = " " + line[potong:]
+		}
+		b.WriteString(line + "\r\n")
+	}
+
+	tulis("BEGIN:VCALENDAR")
+	tulis("VERSION:2.0")
+	tulis("PRODID:-//Skoola//Kalender Akademik//ID")
+	tulis("CALSCALE:GREGORIAN")
+	tulis("X-WR-CALNAME:" + escapeICS(namaKalender))
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, a := range list {
+		uid := a.ID + "@skoola"
+		if a.UID != nil && *a.UID != "" {
+			uid = *a.UID
+		}
+		tulis("BEGIN:VEVENT")
+		tulis("UID:" + uid)
+		tulis("DTSTAMP:" + stamp)
+		tulis("DTSTART;VALUE=DATE:" + a.TanggalMulai.Format(formatTanggalICS))
+		tulis("DTEND;VALUE=DATE:" + a.TanggalSelesai.AddDate(0, 0, 1).Format(formatTanggalICS))
+		tulis("SUMMARY:" + escapeICS(a.Judul))
+		if a.Keterangan != nil && *a.Keterangan != "" {
+			tulis("DESCRIPTION:" + escapeICS(*a.Keterangan))
+		}
+		tulis("CATEGORIES:" + a.Jenis)
+		if a.Jenis == JenisLibur {
+			tulis("TRANSP:TRANSPARENT")
+		}
+		tulis("END:VEVENT")
+	}
+	tulis("END:VCALENDAR")
+	return b.Bytes()
+}
@@ -0,0 +1,145 @@
+// file: backend/internal/kalender/ics_test.go
+package kalender
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestParseICS(t *testing.T) {
+	tgl := func(s string) time.Time {
+		t, _ := time.Parse("2006-01-02", s)
+		return t
+	}
+	vevent := func(baris ...string) string {
+		return "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\n" + strings.Join(baris, "\r\n") + "\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	}
+
+	tests := []struct {
+		name      string
+		ics       string
+		wantJudul string
+		wantMulai string
+		wantAkhir string
+		wantPesan int
+	}{
+		{
+			name:      "judul multi-byte",
+			ics:       vevent("SUMMARY:Libur Idul Fitri 1447 H 🌙 — Hari Raya", "DTSTART;VALUE=DATE:20260320", "DTEND;VALUE=DATE:20260322"),
+			wantJudul: "Libur Idul Fitri 1447 H 🌙 — Hari Raya",
+			wantMulai: "2026-03-20",
+			wantAkhir: "2026-03-21",
+		},
+		{
+			name:      "baris dilipat di tengah karakter multi-byte",
+			ics:       vevent("SUMMARY:Pentas Seni Caf\xc3", " \xa9 日本語", "DTSTART;VALUE=DATE:20260801"),
+			wantJudul: "Pentas Seni Café 日本語",
+			wantMulai: "2026-08-01",
+			wantAkhir: "2026-08-01",
+		},
+		{
+			name:      "karakter escape pada judul",
+			ics:       vevent(`SUMMARY:Rapat Guru\, Komite\; dan Orang Tua`, "DTSTART:20260915T080000", "DTEND:20260915T120000"),
+			wantJudul: "Rapat Guru, Komite; dan Orang Tua",
+			wantMulai: "2026-09-15",
+			wantAkhir: "2026-09-15",
+		},
+		{
+			name:      "DTEND tengah malam bersifat eksklusif",
+			ics:       vevent("SUMMARY:Ujian Akhir Semester", "DTSTART:20261201T000000", "DTEND:20261205T000000"),
+			wantJudul: "Ujian Akhir Semester",
+			wantMulai: "2026-12-01",
+			wantAkhir: "2026-12-04",
+		},
+		{
+			name:      "agenda tanpa DTSTART dilewati",
+			ics:       vevent("SUMMARY:Tanpa tanggal"),
+			wantPesan: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, pesan, err := parseICS([]byte(tt.ics))
+			if err != nil {
+				t.Fatalf("parseICS error: %v", err)
+			}
+			if len(pesan) != tt.wantPesan {
+				t.Errorf("pesan = %v, ingin %d pesan", pesan, tt.wantPesan)
+			}
+			if tt.wantJudul == "" {
+				if len(events) != 0 {
+					t.Errorf("events = %+v, ingin kosong", events)
+				}
+				return
+			}
+			if len(events) != 1 {
+				t.Fatalf("jumlah agenda = %d, ingin 1", len(events))
+			}
+			e := events[0]
+			if e.Judul != tt.wantJudul {
+				t.Errorf("judul = %q, ingin %q", e.Judul, tt.wantJudul)
+			}
+			if !e.Mulai.Equal(tgl(tt.wantMulai)) || !e.Selesai.Equal(tgl(tt.wantAkhir)) {
+				t.Errorf("rentang = %s s.d. %s, ingin %s s.d. %s", e.Mulai.Format("2006-01-02"), e.Selesai.Format("2006-01-02"), tt.wantMulai, tt.wantAkhir)
+			}
+		})
+	}
+}
+
+func TestRenderICSLipatMultiByte(t *testing.T) {
+	judul := strings.Repeat("Peringatan Hari Guru Nasional — 教師の日 ", 4)
+	mulai := time.Date(2026, 11, 25, 0, 0, 0, 0, time.UTC)
+	content := renderICS("Kalender", []Agenda{{ID: "a1", Judul: judul, Jenis: JenisKegiatan, TanggalMulai: mulai, TanggalSelesai: mulai}}, mulai)
+
+	for _, line := range strings.Split(strings.TrimSuffix(string(content), "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("baris lebih dari 75 oktet: %q", line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("baris memotong karakter UTF-8: %q", line)
+		}
+	}
+
+	events, _, err := parseICS(content)
+	if err != nil {
+		t.Fatalf("parseICS error: %v", err)
+	}
+	if len(events) != 1 || events[0].Judul != judul {
+		t.Fatalf("hasil impor ulang = %+v, ingin judul %q", events, judul)
+	}
+}
+
+func TestJudulAgenda(t *testing.T) {
+	panjang := strings.Repeat("é", 300)
+
+	tests := []struct {
+		name     string
+		summary  string
+		want     string
+		wantRune int
+	}{
+		{name: "judul kosong", summary: "  ", want: "(Tanpa judul)", wantRune: 13},
+		{name: "spasi dibuang", summary: "  Libur Semester  ", want: "Libur Semester", wantRune: 14},
+		{name: "tepat 255 karakter multi-byte", summary: panjang[:255*2], want: panjang[:255*2], wantRune: 255},
+		{name: "lebih dari 255 karakter multi-byte dipotong per rune", summary: panjang, want: panjang[:255*2], wantRune: 255},
+		{name: "emoji di batas potong", summary: strings.Repeat("a", 254) + "🎓🎓", want: strings.Repeat("a", 254) + "🎓", wantRune: 255},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := judulAgenda(tt.summary)
+			if got != tt.want {
+				t.Errorf("judulAgenda = %q, ingin %q", got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("judul bukan UTF-8 yang valid: %q", got)
+			}
+			if n := utf8.RuneCountInString(got); n != tt.wantRune {
+				t.Errorf("panjang = %d rune, ingin %d", n, tt.wantRune)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// file: backend/internal/kalender/model.go
+package kalender
+
+import "time"
+
+// Jenis agenda kalender akademik.
+const (
+	JenisLibur    = "LIBUR"
+	JenisUjian    = "UJIAN"
+	JenisKegiatan = "KEGIATAN"
+)
+
+// HariSekolahBawaan dipakai untuk tahun ajaran yang pengaturan kalendernya belum diisi.
+const HariSekolahBawaan = 6
+
+// Pengaturan adalah rentang semester dan pola minggu sekolah satu tahun ajaran.
+type Pengaturan struct {
+	TahunAjaranID        string     `json:"tahun_ajaran_id"`
+	TanggalMulai         *time.Time `json:"tanggal_mulai"`
+	TanggalSelesai       *time.Time `json:"tanggal_selesai"`
+	HariSekolahPerMinggu int        `json:"hari_sekolah_per_minggu"`
+}
+
+// Agenda adalah satu entri kalender akademik. TanggalSelesai inklusif.
+type Agenda struct {
+	ID             string    `json:"id"`
+	TahunAjaranID  string    `json:"tahun_ajaran_id"`
+	Judul          string    `json:"judul"`
+	Jenis          string    `json:"jenis"`
+	TanggalMulai   time.Time `json:"tanggal_mulai"`
+	TanggalSelesai time.Time `json:"tanggal_selesai"`
+	Keterangan     *string   `json:"keterangan"`
+	UID            *string   `json:"uid,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// Kalender adalah pengaturan dan seluruh agenda satu tahun ajaran.
+type Kalender struct {
+	Pengaturan Pengaturan `json:"pengaturan"`
+	Agenda     []Agenda   `json:"agenda"`
+}
+
+// UpdatePengaturanInput adalah DTO untuk mengubah pengaturan kalender.
+type UpdatePengaturanInput struct {
+	TanggalMulai         string `json:"tanggal_mulai" validate:"omitempty,datetime=2006-01-02"`
+	TanggalSelesai       string `json:"tanggal_selesai" validate:"omitempty,datetime=2006-01-02"`
+	HariSekolahPerMinggu int    `json:"hari_sekolah_per_minggu" validate:"required,oneof=5 6"`
+}
+
+// UpsertAgendaInput adalah DTO untuk membuat atau memperbarui agenda. TanggalSelesai
+// boleh kosong untuk agenda satu hari.
+type UpsertAgendaInput struct {
+	Judul          string `json:"judul" validate:"required,max=255"`
+	Jenis          string `json:"jenis" validate:"required,oneof=LIBUR UJIAN KEGIATAN"`
+	TanggalMulai   string `json:"tanggal_mulai" validate:"required,datetime=2006-01-02"`
+	TanggalSelesai string `json:"tanggal_selesai" validate:"omitempty,datetime=2006-01-02"`
+	Keterangan     string `json:"keterangan"`
+}
+
+// HariEfektif adalah jumlah hari sekolah pada rentang [Dari, Sampai]. HariEfektifBerjalan
+// hanya menghitung sampai hari ini dan sama dengan hari efektif yang dipakai rekap presensi.
+type HariEfektif struct {
+	TahunAjaranID        string             `json:"tahun_ajaran_id"`
+	Dari                 string             `json:"dari"`
+	Sampai               string             `json:"sampai"`
+	HariSekolahPerMinggu int                `json:"hari_sekolah_per_minggu"`
+	HariSekolah          int                `json:"hari_sekolah"`
+	HariLibur            int                `json:"hari_libur"`
+	HariEfektif          int                `json:"hari_efektif"`
+	HariEfektifBerjalan  int                `json:"hari_efektif_berjalan"`
+	PerBulan             []HariEfektifBulan `json:"per_bulan"`
+	Libur                []Agenda           `json:"libur"`
+}
+
+// HariEfektifBulan adalah hari efektif dalam satu bulan kalender.
+type HariEfektifBulan struct {
+	Bulan       string `json:"bulan"` // YYYY-MM
+	HariEfektif int    `json:"hari_efektif"`
+}
+
+// HasilImport adalah ringkasan impor berkas iCalendar.
+type HasilImport struct {
+	Dibuat     int      `json:"dibuat"`
+	Diperbarui int      `json:"diperbarui"`
+	Dilewati   int      `json:"dilewati"`
+	Pesan      []string `json:"pesan,omitempty"`
+}
@@ -0,0 +1,271 @@
+// file: backend/internal/kalender/repository.go
+package kalender
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"skoola/pkg/database"
+	"time"
+)
+
+// Repository mendefinisikan interface untuk interaksi database kalender akademik.
+type Repository interface {
+	// GetPengaturan mengembalikan pengaturan bawaan jika belum pernah diisi, dan nil jika
+	// tahun ajaran tidak ditemukan.
+	GetPengaturan(ctx context.Context, schemaName string, tahunAjaranID string) (*Pengaturan, error)
+	SavePengaturan(ctx context.Context, schemaName string, p Pengaturan) error
+	// GetNamaTahunAjaran mengembalikan nama seperti "2025/2026 Ganjil".
+	GetNamaTahunAjaran(ctx context.Context, schemaName string, tahunAjaranID string) (string, error)
+	// GetTahunAjaranKelas mengembalikan tahun ajaran sebuah kelas, atau string kosong jika
+	// kelas tidak ditemukan.
+	GetTahunAjaranKelas(ctx context.Context, schemaName string, kelasID string) (string, error)
+	// GetAgenda mengambil agenda yang beririsan dengan [dari, sampai]. Nil berarti tanpa batas.
+	GetAgenda(ctx context.Context, schemaName string, tahunAjaranID string, dari *time.Time, sampai *time.Time) ([]Agenda, error)
+	GetAgendaByID(ctx context.Context, schemaName string, id string) (*Agenda, error)
+	CreateAgenda(ctx context.Context, schemaName string, a *Agenda) error
+	UpdateAgenda(ctx context.Context, schemaName string, a *Agenda) error
+	DeleteAgenda(ctx context.Context, schemaName string, id string) error
+	// UpsertAgendaByUID menyimpan agenda hasil impor; agenda dengan uid yang sama diperbarui.
+	UpsertAgendaByUID(ctx context.Context, schemaName string, list []Agenda) (dibuat int, diperbarui int, err error)
+}
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewRepository membuat instance baru dari postgresRepository.
+func NewRepository(db *sql.DB) Repository {
+	return &postgresRepository{db: db}
+}
+
+func (r *postgresRepository) GetPengaturan(ctx context.Context, schemaName string, tahunAjaranID string) (*Pengaturan, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT ta.id, kp.tanggal_mulai, kp.tanggal_selesai, COALESCE(kp.hari_sekolah_per_minggu, $2)
+		FROM tahun_ajaran ta
+		LEFT JOIN kalender_pengaturan kp ON kp.tahun_ajaran_id = ta.id
+		WHERE ta.id = $1
+	`
+	var p Pengaturan
+	err = tx.QueryRowContext(ctx, query, tahunAjaranID, HariSekolahBawaan).Scan(&p.TahunAjaranID, &p.TanggalMulai, &p.TanggalSelesai, &p.HariSekolahPerMinggu)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("gagal mengambil pengaturan kalender: %w", err)
+	}
+	return &p, tx.Commit()
+}
+
+func (r *postgresRepository) SavePengaturan(ctx context.Context, schemaName string, p Pengaturan) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO kalender_pengaturan (tahun_ajaran_id, tanggal_mulai, tanggal_selesai, hari_sekolah_per_minggu)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (tahun_ajaran_id) DO UPDATE SET
+			tanggal_mulai = EXCLUDED.tanggal_mulai,
+			tanggal_selesai = EXCLUDED.tanggal_selesai,
+			hari_sekolah_per_minggu = EXCLUDED.hari_sekolah_per_minggu,
+			updated_at = NOW()
+	`
+	if _, err := tx.ExecContext(ctx, query, p.TahunAjaranID, p.TanggalMulai, p.TanggalSelesai, p.HariSekolahPerMinggu); err != nil {
+		return fmt.Errorf("gagal menyimpan pengaturan kalender: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (r *postgresRepository) GetNamaTahunAjaran(ctx context.Context, schemaName string, tahunAjaranID string) (string, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	var nama string
+	query := "SELECT nama_tahun_ajaran || ' ' || semester::text FROM tahun_ajaran WHERE id = $1"
+	if err := tx.QueryRowContext(ctx, query, tahunAjaranID).Scan(&nama); err != nil {
+		return "", fmt.Errorf("gagal mengambil tahun ajaran: %w", err)
+	}
+	return nama, tx.Commit()
+}
+
+func (r *postgresRepository) GetTahunAjaranKelas(ctx context.Context, schemaName string, kelasID string) (string, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	var tahunAjaranID string
+	if err := tx.QueryRowContext(ctx, "SELECT tahun_ajaran_id FROM kelas WHERE id = $1", kelasID).Scan(&tahunAjaranID); err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("gagal mengambil tahun ajaran kelas: %w", err)
+	}
+	return tahunAjaranID, tx.Commit()
+}
+
+const selectAgenda = `
+	SELECT id, tahun_ajaran_id, judul, jenis, tanggal_mulai, tanggal_selesai, keterangan, uid, created_at, updated_at
+	FROM kalender_agenda
+`
+
+func scanAgenda(scanner interface{ Scan(...interface{}) error }, a *Agenda) error {
+	return scanner.Scan(&a.ID, &a.TahunAjaranID, &a.Judul, &a.Jenis, &a.TanggalMulai, &a.TanggalSelesai,
+		&a.Keterangan, &a.UID, &a.CreatedAt, &a.UpdatedAt)
+}
+
+func (r *postgresRepository) GetAgenda(ctx context.Context, schemaName string, tahunAjaranID string, dari *time.Time, sampai *time.Time) ([]Agenda, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := selectAgenda + `
+		WHERE tahun_ajaran_id = $1
+			AND ($2::date IS NULL OR tanggal_selesai >= $2::date)
+			AND ($3::date IS NULL OR tanggal_mulai <= $3::date)
+		ORDER BY tanggal_mulai ASC, judul ASC
+	`
+	rows, err := tx.QueryContext(ctx, query, tahunAjaranID, dari, sampai)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil agenda kalender: %w", err)
+	}
+	defer rows.Close()
+
+	list := []Agenda{}
+	for rows.Next() {
+		var a Agenda
+		if err := scanAgenda(rows, &a); err != nil {
+			return nil, fmt.Errorf("gagal memindai agenda kalender: %w", err)
+		}
+		list = append(list, a)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+func (r *postgresRepository) GetAgendaByID(ctx context.Context, schemaName string, id string) (*Agenda, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var a Agenda
+	if err := scanAgenda(tx.QueryRowContext(ctx, selectAgenda+" WHERE id = $1", id), &a); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("gagal mengambil agenda kalender: %w", err)
+	}
+	return &a, tx.Commit()
+}
+
+func (r *postgresRepository) CreateAgenda(ctx context.Context, schemaName string, a *Agenda) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO kalender_agenda (tahun_ajaran_id, judul, jenis, tanggal_mulai, tanggal_selesai, keterangan)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`
+	err = tx.QueryRowContext(ctx, query, a.TahunAjaranID, a.Judul, a.Jenis, a.TanggalMulai, a.TanggalSelesai, a.Keterangan).
+		Scan(&a.ID, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("gagal membuat agenda kalender: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (r *postgresRepository) UpdateAgenda(ctx context.Context, schemaName string, a *Agenda) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE kalender_agenda
+		SET judul = $1, jenis = $2, tanggal_mulai = $3, tanggal_selesai = $4, keterangan = $5, updated_at = NOW()
+		WHERE id = $6
+		RETURNING updated_at
+	`
+	err = tx.QueryRowContext(ctx, query, a.Judul, a.Jenis, a.TanggalMulai, a.TanggalSelesai, a.Keterangan, a.ID).Scan(&a.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("gagal memperbarui agenda kalender: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (r *postgresRepository) DeleteAgenda(ctx context.Context, schemaName string, id string) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM kalender_agenda WHERE id = $1", id); err != nil {
+		return fmt.Errorf("gagal menghapus agenda kalender: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (r *postgresRepository) UpsertAgendaByUID(ctx context.Context, schemaName string, list []Agenda) (int, int, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+
+	// xmax = 0 hanya untuk baris yang baru disisipkan, bukan yang diperbarui.
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO kalender_agenda (tahun_ajaran_id, judul, jenis, tanggal_mulai, tanggal_selesai, keterangan, uid)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (tahun_ajaran_id, uid) WHERE uid IS NOT NULL DO UPDATE SET
+			judul = EXCLUDED.judul,
+			jenis = EXCLUDED.jenis,
+			tanggal_mulai = EXCLUDED.tanggal_mulai,
+			tanggal_selesai = EXCLUDED.tanggal_selesai,
+			keterangan = EXCLUDED.keterangan,
+			updated_at = NOW()
+		RETURNING (xmax = 0)
+	`)
+	if err != nil {
+		return 0, 0, fmt.Errorf("gagal mempersiapkan statement: %w", err)
+	}
+	defer stmt.Close()
+
+	var dibuat, diperbarui int
+	for _, a := range list {
+		var baru bool
+		if err := stmt.QueryRowContext(ctx, a.TahunAjaranID, a.Judul, a.Jenis, a.TanggalMulai, a.TanggalSelesai, a.Keterangan, a.UID).Scan(&baru); err != nil {
+			return 0, 0, fmt.Errorf("gagal menyimpan agenda %q: %w", a.Judul, err)
+		}
+		if baru {
+			dibuat++
+		} else {
+			diperbarui++
+		}
+	}
+	return dibuat, diperbarui, tx.Commit()
+}
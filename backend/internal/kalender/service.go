@@ -0,0 +1,450 @@
+// file: backend/internal/kalender/service.go
+package kalender
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"skoola/internal/audit"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrValidation = errors.New("validation failed")
+	// ErrBukanHariSekolah dikembalikan CekHariSekolah untuk hari libur dan akhir pekan.
+	ErrBukanHariSekolah = errors.New("bukan hari sekolah")
+)
+
+// Service mendefinisikan logika bisnis kalender akademik.
+type Service interface {
+	GetKalender(ctx context.Context, schemaName string, tahunAjaranID string, dari string, sampai string) (*Kalender, error)
+	UpdatePengaturan(ctx context.Context, schemaName string, tahunAjaranID string, input UpdatePengaturanInput) (*Pengaturan, error)
+	CreateAgenda(ctx context.Context, schemaName string, tahunAjaranID string, input UpsertAgendaInput) (*Agenda, error)
+	UpdateAgenda(ctx context.Context, schemaName string, tahunAjaranID string, id string, input UpsertAgendaInput) (*Agenda, error)
+	DeleteAgenda(ctx context.Context, schemaName string, tahunAjaranID string, id string) error
+	// GetHariEfektif menghitung hari sekolah pada rentang yang diminta, atau seluruh semester
+	// jika dari/sampai kosong. HariEfektif mencakup hari yang belum terjadi (untuk
+	// perencanaan), sedangkan HariEfektifBerjalan memakai aturan HitungHariEfektif.
+	GetHariEfektif(ctx context.Context, schemaName string, tahunAjaranID string, dari string, sampai string) (*HariEfektif, error)
+	// HitungHariEfektif dipakai modul lain. Rentang dipotong ke tanggal semester dan tidak
+	// melewati hari ini, karena hari yang belum terjadi belum bisa dihadiri; nil berarti
+	// rentang tidak diketahui karena tanggal semester belum diisi.
+	HitungHariEfektif(ctx context.Context, schemaName string, tahunAjaranID string, dari *time.Time, sampai *time.Time) (*int, error)
+	// CekHariSekolah mengembalikan error yang membungkus ErrBukanHariSekolah jika tanggal
+	// tersebut libur atau akhir pekan bagi kelas tersebut.
+	CekHariSekolah(ctx context.Context, schemaName string, kelasID string, tanggal time.Time) error
+	ImportICS(ctx context.Context, schemaName string, tahunAjaranID string, content []byte, jenisBawaan string) (*HasilImport, error)
+	// ExportICS mengembalikan isi berkas .ics dan nama filenya.
+	ExportICS(ctx context.Context, schemaName string, tahunAjaranID string) ([]byte, string, error)
+}
+
+type service struct {
+	repo     Repository
+	audit    audit.Recorder
+	validate *validator.Validate
+}
+
+// NewService membuat instance baru dari service kalender akademik.
+func NewService(repo Repository, auditLog audit.Recorder, validate *validator.Validate) Service {
+	return &service{repo: repo, audit: auditLog, validate: validate}
+}
+
+func (s *service) GetKalender(ctx context.Context, schemaName string, tahunAjaranID string, dari string, sampai string) (*Kalender, error) {
+	pengaturan, err := s.getPengaturan(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+	d, sp, err := parseRentang(dari, sampai)
+	if err != nil {
+		return nil, err
+	}
+	agenda, err := s.repo.GetAgenda(ctx, schemaName, tahunAjaranID, d, sp)
+	if err != nil {
+		return nil, err
+	}
+	return &Kalender{Pengaturan: *pengaturan, Agenda: agenda}, nil
+}
+
+func (s *service) UpdatePengaturan(ctx context.Context, schemaName string, tahunAjaranID string, input UpdatePengaturanInput) (*Pengaturan, error) {
+	if err := s.validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	before, err := s.getPengaturan(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+	mulai, selesai, err := parseRentang(input.TanggalMulai, input.TanggalSelesai)
+	if err != nil {
+		return nil, err
+	}
+
+	after := Pengaturan{TahunAjaranID: tahunAjaranID, TanggalMulai: mulai, TanggalSelesai: selesai, HariSekolahPerMinggu: input.HariSekolahPerMinggu}
+	if err := s.repo.SavePengaturan(ctx, schemaName, after); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionUpdate, Entity: "kalender_pengaturan", EntityID: tahunAjaranID, Before: before, After: after})
+	return &after, nil
+}
+
+func (s *service) CreateAgenda(ctx context.Context, schemaName string, tahunAjaranID string, input UpsertAgendaInput) (*Agenda, error) {
+	if _, err := s.getPengaturan(ctx, schemaName, tahunAjaranID); err != nil {
+		return nil, err
+	}
+	a, err := s.agendaFromInput(input)
+	if err != nil {
+		return nil, err
+	}
+	a.TahunAjaranID = tahunAjaranID
+	if err := s.repo.CreateAgenda(ctx, schemaName, a); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionCreate, Entity: "kalender_agenda", EntityID: a.ID, After: a})
+	return a, nil
+}
+
+func (s *service) UpdateAgenda(ctx context.Context, schemaName string, tahunAjaranID string, id string, input UpsertAgendaInput) (*Agenda, error) {
+	before, err := s.getAgenda(ctx, schemaName, tahunAjaranID, id)
+	if err != nil {
+		return nil, err
+	}
+	a, err := s.agendaFromInput(input)
+	if err != nil {
+		return nil, err
+	}
+	a.ID, a.TahunAjaranID, a.UID, a.CreatedAt = before.ID, before.TahunAjaranID, before.UID, before.CreatedAt
+	if err := s.repo.UpdateAgenda(ctx, schemaName, a); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionUpdate, Entity: "kalender_agenda", EntityID: id, Before: before, After: a})
+	return a, nil
+}
+
+func (s *service) DeleteAgenda(ctx context.Context, schemaName string, tahunAjaranID string, id string) error {
+	before, err := s.getAgenda(ctx, schemaName, tahunAjaranID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteAgenda(ctx, schemaName, id); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionDelete, Entity: "kalender_agenda", EntityID: id, Before: before})
+	return nil
+}
+
+func (s *service) GetHariEfektif(ctx context.Context, schemaName string, tahunAjaranID string, dari string, sampai string) (*HariEfektif, error) {
+	pengaturan, err := s.getPengaturan(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+	d, sp, err := parseRentang(dari, sampai)
+	if err != nil {
+		return nil, err
+	}
+	mulai, selesai := potongRentang(pengaturan, d, sp)
+	if mulai == nil || selesai == nil {
+		return nil, fmt.Errorf("%w: isi tanggal mulai dan selesai semester di pengaturan kalender, atau kirim dari dan sampai", ErrValidation)
+	}
+
+	agenda, err := s.repo.GetAgenda(ctx, schemaName, tahunAjaranID, mulai, selesai)
+	if err != nil {
+		return nil, err
+	}
+	hasil := &HariEfektif{
+		TahunAjaranID:        tahunAjaranID,
+		Dari:                 mulai.Format("2006-01-02"),
+		Sampai:               selesai.Format("2006-01-02"),
+		HariSekolahPerMinggu: pengaturan.HariSekolahPerMinggu,
+		PerBulan:             []HariEfektifBulan{},
+		Libur:                []Agenda{},
+	}
+	for _, a := range agenda {
+		if a.Jenis == JenisLibur {
+			hasil.Libur = append(hasil.Libur, a)
+		}
+	}
+	libur := tanggalLibur(agenda)
+	for t := *mulai; !t.After(*selesai); t = t.AddDate(0, 0, 1) {
+		if !hariSekolah(t, pengaturan.HariSekolahPerMinggu) {
+			continue
+		}
+		hasil.HariSekolah++
+		if libur[t.Format("2006-01-02")] != "" {
+			hasil.HariLibur++
+			continue
+		}
+		hasil.HariEfektif++
+		bulan := t.Format("2006-01")
+		if n := len(hasil.PerBulan); n == 0 || hasil.PerBulan[n-1].Bulan != bulan {
+			hasil.PerBulan = append(hasil.PerBulan, HariEfektifBulan{Bulan: bulan})
+		}
+		hasil.PerBulan[len(hasil.PerBulan)-1].HariEfektif++
+	}
+	hasil.HariEfektifBerjalan = hitungHari(pengaturan, libur, *mulai, *batasiHariIni(selesai))
+	return hasil, nil
+}
+
+func (s *service) HitungHariEfektif(ctx context.Context, schemaName string, tahunAjaranID string, dari *time.Time, sampai *time.Time) (*int, error) {
+	pengaturan, err := s.repo.GetPengaturan(ctx, schemaName, tahunAjaranID)
+	if err != nil || pengaturan == nil {
+		return nil, err
+	}
+	mulai, selesai := potongRentang(pengaturan, dari, sampai)
+	if mulai == nil || selesai == nil {
+		return nil, nil
+	}
+	selesai = batasiHariIni(selesai)
+	agenda, err := s.repo.GetAgenda(ctx, schemaName, tahunAjaranID, mulai, selesai)
+	if err != nil {
+		return nil, err
+	}
+	jumlah := hitungHari(pengaturan, tanggalLibur(agenda), *mulai, *selesai)
+	return &jumlah, nil
+}
+
+// hitungHari menghitung hari sekolah yang bukan hari libur pada [mulai, selesai].
+func hitungHari(p *Pengaturan, libur map[string]string, mulai time.Time, selesai time.Time) int {
+	jumlah := 0
+	for t := mulai; !t.After(selesai); t = t.AddDate(0, 0, 1) {
+		if hariSekolah(t, p.HariSekolahPerMinggu) && libur[t.Format("2006-01-02")] == "" {
+			jumlah++
+		}
+	}
+	return jumlah
+}
+
+// batasiHariIni memotong akhir rentang ke hari ini, karena hari yang belum terjadi belum
+// bisa dihadiri.
+func batasiHariIni(selesai *time.Time) *time.Time {
+	if hariIni := tanggalHariIni(); selesai.After(hariIni) {
+		return &hariIni
+	}
+	return selesai
+}
+
+func (s *service) CekHariSekolah(ctx context.Context, schemaName string, kelasID string, tanggal time.Time) error {
+	tahunAjaranID, err := s.repo.GetTahunAjaranKelas(ctx, schemaName, kelasID)
+	if err != nil || tahunAjaranID == "" {
+		return err
+	}
+	pengaturan, err := s.repo.GetPengaturan(ctx, schemaName, tahunAjaranID)
+	if err != nil || pengaturan == nil {
+		return err
+	}
+	if !hariSekolah(tanggal, pengaturan.HariSekolahPerMinggu) {
+		return fmt.Errorf("%w: %s adalah hari %s", ErrBukanHariSekolah, tanggal.Format("2006-01-02"), namaHari[tanggal.Weekday()])
+	}
+	agenda, err := s.repo.GetAgenda(ctx, schemaName, tahunAjaranID, &tanggal, &tanggal)
+	if err != nil {
+		return err
+	}
+	if judul := tanggalLibur(agenda)[tanggal.Format("2006-01-02")]; judul != "" {
+		return fmt.Errorf("%w: %s adalah hari libur (%s)", ErrBukanHariSekolah, tanggal.Format("2006-01-02"), judul)
+	}
+	return nil
+}
+
+func (s *service) ImportICS(ctx context.Context, schemaName string, tahunAjaranID string, content []byte, jenisBawaan string) (*HasilImport, error) {
+	pengaturan, err := s.getPengaturan(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+	if jenisBawaan == "" {
+		jenisBawaan = JenisKegiatan
+	}
+	jenisBawaan = strings.ToUpper(jenisBawaan)
+	if jenisBawaan != JenisLibur && jenisBawaan != JenisUjian && jenisBawaan != JenisKegiatan {
+		return nil, fmt.Errorf("%w: jenis harus LIBUR, UJIAN, atau KEGIATAN", ErrValidation)
+	}
+	events, pesan, err := parseICS(content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+
+	hasil := &HasilImport{Pesan: pesan}
+	hasil.Dilewati = len(pesan)
+	var list []Agenda
+	for _, e := range events {
+		// Agenda di luar semester (misalnya dari kalender libur nasional setahun penuh) dilewati.
+		if (pengaturan.TanggalMulai != nil && e.Selesai.Before(*pengaturan.TanggalMulai)) ||
+			(pengaturan.TanggalSelesai != nil && e.Mulai.After(*pengaturan.TanggalSelesai)) {
+			hasil.Dilewati++
+			continue
+		}
+		uid := uidAgenda(e)
+		a := Agenda{TahunAjaranID: tahunAjaranID, Judul: judulAgenda(e.Judul), Jenis: jenisDariKategori(e.Kategori, jenisBawaan),
+			TanggalMulai: e.Mulai, TanggalSelesai: e.Selesai, UID: &uid}
+		if k := strings.TrimSpace(e.Keterangan); k != "" {
+			a.Keterangan = &k
+		}
+		list = append(list, a)
+	}
+	if len(list) > 0 {
+		hasil.Dibuat, hasil.Diperbarui, err = s.repo.UpsertAgendaByUID(ctx, schemaName, list)
+		if err != nil {
+			return nil, err
+		}
+		s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionCreate, Entity: "kalender_impor", EntityID: tahunAjaranID, After: hasil})
+	}
+	return hasil, nil
+}
+
+func (s *service) ExportICS(ctx context.Context, schemaName string, tahunAjaranID string) ([]byte, string, error) {
+	if _, err := s.getPengaturan(ctx, schemaName, tahunAjaranID); err != nil {
+		return nil, "", err
+	}
+	nama, err := s.repo.GetNamaTahunAjaran(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return nil, "", err
+	}
+	agenda, err := s.repo.GetAgenda(ctx, schemaName, tahunAjaranID, nil, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	content := renderICS("Kalender Akademik "+nama, agenda, time.Now())
+	return content, fmt.Sprintf("kalender_%s.ics", fileSafe(nama)), nil
+}
+
+func (s *service) getPengaturan(ctx context.Context, schemaName string, tahunAjaranID string) (*Pengaturan, error) {
+	if _, err := uuid.Parse(tahunAjaranID); err != nil {
+		return nil, fmt.Errorf("%w: tahun_ajaran_id tidak valid", ErrValidation)
+	}
+	p, err := s.repo.GetPengaturan(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, sql.ErrNoRows
+	}
+	return p, nil
+}
+
+func (s *service) getAgenda(ctx context.Context, schemaName string, tahunAjaranID string, id string) (*Agenda, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, fmt.Errorf("%w: id agenda tidak valid", ErrValidation)
+	}
+	a, err := s.repo.GetAgendaByID(ctx, schemaName, id)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil || a.TahunAjaranID != tahunAjaranID {
+		return nil, sql.ErrNoRows
+	}
+	return a, nil
+}
+
+func (s *service) agendaFromInput(input UpsertAgendaInput) (*Agenda, error) {
+	if err := s.validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	mulai, _ := time.Parse("2006-01-02", input.TanggalMulai)
+	selesai := mulai
+	if input.TanggalSelesai != "" {
+		selesai, _ = time.Parse("2006-01-02", input.TanggalSelesai)
+	}
+	if selesai.Before(mulai) {
+		return nil, fmt.Errorf("%w: tanggal_selesai tidak boleh sebelum tanggal_mulai", ErrValidation)
+	}
+	a := &Agenda{Judul: strings.TrimSpace(input.Judul), Jenis: input.Jenis, TanggalMulai: mulai, TanggalSelesai: selesai}
+	if k := strings.TrimSpace(input.Keterangan); k != "" {
+		a.Keterangan = &k
+	}
+	return a, nil
+}
+
+var namaHari = []string{"Minggu", "Senin", "Selasa", "Rabu", "Kamis", "Jumat", "Sabtu"}
+
+// hariSekolah bernilai true untuk Senin-Jumat, ditambah Sabtu pada sekolah enam hari.
+func hariSekolah(t time.Time, hariPerMinggu int) bool {
+	switch t.Weekday() {
+	case time.Sunday:
+		return false
+	case time.Saturday:
+		return hariPerMinggu >= 6
+	}
+	return true
+}
+
+// tanggalLibur memetakan setiap tanggal (YYYY-MM-DD) yang jatuh pada agenda libur ke judulnya.
+func tanggalLibur(agenda []Agenda) map[string]string {
+	libur := make(map[string]string)
+	for _, a := range agenda {
+		if a.Jenis != JenisLibur {
+			continue
+		}
+		for t := a.TanggalMulai; !t.After(a.TanggalSelesai); t = t.AddDate(0, 0, 1) {
+			if _, ok := libur[t.Format("2006-01-02")]; !ok {
+				libur[t.Format("2006-01-02")] = a.Judul
+			}
+		}
+	}
+	return libur
+}
+
+// potongRentang membatasi [dari, sampai] ke tanggal semester. Batas yang kosong memakai
+// tanggal semester; hasil nil berarti batas tersebut tidak diketahui.
+func potongRentang(p *Pengaturan, dari *time.Time, sampai *time.Time) (*time.Time, *time.Time) {
+	mulai, selesai := dari, sampai
+	if p.TanggalMulai != nil && (mulai == nil || mulai.Before(*p.TanggalMulai)) {
+		mulai = p.TanggalMulai
+	}
+	if p.TanggalSelesai != nil && (selesai == nil || selesai.After(*p.TanggalSelesai)) {
+		selesai = p.TanggalSelesai
+	}
+	if mulai != nil && selesai != nil && selesai.Before(*mulai) {
+		// Rentang di luar semester: tidak ada hari efektif.
+		kosong := mulai.AddDate(0, 0, -1)
+		return mulai, &kosong
+	}
+	return mulai, selesai
+}
+
+// sekarang dapat diganti di test.
+var sekarang = time.Now
+
+// tanggalHariIni mengembalikan tanggal hari ini (UTC tengah malam), sebanding dengan
+// tanggal hasil parse YYYY-MM-DD.
+func tanggalHariIni() time.Time {
+	y, m, d := sekarang().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func parseRentang(dari string, sampai string) (*time.Time, *time.Time, error) {
+	var d, s *time.Time
+	if dari != "" {
+		t, err := time.Parse("2006-01-02", dari)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: tanggal mulai harus berformat YYYY-MM-DD", ErrValidation)
+		}
+		d = &t
+	}
+	if sampai != "" {
+		t, err := time.Parse("2006-01-02", sampai)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: tanggal selesai harus berformat YYYY-MM-DD", ErrValidation)
+		}
+		s = &t
+	}
+	if d != nil && s != nil && s.Before(*d) {
+		return nil, nil, fmt.Errorf("%w: tanggal selesai tidak boleh sebelum tanggal mulai", ErrValidation)
+	}
+	return d, s, nil
+}
+
+// fileSafe mengubah nama tahun ajaran menjadi nama file yang aman.
+func fileSafe(name string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,88 @@
+// file: backend/internal/kalender/service_test.go
+package kalender
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// stubRepository hanya mengisi method yang dipakai perhitungan hari efektif.
+type stubRepository struct {
+	Repository
+	pengaturan *Pengaturan
+	agenda     []Agenda
+}
+
+func (r *stubRepository) GetPengaturan(ctx context.Context, schemaName string, tahunAjaranID string) (*Pengaturan, error) {
+	return r.pengaturan, nil
+}
+
+func (r *stubRepository) GetAgenda(ctx context.Context, schemaName string, tahunAjaranID string, dari *time.Time, sampai *time.Time) ([]Agenda, error) {
+	return r.agenda, nil
+}
+
+func TestHariEfektifBerjalan(t *testing.T) {
+	tgl := func(s string) time.Time {
+		t, _ := time.Parse("2006-01-02", s)
+		return t
+	}
+	ptr := func(s string) *time.Time {
+		t := tgl(s)
+		return &t
+	}
+	asli := sekarang
+	defer func() { sekarang = asli }()
+
+	// Semester 1-31 Agustus 2026, lima hari sekolah per minggu, libur 17 Agustus (Senin).
+	repo := &stubRepository{
+		pengaturan: &Pengaturan{TanggalMulai: ptr("2026-08-01"), TanggalSelesai: ptr("2026-08-31"), HariSekolahPerMinggu: 5},
+		agenda:     []Agenda{{Judul: "HUT RI", Jenis: JenisLibur, TanggalMulai: tgl("2026-08-17"), TanggalSelesai: tgl("2026-08-17")}},
+	}
+	s := &service{repo: repo}
+	const taID = "6f1c2d3e-4a5b-4c6d-8e7f-9a0b1c2d3e4f"
+
+	tests := []struct {
+		name         string
+		hariIni      string
+		dari, sampai string
+		wantRencana  int
+		wantBerjalan int
+	}{
+		{name: "semester sudah selesai", hariIni: "2026-10-18", wantRencana: 20, wantBerjalan: 20},
+		{name: "di tengah semester", hariIni: "2026-08-14", wantRencana: 20, wantBerjalan: 10},
+		{name: "hari ini hari libur", hariIni: "2026-08-17", wantRencana: 20, wantBerjalan: 10},
+		{name: "semester belum dimulai", hariIni: "2026-07-20", wantRencana: 20, wantBerjalan: 0},
+		{name: "rentang diminta sebelum hari ini", hariIni: "2026-08-20", dari: "2026-08-10", sampai: "2026-08-18", wantRencana: 6, wantBerjalan: 6},
+		{name: "rentang diminta melewati hari ini", hariIni: "2026-08-12", dari: "2026-08-10", sampai: "2026-08-18", wantRencana: 6, wantBerjalan: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sekarang = func() time.Time { return tgl(tt.hariIni).Add(9 * time.Hour) }
+
+			hasil, err := s.GetHariEfektif(context.Background(), "tenant", taID, tt.dari, tt.sampai)
+			if err != nil {
+				t.Fatalf("GetHariEfektif error: %v", err)
+			}
+			var dari, sampai *time.Time
+			if tt.dari != "" {
+				dari, sampai = ptr(tt.dari), ptr(tt.sampai)
+			}
+			jumlah, err := s.HitungHariEfektif(context.Background(), "tenant", taID, dari, sampai)
+			if err != nil || jumlah == nil {
+				t.Fatalf("HitungHariEfektif = %v, %v", jumlah, err)
+			}
+
+			if hasil.HariEfektif != tt.wantRencana {
+				t.Errorf("HariEfektif = %d, ingin %d", hasil.HariEfektif, tt.wantRencana)
+			}
+			if hasil.HariEfektifBerjalan != tt.wantBerjalan {
+				t.Errorf("HariEfektifBerjalan = %d, ingin %d", hasil.HariEfektifBerjalan, tt.wantBerjalan)
+			}
+			if *jumlah != hasil.HariEfektifBerjalan {
+				t.Errorf("HitungHariEfektif = %d, tidak sama dengan HariEfektifBerjalan %d", *jumlah, hasil.HariEfektifBerjalan)
+			}
+		})
+	}
+}
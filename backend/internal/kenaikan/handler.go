@@ -0,0 +1,66 @@
+// file: backend/internal/kenaikan/handler.go
+package kenaikan
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"skoola/internal/middleware"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{service: s}
+}
+
+// GetUsulan adalah handler untuk GET /kenaikan-kelas/usulan?tahun_ajaran_asal_id=...&tahun_ajaran_tujuan_id=...
+func (h *Handler) GetUsulan(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	asalID := r.URL.Query().Get("tahun_ajaran_asal_id")
+	tujuanID := r.URL.Query().Get("tahun_ajaran_tujuan_id")
+
+	usulan, err := h.service.GetUsulan(r.Context(), schemaName, asalID, tujuanID)
+	if err != nil {
+		writeError(w, "Gagal menyusun usulan kenaikan kelas: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(usulan)
+}
+
+// Proses adalah handler untuk POST /kenaikan-kelas/proses.
+func (h *Handler) Proses(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	var input ProsesInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	hasil, err := h.service.Proses(r.Context(), schemaName, input)
+	if err != nil {
+		writeError(w, "Gagal memproses kenaikan kelas: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(hasil)
+}
+
+func writeError(w http.ResponseWriter, prefix string, err error) {
+	switch {
+	case errors.Is(err, ErrValidation):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Data tidak ditemukan", http.StatusNotFound)
+	default:
+		http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,101 @@
+// file: backend/internal/kenaikan/model.go
+package kenaikan
+
+// Keputusan akhir tahun untuk setiap siswa.
+const (
+	KeputusanNaik    = "NAIK"
+	KeputusanTinggal = "TINGGAL"
+	KeputusanLulus   = "LULUS"
+)
+
+// KelasTujuan adalah kelas pada tahun ajaran tujuan.
+type KelasTujuan struct {
+	KelasID       string `json:"kelas_id"`
+	NamaKelas     string `json:"nama_kelas"`
+	TingkatanID   int    `json:"tingkatan_id"`
+	NamaTingkatan string `json:"nama_tingkatan"`
+}
+
+// UsulanSiswa adalah usulan penempatan satu siswa. KelasTujuanID nil untuk siswa yang
+// lulus. SudahDitempatkan bernilai true jika siswa sudah menjadi anggota kelas di
+// tahun ajaran tujuan sehingga tidak dapat diproses lagi.
+type UsulanSiswa struct {
+	AnggotaKelasID   string  `json:"anggota_kelas_id"`
+	StudentID        string  `json:"student_id"`
+	NamaSiswa        string  `json:"nama_siswa"`
+	NIS              *string `json:"nis"`
+	Keputusan        string  `json:"keputusan"`
+	KelasTujuanID    *string `json:"kelas_tujuan_id"`
+	SudahDitempatkan bool    `json:"sudah_ditempatkan"`
+}
+
+// UsulanKelas adalah usulan untuk satu kelas asal. KelasNaik adalah kelas tujuan
+// pada tingkatan berikutnya (nil jika tingkatan terakhir atau belum dibuat) dan
+// KelasTinggal adalah kelas pada tingkatan yang sama untuk siswa yang tinggal kelas.
+type UsulanKelas struct {
+	KelasID       string        `json:"kelas_id"`
+	NamaKelas     string        `json:"nama_kelas"`
+	TingkatanID   int           `json:"tingkatan_id"`
+	NamaTingkatan string        `json:"nama_tingkatan"`
+	TingkatAkhir  bool          `json:"tingkat_akhir"`
+	KelasNaik     *KelasTujuan  `json:"kelas_naik"`
+	KelasTinggal  *KelasTujuan  `json:"kelas_tinggal"`
+	Siswa         []UsulanSiswa `json:"siswa"`
+}
+
+// Usulan adalah hasil langkah pertama wizard kenaikan kelas.
+type Usulan struct {
+	TahunAjaranAsalID   string        `json:"tahun_ajaran_asal_id"`
+	TahunAjaranTujuanID string        `json:"tahun_ajaran_tujuan_id"`
+	KelasTujuan         []KelasTujuan `json:"kelas_tujuan"`
+	Kelas               []UsulanKelas `json:"kelas"`
+}
+
+// ProsesSiswaInput adalah keputusan admin untuk satu siswa.
+type ProsesSiswaInput struct {
+	AnggotaKelasID string  `json:"anggota_kelas_id" validate:"required,uuid"`
+	Keputusan      string  `json:"keputusan" validate:"required,oneof=NAIK TINGGAL LULUS"`
+	KelasTujuanID  *string `json:"kelas_tujuan_id" validate:"omitempty,uuid"`
+}
+
+// ProsesInput adalah DTO untuk memproses kenaikan kelas dan kelulusan.
+type ProsesInput struct {
+	TahunAjaranAsalID   string             `json:"tahun_ajaran_asal_id" validate:"required,uuid"`
+	TahunAjaranTujuanID string             `json:"tahun_ajaran_tujuan_id" validate:"required,uuid"`
+	TanggalKejadian     string             `json:"tanggal_kejadian" validate:"omitempty,datetime=2006-01-02"`
+	Siswa               []ProsesSiswaInput `json:"siswa" validate:"required,min=1,dive"`
+}
+
+// HasilProses adalah ringkasan hasil kenaikan kelas.
+type HasilProses struct {
+	Naik    int `json:"naik"`
+	Tinggal int `json:"tinggal"`
+	Lulus   int `json:"lulus"`
+}
+
+// kelasRow adalah satu kelas beserta tingkatannya.
+type kelasRow struct {
+	KelasID       string
+	NamaKelas     string
+	TingkatanID   int
+	NamaTingkatan string
+}
+
+// anggotaRow adalah satu anggota kelas pada tahun ajaran asal.
+type anggotaRow struct {
+	AnggotaKelasID string
+	KelasID        string
+	StudentID      string
+	NamaSiswa      string
+	NIS            *string
+}
+
+// penempatan adalah satu baris yang ditulis saat proses: keanggotaan kelas baru
+// (KelasTujuanID kosong untuk siswa lulus) dan riwayat akademiknya.
+type penempatan struct {
+	StudentID     string
+	KelasTujuanID string
+	Status        string
+	KelasTingkat  string
+	Keterangan    string
+}
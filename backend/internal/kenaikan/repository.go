@@ -0,0 +1,206 @@
+// file: backend/internal/kenaikan/repository.go
+package kenaikan
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"skoola/pkg/database"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Repository mendefinisikan interface untuk interaksi database.
+type Repository interface {
+	// GetUrutanTingkatan mengembalikan ID tingkatan sesuai urutannya.
+	GetUrutanTingkatan(ctx context.Context, schemaName string) ([]int, error)
+	GetKelas(ctx context.Context, schemaName string, tahunAjaranID string) ([]kelasRow, error)
+	GetAnggota(ctx context.Context, schemaName string, tahunAjaranID string) ([]anggotaRow, error)
+	// GetSiswaDitempatkan mengembalikan siswa yang sudah menjadi anggota kelas di tahun ajaran.
+	GetSiswaDitempatkan(ctx context.Context, schemaName string, tahunAjaranID string) (map[string]bool, error)
+	Proses(ctx context.Context, schemaName string, tanggal time.Time, list []penempatan) error
+}
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewRepository membuat instance baru dari postgresRepository.
+func NewRepository(db *sql.DB) Repository {
+	return &postgresRepository{db: db}
+}
+
+func (r *postgresRepository) GetUrutanTingkatan(ctx context.Context, schemaName string) ([]int, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `SELECT id FROM tingkatan ORDER BY urutan ASC NULLS LAST, id ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil urutan tingkatan: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("gagal memindai tingkatan: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return ids, tx.Commit()
+}
+
+func (r *postgresRepository) GetKelas(ctx context.Context, schemaName string, tahunAjaranID string) ([]kelasRow, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT k.id, k.nama_kelas, t.id, t.nama_tingkatan
+		FROM kelas k
+		JOIN tingkatan t ON k.tingkatan_id = t.id
+		WHERE k.tahun_ajaran_id = $1
+		ORDER BY t.urutan ASC NULLS LAST, t.id ASC, k.nama_kelas ASC
+	`
+	rows, err := tx.QueryContext(ctx, query, tahunAjaranID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil kelas: %w", err)
+	}
+	defer rows.Close()
+
+	var list []kelasRow
+	for rows.Next() {
+		var k kelasRow
+		if err := rows.Scan(&k.KelasID, &k.NamaKelas, &k.TingkatanID, &k.NamaTingkatan); err != nil {
+			return nil, fmt.Errorf("gagal memindai kelas: %w", err)
+		}
+		list = append(list, k)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+func (r *postgresRepository) GetAnggota(ctx context.Context, schemaName string, tahunAjaranID string) ([]anggotaRow, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT ak.id, ak.kelas_id, s.id, s.nama_lengkap, s.nis
+		FROM anggota_kelas ak
+		JOIN kelas k ON ak.kelas_id = k.id
+		JOIN students s ON ak.student_id = s.id
+		WHERE k.tahun_ajaran_id = $1 AND ak.tanggal_keluar IS NULL
+		ORDER BY ak.urutan ASC, s.nama_lengkap ASC
+	`
+	rows, err := tx.QueryContext(ctx, query, tahunAjaranID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil anggota kelas: %w", err)
+	}
+	defer rows.Close()
+
+	var list []anggotaRow
+	for rows.Next() {
+		var a anggotaRow
+		if err := rows.Scan(&a.AnggotaKelasID, &a.KelasID, &a.StudentID, &a.NamaSiswa, &a.NIS); err != nil {
+			return nil, fmt.Errorf("gagal memindai anggota kelas: %w", err)
+		}
+		list = append(list, a)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+func (r *postgresRepository) GetSiswaDitempatkan(ctx context.Context, schemaName string, tahunAjaranID string) (map[string]bool, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT DISTINCT ak.student_id
+		FROM anggota_kelas ak
+		JOIN kelas k ON ak.kelas_id = k.id
+		WHERE k.tahun_ajaran_id = $1
+	`
+	rows, err := tx.QueryContext(ctx, query, tahunAjaranID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil siswa yang sudah ditempatkan: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("gagal memindai siswa yang sudah ditempatkan: %w", err)
+		}
+		result[id] = true
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, tx.Commit()
+}
+
+// Proses menulis semua keanggotaan kelas baru dan riwayat akademik dalam satu transaksi.
+func (r *postgresRepository) Proses(ctx context.Context, schemaName string, tanggal time.Time, list []penempatan) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	anggotaStmt, err := tx.PrepareContext(ctx, "INSERT INTO anggota_kelas (id, kelas_id, student_id, urutan) VALUES ($1, $2, $3, $4)")
+	if err != nil {
+		return err
+	}
+	defer anggotaStmt.Close()
+
+	riwayatStmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO riwayat_akademik (id, student_id, status, tanggal_kejadian, kelas_tingkat, keterangan)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`)
+	if err != nil {
+		return err
+	}
+	defer riwayatStmt.Close()
+
+	urutan := make(map[string]int)
+	for _, p := range list {
+		if p.KelasTujuanID != "" {
+			if _, ok := urutan[p.KelasTujuanID]; !ok {
+				var maxUrutan sql.NullInt64
+				if err := tx.QueryRowContext(ctx, "SELECT MAX(urutan) FROM anggota_kelas WHERE kelas_id = $1", p.KelasTujuanID).Scan(&maxUrutan); err != nil {
+					return fmt.Errorf("gagal mendapatkan urutan maksimal: %w", err)
+				}
+				urutan[p.KelasTujuanID] = int(maxUrutan.Int64)
+			}
+			urutan[p.KelasTujuanID]++
+			if _, err := anggotaStmt.ExecContext(ctx, uuid.New().String(), p.KelasTujuanID, p.StudentID, urutan[p.KelasTujuanID]); err != nil {
+				return fmt.Errorf("gagal menempatkan siswa %s: %w", p.StudentID, err)
+			}
+		}
+		if _, err := riwayatStmt.ExecContext(ctx, uuid.New().String(), p.StudentID, p.Status, tanggal, p.KelasTingkat, p.Keterangan); err != nil {
+			return fmt.Errorf("gagal mencatat riwayat akademik siswa %s: %w", p.StudentID, err)
+		}
+	}
+	return tx.Commit()
+}
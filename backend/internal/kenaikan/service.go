@@ -0,0 +1,274 @@
+// file: backend/internal/kenaikan/service.go
+package kenaikan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"skoola/internal/audit"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+var ErrValidation = errors.New("validation failed")
+
+// Service mendefinisikan logika bisnis kenaikan kelas dan kelulusan akhir tahun.
+type Service interface {
+	GetUsulan(ctx context.Context, schemaName string, tahunAjaranAsalID string, tahunAjaranTujuanID string) (*Usulan, error)
+	Proses(ctx context.Context, schemaName string, input ProsesInput) (*HasilProses, error)
+}
+
+type service struct {
+	repo     Repository
+	audit    audit.Recorder
+	validate *validator.Validate
+}
+
+// NewService membuat instance baru dari service kenaikan kelas.
+func NewService(repo Repository, auditLog audit.Recorder, validate *validator.Validate) Service {
+	return &service{repo: repo, audit: auditLog, validate: validate}
+}
+
+// data adalah semua bahan yang dibutuhkan untuk menyusun usulan maupun memvalidasi proses.
+type data struct {
+	// indeks posisi tingkatan dalam urutan; tingkatan terakhir berarti lulus.
+	posisi       map[int]int
+	tingkatan    []int
+	kelasAsal    []kelasRow
+	kelasTujuan  []kelasRow
+	anggota      []anggotaRow
+	ditempatkan  map[string]bool
+	kelasAsalMap map[string]kelasRow
+	tujuanMap    map[string]kelasRow
+}
+
+func (s *service) muat(ctx context.Context, schemaName string, asalID string, tujuanID string) (*data, error) {
+	if _, err := uuid.Parse(asalID); err != nil {
+		return nil, fmt.Errorf("%w: tahun ajaran asal tidak valid", ErrValidation)
+	}
+	if _, err := uuid.Parse(tujuanID); err != nil {
+		return nil, fmt.Errorf("%w: tahun ajaran tujuan tidak valid", ErrValidation)
+	}
+	if asalID == tujuanID {
+		return nil, fmt.Errorf("%w: tahun ajaran asal dan tujuan harus berbeda", ErrValidation)
+	}
+
+	d := &data{posisi: make(map[int]int), kelasAsalMap: make(map[string]kelasRow), tujuanMap: make(map[string]kelasRow)}
+	var err error
+	if d.tingkatan, err = s.repo.GetUrutanTingkatan(ctx, schemaName); err != nil {
+		return nil, err
+	}
+	for i, id := range d.tingkatan {
+		d.posisi[id] = i
+	}
+	if d.kelasAsal, err = s.repo.GetKelas(ctx, schemaName, asalID); err != nil {
+		return nil, err
+	}
+	if d.kelasTujuan, err = s.repo.GetKelas(ctx, schemaName, tujuanID); err != nil {
+		return nil, err
+	}
+	if d.anggota, err = s.repo.GetAnggota(ctx, schemaName, asalID); err != nil {
+		return nil, err
+	}
+	if d.ditempatkan, err = s.repo.GetSiswaDitempatkan(ctx, schemaName, tujuanID); err != nil {
+		return nil, err
+	}
+	for _, k := range d.kelasAsal {
+		d.kelasAsalMap[k.KelasID] = k
+	}
+	for _, k := range d.kelasTujuan {
+		d.tujuanMap[k.KelasID] = k
+	}
+	return d, nil
+}
+
+// tingkatAkhir bernilai true jika tingkatan adalah tingkatan terakhir sehingga siswanya lulus.
+func (d *data) tingkatAkhir(tingkatanID int) bool {
+	return d.posisi[tingkatanID] == len(d.tingkatan)-1
+}
+
+func (s *service) GetUsulan(ctx context.Context, schemaName string, tahunAjaranAsalID string, tahunAjaranTujuanID string) (*Usulan, error) {
+	d, err := s.muat(ctx, schemaName, tahunAjaranAsalID, tahunAjaranTujuanID)
+	if err != nil {
+		return nil, err
+	}
+
+	usulan := &Usulan{
+		TahunAjaranAsalID:   tahunAjaranAsalID,
+		TahunAjaranTujuanID: tahunAjaranTujuanID,
+		KelasTujuan:         make([]KelasTujuan, 0, len(d.kelasTujuan)),
+		Kelas:               make([]UsulanKelas, 0, len(d.kelasAsal)),
+	}
+	for _, k := range d.kelasTujuan {
+		usulan.KelasTujuan = append(usulan.KelasTujuan, toKelasTujuan(k))
+	}
+
+	siswaPerKelas := make(map[string][]anggotaRow)
+	for _, a := range d.anggota {
+		siswaPerKelas[a.KelasID] = append(siswaPerKelas[a.KelasID], a)
+	}
+
+	for _, k := range d.kelasAsal {
+		uk := UsulanKelas{
+			KelasID:       k.KelasID,
+			NamaKelas:     k.NamaKelas,
+			TingkatanID:   k.TingkatanID,
+			NamaTingkatan: k.NamaTingkatan,
+			TingkatAkhir:  d.tingkatAkhir(k.TingkatanID),
+			Siswa:         []UsulanSiswa{},
+		}
+		if !uk.TingkatAkhir {
+			uk.KelasNaik = pilihKelas(k, d.kelasTujuan, d.tingkatan[d.posisi[k.TingkatanID]+1])
+		}
+		uk.KelasTinggal = pilihKelas(k, d.kelasTujuan, k.TingkatanID)
+
+		for _, a := range siswaPerKelas[k.KelasID] {
+			us := UsulanSiswa{
+				AnggotaKelasID:   a.AnggotaKelasID,
+				StudentID:        a.StudentID,
+				NamaSiswa:        a.NamaSiswa,
+				NIS:              a.NIS,
+				Keputusan:        KeputusanNaik,
+				SudahDitempatkan: d.ditempatkan[a.StudentID],
+			}
+			if uk.TingkatAkhir {
+				us.Keputusan = KeputusanLulus
+			} else if uk.KelasNaik != nil {
+				id := uk.KelasNaik.KelasID
+				us.KelasTujuanID = &id
+			}
+			uk.Siswa = append(uk.Siswa, us)
+		}
+		usulan.Kelas = append(usulan.Kelas, uk)
+	}
+	return usulan, nil
+}
+
+func (s *service) Proses(ctx context.Context, schemaName string, input ProsesInput) (*HasilProses, error) {
+	if err := s.validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	tanggal := time.Now()
+	if input.TanggalKejadian != "" {
+		tanggal, _ = time.Parse("2006-01-02", input.TanggalKejadian)
+	}
+
+	d, err := s.muat(ctx, schemaName, input.TahunAjaranAsalID, input.TahunAjaranTujuanID)
+	if err != nil {
+		return nil, err
+	}
+	anggotaMap := make(map[string]anggotaRow, len(d.anggota))
+	for _, a := range d.anggota {
+		anggotaMap[a.AnggotaKelasID] = a
+	}
+
+	hasil := &HasilProses{}
+	list := make([]penempatan, 0, len(input.Siswa))
+	diproses := make(map[string]bool, len(input.Siswa))
+	for _, in := range input.Siswa {
+		a, ok := anggotaMap[in.AnggotaKelasID]
+		if !ok {
+			return nil, fmt.Errorf("%w: anggota kelas %s tidak terdaftar di tahun ajaran asal", ErrValidation, in.AnggotaKelasID)
+		}
+		if diproses[a.StudentID] {
+			return nil, fmt.Errorf("%w: siswa %s diproses lebih dari sekali", ErrValidation, a.NamaSiswa)
+		}
+		diproses[a.StudentID] = true
+		if d.ditempatkan[a.StudentID] {
+			return nil, fmt.Errorf("%w: siswa %s sudah menjadi anggota kelas di tahun ajaran tujuan", ErrValidation, a.NamaSiswa)
+		}
+		asal := d.kelasAsalMap[a.KelasID]
+
+		if in.Keputusan == KeputusanLulus {
+			if in.KelasTujuanID != nil {
+				return nil, fmt.Errorf("%w: siswa %s yang lulus tidak boleh memiliki kelas tujuan", ErrValidation, a.NamaSiswa)
+			}
+			list = append(list, penempatan{
+				StudentID:    a.StudentID,
+				Status:       "Lulus",
+				KelasTingkat: asal.NamaKelas,
+				Keterangan:   "Lulus",
+			})
+			hasil.Lulus++
+			continue
+		}
+
+		if in.KelasTujuanID == nil {
+			return nil, fmt.Errorf("%w: kelas tujuan siswa %s wajib diisi", ErrValidation, a.NamaSiswa)
+		}
+		tujuan, ok := d.tujuanMap[*in.KelasTujuanID]
+		if !ok {
+			return nil, fmt.Errorf("%w: kelas tujuan siswa %s tidak terdaftar di tahun ajaran tujuan", ErrValidation, a.NamaSiswa)
+		}
+		p := penempatan{StudentID: a.StudentID, KelasTujuanID: tujuan.KelasID, Status: "Aktif", KelasTingkat: tujuan.NamaKelas}
+		switch in.Keputusan {
+		case KeputusanNaik:
+			if d.posisi[tujuan.TingkatanID] <= d.posisi[asal.TingkatanID] {
+				return nil, fmt.Errorf("%w: kelas tujuan siswa %s harus berada di tingkatan yang lebih tinggi", ErrValidation, a.NamaSiswa)
+			}
+			p.Keterangan = fmt.Sprintf("Naik Kelas dari %s ke %s", asal.NamaKelas, tujuan.NamaKelas)
+			hasil.Naik++
+		case KeputusanTinggal:
+			if tujuan.TingkatanID != asal.TingkatanID {
+				return nil, fmt.Errorf("%w: siswa %s yang tinggal kelas harus ditempatkan di tingkatan yang sama", ErrValidation, a.NamaSiswa)
+			}
+			p.Keterangan = fmt.Sprintf("Tinggal Kelas di %s", tujuan.NamaKelas)
+			hasil.Tinggal++
+		}
+		list = append(list, p)
+	}
+
+	if err := s.repo.Proses(ctx, schemaName, tanggal, list); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionCreate, Entity: "kenaikan_kelas", EntityID: input.TahunAjaranTujuanID, After: input})
+	return hasil, nil
+}
+
+func toKelasTujuan(k kelasRow) KelasTujuan {
+	return KelasTujuan{KelasID: k.KelasID, NamaKelas: k.NamaKelas, TingkatanID: k.TingkatanID, NamaTingkatan: k.NamaTingkatan}
+}
+
+// pilihKelas memilih kelas di tingkatan tujuan yang paling mirip dengan kelas asal.
+// Rombel dicocokkan dari nama kelas tanpa nama tingkatannya (misalnya "VII A" dan
+// "VIII A" sama-sama rombel "A"); jika tidak ada yang cocok, kelas pertama dipakai.
+func pilihKelas(asal kelasRow, kandidat []kelasRow, tingkatanID int) *KelasTujuan {
+	var pertama *kelasRow
+	rombel := namaRombel(asal.NamaKelas, asal.NamaTingkatan)
+	for i := range kandidat {
+		k := kandidat[i]
+		if k.TingkatanID != tingkatanID {
+			continue
+		}
+		if pertama == nil {
+			pertama = &kandidat[i]
+		}
+		if namaRombel(k.NamaKelas, k.NamaTingkatan) == rombel {
+			kt := toKelasTujuan(k)
+			return &kt
+		}
+	}
+	if pertama == nil {
+		return nil
+	}
+	kt := toKelasTujuan(*pertama)
+	return &kt
+}
+
+// namaRombel mengambil bagian pembeda rombel dari nama kelas. Jika nama kelas tidak
+// diawali nama tingkatan, kata terakhirnya yang dipakai.
+func namaRombel(namaKelas string, namaTingkatan string) string {
+	nama := strings.ToUpper(strings.TrimSpace(namaKelas))
+	tingkat := strings.ToUpper(strings.TrimSpace(namaTingkatan))
+	if tingkat != "" && strings.HasPrefix(nama, tingkat) {
+		return strings.TrimSpace(strings.TrimPrefix(nama, tingkat))
+	}
+	kata := strings.Fields(nama)
+	if len(kata) == 0 {
+		return ""
+	}
+	return kata[len(kata)-1]
+}
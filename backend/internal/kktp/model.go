@@ -0,0 +1,56 @@
+// file: backend/internal/kktp/model.go
+package kktp
+
+import "time"
+
+// KKTP merepresentasikan data dari tabel 'kktp' (kriteria ketercapaian tujuan pembelajaran).
+// Cakupannya satu TP, atau mapel dan/atau tingkatan.
+type KKTP struct {
+	ID                   int       `json:"id"`
+	MataPelajaranID      *string   `json:"mata_pelajaran_id"`
+	NamaMapel            *string   `json:"nama_mapel,omitempty"`
+	TingkatanID          *int      `json:"tingkatan_id"`
+	NamaTingkatan        *string   `json:"nama_tingkatan,omitempty"`
+	TujuanPembelajaranID *int      `json:"tujuan_pembelajaran_id"`
+	DeskripsiTujuan      *string   `json:"deskripsi_tujuan,omitempty"`
+	NilaiMinimal         float64   `json:"nilai_minimal"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+// UpsertKKTPInput adalah DTO untuk membuat atau memperbarui KKTP.
+// Isi TujuanPembelajaranID saja, atau MataPelajaranID dan/atau TingkatanID.
+type UpsertKKTPInput struct {
+	MataPelajaranID      *string `json:"mata_pelajaran_id" validate:"omitempty,uuid"`
+	TingkatanID          *int    `json:"tingkatan_id"`
+	TujuanPembelajaranID *int    `json:"tujuan_pembelajaran_id"`
+	NilaiMinimal         float64 `json:"nilai_minimal" validate:"min=0,max=100"`
+}
+
+// Ambang adalah KKTP yang berlaku untuk satu pengajar kelas (mapel di satu kelas).
+// Mapel adalah ambang tingkat mapel/tingkatan; PerTP menimpanya untuk TP tertentu.
+type Ambang struct {
+	Mapel *float64        `json:"mapel"`
+	PerTP map[int]float64 `json:"per_tp"`
+}
+
+// UntukTP mengembalikan ambang untuk nilai milik TP tertentu. tpID nil berarti nilai
+// tidak terkait TP (misalnya nilai ujian), sehingga ambang mapel yang dipakai.
+func (a Ambang) UntukTP(tpID *int) *float64 {
+	if tpID != nil {
+		if v, ok := a.PerTP[*tpID]; ok {
+			return &v
+		}
+	}
+	return a.Mapel
+}
+
+// Tuntas menilai apakah nilai mencapai ambang. Hasilnya nil jika nilai
+// atau ambangnya belum ada.
+func Tuntas(nilai *float64, ambang *float64) *bool {
+	if nilai == nil || ambang == nil {
+		return nil
+	}
+	tuntas := *nilai >= *ambang
+	return &tuntas
+}
@@ -0,0 +1,221 @@
+// file: backend/internal/kktp/repository.go
+package kktp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"skoola/pkg/database"
+)
+
+// Repository mendefinisikan interface untuk interaksi database.
+type Repository interface {
+	Create(ctx context.Context, schemaName string, input UpsertKKTPInput) (*KKTP, error)
+	GetAll(ctx context.Context, schemaName string) ([]KKTP, error)
+	GetByID(ctx context.Context, schemaName string, id int) (*KKTP, error)
+	Update(ctx context.Context, schemaName string, id int, input UpsertKKTPInput) error
+	Delete(ctx context.Context, schemaName string, id int) error
+	GetAmbang(ctx context.Context, schemaName string, pengajarKelasID string) (*Ambang, error)
+}
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewRepository membuat instance baru dari postgresRepository.
+func NewRepository(db *sql.DB) Repository {
+	return &postgresRepository{db: db}
+}
+
+const selectKKTP = `
+	SELECT k.id, k.mata_pelajaran_id, mp.nama_mapel, k.tingkatan_id, t.nama_tingkatan,
+	       k.tujuan_pembelajaran_id, tp.deskripsi_tujuan, k.nilai_minimal::float8, k.created_at, k.updated_at
+	FROM kktp k
+	LEFT JOIN mata_pelajaran mp ON k.mata_pelajaran_id = mp.id
+	LEFT JOIN tingkatan t ON k.tingkatan_id = t.id
+	LEFT JOIN tujuan_pembelajaran tp ON k.tujuan_pembelajaran_id = tp.id
+`
+
+func scanKKTP(row interface{ Scan(...interface{}) error }) (*KKTP, error) {
+	var k KKTP
+	err := row.Scan(&k.ID, &k.MataPelajaranID, &k.NamaMapel, &k.TingkatanID, &k.NamaTingkatan,
+		&k.TujuanPembelajaranID, &k.DeskripsiTujuan, &k.NilaiMinimal, &k.CreatedAt, &k.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+func (r *postgresRepository) Create(ctx context.Context, schemaName string, input UpsertKKTPInput) (*KKTP, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO kktp (mata_pelajaran_id, tingkatan_id, tujuan_pembelajaran_id, nilai_minimal)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+	var id int
+	if err := tx.QueryRowContext(ctx, query, input.MataPelajaranID, input.TingkatanID, input.TujuanPembelajaranID, input.NilaiMinimal).Scan(&id); err != nil {
+		return nil, fmt.Errorf("gagal menyimpan KKTP: %w", err)
+	}
+
+	k, err := scanKKTP(tx.QueryRowContext(ctx, selectKKTP+` WHERE k.id = $1`, id))
+	if err != nil {
+		return nil, fmt.Errorf("gagal memindai data KKTP setelah dibuat: %w", err)
+	}
+	return k, tx.Commit()
+}
+
+func (r *postgresRepository) GetAll(ctx context.Context, schemaName string) ([]KKTP, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := selectKKTP + ` ORDER BY mp.nama_mapel ASC NULLS LAST, t.urutan ASC NULLS LAST, k.tujuan_pembelajaran_id ASC NULLS FIRST`
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query get all KKTP: %w", err)
+	}
+	defer rows.Close()
+
+	list := []KKTP{}
+	for rows.Next() {
+		k, err := scanKKTP(rows)
+		if err != nil {
+			return nil, fmt.Errorf("gagal memindai data KKTP: %w", err)
+		}
+		list = append(list, *k)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+func (r *postgresRepository) GetByID(ctx context.Context, schemaName string, id int) (*KKTP, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	k, err := scanKKTP(tx.QueryRowContext(ctx, selectKKTP+` WHERE k.id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("gagal mengambil data KKTP: %w", err)
+	}
+	return k, tx.Commit()
+}
+
+func (r *postgresRepository) Update(ctx context.Context, schemaName string, id int, input UpsertKKTPInput) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE kktp
+		SET mata_pelajaran_id = $1, tingkatan_id = $2, tujuan_pembelajaran_id = $3, nilai_minimal = $4, updated_at = NOW()
+		WHERE id = $5
+	`
+	result, err := tx.ExecContext(ctx, query, input.MataPelajaranID, input.TingkatanID, input.TujuanPembelajaranID, input.NilaiMinimal, id)
+	if err != nil {
+		return fmt.Errorf("gagal memperbarui KKTP: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
+func (r *postgresRepository) Delete(ctx context.Context, schemaName string, id int) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM kktp WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("gagal menghapus KKTP: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
+// GetAmbang mengambil KKTP yang berlaku untuk mapel dan tingkatan pengajar kelas,
+// beserta KKTP khusus untuk TP milik pengajar kelas tersebut.
+func (r *postgresRepository) GetAmbang(ctx context.Context, schemaName string, pengajarKelasID string) (*Ambang, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	ambang := &Ambang{PerTP: make(map[int]float64)}
+
+	// Prioritas: mapel + tingkatan, mapel saja, lalu tingkatan saja.
+	mapelQuery := `
+		SELECT k.nilai_minimal::float8
+		FROM pengajar_kelas pk
+		JOIN kelas kl ON pk.kelas_id = kl.id
+		JOIN kktp k ON k.tujuan_pembelajaran_id IS NULL
+			AND (k.mata_pelajaran_id = pk.mata_pelajaran_id OR k.mata_pelajaran_id IS NULL)
+			AND (k.tingkatan_id = kl.tingkatan_id OR k.tingkatan_id IS NULL)
+		WHERE pk.id = $1
+		ORDER BY (k.mata_pelajaran_id IS NOT NULL) DESC, (k.tingkatan_id IS NOT NULL) DESC
+		LIMIT 1
+	`
+	var mapel float64
+	err = tx.QueryRowContext(ctx, mapelQuery, pengajarKelasID).Scan(&mapel)
+	switch {
+	case err == nil:
+		ambang.Mapel = &mapel
+	case err != sql.ErrNoRows:
+		return nil, fmt.Errorf("gagal mengambil KKTP mapel: %w", err)
+	}
+
+	tpQuery := `
+		SELECT k.tujuan_pembelajaran_id, k.nilai_minimal::float8
+		FROM kktp k
+		JOIN tujuan_pembelajaran tp ON k.tujuan_pembelajaran_id = tp.id
+		JOIN materi_pembelajaran m ON tp.materi_pembelajaran_id = m.id
+		WHERE m.pengajar_kelas_id = $1
+	`
+	rows, err := tx.QueryContext(ctx, tpQuery, pengajarKelasID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil KKTP per TP: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var tpID int
+		var nilai float64
+		if err := rows.Scan(&tpID, &nilai); err != nil {
+			return nil, fmt.Errorf("gagal memindai KKTP per TP: %w", err)
+		}
+		ambang.PerTP[tpID] = nilai
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return ambang, tx.Commit()
+}
@@ -0,0 +1,78 @@
+// file: backend/internal/kktp/service.go
+package kktp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var ErrValidation = errors.New("validation failed")
+
+// Service mendefinisikan interface untuk logika bisnis KKTP.
+type Service interface {
+	Create(ctx context.Context, schemaName string, input UpsertKKTPInput) (*KKTP, error)
+	GetAll(ctx context.Context, schemaName string) ([]KKTP, error)
+	GetByID(ctx context.Context, schemaName string, id int) (*KKTP, error)
+	Update(ctx context.Context, schemaName string, id int, input UpsertKKTPInput) error
+	Delete(ctx context.Context, schemaName string, id int) error
+	GetAmbang(ctx context.Context, schemaName string, pengajarKelasID string) (*Ambang, error)
+}
+
+type service struct {
+	repo     Repository
+	validate *validator.Validate
+}
+
+// NewService membuat instance baru dari service.
+func NewService(repo Repository, validate *validator.Validate) Service {
+	return &service{repo: repo, validate: validate}
+}
+
+func (s *service) validateInput(input UpsertKKTPInput) error {
+	if err := s.validate.Struct(input); err != nil {
+		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	if input.TujuanPembelajaranID != nil {
+		if input.MataPelajaranID != nil || input.TingkatanID != nil {
+			return fmt.Errorf("%w: KKTP per TP tidak boleh diisi bersama mapel atau tingkatan", ErrValidation)
+		}
+		return nil
+	}
+	if input.MataPelajaranID == nil && input.TingkatanID == nil {
+		return fmt.Errorf("%w: isi TP, mapel, atau tingkatan sebagai cakupan KKTP", ErrValidation)
+	}
+	return nil
+}
+
+func (s *service) Create(ctx context.Context, schemaName string, input UpsertKKTPInput) (*KKTP, error) {
+	if err := s.validateInput(input); err != nil {
+		return nil, err
+	}
+	return s.repo.Create(ctx, schemaName, input)
+}
+
+func (s *service) GetAll(ctx context.Context, schemaName string) ([]KKTP, error) {
+	return s.repo.GetAll(ctx, schemaName)
+}
+
+func (s *service) GetByID(ctx context.Context, schemaName string, id int) (*KKTP, error) {
+	return s.repo.GetByID(ctx, schemaName, id)
+}
+
+func (s *service) Update(ctx context.Context, schemaName string, id int, input UpsertKKTPInput) error {
+	if err := s.validateInput(input); err != nil {
+		return err
+	}
+	return s.repo.Update(ctx, schemaName, id, input)
+}
+
+func (s *service) Delete(ctx context.Context, schemaName string, id int) error {
+	return s.repo.Delete(ctx, schemaName, id)
+}
+
+func (s *service) GetAmbang(ctx context.Context, schemaName string, pengajarKelasID string) (*Ambang, error) {
+	return s.repo.GetAmbang(ctx, schemaName, pengajarKelasID)
+}
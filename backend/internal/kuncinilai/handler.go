@@ -0,0 +1,121 @@
+// file: backend/internal/kuncinilai/handler.go
+package kuncinilai
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"skoola/internal/access"
+	"skoola/internal/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{service: s}
+}
+
+// GetStatus adalah handler untuk GET /kunci-nilai/tahun-ajaran/{tahunAjaranID}.
+func (h *Handler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	tahunAjaranID := chi.URLParam(r, "tahunAjaranID")
+
+	status, err := h.service.GetStatus(r.Context(), schemaName, tahunAjaranID)
+	if err != nil {
+		writeError(w, "Gagal mengambil status kunci nilai: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// KunciPengajar adalah handler untuk POST /kunci-nilai/pengajar/{pengajarKelasID}/kunci.
+func (h *Handler) KunciPengajar(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	pengajarKelasID := chi.URLParam(r, "pengajarKelasID")
+
+	status, err := h.service.KunciPengajar(r.Context(), schemaName, access.ActorFromContext(r.Context()), pengajarKelasID)
+	if err != nil {
+		writeError(w, "Gagal mengunci nilai: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// BukaPengajar adalah handler untuk POST /kunci-nilai/pengajar/{pengajarKelasID}/buka.
+func (h *Handler) BukaPengajar(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	pengajarKelasID := chi.URLParam(r, "pengajarKelasID")
+
+	var input BukaKunciInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	status, err := h.service.BukaPengajar(r.Context(), schemaName, access.ActorFromContext(r.Context()), pengajarKelasID, input)
+	if err != nil {
+		writeError(w, "Gagal membuka kunci nilai: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// KunciTahunAjaran adalah handler untuk POST /kunci-nilai/tahun-ajaran/{tahunAjaranID}/kunci.
+func (h *Handler) KunciTahunAjaran(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	tahunAjaranID := chi.URLParam(r, "tahunAjaranID")
+
+	status, err := h.service.KunciTahunAjaran(r.Context(), schemaName, access.ActorFromContext(r.Context()), tahunAjaranID)
+	if err != nil {
+		writeError(w, "Gagal mengunci nilai: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// BukaTahunAjaran adalah handler untuk POST /kunci-nilai/tahun-ajaran/{tahunAjaranID}/buka.
+func (h *Handler) BukaTahunAjaran(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	tahunAjaranID := chi.URLParam(r, "tahunAjaranID")
+
+	var input BukaKunciInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	status, err := h.service.BukaTahunAjaran(r.Context(), schemaName, access.ActorFromContext(r.Context()), tahunAjaranID, input)
+	if err != nil {
+		writeError(w, "Gagal membuka kunci nilai: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+func writeError(w http.ResponseWriter, prefix string, err error) {
+	switch {
+	case errors.Is(err, access.ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, ErrValidation):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Data tidak ditemukan", http.StatusNotFound)
+	default:
+		http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,58 @@
+// file: backend/internal/kuncinilai/model.go
+package kuncinilai
+
+import "time"
+
+// Asal status kunci sebuah pengajar kelas.
+const (
+	SumberPengajarKelas = "PENGAJAR_KELAS"
+	SumberTahunAjaran   = "TAHUN_AJARAN"
+)
+
+// KunciNilai merepresentasikan data dari tabel 'kunci_nilai'. PengajarKelasID nil
+// berarti kunci berlaku untuk seluruh tahun ajaran.
+type KunciNilai struct {
+	ID              int        `json:"id"`
+	TahunAjaranID   string     `json:"tahun_ajaran_id"`
+	PengajarKelasID *string    `json:"pengajar_kelas_id"`
+	Terkunci        bool       `json:"terkunci"`
+	DikunciOleh     *string    `json:"dikunci_oleh"`
+	DikunciPada     *time.Time `json:"dikunci_pada"`
+	DibukaOleh      *string    `json:"dibuka_oleh"`
+	DibukaPada      *time.Time `json:"dibuka_pada"`
+	AlasanBuka      *string    `json:"alasan_buka"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// StatusMapel adalah status kunci efektif satu pengajar kelas. Sumber nil berarti
+// nilai belum pernah difinalisasi.
+type StatusMapel struct {
+	PengajarKelasID string     `json:"pengajar_kelas_id"`
+	KelasID         string     `json:"kelas_id"`
+	NamaKelas       string     `json:"nama_kelas"`
+	NamaMapel       string     `json:"nama_mapel"`
+	NamaGuru        string     `json:"nama_guru"`
+	Terkunci        bool       `json:"terkunci"`
+	Sumber          *string    `json:"sumber"`
+	DikunciPada     *time.Time `json:"dikunci_pada"`
+	DibukaPada      *time.Time `json:"dibuka_pada"`
+	AlasanBuka      *string    `json:"alasan_buka"`
+}
+
+// PernahDikunci bernilai true jika nilai pernah difinalisasi lalu dibuka kembali.
+func (s StatusMapel) PernahDikunci() bool {
+	return s.Sumber != nil && !s.Terkunci
+}
+
+// StatusTahunAjaran adalah status kunci seluruh pengajar kelas pada satu tahun ajaran.
+type StatusTahunAjaran struct {
+	TahunAjaranID string        `json:"tahun_ajaran_id"`
+	Kunci         *KunciNilai   `json:"kunci"`
+	Mapel         []StatusMapel `json:"mapel"`
+}
+
+// BukaKunciInput adalah DTO untuk membuka kunci nilai. Alasan wajib diisi
+// karena dicatat di audit log.
+type BukaKunciInput struct {
+	Alasan string `json:"alasan" validate:"required,min=5,max=500"`
+}
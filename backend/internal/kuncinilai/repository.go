@@ -0,0 +1,208 @@
+// file: backend/internal/kuncinilai/repository.go
+package kuncinilai
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"skoola/pkg/database"
+
+	"github.com/lib/pq"
+)
+
+// Repository mendefinisikan interface untuk interaksi database.
+type Repository interface {
+	GetTahunAjaranPengajar(ctx context.Context, schemaName string, pengajarKelasID string) (string, error)
+	GetKunci(ctx context.Context, schemaName string, tahunAjaranID string, pengajarKelasID *string) (*KunciNilai, error)
+	GetStatusByPengajar(ctx context.Context, schemaName string, pengajarKelasIDs []string) ([]StatusMapel, error)
+	GetStatusByTahunAjaran(ctx context.Context, schemaName string, tahunAjaranID string) ([]StatusMapel, error)
+	Kunci(ctx context.Context, schemaName string, tahunAjaranID string, pengajarKelasID *string, userID string) error
+	Buka(ctx context.Context, schemaName string, tahunAjaranID string, pengajarKelasID *string, userID string, alasan string) error
+}
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewRepository membuat instance baru dari postgresRepository.
+func NewRepository(db *sql.DB) Repository {
+	return &postgresRepository{db: db}
+}
+
+func (r *postgresRepository) GetTahunAjaranPengajar(ctx context.Context, schemaName string, pengajarKelasID string) (string, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT k.tahun_ajaran_id FROM pengajar_kelas pk JOIN kelas k ON pk.kelas_id = k.id WHERE pk.id = $1`
+	var tahunAjaranID string
+	if err := tx.QueryRowContext(ctx, query, pengajarKelasID).Scan(&tahunAjaranID); err != nil {
+		return "", err
+	}
+	return tahunAjaranID, tx.Commit()
+}
+
+// GetKunci mengambil baris kunci untuk tahun ajaran (pengajarKelasID nil) atau satu
+// pengajar kelas. Hasilnya nil jika belum pernah dikunci.
+func (r *postgresRepository) GetKunci(ctx context.Context, schemaName string, tahunAjaranID string, pengajarKelasID *string) (*KunciNilai, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT id, tahun_ajaran_id, pengajar_kelas_id, terkunci, dikunci_oleh, dikunci_pada,
+		       dibuka_oleh, dibuka_pada, alasan_buka, updated_at
+		FROM kunci_nilai
+		WHERE tahun_ajaran_id = $1 AND pengajar_kelas_id IS NOT DISTINCT FROM $2::uuid
+	`
+	var k KunciNilai
+	err = tx.QueryRowContext(ctx, query, tahunAjaranID, pengajarKelasID).Scan(
+		&k.ID, &k.TahunAjaranID, &k.PengajarKelasID, &k.Terkunci, &k.DikunciOleh, &k.DikunciPada,
+		&k.DibukaOleh, &k.DibukaPada, &k.AlasanBuka, &k.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("gagal mengambil status kunci nilai: %w", err)
+	}
+	return &k, tx.Commit()
+}
+
+// selectStatus menghitung status efektif: kunci per pengajar kelas menimpa kunci tahun ajaran.
+const selectStatus = `
+	SELECT pk.id, k.id, k.nama_kelas, mp.nama_mapel, t.nama_lengkap,
+	       COALESCE(kp.terkunci, kt.terkunci, FALSE),
+	       CASE WHEN kp.id IS NOT NULL THEN 'PENGAJAR_KELAS' WHEN kt.id IS NOT NULL THEN 'TAHUN_AJARAN' END,
+	       CASE WHEN kp.id IS NOT NULL THEN kp.dikunci_pada ELSE kt.dikunci_pada END,
+	       CASE WHEN kp.id IS NOT NULL THEN kp.dibuka_pada ELSE kt.dibuka_pada END,
+	       CASE WHEN kp.id IS NOT NULL THEN kp.alasan_buka ELSE kt.alasan_buka END
+	FROM pengajar_kelas pk
+	JOIN kelas k ON pk.kelas_id = k.id
+	JOIN mata_pelajaran mp ON pk.mata_pelajaran_id = mp.id
+	JOIN teachers t ON pk.teacher_id = t.id
+	LEFT JOIN kunci_nilai kp ON kp.pengajar_kelas_id = pk.id
+	LEFT JOIN kunci_nilai kt ON kt.tahun_ajaran_id = k.tahun_ajaran_id AND kt.pengajar_kelas_id IS NULL
+`
+
+func (r *postgresRepository) GetStatusByPengajar(ctx context.Context, schemaName string, pengajarKelasIDs []string) ([]StatusMapel, error) {
+	return r.queryStatus(ctx, schemaName, selectStatus+` WHERE pk.id = ANY($1::uuid[])`, pq.Array(pengajarKelasIDs))
+}
+
+func (r *postgresRepository) GetStatusByTahunAjaran(ctx context.Context, schemaName string, tahunAjaranID string) ([]StatusMapel, error) {
+	return r.queryStatus(ctx, schemaName, selectStatus+` WHERE k.tahun_ajaran_id = $1 ORDER BY k.nama_kelas, mp.nama_mapel`, tahunAjaranID)
+}
+
+func (r *postgresRepository) queryStatus(ctx context.Context, schemaName string, query string, arg interface{}) ([]StatusMapel, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil status kunci nilai: %w", err)
+	}
+	defer rows.Close()
+
+	list := []StatusMapel{}
+	for rows.Next() {
+		var s StatusMapel
+		if err := rows.Scan(&s.PengajarKelasID, &s.KelasID, &s.NamaKelas, &s.NamaMapel, &s.NamaGuru,
+			&s.Terkunci, &s.Sumber, &s.DikunciPada, &s.DibukaPada, &s.AlasanBuka); err != nil {
+			return nil, fmt.Errorf("gagal memindai status kunci nilai: %w", err)
+		}
+		list = append(list, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+// Kunci memfinalisasi nilai. Mengunci tahun ajaran juga mengunci kembali semua
+// pengajar kelas yang sebelumnya dibuka secara terpisah.
+func (r *postgresRepository) Kunci(ctx context.Context, schemaName string, tahunAjaranID string, pengajarKelasID *string, userID string) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := upsertKunci(ctx, tx, tahunAjaranID, pengajarKelasID, true, userID, nil); err != nil {
+		return err
+	}
+	if pengajarKelasID == nil {
+		query := `
+			UPDATE kunci_nilai SET terkunci = TRUE, dikunci_oleh = $2, dikunci_pada = NOW(), updated_at = NOW()
+			WHERE tahun_ajaran_id = $1 AND pengajar_kelas_id IS NOT NULL AND terkunci = FALSE
+		`
+		if _, err := tx.ExecContext(ctx, query, tahunAjaranID, userID); err != nil {
+			return fmt.Errorf("gagal mengunci nilai per mapel: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// Buka membuka kunci nilai. Membuka satu pengajar kelas saat tahun ajarannya terkunci
+// membuat baris pengecualian; membuka tahun ajaran membuka semua pengajar kelasnya.
+func (r *postgresRepository) Buka(ctx context.Context, schemaName string, tahunAjaranID string, pengajarKelasID *string, userID string, alasan string) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := upsertKunci(ctx, tx, tahunAjaranID, pengajarKelasID, false, userID, &alasan); err != nil {
+		return err
+	}
+	if pengajarKelasID == nil {
+		query := `
+			UPDATE kunci_nilai SET terkunci = FALSE, dibuka_oleh = $2, dibuka_pada = NOW(), alasan_buka = $3, updated_at = NOW()
+			WHERE tahun_ajaran_id = $1 AND pengajar_kelas_id IS NOT NULL AND terkunci = TRUE
+		`
+		if _, err := tx.ExecContext(ctx, query, tahunAjaranID, userID, alasan); err != nil {
+			return fmt.Errorf("gagal membuka kunci nilai per mapel: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+func upsertKunci(ctx context.Context, tx *sql.Tx, tahunAjaranID string, pengajarKelasID *string, terkunci bool, userID string, alasan *string) error {
+	conflict := `(tahun_ajaran_id) WHERE pengajar_kelas_id IS NULL`
+	if pengajarKelasID != nil {
+		conflict = `(pengajar_kelas_id) WHERE pengajar_kelas_id IS NOT NULL`
+	}
+
+	var query string
+	if terkunci {
+		query = `
+			INSERT INTO kunci_nilai (tahun_ajaran_id, pengajar_kelas_id, terkunci, dikunci_oleh, dikunci_pada)
+			VALUES ($1, $2, TRUE, $3, NOW())
+			ON CONFLICT ` + conflict + ` DO UPDATE SET
+				terkunci = TRUE, dikunci_oleh = EXCLUDED.dikunci_oleh, dikunci_pada = NOW(), updated_at = NOW()
+		`
+		_, err := tx.ExecContext(ctx, query, tahunAjaranID, pengajarKelasID, userID)
+		if err != nil {
+			return fmt.Errorf("gagal mengunci nilai: %w", err)
+		}
+		return nil
+	}
+
+	query = `
+		INSERT INTO kunci_nilai (tahun_ajaran_id, pengajar_kelas_id, terkunci, dibuka_oleh, dibuka_pada, alasan_buka)
+		VALUES ($1, $2, FALSE, $3, NOW(), $4)
+		ON CONFLICT ` + conflict + ` DO UPDATE SET
+			terkunci = FALSE, dibuka_oleh = EXCLUDED.dibuka_oleh, dibuka_pada = NOW(),
+			alasan_buka = EXCLUDED.alasan_buka, updated_at = NOW()
+	`
+	if _, err := tx.ExecContext(ctx, query, tahunAjaranID, pengajarKelasID, userID, alasan); err != nil {
+		return fmt.Errorf("gagal membuka kunci nilai: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+// file: backend/internal/kuncinilai/service.go
+package kuncinilai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"skoola/internal/access"
+	"skoola/internal/audit"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+var ErrValidation = errors.New("validation failed")
+
+// ErrLocked dikembalikan jika nilai yang akan diubah sudah difinalisasi.
+var ErrLocked = errors.New("nilai sudah difinalisasi dan dikunci")
+
+// Service mendefinisikan logika bisnis finalisasi nilai.
+type Service interface {
+	GetStatus(ctx context.Context, schemaName string, tahunAjaranID string) (*StatusTahunAjaran, error)
+	KunciPengajar(ctx context.Context, schemaName string, actor access.Actor, pengajarKelasID string) (*StatusMapel, error)
+	BukaPengajar(ctx context.Context, schemaName string, actor access.Actor, pengajarKelasID string, input BukaKunciInput) (*StatusMapel, error)
+	KunciTahunAjaran(ctx context.Context, schemaName string, actor access.Actor, tahunAjaranID string) (*StatusTahunAjaran, error)
+	BukaTahunAjaran(ctx context.Context, schemaName string, actor access.Actor, tahunAjaranID string, input BukaKunciInput) (*StatusTahunAjaran, error)
+	// EnsureUnlocked menolak perubahan dengan ErrLocked jika salah satu pengajar kelas
+	// yang dirujuk target sudah dikunci. Hasilnya adalah pengajar kelas yang pernah
+	// dikunci lalu dibuka kembali, untuk dicatat dengan RecordPascaKunci.
+	EnsureUnlocked(ctx context.Context, schemaName string, target access.Target) ([]string, error)
+	// RecordPascaKunci mencatat perubahan nilai setelah finalisasi ke audit log.
+	RecordPascaKunci(ctx context.Context, schemaName string, pengajarKelasIDs []string, perubahan interface{})
+}
+
+type service struct {
+	repo       Repository
+	accessRepo access.Repository
+	access     access.Service
+	audit      audit.Recorder
+	validate   *validator.Validate
+}
+
+// NewService membuat instance baru dari service kunci nilai.
+func NewService(repo Repository, accessRepo access.Repository, accessService access.Service, auditLog audit.Recorder, validate *validator.Validate) Service {
+	return &service{repo: repo, accessRepo: accessRepo, access: accessService, audit: auditLog, validate: validate}
+}
+
+func (s *service) GetStatus(ctx context.Context, schemaName string, tahunAjaranID string) (*StatusTahunAjaran, error) {
+	if _, err := uuid.Parse(tahunAjaranID); err != nil {
+		return nil, fmt.Errorf("%w: tahun_ajaran_id tidak valid", ErrValidation)
+	}
+	kunci, err := s.repo.GetKunci(ctx, schemaName, tahunAjaranID, nil)
+	if err != nil {
+		return nil, err
+	}
+	mapel, err := s.repo.GetStatusByTahunAjaran(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+	return &StatusTahunAjaran{TahunAjaranID: tahunAjaranID, Kunci: kunci, Mapel: mapel}, nil
+}
+
+// KunciPengajar memfinalisasi nilai satu mapel di kelas. Guru boleh mengunci mapel yang ia ajar.
+func (s *service) KunciPengajar(ctx context.Context, schemaName string, actor access.Actor, pengajarKelasID string) (*StatusMapel, error) {
+	if _, err := uuid.Parse(pengajarKelasID); err != nil {
+		return nil, fmt.Errorf("%w: pengajar_kelas_id tidak valid", ErrValidation)
+	}
+	if err := s.access.AuthorizeWrite(ctx, schemaName, actor, access.Target{PengajarKelasIDs: []string{pengajarKelasID}}); err != nil {
+		return nil, err
+	}
+	tahunAjaranID, err := s.repo.GetTahunAjaranPengajar(ctx, schemaName, pengajarKelasID)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := s.getStatusPengajar(ctx, schemaName, pengajarKelasID)
+	if err != nil {
+		return nil, err
+	}
+	if before.Terkunci {
+		return nil, fmt.Errorf("%w: nilai %s kelas %s sudah dikunci", ErrValidation, before.NamaMapel, before.NamaKelas)
+	}
+	if err := s.repo.Kunci(ctx, schemaName, tahunAjaranID, &pengajarKelasID, actor.UserID); err != nil {
+		return nil, err
+	}
+	after, err := s.getStatusPengajar(ctx, schemaName, pengajarKelasID)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionLock, Entity: "kunci_nilai", EntityID: pengajarKelasID, Before: before, After: after})
+	return after, nil
+}
+
+// BukaPengajar membuka kunci nilai satu mapel di kelas. Hanya admin yang boleh membuka kunci.
+func (s *service) BukaPengajar(ctx context.Context, schemaName string, actor access.Actor, pengajarKelasID string, input BukaKunciInput) (*StatusMapel, error) {
+	if !actor.IsAdmin() {
+		return nil, access.ErrForbidden
+	}
+	if _, err := uuid.Parse(pengajarKelasID); err != nil {
+		return nil, fmt.Errorf("%w: pengajar_kelas_id tidak valid", ErrValidation)
+	}
+	if err := s.validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	tahunAjaranID, err := s.repo.GetTahunAjaranPengajar(ctx, schemaName, pengajarKelasID)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := s.getStatusPengajar(ctx, schemaName, pengajarKelasID)
+	if err != nil {
+		return nil, err
+	}
+	if !before.Terkunci {
+		return nil, fmt.Errorf("%w: nilai %s kelas %s tidak sedang dikunci", ErrValidation, before.NamaMapel, before.NamaKelas)
+	}
+	if err := s.repo.Buka(ctx, schemaName, tahunAjaranID, &pengajarKelasID, actor.UserID, input.Alasan); err != nil {
+		return nil, err
+	}
+	after, err := s.getStatusPengajar(ctx, schemaName, pengajarKelasID)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionUnlock, Entity: "kunci_nilai", EntityID: pengajarKelasID, Before: before, After: after})
+	return after, nil
+}
+
+// KunciTahunAjaran memfinalisasi nilai semua mapel pada tahun ajaran (akhir semester).
+func (s *service) KunciTahunAjaran(ctx context.Context, schemaName string, actor access.Actor, tahunAjaranID string) (*StatusTahunAjaran, error) {
+	if !actor.IsAdmin() {
+		return nil, access.ErrForbidden
+	}
+	before, err := s.GetStatus(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Kunci(ctx, schemaName, tahunAjaranID, nil, actor.UserID); err != nil {
+		return nil, err
+	}
+	after, err := s.GetStatus(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionLock, Entity: "kunci_nilai", EntityID: tahunAjaranID, Before: before.Kunci, After: after.Kunci})
+	return after, nil
+}
+
+// BukaTahunAjaran membuka kunci nilai semua mapel pada tahun ajaran.
+func (s *service) BukaTahunAjaran(ctx context.Context, schemaName string, actor access.Actor, tahunAjaranID string, input BukaKunciInput) (*StatusTahunAjaran, error) {
+	if !actor.IsAdmin() {
+		return nil, access.ErrForbidden
+	}
+	if err := s.validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	before, err := s.GetStatus(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+	if before.Kunci == nil || !before.Kunci.Terkunci {
+		return nil, fmt.Errorf("%w: nilai tahun ajaran ini tidak sedang dikunci", ErrValidation)
+	}
+	if err := s.repo.Buka(ctx, schemaName, tahunAjaranID, nil, actor.UserID, input.Alasan); err != nil {
+		return nil, err
+	}
+	after, err := s.GetStatus(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionUnlock, Entity: "kunci_nilai", EntityID: tahunAjaranID, Before: before.Kunci, After: after.Kunci})
+	return after, nil
+}
+
+func (s *service) EnsureUnlocked(ctx context.Context, schemaName string, target access.Target) ([]string, error) {
+	scopes, err := s.accessRepo.GetPengajarKelasScopes(ctx, schemaName, target)
+	if err != nil {
+		return nil, err
+	}
+	if len(scopes) == 0 {
+		return nil, nil
+	}
+	ids := make([]string, 0, len(scopes))
+	for _, sc := range scopes {
+		ids = append(ids, sc.PengajarKelasID)
+	}
+
+	status, err := s.repo.GetStatusByPengajar(ctx, schemaName, ids)
+	if err != nil {
+		return nil, err
+	}
+	var terkunci []string
+	var pascaKunci []string
+	for _, st := range status {
+		if st.Terkunci {
+			terkunci = append(terkunci, fmt.Sprintf("%s kelas %s", st.NamaMapel, st.NamaKelas))
+		} else if st.PernahDikunci() {
+			pascaKunci = append(pascaKunci, st.PengajarKelasID)
+		}
+	}
+	if len(terkunci) > 0 {
+		return nil, fmt.Errorf("%w: %s. Hubungi admin untuk membuka kunci", ErrLocked, strings.Join(terkunci, ", "))
+	}
+	return pascaKunci, nil
+}
+
+func (s *service) RecordPascaKunci(ctx context.Context, schemaName string, pengajarKelasIDs []string, perubahan interface{}) {
+	if len(pengajarKelasIDs) == 0 {
+		return
+	}
+	entries := make([]audit.Entry, 0, len(pengajarKelasIDs))
+	for _, id := range pengajarKelasIDs {
+		entries = append(entries, audit.Entry{Action: audit.ActionUpdate, Entity: "nilai_pasca_kunci", EntityID: id, After: perubahan})
+	}
+	s.audit.Record(ctx, schemaName, entries...)
+}
+
+func (s *service) getStatusPengajar(ctx context.Context, schemaName string, pengajarKelasID string) (*StatusMapel, error) {
+	list, err := s.repo.GetStatusByPengajar(ctx, schemaName, []string{pengajarKelasID})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("%w: pengajar kelas tidak ditemukan", ErrValidation)
+	}
+	return &list[0], nil
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"skoola/pkg/database"
 )
 
 type Repository interface {
@@ -39,20 +40,25 @@ func NewRepository(db *sql.DB) Repository {
 
 // --- FUNGSI BARU UNTUK MENAMBAHKAN ASOSIASI ---
 func (r *postgresRepository) AddKurikulumToTahunAjaran(ctx context.Context, schemaName string, input AddKurikulumToTahunAjaranInput) error {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 	query := `INSERT INTO tahun_ajaran_kurikulum (tahun_ajaran_id, kurikulum_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
-	_, err := r.db.ExecContext(ctx, query, input.TahunAjaranID, input.KurikulumID)
-	return err
+	_, err = tx.ExecContext(ctx, query, input.TahunAjaranID, input.KurikulumID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (r *postgresRepository) GetAllKurikulumByTahunAjaran(ctx context.Context, schemaName string, tahunAjaranID string) ([]Kurikulum, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `
 		SELECT k.id, k.nama_kurikulum, k.deskripsi
@@ -61,7 +67,7 @@ func (r *postgresRepository) GetAllKurikulumByTahunAjaran(ctx context.Context, s
 		WHERE tak.tahun_ajaran_id = $1
 		ORDER BY k.nama_kurikulum ASC
 	`
-	rows, err := r.db.QueryContext(ctx, query, tahunAjaranID)
+	rows, err := tx.QueryContext(ctx, query, tahunAjaranID)
 	if err != nil {
 		return nil, fmt.Errorf("gagal query get all kurikulum by tahun ajaran: %w", err)
 	}
@@ -76,18 +82,22 @@ func (r *postgresRepository) GetAllKurikulumByTahunAjaran(ctx context.Context, s
 		}
 		kurikulumList = append(kurikulumList, k)
 	}
-	return kurikulumList, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return kurikulumList, tx.Commit()
 }
 
 // GetAllKurikulum mengambil semua data master kurikulum.
 func (r *postgresRepository) GetAllKurikulum(ctx context.Context, schemaName string) ([]Kurikulum, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `SELECT id, nama_kurikulum, deskripsi FROM kurikulum ORDER BY nama_kurikulum ASC`
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := tx.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("gagal query get all kurikulum: %w", err)
 	}
@@ -101,35 +111,40 @@ func (r *postgresRepository) GetAllKurikulum(ctx context.Context, schemaName str
 		}
 		kurikulumList = append(kurikulumList, k)
 	}
-	return kurikulumList, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return kurikulumList, tx.Commit()
 }
 
 // CreateKurikulum membuat data master kurikulum baru.
 func (r *postgresRepository) CreateKurikulum(ctx context.Context, schemaName string, input UpsertKurikulumInput) (*Kurikulum, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `INSERT INTO kurikulum (nama_kurikulum, deskripsi) VALUES ($1, $2) RETURNING id, nama_kurikulum, deskripsi`
-	row := r.db.QueryRowContext(ctx, query, input.NamaKurikulum, input.Deskripsi)
+	row := tx.QueryRowContext(ctx, query, input.NamaKurikulum, input.Deskripsi)
 
 	var k Kurikulum
 	if err := row.Scan(&k.ID, &k.NamaKurikulum, &k.Deskripsi); err != nil {
 		return nil, fmt.Errorf("gagal memindai data kurikulum setelah dibuat: %w", err)
 	}
-	return &k, nil
+	return &k, tx.Commit()
 }
 
 // UpdateKurikulum memperbarui data master kurikulum.
 func (r *postgresRepository) UpdateKurikulum(ctx context.Context, schemaName string, id int, input UpsertKurikulumInput) error {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 
 	query := `UPDATE kurikulum SET nama_kurikulum = $1, deskripsi = $2 WHERE id = $3`
-	result, err := r.db.ExecContext(ctx, query, input.NamaKurikulum, input.Deskripsi, id)
+	result, err := tx.ExecContext(ctx, query, input.NamaKurikulum, input.Deskripsi, id)
 	if err != nil {
 		return fmt.Errorf("gagal mengeksekusi query update kurikulum: %w", err)
 	}
@@ -141,18 +156,19 @@ func (r *postgresRepository) UpdateKurikulum(ctx context.Context, schemaName str
 	if rowsAffected == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+	return tx.Commit()
 }
 
 // DeleteKurikulum menghapus data master kurikulum.
 func (r *postgresRepository) DeleteKurikulum(ctx context.Context, schemaName string, id int) error {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 
 	query := `DELETE FROM kurikulum WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := tx.ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("gagal mengeksekusi query delete kurikulum: %w", err)
 	}
@@ -164,15 +180,17 @@ func (r *postgresRepository) DeleteKurikulum(ctx context.Context, schemaName str
 	if rowsAffected == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+	return tx.Commit()
 }
 
 // === METODE FASE ===
 func (r *postgresRepository) GetAllFase(ctx context.Context, schemaName string) ([]Fase, error) {
-	if _, err := r.db.ExecContext(ctx, fmt.Sprintf("SET search_path TO %q", schemaName)); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
-	rows, err := r.db.QueryContext(ctx, "SELECT id, nama_fase, deskripsi FROM fase ORDER BY nama_fase ASC")
+	defer tx.Rollback()
+	rows, err := tx.QueryContext(ctx, "SELECT id, nama_fase, deskripsi FROM fase ORDER BY nama_fase ASC")
 	if err != nil {
 		return nil, err
 	}
@@ -185,26 +203,30 @@ func (r *postgresRepository) GetAllFase(ctx context.Context, schemaName string)
 		}
 		fases = append(fases, f)
 	}
-	return fases, nil
+	return fases, tx.Commit()
 }
 
 func (r *postgresRepository) CreateFase(ctx context.Context, schemaName string, input UpsertFaseInput) (*Fase, error) {
-	if _, err := r.db.ExecContext(ctx, fmt.Sprintf("SET search_path TO %q", schemaName)); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
-	row := r.db.QueryRowContext(ctx, "INSERT INTO fase (nama_fase, deskripsi) VALUES ($1, $2) RETURNING id, nama_fase, deskripsi", input.NamaFase, input.Deskripsi)
+	defer tx.Rollback()
+	row := tx.QueryRowContext(ctx, "INSERT INTO fase (nama_fase, deskripsi) VALUES ($1, $2) RETURNING id, nama_fase, deskripsi", input.NamaFase, input.Deskripsi)
 	var f Fase
 	if err := row.Scan(&f.ID, &f.NamaFase, &f.Deskripsi); err != nil {
 		return nil, err
 	}
-	return &f, nil
+	return &f, tx.Commit()
 }
 
 // === METODE PEMETAAN ===
 func (r *postgresRepository) GetFaseTingkatanByKurikulum(ctx context.Context, schemaName string, tahunAjaranID string, kurikulumID int) ([]FaseTingkatan, error) {
-	if _, err := r.db.ExecContext(ctx, fmt.Sprintf("SET search_path TO %q", schemaName)); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 	query := `
 		SELECT f.id, f.nama_fase, f.deskripsi, t.id, t.nama_tingkatan
 		FROM pemetaan_kurikulum pk
@@ -213,7 +235,7 @@ func (r *postgresRepository) GetFaseTingkatanByKurikulum(ctx context.Context, sc
 		WHERE pk.tahun_ajaran_id = $1 AND pk.kurikulum_id = $2
 		ORDER BY t.urutan, t.nama_tingkatan ASC
 	`
-	rows, err := r.db.QueryContext(ctx, query, tahunAjaranID, kurikulumID)
+	rows, err := tx.QueryContext(ctx, query, tahunAjaranID, kurikulumID)
 	if err != nil {
 		return nil, err
 	}
@@ -226,21 +248,16 @@ func (r *postgresRepository) GetFaseTingkatanByKurikulum(ctx context.Context, sc
 		}
 		results = append(results, ft)
 	}
-	return results, nil
+	return results, tx.Commit()
 }
 
 func (r *postgresRepository) CreatePemetaan(ctx context.Context, schemaName string, input PemetaanInput) error {
-	tx, err := r.db.Begin()
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := tx.ExecContext(ctx, setSchemaQuery); err != nil {
-		return err
-	}
-
 	// Pertama, pastikan asosiasi antara tahun ajaran dan kurikulum ada
 	assocQuery := `
         INSERT INTO tahun_ajaran_kurikulum (tahun_ajaran_id, kurikulum_id)
@@ -266,19 +283,26 @@ func (r *postgresRepository) CreatePemetaan(ctx context.Context, schemaName stri
 }
 
 func (r *postgresRepository) DeletePemetaan(ctx context.Context, schemaName string, tahunAjaranID string, kurikulumID int, tingkatanID int) error {
-	if _, err := r.db.ExecContext(ctx, fmt.Sprintf("SET search_path TO %q", schemaName)); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return err
 	}
-	_, err := r.db.ExecContext(ctx, "DELETE FROM pemetaan_kurikulum WHERE tahun_ajaran_id = $1 AND kurikulum_id = $2 AND tingkatan_id = $3", tahunAjaranID, kurikulumID, tingkatanID)
-	return err
+	defer tx.Rollback()
+	_, err = tx.ExecContext(ctx, "DELETE FROM pemetaan_kurikulum WHERE tahun_ajaran_id = $1 AND kurikulum_id = $2 AND tingkatan_id = $3", tahunAjaranID, kurikulumID, tingkatanID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 // === METODE TINGKATAN ===
 func (r *postgresRepository) GetAllTingkatan(ctx context.Context, schemaName string) ([]Tingkatan, error) {
-	if _, err := r.db.ExecContext(ctx, fmt.Sprintf("SET search_path TO %q", schemaName)); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
-	rows, err := r.db.QueryContext(ctx, "SELECT id, nama_tingkatan FROM tingkatan ORDER BY urutan, nama_tingkatan ASC")
+	defer tx.Rollback()
+	rows, err := tx.QueryContext(ctx, "SELECT id, nama_tingkatan FROM tingkatan ORDER BY urutan, nama_tingkatan ASC")
 	if err != nil {
 		return nil, err
 	}
@@ -291,5 +315,5 @@ func (r *postgresRepository) GetAllTingkatan(ctx context.Context, schemaName str
 		}
 		tingkatans = append(tingkatans, t)
 	}
-	return tingkatans, nil
+	return tingkatans, tx.Commit()
 }
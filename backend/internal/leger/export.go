@@ -0,0 +1,229 @@
+// file: backend/internal/leger/export.go
+package leger
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	sheetLeger = "Leger"
+	// kolomMapel adalah kolom pertama nilai mapel, setelah No, NIS, NISN, dan Nama Lengkap.
+	kolomMapel  = 5
+	barisKepala = 5
+	barisData   = 7
+)
+
+func renderExcel(l *Leger) ([]byte, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	index, err := f.NewSheet(sheetLeger)
+	if err != nil {
+		return nil, err
+	}
+
+	kolomRekap := kolomMapel + len(l.Mapel)
+	kolomAkhir := kolomRekap + 3
+	akhir, _ := excelize.CoordinatesToCellName(kolomAkhir, 1)
+	f.MergeCell(sheetLeger, "A1", akhir)
+	f.SetCellValue(sheetLeger, "A1", "LEGER NILAI KELAS "+l.Kelas.NamaKelas)
+	f.SetCellValue(sheetLeger, "A2", fmt.Sprintf("Tahun Ajaran %s Semester %s", l.Kelas.NamaTahunAjaran, l.Kelas.Semester))
+	wali := "-"
+	if l.Kelas.NamaWaliKelas != nil {
+		wali = *l.Kelas.NamaWaliKelas
+	}
+	f.SetCellValue(sheetLeger, "A3", "Wali Kelas: "+wali)
+
+	// Kolom identitas dan rekap memakai dua baris kepala.
+	tetap := map[int]string{1: "No", 2: "NIS", 3: "NISN", 4: "Nama Lengkap",
+		kolomRekap: "Jumlah", kolomRekap + 1: "Rata-rata", kolomRekap + 2: "Peringkat Kelas", kolomRekap + 3: "Peringkat Tingkatan"}
+	for col, judul := range tetap {
+		atas, _ := excelize.CoordinatesToCellName(col, barisKepala)
+		bawah, _ := excelize.CoordinatesToCellName(col, barisKepala+1)
+		f.MergeCell(sheetLeger, atas, bawah)
+		f.SetCellValue(sheetLeger, atas, judul)
+	}
+
+	// Baris kepala pertama berisi kelompok mapel yang digabung untuk mapel berurutan.
+	for i := 0; i < len(l.Mapel); {
+		j := i
+		for j+1 < len(l.Mapel) && namaKelompok(l.Mapel[j+1]) == namaKelompok(l.Mapel[i]) {
+			j++
+		}
+		awal, _ := excelize.CoordinatesToCellName(kolomMapel+i, barisKepala)
+		ujung, _ := excelize.CoordinatesToCellName(kolomMapel+j, barisKepala)
+		if i != j {
+			f.MergeCell(sheetLeger, awal, ujung)
+		}
+		f.SetCellValue(sheetLeger, awal, namaKelompok(l.Mapel[i]))
+		i = j + 1
+	}
+	for i, m := range l.Mapel {
+		cell, _ := excelize.CoordinatesToCellName(kolomMapel+i, barisKepala+1)
+		f.SetCellValue(sheetLeger, cell, m.NamaMapel)
+	}
+
+	for i, s := range l.Siswa {
+		row := barisData + i
+		f.SetCellValue(sheetLeger, fmt.Sprintf("A%d", row), i+1)
+		f.SetCellValue(sheetLeger, fmt.Sprintf("B%d", row), derefString(s.NIS))
+		f.SetCellValue(sheetLeger, fmt.Sprintf("C%d", row), derefString(s.NISN))
+		f.SetCellValue(sheetLeger, fmt.Sprintf("D%d", row), s.NamaLengkap)
+		for j, m := range l.Mapel {
+			if n := s.Nilai[m.PengajarKelasID]; n != nil {
+				cell, _ := excelize.CoordinatesToCellName(kolomMapel+j, row)
+				f.SetCellValue(sheetLeger, cell, *n)
+			}
+		}
+		setAngka(f, kolomRekap, row, s.Jumlah)
+		setAngka(f, kolomRekap+1, row, s.RataRata)
+		setPeringkat(f, kolomRekap+2, row, s.PeringkatKelas)
+		setPeringkat(f, kolomRekap+3, row, s.PeringkatTingkatan)
+	}
+
+	// Baris terakhir berisi rata-rata kelas per mapel.
+	rowRata := barisData + len(l.Siswa)
+	f.MergeCell(sheetLeger, fmt.Sprintf("A%d", rowRata), fmt.Sprintf("D%d", rowRata))
+	f.SetCellValue(sheetLeger, fmt.Sprintf("A%d", rowRata), "Rata-rata Kelas")
+	for j, m := range l.Mapel {
+		setAngka(f, kolomMapel+j, rowRata, rataRataMapel(l.Siswa, m.PengajarKelasID))
+	}
+
+	headerStyle, _ := f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true},
+		Fill:      excelize.Fill{Type: "pattern", Color: []string{"CCCCCC"}, Pattern: 1},
+		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center", WrapText: true},
+	})
+	kepalaAkhir, _ := excelize.CoordinatesToCellName(kolomAkhir, barisKepala+1)
+	f.SetCellStyle(sheetLeger, fmt.Sprintf("A%d", barisKepala), kepalaAkhir, headerStyle)
+	titleStyle, _ := f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true, Size: 14},
+		Alignment: &excelize.Alignment{Horizontal: "center"},
+	})
+	f.SetCellStyle(sheetLeger, "A1", "A1", titleStyle)
+	rataStyle, _ := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	rataAkhir, _ := excelize.CoordinatesToCellName(kolomAkhir, rowRata)
+	f.SetCellStyle(sheetLeger, fmt.Sprintf("A%d", rowRata), rataAkhir, rataStyle)
+
+	f.SetColWidth(sheetLeger, "A", "A", 5)
+	f.SetColWidth(sheetLeger, "B", "C", 14)
+	f.SetColWidth(sheetLeger, "D", "D", 30)
+	if len(l.Mapel) > 0 {
+		awal, _ := excelize.ColumnNumberToName(kolomMapel)
+		ujung, _ := excelize.ColumnNumberToName(kolomRekap - 1)
+		f.SetColWidth(sheetLeger, awal, ujung, 12)
+	}
+	awal, _ := excelize.ColumnNumberToName(kolomRekap)
+	ujung, _ := excelize.ColumnNumberToName(kolomAkhir)
+	f.SetColWidth(sheetLeger, awal, ujung, 12)
+	f.SetRowHeight(sheetLeger, barisKepala+1, 45)
+	f.SetPanes(sheetLeger, &excelize.Panes{
+		Freeze:      true,
+		XSplit:      kolomMapel - 1,
+		YSplit:      barisData - 1,
+		TopLeftCell: fmt.Sprintf("E%d", barisData),
+		ActivePane:  "bottomRight",
+	})
+
+	f.SetActiveSheet(index)
+	f.DeleteSheet("Sheet1")
+
+	buffer, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func renderCSV(l *Leger) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	headers := []string{"No", "NIS", "NISN", "Nama Lengkap"}
+	for _, m := range l.Mapel {
+		headers = append(headers, m.NamaMapel)
+	}
+	headers = append(headers, "Jumlah", "Rata-rata", "Peringkat Kelas", "Peringkat Tingkatan")
+	writer.Write(headers)
+
+	for i, s := range l.Siswa {
+		record := []string{strconv.Itoa(i + 1), derefString(s.NIS), derefString(s.NISN), s.NamaLengkap}
+		for _, m := range l.Mapel {
+			record = append(record, formatAngka(s.Nilai[m.PengajarKelasID]))
+		}
+		record = append(record, formatAngka(s.Jumlah), formatAngka(s.RataRata),
+			formatPeringkat(s.PeringkatKelas), formatPeringkat(s.PeringkatTingkatan))
+		writer.Write(record)
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("gagal menulis CSV leger: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// rataRataMapel menghitung rata-rata kelas untuk satu mapel dari siswa yang sudah bernilai.
+func rataRataMapel(siswa []SiswaLeger, pengajarKelasID string) *float64 {
+	var total float64
+	var n int
+	for _, s := range siswa {
+		if v := s.Nilai[pengajarKelasID]; v != nil {
+			total += *v
+			n++
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	return round2(total / float64(n))
+}
+
+func namaKelompok(m MapelLeger) string {
+	if m.NamaKelompok == nil {
+		return "Lainnya"
+	}
+	return *m.NamaKelompok
+}
+
+func setAngka(f *excelize.File, col, row int, v *float64) {
+	if v == nil {
+		return
+	}
+	cell, _ := excelize.CoordinatesToCellName(col, row)
+	f.SetCellValue(sheetLeger, cell, *v)
+}
+
+func setPeringkat(f *excelize.File, col, row int, v *int) {
+	if v == nil {
+		return
+	}
+	cell, _ := excelize.CoordinatesToCellName(col, row)
+	f.SetCellValue(sheetLeger, cell, *v)
+}
+
+func formatAngka(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
+
+func formatPeringkat(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
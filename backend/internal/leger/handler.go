@@ -0,0 +1,60 @@
+// file: backend/internal/leger/handler.go
+package leger
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"skoola/internal/access"
+	"skoola/internal/middleware"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{service: s}
+}
+
+// ExportLeger adalah handler untuk GET /rombel/{kelasID}/leger?format=xlsx|csv.
+func (h *Handler) ExportLeger(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	kelasID := chi.URLParam(r, "kelasID")
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = FormatXLSX
+	}
+
+	content, filename, err := h.service.ExportLeger(r.Context(), schemaName, access.ActorFromContext(r.Context()), kelasID, format)
+	if err != nil {
+		writeError(w, "Gagal membuat leger: ", err)
+		return
+	}
+
+	if format == FormatCSV {
+		w.Header().Set("Content-Type", "text/csv")
+	} else {
+		w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	w.Write(content)
+}
+
+func writeError(w http.ResponseWriter, prefix string, err error) {
+	switch {
+	case errors.Is(err, access.ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, ErrValidation):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Kelas tidak ditemukan", http.StatusNotFound)
+	default:
+		http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+	}
+}
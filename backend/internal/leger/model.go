@@ -0,0 +1,49 @@
+// file: backend/internal/leger/model.go
+package leger
+
+// Format ekspor leger.
+const (
+	FormatXLSX = "xlsx"
+	FormatCSV  = "csv"
+)
+
+// LegerKelas adalah identitas kelas pada kepala leger.
+type LegerKelas struct {
+	KelasID         string  `json:"kelas_id"`
+	NamaKelas       string  `json:"nama_kelas"`
+	TingkatanID     int     `json:"tingkatan_id"`
+	NamaTingkatan   string  `json:"nama_tingkatan"`
+	TahunAjaranID   string  `json:"tahun_ajaran_id"`
+	NamaTahunAjaran string  `json:"nama_tahun_ajaran"`
+	Semester        string  `json:"semester"`
+	NamaWaliKelas   *string `json:"nama_wali_kelas"`
+}
+
+// MapelLeger adalah satu kolom mata pelajaran pada leger.
+type MapelLeger struct {
+	PengajarKelasID string  `json:"pengajar_kelas_id"`
+	KodeMapel       string  `json:"kode_mapel"`
+	NamaMapel       string  `json:"nama_mapel"`
+	NamaKelompok    *string `json:"nama_kelompok"`
+}
+
+// SiswaLeger adalah satu baris leger. Nilai berisi nilai akhir per pengajar_kelas_id.
+// Peringkat dihitung dari Jumlah; siswa tanpa nilai sama sekali tidak diberi peringkat.
+type SiswaLeger struct {
+	AnggotaKelasID     string              `json:"anggota_kelas_id"`
+	NamaLengkap        string              `json:"nama_lengkap"`
+	NIS                *string             `json:"nis"`
+	NISN               *string             `json:"nisn"`
+	Nilai              map[string]*float64 `json:"nilai"`
+	Jumlah             *float64            `json:"jumlah"`
+	RataRata           *float64            `json:"rata_rata"`
+	PeringkatKelas     *int                `json:"peringkat_kelas"`
+	PeringkatTingkatan *int                `json:"peringkat_tingkatan"`
+}
+
+// Leger adalah rekap nilai akhir seluruh siswa di satu kelas untuk semua mapel.
+type Leger struct {
+	Kelas LegerKelas   `json:"kelas"`
+	Mapel []MapelLeger `json:"mapel"`
+	Siswa []SiswaLeger `json:"siswa"`
+}
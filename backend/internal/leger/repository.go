@@ -0,0 +1,160 @@
+// file: backend/internal/leger/repository.go
+package leger
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"skoola/pkg/database"
+)
+
+// Repository mendefinisikan interface untuk interaksi database.
+type Repository interface {
+	GetKelas(ctx context.Context, schemaName string, kelasID string) (*LegerKelas, error)
+	GetKelasSetingkat(ctx context.Context, schemaName string, tahunAjaranID string, tingkatanID int) ([]string, error)
+	// GetSiswa mengembalikan anggota kelas yang masih aktif saat semester berakhir menurut
+	// kalender akademik (hari ini bila tanggal selesai belum diatur), sehingga siswa yang
+	// keluar setelah semester selesai tetap tercantum di leger.
+	GetSiswa(ctx context.Context, schemaName string, kelasID string) ([]SiswaLeger, error)
+	GetMapel(ctx context.Context, schemaName string, kelasID string) ([]MapelLeger, error)
+}
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewRepository membuat instance baru dari postgresRepository.
+func NewRepository(db *sql.DB) Repository {
+	return &postgresRepository{db: db}
+}
+
+func (r *postgresRepository) GetKelas(ctx context.Context, schemaName string, kelasID string) (*LegerKelas, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT k.id, k.nama_kelas, t.id, t.nama_tingkatan, ta.id, ta.nama_tahun_ajaran, ta.semester, g.nama_lengkap
+		FROM kelas k
+		JOIN tingkatan t ON k.tingkatan_id = t.id
+		JOIN tahun_ajaran ta ON k.tahun_ajaran_id = ta.id
+		LEFT JOIN teachers g ON k.wali_kelas_id = g.id
+		WHERE k.id = $1
+	`
+	var k LegerKelas
+	err = tx.QueryRowContext(ctx, query, kelasID).Scan(
+		&k.KelasID, &k.NamaKelas, &k.TingkatanID, &k.NamaTingkatan,
+		&k.TahunAjaranID, &k.NamaTahunAjaran, &k.Semester, &k.NamaWaliKelas,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("gagal mengambil data kelas leger: %w", err)
+	}
+	return &k, tx.Commit()
+}
+
+// GetKelasSetingkat mengambil semua kelas pada tingkatan dan tahun ajaran yang sama.
+func (r *postgresRepository) GetKelasSetingkat(ctx context.Context, schemaName string, tahunAjaranID string, tingkatanID int) ([]string, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT id FROM kelas WHERE tahun_ajaran_id = $1 AND tingkatan_id = $2 ORDER BY nama_kelas`
+	rows, err := tx.QueryContext(ctx, query, tahunAjaranID, tingkatanID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil kelas setingkat: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("gagal memindai kelas setingkat: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return ids, tx.Commit()
+}
+
+func (r *postgresRepository) GetSiswa(ctx context.Context, schemaName string, kelasID string) ([]SiswaLeger, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT ak.id, s.nama_lengkap, s.nis, s.nisn
+		FROM anggota_kelas ak
+		JOIN students s ON ak.student_id = s.id
+		JOIN kelas k ON ak.kelas_id = k.id
+		LEFT JOIN kalender_pengaturan kp ON kp.tahun_ajaran_id = k.tahun_ajaran_id
+		WHERE ak.kelas_id = $1 AND (ak.tanggal_keluar IS NULL OR ak.tanggal_keluar > COALESCE(kp.tanggal_selesai, CURRENT_DATE))
+		ORDER BY ak.urutan ASC, s.nama_lengkap ASC
+	`
+	rows, err := tx.QueryContext(ctx, query, kelasID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil siswa leger: %w", err)
+	}
+	defer rows.Close()
+
+	list := []SiswaLeger{}
+	for rows.Next() {
+		s := SiswaLeger{Nilai: make(map[string]*float64)}
+		if err := rows.Scan(&s.AnggotaKelasID, &s.NamaLengkap, &s.NIS, &s.NISN); err != nil {
+			return nil, fmt.Errorf("gagal memindai siswa leger: %w", err)
+		}
+		list = append(list, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+// GetMapel mengambil mapel kelas dengan urutan yang sama seperti rapor:
+// per kelompok mapel, lalu urutan mapel. Mapel tanpa kelompok diletakkan paling akhir.
+func (r *postgresRepository) GetMapel(ctx context.Context, schemaName string, kelasID string) ([]MapelLeger, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT pk.id, mp.kode_mapel, mp.nama_mapel, kmp.nama_kelompok
+		FROM pengajar_kelas pk
+		JOIN mata_pelajaran mp ON pk.mata_pelajaran_id = mp.id
+		LEFT JOIN kelompok_mata_pelajaran kmp ON mp.kelompok_id = kmp.id
+		WHERE pk.kelas_id = $1
+		ORDER BY kmp.urutan ASC NULLS LAST, kmp.nama_kelompok ASC, mp.urutan ASC, mp.nama_mapel ASC
+	`
+	rows, err := tx.QueryContext(ctx, query, kelasID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil mapel leger: %w", err)
+	}
+	defer rows.Close()
+
+	list := []MapelLeger{}
+	for rows.Next() {
+		var m MapelLeger
+		if err := rows.Scan(&m.PengajarKelasID, &m.KodeMapel, &m.NamaMapel, &m.NamaKelompok); err != nil {
+			return nil, fmt.Errorf("gagal memindai mapel leger: %w", err)
+		}
+		list = append(list, m)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
@@ -0,0 +1,197 @@
+// file: backend/internal/leger/service.go
+package leger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"skoola/internal/access"
+	"skoola/internal/nilaiakhir"
+	"sort"
+	"strings"
+)
+
+var ErrValidation = errors.New("validation failed")
+
+// Service mendefinisikan logika bisnis leger nilai.
+type Service interface {
+	// ExportLeger membuat file leger satu kelas dan mengembalikan isi serta nama filenya.
+	ExportLeger(ctx context.Context, schemaName string, actor access.Actor, kelasID string, format string) ([]byte, string, error)
+}
+
+type service struct {
+	repo       Repository
+	nilaiAkhir nilaiakhir.Service
+	access     access.Service
+}
+
+// NewService membuat instance baru dari service leger.
+func NewService(repo Repository, nilaiAkhirService nilaiakhir.Service, accessService access.Service) Service {
+	return &service{repo: repo, nilaiAkhir: nilaiAkhirService, access: accessService}
+}
+
+func (s *service) ExportLeger(ctx context.Context, schemaName string, actor access.Actor, kelasID string, format string) ([]byte, string, error) {
+	if format != FormatXLSX && format != FormatCSV {
+		return nil, "", fmt.Errorf("%w: format harus 'xlsx' atau 'csv'", ErrValidation)
+	}
+	// Leger berisi nilai semua mapel, sehingga hanya admin dan wali kelas yang boleh mengunduhnya.
+	if err := s.access.AuthorizeRead(ctx, schemaName, actor, access.Target{KelasIDs: []string{kelasID}}); err != nil {
+		return nil, "", err
+	}
+
+	leger, err := s.build(ctx, schemaName, kelasID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	filename := fmt.Sprintf("leger_%s.%s", fileSafe(leger.Kelas.NamaKelas), format)
+	if format == FormatCSV {
+		content, err := renderCSV(leger)
+		return content, filename, err
+	}
+	content, err := renderExcel(leger)
+	return content, filename, err
+}
+
+// rekapNilai adalah jumlah nilai akhir satu siswa dan banyaknya mapel yang sudah bernilai.
+type rekapNilai struct {
+	jumlah float64
+	mapel  int
+}
+
+func (s *service) build(ctx context.Context, schemaName string, kelasID string) (*Leger, error) {
+	kelas, err := s.repo.GetKelas(ctx, schemaName, kelasID)
+	if err != nil {
+		return nil, err
+	}
+	mapel, err := s.repo.GetMapel(ctx, schemaName, kelasID)
+	if err != nil {
+		return nil, err
+	}
+	siswa, err := s.repo.GetSiswa(ctx, schemaName, kelasID)
+	if err != nil {
+		return nil, err
+	}
+	akhir, err := s.nilaiAkhir.HitungKelas(ctx, schemaName, kelasID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range siswa {
+		for _, m := range mapel {
+			if n, ok := akhir[m.PengajarKelasID][siswa[i].AnggotaKelasID]; ok {
+				siswa[i].Nilai[m.PengajarKelasID] = n.NilaiAkhir
+			}
+		}
+	}
+
+	rekapKelas := rekapSiswa(akhir)
+	rekapTingkatan := make(map[string]rekapNilai)
+	for id, r := range rekapKelas {
+		rekapTingkatan[id] = r
+	}
+
+	setingkat, err := s.repo.GetKelasSetingkat(ctx, schemaName, kelas.TahunAjaranID, kelas.TingkatanID)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range setingkat {
+		if id == kelasID {
+			continue
+		}
+		lain, err := s.nilaiAkhir.HitungKelas(ctx, schemaName, id)
+		if err != nil {
+			return nil, err
+		}
+		for anggotaID, r := range rekapSiswa(lain) {
+			rekapTingkatan[anggotaID] = r
+		}
+	}
+
+	peringkatKelas := peringkat(rekapKelas)
+	peringkatTingkatan := peringkat(rekapTingkatan)
+	for i := range siswa {
+		id := siswa[i].AnggotaKelasID
+		r, ok := rekapKelas[id]
+		if !ok || r.mapel == 0 {
+			continue
+		}
+		siswa[i].Jumlah = round2(r.jumlah)
+		siswa[i].RataRata = round2(r.jumlah / float64(r.mapel))
+		if p, ok := peringkatKelas[id]; ok {
+			siswa[i].PeringkatKelas = &p
+		}
+		if p, ok := peringkatTingkatan[id]; ok {
+			siswa[i].PeringkatTingkatan = &p
+		}
+	}
+
+	return &Leger{Kelas: *kelas, Mapel: mapel, Siswa: siswa}, nil
+}
+
+// rekapSiswa menjumlahkan nilai akhir setiap siswa dari hasil nilaiakhir.HitungKelas.
+func rekapSiswa(akhir map[string]map[string]nilaiakhir.NilaiAkhirSiswa) map[string]rekapNilai {
+	rekap := make(map[string]rekapNilai)
+	for _, perSiswa := range akhir {
+		for anggotaID, n := range perSiswa {
+			r := rekap[anggotaID]
+			if n.NilaiAkhir != nil {
+				r.jumlah += *n.NilaiAkhir
+				r.mapel++
+			}
+			rekap[anggotaID] = r
+		}
+	}
+	return rekap
+}
+
+// peringkat mengurutkan siswa berdasarkan jumlah nilai. Jumlah yang sama mendapat
+// peringkat yang sama dan peringkat berikutnya dilewati (1, 2, 2, 4).
+func peringkat(rekap map[string]rekapNilai) map[string]int {
+	type item struct {
+		id     string
+		jumlah float64
+	}
+	var list []item
+	for id, r := range rekap {
+		if r.mapel > 0 {
+			list = append(list, item{id: id, jumlah: math.Round(r.jumlah*100) / 100})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].jumlah != list[j].jumlah {
+			return list[i].jumlah > list[j].jumlah
+		}
+		return list[i].id < list[j].id
+	})
+
+	hasil := make(map[string]int, len(list))
+	for i, it := range list {
+		if i > 0 && it.jumlah == list[i-1].jumlah {
+			hasil[it.id] = hasil[list[i-1].id]
+			continue
+		}
+		hasil[it.id] = i + 1
+	}
+	return hasil
+}
+
+func round2(v float64) *float64 {
+	r := math.Round(v*100) / 100
+	return &r
+}
+
+// fileSafe mengubah nama kelas menjadi nama file yang aman.
+func fileSafe(name string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"skoola/pkg/database"
 
 	"github.com/lib/pq"
 )
@@ -29,52 +30,50 @@ func NewRepository(db *sql.DB) Repository {
 	return &postgresRepository{db: db}
 }
 
-func (r *postgresRepository) setSchema(ctx context.Context, schemaName string) error {
-	_, err := r.db.ExecContext(ctx, fmt.Sprintf("SET search_path TO %q", schemaName))
-	if err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
-	}
-	return nil
-}
-
 func (r *postgresRepository) GetMaxUrutan(ctx context.Context, schemaName string) (int, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return 0, err
 	}
+	defer tx.Rollback()
 	var maxUrutan sql.NullInt64
 	query := `SELECT MAX(urutan) FROM mata_pelajaran WHERE parent_id IS NULL`
-	err := r.db.QueryRowContext(ctx, query).Scan(&maxUrutan)
+	err = tx.QueryRowContext(ctx, query).Scan(&maxUrutan)
 	if err != nil && err != sql.ErrNoRows {
 		return 0, err
 	}
 	if maxUrutan.Valid {
-		return int(maxUrutan.Int64), nil
+		return int(maxUrutan.Int64), tx.Commit()
 	}
-	return 0, nil
+	return 0, tx.Commit()
 }
 
 func (r *postgresRepository) GetMaxUrutanByParentID(ctx context.Context, schemaName string, parentID string) (int, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return 0, err
 	}
+	defer tx.Rollback()
 	var maxUrutan sql.NullInt64
 	query := `SELECT MAX(urutan) FROM mata_pelajaran WHERE parent_id = $1`
-	err := r.db.QueryRowContext(ctx, query, parentID).Scan(&maxUrutan)
+	err = tx.QueryRowContext(ctx, query, parentID).Scan(&maxUrutan)
 	if err != nil && err != sql.ErrNoRows {
 		return 0, err
 	}
 	if maxUrutan.Valid {
-		return int(maxUrutan.Int64), nil
+		return int(maxUrutan.Int64), tx.Commit()
 	}
-	return 0, nil
+	return 0, tx.Commit()
 }
 
 func (r *postgresRepository) Create(ctx context.Context, schemaName string, input UpsertMataPelajaranInput) (*MataPelajaran, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 	query := `INSERT INTO mata_pelajaran (kode_mapel, nama_mapel, parent_id, kelompok_id, urutan) VALUES ($1, $2, $3, $4, $5) RETURNING id, kode_mapel, nama_mapel, parent_id, kelompok_id, created_at, updated_at, urutan`
-	row := r.db.QueryRowContext(ctx, query, input.KodeMapel, input.NamaMapel, input.ParentID, input.KelompokID, input.Urutan)
+	row := tx.QueryRowContext(ctx, query, input.KodeMapel, input.NamaMapel, input.ParentID, input.KelompokID, input.Urutan)
 
 	var mp MataPelajaran
 	var parentID sql.NullString
@@ -89,20 +88,15 @@ func (r *postgresRepository) Create(ctx context.Context, schemaName string, inpu
 		id := int(kelompokID.Int32)
 		mp.KelompokID = &id
 	}
-	return &mp, nil
+	return &mp, tx.Commit()
 }
 
 // ... sisa file tetap sama ...
 // GetAll, GetByID, Update, dll tidak perlu diubah
 func (r *postgresRepository) UpdateUrutan(ctx context.Context, schemaName string, orderedIDs []string) error {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return err
-	}
-
-	tx, err := r.db.BeginTx(ctx, nil)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
 	if err != nil {
-		return fmt.Errorf("gagal memulai transaksi: %w", err)
+		return err
 	}
 	defer tx.Rollback()
 
@@ -123,10 +117,11 @@ func (r *postgresRepository) UpdateUrutan(ctx context.Context, schemaName string
 	return tx.Commit()
 }
 func (r *postgresRepository) GetAll(ctx context.Context, schemaName string) ([]MataPelajaran, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `
 		SELECT mp.id, mp.kode_mapel, mp.nama_mapel, mp.parent_id, mp.created_at, mp.updated_at, mp.urutan, mp.kelompok_id, kmp.nama_kelompok
@@ -134,7 +129,7 @@ func (r *postgresRepository) GetAll(ctx context.Context, schemaName string) ([]M
 		LEFT JOIN kelompok_mata_pelajaran kmp ON mp.kelompok_id = kmp.id
 		ORDER BY mp.urutan ASC, mp.nama_mapel ASC
 	`
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := tx.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("gagal query get all mata pelajaran: %w", err)
 	}
@@ -161,21 +156,25 @@ func (r *postgresRepository) GetAll(ctx context.Context, schemaName string) ([]M
 		}
 		list = append(list, mp)
 	}
-	return list, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
 }
 
 func (r *postgresRepository) GetByID(ctx context.Context, schemaName string, id string) (*MataPelajaran, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `SELECT id, kode_mapel, nama_mapel, parent_id, created_at, updated_at, urutan FROM mata_pelajaran WHERE id = $1`
-	row := r.db.QueryRowContext(ctx, query, id)
+	row := tx.QueryRowContext(ctx, query, id)
 
 	var mp MataPelajaran
 	var parentID sql.NullString
-	err := row.Scan(&mp.ID, &mp.KodeMapel, &mp.NamaMapel, &parentID, &mp.CreatedAt, &mp.UpdatedAt, &mp.Urutan)
+	err = row.Scan(&mp.ID, &mp.KodeMapel, &mp.NamaMapel, &parentID, &mp.CreatedAt, &mp.UpdatedAt, &mp.Urutan)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // Data tidak ditemukan
@@ -185,17 +184,18 @@ func (r *postgresRepository) GetByID(ctx context.Context, schemaName string, id
 	if parentID.Valid {
 		mp.ParentID = &parentID.String
 	}
-	return &mp, nil
+	return &mp, tx.Commit()
 }
 
 func (r *postgresRepository) Update(ctx context.Context, schemaName string, id string, input UpsertMataPelajaranInput) error {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 
 	query := `UPDATE mata_pelajaran SET kode_mapel = $1, nama_mapel = $2, parent_id = $3, kelompok_id = $4, updated_at = NOW() WHERE id = $5`
-	result, err := r.db.ExecContext(ctx, query, input.KodeMapel, input.NamaMapel, input.ParentID, input.KelompokID, id)
+	result, err := tx.ExecContext(ctx, query, input.KodeMapel, input.NamaMapel, input.ParentID, input.KelompokID, id)
 	if err != nil {
 		return fmt.Errorf("gagal mengeksekusi query update: %w", err)
 	}
@@ -207,13 +207,15 @@ func (r *postgresRepository) Update(ctx context.Context, schemaName string, id s
 	if rowsAffected == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+	return tx.Commit()
 }
 
 func (r *postgresRepository) Delete(ctx context.Context, schemaName string, id string) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
 	query := `
 		WITH RECURSIVE sub_mapel AS (
@@ -224,7 +226,7 @@ func (r *postgresRepository) Delete(ctx context.Context, schemaName string, id s
 		)
 		DELETE FROM mata_pelajaran WHERE id IN (SELECT id FROM sub_mapel)
 	`
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := tx.ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("gagal mengeksekusi query delete: %w", err)
 	}
@@ -236,14 +238,15 @@ func (r *postgresRepository) Delete(ctx context.Context, schemaName string, id s
 	if rowsAffected == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+	return tx.Commit()
 }
 
 func (r *postgresRepository) GetAllTaught(ctx context.Context, schemaName string) ([]MataPelajaran, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `
 		SELECT id, kode_mapel, nama_mapel, parent_id, created_at, updated_at, urutan
@@ -251,7 +254,7 @@ func (r *postgresRepository) GetAllTaught(ctx context.Context, schemaName string
 		WHERE id NOT IN (SELECT DISTINCT parent_id FROM mata_pelajaran WHERE parent_id IS NOT NULL)
 		ORDER BY urutan ASC, nama_mapel ASC
 	`
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := tx.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("gagal query get all taught mata pelajaran: %w", err)
 	}
@@ -269,5 +272,8 @@ func (r *postgresRepository) GetAllTaught(ctx context.Context, schemaName string
 		}
 		list = append(list, mp)
 	}
-	return list, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
 }
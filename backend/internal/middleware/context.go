@@ -10,4 +10,7 @@ const (
 	UserIDKey     = contextKey("userID")
 	UserRoleKey   = contextKey("userRole")
 	SchemaNameKey = contextKey("schemaName")
+	SessionIDKey  = contextKey("sessionID")
+	// PermissionsKey menyimpan map[string]bool berisi permission milik user.
+	PermissionsKey = contextKey("permissions")
 )
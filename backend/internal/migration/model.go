@@ -0,0 +1,41 @@
+// file: backend/internal/migration/model.go
+package migration
+
+import "time"
+
+// Scope menentukan skema tujuan sebuah file migrasi.
+const (
+	ScopePublic = "public"
+	ScopeTenant = "tenant"
+)
+
+// Migration merepresentasikan satu file SQL di folder db/migrations.
+type Migration struct {
+	Version int    `json:"version"`
+	Name    string `json:"name"`
+	Scope   string `json:"scope"`
+	SQL     string `json:"-"`
+}
+
+// AppliedMigration merepresentasikan satu baris dari tabel 'schema_migrations'.
+type AppliedMigration struct {
+	Version   int       `json:"version"`
+	Name      string    `json:"name"`
+	AppliedAt time.Time `json:"applied_at"`
+}
+
+// SchemaResult adalah hasil menjalankan migrasi pada satu skema.
+type SchemaResult struct {
+	SchemaName  string   `json:"schema_name"`
+	NamaSekolah string   `json:"nama_sekolah,omitempty"`
+	Applied     []string `json:"applied"`
+	Success     bool     `json:"success"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// Report adalah ringkasan menjalankan migrasi untuk semua tenant.
+type Report struct {
+	SuccessCount int            `json:"success_count"`
+	FailedCount  int            `json:"failed_count"`
+	Results      []SchemaResult `json:"results"`
+}
@@ -0,0 +1,140 @@
+// file: backend/internal/migration/repository.go
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Querier adalah abstraksi untuk *sql.DB maupun *sql.Tx.
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// TenantRef adalah data minimal sebuah tenant yang dibutuhkan oleh runner migrasi.
+type TenantRef struct {
+	SchemaName  string
+	NamaSekolah string
+}
+
+// Repository mendefinisikan interface untuk pencatatan migrasi per skema.
+type Repository interface {
+	EnsureTable(ctx context.Context, q Querier, schemaName string) (bool, error)
+	IsLegacySchema(ctx context.Context, q Querier, schemaName string) (bool, error)
+	GetApplied(ctx context.Context, q Querier, schemaName string) ([]AppliedMigration, error)
+	LockSchema(ctx context.Context, q Querier, schemaName string) error
+	Apply(ctx context.Context, q Querier, schemaName string, m Migration) error
+	MarkApplied(ctx context.Context, q Querier, schemaName string, m Migration) error
+	GetAllTenants(ctx context.Context) ([]TenantRef, error)
+}
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewRepository membuat instance baru dari postgresRepository.
+func NewRepository(db *sql.DB) Repository {
+	return &postgresRepository{db: db}
+}
+
+// EnsureTable membuat tabel schema_migrations di skema tujuan jika belum ada.
+// Nilai kembalian bernilai true jika tabel baru saja dibuat.
+func (r *postgresRepository) EnsureTable(ctx context.Context, q Querier, schemaName string) (bool, error) {
+	var exists bool
+	err := q.QueryRowContext(ctx, `SELECT to_regclass($1) IS NOT NULL`, fmt.Sprintf("%q.schema_migrations", schemaName)).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("gagal memeriksa tabel schema_migrations: %w", err)
+	}
+	if exists {
+		return false, nil
+	}
+
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %q.schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)
+	`, schemaName)
+	if _, err := q.ExecContext(ctx, query); err != nil {
+		return false, fmt.Errorf("gagal membuat tabel schema_migrations: %w", err)
+	}
+	return true, nil
+}
+
+// IsLegacySchema memeriksa apakah skema tenant sudah berisi tabel dari masa sebelum
+// migrasi dicatat (ditandai dengan adanya tabel 'users').
+func (r *postgresRepository) IsLegacySchema(ctx context.Context, q Querier, schemaName string) (bool, error) {
+	var exists bool
+	err := q.QueryRowContext(ctx, `SELECT to_regclass($1) IS NOT NULL`, fmt.Sprintf("%q.users", schemaName)).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("gagal memeriksa skema lama: %w", err)
+	}
+	return exists, nil
+}
+
+func (r *postgresRepository) GetApplied(ctx context.Context, q Querier, schemaName string) ([]AppliedMigration, error) {
+	query := fmt.Sprintf(`SELECT version, name, applied_at FROM %q.schema_migrations ORDER BY version ASC`, schemaName)
+	rows, err := q.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	var list []AppliedMigration
+	for rows.Next() {
+		var m AppliedMigration
+		if err := rows.Scan(&m.Version, &m.Name, &m.AppliedAt); err != nil {
+			return nil, fmt.Errorf("gagal memindai data schema_migrations: %w", err)
+		}
+		list = append(list, m)
+	}
+	return list, rows.Err()
+}
+
+// LockSchema mengambil advisory lock transaksional agar dua proses tidak
+// menjalankan migrasi pada skema yang sama secara bersamaan.
+func (r *postgresRepository) LockSchema(ctx context.Context, q Querier, schemaName string) error {
+	if _, err := q.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('schema_migrations:' || $1))`, schemaName); err != nil {
+		return fmt.Errorf("gagal mengambil lock migrasi untuk skema %s: %w", schemaName, err)
+	}
+	return nil
+}
+
+// Apply menjalankan isi file migrasi lalu mencatat versinya. Pemanggil wajib
+// menjalankan fungsi ini di dalam transaksi yang search_path-nya sudah diatur.
+func (r *postgresRepository) Apply(ctx context.Context, q Querier, schemaName string, m Migration) error {
+	if _, err := q.ExecContext(ctx, m.SQL); err != nil {
+		return fmt.Errorf("gagal menjalankan migrasi %s: %w", m.Name, err)
+	}
+	return r.MarkApplied(ctx, q, schemaName, m)
+}
+
+func (r *postgresRepository) MarkApplied(ctx context.Context, q Querier, schemaName string, m Migration) error {
+	query := fmt.Sprintf(`INSERT INTO %q.schema_migrations (version, name) VALUES ($1, $2) ON CONFLICT (version) DO NOTHING`, schemaName)
+	if _, err := q.ExecContext(ctx, query, m.Version, m.Name); err != nil {
+		return fmt.Errorf("gagal mencatat migrasi %s: %w", m.Name, err)
+	}
+	return nil
+}
+
+func (r *postgresRepository) GetAllTenants(ctx context.Context) ([]TenantRef, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT schema_name, nama_sekolah FROM public.tenants ORDER BY created_at ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query daftar tenant: %w", err)
+	}
+	defer rows.Close()
+
+	var list []TenantRef
+	for rows.Next() {
+		var t TenantRef
+		if err := rows.Scan(&t.SchemaName, &t.NamaSekolah); err != nil {
+			return nil, fmt.Errorf("gagal memindai data tenant: %w", err)
+		}
+		list = append(list, t)
+	}
+	return list, rows.Err()
+}
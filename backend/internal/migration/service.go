@@ -0,0 +1,230 @@
+// file: backend/internal/migration/service.go
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// legacyBaselineVersion adalah versi terakhir yang dijalankan secara manual
+// (daftar hard-coded di tenant.CreateTenantSchema) sebelum migrasi dicatat.
+// Skema tenant lama yang belum punya schema_migrations dianggap sudah menjalankan
+// semua migrasi tenant sampai versi ini.
+const legacyBaselineVersion = 35
+
+// Service mendefinisikan interface untuk menjalankan migrasi database.
+type Service interface {
+	RunPublic(ctx context.Context) ([]string, error)
+	MigrateTenant(ctx context.Context, schemaName string) ([]string, error)
+	MigrateTenantTx(ctx context.Context, tx *sql.Tx, schemaName string) ([]string, error)
+	RunAllTenants(ctx context.Context) (*Report, error)
+}
+
+type service struct {
+	repo Repository
+	db   *sql.DB
+	dir  string
+}
+
+// NewService membuat instance baru dari service migrasi yang membaca file dari dir.
+func NewService(repo Repository, db *sql.DB, dir string) Service {
+	return &service{repo: repo, db: db, dir: dir}
+}
+
+// RunPublic menjalankan semua migrasi ber-scope public yang belum tercatat.
+func (s *service) RunPublic(ctx context.Context) ([]string, error) {
+	migrations, err := LoadMigrations(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	return s.migrate(ctx, "public", filterByScope(migrations, ScopePublic), false)
+}
+
+// MigrateTenant menjalankan migrasi tenant yang belum tercatat pada satu skema.
+// Setiap file dijalankan di transaksinya sendiri.
+func (s *service) MigrateTenant(ctx context.Context, schemaName string) ([]string, error) {
+	migrations, err := LoadMigrations(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	return s.migrate(ctx, schemaName, filterByScope(migrations, ScopeTenant), true)
+}
+
+// MigrateTenantTx menjalankan semua migrasi tenant di dalam transaksi milik pemanggil.
+// Dipakai saat pendaftaran sekolah baru agar pembuatan skema bersifat atomik.
+func (s *service) MigrateTenantTx(ctx context.Context, tx *sql.Tx, schemaName string) ([]string, error) {
+	migrations, err := LoadMigrations(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.setSearchPath(ctx, tx, schemaName); err != nil {
+		return nil, err
+	}
+	if err := s.repo.LockSchema(ctx, tx, schemaName); err != nil {
+		return nil, err
+	}
+	if err := s.prepareTable(ctx, tx, schemaName, true); err != nil {
+		return nil, err
+	}
+	appliedSet, err := s.appliedSet(ctx, tx, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []string
+	for _, m := range filterByScope(migrations, ScopeTenant) {
+		if appliedSet[m.Version] {
+			continue
+		}
+		if err := s.repo.Apply(ctx, tx, schemaName, m); err != nil {
+			return applied, err
+		}
+		applied = append(applied, m.Name)
+	}
+	return applied, nil
+}
+
+// RunAllTenants menjalankan migrasi tenant untuk setiap sekolah. Kegagalan pada
+// satu sekolah tidak menghentikan proses untuk sekolah lainnya.
+func (s *service) RunAllTenants(ctx context.Context) (*Report, error) {
+	tenants, err := s.repo.GetAllTenants(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mendapatkan daftar tenant: %w", err)
+	}
+
+	report := &Report{Results: []SchemaResult{}}
+	for _, t := range tenants {
+		applied, err := s.MigrateTenant(ctx, t.SchemaName)
+		result := SchemaResult{
+			SchemaName:  t.SchemaName,
+			NamaSekolah: t.NamaSekolah,
+			Applied:     applied,
+			Success:     err == nil,
+		}
+		if result.Applied == nil {
+			result.Applied = []string{}
+		}
+		if err != nil {
+			log.Printf("Migrasi gagal untuk sekolah %s (%s): %v", t.NamaSekolah, t.SchemaName, err)
+			result.Error = err.Error()
+			report.FailedCount++
+		} else {
+			report.SuccessCount++
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+func (s *service) migrate(ctx context.Context, schemaName string, migrations []Migration, tenantScope bool) ([]string, error) {
+	if err := s.withTx(ctx, schemaName, func(tx *sql.Tx) error {
+		return s.prepareTable(ctx, tx, schemaName, tenantScope)
+	}); err != nil {
+		return nil, err
+	}
+
+	var applied []string
+	for _, m := range migrations {
+		ran := false
+		err := s.withTx(ctx, schemaName, func(tx *sql.Tx) error {
+			// Cek ulang di dalam lock karena proses lain mungkin sudah menjalankannya.
+			appliedSet, err := s.appliedSet(ctx, tx, schemaName)
+			if err != nil {
+				return err
+			}
+			if appliedSet[m.Version] {
+				return nil
+			}
+			ran = true
+			return s.repo.Apply(ctx, tx, schemaName, m)
+		})
+		if err != nil {
+			return applied, fmt.Errorf("skema %s: %w", schemaName, err)
+		}
+		if ran {
+			log.Printf("Migrasi %s berhasil dijalankan pada skema %s", m.Name, schemaName)
+			applied = append(applied, m.Name)
+		}
+	}
+	return applied, nil
+}
+
+// prepareTable memastikan tabel schema_migrations ada. Untuk skema tenant lama,
+// semua migrasi sampai legacyBaselineVersion langsung dicatat sebagai sudah berjalan.
+func (s *service) prepareTable(ctx context.Context, tx *sql.Tx, schemaName string, tenantScope bool) error {
+	created, err := s.repo.EnsureTable(ctx, tx, schemaName)
+	if err != nil {
+		return err
+	}
+	if !created || !tenantScope {
+		return nil
+	}
+
+	legacy, err := s.repo.IsLegacySchema(ctx, tx, schemaName)
+	if err != nil {
+		return err
+	}
+	if !legacy {
+		return nil
+	}
+
+	migrations, err := LoadMigrations(s.dir)
+	if err != nil {
+		return err
+	}
+	for _, m := range filterByScope(migrations, ScopeTenant) {
+		if m.Version > legacyBaselineVersion {
+			break
+		}
+		if err := s.repo.MarkApplied(ctx, tx, schemaName, m); err != nil {
+			return err
+		}
+	}
+	log.Printf("Skema %s ditandai sudah menjalankan migrasi sampai versi %d", schemaName, legacyBaselineVersion)
+	return nil
+}
+
+func (s *service) appliedSet(ctx context.Context, q Querier, schemaName string) (map[int]bool, error) {
+	list, err := s.repo.GetApplied(ctx, q, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[int]bool, len(list))
+	for _, m := range list {
+		set[m.Version] = true
+	}
+	return set, nil
+}
+
+func (s *service) withTx(ctx context.Context, schemaName string, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("gagal memulai transaksi untuk migrasi: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.setSearchPath(ctx, tx, schemaName); err != nil {
+		return err
+	}
+	if err := s.repo.LockSchema(ctx, tx, schemaName); err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *service) setSearchPath(ctx context.Context, tx *sql.Tx, schemaName string) error {
+	query := fmt.Sprintf("SET LOCAL search_path TO %q, public", schemaName)
+	if schemaName == "public" {
+		query = "SET LOCAL search_path TO public"
+	}
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("gagal mengatur search_path untuk migrasi: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+// file: backend/internal/migration/source.go
+package migration
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultDir adalah lokasi folder migrasi relatif terhadap direktori kerja server.
+const DefaultDir = "./db/migrations"
+
+// scopeMarker adalah komentar di awal file yang menandai migrasi untuk skema public.
+// File tanpa penanda ini dianggap sebagai migrasi tenant.
+const scopeMarker = "-- scope: public"
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([a-zA-Z0-9_]+)\.sql$`)
+
+// LoadMigrations membaca semua file migrasi dari dir dan mengurutkannya berdasarkan versi.
+func LoadMigrations(dir string) ([]Migration, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mendapatkan path absolut untuk %s: %w", dir, err)
+	}
+	entries, err := os.ReadDir(absDir)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca folder migrasi %s: %w", absDir, err)
+	}
+
+	seen := make(map[int]string)
+	var migrations []Migration
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, fmt.Errorf("versi migrasi tidak valid pada file %s: %w", entry.Name(), err)
+		}
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("versi migrasi %d ganda: %s dan %s", version, other, entry.Name())
+		}
+		seen[version] = entry.Name()
+
+		content, err := os.ReadFile(filepath.Join(absDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("gagal membaca file migrasi %s: %w", entry.Name(), err)
+		}
+
+		migrations = append(migrations, Migration{
+			Version: version,
+			Name:    strings.TrimSuffix(entry.Name(), ".sql"),
+			Scope:   detectScope(string(content)),
+			SQL:     string(content),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// detectScope mencari penanda scope pada baris komentar di awal file.
+func detectScope(content string) string {
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "--") {
+			break
+		}
+		if strings.EqualFold(line, scopeMarker) {
+			return ScopePublic
+		}
+	}
+	return ScopeTenant
+}
+
+// filterByScope mengembalikan migrasi dengan scope tertentu saja.
+func filterByScope(migrations []Migration, scope string) []Migration {
+	var result []Migration
+	for _, m := range migrations {
+		if m.Scope == scope {
+			result = append(result, m)
+		}
+	}
+	return result
+}
@@ -0,0 +1,122 @@
+// file: backend/internal/mutasi/handler.go
+package mutasi
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"skoola/internal/access"
+	"skoola/internal/middleware"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{service: s}
+}
+
+// GetLaporan adalah handler untuk GET /mutasi?dari=YYYY-MM-DD&sampai=YYYY-MM-DD&jenis=MASUK|KELUAR.
+func (h *Handler) GetLaporan(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	q := r.URL.Query()
+
+	laporan, err := h.service.GetLaporan(r.Context(), schemaName, q.Get("dari"), q.Get("sampai"), q.Get("jenis"))
+	if err != nil {
+		writeError(w, "Gagal mengambil laporan mutasi: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(laporan)
+}
+
+// GetByID adalah handler untuk GET /mutasi/{id}.
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	m, err := h.service.GetByID(r.Context(), schemaName, chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, "Gagal mengambil data mutasi: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(m)
+}
+
+// Masuk adalah handler untuk POST /mutasi/masuk.
+func (h *Handler) Masuk(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	var input MutasiMasukInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	m, err := h.service.Masuk(r.Context(), schemaName, access.ActorFromContext(r.Context()), input)
+	if err != nil {
+		writeError(w, "Gagal mencatat mutasi masuk: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(m)
+}
+
+// Keluar adalah handler untuk POST /mutasi/keluar.
+func (h *Handler) Keluar(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	var input MutasiKeluarInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	m, err := h.service.Keluar(r.Context(), schemaName, access.ActorFromContext(r.Context()), input)
+	if err != nil {
+		writeError(w, "Gagal mencatat mutasi keluar: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(m)
+}
+
+// GetSurat adalah handler untuk GET /mutasi/{id}/surat.
+func (h *Handler) GetSurat(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	id := chi.URLParam(r, "id")
+
+	content, err := h.service.GenerateSurat(r.Context(), schemaName, id)
+	if err != nil {
+		writeError(w, "Gagal membuat surat mutasi: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=surat_mutasi_%s.pdf", id))
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	w.Write(content)
+}
+
+func writeError(w http.ResponseWriter, prefix string, err error) {
+	switch {
+	case errors.Is(err, ErrValidation):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Data tidak ditemukan", http.StatusNotFound)
+	default:
+		http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+	}
+}
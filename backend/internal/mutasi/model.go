@@ -0,0 +1,101 @@
+// file: backend/internal/mutasi/model.go
+package mutasi
+
+import "time"
+
+// Jenis mutasi siswa.
+const (
+	JenisMasuk  = "MASUK"
+	JenisKeluar = "KELUAR"
+)
+
+// Mutasi merepresentasikan satu baris dari tabel 'mutasi_siswa'.
+type Mutasi struct {
+	ID                string    `json:"id"`
+	StudentID         string    `json:"student_id"`
+	NamaSiswa         string    `json:"nama_siswa"`
+	NIS               *string   `json:"nis"`
+	NISN              *string   `json:"nisn"`
+	Jenis             string    `json:"jenis"`
+	Status            *string   `json:"status"`
+	Tanggal           time.Time `json:"tanggal"`
+	NomorSurat        *string   `json:"nomor_surat"`
+	NamaSekolah       *string   `json:"nama_sekolah"`
+	NPSNSekolah       *string   `json:"npsn_sekolah"`
+	AlamatSekolah     *string   `json:"alamat_sekolah"`
+	Alasan            *string   `json:"alasan"`
+	KelasID           *string   `json:"kelas_id"`
+	NamaKelas         *string   `json:"nama_kelas"`
+	NamaTahunAjaran   *string   `json:"nama_tahun_ajaran"`
+	RiwayatAkademikID *string   `json:"riwayat_akademik_id"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// SiswaBaruInput adalah data minimal siswa pindahan yang belum terdaftar.
+type SiswaBaruInput struct {
+	NamaLengkap  string `json:"nama_lengkap" validate:"required,min=3,max=255"`
+	NIS          string `json:"nis" validate:"omitempty,max=30"`
+	JenisKelamin string `json:"jenis_kelamin" validate:"omitempty,oneof=Laki-laki Perempuan"`
+	TempatLahir  string `json:"tempat_lahir" validate:"omitempty,max=100"`
+	TanggalLahir string `json:"tanggal_lahir" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// MutasiMasukInput adalah DTO untuk siswa pindahan dari sekolah lain. Isi StudentID untuk
+// siswa yang sudah terdaftar, atau Siswa untuk mendaftarkan siswa baru sekaligus.
+type MutasiMasukInput struct {
+	StudentID     *string         `json:"student_id" validate:"omitempty,uuid"`
+	Siswa         *SiswaBaruInput `json:"siswa"`
+	NISN          string          `json:"nisn" validate:"omitempty,max=20"`
+	KelasID       string          `json:"kelas_id" validate:"required,uuid"`
+	Tanggal       string          `json:"tanggal" validate:"required,datetime=2006-01-02"`
+	NomorSurat    string          `json:"nomor_surat" validate:"omitempty,max=100"`
+	NamaSekolah   string          `json:"nama_sekolah" validate:"required,max=255"`
+	NPSNSekolah   string          `json:"npsn_sekolah" validate:"omitempty,max=20"`
+	AlamatSekolah string          `json:"alamat_sekolah"`
+	Alasan        string          `json:"alasan"`
+}
+
+// MutasiKeluarInput adalah DTO untuk siswa yang pindah sekolah atau keluar. Nomor surat
+// dibuat otomatis jika kosong.
+type MutasiKeluarInput struct {
+	StudentID     string `json:"student_id" validate:"required,uuid"`
+	Status        string `json:"status" validate:"required,oneof=Pindah Keluar"`
+	Tanggal       string `json:"tanggal" validate:"required,datetime=2006-01-02"`
+	NomorSurat    string `json:"nomor_surat" validate:"omitempty,max=100"`
+	NamaSekolah   string `json:"nama_sekolah" validate:"required_if=Status Pindah,max=255"`
+	NPSNSekolah   string `json:"npsn_sekolah" validate:"omitempty,max=20"`
+	AlamatSekolah string `json:"alamat_sekolah"`
+	Alasan        string `json:"alasan"`
+}
+
+// Laporan adalah rekap mutasi dalam satu periode.
+type Laporan struct {
+	Dari         string   `json:"dari"`
+	Sampai       string   `json:"sampai"`
+	JumlahMasuk  int      `json:"jumlah_masuk"`
+	JumlahKeluar int      `json:"jumlah_keluar"`
+	Data         []Mutasi `json:"data"`
+}
+
+// keanggotaanAktif adalah keanggotaan kelas siswa di tahun ajaran aktif.
+type keanggotaanAktif struct {
+	AnggotaKelasID string
+	KelasID        string
+	NamaKelas      string
+}
+
+// kelasTujuan adalah rombel tempat siswa pindahan ditempatkan.
+type kelasTujuan struct {
+	KelasID       string
+	NamaKelas     string
+	TahunAjaranID string
+}
+
+// suratKeluar adalah data yang dicetak pada surat keterangan pindah.
+type suratKeluar struct {
+	Mutasi       Mutasi
+	TempatLahir  *string
+	TanggalLahir *time.Time
+	JenisKelamin *string
+	NamaWali     *string
+}
@@ -0,0 +1,166 @@
+// file: backend/internal/mutasi/pdf.go
+package mutasi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"skoola/internal/profile"
+	"strings"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+var namaBulan = []string{"", "Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember"}
+
+// tanggalIndonesia memformat tanggal seperti "17 Agustus 2025".
+func tanggalIndonesia(t time.Time) string {
+	return fmt.Sprintf("%d %s %d", t.Day(), namaBulan[t.Month()], t.Year())
+}
+
+// renderSurat mencetak surat keterangan pindah/keluar pada kertas A4.
+func renderSurat(sekolah *profile.ProfilSekolah, s suratKeluar) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(25, 20, 25)
+	pdf.SetAutoPageBreak(true, 20)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+
+	left, _, right, _ := pdf.GetMargins()
+	pageWidth, _ := pdf.GetPageSize()
+	contentWidth := pageWidth - left - right
+
+	// 1. Kop sekolah
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(contentWidth, 7, tr(strings.ToUpper(sekolah.NamaSekolah)), "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	if alamat := alamatSekolah(sekolah); alamat != "" {
+		pdf.MultiCell(contentWidth, 4.5, tr(alamat), "", "C", false)
+	}
+	if npsn := derefString(sekolah.NPSN); npsn != "" {
+		pdf.CellFormat(contentWidth, 4.5, tr("NPSN: "+npsn), "", 1, "C", false, 0, "")
+	}
+	y := pdf.GetY() + 1
+	pdf.SetLineWidth(0.6)
+	pdf.Line(left, y, left+contentWidth, y)
+	pdf.SetLineWidth(0.2)
+	pdf.Ln(6)
+
+	// 2. Judul dan nomor surat
+	m := s.Mutasi
+	judul := "SURAT KETERANGAN PINDAH SEKOLAH"
+	if derefString(m.Status) == "Keluar" {
+		judul = "SURAT KETERANGAN KELUAR"
+	}
+	pdf.SetFont("Helvetica", "BU", 12)
+	pdf.CellFormat(contentWidth, 6, judul, "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(contentWidth, 6, tr("Nomor: "+derefString(m.NomorSurat)), "", 1, "C", false, 0, "")
+	pdf.Ln(6)
+
+	// 3. Isi surat
+	pdf.MultiCell(contentWidth, 5.5, tr("Yang bertanda tangan di bawah ini, Kepala "+sekolah.NamaSekolah+", menerangkan bahwa:"), "", "L", false)
+	pdf.Ln(2)
+
+	ttl := derefString(s.TempatLahir)
+	if s.TanggalLahir != nil {
+		if ttl != "" {
+			ttl += ", "
+		}
+		ttl += tanggalIndonesia(*s.TanggalLahir)
+	}
+	identitas := [][2]string{
+		{"Nama", m.NamaSiswa},
+		{"NIS / NISN", strings.TrimSpace(derefString(m.NIS) + " / " + derefString(m.NISN))},
+		{"Tempat, Tanggal Lahir", ttl},
+		{"Jenis Kelamin", derefString(s.JenisKelamin)},
+		{"Kelas", derefString(m.NamaKelas)},
+		{"Tahun Pelajaran", derefString(m.NamaTahunAjaran)},
+		{"Nama Orang Tua/Wali", derefString(s.NamaWali)},
+	}
+	for _, row := range identitas {
+		pdf.SetX(left + 10)
+		pdf.CellFormat(50, 6, tr(row[0]), "", 0, "L", false, 0, "")
+		pdf.CellFormat(contentWidth-60, 6, tr(": "+orDash(row[1])), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(3)
+
+	isi := "terhitung sejak tanggal " + tanggalIndonesia(m.Tanggal) + " telah keluar dari sekolah kami"
+	if sekolahTujuan := derefString(m.NamaSekolah); sekolahTujuan != "" {
+		isi = "terhitung sejak tanggal " + tanggalIndonesia(m.Tanggal) + " telah pindah ke " + sekolahTujuan
+		if npsn := derefString(m.NPSNSekolah); npsn != "" {
+			isi += " (NPSN " + npsn + ")"
+		}
+		if alamat := derefString(m.AlamatSekolah); alamat != "" {
+			isi += ", " + alamat
+		}
+	}
+	if alasan := derefString(m.Alasan); alasan != "" {
+		isi += " dengan alasan " + alasan
+	}
+	pdf.MultiCell(contentWidth, 5.5, tr(isi+"."), "", "J", false)
+	pdf.Ln(2)
+	pdf.MultiCell(contentWidth, 5.5, tr("Demikian surat keterangan ini dibuat untuk dapat dipergunakan sebagaimana mestinya."), "", "J", false)
+	pdf.Ln(10)
+
+	// 4. Tanda tangan kepala sekolah
+	colWidth := contentWidth / 2
+	tempatTanggal := tanggalIndonesia(m.Tanggal)
+	if kota := derefString(sekolah.KotaKabupaten); kota != "" {
+		tempatTanggal = kota + ", " + tempatTanggal
+	}
+	pdf.SetX(left + colWidth)
+	pdf.CellFormat(colWidth, 5, tr(tempatTanggal), "", 1, "C", false, 0, "")
+	pdf.SetX(left + colWidth)
+	pdf.CellFormat(colWidth, 5, "Kepala Sekolah", "", 1, "C", false, 0, "")
+	pdf.Ln(20)
+	pdf.SetFont("Helvetica", "BU", 10)
+	pdf.SetX(left + colWidth)
+	kepala := derefString(sekolah.KepalaSekolah)
+	if kepala == "" {
+		kepala = "...................................."
+	}
+	pdf.CellFormat(colWidth, 5, tr(kepala), "", 1, "C", false, 0, "")
+
+	if pdf.Err() {
+		return nil, fmt.Errorf("gagal merender surat mutasi: %w", pdf.Error())
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("gagal menghasilkan file PDF: %w", err)
+	}
+	if buf.Len() == 0 {
+		return nil, errors.New("output PDF kosong")
+	}
+	return buf.Bytes(), nil
+}
+
+func alamatSekolah(sekolah *profile.ProfilSekolah) string {
+	var parts []string
+	for _, p := range []*string{sekolah.Alamat, sekolah.Kelurahan, sekolah.Kecamatan, sekolah.KotaKabupaten, sekolah.Provinsi} {
+		if v := derefString(p); v != "" {
+			parts = append(parts, v)
+		}
+	}
+	alamat := strings.Join(parts, ", ")
+	if telp := derefString(sekolah.Telepon); telp != "" {
+		alamat += " - Telp. " + telp
+	}
+	return alamat
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return strings.TrimSpace(*s)
+}
+
+func orDash(s string) string {
+	if s == "" || s == "/" {
+		return "-"
+	}
+	return s
+}
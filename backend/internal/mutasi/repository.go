@@ -0,0 +1,364 @@
+// file: backend/internal/mutasi/repository.go
+package mutasi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"skoola/pkg/database"
+	"strings"
+	"time"
+)
+
+// Repository mendefinisikan interface untuk interaksi database.
+type Repository interface {
+	GetAll(ctx context.Context, schemaName string, dari *time.Time, sampai *time.Time, jenis string) ([]Mutasi, error)
+	GetByID(ctx context.Context, schemaName string, id string) (*Mutasi, error)
+	GetSurat(ctx context.Context, schemaName string, id string) (*suratKeluar, error)
+	// GetStatusSiswa mengembalikan status terakhir siswa. sql.ErrNoRows jika siswa tidak ada.
+	GetStatusSiswa(ctx context.Context, schemaName string, studentID string) (*string, error)
+	GetKeanggotaanAktif(ctx context.Context, schemaName string, studentID string) ([]keanggotaanAktif, error)
+	GetKelas(ctx context.Context, schemaName string, kelasID string) (*kelasTujuan, error)
+	IsTerdaftar(ctx context.Context, schemaName string, studentID string, tahunAjaranID string) (bool, error)
+	CountKeluar(ctx context.Context, schemaName string, tahun int) (int, error)
+	CreateMasuk(ctx context.Context, schemaName string, m *Mutasi, siswaBaru *SiswaBaruInput, dicatatOleh string) error
+	CreateKeluar(ctx context.Context, schemaName string, m *Mutasi, status string, anggotaKelasIDs []string, dicatatOleh string) error
+}
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewRepository membuat instance baru dari postgresRepository.
+func NewRepository(db *sql.DB) Repository {
+	return &postgresRepository{db: db}
+}
+
+const selectMutasi = `
+	SELECT m.id, m.student_id, s.nama_lengkap, s.nis, s.nisn, m.jenis, ra.status::text, m.tanggal,
+		m.nomor_surat, m.nama_sekolah, m.npsn_sekolah, m.alamat_sekolah, m.alasan,
+		m.kelas_id, k.nama_kelas, ta.nama_tahun_ajaran, m.riwayat_akademik_id, m.created_at
+	FROM mutasi_siswa m
+	JOIN students s ON m.student_id = s.id
+	LEFT JOIN riwayat_akademik ra ON m.riwayat_akademik_id = ra.id
+	LEFT JOIN kelas k ON m.kelas_id = k.id
+	LEFT JOIN tahun_ajaran ta ON k.tahun_ajaran_id = ta.id
+`
+
+func scanMutasi(scanner interface{ Scan(...interface{}) error }, m *Mutasi) error {
+	return scanner.Scan(&m.ID, &m.StudentID, &m.NamaSiswa, &m.NIS, &m.NISN, &m.Jenis, &m.Status, &m.Tanggal,
+		&m.NomorSurat, &m.NamaSekolah, &m.NPSNSekolah, &m.AlamatSekolah, &m.Alasan,
+		&m.KelasID, &m.NamaKelas, &m.NamaTahunAjaran, &m.RiwayatAkademikID, &m.CreatedAt)
+}
+
+func (r *postgresRepository) GetAll(ctx context.Context, schemaName string, dari *time.Time, sampai *time.Time, jenis string) ([]Mutasi, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var where []string
+	var args []interface{}
+	if dari != nil {
+		args = append(args, *dari)
+		where = append(where, fmt.Sprintf("m.tanggal >= $%d", len(args)))
+	}
+	if sampai != nil {
+		args = append(args, *sampai)
+		where = append(where, fmt.Sprintf("m.tanggal <= $%d", len(args)))
+	}
+	if jenis != "" {
+		args = append(args, jenis)
+		where = append(where, fmt.Sprintf("m.jenis = $%d", len(args)))
+	}
+	query := selectMutasi
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY m.tanggal DESC, m.created_at DESC"
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil data mutasi: %w", err)
+	}
+	defer rows.Close()
+
+	list := []Mutasi{}
+	for rows.Next() {
+		var m Mutasi
+		if err := scanMutasi(rows, &m); err != nil {
+			return nil, fmt.Errorf("gagal memindai data mutasi: %w", err)
+		}
+		list = append(list, m)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+func (r *postgresRepository) GetByID(ctx context.Context, schemaName string, id string) (*Mutasi, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var m Mutasi
+	if err := scanMutasi(tx.QueryRowContext(ctx, selectMutasi+" WHERE m.id = $1", id), &m); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("gagal mengambil data mutasi: %w", err)
+	}
+	return &m, tx.Commit()
+}
+
+func (r *postgresRepository) GetSurat(ctx context.Context, schemaName string, id string) (*suratKeluar, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var s suratKeluar
+	if err := scanMutasi(tx.QueryRowContext(ctx, selectMutasi+" WHERE m.id = $1", id), &s.Mutasi); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("gagal mengambil data mutasi: %w", err)
+	}
+	query := `SELECT tempat_lahir, tanggal_lahir, jenis_kelamin::text, COALESCE(nama_wali, nama_ayah, nama_ibu) FROM students WHERE id = $1`
+	if err := tx.QueryRowContext(ctx, query, s.Mutasi.StudentID).Scan(&s.TempatLahir, &s.TanggalLahir, &s.JenisKelamin, &s.NamaWali); err != nil {
+		return nil, fmt.Errorf("gagal mengambil biodata siswa: %w", err)
+	}
+	return &s, tx.Commit()
+}
+
+func (r *postgresRepository) GetStatusSiswa(ctx context.Context, schemaName string, studentID string) (*string, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT (
+			SELECT ra.status::text FROM riwayat_akademik ra
+			WHERE ra.student_id = s.id
+			ORDER BY ra.tanggal_kejadian DESC, ra.created_at DESC
+			LIMIT 1
+		)
+		FROM students s WHERE s.id = $1
+	`
+	var status *string
+	if err := tx.QueryRowContext(ctx, query, studentID).Scan(&status); err != nil {
+		return nil, err
+	}
+	return status, tx.Commit()
+}
+
+func (r *postgresRepository) GetKeanggotaanAktif(ctx context.Context, schemaName string, studentID string) ([]keanggotaanAktif, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT ak.id, k.id, k.nama_kelas
+		FROM anggota_kelas ak
+		JOIN kelas k ON ak.kelas_id = k.id
+		JOIN tahun_ajaran ta ON k.tahun_ajaran_id = ta.id
+		WHERE ak.student_id = $1 AND ta.status = 'Aktif' AND ak.tanggal_keluar IS NULL
+		ORDER BY k.nama_kelas
+	`
+	rows, err := tx.QueryContext(ctx, query, studentID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil keanggotaan kelas: %w", err)
+	}
+	defer rows.Close()
+
+	var list []keanggotaanAktif
+	for rows.Next() {
+		var k keanggotaanAktif
+		if err := rows.Scan(&k.AnggotaKelasID, &k.KelasID, &k.NamaKelas); err != nil {
+			return nil, fmt.Errorf("gagal memindai keanggotaan kelas: %w", err)
+		}
+		list = append(list, k)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+func (r *postgresRepository) GetKelas(ctx context.Context, schemaName string, kelasID string) (*kelasTujuan, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var k kelasTujuan
+	err = tx.QueryRowContext(ctx, "SELECT id, nama_kelas, tahun_ajaran_id FROM kelas WHERE id = $1", kelasID).Scan(&k.KelasID, &k.NamaKelas, &k.TahunAjaranID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("gagal mengambil kelas: %w", err)
+	}
+	return &k, tx.Commit()
+}
+
+func (r *postgresRepository) IsTerdaftar(ctx context.Context, schemaName string, studentID string, tahunAjaranID string) (bool, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM anggota_kelas ak
+			JOIN kelas k ON ak.kelas_id = k.id
+			WHERE ak.student_id = $1 AND k.tahun_ajaran_id = $2 AND ak.tanggal_keluar IS NULL
+		)
+	`
+	var ada bool
+	if err := tx.QueryRowContext(ctx, query, studentID, tahunAjaranID).Scan(&ada); err != nil {
+		return false, fmt.Errorf("gagal memeriksa keanggotaan kelas: %w", err)
+	}
+	return ada, tx.Commit()
+}
+
+func (r *postgresRepository) CountKeluar(ctx context.Context, schemaName string, tahun int) (int, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var jumlah int
+	query := "SELECT COUNT(*) FROM mutasi_siswa WHERE jenis = 'KELUAR' AND EXTRACT(YEAR FROM tanggal) = $1"
+	if err := tx.QueryRowContext(ctx, query, tahun).Scan(&jumlah); err != nil {
+		return 0, fmt.Errorf("gagal menghitung mutasi keluar: %w", err)
+	}
+	return jumlah, tx.Commit()
+}
+
+// CreateMasuk mendaftarkan siswa baru (jika ada), menempatkannya di rombel, lalu mencatat
+// riwayat akademik dan mutasinya dalam satu transaksi.
+func (r *postgresRepository) CreateMasuk(ctx context.Context, schemaName string, m *Mutasi, siswaBaru *SiswaBaruInput, dicatatOleh string) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if siswaBaru != nil {
+		query := `
+			INSERT INTO students (id, nama_lengkap, nis, nisn, jenis_kelamin, tempat_lahir, tanggal_lahir)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`
+		if _, err := tx.ExecContext(ctx, query, m.StudentID, siswaBaru.NamaLengkap, nullString(siswaBaru.NIS), m.NISN,
+			nullString(siswaBaru.JenisKelamin), nullString(siswaBaru.TempatLahir), nullString(siswaBaru.TanggalLahir)); err != nil {
+			return fmt.Errorf("gagal mendaftarkan siswa: %w", err)
+		}
+	} else if m.NISN != nil {
+		if _, err := tx.ExecContext(ctx, "UPDATE students SET nisn = $1, updated_at = NOW() WHERE id = $2", m.NISN, m.StudentID); err != nil {
+			return fmt.Errorf("gagal memperbarui NISN siswa: %w", err)
+		}
+	}
+
+	var maxUrutan sql.NullInt64
+	if err := tx.QueryRowContext(ctx, "SELECT MAX(urutan) FROM anggota_kelas WHERE kelas_id = $1", m.KelasID).Scan(&maxUrutan); err != nil {
+		return fmt.Errorf("gagal mendapatkan urutan maksimal: %w", err)
+	}
+	anggotaQuery := `
+		INSERT INTO anggota_kelas (kelas_id, student_id, urutan) VALUES ($1, $2, $3)
+		ON CONFLICT (kelas_id, student_id) DO UPDATE SET tanggal_keluar = NULL
+	`
+	if _, err := tx.ExecContext(ctx, anggotaQuery, m.KelasID, m.StudentID, maxUrutan.Int64+1); err != nil {
+		return fmt.Errorf("gagal menempatkan siswa di kelas: %w", err)
+	}
+
+	keterangan := "Mutasi masuk"
+	if m.NamaSekolah != nil {
+		keterangan += " dari " + *m.NamaSekolah
+	}
+	if err := insertRiwayat(ctx, tx, m, "Aktif", m.NamaKelas, keterangan); err != nil {
+		return err
+	}
+	if err := insertMutasi(ctx, tx, m, dicatatOleh); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// CreateKeluar menutup keanggotaan kelas aktif siswa, lalu mencatat riwayat akademik dan
+// mutasinya dalam satu transaksi.
+func (r *postgresRepository) CreateKeluar(ctx context.Context, schemaName string, m *Mutasi, status string, anggotaKelasIDs []string, dicatatOleh string) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, id := range anggotaKelasIDs {
+		if _, err := tx.ExecContext(ctx, "UPDATE anggota_kelas SET tanggal_keluar = $1 WHERE id = $2", m.Tanggal, id); err != nil {
+			return fmt.Errorf("gagal menutup keanggotaan kelas: %w", err)
+		}
+	}
+
+	keterangan := "Keluar"
+	if status == "Pindah" {
+		keterangan = "Pindah"
+		if m.NamaSekolah != nil {
+			keterangan += " ke " + *m.NamaSekolah
+		}
+	}
+	if m.Alasan != nil {
+		keterangan += ": " + *m.Alasan
+	}
+	if err := insertRiwayat(ctx, tx, m, status, m.NamaKelas, keterangan); err != nil {
+		return err
+	}
+	if err := insertMutasi(ctx, tx, m, dicatatOleh); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertRiwayat(ctx context.Context, tx *sql.Tx, m *Mutasi, status string, kelasTingkat *string, keterangan string) error {
+	query := `
+		INSERT INTO riwayat_akademik (id, student_id, status, tanggal_kejadian, kelas_tingkat, keterangan)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	if _, err := tx.ExecContext(ctx, query, m.RiwayatAkademikID, m.StudentID, status, m.Tanggal, kelasTingkat, keterangan); err != nil {
+		return fmt.Errorf("gagal mencatat riwayat akademik: %w", err)
+	}
+	return nil
+}
+
+func insertMutasi(ctx context.Context, tx *sql.Tx, m *Mutasi, dicatatOleh string) error {
+	query := `
+		INSERT INTO mutasi_siswa (id, student_id, jenis, tanggal, nomor_surat, nama_sekolah, npsn_sekolah,
+			alamat_sekolah, alasan, kelas_id, riwayat_akademik_id, dicatat_oleh)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+	_, err := tx.ExecContext(ctx, query, m.ID, m.StudentID, m.Jenis, m.Tanggal, m.NomorSurat, m.NamaSekolah, m.NPSNSekolah,
+		m.AlamatSekolah, m.Alasan, m.KelasID, m.RiwayatAkademikID, nullString(dicatatOleh))
+	if err != nil {
+		return fmt.Errorf("gagal menyimpan data mutasi: %w", err)
+	}
+	return nil
+}
+
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,265 @@
+// file: backend/internal/mutasi/service.go
+package mutasi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"skoola/internal/access"
+	"skoola/internal/audit"
+	"skoola/internal/profile"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+var ErrValidation = errors.New("validation failed")
+
+// Service mendefinisikan logika bisnis mutasi siswa.
+type Service interface {
+	GetLaporan(ctx context.Context, schemaName string, dari string, sampai string, jenis string) (*Laporan, error)
+	GetByID(ctx context.Context, schemaName string, id string) (*Mutasi, error)
+	Masuk(ctx context.Context, schemaName string, actor access.Actor, input MutasiMasukInput) (*Mutasi, error)
+	Keluar(ctx context.Context, schemaName string, actor access.Actor, input MutasiKeluarInput) (*Mutasi, error)
+	GenerateSurat(ctx context.Context, schemaName string, id string) ([]byte, error)
+}
+
+type service struct {
+	repo        Repository
+	profileRepo profile.Repository
+	audit       audit.Recorder
+	validate    *validator.Validate
+}
+
+// NewService membuat instance baru dari service mutasi siswa.
+func NewService(repo Repository, profileRepo profile.Repository, auditLog audit.Recorder, validate *validator.Validate) Service {
+	return &service{repo: repo, profileRepo: profileRepo, audit: auditLog, validate: validate}
+}
+
+func (s *service) GetLaporan(ctx context.Context, schemaName string, dari string, sampai string, jenis string) (*Laporan, error) {
+	dariTgl, err := parseTanggal(dari, "dari")
+	if err != nil {
+		return nil, err
+	}
+	sampaiTgl, err := parseTanggal(sampai, "sampai")
+	if err != nil {
+		return nil, err
+	}
+	if dariTgl != nil && sampaiTgl != nil && sampaiTgl.Before(*dariTgl) {
+		return nil, fmt.Errorf("%w: tanggal sampai tidak boleh sebelum tanggal dari", ErrValidation)
+	}
+	jenis = strings.ToUpper(jenis)
+	if jenis != "" && jenis != JenisMasuk && jenis != JenisKeluar {
+		return nil, fmt.Errorf("%w: jenis harus MASUK atau KELUAR", ErrValidation)
+	}
+
+	list, err := s.repo.GetAll(ctx, schemaName, dariTgl, sampaiTgl, jenis)
+	if err != nil {
+		return nil, err
+	}
+	laporan := &Laporan{Dari: dari, Sampai: sampai, Data: list}
+	for _, m := range list {
+		if m.Jenis == JenisMasuk {
+			laporan.JumlahMasuk++
+		} else {
+			laporan.JumlahKeluar++
+		}
+	}
+	return laporan, nil
+}
+
+func (s *service) GetByID(ctx context.Context, schemaName string, id string) (*Mutasi, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, fmt.Errorf("%w: id mutasi tidak valid", ErrValidation)
+	}
+	m, err := s.repo.GetByID(ctx, schemaName, id)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, sql.ErrNoRows
+	}
+	return m, nil
+}
+
+func (s *service) Masuk(ctx context.Context, schemaName string, actor access.Actor, input MutasiMasukInput) (*Mutasi, error) {
+	if err := s.validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	if (input.StudentID == nil) == (input.Siswa == nil) {
+		return nil, fmt.Errorf("%w: isi salah satu dari student_id atau data siswa baru", ErrValidation)
+	}
+	if input.Siswa != nil {
+		if err := s.validate.Struct(input.Siswa); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+		}
+	}
+
+	kelas, err := s.repo.GetKelas(ctx, schemaName, input.KelasID)
+	if err != nil {
+		return nil, err
+	}
+	if kelas == nil {
+		return nil, fmt.Errorf("%w: kelas tujuan tidak ditemukan", ErrValidation)
+	}
+
+	studentID := uuid.New().String()
+	if input.StudentID != nil {
+		studentID = *input.StudentID
+		status, err := s.repo.GetStatusSiswa(ctx, schemaName, studentID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("%w: siswa tidak ditemukan", ErrValidation)
+			}
+			return nil, err
+		}
+		if status != nil && *status == "Lulus" {
+			return nil, fmt.Errorf("%w: siswa sudah lulus", ErrValidation)
+		}
+		terdaftar, err := s.repo.IsTerdaftar(ctx, schemaName, studentID, kelas.TahunAjaranID)
+		if err != nil {
+			return nil, err
+		}
+		if terdaftar {
+			return nil, fmt.Errorf("%w: siswa sudah terdaftar di kelas pada tahun ajaran ini", ErrValidation)
+		}
+	}
+
+	tanggal, _ := time.Parse("2006-01-02", input.Tanggal)
+	m := &Mutasi{
+		ID:                uuid.New().String(),
+		StudentID:         studentID,
+		NISN:              nullString(input.NISN),
+		Jenis:             JenisMasuk,
+		Tanggal:           tanggal,
+		NomorSurat:        nullString(strings.TrimSpace(input.NomorSurat)),
+		NamaSekolah:       nullString(input.NamaSekolah),
+		NPSNSekolah:       nullString(input.NPSNSekolah),
+		AlamatSekolah:     nullString(input.AlamatSekolah),
+		Alasan:            nullString(input.Alasan),
+		KelasID:           &kelas.KelasID,
+		NamaKelas:         &kelas.NamaKelas,
+		RiwayatAkademikID: ptr(uuid.New().String()),
+	}
+	if err := s.repo.CreateMasuk(ctx, schemaName, m, input.Siswa, actor.UserID); err != nil {
+		return nil, err
+	}
+
+	created, err := s.GetByID(ctx, schemaName, m.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionCreate, Entity: "mutasi_siswa", EntityID: m.ID, After: created})
+	return created, nil
+}
+
+func (s *service) Keluar(ctx context.Context, schemaName string, actor access.Actor, input MutasiKeluarInput) (*Mutasi, error) {
+	if err := s.validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+
+	status, err := s.repo.GetStatusSiswa(ctx, schemaName, input.StudentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: siswa tidak ditemukan", ErrValidation)
+		}
+		return nil, err
+	}
+	if status != nil && *status != "Aktif" {
+		return nil, fmt.Errorf("%w: siswa berstatus %s, bukan siswa aktif", ErrValidation, *status)
+	}
+	keanggotaan, err := s.repo.GetKeanggotaanAktif(ctx, schemaName, input.StudentID)
+	if err != nil {
+		return nil, err
+	}
+
+	tanggal, _ := time.Parse("2006-01-02", input.Tanggal)
+	nomorSurat := strings.TrimSpace(input.NomorSurat)
+	if nomorSurat == "" {
+		jumlah, err := s.repo.CountKeluar(ctx, schemaName, tanggal.Year())
+		if err != nil {
+			return nil, err
+		}
+		nomorSurat = nomorSuratOtomatis(jumlah+1, tanggal)
+	}
+
+	m := &Mutasi{
+		ID:                uuid.New().String(),
+		StudentID:         input.StudentID,
+		Jenis:             JenisKeluar,
+		Tanggal:           tanggal,
+		NomorSurat:        &nomorSurat,
+		NamaSekolah:       nullString(input.NamaSekolah),
+		NPSNSekolah:       nullString(input.NPSNSekolah),
+		AlamatSekolah:     nullString(input.AlamatSekolah),
+		Alasan:            nullString(input.Alasan),
+		RiwayatAkademikID: ptr(uuid.New().String()),
+	}
+	anggotaKelasIDs := make([]string, 0, len(keanggotaan))
+	for _, k := range keanggotaan {
+		anggotaKelasIDs = append(anggotaKelasIDs, k.AnggotaKelasID)
+	}
+	if len(keanggotaan) > 0 {
+		m.KelasID = &keanggotaan[0].KelasID
+		m.NamaKelas = &keanggotaan[0].NamaKelas
+	}
+	if err := s.repo.CreateKeluar(ctx, schemaName, m, input.Status, anggotaKelasIDs, actor.UserID); err != nil {
+		return nil, err
+	}
+
+	created, err := s.GetByID(ctx, schemaName, m.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionCreate, Entity: "mutasi_siswa", EntityID: m.ID, After: created})
+	return created, nil
+}
+
+func (s *service) GenerateSurat(ctx context.Context, schemaName string, id string) ([]byte, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, fmt.Errorf("%w: id mutasi tidak valid", ErrValidation)
+	}
+	surat, err := s.repo.GetSurat(ctx, schemaName, id)
+	if err != nil {
+		return nil, err
+	}
+	if surat == nil {
+		return nil, sql.ErrNoRows
+	}
+	if surat.Mutasi.Jenis != JenisKeluar {
+		return nil, fmt.Errorf("%w: surat pindah hanya tersedia untuk mutasi keluar", ErrValidation)
+	}
+	sekolah, err := s.profileRepo.GetProfile(ctx, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	if sekolah == nil {
+		sekolah = &profile.ProfilSekolah{}
+	}
+	return renderSurat(sekolah, *surat)
+}
+
+var bulanRomawi = []string{"", "I", "II", "III", "IV", "V", "VI", "VII", "VIII", "IX", "X", "XI", "XII"}
+
+// nomorSuratOtomatis membuat nomor seperti "007/MUTASI/VIII/2025".
+func nomorSuratOtomatis(urutan int, tanggal time.Time) string {
+	return fmt.Sprintf("%03d/MUTASI/%s/%d", urutan, bulanRomawi[tanggal.Month()], tanggal.Year())
+}
+
+func parseTanggal(value string, field string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: format tanggal %s harus YYYY-MM-DD", ErrValidation, field)
+	}
+	return &t, nil
+}
+
+func ptr(s string) *string {
+	return &s
+}
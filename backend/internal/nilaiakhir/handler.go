@@ -0,0 +1,99 @@
+// file: backend/internal/nilaiakhir/handler.go
+package nilaiakhir
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"skoola/internal/access"
+	"skoola/internal/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{service: s}
+}
+
+// GetAturan adalah handler untuk GET /nilai-akhir/aturan/{tahunAjaranID}.
+func (h *Handler) GetAturan(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	tahunAjaranID := chi.URLParam(r, "tahunAjaranID")
+
+	aturan, err := h.service.GetAturan(r.Context(), schemaName, tahunAjaranID)
+	if err != nil {
+		writeError(w, "Gagal mengambil aturan nilai: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(aturan)
+}
+
+// UpsertAturan adalah handler untuk PUT /nilai-akhir/aturan/{tahunAjaranID}.
+func (h *Handler) UpsertAturan(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	tahunAjaranID := chi.URLParam(r, "tahunAjaranID")
+
+	var input UpsertAturanInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	aturan, err := h.service.UpsertAturan(r.Context(), schemaName, tahunAjaranID, input)
+	if err != nil {
+		writeError(w, "Gagal menyimpan aturan nilai: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(aturan)
+}
+
+// DeleteAturan adalah handler untuk DELETE /nilai-akhir/aturan/{tahunAjaranID}.
+func (h *Handler) DeleteAturan(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	tahunAjaranID := chi.URLParam(r, "tahunAjaranID")
+
+	if err := h.service.DeleteAturan(r.Context(), schemaName, tahunAjaranID); err != nil {
+		writeError(w, "Gagal menghapus aturan nilai: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Aturan nilai berhasil dihapus, perhitungan kembali memakai aturan bawaan."})
+}
+
+// GetNilaiAkhirMapel adalah handler untuk GET /nilai-akhir/pengajar/{pengajarKelasID}.
+func (h *Handler) GetNilaiAkhirMapel(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	pengajarKelasID := chi.URLParam(r, "pengajarKelasID")
+
+	result, err := h.service.GetNilaiAkhirMapel(r.Context(), schemaName, access.ActorFromContext(r.Context()), pengajarKelasID)
+	if err != nil {
+		writeError(w, "Gagal menghitung nilai akhir: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func writeError(w http.ResponseWriter, prefix string, err error) {
+	switch {
+	case errors.Is(err, access.ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, ErrValidation):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Data tidak ditemukan", http.StatusNotFound)
+	default:
+		http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+	}
+}
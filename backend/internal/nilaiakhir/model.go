@@ -0,0 +1,98 @@
+// file: backend/internal/nilaiakhir/model.go
+package nilaiakhir
+
+import "time"
+
+// Kebijakan untuk nilai yang belum diisi saat menghitung nilai akhir.
+const (
+	KebijakanNol     = "NOL"
+	KebijakanAbaikan = "ABAIKAN"
+	KebijakanBlokir  = "BLOKIR"
+)
+
+// KodeFormatif adalah kode komponen rata-rata nilai TP pada hasil perhitungan.
+const KodeFormatif = "FORMATIF"
+
+// BobotJenisUjian adalah bobot satu jenis ujian dalam aturan nilai.
+type BobotJenisUjian struct {
+	JenisUjianID int     `json:"jenis_ujian_id"`
+	KodeUjian    string  `json:"kode_ujian"`
+	NamaUjian    string  `json:"nama_ujian"`
+	Bobot        float64 `json:"bobot"`
+}
+
+// AturanNilai merepresentasikan data dari tabel 'aturan_nilai' beserta bobot jenis ujiannya.
+// ID bernilai 0 berarti tahun ajaran belum memiliki aturan dan aturan bawaan yang dipakai:
+// formatif 50%, sisanya dibagi rata ke jenis ujian yang dipakai di mapel tersebut.
+type AturanNilai struct {
+	ID                   int               `json:"id"`
+	TahunAjaranID        string            `json:"tahun_ajaran_id"`
+	BobotFormatif        float64           `json:"bobot_formatif"`
+	KebijakanNilaiKosong string            `json:"kebijakan_nilai_kosong"`
+	BobotJenisUjian      []BobotJenisUjian `json:"bobot_jenis_ujian"`
+	CreatedAt            *time.Time        `json:"created_at,omitempty"`
+	UpdatedAt            *time.Time        `json:"updated_at,omitempty"`
+}
+
+// UpsertBobotInput adalah bobot satu jenis ujian pada UpsertAturanInput.
+type UpsertBobotInput struct {
+	JenisUjianID int     `json:"jenis_ujian_id" validate:"required"`
+	Bobot        float64 `json:"bobot" validate:"min=0,max=100"`
+}
+
+// UpsertAturanInput adalah DTO untuk menyimpan aturan nilai satu tahun ajaran.
+// Jumlah bobot formatif dan seluruh bobot jenis ujian harus 100.
+type UpsertAturanInput struct {
+	BobotFormatif        float64            `json:"bobot_formatif" validate:"min=0,max=100"`
+	KebijakanNilaiKosong string             `json:"kebijakan_nilai_kosong" validate:"required,oneof=NOL ABAIKAN BLOKIR"`
+	BobotJenisUjian      []UpsertBobotInput `json:"bobot_jenis_ujian" validate:"dive"`
+}
+
+// KomponenNilai adalah satu komponen nilai akhir (formatif atau satu jenis ujian).
+// Nilai adalah rata-rata komponen setelah kebijakan nilai kosong diterapkan.
+type KomponenNilai struct {
+	Kode         string   `json:"kode"`
+	Nama         string   `json:"nama"`
+	Bobot        float64  `json:"bobot"`
+	Nilai        *float64 `json:"nilai"`
+	JumlahKosong int      `json:"jumlah_kosong"`
+}
+
+// NilaiAkhirSiswa adalah hasil perhitungan nilai akhir satu siswa pada satu mapel.
+// NilaiAkhir nil berarti belum ada nilai, atau diblokir karena masih ada nilai kosong.
+// Tuntas dibandingkan dengan KKTP mapel dan nil jika KKTP belum diatur.
+type NilaiAkhirSiswa struct {
+	AnggotaKelasID string          `json:"anggota_kelas_id"`
+	NamaSiswa      string          `json:"nama_siswa"`
+	NIS            *string         `json:"nis"`
+	Komponen       []KomponenNilai `json:"komponen"`
+	NilaiAkhir     *float64        `json:"nilai_akhir"`
+	Lengkap        bool            `json:"lengkap"`
+	Tuntas         *bool           `json:"tuntas,omitempty"`
+}
+
+// NilaiAkhirMapel adalah nilai akhir semua siswa pada satu pengajar kelas (mapel di kelas).
+type NilaiAkhirMapel struct {
+	PengajarKelasID string            `json:"pengajar_kelas_id"`
+	KelasID         string            `json:"kelas_id"`
+	Aturan          AturanNilai       `json:"aturan"`
+	KKTP            *float64          `json:"kktp"`
+	Siswa           []NilaiAkhirSiswa `json:"siswa"`
+}
+
+// siswaKelas adalah anggota kelas yang dihitung nilainya.
+type siswaKelas struct {
+	AnggotaKelasID string
+	NamaSiswa      string
+	NIS            *string
+}
+
+// asesmen adalah satu kolom nilai milik pengajar kelas: TP (formatif, JenisUjianID 0)
+// atau penilaian sumatif dengan jenis ujiannya.
+type asesmen struct {
+	PengajarKelasID string
+	Key             string
+	JenisUjianID    int
+	KodeUjian       string
+	NamaUjian       string
+}
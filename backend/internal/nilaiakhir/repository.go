@@ -0,0 +1,329 @@
+// file: backend/internal/nilaiakhir/repository.go
+package nilaiakhir
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"skoola/pkg/database"
+)
+
+// Repository mendefinisikan interface untuk interaksi database.
+type Repository interface {
+	GetAturan(ctx context.Context, schemaName string, tahunAjaranID string) (*AturanNilai, error)
+	UpsertAturan(ctx context.Context, schemaName string, tahunAjaranID string, input UpsertAturanInput) error
+	DeleteAturan(ctx context.Context, schemaName string, tahunAjaranID string) error
+	GetKelasPengajar(ctx context.Context, schemaName string, pengajarKelasID string) (kelasID string, tahunAjaranID string, err error)
+	GetTahunAjaranKelas(ctx context.Context, schemaName string, kelasID string) (string, error)
+	// GetPengajarKelasIDs mengembalikan semua pengajar kelas (mapel) di kelas, termasuk yang
+	// belum memiliki kolom nilai.
+	GetPengajarKelasIDs(ctx context.Context, schemaName string, kelasID string) ([]string, error)
+	// GetSiswa mengembalikan anggota kelas yang belum keluar per tanggal selesai semester
+	// pada kalender akademik, atau per hari ini jika tanggal itu belum diisi.
+	GetSiswa(ctx context.Context, schemaName string, kelasID string) ([]siswaKelas, error)
+	GetAsesmen(ctx context.Context, schemaName string, kelasID string, pengajarKelasID string) ([]asesmen, error)
+	GetNilai(ctx context.Context, schemaName string, kelasID string) (map[string]map[string]float64, error)
+}
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewRepository membuat instance baru dari postgresRepository.
+func NewRepository(db *sql.DB) Repository {
+	return &postgresRepository{db: db}
+}
+
+// GetAturan mengambil aturan nilai tahun ajaran. Hasilnya nil jika belum diatur.
+func (r *postgresRepository) GetAturan(ctx context.Context, schemaName string, tahunAjaranID string) (*AturanNilai, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT id, tahun_ajaran_id, bobot_formatif::float8, kebijakan_nilai_kosong, created_at, updated_at
+		FROM aturan_nilai
+		WHERE tahun_ajaran_id = $1
+	`
+	var a AturanNilai
+	err = tx.QueryRowContext(ctx, query, tahunAjaranID).Scan(
+		&a.ID, &a.TahunAjaranID, &a.BobotFormatif, &a.KebijakanNilaiKosong, &a.CreatedAt, &a.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("gagal mengambil aturan nilai: %w", err)
+	}
+
+	bobotQuery := `
+		SELECT b.jenis_ujian_id, ju.kode_ujian, ju.nama_ujian, b.bobot::float8
+		FROM bobot_jenis_ujian b
+		JOIN jenis_ujian ju ON b.jenis_ujian_id = ju.id
+		WHERE b.aturan_nilai_id = $1
+		ORDER BY ju.kode_ujian
+	`
+	rows, err := tx.QueryContext(ctx, bobotQuery, a.ID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil bobot jenis ujian: %w", err)
+	}
+	defer rows.Close()
+
+	a.BobotJenisUjian = []BobotJenisUjian{}
+	for rows.Next() {
+		var b BobotJenisUjian
+		if err := rows.Scan(&b.JenisUjianID, &b.KodeUjian, &b.NamaUjian, &b.Bobot); err != nil {
+			return nil, fmt.Errorf("gagal memindai bobot jenis ujian: %w", err)
+		}
+		a.BobotJenisUjian = append(a.BobotJenisUjian, b)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return &a, tx.Commit()
+}
+
+// UpsertAturan menyimpan aturan nilai dan mengganti seluruh bobot jenis ujiannya.
+func (r *postgresRepository) UpsertAturan(ctx context.Context, schemaName string, tahunAjaranID string, input UpsertAturanInput) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO aturan_nilai (tahun_ajaran_id, bobot_formatif, kebijakan_nilai_kosong)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (tahun_ajaran_id) DO UPDATE SET
+			bobot_formatif = EXCLUDED.bobot_formatif,
+			kebijakan_nilai_kosong = EXCLUDED.kebijakan_nilai_kosong,
+			updated_at = NOW()
+		RETURNING id
+	`
+	var id int
+	if err := tx.QueryRowContext(ctx, query, tahunAjaranID, input.BobotFormatif, input.KebijakanNilaiKosong).Scan(&id); err != nil {
+		return fmt.Errorf("gagal menyimpan aturan nilai: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM bobot_jenis_ujian WHERE aturan_nilai_id = $1`, id); err != nil {
+		return fmt.Errorf("gagal menghapus bobot jenis ujian lama: %w", err)
+	}
+	for _, b := range input.BobotJenisUjian {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO bobot_jenis_ujian (aturan_nilai_id, jenis_ujian_id, bobot) VALUES ($1, $2, $3)`,
+			id, b.JenisUjianID, b.Bobot)
+		if err != nil {
+			return fmt.Errorf("gagal menyimpan bobot jenis ujian %d: %w", b.JenisUjianID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *postgresRepository) DeleteAturan(ctx context.Context, schemaName string, tahunAjaranID string) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM aturan_nilai WHERE tahun_ajaran_id = $1`, tahunAjaranID)
+	if err != nil {
+		return fmt.Errorf("gagal menghapus aturan nilai: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
+func (r *postgresRepository) GetKelasPengajar(ctx context.Context, schemaName string, pengajarKelasID string) (string, string, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return "", "", err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT k.id, k.tahun_ajaran_id
+		FROM pengajar_kelas pk
+		JOIN kelas k ON pk.kelas_id = k.id
+		WHERE pk.id = $1
+	`
+	var kelasID, tahunAjaranID string
+	if err := tx.QueryRowContext(ctx, query, pengajarKelasID).Scan(&kelasID, &tahunAjaranID); err != nil {
+		return "", "", err
+	}
+	return kelasID, tahunAjaranID, tx.Commit()
+}
+
+func (r *postgresRepository) GetTahunAjaranKelas(ctx context.Context, schemaName string, kelasID string) (string, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	var tahunAjaranID string
+	if err := tx.QueryRowContext(ctx, `SELECT tahun_ajaran_id FROM kelas WHERE id = $1`, kelasID).Scan(&tahunAjaranID); err != nil {
+		return "", err
+	}
+	return tahunAjaranID, tx.Commit()
+}
+
+func (r *postgresRepository) GetPengajarKelasIDs(ctx context.Context, schemaName string, kelasID string) ([]string, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `SELECT id FROM pengajar_kelas WHERE kelas_id = $1`, kelasID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil daftar mapel kelas: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("gagal memindai daftar mapel kelas: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return ids, tx.Commit()
+}
+
+func (r *postgresRepository) GetSiswa(ctx context.Context, schemaName string, kelasID string) ([]siswaKelas, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT ak.id, s.nama_lengkap, s.nis
+		FROM anggota_kelas ak
+		JOIN students s ON ak.student_id = s.id
+		JOIN kelas k ON ak.kelas_id = k.id
+		LEFT JOIN kalender_pengaturan kp ON kp.tahun_ajaran_id = k.tahun_ajaran_id
+		WHERE ak.kelas_id = $1 AND (ak.tanggal_keluar IS NULL OR ak.tanggal_keluar > COALESCE(kp.tanggal_selesai, CURRENT_DATE))
+		ORDER BY ak.urutan ASC, s.nama_lengkap ASC
+	`
+	rows, err := tx.QueryContext(ctx, query, kelasID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil data siswa: %w", err)
+	}
+	defer rows.Close()
+
+	var list []siswaKelas
+	for rows.Next() {
+		var s siswaKelas
+		if err := rows.Scan(&s.AnggotaKelasID, &s.NamaSiswa, &s.NIS); err != nil {
+			return nil, fmt.Errorf("gagal memindai data siswa: %w", err)
+		}
+		list = append(list, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+// GetAsesmen mengambil semua kolom nilai (TP dan penilaian sumatif) di kelas.
+// pengajarKelasID kosong berarti semua mapel di kelas.
+func (r *postgresRepository) GetAsesmen(ctx context.Context, schemaName string, kelasID string, pengajarKelasID string) ([]asesmen, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT pk.id, 'tp:' || tp.id, 0, '', ''
+		FROM tujuan_pembelajaran tp
+		JOIN materi_pembelajaran m ON tp.materi_pembelajaran_id = m.id
+		JOIN pengajar_kelas pk ON m.pengajar_kelas_id = pk.id
+		WHERE pk.kelas_id = $1 AND ($2 = '' OR pk.id::text = $2)
+		UNION ALL
+		SELECT pk.id, 'ps:' || ps.id, ju.id, ju.kode_ujian, ju.nama_ujian
+		FROM penilaian_sumatif ps
+		JOIN jenis_ujian ju ON ps.jenis_ujian_id = ju.id
+		LEFT JOIN tujuan_pembelajaran tp ON ps.tujuan_pembelajaran_id = tp.id
+		LEFT JOIN materi_pembelajaran m ON tp.materi_pembelajaran_id = m.id
+		LEFT JOIN ujian u ON ps.ujian_id = u.id
+		JOIN pengajar_kelas pk ON pk.id = COALESCE(m.pengajar_kelas_id, u.pengajar_kelas_id)
+		WHERE pk.kelas_id = $1 AND ($2 = '' OR pk.id::text = $2)
+	`
+	rows, err := tx.QueryContext(ctx, query, kelasID, pengajarKelasID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil daftar penilaian: %w", err)
+	}
+	defer rows.Close()
+
+	var list []asesmen
+	for rows.Next() {
+		var a asesmen
+		if err := rows.Scan(&a.PengajarKelasID, &a.Key, &a.JenisUjianID, &a.KodeUjian, &a.NamaUjian); err != nil {
+			return nil, fmt.Errorf("gagal memindai daftar penilaian: %w", err)
+		}
+		list = append(list, a)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+// GetNilai mengambil semua nilai yang sudah terisi di kelas.
+// Hasilnya map[anggota_kelas_id]map[key asesmen]nilai.
+func (r *postgresRepository) GetNilai(ctx context.Context, schemaName string, kelasID string) (map[string]map[string]float64, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT p.anggota_kelas_id, 'tp:' || p.tujuan_pembelajaran_id, p.nilai::float8
+		FROM penilaian p
+		JOIN anggota_kelas ak ON p.anggota_kelas_id = ak.id
+		WHERE ak.kelas_id = $1 AND p.nilai IS NOT NULL
+		UNION ALL
+		SELECT ns.anggota_kelas_id, 'ps:' || ns.penilaian_sumatif_id, ns.nilai::float8
+		FROM nilai_sumatif_siswa ns
+		JOIN anggota_kelas ak ON ns.anggota_kelas_id = ak.id
+		WHERE ak.kelas_id = $1 AND ns.nilai IS NOT NULL
+	`
+	rows, err := tx.QueryContext(ctx, query, kelasID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil nilai siswa: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[string]map[string]float64)
+	for rows.Next() {
+		var anggotaID, key string
+		var nilai float64
+		if err := rows.Scan(&anggotaID, &key, &nilai); err != nil {
+			return nil, fmt.Errorf("gagal memindai nilai siswa: %w", err)
+		}
+		if result[anggotaID] == nil {
+			result[anggotaID] = make(map[string]float64)
+		}
+		result[anggotaID][key] = nilai
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, tx.Commit()
+}
@@ -0,0 +1,335 @@
+// file: backend/internal/nilaiakhir/service.go
+package nilaiakhir
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"skoola/internal/access"
+	"skoola/internal/audit"
+	"skoola/internal/kktp"
+	"sort"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+var ErrValidation = errors.New("validation failed")
+
+// bobotFormatifBawaan dipakai selama tahun ajaran belum memiliki aturan nilai.
+const bobotFormatifBawaan = 50
+
+// Service mendefinisikan logika bisnis aturan dan perhitungan nilai akhir.
+type Service interface {
+	GetAturan(ctx context.Context, schemaName string, tahunAjaranID string) (*AturanNilai, error)
+	UpsertAturan(ctx context.Context, schemaName string, tahunAjaranID string, input UpsertAturanInput) (*AturanNilai, error)
+	DeleteAturan(ctx context.Context, schemaName string, tahunAjaranID string) error
+	GetNilaiAkhirMapel(ctx context.Context, schemaName string, actor access.Actor, pengajarKelasID string) (*NilaiAkhirMapel, error)
+	// HitungKelas menghitung nilai akhir semua mapel di kelas tanpa memeriksa akses;
+	// pemanggil (misalnya rapor) wajib memeriksa akses sendiri.
+	// Hasilnya map[pengajar_kelas_id]map[anggota_kelas_id]NilaiAkhirSiswa.
+	HitungKelas(ctx context.Context, schemaName string, kelasID string) (map[string]map[string]NilaiAkhirSiswa, error)
+}
+
+type service struct {
+	repo     Repository
+	kktp     kktp.Service
+	access   access.Service
+	audit    audit.Recorder
+	validate *validator.Validate
+}
+
+// NewService membuat instance baru dari service nilai akhir.
+func NewService(repo Repository, kktpService kktp.Service, accessService access.Service, auditLog audit.Recorder, validate *validator.Validate) Service {
+	return &service{repo: repo, kktp: kktpService, access: accessService, audit: auditLog, validate: validate}
+}
+
+func (s *service) GetAturan(ctx context.Context, schemaName string, tahunAjaranID string) (*AturanNilai, error) {
+	if _, err := uuid.Parse(tahunAjaranID); err != nil {
+		return nil, fmt.Errorf("%w: tahun_ajaran_id tidak valid", ErrValidation)
+	}
+	aturan, err := s.repo.GetAturan(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+	if aturan == nil {
+		return aturanBawaan(tahunAjaranID), nil
+	}
+	return aturan, nil
+}
+
+func (s *service) UpsertAturan(ctx context.Context, schemaName string, tahunAjaranID string, input UpsertAturanInput) (*AturanNilai, error) {
+	if _, err := uuid.Parse(tahunAjaranID); err != nil {
+		return nil, fmt.Errorf("%w: tahun_ajaran_id tidak valid", ErrValidation)
+	}
+	if err := s.validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+
+	total := input.BobotFormatif
+	seen := make(map[int]bool)
+	for _, b := range input.BobotJenisUjian {
+		if seen[b.JenisUjianID] {
+			return nil, fmt.Errorf("%w: jenis ujian %d disebutkan lebih dari sekali", ErrValidation, b.JenisUjianID)
+		}
+		seen[b.JenisUjianID] = true
+		total += b.Bobot
+	}
+	if math.Abs(total-100) > 0.001 {
+		return nil, fmt.Errorf("%w: jumlah bobot harus 100, saat ini %.2f", ErrValidation, total)
+	}
+
+	before, err := s.repo.GetAturan(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpsertAturan(ctx, schemaName, tahunAjaranID, input); err != nil {
+		return nil, err
+	}
+	after, err := s.repo.GetAturan(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := audit.Entry{Action: audit.ActionUpdate, Entity: "aturan_nilai", EntityID: tahunAjaranID, Before: before, After: after}
+	if before == nil {
+		entry = audit.Entry{Action: audit.ActionCreate, Entity: "aturan_nilai", EntityID: tahunAjaranID, After: after}
+	}
+	s.audit.Record(ctx, schemaName, entry)
+	return after, nil
+}
+
+// DeleteAturan menghapus aturan tahun ajaran sehingga perhitungan kembali memakai aturan bawaan.
+func (s *service) DeleteAturan(ctx context.Context, schemaName string, tahunAjaranID string) error {
+	if _, err := uuid.Parse(tahunAjaranID); err != nil {
+		return fmt.Errorf("%w: tahun_ajaran_id tidak valid", ErrValidation)
+	}
+	before, err := s.repo.GetAturan(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return err
+	}
+	if before == nil {
+		return sql.ErrNoRows
+	}
+	if err := s.repo.DeleteAturan(ctx, schemaName, tahunAjaranID); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionDelete, Entity: "aturan_nilai", EntityID: tahunAjaranID, Before: before})
+	return nil
+}
+
+func (s *service) GetNilaiAkhirMapel(ctx context.Context, schemaName string, actor access.Actor, pengajarKelasID string) (*NilaiAkhirMapel, error) {
+	kelasID, tahunAjaranID, err := s.repo.GetKelasPengajar(ctx, schemaName, pengajarKelasID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pengajar mapel dan wali kelas boleh melihat nilai akhir kelas ini.
+	target := access.Target{PengajarKelasIDs: []string{pengajarKelasID}, KelasIDs: []string{kelasID}}
+	if err := s.access.AuthorizeRead(ctx, schemaName, actor, target); err != nil {
+		return nil, err
+	}
+
+	aturan, err := s.GetAturan(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+	siswa, err := s.repo.GetSiswa(ctx, schemaName, kelasID)
+	if err != nil {
+		return nil, err
+	}
+	asesmenList, err := s.repo.GetAsesmen(ctx, schemaName, kelasID, pengajarKelasID)
+	if err != nil {
+		return nil, err
+	}
+	nilai, err := s.repo.GetNilai(ctx, schemaName, kelasID)
+	if err != nil {
+		return nil, err
+	}
+	ambang, err := s.kktp.GetAmbang(ctx, schemaName, pengajarKelasID)
+	if err != nil {
+		return nil, err
+	}
+
+	hasil := hitung(*aturan, siswa, asesmenList, nilai)
+	for i := range hasil {
+		hasil[i].Tuntas = kktp.Tuntas(hasil[i].NilaiAkhir, ambang.Mapel)
+	}
+	return &NilaiAkhirMapel{
+		PengajarKelasID: pengajarKelasID,
+		KelasID:         kelasID,
+		Aturan:          *aturan,
+		KKTP:            ambang.Mapel,
+		Siswa:           hasil,
+	}, nil
+}
+
+func (s *service) HitungKelas(ctx context.Context, schemaName string, kelasID string) (map[string]map[string]NilaiAkhirSiswa, error) {
+	tahunAjaranID, err := s.repo.GetTahunAjaranKelas(ctx, schemaName, kelasID)
+	if err != nil {
+		return nil, err
+	}
+	aturan, err := s.GetAturan(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+	siswa, err := s.repo.GetSiswa(ctx, schemaName, kelasID)
+	if err != nil {
+		return nil, err
+	}
+	pengajarIDs, err := s.repo.GetPengajarKelasIDs(ctx, schemaName, kelasID)
+	if err != nil {
+		return nil, err
+	}
+	asesmenList, err := s.repo.GetAsesmen(ctx, schemaName, kelasID, "")
+	if err != nil {
+		return nil, err
+	}
+	nilai, err := s.repo.GetNilai(ctx, schemaName, kelasID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Daftar mapel diambil dari pengajar kelas, bukan dari kolom nilai, agar mapel yang belum
+	// punya penilaian tetap dihitung: 0 pada kebijakan NOL dan terblokir pada BLOKIR.
+	perMapel := make(map[string][]asesmen, len(pengajarIDs))
+	for _, id := range pengajarIDs {
+		perMapel[id] = nil
+	}
+	for _, a := range asesmenList {
+		perMapel[a.PengajarKelasID] = append(perMapel[a.PengajarKelasID], a)
+	}
+
+	result := make(map[string]map[string]NilaiAkhirSiswa, len(perMapel))
+	for pkID, list := range perMapel {
+		hasil := hitung(*aturan, siswa, list, nilai)
+		result[pkID] = make(map[string]NilaiAkhirSiswa, len(hasil))
+		for _, h := range hasil {
+			result[pkID][h.AnggotaKelasID] = h
+		}
+	}
+	return result, nil
+}
+
+// aturanBawaan adalah aturan yang dipakai selama tahun ajaran belum diatur.
+func aturanBawaan(tahunAjaranID string) *AturanNilai {
+	return &AturanNilai{
+		TahunAjaranID:        tahunAjaranID,
+		BobotFormatif:        bobotFormatifBawaan,
+		KebijakanNilaiKosong: KebijakanAbaikan,
+		BobotJenisUjian:      []BobotJenisUjian{},
+	}
+}
+
+// komponen adalah satu komponen nilai akhir beserta kolom nilai yang dirata-rata.
+type komponen struct {
+	Kode  string
+	Nama  string
+	Bobot float64
+	Keys  []string
+}
+
+// susunKomponen menyusun komponen nilai satu mapel dari aturan dan kolom nilai yang ada.
+// Komponen berbobot 0 tidak ikut dihitung. Komponen berbobot yang belum punya kolom nilai
+// hanya dilewati (bobotnya dibagi ulang ke komponen lain) pada kebijakan ABAIKAN; pada
+// kebijakan NOL dan BLOKIR komponen tersebut tetap disertakan tanpa kolom.
+func susunKomponen(aturan AturanNilai, asesmenList []asesmen) []komponen {
+	keysByJenis := make(map[int][]string)
+	var formatifKeys []string
+	jenisInfo := make(map[int]asesmen)
+	for _, a := range asesmenList {
+		if a.JenisUjianID == 0 {
+			formatifKeys = append(formatifKeys, a.Key)
+			continue
+		}
+		keysByJenis[a.JenisUjianID] = append(keysByJenis[a.JenisUjianID], a.Key)
+		jenisInfo[a.JenisUjianID] = a
+	}
+
+	lewatiKosong := aturan.KebijakanNilaiKosong == KebijakanAbaikan
+	var list []komponen
+	if aturan.BobotFormatif > 0 && (len(formatifKeys) > 0 || !lewatiKosong) {
+		list = append(list, komponen{Kode: KodeFormatif, Nama: "Formatif", Bobot: aturan.BobotFormatif, Keys: formatifKeys})
+	}
+
+	bobotJenis := aturan.BobotJenisUjian
+	if aturan.ID == 0 && len(jenisInfo) > 0 {
+		// Aturan bawaan: sisa bobot dibagi rata ke jenis ujian yang dipakai.
+		bagian := (100 - aturan.BobotFormatif) / float64(len(jenisInfo))
+		bobotJenis = make([]BobotJenisUjian, 0, len(jenisInfo))
+		for id, a := range jenisInfo {
+			bobotJenis = append(bobotJenis, BobotJenisUjian{JenisUjianID: id, KodeUjian: a.KodeUjian, NamaUjian: a.NamaUjian, Bobot: bagian})
+		}
+		sort.Slice(bobotJenis, func(i, j int) bool { return bobotJenis[i].KodeUjian < bobotJenis[j].KodeUjian })
+	}
+	for _, b := range bobotJenis {
+		keys := keysByJenis[b.JenisUjianID]
+		if b.Bobot <= 0 || (len(keys) == 0 && lewatiKosong) {
+			continue
+		}
+		list = append(list, komponen{Kode: b.KodeUjian, Nama: b.NamaUjian, Bobot: b.Bobot, Keys: keys})
+	}
+	return list
+}
+
+// hitung menerapkan aturan nilai ke setiap siswa untuk satu mapel.
+func hitung(aturan AturanNilai, siswa []siswaKelas, asesmenList []asesmen, nilai map[string]map[string]float64) []NilaiAkhirSiswa {
+	komponenList := susunKomponen(aturan, asesmenList)
+
+	result := make([]NilaiAkhirSiswa, 0, len(siswa))
+	for _, sw := range siswa {
+		hasil := NilaiAkhirSiswa{
+			AnggotaKelasID: sw.AnggotaKelasID,
+			NamaSiswa:      sw.NamaSiswa,
+			NIS:            sw.NIS,
+			Komponen:       make([]KomponenNilai, 0, len(komponenList)),
+			Lengkap:        true,
+		}
+
+		var total, totalBobot float64
+		for _, k := range komponenList {
+			kn := KomponenNilai{Kode: k.Kode, Nama: k.Nama, Bobot: k.Bobot}
+			var sum float64
+			var terisi int
+			for _, key := range k.Keys {
+				if v, ok := nilai[sw.AnggotaKelasID][key]; ok {
+					sum += v
+					terisi++
+				}
+			}
+			kn.JumlahKosong = len(k.Keys) - terisi
+			if kn.JumlahKosong > 0 || len(k.Keys) == 0 {
+				hasil.Lengkap = false
+			}
+
+			switch {
+			case aturan.KebijakanNilaiKosong == KebijakanNol && len(k.Keys) == 0:
+				kn.Nilai = round2(0)
+			case aturan.KebijakanNilaiKosong == KebijakanNol:
+				avg := sum / float64(len(k.Keys))
+				kn.Nilai = round2(avg)
+			case terisi > 0:
+				kn.Nilai = round2(sum / float64(terisi))
+			}
+			if kn.Nilai != nil {
+				total += *kn.Nilai * k.Bobot
+				totalBobot += k.Bobot
+			}
+			hasil.Komponen = append(hasil.Komponen, kn)
+		}
+
+		blokir := aturan.KebijakanNilaiKosong == KebijakanBlokir && !hasil.Lengkap
+		if totalBobot > 0 && !blokir {
+			hasil.NilaiAkhir = round2(total / totalBobot)
+		}
+		result = append(result, hasil)
+	}
+	return result
+}
+
+func round2(v float64) *float64 {
+	r := math.Round(v*100) / 100
+	return &r
+}
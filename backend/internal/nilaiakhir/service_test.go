@@ -0,0 +1,321 @@
+// file: backend/internal/nilaiakhir/service_test.go
+package nilaiakhir
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestSusunKomponen(t *testing.T) {
+	tp := []asesmen{{Key: "tp1"}, {Key: "tp2"}}
+	uts := asesmen{Key: "uts1", JenisUjianID: 1, KodeUjian: "UTS", NamaUjian: "Ujian Tengah Semester"}
+	uas := asesmen{Key: "uas1", JenisUjianID: 2, KodeUjian: "UAS", NamaUjian: "Ujian Akhir Semester"}
+	aturan := func(kebijakan string) AturanNilai {
+		return AturanNilai{
+			ID:                   1,
+			BobotFormatif:        40,
+			KebijakanNilaiKosong: kebijakan,
+			BobotJenisUjian: []BobotJenisUjian{
+				{JenisUjianID: 1, KodeUjian: "UTS", NamaUjian: "Ujian Tengah Semester", Bobot: 60},
+				{JenisUjianID: 2, KodeUjian: "UAS", NamaUjian: "Ujian Akhir Semester", Bobot: 0},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		aturan  AturanNilai
+		asesmen []asesmen
+		want    []komponen
+	}{
+		{
+			name:    "ABAIKAN melewati komponen berbobot tanpa kolom",
+			aturan:  aturan(KebijakanAbaikan),
+			asesmen: tp,
+			want:    []komponen{{Kode: KodeFormatif, Bobot: 40, Keys: []string{"tp1", "tp2"}}},
+		},
+		{
+			name:    "NOL tetap menyertakan komponen berbobot tanpa kolom",
+			aturan:  aturan(KebijakanNol),
+			asesmen: tp,
+			want: []komponen{
+				{Kode: KodeFormatif, Bobot: 40, Keys: []string{"tp1", "tp2"}},
+				{Kode: "UTS", Bobot: 60},
+			},
+		},
+		{
+			name:    "BLOKIR tetap menyertakan formatif tanpa TP",
+			aturan:  aturan(KebijakanBlokir),
+			asesmen: []asesmen{uts},
+			want: []komponen{
+				{Kode: KodeFormatif, Bobot: 40},
+				{Kode: "UTS", Bobot: 60, Keys: []string{"uts1"}},
+			},
+		},
+		{
+			name:    "komponen berbobot 0 tidak dihitung walau punya kolom",
+			aturan:  aturan(KebijakanNol),
+			asesmen: append(append([]asesmen{}, tp...), uts, uas),
+			want: []komponen{
+				{Kode: KodeFormatif, Bobot: 40, Keys: []string{"tp1", "tp2"}},
+				{Kode: "UTS", Bobot: 60, Keys: []string{"uts1"}},
+			},
+		},
+		{
+			name:    "aturan bawaan membagi rata sisa bobot ke jenis ujian yang dipakai",
+			aturan:  *aturanBawaan("ta"),
+			asesmen: append(append([]asesmen{}, tp...), uts, uas),
+			want: []komponen{
+				{Kode: KodeFormatif, Bobot: 50, Keys: []string{"tp1", "tp2"}},
+				{Kode: "UAS", Bobot: 25, Keys: []string{"uas1"}},
+				{Kode: "UTS", Bobot: 25, Keys: []string{"uts1"}},
+			},
+		},
+		{
+			name:    "aturan bawaan tanpa kolom nilai",
+			aturan:  *aturanBawaan("ta"),
+			asesmen: nil,
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := susunKomponen(tt.aturan, tt.asesmen)
+			if len(got) != len(tt.want) {
+				t.Fatalf("jumlah komponen = %d, ingin %d (%+v)", len(got), len(tt.want), got)
+			}
+			for i, w := range tt.want {
+				g := got[i]
+				if g.Kode != w.Kode || g.Bobot != w.Bobot || !samaKeys(g.Keys, w.Keys) {
+					t.Errorf("komponen[%d] = {%s %v %v}, ingin {%s %v %v}", i, g.Kode, g.Bobot, g.Keys, w.Kode, w.Bobot, w.Keys)
+				}
+			}
+		})
+	}
+}
+
+func TestHitung(t *testing.T) {
+	aturan := func(kebijakan string) AturanNilai {
+		return AturanNilai{
+			ID:                   1,
+			BobotFormatif:        40,
+			KebijakanNilaiKosong: kebijakan,
+			BobotJenisUjian:      []BobotJenisUjian{{JenisUjianID: 1, KodeUjian: "UTS", NamaUjian: "UTS", Bobot: 60}},
+		}
+	}
+	siswa := []siswaKelas{{AnggotaKelasID: "ak1", NamaSiswa: "Budi"}}
+	hanyaTP := []asesmen{{Key: "tp1"}, {Key: "tp2"}}
+	lengkap := append(append([]asesmen{}, hanyaTP...), asesmen{Key: "uts1", JenisUjianID: 1, KodeUjian: "UTS", NamaUjian: "UTS"})
+
+	tests := []struct {
+		name        string
+		aturan      AturanNilai
+		asesmen     []asesmen
+		nilai       map[string]float64
+		wantAkhir   *float64
+		wantLengkap bool
+		wantKomp    []*float64
+	}{
+		{
+			name:        "semua nilai terisi",
+			aturan:      aturan(KebijakanBlokir),
+			asesmen:     lengkap,
+			nilai:       map[string]float64{"tp1": 80, "tp2": 90, "uts1": 70},
+			wantAkhir:   f(76),
+			wantLengkap: true,
+			wantKomp:    []*float64{f(85), f(70)},
+		},
+		{
+			name:        "ABAIKAN: komponen kosong dilewati dan bobot dibagi ulang",
+			aturan:      aturan(KebijakanAbaikan),
+			asesmen:     hanyaTP,
+			nilai:       map[string]float64{"tp1": 80, "tp2": 90},
+			wantAkhir:   f(85),
+			wantLengkap: true,
+			wantKomp:    []*float64{f(85)},
+		},
+		{
+			name:        "NOL: komponen tanpa kolom dihitung 0",
+			aturan:      aturan(KebijakanNol),
+			asesmen:     hanyaTP,
+			nilai:       map[string]float64{"tp1": 80, "tp2": 90},
+			wantAkhir:   f(34),
+			wantLengkap: false,
+			wantKomp:    []*float64{f(85), f(0)},
+		},
+		{
+			name:        "BLOKIR: komponen tanpa kolom membuat nilai belum lengkap",
+			aturan:      aturan(KebijakanBlokir),
+			asesmen:     hanyaTP,
+			nilai:       map[string]float64{"tp1": 80, "tp2": 90},
+			wantAkhir:   nil,
+			wantLengkap: false,
+			wantKomp:    []*float64{f(85), nil},
+		},
+		{
+			name:        "ABAIKAN: nilai kosong dirata-rata dari yang terisi",
+			aturan:      aturan(KebijakanAbaikan),
+			asesmen:     lengkap,
+			nilai:       map[string]float64{"tp1": 80, "uts1": 70},
+			wantAkhir:   f(74),
+			wantLengkap: false,
+			wantKomp:    []*float64{f(80), f(70)},
+		},
+		{
+			name:        "NOL: nilai kosong dihitung 0",
+			aturan:      aturan(KebijakanNol),
+			asesmen:     lengkap,
+			nilai:       map[string]float64{"tp1": 80, "uts1": 70},
+			wantAkhir:   f(58),
+			wantLengkap: false,
+			wantKomp:    []*float64{f(40), f(70)},
+		},
+		{
+			name:        "ABAIKAN: belum ada nilai sama sekali",
+			aturan:      aturan(KebijakanAbaikan),
+			asesmen:     lengkap,
+			nilai:       nil,
+			wantAkhir:   nil,
+			wantLengkap: false,
+			wantKomp:    []*float64{nil, nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nilai := map[string]map[string]float64{"ak1": tt.nilai}
+			got := hitung(tt.aturan, siswa, tt.asesmen, nilai)
+			if len(got) != 1 {
+				t.Fatalf("jumlah siswa = %d, ingin 1", len(got))
+			}
+			h := got[0]
+			if !samaNilai(h.NilaiAkhir, tt.wantAkhir) {
+				t.Errorf("NilaiAkhir = %s, ingin %s", teks(h.NilaiAkhir), teks(tt.wantAkhir))
+			}
+			if h.Lengkap != tt.wantLengkap {
+				t.Errorf("Lengkap = %v, ingin %v", h.Lengkap, tt.wantLengkap)
+			}
+			if len(h.Komponen) != len(tt.wantKomp) {
+				t.Fatalf("jumlah komponen = %d, ingin %d", len(h.Komponen), len(tt.wantKomp))
+			}
+			for i, w := range tt.wantKomp {
+				if !samaNilai(h.Komponen[i].Nilai, w) {
+					t.Errorf("komponen %s = %s, ingin %s", h.Komponen[i].Kode, teks(h.Komponen[i].Nilai), teks(w))
+				}
+			}
+		})
+	}
+}
+
+// stubRepository hanya mengisi method yang dipakai HitungKelas.
+type stubRepository struct {
+	Repository
+	aturan   *AturanNilai
+	pengajar []string
+	asesmen  []asesmen
+	nilai    map[string]map[string]float64
+}
+
+func (r *stubRepository) GetTahunAjaranKelas(ctx context.Context, schemaName string, kelasID string) (string, error) {
+	return "6f1c2d3e-4a5b-4c6d-8e7f-9a0b1c2d3e4f", nil
+}
+
+func (r *stubRepository) GetAturan(ctx context.Context, schemaName string, tahunAjaranID string) (*AturanNilai, error) {
+	return r.aturan, nil
+}
+
+func (r *stubRepository) GetSiswa(ctx context.Context, schemaName string, kelasID string) ([]siswaKelas, error) {
+	return []siswaKelas{{AnggotaKelasID: "ak1", NamaSiswa: "Budi"}}, nil
+}
+
+func (r *stubRepository) GetPengajarKelasIDs(ctx context.Context, schemaName string, kelasID string) ([]string, error) {
+	return r.pengajar, nil
+}
+
+func (r *stubRepository) GetAsesmen(ctx context.Context, schemaName string, kelasID string, pengajarKelasID string) ([]asesmen, error) {
+	return r.asesmen, nil
+}
+
+func (r *stubRepository) GetNilai(ctx context.Context, schemaName string, kelasID string) (map[string]map[string]float64, error) {
+	return r.nilai, nil
+}
+
+func TestHitungKelasMapelTanpaPenilaian(t *testing.T) {
+	aturan := func(kebijakan string) *AturanNilai {
+		return &AturanNilai{ID: 1, BobotFormatif: 100, KebijakanNilaiKosong: kebijakan}
+	}
+
+	tests := []struct {
+		name        string
+		aturan      *AturanNilai
+		wantAkhir   *float64
+		wantLengkap bool
+	}{
+		{name: "NOL memberi nilai 0", aturan: aturan(KebijakanNol), wantAkhir: f(0)},
+		{name: "BLOKIR memblokir nilai akhir", aturan: aturan(KebijakanBlokir)},
+		{name: "ABAIKAN tanpa nilai akhir", aturan: aturan(KebijakanAbaikan), wantLengkap: true},
+		{name: "aturan bawaan mengikuti ABAIKAN", wantLengkap: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubRepository{
+				aturan:   tt.aturan,
+				pengajar: []string{"pk-mtk", "pk-seni"},
+				asesmen:  []asesmen{{PengajarKelasID: "pk-mtk", Key: "tp1"}},
+				nilai:    map[string]map[string]float64{"ak1": {"tp1": 80}},
+			}
+			s := &service{repo: repo}
+			hasil, err := s.HitungKelas(context.Background(), "tenant", "kelas")
+			if err != nil {
+				t.Fatalf("HitungKelas error: %v", err)
+			}
+			if len(hasil) != 2 {
+				t.Fatalf("jumlah mapel = %d, ingin 2", len(hasil))
+			}
+			if n := hasil["pk-mtk"]["ak1"]; !samaNilai(n.NilaiAkhir, f(80)) || !n.Lengkap {
+				t.Errorf("mapel berpenilaian = %s (lengkap %v), ingin 80 (lengkap)", teks(n.NilaiAkhir), n.Lengkap)
+			}
+			n, ok := hasil["pk-seni"]["ak1"]
+			if !ok {
+				t.Fatalf("mapel tanpa penilaian tidak ada di hasil")
+			}
+			if !samaNilai(n.NilaiAkhir, tt.wantAkhir) {
+				t.Errorf("nilai akhir = %s, ingin %s", teks(n.NilaiAkhir), teks(tt.wantAkhir))
+			}
+			if n.Lengkap != tt.wantLengkap {
+				t.Errorf("lengkap = %v, ingin %v", n.Lengkap, tt.wantLengkap)
+			}
+		})
+	}
+}
+
+func f(v float64) *float64 { return &v }
+
+func samaNilai(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func teks(v *float64) string {
+	if v == nil {
+		return "nil"
+	}
+	return fmt.Sprintf("%v", *v)
+}
+
+func samaKeys(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
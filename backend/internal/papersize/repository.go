@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"skoola/pkg/database"
 	"time"
 
 	"github.com/google/uuid"
@@ -26,16 +27,13 @@ func NewRepository(db *sql.DB) Repository {
 	return &postgresRepository{db: db}
 }
 
-func (r *postgresRepository) setSchema(ctx context.Context, schemaName string) error {
-	_, err := r.db.ExecContext(ctx, fmt.Sprintf("SET search_path TO %q", schemaName))
-	return err
-}
-
 // Implementasi CRUD
 func (r *postgresRepository) Create(ctx context.Context, schemaName string, input UpsertPaperSizeInput) (*PaperSize, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 	newID := uuid.New().String()
 	query := `
         INSERT INTO paper_size (id, nama_kertas, satuan, panjang, lebar, margin_atas, margin_bawah, margin_kiri, margin_kanan)
@@ -43,21 +41,26 @@ func (r *postgresRepository) Create(ctx context.Context, schemaName string, inpu
         RETURNING created_at, updated_at
     `
 	now := time.Now()
-	err := r.db.QueryRowContext(ctx, query, newID, input.NamaKertas, input.Satuan, input.Panjang, input.Lebar, input.MarginAtas, input.MarginBawah, input.MarginKiri, input.MarginKanan).Scan(&now, &now)
+	err = tx.QueryRowContext(ctx, query, newID, input.NamaKertas, input.Satuan, input.Panjang, input.Lebar, input.MarginAtas, input.MarginBawah, input.MarginKiri, input.MarginKanan).Scan(&now, &now)
 
 	if err != nil {
 		return nil, fmt.Errorf("gagal memuat data paper size setelah dibuat: %w", err)
 	}
 
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 	return r.GetByID(ctx, schemaName, newID)
 }
 
 func (r *postgresRepository) GetAll(ctx context.Context, schemaName string) ([]PaperSize, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 	query := `SELECT id, nama_kertas, satuan, panjang, lebar, margin_atas, margin_bawah, margin_kiri, margin_kanan, created_at, updated_at FROM paper_size ORDER BY nama_kertas ASC`
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := tx.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("gagal query get all paper size: %w", err)
 	}
@@ -71,32 +74,39 @@ func (r *postgresRepository) GetAll(ctx context.Context, schemaName string) ([]P
 		}
 		list = append(list, p)
 	}
-	return list, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
 }
 
 func (r *postgresRepository) GetByID(ctx context.Context, schemaName string, id string) (*PaperSize, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `SELECT id, nama_kertas, satuan, panjang, lebar, margin_atas, margin_bawah, margin_kiri, margin_kanan, created_at, updated_at FROM paper_size WHERE id = $1`
-	row := r.db.QueryRowContext(ctx, query, id)
+	row := tx.QueryRowContext(ctx, query, id)
 
 	var p PaperSize
-	err := row.Scan(&p.ID, &p.NamaKertas, &p.Satuan, &p.Panjang, &p.Lebar, &p.MarginAtas, &p.MarginBawah, &p.MarginKiri, &p.MarginKanan, &p.CreatedAt, &p.UpdatedAt)
+	err = row.Scan(&p.ID, &p.NamaKertas, &p.Satuan, &p.Panjang, &p.Lebar, &p.MarginAtas, &p.MarginBawah, &p.MarginKiri, &p.MarginKanan, &p.CreatedAt, &p.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, fmt.Errorf("gagal memindai data paper size by id: %w", err)
 	}
-	return &p, nil
+	return &p, tx.Commit()
 }
 
 func (r *postgresRepository) Update(ctx context.Context, schemaName string, id string, input UpsertPaperSizeInput) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
 	query := `
         UPDATE paper_size SET 
@@ -105,7 +115,7 @@ func (r *postgresRepository) Update(ctx context.Context, schemaName string, id s
             updated_at = NOW() 
         WHERE id = $9
     `
-	result, err := r.db.ExecContext(ctx, query, input.NamaKertas, input.Satuan, input.Panjang, input.Lebar, input.MarginAtas, input.MarginBawah, input.MarginKiri, input.MarginKanan, id)
+	result, err := tx.ExecContext(ctx, query, input.NamaKertas, input.Satuan, input.Panjang, input.Lebar, input.MarginAtas, input.MarginBawah, input.MarginKiri, input.MarginKanan, id)
 	if err != nil {
 		return fmt.Errorf("gagal mengeksekusi query update: %w", err)
 	}
@@ -117,16 +127,18 @@ func (r *postgresRepository) Update(ctx context.Context, schemaName string, id s
 	if rowsAffected == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+	return tx.Commit()
 }
 
 func (r *postgresRepository) Delete(ctx context.Context, schemaName string, id string) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
 	query := `DELETE FROM paper_size WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := tx.ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("gagal mengeksekusi query delete: %w", err)
 	}
@@ -138,5 +150,5 @@ func (r *postgresRepository) Delete(ctx context.Context, schemaName string, id s
 	if rowsAffected == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+	return tx.Commit()
 }
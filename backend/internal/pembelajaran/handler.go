@@ -3,7 +3,9 @@ package pembelajaran
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"skoola/internal/access"
 	"skoola/internal/middleware"
 	"strconv"
 
@@ -49,8 +51,12 @@ func (h *Handler) CreateBulkUjian(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := h.service.CreateBulkUjian(r.Context(), schemaName, input)
+	result, err := h.service.CreateBulkUjian(r.Context(), schemaName, access.ActorFromContext(r.Context()), input)
 	if err != nil {
+		if errors.Is(err, access.ErrForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		http.Error(w, "Gagal membuat ujian massal: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -68,7 +74,11 @@ func (h *Handler) UpdateRencanaUrutan(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
 		return
 	}
-	if err := h.service.UpdateRencanaUrutan(r.Context(), schemaName, input); err != nil {
+	if err := h.service.UpdateRencanaUrutan(r.Context(), schemaName, access.ActorFromContext(r.Context()), input); err != nil {
+		if errors.Is(err, access.ErrForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		http.Error(w, "Gagal memperbarui urutan: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -82,7 +92,11 @@ func (h *Handler) UpdateUrutanTujuan(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
 		return
 	}
-	if err := h.service.UpdateUrutanTujuan(r.Context(), schemaName, input); err != nil {
+	if err := h.service.UpdateUrutanTujuan(r.Context(), schemaName, access.ActorFromContext(r.Context()), input); err != nil {
+		if errors.Is(err, access.ErrForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		http.Error(w, "Gagal memperbarui urutan tujuan: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -98,8 +112,12 @@ func (h *Handler) CreateUjian(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := h.service.CreateUjian(r.Context(), schemaName, input)
+	result, err := h.service.CreateUjian(r.Context(), schemaName, access.ActorFromContext(r.Context()), input)
 	if err != nil {
+		if errors.Is(err, access.ErrForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		http.Error(w, "Gagal membuat ujian: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -118,7 +136,11 @@ func (h *Handler) UpdateUjian(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.UpdateUjian(r.Context(), schemaName, ujianID, input); err != nil {
+	if err := h.service.UpdateUjian(r.Context(), schemaName, access.ActorFromContext(r.Context()), ujianID, input); err != nil {
+		if errors.Is(err, access.ErrForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		http.Error(w, "Gagal memperbarui ujian: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -129,7 +151,11 @@ func (h *Handler) DeleteUjian(w http.ResponseWriter, r *http.Request) {
 	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
 	ujianID, _ := strconv.Atoi(chi.URLParam(r, "id"))
 
-	if err := h.service.DeleteUjian(r.Context(), schemaName, ujianID); err != nil {
+	if err := h.service.DeleteUjian(r.Context(), schemaName, access.ActorFromContext(r.Context()), ujianID); err != nil {
+		if errors.Is(err, access.ErrForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		http.Error(w, "Gagal menghapus ujian: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -145,8 +171,12 @@ func (h *Handler) CreateMateri(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := h.service.CreateMateri(r.Context(), schemaName, input)
+	result, err := h.service.CreateMateri(r.Context(), schemaName, access.ActorFromContext(r.Context()), input)
 	if err != nil {
+		if errors.Is(err, access.ErrForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		http.Error(w, "Gagal membuat materi: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -160,8 +190,12 @@ func (h *Handler) GetAllRencanaPembelajaran(w http.ResponseWriter, r *http.Reque
 	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
 	pengajarKelasID := chi.URLParam(r, "pengajarKelasID")
 
-	result, err := h.service.GetAllRencanaPembelajaran(r.Context(), schemaName, pengajarKelasID)
+	result, err := h.service.GetAllRencanaPembelajaran(r.Context(), schemaName, access.ActorFromContext(r.Context()), pengajarKelasID)
 	if err != nil {
+		if errors.Is(err, access.ErrForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		http.Error(w, "Gagal mengambil data rencana pembelajaran: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -179,7 +213,11 @@ func (h *Handler) UpdateMateri(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.UpdateMateri(r.Context(), schemaName, materiID, input); err != nil {
+	if err := h.service.UpdateMateri(r.Context(), schemaName, access.ActorFromContext(r.Context()), materiID, input); err != nil {
+		if errors.Is(err, access.ErrForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		http.Error(w, "Gagal memperbarui materi: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -190,7 +228,11 @@ func (h *Handler) DeleteMateri(w http.ResponseWriter, r *http.Request) {
 	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
 	materiID, _ := strconv.Atoi(chi.URLParam(r, "materiID"))
 
-	if err := h.service.DeleteMateri(r.Context(), schemaName, materiID); err != nil {
+	if err := h.service.DeleteMateri(r.Context(), schemaName, access.ActorFromContext(r.Context()), materiID); err != nil {
+		if errors.Is(err, access.ErrForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		http.Error(w, "Gagal menghapus materi: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -205,8 +247,12 @@ func (h *Handler) CreateTujuan(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := h.service.CreateTujuan(r.Context(), schemaName, input)
+	result, err := h.service.CreateTujuan(r.Context(), schemaName, access.ActorFromContext(r.Context()), input)
 	if err != nil {
+		if errors.Is(err, access.ErrForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		http.Error(w, "Gagal membuat tujuan pembelajaran: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -225,7 +271,11 @@ func (h *Handler) UpdateTujuan(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.UpdateTujuan(r.Context(), schemaName, tujuanID, input); err != nil {
+	if err := h.service.UpdateTujuan(r.Context(), schemaName, access.ActorFromContext(r.Context()), tujuanID, input); err != nil {
+		if errors.Is(err, access.ErrForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		http.Error(w, "Gagal memperbarui tujuan pembelajaran: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -236,7 +286,11 @@ func (h *Handler) DeleteTujuan(w http.ResponseWriter, r *http.Request) {
 	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
 	tujuanID, _ := strconv.Atoi(chi.URLParam(r, "tujuanID"))
 
-	if err := h.service.DeleteTujuan(r.Context(), schemaName, tujuanID); err != nil {
+	if err := h.service.DeleteTujuan(r.Context(), schemaName, access.ActorFromContext(r.Context()), tujuanID); err != nil {
+		if errors.Is(err, access.ErrForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		http.Error(w, "Gagal menghapus tujuan pembelajaran: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -6,6 +6,7 @@ import (
 	"database/sql"
 	"fmt"
 	"skoola/internal/penilaiansumatif"
+	"skoola/pkg/database"
 
 	"github.com/lib/pq"
 )
@@ -14,6 +15,9 @@ import (
 type Repository interface {
 	// Rencana Pembelajaran (Gabungan)
 	GetAllRencanaPembelajaran(ctx context.Context, schemaName string, pengajarKelasID string) ([]RencanaPembelajaranItem, error)
+	// GetAllRencanaPembelajaranTx sama dengan GetAllRencanaPembelajaran, tetapi membaca di
+	// dalam transaksi tenant milik pemanggil.
+	GetAllRencanaPembelajaranTx(ctx context.Context, tx *sql.Tx, pengajarKelasID string) ([]RencanaPembelajaranItem, error)
 	UpdateRencanaUrutan(ctx context.Context, schemaName string, orderedItems []RencanaUrutanItem) error
 
 	// Materi
@@ -46,22 +50,24 @@ func NewRepository(db *sql.DB) Repository {
 	return &postgresRepository{db: db}
 }
 
-func (r *postgresRepository) setSchema(ctx context.Context, schemaName string) error {
-	_, err := r.db.ExecContext(ctx, fmt.Sprintf("SET search_path TO %q", schemaName))
-	if err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
-	}
-	return nil
-}
-
 // =================================================================================
 // PERBAIKAN UTAMA DI FUNGSI INI
 // =================================================================================
 func (r *postgresRepository) GetAllRencanaPembelajaran(ctx context.Context, schemaName string, pengajarKelasID string) ([]RencanaPembelajaranItem, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	items, err := r.GetAllRencanaPembelajaranTx(ctx, tx, pengajarKelasID)
+	if err != nil {
 		return nil, err
 	}
+	return items, tx.Commit()
+}
 
+func (r *postgresRepository) GetAllRencanaPembelajaranTx(ctx context.Context, tx *sql.Tx, pengajarKelasID string) ([]RencanaPembelajaranItem, error) {
 	// FIXED QUERY: Mengubah query Ujian untuk JOIN dengan ujian_master dan mengambil nama dari sana
 	query := `
         SELECT 'materi' as type, id, pengajar_kelas_id, nama_materi as nama, deskripsi, urutan 
@@ -75,7 +81,7 @@ func (r *postgresRepository) GetAllRencanaPembelajaran(ctx context.Context, sche
         ORDER BY urutan ASC, type DESC
     `
 
-	rows, err := r.db.QueryContext(ctx, query, pengajarKelasID)
+	rows, err := tx.QueryContext(ctx, query, pengajarKelasID)
 	if err != nil {
 		return nil, fmt.Errorf("gagal query gabungan materi dan ujian: %w", err)
 	}
@@ -118,7 +124,7 @@ func (r *postgresRepository) GetAllRencanaPembelajaran(ctx context.Context, sche
             WHERE materi_pembelajaran_id = ANY($1)
             ORDER BY urutan ASC, created_at ASC
         `
-		tpRows, err := r.db.QueryContext(ctx, tpQuery, pq.Array(materiIDs))
+		tpRows, err := tx.QueryContext(ctx, tpQuery, pq.Array(materiIDs))
 		if err != nil {
 			return nil, fmt.Errorf("gagal query tujuan pembelajaran: %w", err)
 		}
@@ -147,7 +153,7 @@ func (r *postgresRepository) GetAllRencanaPembelajaran(ctx context.Context, sche
             WHERE ps.tujuan_pembelajaran_id = ANY($1) OR ps.ujian_id = ANY($2)
             ORDER BY ps.tanggal_pelaksanaan ASC, ps.created_at ASC
         `
-		penilaianRows, err := r.db.QueryContext(ctx, penilaianSumatifQuery, pq.Array(tpIDs), pq.Array(ujianIDs))
+		penilaianRows, err := tx.QueryContext(ctx, penilaianSumatifQuery, pq.Array(tpIDs), pq.Array(ujianIDs))
 		if err != nil {
 			return nil, fmt.Errorf("gagal query penilaian sumatif: %w", err)
 		}
@@ -186,13 +192,9 @@ func (r *postgresRepository) GetAllRencanaPembelajaran(ctx context.Context, sche
 // PERBAIKAN KEDUA DI FUNGSI INI
 // =================================================================================
 func (r *postgresRepository) CreateMateri(ctx context.Context, schemaName string, input UpsertMateriInput) (*MateriPembelajaran, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
-		return nil, err
-	}
-
-	tx, err := r.db.BeginTx(ctx, nil)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
 	if err != nil {
-		return nil, fmt.Errorf("gagal memulai transaksi: %w", err)
+		return nil, err
 	}
 	defer tx.Rollback()
 
@@ -239,9 +241,11 @@ func (r *postgresRepository) CreateMateri(ctx context.Context, schemaName string
 
 // --- Sisa file (tidak ada perubahan signifikan, hanya menyalin untuk kelengkapan) ---
 func (r *postgresRepository) GetAllUjianMonitoringByTahunAjaran(ctx context.Context, schemaName string, tahunAjaranID string) ([]UjianMonitoring, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 	query := `
         SELECT
             MIN(u.id) AS id, 
@@ -256,7 +260,7 @@ func (r *postgresRepository) GetAllUjianMonitoringByTahunAjaran(ctx context.Cont
         GROUP BY um.nama_paket_ujian
         ORDER BY MIN(u.created_at) DESC
     `
-	rows, err := r.db.QueryContext(ctx, query, tahunAjaranID)
+	rows, err := tx.QueryContext(ctx, query, tahunAjaranID)
 	if err != nil {
 		return nil, fmt.Errorf("gagal query monitoring ujian: %w", err)
 	}
@@ -272,21 +276,26 @@ func (r *postgresRepository) GetAllUjianMonitoringByTahunAjaran(ctx context.Cont
 		um.ID = fmt.Sprintf("%d", rawID)
 		list = append(list, um)
 	}
-	return list, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
 }
 
 func (r *postgresRepository) GetMateriByID(ctx context.Context, schemaName string, id int) (*MateriPembelajaran, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 	query := `
         SELECT id, pengajar_kelas_id, nama_materi, deskripsi, urutan, created_at, updated_at
         FROM materi_pembelajaran WHERE id = $1
     `
 	var m MateriPembelajaran
 	var deskripsi sql.NullString
-	row := r.db.QueryRowContext(ctx, query, id)
-	err := row.Scan(&m.ID, &m.PengajarKelasID, &m.NamaMateri, &deskripsi, &m.Urutan, &m.CreatedAt, &m.UpdatedAt)
+	row := tx.QueryRowContext(ctx, query, id)
+	err = row.Scan(&m.ID, &m.PengajarKelasID, &m.NamaMateri, &deskripsi, &m.Urutan, &m.CreatedAt, &m.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -297,16 +306,13 @@ func (r *postgresRepository) GetMateriByID(ctx context.Context, schemaName strin
 		m.Deskripsi = &deskripsi.String
 	}
 	m.TujuanPembelajaran = []TujuanPembelajaran{}
-	return &m, nil
+	return &m, tx.Commit()
 }
 
 func (r *postgresRepository) CreateBulkUjian(ctx context.Context, schemaName string, input CreateBulkUjianInput) (*BulkUjianResult, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
-		return nil, err
-	}
-	tx, err := r.db.BeginTx(ctx, nil)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
 	if err != nil {
-		return nil, fmt.Errorf("gagal memulai transaksi: %w", err)
+		return nil, err
 	}
 	defer tx.Rollback()
 	urutanQuery := `
@@ -340,12 +346,9 @@ func (r *postgresRepository) CreateBulkUjian(ctx context.Context, schemaName str
 }
 
 func (r *postgresRepository) UpdateRencanaUrutan(ctx context.Context, schemaName string, orderedItems []RencanaUrutanItem) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
-		return err
-	}
-	tx, err := r.db.BeginTx(ctx, nil)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
 	if err != nil {
-		return fmt.Errorf("gagal memulai transaksi: %w", err)
+		return err
 	}
 	defer tx.Rollback()
 	materiStmt, err := tx.PrepareContext(ctx, `UPDATE materi_pembelajaran SET urutan = $1, updated_at = NOW() WHERE id = $2`)
@@ -375,12 +378,9 @@ func (r *postgresRepository) UpdateRencanaUrutan(ctx context.Context, schemaName
 }
 
 func (r *postgresRepository) UpdateUrutanTujuan(ctx context.Context, schemaName string, orderedIDs []int) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
-		return err
-	}
-	tx, err := r.db.BeginTx(ctx, nil)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
 	if err != nil {
-		return fmt.Errorf("gagal memulai transaksi: %w", err)
+		return err
 	}
 	defer tx.Rollback()
 	query := `
@@ -400,32 +400,39 @@ func (r *postgresRepository) UpdateUrutanTujuan(ctx context.Context, schemaName
 }
 
 func (r *postgresRepository) UpdateMateri(ctx context.Context, schemaName string, id int, input UpsertMateriInput) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 	query := `
         UPDATE materi_pembelajaran SET nama_materi = $1, deskripsi = $2, updated_at = NOW()
         WHERE id = $3
     `
-	_, err := r.db.ExecContext(ctx, query, input.NamaMateri, sql.NullString{String: input.Deskripsi, Valid: input.Deskripsi != ""}, id)
-	return err
+	_, err = tx.ExecContext(ctx, query, input.NamaMateri, sql.NullString{String: input.Deskripsi, Valid: input.Deskripsi != ""}, id)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (r *postgresRepository) DeleteMateri(ctx context.Context, schemaName string, id int) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return err
 	}
-	_, err := r.db.ExecContext(ctx, "DELETE FROM materi_pembelajaran WHERE id = $1", id)
-	return err
+	defer tx.Rollback()
+	_, err = tx.ExecContext(ctx, "DELETE FROM materi_pembelajaran WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (r *postgresRepository) CreateTujuan(ctx context.Context, schemaName string, input UpsertTujuanInput) (*TujuanPembelajaran, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
-		return nil, err
-	}
-	tx, err := r.db.BeginTx(ctx, nil)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
 	if err != nil {
-		return nil, fmt.Errorf("gagal memulai transaksi: %w", err)
+		return nil, err
 	}
 	defer tx.Rollback()
 	var maxUrutan sql.NullInt64
@@ -454,32 +461,39 @@ func (r *postgresRepository) CreateTujuan(ctx context.Context, schemaName string
 }
 
 func (r *postgresRepository) UpdateTujuan(ctx context.Context, schemaName string, id int, input UpsertTujuanInput) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 	query := `
         UPDATE tujuan_pembelajaran SET deskripsi_tujuan = $1, updated_at = NOW()
         WHERE id = $2
     `
-	_, err := r.db.ExecContext(ctx, query, input.DeskripsiTujuan, id)
-	return err
+	_, err = tx.ExecContext(ctx, query, input.DeskripsiTujuan, id)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (r *postgresRepository) DeleteTujuan(ctx context.Context, schemaName string, id int) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return err
 	}
-	_, err := r.db.ExecContext(ctx, "DELETE FROM tujuan_pembelajaran WHERE id = $1", id)
-	return err
+	defer tx.Rollback()
+	_, err = tx.ExecContext(ctx, "DELETE FROM tujuan_pembelajaran WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (r *postgresRepository) CreateUjian(ctx context.Context, schemaName string, input UpsertUjianInput) (*Ujian, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
-		return nil, err
-	}
-	tx, err := r.db.BeginTx(ctx, nil)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
 	if err != nil {
-		return nil, fmt.Errorf("gagal memulai transaksi: %w", err)
+		return nil, err
 	}
 	defer tx.Rollback()
 	var maxUrutan sql.NullInt64
@@ -512,33 +526,45 @@ func (r *postgresRepository) CreateUjian(ctx context.Context, schemaName string,
 }
 
 func (r *postgresRepository) UpdateUjian(ctx context.Context, schemaName string, id int, input UpsertUjianInput) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 	query := `UPDATE ujian SET nama_ujian = $1, updated_at = NOW() WHERE id = $2`
-	_, err := r.db.ExecContext(ctx, query, input.NamaUjian, id)
-	return err
+	_, err = tx.ExecContext(ctx, query, input.NamaUjian, id)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (r *postgresRepository) DeleteUjian(ctx context.Context, schemaName string, id int) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return err
 	}
-	_, err := r.db.ExecContext(ctx, "DELETE FROM ujian WHERE id = $1", id)
-	return err
+	defer tx.Rollback()
+	_, err = tx.ExecContext(ctx, "DELETE FROM ujian WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (r *postgresRepository) GetAllUjianByPengajarKelas(ctx context.Context, schemaName string, pengajarKelasIDs []string) (map[string][]UjianDetail, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 	query := `
         SELECT u.id, u.pengajar_kelas_id, u.ujian_master_id, um.nama_paket_ujian, u.urutan, u.created_at, u.updated_at
         FROM ujian u
         JOIN ujian_master um ON u.ujian_master_id = um.id
         WHERE u.pengajar_kelas_id = ANY($1)
     `
-	rows, err := r.db.QueryContext(ctx, query, pq.Array(pengajarKelasIDs))
+	rows, err := tx.QueryContext(ctx, query, pq.Array(pengajarKelasIDs))
 	if err != nil {
 		return nil, fmt.Errorf("gagal query ujian: %w", err)
 	}
@@ -551,5 +577,8 @@ func (r *postgresRepository) GetAllUjianByPengajarKelas(ctx context.Context, sch
 		}
 		ujianMap[u.PengajarKelasID] = append(ujianMap[u.PengajarKelasID], u)
 	}
-	return ujianMap, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return ujianMap, tx.Commit()
 }
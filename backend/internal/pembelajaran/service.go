@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"skoola/internal/access"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -14,37 +15,38 @@ var ErrValidation = errors.New("validation failed")
 // Service mendefinisikan interface untuk logika bisnis.
 type Service interface {
 	// Rencana Pembelajaran
-	GetAllRencanaPembelajaran(ctx context.Context, schemaName string, pengajarKelasID string) ([]RencanaPembelajaranItem, error)
-	UpdateRencanaUrutan(ctx context.Context, schemaName string, input UpdateRencanaUrutanInput) error
+	GetAllRencanaPembelajaran(ctx context.Context, schemaName string, actor access.Actor, pengajarKelasID string) ([]RencanaPembelajaranItem, error)
+	UpdateRencanaUrutan(ctx context.Context, schemaName string, actor access.Actor, input UpdateRencanaUrutanInput) error
 
 	// Materi
-	CreateMateri(ctx context.Context, schemaName string, input UpsertMateriInput) (*MateriPembelajaran, error)
-	UpdateMateri(ctx context.Context, schemaName string, id int, input UpsertMateriInput) error
-	DeleteMateri(ctx context.Context, schemaName string, id int) error
+	CreateMateri(ctx context.Context, schemaName string, actor access.Actor, input UpsertMateriInput) (*MateriPembelajaran, error)
+	UpdateMateri(ctx context.Context, schemaName string, actor access.Actor, id int, input UpsertMateriInput) error
+	DeleteMateri(ctx context.Context, schemaName string, actor access.Actor, id int) error
 
 	// Ujian
-	CreateUjian(ctx context.Context, schemaName string, input UpsertUjianInput) (*Ujian, error)
-	UpdateUjian(ctx context.Context, schemaName string, id int, input UpsertUjianInput) error
-	DeleteUjian(ctx context.Context, schemaName string, id int) error
-	CreateBulkUjian(ctx context.Context, schemaName string, input CreateBulkUjianInput) (*BulkUjianResult, error)
+	CreateUjian(ctx context.Context, schemaName string, actor access.Actor, input UpsertUjianInput) (*Ujian, error)
+	UpdateUjian(ctx context.Context, schemaName string, actor access.Actor, id int, input UpsertUjianInput) error
+	DeleteUjian(ctx context.Context, schemaName string, actor access.Actor, id int) error
+	CreateBulkUjian(ctx context.Context, schemaName string, actor access.Actor, input CreateBulkUjianInput) (*BulkUjianResult, error)
 	// BARU: Service untuk monitoring
 	GetAllUjianMonitoringByTahunAjaran(ctx context.Context, schemaName string, tahunAjaranID string) ([]UjianMonitoring, error)
 
 	// Tujuan Pembelajaran
-	CreateTujuan(ctx context.Context, schemaName string, input UpsertTujuanInput) (*TujuanPembelajaran, error)
-	UpdateTujuan(ctx context.Context, schemaName string, id int, input UpsertTujuanInput) error
-	DeleteTujuan(ctx context.Context, schemaName string, id int) error
-	UpdateUrutanTujuan(ctx context.Context, schemaName string, input UpdateUrutanInput) error
+	CreateTujuan(ctx context.Context, schemaName string, actor access.Actor, input UpsertTujuanInput) (*TujuanPembelajaran, error)
+	UpdateTujuan(ctx context.Context, schemaName string, actor access.Actor, id int, input UpsertTujuanInput) error
+	DeleteTujuan(ctx context.Context, schemaName string, actor access.Actor, id int) error
+	UpdateUrutanTujuan(ctx context.Context, schemaName string, actor access.Actor, input UpdateUrutanInput) error
 }
 
 type service struct {
 	repo     Repository
+	access   access.Service
 	validate *validator.Validate
 }
 
 // NewService membuat instance baru dari service.
-func NewService(repo Repository, validate *validator.Validate) Service {
-	return &service{repo: repo, validate: validate}
+func NewService(repo Repository, accessService access.Service, validate *validator.Validate) Service {
+	return &service{repo: repo, access: accessService, validate: validate}
 }
 
 // --- Implementasi GetAllUjianMonitoringByTahunAjaran (BARU) ---
@@ -55,84 +57,132 @@ func (s *service) GetAllUjianMonitoringByTahunAjaran(ctx context.Context, schema
 
 // --- Implementasi CreateBulkUjian ---
 
-func (s *service) CreateBulkUjian(ctx context.Context, schemaName string, input CreateBulkUjianInput) (*BulkUjianResult, error) {
+func (s *service) CreateBulkUjian(ctx context.Context, schemaName string, actor access.Actor, input CreateBulkUjianInput) (*BulkUjianResult, error) {
 	if err := s.validate.Struct(input); err != nil {
 		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
 	}
 
+	if err := s.access.AuthorizeWrite(ctx, schemaName, actor, access.Target{PengajarKelasIDs: input.PengajarKelasIDs}); err != nil {
+		return nil, err
+	}
 	return s.repo.CreateBulkUjian(ctx, schemaName, input)
 }
 
-func (s *service) GetAllRencanaPembelajaran(ctx context.Context, schemaName string, pengajarKelasID string) ([]RencanaPembelajaranItem, error) {
+func (s *service) GetAllRencanaPembelajaran(ctx context.Context, schemaName string, actor access.Actor, pengajarKelasID string) ([]RencanaPembelajaranItem, error) {
+	// Pengajar mapel dan wali kelas boleh melihat rencana pembelajaran.
+	if err := s.access.AuthorizeRead(ctx, schemaName, actor, access.Target{PengajarKelasIDs: []string{pengajarKelasID}}); err != nil {
+		return nil, err
+	}
 	return s.repo.GetAllRencanaPembelajaran(ctx, schemaName, pengajarKelasID)
 }
 
-func (s *service) UpdateRencanaUrutan(ctx context.Context, schemaName string, input UpdateRencanaUrutanInput) error {
+func (s *service) UpdateRencanaUrutan(ctx context.Context, schemaName string, actor access.Actor, input UpdateRencanaUrutanInput) error {
 	if err := s.validate.Struct(input); err != nil {
 		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
 	}
+	var target access.Target
+	for _, item := range input.OrderedItems {
+		if item.Type == "materi" {
+			target.MateriIDs = append(target.MateriIDs, item.ID)
+		} else {
+			target.UjianIDs = append(target.UjianIDs, item.ID)
+		}
+	}
+	if err := s.access.AuthorizeWrite(ctx, schemaName, actor, target); err != nil {
+		return err
+	}
 	return s.repo.UpdateRencanaUrutan(ctx, schemaName, input.OrderedItems)
 }
 
-func (s *service) UpdateUrutanTujuan(ctx context.Context, schemaName string, input UpdateUrutanInput) error {
+func (s *service) UpdateUrutanTujuan(ctx context.Context, schemaName string, actor access.Actor, input UpdateUrutanInput) error {
 	if err := s.validate.Struct(input); err != nil {
 		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
 	}
+	if err := s.access.AuthorizeWrite(ctx, schemaName, actor, access.Target{TujuanIDs: input.OrderedIDs}); err != nil {
+		return err
+	}
 	return s.repo.UpdateUrutanTujuan(ctx, schemaName, input.OrderedIDs)
 }
 
-func (s *service) CreateMateri(ctx context.Context, schemaName string, input UpsertMateriInput) (*MateriPembelajaran, error) {
+func (s *service) CreateMateri(ctx context.Context, schemaName string, actor access.Actor, input UpsertMateriInput) (*MateriPembelajaran, error) {
 	if err := s.validate.Struct(input); err != nil {
 		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
 	}
+	if err := s.access.AuthorizeWrite(ctx, schemaName, actor, access.Target{PengajarKelasIDs: []string{input.PengajarKelasID}}); err != nil {
+		return nil, err
+	}
 	return s.repo.CreateMateri(ctx, schemaName, input)
 }
 
-func (s *service) UpdateMateri(ctx context.Context, schemaName string, id int, input UpsertMateriInput) error {
+func (s *service) UpdateMateri(ctx context.Context, schemaName string, actor access.Actor, id int, input UpsertMateriInput) error {
 	if err := s.validate.Struct(input); err != nil {
 		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
 	}
+	if err := s.access.AuthorizeWrite(ctx, schemaName, actor, access.Target{MateriIDs: []int{id}}); err != nil {
+		return err
+	}
 	return s.repo.UpdateMateri(ctx, schemaName, id, input)
 }
 
-func (s *service) DeleteMateri(ctx context.Context, schemaName string, id int) error {
+func (s *service) DeleteMateri(ctx context.Context, schemaName string, actor access.Actor, id int) error {
+	if err := s.access.AuthorizeWrite(ctx, schemaName, actor, access.Target{MateriIDs: []int{id}}); err != nil {
+		return err
+	}
 	return s.repo.DeleteMateri(ctx, schemaName, id)
 }
 
 // --- UJIAN ---
-func (s *service) CreateUjian(ctx context.Context, schemaName string, input UpsertUjianInput) (*Ujian, error) {
+func (s *service) CreateUjian(ctx context.Context, schemaName string, actor access.Actor, input UpsertUjianInput) (*Ujian, error) {
 	if err := s.validate.Struct(input); err != nil {
 		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
 	}
+	if err := s.access.AuthorizeWrite(ctx, schemaName, actor, access.Target{PengajarKelasIDs: []string{input.PengajarKelasID}}); err != nil {
+		return nil, err
+	}
 	return s.repo.CreateUjian(ctx, schemaName, input)
 }
 
-func (s *service) UpdateUjian(ctx context.Context, schemaName string, id int, input UpsertUjianInput) error {
+func (s *service) UpdateUjian(ctx context.Context, schemaName string, actor access.Actor, id int, input UpsertUjianInput) error {
 	if err := s.validate.Struct(input); err != nil {
 		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
 	}
+	if err := s.access.AuthorizeWrite(ctx, schemaName, actor, access.Target{UjianIDs: []int{id}}); err != nil {
+		return err
+	}
 	return s.repo.UpdateUjian(ctx, schemaName, id, input)
 }
 
-func (s *service) DeleteUjian(ctx context.Context, schemaName string, id int) error {
+func (s *service) DeleteUjian(ctx context.Context, schemaName string, actor access.Actor, id int) error {
+	if err := s.access.AuthorizeWrite(ctx, schemaName, actor, access.Target{UjianIDs: []int{id}}); err != nil {
+		return err
+	}
 	return s.repo.DeleteUjian(ctx, schemaName, id)
 }
 
 // --- TUJUAN ---
-func (s *service) CreateTujuan(ctx context.Context, schemaName string, input UpsertTujuanInput) (*TujuanPembelajaran, error) {
+func (s *service) CreateTujuan(ctx context.Context, schemaName string, actor access.Actor, input UpsertTujuanInput) (*TujuanPembelajaran, error) {
 	if err := s.validate.Struct(input); err != nil {
 		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
 	}
+	if err := s.access.AuthorizeWrite(ctx, schemaName, actor, access.Target{MateriIDs: []int{input.MateriPembelajaranID}}); err != nil {
+		return nil, err
+	}
 	return s.repo.CreateTujuan(ctx, schemaName, input)
 }
 
-func (s *service) UpdateTujuan(ctx context.Context, schemaName string, id int, input UpsertTujuanInput) error {
+func (s *service) UpdateTujuan(ctx context.Context, schemaName string, actor access.Actor, id int, input UpsertTujuanInput) error {
 	if err := s.validate.Struct(input); err != nil {
 		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
 	}
+	if err := s.access.AuthorizeWrite(ctx, schemaName, actor, access.Target{TujuanIDs: []int{id}}); err != nil {
+		return err
+	}
 	return s.repo.UpdateTujuan(ctx, schemaName, id, input)
 }
 
-func (s *service) DeleteTujuan(ctx context.Context, schemaName string, id int) error {
+func (s *service) DeleteTujuan(ctx context.Context, schemaName string, actor access.Actor, id int) error {
+	if err := s.access.AuthorizeWrite(ctx, schemaName, actor, access.Target{TujuanIDs: []int{id}}); err != nil {
+		return err
+	}
 	return s.repo.DeleteTujuan(ctx, schemaName, id)
 }
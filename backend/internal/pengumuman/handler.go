@@ -0,0 +1,98 @@
+// file: backend/internal/pengumuman/handler.go
+package pengumuman
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"skoola/internal/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{service: s}
+}
+
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	var input UpsertPengumumanInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.Create(r.Context(), schemaName, input)
+	if err != nil {
+		if errors.Is(err, ErrValidation) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Gagal membuat pengumuman: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	result, err := h.service.GetAll(r.Context(), schemaName)
+	if err != nil {
+		http.Error(w, "Gagal mengambil pengumuman: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	id := chi.URLParam(r, "id")
+	var input UpsertPengumumanInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.Update(r.Context(), schemaName, id, input)
+	if err != nil {
+		if errors.Is(err, ErrValidation) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Pengumuman tidak ditemukan", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Gagal memperbarui pengumuman: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	id := chi.URLParam(r, "id")
+
+	if err := h.service.Delete(r.Context(), schemaName, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Pengumuman tidak ditemukan", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Gagal menghapus pengumuman: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
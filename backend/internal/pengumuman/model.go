@@ -0,0 +1,31 @@
+// file: backend/internal/pengumuman/model.go
+package pengumuman
+
+import "time"
+
+// Target pengumuman menentukan pembaca di portal.
+const (
+	TargetSemua = "SEMUA"
+	TargetSiswa = "SISWA"
+	TargetWali  = "WALI"
+)
+
+// Pengumuman merepresentasikan satu pengumuman sekolah.
+type Pengumuman struct {
+	ID            string    `json:"id"`
+	Judul         string    `json:"judul"`
+	Isi           string    `json:"isi"`
+	Target        string    `json:"target"`
+	TanggalTerbit time.Time `json:"tanggal_terbit"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// UpsertPengumumanInput adalah DTO untuk membuat atau memperbarui pengumuman.
+// TanggalTerbit kosong berarti pengumuman langsung terbit.
+type UpsertPengumumanInput struct {
+	Judul         string     `json:"judul" validate:"required,min=3,max=255"`
+	Isi           string     `json:"isi" validate:"required"`
+	Target        string     `json:"target" validate:"required,oneof=SEMUA SISWA WALI"`
+	TanggalTerbit *time.Time `json:"tanggal_terbit"`
+}
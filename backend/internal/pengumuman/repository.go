@@ -0,0 +1,149 @@
+// file: backend/internal/pengumuman/repository.go
+package pengumuman
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"skoola/pkg/database"
+)
+
+// Repository mendefinisikan interface untuk interaksi database pengumuman.
+type Repository interface {
+	Create(ctx context.Context, schemaName string, p *Pengumuman) (*Pengumuman, error)
+	GetAll(ctx context.Context, schemaName string) ([]Pengumuman, error)
+	GetByID(ctx context.Context, schemaName string, id string) (*Pengumuman, error)
+	GetTerbit(ctx context.Context, schemaName string, target string) ([]Pengumuman, error)
+	Update(ctx context.Context, schemaName string, p *Pengumuman) error
+	Delete(ctx context.Context, schemaName string, id string) error
+}
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewRepository membuat instance baru dari postgresRepository.
+func NewRepository(db *sql.DB) Repository {
+	return &postgresRepository{db: db}
+}
+
+const selectPengumuman = `SELECT id, judul, isi, target, tanggal_terbit, created_at, updated_at FROM pengumuman`
+
+func (r *postgresRepository) Create(ctx context.Context, schemaName string, p *Pengumuman) (*Pengumuman, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO pengumuman (id, judul, isi, target, tanggal_terbit)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at, updated_at
+	`
+	err = tx.QueryRowContext(ctx, query, p.ID, p.Judul, p.Isi, p.Target, p.TanggalTerbit).Scan(&p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("gagal insert pengumuman: %w", err)
+	}
+	return p, tx.Commit()
+}
+
+func (r *postgresRepository) GetAll(ctx context.Context, schemaName string) ([]Pengumuman, error) {
+	return r.query(ctx, schemaName, selectPengumuman+` ORDER BY tanggal_terbit DESC`)
+}
+
+// GetTerbit mengambil pengumuman yang sudah terbit untuk target tertentu,
+// termasuk pengumuman dengan target SEMUA.
+func (r *postgresRepository) GetTerbit(ctx context.Context, schemaName string, target string) ([]Pengumuman, error) {
+	query := selectPengumuman + ` WHERE target IN ('SEMUA', $1) AND tanggal_terbit <= NOW() ORDER BY tanggal_terbit DESC`
+	return r.query(ctx, schemaName, query, target)
+}
+
+func (r *postgresRepository) query(ctx context.Context, schemaName string, query string, args ...interface{}) ([]Pengumuman, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil pengumuman: %w", err)
+	}
+	defer rows.Close()
+
+	list := []Pengumuman{}
+	for rows.Next() {
+		var p Pengumuman
+		if err := rows.Scan(&p.ID, &p.Judul, &p.Isi, &p.Target, &p.TanggalTerbit, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("gagal memindai pengumuman: %w", err)
+		}
+		list = append(list, p)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+func (r *postgresRepository) GetByID(ctx context.Context, schemaName string, id string) (*Pengumuman, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var p Pengumuman
+	err = tx.QueryRowContext(ctx, selectPengumuman+` WHERE id = $1`, id).
+		Scan(&p.ID, &p.Judul, &p.Isi, &p.Target, &p.TanggalTerbit, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("gagal mengambil pengumuman: %w", err)
+	}
+	return &p, tx.Commit()
+}
+
+func (r *postgresRepository) Update(ctx context.Context, schemaName string, p *Pengumuman) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE pengumuman SET judul = $1, isi = $2, target = $3, tanggal_terbit = $4, updated_at = NOW()
+		WHERE id = $5
+		RETURNING updated_at
+	`
+	err = tx.QueryRowContext(ctx, query, p.Judul, p.Isi, p.Target, p.TanggalTerbit, p.ID).Scan(&p.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return sql.ErrNoRows
+		}
+		return fmt.Errorf("gagal update pengumuman: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (r *postgresRepository) Delete(ctx context.Context, schemaName string, id string) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM pengumuman WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("gagal menghapus pengumuman: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
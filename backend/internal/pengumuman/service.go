@@ -0,0 +1,107 @@
+// file: backend/internal/pengumuman/service.go
+package pengumuman
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"skoola/internal/audit"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+var ErrValidation = errors.New("validation failed")
+
+// Service mendefinisikan interface untuk logika bisnis pengumuman.
+type Service interface {
+	Create(ctx context.Context, schemaName string, input UpsertPengumumanInput) (*Pengumuman, error)
+	GetAll(ctx context.Context, schemaName string) ([]Pengumuman, error)
+	GetTerbit(ctx context.Context, schemaName string, target string) ([]Pengumuman, error)
+	Update(ctx context.Context, schemaName string, id string, input UpsertPengumumanInput) (*Pengumuman, error)
+	Delete(ctx context.Context, schemaName string, id string) error
+}
+
+type service struct {
+	repo     Repository
+	audit    audit.Recorder
+	validate *validator.Validate
+}
+
+// NewService membuat instance baru dari service pengumuman.
+func NewService(repo Repository, auditLog audit.Recorder, validate *validator.Validate) Service {
+	return &service{repo: repo, audit: auditLog, validate: validate}
+}
+
+func (s *service) Create(ctx context.Context, schemaName string, input UpsertPengumumanInput) (*Pengumuman, error) {
+	if err := s.validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+
+	p := &Pengumuman{ID: uuid.New().String()}
+	applyInput(p, input)
+
+	created, err := s.repo.Create(ctx, schemaName, p)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionCreate, Entity: "pengumuman", EntityID: created.ID, After: created})
+	return created, nil
+}
+
+func (s *service) GetAll(ctx context.Context, schemaName string) ([]Pengumuman, error) {
+	return s.repo.GetAll(ctx, schemaName)
+}
+
+func (s *service) GetTerbit(ctx context.Context, schemaName string, target string) ([]Pengumuman, error) {
+	return s.repo.GetTerbit(ctx, schemaName, target)
+}
+
+func (s *service) Update(ctx context.Context, schemaName string, id string, input UpsertPengumumanInput) (*Pengumuman, error) {
+	if err := s.validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+
+	existing, err := s.repo.GetByID(ctx, schemaName, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	updated := *existing
+	applyInput(&updated, input)
+	if err := s.repo.Update(ctx, schemaName, &updated); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionUpdate, Entity: "pengumuman", EntityID: id, Before: existing, After: updated})
+	return &updated, nil
+}
+
+func (s *service) Delete(ctx context.Context, schemaName string, id string) error {
+	existing, err := s.repo.GetByID(ctx, schemaName, id)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return sql.ErrNoRows
+	}
+	if err := s.repo.Delete(ctx, schemaName, id); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionDelete, Entity: "pengumuman", EntityID: id, Before: existing})
+	return nil
+}
+
+func applyInput(p *Pengumuman, input UpsertPengumumanInput) {
+	p.Judul = input.Judul
+	p.Isi = input.Isi
+	p.Target = input.Target
+	p.TanggalTerbit = time.Now()
+	if input.TanggalTerbit != nil {
+		p.TanggalTerbit = *input.TanggalTerbit
+	}
+}
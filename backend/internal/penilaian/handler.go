@@ -3,7 +3,10 @@ package penilaian
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"skoola/internal/access"
+	"skoola/internal/kuncinilai"
 	"skoola/internal/middleware"
 
 	"github.com/go-chi/chi/v5"
@@ -22,8 +25,12 @@ func (h *Handler) GetPenilaianLengkap(w http.ResponseWriter, r *http.Request) {
 	kelasID := chi.URLParam(r, "kelasID")
 	pengajarKelasID := chi.URLParam(r, "pengajarKelasID")
 
-	result, err := h.service.GetPenilaianLengkap(r.Context(), schemaName, kelasID, pengajarKelasID)
+	result, err := h.service.GetPenilaianLengkap(r.Context(), schemaName, access.ActorFromContext(r.Context()), kelasID, pengajarKelasID)
 	if err != nil {
+		if errors.Is(err, access.ErrForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		http.Error(w, "Gagal mengambil data penilaian lengkap: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -40,8 +47,16 @@ func (h *Handler) UpsertNilaiBulk(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.service.UpsertNilaiBulk(r.Context(), schemaName, input)
+	err := h.service.UpsertNilaiBulk(r.Context(), schemaName, access.ActorFromContext(r.Context()), input)
 	if err != nil {
+		if errors.Is(err, access.ErrForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		if errors.Is(err, kuncinilai.ErrLocked) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		http.Error(w, "Gagal menyimpan nilai: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
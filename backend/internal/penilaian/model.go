@@ -25,18 +25,29 @@ type BulkUpsertNilaiInput struct {
 	NilaiSumatif  []UpsertNilaiSumatifSiswaInput `json:"nilai_sumatif" validate:"dive"`
 }
 
+// NilaiSnapshot adalah kumpulan nilai seorang siswa yang dicatat di audit log.
+type NilaiSnapshot struct {
+	NilaiFormatif map[int]*float64    `json:"nilai_formatif,omitempty"` // map[tp_id]nilai
+	NilaiSumatif  map[string]*float64 `json:"nilai_sumatif,omitempty"`  // map[penilaian_sumatif_id]nilai
+}
+
 // --- STRUCT UNTUK MENGIRIM DATA KE FRONTEND ---
 
 // NilaiSiswa merepresentasikan nilai akhir formatif untuk satu TP.
+// Tuntas nil berarti nilai atau KKTP-nya belum ada.
 type NilaiSiswa struct {
-	Nilai     *float64   `json:"nilai"`
-	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	Nilai     *float64      `json:"nilai"`
+	UpdatedAt *time.Time    `json:"updated_at,omitempty"`
+	Tuntas    *bool         `json:"tuntas,omitempty"`
+	Remedial  *RemedialInfo `json:"remedial,omitempty"`
 }
 
 // NilaiSumatifSiswa merepresentasikan nilai untuk satu komponen penilaian (PR, Tugas, dll).
 type NilaiSumatifSiswa struct {
-	Nilai     *float64   `json:"nilai"`
-	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	Nilai     *float64      `json:"nilai"`
+	UpdatedAt *time.Time    `json:"updated_at,omitempty"`
+	Tuntas    *bool         `json:"tuntas,omitempty"`
+	Remedial  *RemedialInfo `json:"remedial,omitempty"`
 }
 
 // PenilaianSiswaData adalah data lengkap seorang siswa, berisi semua nilainya.
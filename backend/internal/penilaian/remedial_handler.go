@@ -0,0 +1,85 @@
+// file: backend/internal/penilaian/remedial_handler.go
+package penilaian
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"skoola/internal/access"
+	"skoola/internal/kuncinilai"
+	"skoola/internal/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type RemedialHandler struct {
+	service RemedialService
+}
+
+func NewRemedialHandler(s RemedialService) *RemedialHandler {
+	return &RemedialHandler{service: s}
+}
+
+// GetByPengajar adalah handler untuk GET /penilaian/remedial/kelas/{kelasID}/pengajar/{pengajarKelasID}.
+func (h *RemedialHandler) GetByPengajar(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	kelasID := chi.URLParam(r, "kelasID")
+	pengajarKelasID := chi.URLParam(r, "pengajarKelasID")
+
+	list, err := h.service.GetByPengajar(r.Context(), schemaName, access.ActorFromContext(r.Context()), kelasID, pengajarKelasID)
+	if err != nil {
+		writeRemedialError(w, "Gagal mengambil data remedial: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// Upsert adalah handler untuk POST /penilaian/remedial.
+func (h *RemedialHandler) Upsert(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	var input UpsertRemedialInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.Upsert(r.Context(), schemaName, access.ActorFromContext(r.Context()), input)
+	if err != nil {
+		writeRemedialError(w, "Gagal menyimpan remedial: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// Delete adalah handler untuk DELETE /penilaian/remedial/{id}.
+func (h *RemedialHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	id := chi.URLParam(r, "id")
+
+	if err := h.service.Delete(r.Context(), schemaName, access.ActorFromContext(r.Context()), id); err != nil {
+		writeRemedialError(w, "Gagal menghapus remedial: ", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeRemedialError(w http.ResponseWriter, prefix string, err error) {
+	switch {
+	case errors.Is(err, access.ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, ErrValidation):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, kuncinilai.ErrLocked):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Remedial tidak ditemukan", http.StatusNotFound)
+	default:
+		http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,55 @@
+// file: backend/internal/penilaian/remedial_model.go
+package penilaian
+
+import "time"
+
+// Nilai yang dipakai setelah remedial.
+const (
+	NilaiDipakaiAsli     = "ASLI"
+	NilaiDipakaiRemedial = "REMEDIAL"
+)
+
+// Remedial merepresentasikan data dari tabel 'remedial'. Satu remedial melekat pada
+// satu nilai formatif (TujuanPembelajaranID) atau satu nilai sumatif (PenilaianSumatifID).
+type Remedial struct {
+	ID                   string    `json:"id"`
+	AnggotaKelasID       string    `json:"anggota_kelas_id"`
+	NamaSiswa            string    `json:"nama_siswa,omitempty"`
+	TujuanPembelajaranID *int      `json:"tujuan_pembelajaran_id"`
+	PenilaianSumatifID   *string   `json:"penilaian_sumatif_id"`
+	NilaiAsli            float64   `json:"nilai_asli"`
+	NilaiRemedial        float64   `json:"nilai_remedial"`
+	NilaiDipakai         string    `json:"nilai_dipakai"`
+	Tanggal              time.Time `json:"tanggal"`
+	Catatan              *string   `json:"catatan"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+// RemedialInfo adalah ringkasan remedial yang disertakan pada tampilan nilai.
+type RemedialInfo struct {
+	ID            string  `json:"id"`
+	NilaiAsli     float64 `json:"nilai_asli"`
+	NilaiRemedial float64 `json:"nilai_remedial"`
+	NilaiDipakai  string  `json:"nilai_dipakai"`
+}
+
+// UpsertRemedialInput adalah DTO untuk mencatat atau mengubah remedial satu nilai.
+// Isi salah satu dari TujuanPembelajaranID atau PenilaianSumatifID.
+type UpsertRemedialInput struct {
+	AnggotaKelasID       string   `json:"anggota_kelas_id" validate:"required,uuid"`
+	TujuanPembelajaranID *int     `json:"tujuan_pembelajaran_id"`
+	PenilaianSumatifID   *string  `json:"penilaian_sumatif_id" validate:"omitempty,uuid"`
+	NilaiRemedial        *float64 `json:"nilai_remedial" validate:"required,min=0,max=100"`
+	NilaiDipakai         string   `json:"nilai_dipakai" validate:"required,oneof=ASLI REMEDIAL"`
+	Tanggal              string   `json:"tanggal" validate:"omitempty,datetime=2006-01-02"`
+	Catatan              string   `json:"catatan"`
+}
+
+// NilaiEfektif mengembalikan nilai yang dipakai sesuai pilihan NilaiDipakai.
+func (r *Remedial) NilaiEfektif() float64 {
+	if r.NilaiDipakai == NilaiDipakaiAsli {
+		return r.NilaiAsli
+	}
+	return r.NilaiRemedial
+}
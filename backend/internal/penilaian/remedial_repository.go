@@ -0,0 +1,240 @@
+// file: backend/internal/penilaian/remedial_repository.go
+package penilaian
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"skoola/pkg/database"
+)
+
+// RemedialRepository mendefinisikan interaksi database untuk remedial.
+type RemedialRepository interface {
+	GetByID(ctx context.Context, schemaName string, id string) (*Remedial, error)
+	GetByNilai(ctx context.Context, schemaName string, anggotaKelasID string, tpID *int, penilaianSumatifID *string) (*Remedial, error)
+	GetByPengajar(ctx context.Context, schemaName string, kelasID string, pengajarKelasID string) ([]Remedial, error)
+	GetNilaiSaatIni(ctx context.Context, schemaName string, anggotaKelasID string, tpID *int, penilaianSumatifID *string) (*float64, error)
+	Save(ctx context.Context, schemaName string, r *Remedial) (*Remedial, error)
+	Delete(ctx context.Context, schemaName string, r *Remedial) error
+}
+
+type remedialRepository struct {
+	db *sql.DB
+}
+
+// NewRemedialRepository membuat instance baru dari RemedialRepository.
+func NewRemedialRepository(db *sql.DB) RemedialRepository {
+	return &remedialRepository{db: db}
+}
+
+const selectRemedial = `
+	SELECT rm.id, rm.anggota_kelas_id, s.nama_lengkap, rm.tujuan_pembelajaran_id, rm.penilaian_sumatif_id,
+	       rm.nilai_asli::float8, rm.nilai_remedial::float8, rm.nilai_dipakai, rm.tanggal, rm.catatan,
+	       rm.created_at, rm.updated_at
+	FROM remedial rm
+	JOIN anggota_kelas ak ON rm.anggota_kelas_id = ak.id
+	JOIN students s ON ak.student_id = s.id
+`
+
+func scanRemedial(row interface{ Scan(...interface{}) error }) (*Remedial, error) {
+	var r Remedial
+	err := row.Scan(&r.ID, &r.AnggotaKelasID, &r.NamaSiswa, &r.TujuanPembelajaranID, &r.PenilaianSumatifID,
+		&r.NilaiAsli, &r.NilaiRemedial, &r.NilaiDipakai, &r.Tanggal, &r.Catatan, &r.CreatedAt, &r.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (r *remedialRepository) GetByID(ctx context.Context, schemaName string, id string) (*Remedial, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rm, err := scanRemedial(tx.QueryRowContext(ctx, selectRemedial+` WHERE rm.id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("gagal mengambil data remedial: %w", err)
+	}
+	return rm, tx.Commit()
+}
+
+func (r *remedialRepository) GetByNilai(ctx context.Context, schemaName string, anggotaKelasID string, tpID *int, penilaianSumatifID *string) (*Remedial, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := selectRemedial + `
+		WHERE rm.anggota_kelas_id = $1
+		  AND rm.tujuan_pembelajaran_id IS NOT DISTINCT FROM $2
+		  AND rm.penilaian_sumatif_id IS NOT DISTINCT FROM $3::uuid
+	`
+	rm, err := scanRemedial(tx.QueryRowContext(ctx, query, anggotaKelasID, tpID, penilaianSumatifID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("gagal mengambil data remedial: %w", err)
+	}
+	return rm, tx.Commit()
+}
+
+// GetByPengajar mengambil semua remedial untuk nilai milik pengajar kelas di kelas tersebut.
+func (r *remedialRepository) GetByPengajar(ctx context.Context, schemaName string, kelasID string, pengajarKelasID string) ([]Remedial, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := selectRemedial + `
+		LEFT JOIN penilaian_sumatif ps ON rm.penilaian_sumatif_id = ps.id
+		LEFT JOIN tujuan_pembelajaran tp ON tp.id = COALESCE(rm.tujuan_pembelajaran_id, ps.tujuan_pembelajaran_id)
+		LEFT JOIN materi_pembelajaran m ON tp.materi_pembelajaran_id = m.id
+		LEFT JOIN ujian u ON ps.ujian_id = u.id
+		WHERE ak.kelas_id = $1 AND COALESCE(m.pengajar_kelas_id, u.pengajar_kelas_id) = $2
+		ORDER BY ak.urutan ASC, s.nama_lengkap ASC, rm.tanggal ASC
+	`
+	rows, err := tx.QueryContext(ctx, query, kelasID, pengajarKelasID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil daftar remedial: %w", err)
+	}
+	defer rows.Close()
+
+	list := []Remedial{}
+	for rows.Next() {
+		rm, err := scanRemedial(rows)
+		if err != nil {
+			return nil, fmt.Errorf("gagal memindai data remedial: %w", err)
+		}
+		list = append(list, *rm)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+// GetNilaiSaatIni mengambil nilai yang tersimpan untuk satu TP atau satu penilaian sumatif.
+// Hasilnya nil jika nilai belum diisi.
+func (r *remedialRepository) GetNilaiSaatIni(ctx context.Context, schemaName string, anggotaKelasID string, tpID *int, penilaianSumatifID *string) (*float64, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var nilai sql.NullFloat64
+	if tpID != nil {
+		err = tx.QueryRowContext(ctx,
+			`SELECT nilai::float8 FROM penilaian WHERE anggota_kelas_id = $1 AND tujuan_pembelajaran_id = $2`,
+			anggotaKelasID, *tpID).Scan(&nilai)
+	} else {
+		err = tx.QueryRowContext(ctx,
+			`SELECT nilai::float8 FROM nilai_sumatif_siswa WHERE anggota_kelas_id = $1 AND penilaian_sumatif_id = $2`,
+			anggotaKelasID, *penilaianSumatifID).Scan(&nilai)
+	}
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("gagal mengambil nilai saat ini: %w", err)
+	}
+	if !nilai.Valid {
+		return nil, tx.Commit()
+	}
+	return &nilai.Float64, tx.Commit()
+}
+
+// Save menyimpan remedial dan menulis nilai yang dipakai ke tabel nilai asalnya
+// dalam satu transaksi.
+func (r *remedialRepository) Save(ctx context.Context, schemaName string, rm *Remedial) (*Remedial, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if rm.ID == "" {
+		query := `
+			INSERT INTO remedial (anggota_kelas_id, tujuan_pembelajaran_id, penilaian_sumatif_id,
+				nilai_asli, nilai_remedial, nilai_dipakai, tanggal, catatan)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING id
+		`
+		err = tx.QueryRowContext(ctx, query, rm.AnggotaKelasID, rm.TujuanPembelajaranID, rm.PenilaianSumatifID,
+			rm.NilaiAsli, rm.NilaiRemedial, rm.NilaiDipakai, rm.Tanggal, rm.Catatan).Scan(&rm.ID)
+		if err != nil {
+			return nil, fmt.Errorf("gagal menyimpan remedial: %w", err)
+		}
+	} else {
+		query := `
+			UPDATE remedial
+			SET nilai_remedial = $1, nilai_dipakai = $2, tanggal = $3, catatan = $4, updated_at = NOW()
+			WHERE id = $5
+		`
+		if _, err := tx.ExecContext(ctx, query, rm.NilaiRemedial, rm.NilaiDipakai, rm.Tanggal, rm.Catatan, rm.ID); err != nil {
+			return nil, fmt.Errorf("gagal memperbarui remedial: %w", err)
+		}
+	}
+
+	if err := setNilai(ctx, tx, rm, rm.NilaiEfektif()); err != nil {
+		return nil, err
+	}
+
+	saved, err := scanRemedial(tx.QueryRowContext(ctx, selectRemedial+` WHERE rm.id = $1`, rm.ID))
+	if err != nil {
+		return nil, fmt.Errorf("gagal memindai data remedial setelah disimpan: %w", err)
+	}
+	return saved, tx.Commit()
+}
+
+// Delete menghapus remedial dan mengembalikan nilai asli ke tabel nilai asalnya. Nilai
+// asli hanya dikembalikan jika nilai tersimpan masih sama dengan nilai yang ditulis
+// remedial, agar nilai yang sudah diubah guru tidak tertimpa.
+func (r *remedialRepository) Delete(ctx context.Context, schemaName string, rm *Remedial) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if rm.TujuanPembelajaranID != nil {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE penilaian SET nilai = $1, updated_at = NOW()
+			WHERE anggota_kelas_id = $2 AND tujuan_pembelajaran_id = $3 AND nilai = $4`,
+			rm.NilaiAsli, rm.AnggotaKelasID, *rm.TujuanPembelajaranID, rm.NilaiEfektif())
+	} else {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE nilai_sumatif_siswa SET nilai = $1, updated_at = NOW()
+			WHERE anggota_kelas_id = $2 AND penilaian_sumatif_id = $3 AND nilai = $4`,
+			rm.NilaiAsli, rm.AnggotaKelasID, *rm.PenilaianSumatifID, rm.NilaiEfektif())
+	}
+	if err != nil {
+		return fmt.Errorf("gagal mengembalikan nilai asli: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM remedial WHERE id = $1`, rm.ID); err != nil {
+		return fmt.Errorf("gagal menghapus remedial: %w", err)
+	}
+	return tx.Commit()
+}
+
+func setNilai(ctx context.Context, tx *sql.Tx, rm *Remedial, nilai float64) error {
+	var err error
+	if rm.TujuanPembelajaranID != nil {
+		_, err = tx.ExecContext(ctx,
+			`UPDATE penilaian SET nilai = $1, updated_at = NOW() WHERE anggota_kelas_id = $2 AND tujuan_pembelajaran_id = $3`,
+			nilai, rm.AnggotaKelasID, *rm.TujuanPembelajaranID)
+	} else {
+		_, err = tx.ExecContext(ctx,
+			`UPDATE nilai_sumatif_siswa SET nilai = $1, updated_at = NOW() WHERE anggota_kelas_id = $2 AND penilaian_sumatif_id = $3`,
+			nilai, rm.AnggotaKelasID, *rm.PenilaianSumatifID)
+	}
+	if err != nil {
+		return fmt.Errorf("gagal memperbarui nilai yang dipakai: %w", err)
+	}
+	return nil
+}
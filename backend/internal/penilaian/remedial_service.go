@@ -0,0 +1,149 @@
+// file: backend/internal/penilaian/remedial_service.go
+package penilaian
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"skoola/internal/access"
+	"skoola/internal/audit"
+	"skoola/internal/kuncinilai"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// RemedialService mendefinisikan logika bisnis remedial. Nilai asli selalu disimpan;
+// nilai yang dipakai ditulis ke tabel nilai sehingga nilai akhir dan rapor ikut berubah.
+type RemedialService interface {
+	GetByPengajar(ctx context.Context, schemaName string, actor access.Actor, kelasID string, pengajarKelasID string) ([]Remedial, error)
+	Upsert(ctx context.Context, schemaName string, actor access.Actor, input UpsertRemedialInput) (*Remedial, error)
+	Delete(ctx context.Context, schemaName string, actor access.Actor, id string) error
+}
+
+type remedialService struct {
+	repo     RemedialRepository
+	kunci    kuncinilai.Service
+	access   access.Service
+	audit    audit.Recorder
+	validate *validator.Validate
+}
+
+// NewRemedialService membuat instance baru dari RemedialService.
+func NewRemedialService(repo RemedialRepository, kunciService kuncinilai.Service, accessService access.Service, auditLog audit.Recorder, validate *validator.Validate) RemedialService {
+	return &remedialService{repo: repo, kunci: kunciService, access: accessService, audit: auditLog, validate: validate}
+}
+
+func (s *remedialService) GetByPengajar(ctx context.Context, schemaName string, actor access.Actor, kelasID string, pengajarKelasID string) ([]Remedial, error) {
+	target := access.Target{PengajarKelasIDs: []string{pengajarKelasID}, KelasIDs: []string{kelasID}}
+	if err := s.access.AuthorizeRead(ctx, schemaName, actor, target); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByPengajar(ctx, schemaName, kelasID, pengajarKelasID)
+}
+
+func (s *remedialService) Upsert(ctx context.Context, schemaName string, actor access.Actor, input UpsertRemedialInput) (*Remedial, error) {
+	if err := s.validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	if (input.TujuanPembelajaranID == nil) == (input.PenilaianSumatifID == nil) {
+		return nil, fmt.Errorf("%w: isi salah satu dari tujuan_pembelajaran_id atau penilaian_sumatif_id", ErrValidation)
+	}
+	pascaKunci, err := s.authorizeWrite(ctx, schemaName, actor, input.AnggotaKelasID, input.TujuanPembelajaranID, input.PenilaianSumatifID)
+	if err != nil {
+		return nil, err
+	}
+
+	tanggal := time.Now()
+	if input.Tanggal != "" {
+		tanggal, _ = time.Parse("2006-01-02", input.Tanggal)
+	}
+	var catatan *string
+	if c := strings.TrimSpace(input.Catatan); c != "" {
+		catatan = &c
+	}
+
+	existing, err := s.repo.GetByNilai(ctx, schemaName, input.AnggotaKelasID, input.TujuanPembelajaranID, input.PenilaianSumatifID)
+	if err != nil {
+		return nil, err
+	}
+
+	rm := &Remedial{
+		AnggotaKelasID:       input.AnggotaKelasID,
+		TujuanPembelajaranID: input.TujuanPembelajaranID,
+		PenilaianSumatifID:   input.PenilaianSumatifID,
+		NilaiRemedial:        *input.NilaiRemedial,
+		NilaiDipakai:         input.NilaiDipakai,
+		Tanggal:              tanggal,
+		Catatan:              catatan,
+	}
+	if existing != nil {
+		// Nilai asli tidak pernah ditimpa oleh remedial berikutnya.
+		rm.ID = existing.ID
+		rm.NilaiAsli = existing.NilaiAsli
+	} else {
+		asli, err := s.repo.GetNilaiSaatIni(ctx, schemaName, input.AnggotaKelasID, input.TujuanPembelajaranID, input.PenilaianSumatifID)
+		if err != nil {
+			return nil, err
+		}
+		if asli == nil {
+			return nil, fmt.Errorf("%w: nilai asli belum diisi, remedial belum dapat dicatat", ErrValidation)
+		}
+		rm.NilaiAsli = *asli
+	}
+
+	saved, err := s.repo.Save(ctx, schemaName, rm)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := audit.Entry{Action: audit.ActionUpdate, Entity: "remedial", EntityID: saved.ID, Before: existing, After: saved}
+	if existing == nil {
+		entry = audit.Entry{Action: audit.ActionCreate, Entity: "remedial", EntityID: saved.ID, After: saved}
+	}
+	s.audit.Record(ctx, schemaName, entry)
+	s.kunci.RecordPascaKunci(ctx, schemaName, pascaKunci, entry)
+	return saved, nil
+}
+
+// Delete menghapus remedial dan mengembalikan nilai asli sebagai nilai yang dipakai.
+func (s *remedialService) Delete(ctx context.Context, schemaName string, actor access.Actor, id string) error {
+	existing, err := s.repo.GetByID(ctx, schemaName, id)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return sql.ErrNoRows
+	}
+	pascaKunci, err := s.authorizeWrite(ctx, schemaName, actor, existing.AnggotaKelasID, existing.TujuanPembelajaranID, existing.PenilaianSumatifID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, schemaName, existing); err != nil {
+		return err
+	}
+	entry := audit.Entry{Action: audit.ActionDelete, Entity: "remedial", EntityID: id, Before: existing}
+	s.audit.Record(ctx, schemaName, entry)
+	s.kunci.RecordPascaKunci(ctx, schemaName, pascaKunci, entry)
+	return nil
+}
+
+// authorizeWrite memakai aturan yang sama dengan pengisian nilai: guru hanya boleh
+// mengubah nilai untuk TP/penilaian miliknya dan siswa di kelas yang ia ajar, dan
+// nilai yang sudah dikunci tidak boleh diubah. Hasilnya adalah pengajar kelas yang
+// dibuka kembali setelah finalisasi.
+func (s *remedialService) authorizeWrite(ctx context.Context, schemaName string, actor access.Actor, anggotaKelasID string, tpID *int, penilaianSumatifID *string) ([]string, error) {
+	target := access.Target{AnggotaKelasIDs: []string{anggotaKelasID}}
+	if tpID != nil {
+		target.TujuanIDs = []int{*tpID}
+	}
+	if penilaianSumatifID != nil {
+		target.PenilaianSumatifIDs = []string{*penilaianSumatifID}
+	}
+	if err := s.access.AuthorizeWrite(ctx, schemaName, actor, target); err != nil {
+		return nil, err
+	}
+	return s.kunci.EnsureUnlocked(ctx, schemaName, target)
+}
@@ -6,6 +6,7 @@ import (
 	"database/sql"
 	"fmt"
 	"skoola/internal/pembelajaran" // Import paket pembelajaran
+	"skoola/pkg/database"
 	"time"
 
 	"github.com/lib/pq"
@@ -15,6 +16,7 @@ import (
 type Repository interface {
 	GetPenilaianLengkap(ctx context.Context, schemaName string, kelasID string, pengajarKelasID string) (*FullPenilaianData, []pembelajaran.RencanaPembelajaranItem, error)
 	UpsertNilaiBulk(ctx context.Context, schemaName string, input BulkUpsertNilaiInput) error
+	GetNilaiSnapshots(ctx context.Context, schemaName string, input BulkUpsertNilaiInput) (map[string]*NilaiSnapshot, error)
 }
 
 type postgresRepository struct {
@@ -26,19 +28,16 @@ func NewRepository(db *sql.DB) Repository {
 	return &postgresRepository{db: db}
 }
 
-func (r *postgresRepository) setSchema(ctx context.Context, schemaName string) error {
-	_, err := r.db.ExecContext(ctx, fmt.Sprintf("SET search_path TO %q", schemaName))
-	return err
-}
-
 func (r *postgresRepository) GetPenilaianLengkap(ctx context.Context, schemaName string, kelasID string, pengajarKelasID string) (*FullPenilaianData, []pembelajaran.RencanaPembelajaranItem, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, nil, err
 	}
+	defer tx.Rollback()
 
 	// 1. Ambil struktur Rencana Pembelajaran (Materi & Ujian)
 	pembelajaranRepo := pembelajaran.NewRepository(r.db)
-	rencanaList, err := pembelajaranRepo.GetAllRencanaPembelajaran(ctx, schemaName, pengajarKelasID)
+	rencanaList, err := pembelajaranRepo.GetAllRencanaPembelajaranTx(ctx, tx, pengajarKelasID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("gagal mengambil struktur rencana pembelajaran: %w", err)
 	}
@@ -51,7 +50,7 @@ func (r *postgresRepository) GetPenilaianLengkap(ctx context.Context, schemaName
 		WHERE ak.kelas_id = $1
 		ORDER BY ak.urutan ASC, s.nama_lengkap ASC
 	`
-	rowsSiswa, err := r.db.QueryContext(ctx, siswaQuery, kelasID)
+	rowsSiswa, err := tx.QueryContext(ctx, siswaQuery, kelasID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("gagal mengambil data siswa: %w", err)
 	}
@@ -74,7 +73,7 @@ func (r *postgresRepository) GetPenilaianLengkap(ctx context.Context, schemaName
 	}
 
 	if len(siswaList) == 0 {
-		return &FullPenilaianData{Siswa: siswaList}, rencanaList, nil
+		return &FullPenilaianData{Siswa: siswaList}, rencanaList, tx.Commit()
 	}
 
 	siswaMap := make(map[string]*PenilaianSiswaData)
@@ -86,11 +85,14 @@ func (r *postgresRepository) GetPenilaianLengkap(ctx context.Context, schemaName
 
 	// 3. Ambil nilai formatif yang sudah ada
 	nilaiFormatifQuery := `
-		SELECT anggota_kelas_id, tujuan_pembelajaran_id, nilai, updated_at
-		FROM penilaian
-		WHERE anggota_kelas_id = ANY($1)
+		SELECT p.anggota_kelas_id, p.tujuan_pembelajaran_id, p.nilai, p.updated_at,
+		       rm.id, rm.nilai_asli::float8, rm.nilai_remedial::float8, rm.nilai_dipakai
+		FROM penilaian p
+		LEFT JOIN remedial rm ON rm.anggota_kelas_id = p.anggota_kelas_id
+			AND rm.tujuan_pembelajaran_id = p.tujuan_pembelajaran_id
+		WHERE p.anggota_kelas_id = ANY($1)
 	`
-	rowsNilaiFormatif, err := r.db.QueryContext(ctx, nilaiFormatifQuery, pq.Array(anggotaKelasIDs))
+	rowsNilaiFormatif, err := tx.QueryContext(ctx, nilaiFormatifQuery, pq.Array(anggotaKelasIDs))
 	if err != nil {
 		return nil, nil, fmt.Errorf("gagal mengambil data nilai formatif: %w", err)
 	}
@@ -101,7 +103,8 @@ func (r *postgresRepository) GetPenilaianLengkap(ctx context.Context, schemaName
 		var tpID int
 		var nilai sql.NullFloat64
 		var updatedAt time.Time
-		if err := rowsNilaiFormatif.Scan(&anggotaID, &tpID, &nilai, &updatedAt); err != nil {
+		var rm remedialRow
+		if err := rowsNilaiFormatif.Scan(&anggotaID, &tpID, &nilai, &updatedAt, &rm.ID, &rm.NilaiAsli, &rm.NilaiRemedial, &rm.NilaiDipakai); err != nil {
 			return nil, nil, fmt.Errorf("gagal memindai data nilai formatif: %w", err)
 		}
 		if siswa, ok := siswaMap[anggotaID]; ok {
@@ -109,7 +112,7 @@ func (r *postgresRepository) GetPenilaianLengkap(ctx context.Context, schemaName
 			if nilai.Valid {
 				nilaiPtr = &nilai.Float64
 			}
-			siswa.NilaiFormatif[tpID] = NilaiSiswa{Nilai: nilaiPtr, UpdatedAt: &updatedAt}
+			siswa.NilaiFormatif[tpID] = NilaiSiswa{Nilai: nilaiPtr, UpdatedAt: &updatedAt, Remedial: rm.info()}
 		}
 		if lastUpdated == nil || updatedAt.After(*lastUpdated) {
 			lastUpdated = &updatedAt
@@ -118,11 +121,14 @@ func (r *postgresRepository) GetPenilaianLengkap(ctx context.Context, schemaName
 
 	// 4. Ambil nilai sumatif yang sudah ada
 	nilaiSumatifQuery := `
-		SELECT anggota_kelas_id, penilaian_sumatif_id, nilai, updated_at
-		FROM nilai_sumatif_siswa
-		WHERE anggota_kelas_id = ANY($1)
+		SELECT ns.anggota_kelas_id, ns.penilaian_sumatif_id, ns.nilai, ns.updated_at,
+		       rm.id, rm.nilai_asli::float8, rm.nilai_remedial::float8, rm.nilai_dipakai
+		FROM nilai_sumatif_siswa ns
+		LEFT JOIN remedial rm ON rm.anggota_kelas_id = ns.anggota_kelas_id
+			AND rm.penilaian_sumatif_id = ns.penilaian_sumatif_id
+		WHERE ns.anggota_kelas_id = ANY($1)
 	`
-	rowsNilaiSumatif, err := r.db.QueryContext(ctx, nilaiSumatifQuery, pq.Array(anggotaKelasIDs))
+	rowsNilaiSumatif, err := tx.QueryContext(ctx, nilaiSumatifQuery, pq.Array(anggotaKelasIDs))
 	if err != nil {
 		return nil, nil, fmt.Errorf("gagal mengambil data nilai sumatif: %w", err)
 	}
@@ -132,7 +138,8 @@ func (r *postgresRepository) GetPenilaianLengkap(ctx context.Context, schemaName
 		var anggotaID, psID string
 		var nilai sql.NullFloat64
 		var updatedAt time.Time
-		if err := rowsNilaiSumatif.Scan(&anggotaID, &psID, &nilai, &updatedAt); err != nil {
+		var rm remedialRow
+		if err := rowsNilaiSumatif.Scan(&anggotaID, &psID, &nilai, &updatedAt, &rm.ID, &rm.NilaiAsli, &rm.NilaiRemedial, &rm.NilaiDipakai); err != nil {
 			return nil, nil, fmt.Errorf("gagal memindai data nilai sumatif: %w", err)
 		}
 		if siswa, ok := siswaMap[anggotaID]; ok {
@@ -140,24 +147,26 @@ func (r *postgresRepository) GetPenilaianLengkap(ctx context.Context, schemaName
 			if nilai.Valid {
 				nilaiPtr = &nilai.Float64
 			}
-			siswa.NilaiSumatif[psID] = NilaiSumatifSiswa{Nilai: nilaiPtr, UpdatedAt: &updatedAt}
+			siswa.NilaiSumatif[psID] = NilaiSumatifSiswa{Nilai: nilaiPtr, UpdatedAt: &updatedAt, Remedial: rm.info()}
 		}
 		if lastUpdated == nil || updatedAt.After(*lastUpdated) {
 			lastUpdated = &updatedAt
 		}
 	}
 
-	return &FullPenilaianData{Siswa: siswaList, LastUpdated: lastUpdated}, rencanaList, nil
+	return &FullPenilaianData{Siswa: siswaList, LastUpdated: lastUpdated}, rencanaList, tx.Commit()
 }
 
-func (r *postgresRepository) UpsertNilaiBulk(ctx context.Context, schemaName string, input BulkUpsertNilaiInput) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
-		return err
-	}
+// efektifRemedial adalah nilai yang ditulis remedial ke kolom nilai asalnya.
+const efektifRemedial = "CASE rm.nilai_dipakai WHEN 'ASLI' THEN rm.nilai_asli ELSE rm.nilai_remedial END"
 
-	tx, err := r.db.BeginTx(ctx, nil)
+// UpsertNilaiBulk menyimpan nilai dari grid. Jika nilai sel yang punya remedial diubah
+// (berbeda dari nilai yang ditulis remedial), nilai baru guru menggantikan remedial
+// tersebut sehingga remedialnya dihapus.
+func (r *postgresRepository) UpsertNilaiBulk(ctx context.Context, schemaName string, input BulkUpsertNilaiInput) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
 	if err != nil {
-		return fmt.Errorf("gagal memulai transaksi: %w", err)
+		return err
 	}
 	defer tx.Rollback()
 
@@ -173,10 +182,22 @@ func (r *postgresRepository) UpsertNilaiBulk(ctx context.Context, schemaName str
 			return fmt.Errorf("gagal mempersiapkan statement formatif: %w", err)
 		}
 		defer stmtFormatif.Close()
+		stmtRemedial, err := tx.PrepareContext(ctx, `
+			DELETE FROM remedial rm
+			WHERE rm.anggota_kelas_id = $1 AND rm.tujuan_pembelajaran_id = $2
+			  AND `+efektifRemedial+` IS DISTINCT FROM ROUND($3::numeric, 2)
+		`)
+		if err != nil {
+			return fmt.Errorf("gagal mempersiapkan statement remedial formatif: %w", err)
+		}
+		defer stmtRemedial.Close()
 		for _, p := range input.NilaiFormatif {
 			if _, err := stmtFormatif.ExecContext(ctx, p.AnggotaKelasID, p.TujuanPembelajaranID, p.Nilai); err != nil {
 				return fmt.Errorf("gagal upsert nilai formatif: %w", err)
 			}
+			if _, err := stmtRemedial.ExecContext(ctx, p.AnggotaKelasID, p.TujuanPembelajaranID, p.Nilai); err != nil {
+				return fmt.Errorf("gagal menghapus remedial yang tergantikan: %w", err)
+			}
 		}
 	}
 
@@ -192,12 +213,130 @@ func (r *postgresRepository) UpsertNilaiBulk(ctx context.Context, schemaName str
 			return fmt.Errorf("gagal mempersiapkan statement sumatif: %w", err)
 		}
 		defer stmtSumatif.Close()
+		stmtRemedial, err := tx.PrepareContext(ctx, `
+			DELETE FROM remedial rm
+			WHERE rm.anggota_kelas_id = $1 AND rm.penilaian_sumatif_id = $2
+			  AND `+efektifRemedial+` IS DISTINCT FROM ROUND($3::numeric, 2)
+		`)
+		if err != nil {
+			return fmt.Errorf("gagal mempersiapkan statement remedial sumatif: %w", err)
+		}
+		defer stmtRemedial.Close()
 		for _, p := range input.NilaiSumatif {
 			if _, err := stmtSumatif.ExecContext(ctx, p.AnggotaKelasID, p.PenilaianSumatifID, p.Nilai); err != nil {
 				return fmt.Errorf("gagal upsert nilai sumatif: %w", err)
 			}
+			if _, err := stmtRemedial.ExecContext(ctx, p.AnggotaKelasID, p.PenilaianSumatifID, p.Nilai); err != nil {
+				return fmt.Errorf("gagal menghapus remedial yang tergantikan: %w", err)
+			}
 		}
 	}
 
 	return tx.Commit()
 }
+
+// GetNilaiSnapshots mengambil nilai yang sudah tersimpan untuk semua siswa, TP dan
+// penilaian sumatif yang dirujuk input. Hasilnya dikelompokkan per anggota_kelas_id.
+func (r *postgresRepository) GetNilaiSnapshots(ctx context.Context, schemaName string, input BulkUpsertNilaiInput) (map[string]*NilaiSnapshot, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	snapshots := make(map[string]*NilaiSnapshot)
+	get := func(anggotaID string) *NilaiSnapshot {
+		if snapshots[anggotaID] == nil {
+			snapshots[anggotaID] = &NilaiSnapshot{}
+		}
+		return snapshots[anggotaID]
+	}
+
+	if len(input.NilaiFormatif) > 0 {
+		var anggotaIDs []string
+		var tpIDs []int
+		for _, n := range input.NilaiFormatif {
+			anggotaIDs = append(anggotaIDs, n.AnggotaKelasID)
+			tpIDs = append(tpIDs, n.TujuanPembelajaranID)
+		}
+		rows, err := tx.QueryContext(ctx, `
+			SELECT anggota_kelas_id, tujuan_pembelajaran_id, nilai
+			FROM penilaian
+			WHERE anggota_kelas_id = ANY($1) AND tujuan_pembelajaran_id = ANY($2)
+		`, pq.Array(anggotaIDs), pq.Array(tpIDs))
+		if err != nil {
+			return nil, fmt.Errorf("gagal mengambil nilai formatif lama: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var anggotaID string
+			var tpID int
+			var nilai *float64
+			if err := rows.Scan(&anggotaID, &tpID, &nilai); err != nil {
+				return nil, fmt.Errorf("gagal memindai nilai formatif lama: %w", err)
+			}
+			snap := get(anggotaID)
+			if snap.NilaiFormatif == nil {
+				snap.NilaiFormatif = make(map[int]*float64)
+			}
+			snap.NilaiFormatif[tpID] = nilai
+		}
+		if err = rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(input.NilaiSumatif) > 0 {
+		var anggotaIDs, psIDs []string
+		for _, n := range input.NilaiSumatif {
+			anggotaIDs = append(anggotaIDs, n.AnggotaKelasID)
+			psIDs = append(psIDs, n.PenilaianSumatifID)
+		}
+		rows, err := tx.QueryContext(ctx, `
+			SELECT anggota_kelas_id, penilaian_sumatif_id, nilai
+			FROM nilai_sumatif_siswa
+			WHERE anggota_kelas_id = ANY($1) AND penilaian_sumatif_id = ANY($2)
+		`, pq.Array(anggotaIDs), pq.Array(psIDs))
+		if err != nil {
+			return nil, fmt.Errorf("gagal mengambil nilai sumatif lama: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var anggotaID, psID string
+			var nilai *float64
+			if err := rows.Scan(&anggotaID, &psID, &nilai); err != nil {
+				return nil, fmt.Errorf("gagal memindai nilai sumatif lama: %w", err)
+			}
+			snap := get(anggotaID)
+			if snap.NilaiSumatif == nil {
+				snap.NilaiSumatif = make(map[string]*float64)
+			}
+			snap.NilaiSumatif[psID] = nilai
+		}
+		if err = rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return snapshots, tx.Commit()
+}
+
+// remedialRow menampung kolom remedial hasil LEFT JOIN yang bisa bernilai NULL.
+type remedialRow struct {
+	ID            sql.NullString
+	NilaiAsli     sql.NullFloat64
+	NilaiRemedial sql.NullFloat64
+	NilaiDipakai  sql.NullString
+}
+
+func (r remedialRow) info() *RemedialInfo {
+	if !r.ID.Valid {
+		return nil
+	}
+	return &RemedialInfo{
+		ID:            r.ID.String,
+		NilaiAsli:     r.NilaiAsli.Float64,
+		NilaiRemedial: r.NilaiRemedial.Float64,
+		NilaiDipakai:  r.NilaiDipakai.String,
+	}
+}
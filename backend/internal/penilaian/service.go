@@ -5,6 +5,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"skoola/internal/access"
+	"skoola/internal/audit"
+	"skoola/internal/kktp"
+	"skoola/internal/kuncinilai"
+	"skoola/internal/pembelajaran"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -13,42 +18,187 @@ var ErrValidation = errors.New("validation failed")
 
 // Service mendefinisikan interface untuk logika bisnis.
 type Service interface {
-	GetPenilaianLengkap(ctx context.Context, schemaName string, kelasID string, pengajarKelasID string) (map[string]interface{}, error)
-	UpsertNilaiBulk(ctx context.Context, schemaName string, input BulkUpsertNilaiInput) error
+	GetPenilaianLengkap(ctx context.Context, schemaName string, actor access.Actor, kelasID string, pengajarKelasID string) (map[string]interface{}, error)
+	UpsertNilaiBulk(ctx context.Context, schemaName string, actor access.Actor, input BulkUpsertNilaiInput) error
 }
 
 type service struct {
 	repo     Repository
+	kktp     kktp.Service
+	kunci    kuncinilai.Service
+	access   access.Service
+	audit    audit.Recorder
 	validate *validator.Validate
 }
 
 // NewService membuat instance baru dari service.
-func NewService(repo Repository, validate *validator.Validate) Service {
-	return &service{repo: repo, validate: validate}
+func NewService(repo Repository, kktpService kktp.Service, kunciService kuncinilai.Service, accessService access.Service, auditLog audit.Recorder, validate *validator.Validate) Service {
+	return &service{repo: repo, kktp: kktpService, kunci: kunciService, access: accessService, audit: auditLog, validate: validate}
 }
 
-func (s *service) GetPenilaianLengkap(ctx context.Context, schemaName string, kelasID string, pengajarKelasID string) (map[string]interface{}, error) {
+func (s *service) GetPenilaianLengkap(ctx context.Context, schemaName string, actor access.Actor, kelasID string, pengajarKelasID string) (map[string]interface{}, error) {
 	if kelasID == "" || pengajarKelasID == "" {
 		return nil, errors.New("kelasID dan pengajarKelasID tidak boleh kosong")
 	}
 
+	// Pengajar mapel dan wali kelas boleh melihat nilai kelas ini.
+	target := access.Target{PengajarKelasIDs: []string{pengajarKelasID}, KelasIDs: []string{kelasID}}
+	if err := s.access.AuthorizeRead(ctx, schemaName, actor, target); err != nil {
+		return nil, err
+	}
+
 	penilaianData, rencanaData, err := s.repo.GetPenilaianLengkap(ctx, schemaName, kelasID, pengajarKelasID)
 	if err != nil {
 		return nil, err
 	}
 
+	ambang, err := s.kktp.GetAmbang(ctx, schemaName, pengajarKelasID)
+	if err != nil {
+		return nil, err
+	}
+	tandaiKetuntasan(penilaianData, rencanaData, *ambang)
+
 	// Gabungkan kedua hasil ke dalam satu map untuk dikirim sebagai JSON
 	response := map[string]interface{}{
 		"penilaian": penilaianData,
 		"rencana":   rencanaData, // <-- PERBAIKAN: Mengganti "materi" menjadi "rencana"
+		"kktp":      ambang,
 	}
 
 	return response, nil
 }
 
-func (s *service) UpsertNilaiBulk(ctx context.Context, schemaName string, input BulkUpsertNilaiInput) error {
+// tandaiKetuntasan mengisi flag Tuntas setiap nilai berdasarkan KKTP. Nilai sumatif
+// memakai KKTP TP induknya; nilai ujian (tanpa TP) memakai KKTP mapel.
+func tandaiKetuntasan(data *FullPenilaianData, rencana []pembelajaran.RencanaPembelajaranItem, ambang kktp.Ambang) {
+	tpSumatif := make(map[string]*int)
+	for _, item := range rencana {
+		for _, ps := range item.PenilaianSumatif {
+			tpSumatif[ps.ID] = ps.TujuanPembelajaranID
+		}
+		for _, tp := range item.TujuanPembelajaran {
+			for _, ps := range tp.PenilaianSumatif {
+				tpSumatif[ps.ID] = ps.TujuanPembelajaranID
+			}
+		}
+	}
+
+	for i := range data.Siswa {
+		for tpID, n := range data.Siswa[i].NilaiFormatif {
+			id := tpID
+			n.Tuntas = kktp.Tuntas(n.Nilai, ambang.UntukTP(&id))
+			data.Siswa[i].NilaiFormatif[tpID] = n
+		}
+		for psID, n := range data.Siswa[i].NilaiSumatif {
+			n.Tuntas = kktp.Tuntas(n.Nilai, ambang.UntukTP(tpSumatif[psID]))
+			data.Siswa[i].NilaiSumatif[psID] = n
+		}
+	}
+}
+
+func (s *service) UpsertNilaiBulk(ctx context.Context, schemaName string, actor access.Actor, input BulkUpsertNilaiInput) error {
 	if err := s.validate.Struct(input); err != nil {
 		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
 	}
-	return s.repo.UpsertNilaiBulk(ctx, schemaName, input)
+
+	// Guru hanya boleh mengisi nilai untuk TP dan penilaian miliknya,
+	// dan hanya untuk siswa di kelas yang ia ajar.
+	var target access.Target
+	for _, n := range input.NilaiFormatif {
+		target.TujuanIDs = append(target.TujuanIDs, n.TujuanPembelajaranID)
+		target.AnggotaKelasIDs = append(target.AnggotaKelasIDs, n.AnggotaKelasID)
+	}
+	for _, n := range input.NilaiSumatif {
+		target.PenilaianSumatifIDs = append(target.PenilaianSumatifIDs, n.PenilaianSumatifID)
+		target.AnggotaKelasIDs = append(target.AnggotaKelasIDs, n.AnggotaKelasID)
+	}
+	if err := s.access.AuthorizeWrite(ctx, schemaName, actor, target); err != nil {
+		return err
+	}
+	pascaKunci, err := s.kunci.EnsureUnlocked(ctx, schemaName, target)
+	if err != nil {
+		return err
+	}
+
+	before, err := s.repo.GetNilaiSnapshots(ctx, schemaName, input)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.UpsertNilaiBulk(ctx, schemaName, input); err != nil {
+		return err
+	}
+	entries := nilaiAuditEntries(before, input)
+	s.audit.Record(ctx, schemaName, entries...)
+	if len(entries) > 0 {
+		s.kunci.RecordPascaKunci(ctx, schemaName, pascaKunci, entries)
+	}
+	return nil
+}
+
+// nilaiAuditEntries membuat satu entri audit per siswa yang nilainya berubah.
+// Before dan After hanya berisi sel nilai yang berubah.
+func nilaiAuditEntries(before map[string]*NilaiSnapshot, input BulkUpsertNilaiInput) []audit.Entry {
+	changesBefore := make(map[string]*NilaiSnapshot)
+	changesAfter := make(map[string]*NilaiSnapshot)
+	var order []string
+	track := func(anggotaID string) (*NilaiSnapshot, *NilaiSnapshot) {
+		if changesBefore[anggotaID] == nil {
+			changesBefore[anggotaID] = &NilaiSnapshot{}
+			changesAfter[anggotaID] = &NilaiSnapshot{}
+			order = append(order, anggotaID)
+		}
+		return changesBefore[anggotaID], changesAfter[anggotaID]
+	}
+
+	for _, n := range input.NilaiFormatif {
+		var old *float64
+		if snap := before[n.AnggotaKelasID]; snap != nil {
+			old = snap.NilaiFormatif[n.TujuanPembelajaranID]
+		}
+		if sameNilai(old, n.Nilai) {
+			continue
+		}
+		b, a := track(n.AnggotaKelasID)
+		if b.NilaiFormatif == nil {
+			b.NilaiFormatif = make(map[int]*float64)
+			a.NilaiFormatif = make(map[int]*float64)
+		}
+		b.NilaiFormatif[n.TujuanPembelajaranID] = old
+		a.NilaiFormatif[n.TujuanPembelajaranID] = n.Nilai
+	}
+	for _, n := range input.NilaiSumatif {
+		var old *float64
+		if snap := before[n.AnggotaKelasID]; snap != nil {
+			old = snap.NilaiSumatif[n.PenilaianSumatifID]
+		}
+		if sameNilai(old, n.Nilai) {
+			continue
+		}
+		b, a := track(n.AnggotaKelasID)
+		if b.NilaiSumatif == nil {
+			b.NilaiSumatif = make(map[string]*float64)
+			a.NilaiSumatif = make(map[string]*float64)
+		}
+		b.NilaiSumatif[n.PenilaianSumatifID] = old
+		a.NilaiSumatif[n.PenilaianSumatifID] = n.Nilai
+	}
+
+	entries := make([]audit.Entry, 0, len(order))
+	for _, anggotaID := range order {
+		entries = append(entries, audit.Entry{
+			Action:   audit.ActionUpdate,
+			Entity:   "penilaian",
+			EntityID: anggotaID,
+			Before:   changesBefore[anggotaID],
+			After:    changesAfter[anggotaID],
+		})
+	}
+	return entries
+}
+
+func sameNilai(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
 }
@@ -3,7 +3,10 @@ package penilaiansumatif
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"skoola/internal/access"
+	"skoola/internal/kuncinilai"
 	"skoola/internal/middleware"
 	"strconv"
 
@@ -26,8 +29,16 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := h.service.Create(r.Context(), schemaName, input)
+	result, err := h.service.Create(r.Context(), schemaName, access.ActorFromContext(r.Context()), input)
 	if err != nil {
+		if errors.Is(err, access.ErrForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		if errors.Is(err, kuncinilai.ErrLocked) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		http.Error(w, "Gagal membuat penilaian: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -46,7 +57,15 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.Update(r.Context(), schemaName, id, input); err != nil {
+	if err := h.service.Update(r.Context(), schemaName, access.ActorFromContext(r.Context()), id, input); err != nil {
+		if errors.Is(err, access.ErrForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		if errors.Is(err, kuncinilai.ErrLocked) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		http.Error(w, "Gagal memperbarui penilaian: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -56,7 +75,15 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
 	id := chi.URLParam(r, "id")
-	if err := h.service.Delete(r.Context(), schemaName, id); err != nil {
+	if err := h.service.Delete(r.Context(), schemaName, access.ActorFromContext(r.Context()), id); err != nil {
+		if errors.Is(err, access.ErrForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		if errors.Is(err, kuncinilai.ErrLocked) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		http.Error(w, "Gagal menghapus penilaian: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -71,8 +98,12 @@ func (h *Handler) GetByTujuanPembelajaranID(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	result, err := h.service.GetByTujuanPembelajaranID(r.Context(), schemaName, tpID)
+	result, err := h.service.GetByTujuanPembelajaranID(r.Context(), schemaName, access.ActorFromContext(r.Context()), tpID)
 	if err != nil {
+		if errors.Is(err, access.ErrForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		http.Error(w, "Gagal mengambil data penilaian: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"skoola/pkg/database"
 
 	"github.com/google/uuid"
 )
@@ -26,32 +27,31 @@ func NewRepository(db *sql.DB) Repository {
 	return &postgresRepository{db: db}
 }
 
-func (r *postgresRepository) setSchema(ctx context.Context, schemaName string) error {
-	_, err := r.db.ExecContext(ctx, fmt.Sprintf("SET search_path TO %q", schemaName))
-	return err
-}
-
 func (r *postgresRepository) Create(ctx context.Context, schemaName string, ps *PenilaianSumatif) (*PenilaianSumatif, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 	ps.ID = uuid.New().String()
 	query := `
 		INSERT INTO penilaian_sumatif (id, tujuan_pembelajaran_id, ujian_id, jenis_ujian_id, nama_penilaian, tanggal_pelaksanaan, keterangan)
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING created_at, updated_at
 	`
-	err := r.db.QueryRowContext(ctx, query, ps.ID, ps.TujuanPembelajaranID, ps.UjianID, ps.JenisUjianID, ps.NamaPenilaian, ps.TanggalPelaksanaan, ps.Keterangan).Scan(&ps.CreatedAt, &ps.UpdatedAt)
+	err = tx.QueryRowContext(ctx, query, ps.ID, ps.TujuanPembelajaranID, ps.UjianID, ps.JenisUjianID, ps.NamaPenilaian, ps.TanggalPelaksanaan, ps.Keterangan).Scan(&ps.CreatedAt, &ps.UpdatedAt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create penilaian sumatif: %w", err)
 	}
-	return ps, nil
+	return ps, tx.Commit()
 }
 
 func (r *postgresRepository) Update(ctx context.Context, schemaName string, ps *PenilaianSumatif) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 	query := `
 		UPDATE penilaian_sumatif SET
 			jenis_ujian_id = $1,
@@ -61,22 +61,32 @@ func (r *postgresRepository) Update(ctx context.Context, schemaName string, ps *
 			updated_at = NOW()
 		WHERE id = $5
 	`
-	_, err := r.db.ExecContext(ctx, query, ps.JenisUjianID, ps.NamaPenilaian, ps.TanggalPelaksanaan, ps.Keterangan, ps.ID)
-	return err
+	_, err = tx.ExecContext(ctx, query, ps.JenisUjianID, ps.NamaPenilaian, ps.TanggalPelaksanaan, ps.Keterangan, ps.ID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (r *postgresRepository) Delete(ctx context.Context, schemaName string, id string) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	_, err = tx.ExecContext(ctx, "DELETE FROM penilaian_sumatif WHERE id = $1", id)
+	if err != nil {
 		return err
 	}
-	_, err := r.db.ExecContext(ctx, "DELETE FROM penilaian_sumatif WHERE id = $1", id)
-	return err
+	return tx.Commit()
 }
 
 func (r *postgresRepository) GetByTujuanPembelajaranID(ctx context.Context, schemaName string, tpID int) ([]PenilaianSumatif, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `
 		SELECT 
@@ -89,7 +99,7 @@ func (r *postgresRepository) GetByTujuanPembelajaranID(ctx context.Context, sche
 		ORDER BY ps.tanggal_pelaksanaan ASC, ps.created_at ASC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, tpID)
+	rows, err := tx.QueryContext(ctx, query, tpID)
 	if err != nil {
 		return nil, err
 	}
@@ -107,5 +117,5 @@ func (r *postgresRepository) GetByTujuanPembelajaranID(ctx context.Context, sche
 		}
 		results = append(results, ps)
 	}
-	return results, nil
+	return results, tx.Commit()
 }
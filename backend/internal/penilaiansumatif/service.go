@@ -5,6 +5,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"skoola/internal/access"
+	"skoola/internal/kuncinilai"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -14,26 +16,35 @@ var ErrValidation = errors.New("validation failed")
 
 // Service defines the business logic interface.
 type Service interface {
-	Create(ctx context.Context, schemaName string, input UpsertPenilaianSumatifInput) (*PenilaianSumatif, error)
-	Update(ctx context.Context, schemaName string, id string, input UpsertPenilaianSumatifInput) error
-	Delete(ctx context.Context, schemaName string, id string) error
-	GetByTujuanPembelajaranID(ctx context.Context, schemaName string, tpID int) ([]PenilaianSumatif, error)
+	Create(ctx context.Context, schemaName string, actor access.Actor, input UpsertPenilaianSumatifInput) (*PenilaianSumatif, error)
+	Update(ctx context.Context, schemaName string, actor access.Actor, id string, input UpsertPenilaianSumatifInput) error
+	Delete(ctx context.Context, schemaName string, actor access.Actor, id string) error
+	GetByTujuanPembelajaranID(ctx context.Context, schemaName string, actor access.Actor, tpID int) ([]PenilaianSumatif, error)
 }
 
 type service struct {
 	repo     Repository
+	kunci    kuncinilai.Service
+	access   access.Service
 	validate *validator.Validate
 }
 
 // NewService creates a new service instance.
-func NewService(repo Repository, validate *validator.Validate) Service {
-	return &service{repo: repo, validate: validate}
+func NewService(repo Repository, kunciService kuncinilai.Service, accessService access.Service, validate *validator.Validate) Service {
+	return &service{repo: repo, kunci: kunciService, access: accessService, validate: validate}
 }
 
-func (s *service) Create(ctx context.Context, schemaName string, input UpsertPenilaianSumatifInput) (*PenilaianSumatif, error) {
+func (s *service) Create(ctx context.Context, schemaName string, actor access.Actor, input UpsertPenilaianSumatifInput) (*PenilaianSumatif, error) {
 	if err := s.validate.Struct(input); err != nil {
 		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
 	}
+	if err := s.access.AuthorizeWrite(ctx, schemaName, actor, parentTarget(input)); err != nil {
+		return nil, err
+	}
+	pascaKunci, err := s.kunci.EnsureUnlocked(ctx, schemaName, parentTarget(input))
+	if err != nil {
+		return nil, err
+	}
 
 	var tanggal *time.Time
 	if input.TanggalPelaksanaan != "" {
@@ -57,13 +68,28 @@ func (s *service) Create(ctx context.Context, schemaName string, input UpsertPen
 		Keterangan:           keterangan,
 	}
 
-	return s.repo.Create(ctx, schemaName, ps)
+	created, err := s.repo.Create(ctx, schemaName, ps)
+	if err != nil {
+		return nil, err
+	}
+	s.kunci.RecordPascaKunci(ctx, schemaName, pascaKunci, created)
+	return created, nil
 }
 
-func (s *service) Update(ctx context.Context, schemaName string, id string, input UpsertPenilaianSumatifInput) error {
+func (s *service) Update(ctx context.Context, schemaName string, actor access.Actor, id string, input UpsertPenilaianSumatifInput) error {
 	if err := s.validate.Struct(input); err != nil {
 		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
 	}
+	if err := s.access.AuthorizeWrite(ctx, schemaName, actor, access.Target{PenilaianSumatifIDs: []string{id}}); err != nil {
+		return err
+	}
+	// Penilaian tidak boleh diubah jika mapel asal atau mapel tujuannya sudah dikunci.
+	lockTarget := parentTarget(input)
+	lockTarget.PenilaianSumatifIDs = []string{id}
+	pascaKunci, err := s.kunci.EnsureUnlocked(ctx, schemaName, lockTarget)
+	if err != nil {
+		return err
+	}
 
 	var tanggal *time.Time
 	if input.TanggalPelaksanaan != "" {
@@ -88,13 +114,44 @@ func (s *service) Update(ctx context.Context, schemaName string, id string, inpu
 		Keterangan:           keterangan,
 	}
 
-	return s.repo.Update(ctx, schemaName, ps)
+	if err := s.repo.Update(ctx, schemaName, ps); err != nil {
+		return err
+	}
+	s.kunci.RecordPascaKunci(ctx, schemaName, pascaKunci, ps)
+	return nil
 }
 
-func (s *service) Delete(ctx context.Context, schemaName string, id string) error {
-	return s.repo.Delete(ctx, schemaName, id)
+func (s *service) Delete(ctx context.Context, schemaName string, actor access.Actor, id string) error {
+	target := access.Target{PenilaianSumatifIDs: []string{id}}
+	if err := s.access.AuthorizeWrite(ctx, schemaName, actor, target); err != nil {
+		return err
+	}
+	pascaKunci, err := s.kunci.EnsureUnlocked(ctx, schemaName, target)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, schemaName, id); err != nil {
+		return err
+	}
+	s.kunci.RecordPascaKunci(ctx, schemaName, pascaKunci, map[string]string{"penilaian_sumatif_id_dihapus": id})
+	return nil
 }
 
-func (s *service) GetByTujuanPembelajaranID(ctx context.Context, schemaName string, tpID int) ([]PenilaianSumatif, error) {
+func (s *service) GetByTujuanPembelajaranID(ctx context.Context, schemaName string, actor access.Actor, tpID int) ([]PenilaianSumatif, error) {
+	if err := s.access.AuthorizeRead(ctx, schemaName, actor, access.Target{TujuanIDs: []int{tpID}}); err != nil {
+		return nil, err
+	}
 	return s.repo.GetByTujuanPembelajaranID(ctx, schemaName, tpID)
 }
+
+// parentTarget mengembalikan TP atau ujian tempat penilaian sumatif akan dibuat.
+func parentTarget(input UpsertPenilaianSumatifInput) access.Target {
+	var target access.Target
+	if input.TujuanPembelajaranID != nil {
+		target.TujuanIDs = []int{*input.TujuanPembelajaranID}
+	}
+	if input.UjianID != nil {
+		target.UjianIDs = []int{*input.UjianID}
+	}
+	return target
+}
@@ -0,0 +1,295 @@
+// file: backend/internal/portal/handler.go
+package portal
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"skoola/internal/middleware"
+	"skoola/internal/pengumuman"
+	"skoola/internal/student"
+	"skoola/internal/walimurid"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	service           Service
+	studentService    student.Service
+	waliMuridService  walimurid.Service
+	pengumumanService pengumuman.Service
+}
+
+func NewHandler(s Service, studentService student.Service, waliMuridService walimurid.Service, pengumumanService pengumuman.Service) *Handler {
+	return &Handler{
+		service:           s,
+		studentService:    studentService,
+		waliMuridService:  waliMuridService,
+		pengumumanService: pengumumanService,
+	}
+}
+
+// currentUser mengambil schema dan ID user dari token.
+func currentUser(w http.ResponseWriter, r *http.Request) (schemaName string, userID string, ok bool) {
+	schemaName, ok = r.Context().Value(middleware.SchemaNameKey).(string)
+	if !ok || schemaName == "" {
+		http.Error(w, "Gagal mengidentifikasi tenant dari token", http.StatusUnauthorized)
+		return "", "", false
+	}
+	userID, ok = r.Context().Value(middleware.UserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "Gagal mengidentifikasi user dari token", http.StatusUnauthorized)
+		return "", "", false
+	}
+	return schemaName, userID, true
+}
+
+// currentStudent mengambil schema dan ID siswa milik pengguna yang login.
+// Jika gagal, respons error sudah ditulis dan ok bernilai false.
+func (h *Handler) currentStudent(w http.ResponseWriter, r *http.Request) (schemaName string, studentID string, ok bool) {
+	schemaName, userID, ok := currentUser(w, r)
+	if !ok {
+		return "", "", false
+	}
+
+	studentID, err := h.service.ResolveStudentID(r.Context(), schemaName, userID)
+	if err != nil {
+		if errors.Is(err, ErrNotLinked) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return "", "", false
+		}
+		http.Error(w, "Gagal mengidentifikasi siswa: "+err.Error(), http.StatusInternalServerError)
+		return "", "", false
+	}
+	return schemaName, studentID, true
+}
+
+// GetMe adalah handler untuk GET /students/me.
+func (h *Handler) GetMe(w http.ResponseWriter, r *http.Request) {
+	schemaName, studentID, ok := h.currentStudent(w, r)
+	if !ok {
+		return
+	}
+
+	s, err := h.studentService.GetByID(r.Context(), schemaName, studentID)
+	if err != nil {
+		http.Error(w, "Gagal mengambil data siswa: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s)
+}
+
+// GetNilai adalah handler untuk GET /students/me/nilai?tahun_ajaran_id=...
+func (h *Handler) GetNilai(w http.ResponseWriter, r *http.Request) {
+	h.serveNilai(w, r, h.currentStudent)
+}
+
+// GetPresensi adalah handler untuk GET /students/me/presensi?year=...&month=...
+func (h *Handler) GetPresensi(w http.ResponseWriter, r *http.Request) {
+	h.servePresensi(w, r, h.currentStudent)
+}
+
+// GetPrestasi adalah handler untuk GET /students/me/prestasi.
+func (h *Handler) GetPrestasi(w http.ResponseWriter, r *http.Request) {
+	h.servePrestasi(w, r, h.currentStudent)
+}
+
+// GetEkstrakurikuler adalah handler untuk GET /students/me/ekstrakurikuler.
+func (h *Handler) GetEkstrakurikuler(w http.ResponseWriter, r *http.Request) {
+	schemaName, studentID, ok := h.currentStudent(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.GetEkstrakurikuler(r.Context(), schemaName, studentID)
+	if err != nil {
+		writeError(w, "Gagal mengambil data ekstrakurikuler: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// GetKartuUjian adalah handler untuk GET /students/me/kartu-ujian?tahun_ajaran_id=...
+func (h *Handler) GetKartuUjian(w http.ResponseWriter, r *http.Request) {
+	schemaName, studentID, ok := h.currentStudent(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.GetKartuUjian(r.Context(), schemaName, studentID, r.URL.Query().Get("tahun_ajaran_id"))
+	if err != nil {
+		writeError(w, "Gagal mengambil kartu ujian: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// GetPengumumanSiswa adalah handler untuk GET /students/me/pengumuman.
+func (h *Handler) GetPengumumanSiswa(w http.ResponseWriter, r *http.Request) {
+	schemaName, _, ok := h.currentStudent(w, r)
+	if !ok {
+		return
+	}
+	h.servePengumuman(w, r, schemaName, pengumuman.TargetSiswa)
+}
+
+// --- Portal wali murid ---
+
+// currentWali mengambil wali murid milik pengguna yang login.
+// Jika gagal, respons error sudah ditulis dan hasilnya nil.
+func (h *Handler) currentWali(w http.ResponseWriter, r *http.Request) (string, *walimurid.WaliMurid) {
+	schemaName, userID, ok := currentUser(w, r)
+	if !ok {
+		return "", nil
+	}
+
+	wali, err := h.waliMuridService.GetByUserID(r.Context(), schemaName, userID)
+	if err != nil {
+		http.Error(w, "Gagal mengidentifikasi wali murid: "+err.Error(), http.StatusInternalServerError)
+		return "", nil
+	}
+	if wali == nil {
+		http.Error(w, "Akun tidak terhubung dengan data wali murid", http.StatusNotFound)
+		return "", nil
+	}
+	return schemaName, wali
+}
+
+// currentAnak memastikan {studentID} pada URL adalah anak dari wali murid yang login.
+func (h *Handler) currentAnak(w http.ResponseWriter, r *http.Request) (schemaName string, studentID string, ok bool) {
+	schemaName, wali := h.currentWali(w, r)
+	if wali == nil {
+		return "", "", false
+	}
+
+	studentID = chi.URLParam(r, "studentID")
+	for _, a := range wali.Anak {
+		if a.StudentID == studentID {
+			return schemaName, studentID, true
+		}
+	}
+	http.Error(w, "Siswa ini bukan anak dari wali murid yang login", http.StatusForbidden)
+	return "", "", false
+}
+
+// GetWaliMe adalah handler untuk GET /wali-murid/me. Respons memuat daftar anak.
+func (h *Handler) GetWaliMe(w http.ResponseWriter, r *http.Request) {
+	_, wali := h.currentWali(w, r)
+	if wali == nil {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(wali)
+}
+
+// GetPengumumanWali adalah handler untuk GET /wali-murid/me/pengumuman.
+func (h *Handler) GetPengumumanWali(w http.ResponseWriter, r *http.Request) {
+	schemaName, wali := h.currentWali(w, r)
+	if wali == nil {
+		return
+	}
+	h.servePengumuman(w, r, schemaName, pengumuman.TargetWali)
+}
+
+// GetAnakNilai adalah handler untuk GET /wali-murid/me/anak/{studentID}/nilai.
+func (h *Handler) GetAnakNilai(w http.ResponseWriter, r *http.Request) {
+	h.serveNilai(w, r, h.currentAnak)
+}
+
+// GetAnakPresensi adalah handler untuk GET /wali-murid/me/anak/{studentID}/presensi.
+func (h *Handler) GetAnakPresensi(w http.ResponseWriter, r *http.Request) {
+	h.servePresensi(w, r, h.currentAnak)
+}
+
+// GetAnakPrestasi adalah handler untuk GET /wali-murid/me/anak/{studentID}/prestasi.
+func (h *Handler) GetAnakPrestasi(w http.ResponseWriter, r *http.Request) {
+	h.servePrestasi(w, r, h.currentAnak)
+}
+
+// studentResolver menentukan siswa yang datanya boleh dibaca oleh pengguna yang login.
+type studentResolver func(w http.ResponseWriter, r *http.Request) (schemaName string, studentID string, ok bool)
+
+func (h *Handler) serveNilai(w http.ResponseWriter, r *http.Request, resolve studentResolver) {
+	schemaName, studentID, ok := resolve(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.GetNilai(r.Context(), schemaName, studentID, r.URL.Query().Get("tahun_ajaran_id"))
+	if err != nil {
+		writeError(w, "Gagal mengambil data nilai: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *Handler) servePresensi(w http.ResponseWriter, r *http.Request, resolve studentResolver) {
+	schemaName, studentID, ok := resolve(w, r)
+	if !ok {
+		return
+	}
+
+	year, err := strconv.Atoi(r.URL.Query().Get("year"))
+	if err != nil {
+		year = time.Now().Year()
+	}
+	month, err := strconv.Atoi(r.URL.Query().Get("month"))
+	if err != nil {
+		month = int(time.Now().Month())
+	}
+
+	result, err := h.service.GetPresensiBulanan(r.Context(), schemaName, studentID, year, month)
+	if err != nil {
+		writeError(w, "Gagal mengambil data presensi: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *Handler) servePrestasi(w http.ResponseWriter, r *http.Request, resolve studentResolver) {
+	schemaName, studentID, ok := resolve(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.GetPrestasi(r.Context(), schemaName, studentID)
+	if err != nil {
+		writeError(w, "Gagal mengambil data prestasi: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *Handler) servePengumuman(w http.ResponseWriter, r *http.Request, schemaName string, target string) {
+	result, err := h.pengumumanService.GetTerbit(r.Context(), schemaName, target)
+	if err != nil {
+		http.Error(w, "Gagal mengambil pengumuman: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func writeError(w http.ResponseWriter, prefix string, err error) {
+	if errors.Is(err, ErrValidation) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+}
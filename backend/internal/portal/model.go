@@ -0,0 +1,60 @@
+// file: backend/internal/portal/model.go
+package portal
+
+import (
+	"skoola/internal/presensi"
+	"skoola/internal/ujianmaster"
+	"time"
+)
+
+// NilaiTP adalah nilai formatif siswa untuk satu tujuan pembelajaran.
+type NilaiTP struct {
+	TujuanPembelajaranID int      `json:"tujuan_pembelajaran_id"`
+	NamaMateri           string   `json:"nama_materi"`
+	DeskripsiTujuan      string   `json:"deskripsi_tujuan"`
+	Nilai                *float64 `json:"nilai"`
+}
+
+// NilaiSumatif adalah nilai siswa untuk satu penilaian sumatif (PR, PTS, PAS, dll).
+type NilaiSumatif struct {
+	PenilaianSumatifID string     `json:"penilaian_sumatif_id"`
+	NamaPenilaian      string     `json:"nama_penilaian"`
+	KodeUjian          string     `json:"kode_ujian"`
+	NamaUjian          string     `json:"nama_ujian"`
+	TanggalPelaksanaan *time.Time `json:"tanggal_pelaksanaan"`
+	Nilai              *float64   `json:"nilai"`
+}
+
+// NilaiMapel mengelompokkan nilai siswa per mata pelajaran di kelasnya.
+type NilaiMapel struct {
+	PengajarKelasID string         `json:"pengajar_kelas_id"`
+	NamaMapel       string         `json:"nama_mapel"`
+	NamaGuru        string         `json:"nama_guru"`
+	NilaiFormatif   []NilaiTP      `json:"nilai_formatif"`
+	NilaiSumatif    []NilaiSumatif `json:"nilai_sumatif"`
+}
+
+// PresensiBulanan adalah presensi siswa dalam satu bulan beserta rekap per status.
+type PresensiBulanan struct {
+	Tahun           int                           `json:"tahun"`
+	Bulan           int                           `json:"bulan"`
+	PresensiPerHari map[int]presensi.PresensiHari `json:"presensi_per_hari"` // map[tanggal]PresensiHari
+	Rekap           map[string]int                `json:"rekap"`             // map[status]jumlah hari
+}
+
+// EkstrakurikulerSiswa adalah keanggotaan siswa pada satu sesi ekstrakurikuler.
+type EkstrakurikulerSiswa struct {
+	SesiID          int     `json:"sesi_id"`
+	NamaKegiatan    string  `json:"nama_kegiatan"`
+	TahunAjaranID   string  `json:"tahun_ajaran_id"`
+	NamaTahunAjaran string  `json:"nama_tahun_ajaran"`
+	Semester        string  `json:"semester"`
+	NamaPembina     *string `json:"nama_pembina"`
+}
+
+// KartuUjian adalah kartu ujian siswa untuk satu paket ujian.
+type KartuUjian struct {
+	UjianMasterID  string                       `json:"ujian_master_id"`
+	NamaPaketUjian string                       `json:"nama_paket_ujian"`
+	Kartu          ujianmaster.KartuUjianDetail `json:"kartu"`
+}
@@ -0,0 +1,317 @@
+// file: backend/internal/portal/repository.go
+package portal
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"skoola/internal/presensi"
+	"skoola/internal/prestasi"
+	"skoola/pkg/database"
+	"time"
+)
+
+// Repository mendefinisikan query baca-saja untuk data milik seorang siswa.
+type Repository interface {
+	GetStudentIDByUserID(ctx context.Context, schemaName string, userID string) (string, error)
+	GetNilai(ctx context.Context, schemaName string, studentID string, tahunAjaranID string) ([]NilaiMapel, error)
+	GetPresensi(ctx context.Context, schemaName string, studentID string, from time.Time, to time.Time) (map[int]presensi.PresensiHari, error)
+	GetPrestasi(ctx context.Context, schemaName string, studentID string) ([]prestasi.Prestasi, error)
+	GetEkstrakurikuler(ctx context.Context, schemaName string, studentID string) ([]EkstrakurikulerSiswa, error)
+	GetKartuUjian(ctx context.Context, schemaName string, studentID string, tahunAjaranID string) ([]KartuUjian, error)
+}
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewRepository membuat instance baru dari postgresRepository.
+func NewRepository(db *sql.DB) Repository {
+	return &postgresRepository{db: db}
+}
+
+// GetStudentIDByUserID mencari siswa yang terhubung dengan akun login. String kosong
+// berarti akun tersebut tidak terhubung dengan data siswa mana pun.
+func (r *postgresRepository) GetStudentIDByUserID(ctx context.Context, schemaName string, userID string) (string, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	var id string
+	err = tx.QueryRowContext(ctx, `SELECT id FROM students WHERE user_id = $1`, userID).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("gagal mencari siswa dari akun: %w", err)
+	}
+	return id, tx.Commit()
+}
+
+func (r *postgresRepository) GetNilai(ctx context.Context, schemaName string, studentID string, tahunAjaranID string) ([]NilaiMapel, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// 1. Semua mapel di kelas siswa pada tahun ajaran tersebut, walaupun belum ada nilainya.
+	mapelQuery := `
+		SELECT pk.id, mp.nama_mapel, t.nama_lengkap
+		FROM anggota_kelas ak
+		JOIN kelas k ON ak.kelas_id = k.id
+		JOIN pengajar_kelas pk ON pk.kelas_id = k.id
+		JOIN mata_pelajaran mp ON pk.mata_pelajaran_id = mp.id
+		JOIN teachers t ON pk.teacher_id = t.id
+		WHERE ak.student_id = $1 AND k.tahun_ajaran_id = $2
+		ORDER BY mp.urutan ASC, mp.nama_mapel ASC
+	`
+	rows, err := tx.QueryContext(ctx, mapelQuery, studentID, tahunAjaranID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil mapel siswa: %w", err)
+	}
+	defer rows.Close()
+
+	result := []NilaiMapel{}
+	index := make(map[string]int)
+	for rows.Next() {
+		m := NilaiMapel{NilaiFormatif: []NilaiTP{}, NilaiSumatif: []NilaiSumatif{}}
+		if err := rows.Scan(&m.PengajarKelasID, &m.NamaMapel, &m.NamaGuru); err != nil {
+			return nil, fmt.Errorf("gagal memindai mapel siswa: %w", err)
+		}
+		index[m.PengajarKelasID] = len(result)
+		result = append(result, m)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// 2. Nilai formatif per TP.
+	formatifQuery := `
+		SELECT m.pengajar_kelas_id, tp.id, m.nama_materi, tp.deskripsi_tujuan, p.nilai
+		FROM anggota_kelas ak
+		JOIN kelas k ON ak.kelas_id = k.id
+		JOIN penilaian p ON p.anggota_kelas_id = ak.id
+		JOIN tujuan_pembelajaran tp ON p.tujuan_pembelajaran_id = tp.id
+		JOIN materi_pembelajaran m ON tp.materi_pembelajaran_id = m.id
+		WHERE ak.student_id = $1 AND k.tahun_ajaran_id = $2
+		ORDER BY m.urutan ASC, tp.urutan ASC
+	`
+	fRows, err := tx.QueryContext(ctx, formatifQuery, studentID, tahunAjaranID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil nilai formatif: %w", err)
+	}
+	defer fRows.Close()
+	for fRows.Next() {
+		var pkID string
+		var n NilaiTP
+		if err := fRows.Scan(&pkID, &n.TujuanPembelajaranID, &n.NamaMateri, &n.DeskripsiTujuan, &n.Nilai); err != nil {
+			return nil, fmt.Errorf("gagal memindai nilai formatif: %w", err)
+		}
+		if i, ok := index[pkID]; ok {
+			result[i].NilaiFormatif = append(result[i].NilaiFormatif, n)
+		}
+	}
+	if err = fRows.Err(); err != nil {
+		return nil, err
+	}
+
+	// 3. Nilai sumatif. Penilaian sumatif terhubung ke pengajar_kelas lewat TP atau lewat ujian.
+	sumatifQuery := `
+		SELECT COALESCE(m.pengajar_kelas_id, u.pengajar_kelas_id), ps.id, ps.nama_penilaian,
+		       ju.kode_ujian, ju.nama_ujian, ps.tanggal_pelaksanaan, ns.nilai
+		FROM anggota_kelas ak
+		JOIN kelas k ON ak.kelas_id = k.id
+		JOIN nilai_sumatif_siswa ns ON ns.anggota_kelas_id = ak.id
+		JOIN penilaian_sumatif ps ON ns.penilaian_sumatif_id = ps.id
+		JOIN jenis_ujian ju ON ps.jenis_ujian_id = ju.id
+		LEFT JOIN tujuan_pembelajaran tp ON ps.tujuan_pembelajaran_id = tp.id
+		LEFT JOIN materi_pembelajaran m ON tp.materi_pembelajaran_id = m.id
+		LEFT JOIN ujian u ON ps.ujian_id = u.id
+		WHERE ak.student_id = $1 AND k.tahun_ajaran_id = $2
+		ORDER BY ps.tanggal_pelaksanaan ASC NULLS LAST, ps.nama_penilaian ASC
+	`
+	sRows, err := tx.QueryContext(ctx, sumatifQuery, studentID, tahunAjaranID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil nilai sumatif: %w", err)
+	}
+	defer sRows.Close()
+	for sRows.Next() {
+		var pkID sql.NullString
+		var n NilaiSumatif
+		if err := sRows.Scan(&pkID, &n.PenilaianSumatifID, &n.NamaPenilaian, &n.KodeUjian, &n.NamaUjian, &n.TanggalPelaksanaan, &n.Nilai); err != nil {
+			return nil, fmt.Errorf("gagal memindai nilai sumatif: %w", err)
+		}
+		if i, ok := index[pkID.String]; ok {
+			result[i].NilaiSumatif = append(result[i].NilaiSumatif, n)
+		}
+	}
+	if err = sRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, tx.Commit()
+}
+
+// GetPresensi mengambil presensi siswa pada rentang [from, to), dikelompokkan per tanggal.
+func (r *postgresRepository) GetPresensi(ctx context.Context, schemaName string, studentID string, from time.Time, to time.Time) (map[int]presensi.PresensiHari, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT p.tanggal, p.status, p.catatan
+		FROM presensi p
+		JOIN anggota_kelas ak ON p.anggota_kelas_id = ak.id
+		WHERE ak.student_id = $1 AND p.tanggal >= $2 AND p.tanggal < $3
+		ORDER BY p.tanggal ASC
+	`
+	rows, err := tx.QueryContext(ctx, query, studentID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil presensi siswa: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[int]presensi.PresensiHari)
+	for rows.Next() {
+		var tanggal time.Time
+		var hari presensi.PresensiHari
+		if err := rows.Scan(&tanggal, &hari.Status, &hari.Catatan); err != nil {
+			return nil, fmt.Errorf("gagal memindai presensi siswa: %w", err)
+		}
+		result[tanggal.Day()] = hari
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, tx.Commit()
+}
+
+func (r *postgresRepository) GetPrestasi(ctx context.Context, schemaName string, studentID string) ([]prestasi.Prestasi, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT ps.id, ps.tahun_ajaran_id, ps.anggota_kelas_id, ps.nama_prestasi, ps.tingkat, ps.peringkat,
+		       ps.tanggal, ps.deskripsi, ps.created_at, ps.updated_at, s.nama_lengkap, k.nama_kelas
+		FROM prestasi_siswa ps
+		JOIN anggota_kelas ak ON ps.anggota_kelas_id = ak.id
+		JOIN students s ON ak.student_id = s.id
+		JOIN kelas k ON ak.kelas_id = k.id
+		WHERE ak.student_id = $1
+		ORDER BY ps.tanggal DESC
+	`
+	rows, err := tx.QueryContext(ctx, query, studentID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil prestasi siswa: %w", err)
+	}
+	defer rows.Close()
+
+	list := []prestasi.Prestasi{}
+	for rows.Next() {
+		var p prestasi.Prestasi
+		if err := rows.Scan(&p.ID, &p.TahunAjaranID, &p.AnggotaKelasID, &p.NamaPrestasi, &p.Tingkat, &p.Peringkat,
+			&p.Tanggal, &p.Deskripsi, &p.CreatedAt, &p.UpdatedAt, &p.NamaSiswa, &p.NamaKelas); err != nil {
+			return nil, fmt.Errorf("gagal memindai prestasi siswa: %w", err)
+		}
+		list = append(list, p)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+func (r *postgresRepository) GetEkstrakurikuler(ctx context.Context, schemaName string, studentID string) ([]EkstrakurikulerSiswa, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT es.id, e.nama_kegiatan, ta.id, ta.nama_tahun_ajaran, ta.semester, t.nama_lengkap
+		FROM ekstrakurikuler_anggota ea
+		JOIN ekstrakurikuler_sesi es ON ea.sesi_id = es.id
+		JOIN ekstrakurikuler e ON es.ekstrakurikuler_id = e.id
+		JOIN tahun_ajaran ta ON es.tahun_ajaran_id = ta.id
+		LEFT JOIN teachers t ON es.pembina_id = t.id
+		WHERE ea.student_id = $1
+		ORDER BY ta.nama_tahun_ajaran DESC, ta.semester DESC, e.nama_kegiatan ASC
+	`
+	rows, err := tx.QueryContext(ctx, query, studentID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil ekstrakurikuler siswa: %w", err)
+	}
+	defer rows.Close()
+
+	list := []EkstrakurikulerSiswa{}
+	for rows.Next() {
+		var e EkstrakurikulerSiswa
+		if err := rows.Scan(&e.SesiID, &e.NamaKegiatan, &e.TahunAjaranID, &e.NamaTahunAjaran, &e.Semester, &e.NamaPembina); err != nil {
+			return nil, fmt.Errorf("gagal memindai ekstrakurikuler siswa: %w", err)
+		}
+		list = append(list, e)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+func (r *postgresRepository) GetKartuUjian(ctx context.Context, schemaName string, studentID string, tahunAjaranID string) ([]KartuUjian, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT um.id, um.nama_paket_ujian,
+		       s.nisn, s.nama_lengkap, pu.kelas_id, k.nama_kelas, pu.nomor_ujian,
+		       aru.id, ru.nama_ruangan, pu.nomor_kursi
+		FROM peserta_ujian pu
+		JOIN ujian_master um ON pu.ujian_master_id = um.id
+		JOIN anggota_kelas ak ON pu.anggota_kelas_id = ak.id
+		JOIN students s ON ak.student_id = s.id
+		JOIN kelas k ON pu.kelas_id = k.id
+		LEFT JOIN alokasi_ruangan_ujian aru ON pu.alokasi_ruangan_id = aru.id
+		LEFT JOIN ruangan_ujian ru ON aru.ruangan_id = ru.id
+		WHERE ak.student_id = $1 AND um.tahun_ajaran_id = $2
+		ORDER BY um.created_at DESC
+	`
+	rows, err := tx.QueryContext(ctx, query, studentID, tahunAjaranID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil kartu ujian siswa: %w", err)
+	}
+	defer rows.Close()
+
+	list := []KartuUjian{}
+	for rows.Next() {
+		var k KartuUjian
+		var nisn, noUjian, ruangID, namaRuangan, nomorKursi sql.NullString
+		if err := rows.Scan(&k.UjianMasterID, &k.NamaPaketUjian,
+			&nisn, &k.Kartu.NamaSiswa, &k.Kartu.RombelID, &k.Kartu.NamaKelas, &noUjian,
+			&ruangID, &namaRuangan, &nomorKursi); err != nil {
+			return nil, fmt.Errorf("gagal memindai kartu ujian siswa: %w", err)
+		}
+		k.Kartu.NISN = nisn.String
+		k.Kartu.NoUjian = noUjian.String
+		k.Kartu.RuangUjianID = ruangID.String
+		k.Kartu.NamaRuangan = namaRuangan.String
+		k.Kartu.NomorKursi = nomorKursi.String
+		k.Kartu.IsDataLengkap = k.Kartu.NoUjian != "" && ruangID.Valid
+		list = append(list, k)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
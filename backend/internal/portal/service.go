@@ -0,0 +1,117 @@
+// file: backend/internal/portal/service.go
+package portal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"skoola/internal/prestasi"
+	"skoola/internal/tahunajaran"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrValidation = errors.New("validation failed")
+	// ErrNotLinked dikembalikan jika akun login tidak terhubung dengan data siswa.
+	ErrNotLinked = errors.New("akun tidak terhubung dengan data siswa")
+)
+
+// Service mendefinisikan logika bisnis portal siswa. Semua method menerima studentID
+// yang sudah diverifikasi sebagai milik pengguna yang login.
+type Service interface {
+	ResolveStudentID(ctx context.Context, schemaName string, userID string) (string, error)
+	GetNilai(ctx context.Context, schemaName string, studentID string, tahunAjaranID string) ([]NilaiMapel, error)
+	GetPresensiBulanan(ctx context.Context, schemaName string, studentID string, tahun int, bulan int) (*PresensiBulanan, error)
+	GetPrestasi(ctx context.Context, schemaName string, studentID string) ([]prestasi.Prestasi, error)
+	GetEkstrakurikuler(ctx context.Context, schemaName string, studentID string) ([]EkstrakurikulerSiswa, error)
+	GetKartuUjian(ctx context.Context, schemaName string, studentID string, tahunAjaranID string) ([]KartuUjian, error)
+}
+
+type service struct {
+	repo            Repository
+	tahunAjaranRepo tahunajaran.Repository
+}
+
+// NewService membuat instance baru dari service portal.
+func NewService(repo Repository, tahunAjaranRepo tahunajaran.Repository) Service {
+	return &service{repo: repo, tahunAjaranRepo: tahunAjaranRepo}
+}
+
+func (s *service) ResolveStudentID(ctx context.Context, schemaName string, userID string) (string, error) {
+	studentID, err := s.repo.GetStudentIDByUserID(ctx, schemaName, userID)
+	if err != nil {
+		return "", err
+	}
+	if studentID == "" {
+		return "", ErrNotLinked
+	}
+	return studentID, nil
+}
+
+func (s *service) GetNilai(ctx context.Context, schemaName string, studentID string, tahunAjaranID string) ([]NilaiMapel, error) {
+	tahunAjaranID, err := s.resolveTahunAjaran(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+	if tahunAjaranID == "" {
+		return []NilaiMapel{}, nil
+	}
+	return s.repo.GetNilai(ctx, schemaName, studentID, tahunAjaranID)
+}
+
+func (s *service) GetPresensiBulanan(ctx context.Context, schemaName string, studentID string, tahun int, bulan int) (*PresensiBulanan, error) {
+	if tahun < 2000 || bulan < 1 || bulan > 12 {
+		return nil, fmt.Errorf("%w: tahun atau bulan tidak valid", ErrValidation)
+	}
+
+	from := time.Date(tahun, time.Month(bulan), 1, 0, 0, 0, 0, time.UTC)
+	perHari, err := s.repo.GetPresensi(ctx, schemaName, studentID, from, from.AddDate(0, 1, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	rekap := make(map[string]int)
+	for _, hari := range perHari {
+		rekap[hari.Status]++
+	}
+
+	return &PresensiBulanan{
+		Tahun:           tahun,
+		Bulan:           bulan,
+		PresensiPerHari: perHari,
+		Rekap:           rekap,
+	}, nil
+}
+
+func (s *service) GetPrestasi(ctx context.Context, schemaName string, studentID string) ([]prestasi.Prestasi, error) {
+	return s.repo.GetPrestasi(ctx, schemaName, studentID)
+}
+
+func (s *service) GetEkstrakurikuler(ctx context.Context, schemaName string, studentID string) ([]EkstrakurikulerSiswa, error) {
+	return s.repo.GetEkstrakurikuler(ctx, schemaName, studentID)
+}
+
+func (s *service) GetKartuUjian(ctx context.Context, schemaName string, studentID string, tahunAjaranID string) ([]KartuUjian, error) {
+	tahunAjaranID, err := s.resolveTahunAjaran(ctx, schemaName, tahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+	if tahunAjaranID == "" {
+		return []KartuUjian{}, nil
+	}
+	return s.repo.GetKartuUjian(ctx, schemaName, studentID, tahunAjaranID)
+}
+
+// resolveTahunAjaran memakai tahun ajaran aktif jika tahunAjaranID tidak diisi.
+// String kosong berarti sekolah belum memiliki tahun ajaran aktif.
+func (s *service) resolveTahunAjaran(ctx context.Context, schemaName string, tahunAjaranID string) (string, error) {
+	if tahunAjaranID != "" {
+		if _, err := uuid.Parse(tahunAjaranID); err != nil {
+			return "", fmt.Errorf("%w: tahun_ajaran_id tidak valid", ErrValidation)
+		}
+		return tahunAjaranID, nil
+	}
+	return s.tahunAjaranRepo.GetActiveTahunAjaranID(ctx, schemaName)
+}
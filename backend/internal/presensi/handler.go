@@ -3,6 +3,7 @@ package presensi
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"skoola/internal/middleware"
 	"strconv"
@@ -74,6 +75,10 @@ func (h *Handler) UpsertPresensi(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.service.UpsertPresensi(r.Context(), schemaName, input); err != nil {
+		if errors.Is(err, ErrValidation) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, "Gagal menyimpan data presensi: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -0,0 +1,102 @@
+// file: backend/internal/presensi/jam_handler.go
+package presensi
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"skoola/internal/access"
+	"skoola/internal/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type JamHandler struct {
+	service JamService
+}
+
+func NewJamHandler(s JamService) *JamHandler {
+	return &JamHandler{service: s}
+}
+
+// GetByPengajar adalah handler untuk GET /presensi/jam/pengajar/{pengajarKelasID}?tanggal=YYYY-MM-DD.
+func (h *JamHandler) GetByPengajar(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	pengajarKelasID := chi.URLParam(r, "pengajarKelasID")
+
+	list, err := h.service.GetByPengajar(r.Context(), schemaName, access.ActorFromContext(r.Context()), pengajarKelasID, r.URL.Query().Get("tanggal"))
+	if err != nil {
+		writeJamError(w, "Gagal mengambil presensi per jam: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// GetByKelas adalah handler untuk GET /presensi/jam/kelas/{kelasID}?tanggal=YYYY-MM-DD.
+func (h *JamHandler) GetByKelas(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	kelasID := chi.URLParam(r, "kelasID")
+
+	list, err := h.service.GetByKelas(r.Context(), schemaName, access.ActorFromContext(r.Context()), kelasID, r.URL.Query().Get("tanggal"))
+	if err != nil {
+		writeJamError(w, "Gagal mengambil presensi per jam: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// Upsert adalah handler untuk POST /presensi/jam.
+func (h *JamHandler) Upsert(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	var input UpsertPresensiJamInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Upsert(r.Context(), schemaName, access.ActorFromContext(r.Context()), input); err != nil {
+		writeJamError(w, "Gagal menyimpan presensi per jam: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Data presensi berhasil disimpan."})
+}
+
+// Delete adalah handler untuk DELETE /presensi/jam.
+func (h *JamHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	var input DeletePresensiJamInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), schemaName, access.ActorFromContext(r.Context()), input); err != nil {
+		writeJamError(w, "Gagal menghapus presensi per jam: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Data presensi berhasil dihapus."})
+}
+
+func writeJamError(w http.ResponseWriter, prefix string, err error) {
+	switch {
+	case errors.Is(err, access.ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, ErrValidation):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Pengajar kelas tidak ditemukan", http.StatusNotFound)
+	default:
+		http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,58 @@
+// file: backend/internal/presensi/jam_model.go
+package presensi
+
+// Metode absensi tahun ajaran.
+const (
+	MetodeHarian          = "HARIAN"
+	MetodePerJamPelajaran = "PER_JAM_PELAJARAN"
+)
+
+// PresensiJamSiswa adalah presensi satu siswa pada satu tanggal, per jam pelajaran.
+// PerJam berisi jam yang dicatat oleh pengajar yang diminta saja, sedangkan Harian adalah
+// rekap harian siswa tersebut (dari seluruh mapel) jika sudah ada.
+type PresensiJamSiswa struct {
+	AnggotaKelasID string               `json:"anggota_kelas_id"`
+	NamaSiswa      string               `json:"nama_siswa"`
+	NIS            *string              `json:"nis"`
+	PerJam         map[int]PresensiHari `json:"per_jam"` // map[jam_ke]PresensiHari
+	Harian         *PresensiHari        `json:"harian,omitempty"`
+}
+
+// PresensiJamKelas adalah seluruh presensi per jam di satu kelas pada satu tanggal.
+type PresensiJamKelas struct {
+	AnggotaKelasID  string  `json:"anggota_kelas_id"`
+	NamaSiswa       string  `json:"nama_siswa"`
+	JamKe           int     `json:"jam_ke"`
+	PengajarKelasID string  `json:"pengajar_kelas_id"`
+	NamaMapel       string  `json:"nama_mapel"`
+	Status          string  `json:"status"`
+	Catatan         *string `json:"catatan"`
+}
+
+// UpsertPresensiJamInput adalah DTO untuk mencatat presensi satu jam pelajaran secara bulk.
+type UpsertPresensiJamInput struct {
+	PengajarKelasID string         `json:"pengajar_kelas_id" validate:"required,uuid"`
+	Tanggal         string         `json:"tanggal" validate:"required,datetime=2006-01-02"`
+	JamKe           int            `json:"jam_ke" validate:"required,min=1,max=20"`
+	Data            []PresensiData `json:"data" validate:"required,min=1,dive"`
+}
+
+// DeletePresensiJamInput adalah DTO untuk menghapus presensi satu jam pelajaran.
+type DeletePresensiJamInput struct {
+	PengajarKelasID string   `json:"pengajar_kelas_id" validate:"required,uuid"`
+	Tanggal         string   `json:"tanggal" validate:"required,datetime=2006-01-02"`
+	JamKe           int      `json:"jam_ke" validate:"required,min=1,max=20"`
+	AnggotaKelasIDs []string `json:"anggota_kelas_ids" validate:"required,min=1,dive,uuid"`
+}
+
+// pengajarInfo adalah kelas dan metode absensi tahun ajaran dari satu pengajar_kelas.
+type pengajarInfo struct {
+	KelasID       string
+	MetodeAbsensi string
+}
+
+// statusJam adalah status presensi satu siswa pada satu jam, dipakai untuk rekap harian.
+type statusJam struct {
+	JamKe  int
+	Status string
+}
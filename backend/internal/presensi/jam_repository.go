@@ -0,0 +1,319 @@
+// file: backend/internal/presensi/jam_repository.go
+package presensi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"skoola/pkg/database"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// JamRepository mendefinisikan interaksi database untuk presensi per jam pelajaran.
+type JamRepository interface {
+	// GetPengajarInfo mengembalikan nil jika pengajar_kelas tidak ditemukan.
+	GetPengajarInfo(ctx context.Context, schemaName string, pengajarKelasID string) (*pengajarInfo, error)
+	// GetSiswa mengembalikan anggota kelas yang masih aktif pada tanggal tersebut beserta
+	// presensi hariannya, tanpa data per jam.
+	GetSiswa(ctx context.Context, schemaName string, kelasID string, tanggal time.Time) ([]PresensiJamSiswa, error)
+	// GetByPengajar mengembalikan presensi yang dicatat satu pengajar pada satu tanggal,
+	// dikelompokkan per anggota_kelas_id lalu per jam_ke.
+	GetByPengajar(ctx context.Context, schemaName string, pengajarKelasID string, tanggal time.Time) (map[string]map[int]PresensiHari, error)
+	GetByKelas(ctx context.Context, schemaName string, kelasID string, tanggal time.Time) ([]PresensiJamKelas, error)
+	// IsJamTerisi memeriksa apakah jam tersebut sudah dicatat oleh pengajar lain di kelas yang sama.
+	IsJamTerisi(ctx context.Context, schemaName string, kelasID string, pengajarKelasID string, tanggal time.Time, jamKe int) (bool, error)
+	// Upsert dan Delete sekaligus memperbarui rekap harian siswa yang terdampak.
+	Upsert(ctx context.Context, schemaName string, pengajarKelasID string, tanggal time.Time, jamKe int, data []PresensiData, dicatatOleh string) error
+	Delete(ctx context.Context, schemaName string, pengajarKelasID string, tanggal time.Time, jamKe int, anggotaKelasIDs []string) error
+	// GetAnggotaKeluar sama dengan Repository.GetAnggotaKeluar.
+	GetAnggotaKeluar(ctx context.Context, schemaName string, tanggal time.Time, anggotaKelasIDs []string) (map[string]time.Time, error)
+}
+
+type jamRepository struct {
+	db *sql.DB
+}
+
+// NewJamRepository membuat instance baru dari JamRepository.
+func NewJamRepository(db *sql.DB) JamRepository {
+	return &jamRepository{db: db}
+}
+
+func (r *jamRepository) GetPengajarInfo(ctx context.Context, schemaName string, pengajarKelasID string) (*pengajarInfo, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT pk.kelas_id, ta.metode_absensi::text
+		FROM pengajar_kelas pk
+		JOIN kelas k ON pk.kelas_id = k.id
+		JOIN tahun_ajaran ta ON k.tahun_ajaran_id = ta.id
+		WHERE pk.id = $1
+	`
+	var info pengajarInfo
+	if err := tx.QueryRowContext(ctx, query, pengajarKelasID).Scan(&info.KelasID, &info.MetodeAbsensi); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("gagal mengambil data pengajar kelas: %w", err)
+	}
+	return &info, tx.Commit()
+}
+
+func (r *jamRepository) GetSiswa(ctx context.Context, schemaName string, kelasID string, tanggal time.Time) ([]PresensiJamSiswa, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT ak.id, s.nama_lengkap, s.nis, p.status, p.catatan
+		FROM anggota_kelas ak
+		JOIN students s ON ak.student_id = s.id
+		LEFT JOIN presensi p ON p.anggota_kelas_id = ak.id AND p.tanggal = $2
+		WHERE ak.kelas_id = $1 AND (ak.tanggal_keluar IS NULL OR ak.tanggal_keluar > $2)
+		ORDER BY ak.urutan ASC, s.nama_lengkap ASC
+	`
+	rows, err := tx.QueryContext(ctx, query, kelasID, tanggal)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil anggota kelas: %w", err)
+	}
+	defer rows.Close()
+
+	list := []PresensiJamSiswa{}
+	for rows.Next() {
+		var p PresensiJamSiswa
+		var status, catatan sql.NullString
+		if err := rows.Scan(&p.AnggotaKelasID, &p.NamaSiswa, &p.NIS, &status, &catatan); err != nil {
+			return nil, fmt.Errorf("gagal memindai anggota kelas: %w", err)
+		}
+		if status.Valid {
+			p.Harian = &PresensiHari{Status: status.String}
+			if catatan.Valid {
+				p.Harian.Catatan = &catatan.String
+			}
+		}
+		p.PerJam = make(map[int]PresensiHari)
+		list = append(list, p)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+func (r *jamRepository) GetByPengajar(ctx context.Context, schemaName string, pengajarKelasID string, tanggal time.Time) (map[string]map[int]PresensiHari, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT anggota_kelas_id, jam_ke, status, catatan
+		FROM presensi_jam
+		WHERE pengajar_kelas_id = $1 AND tanggal = $2
+	`
+	rows, err := tx.QueryContext(ctx, query, pengajarKelasID, tanggal)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil presensi per jam: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[string]map[int]PresensiHari)
+	for rows.Next() {
+		var anggotaID string
+		var jamKe int
+		var hari PresensiHari
+		if err := rows.Scan(&anggotaID, &jamKe, &hari.Status, &hari.Catatan); err != nil {
+			return nil, fmt.Errorf("gagal memindai presensi per jam: %w", err)
+		}
+		if result[anggotaID] == nil {
+			result[anggotaID] = make(map[int]PresensiHari)
+		}
+		result[anggotaID][jamKe] = hari
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, tx.Commit()
+}
+
+func (r *jamRepository) GetByKelas(ctx context.Context, schemaName string, kelasID string, tanggal time.Time) ([]PresensiJamKelas, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT pj.anggota_kelas_id, s.nama_lengkap, pj.jam_ke, pj.pengajar_kelas_id, mp.nama_mapel, pj.status, pj.catatan
+		FROM presensi_jam pj
+		JOIN anggota_kelas ak ON pj.anggota_kelas_id = ak.id
+		JOIN students s ON ak.student_id = s.id
+		JOIN pengajar_kelas pk ON pj.pengajar_kelas_id = pk.id
+		JOIN mata_pelajaran mp ON pk.mata_pelajaran_id = mp.id
+		WHERE ak.kelas_id = $1 AND pj.tanggal = $2
+		ORDER BY ak.urutan ASC, s.nama_lengkap ASC, pj.jam_ke ASC
+	`
+	rows, err := tx.QueryContext(ctx, query, kelasID, tanggal)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil presensi per jam kelas: %w", err)
+	}
+	defer rows.Close()
+
+	list := []PresensiJamKelas{}
+	for rows.Next() {
+		var p PresensiJamKelas
+		if err := rows.Scan(&p.AnggotaKelasID, &p.NamaSiswa, &p.JamKe, &p.PengajarKelasID, &p.NamaMapel, &p.Status, &p.Catatan); err != nil {
+			return nil, fmt.Errorf("gagal memindai presensi per jam kelas: %w", err)
+		}
+		list = append(list, p)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+func (r *jamRepository) IsJamTerisi(ctx context.Context, schemaName string, kelasID string, pengajarKelasID string, tanggal time.Time, jamKe int) (bool, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM presensi_jam pj
+			JOIN anggota_kelas ak ON pj.anggota_kelas_id = ak.id
+			WHERE ak.kelas_id = $1 AND pj.pengajar_kelas_id <> $2 AND pj.tanggal = $3 AND pj.jam_ke = $4
+		)
+	`
+	var terisi bool
+	if err := tx.QueryRowContext(ctx, query, kelasID, pengajarKelasID, tanggal, jamKe).Scan(&terisi); err != nil {
+		return false, fmt.Errorf("gagal memeriksa jam pelajaran: %w", err)
+	}
+	return terisi, tx.Commit()
+}
+
+func (r *jamRepository) Upsert(ctx context.Context, schemaName string, pengajarKelasID string, tanggal time.Time, jamKe int, data []PresensiData, dicatatOleh string) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO presensi_jam (anggota_kelas_id, pengajar_kelas_id, tanggal, jam_ke, status, catatan, dicatat_oleh)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (anggota_kelas_id, tanggal, jam_ke) DO UPDATE SET
+			pengajar_kelas_id = EXCLUDED.pengajar_kelas_id,
+			status = EXCLUDED.status,
+			catatan = EXCLUDED.catatan,
+			dicatat_oleh = EXCLUDED.dicatat_oleh,
+			updated_at = NOW()
+	`)
+	if err != nil {
+		return fmt.Errorf("gagal mempersiapkan statement: %w", err)
+	}
+	defer stmt.Close()
+
+	var oleh *string
+	if dicatatOleh != "" {
+		oleh = &dicatatOleh
+	}
+	anggotaIDs := make([]string, len(data))
+	for i, item := range data {
+		if _, err := stmt.ExecContext(ctx, item.AnggotaKelasID, pengajarKelasID, tanggal, jamKe, item.Status, item.Catatan, oleh); err != nil {
+			return fmt.Errorf("gagal upsert presensi per jam untuk anggota %s: %w", item.AnggotaKelasID, err)
+		}
+		anggotaIDs[i] = item.AnggotaKelasID
+	}
+
+	if err := rekapPresensiHarian(ctx, tx, tanggal, anggotaIDs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *jamRepository) Delete(ctx context.Context, schemaName string, pengajarKelasID string, tanggal time.Time, jamKe int, anggotaKelasIDs []string) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		DELETE FROM presensi_jam
+		WHERE pengajar_kelas_id = $1 AND tanggal = $2 AND jam_ke = $3 AND anggota_kelas_id = ANY($4)
+	`
+	if _, err := tx.ExecContext(ctx, query, pengajarKelasID, tanggal, jamKe, pq.Array(anggotaKelasIDs)); err != nil {
+		return fmt.Errorf("gagal menghapus presensi per jam: %w", err)
+	}
+
+	if err := rekapPresensiHarian(ctx, tx, tanggal, anggotaKelasIDs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// rekapPresensiHarian menghitung ulang baris presensi harian siswa dari seluruh presensi per
+// jamnya pada tanggal tersebut. Baris yang diisi langsung (sumber 'HARIAN') tidak ditimpa.
+func rekapPresensiHarian(ctx context.Context, tx *sql.Tx, tanggal time.Time, anggotaKelasIDs []string) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT anggota_kelas_id, jam_ke, status
+		FROM presensi_jam
+		WHERE tanggal = $1 AND anggota_kelas_id = ANY($2)
+		ORDER BY jam_ke ASC
+	`, tanggal, pq.Array(anggotaKelasIDs))
+	if err != nil {
+		return fmt.Errorf("gagal mengambil presensi per jam untuk rekap: %w", err)
+	}
+	perSiswa := make(map[string][]statusJam)
+	for rows.Next() {
+		var anggotaID string
+		var s statusJam
+		if err := rows.Scan(&anggotaID, &s.JamKe, &s.Status); err != nil {
+			rows.Close()
+			return fmt.Errorf("gagal memindai presensi per jam untuk rekap: %w", err)
+		}
+		perSiswa[anggotaID] = append(perSiswa[anggotaID], s)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	upsert := `
+		INSERT INTO presensi (anggota_kelas_id, tanggal, status, catatan, sumber)
+		VALUES ($1, $2, $3, $4, 'JAM')
+		ON CONFLICT (anggota_kelas_id, tanggal) DO UPDATE SET
+			status = EXCLUDED.status, catatan = EXCLUDED.catatan, updated_at = NOW()
+		WHERE presensi.sumber = 'JAM'
+	`
+	hapus := `DELETE FROM presensi WHERE anggota_kelas_id = $1 AND tanggal = $2 AND sumber = 'JAM'`
+	for _, anggotaID := range anggotaKelasIDs {
+		jam, ok := perSiswa[anggotaID]
+		if !ok {
+			if _, err := tx.ExecContext(ctx, hapus, anggotaID, tanggal); err != nil {
+				return fmt.Errorf("gagal menghapus rekap presensi harian: %w", err)
+			}
+			continue
+		}
+		status, catatan := rekapHarian(jam)
+		if _, err := tx.ExecContext(ctx, upsert, anggotaID, tanggal, status, catatan); err != nil {
+			return fmt.Errorf("gagal menyimpan rekap presensi harian: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *jamRepository) GetAnggotaKeluar(ctx context.Context, schemaName string, tanggal time.Time, anggotaKelasIDs []string) (map[string]time.Time, error) {
+	return getAnggotaKeluar(ctx, r.db, schemaName, tanggal, anggotaKelasIDs)
+}
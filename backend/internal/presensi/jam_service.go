@@ -0,0 +1,257 @@
+// file: backend/internal/presensi/jam_service.go
+package presensi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"skoola/internal/access"
+	"skoola/internal/audit"
+	"skoola/internal/kalender"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// JamService mendefinisikan logika bisnis presensi per jam pelajaran. Presensi dicatat oleh
+// guru mapel, lalu direkap menjadi presensi harian agar rekap bulanan tetap berlaku.
+type JamService interface {
+	GetByPengajar(ctx context.Context, schemaName string, actor access.Actor, pengajarKelasID string, tanggal string) ([]PresensiJamSiswa, error)
+	GetByKelas(ctx context.Context, schemaName string, actor access.Actor, kelasID string, tanggal string) ([]PresensiJamKelas, error)
+	Upsert(ctx context.Context, schemaName string, actor access.Actor, input UpsertPresensiJamInput) error
+	Delete(ctx context.Context, schemaName string, actor access.Actor, input DeletePresensiJamInput) error
+}
+
+type jamService struct {
+	repo     JamRepository
+	kalender kalender.Service
+	access   access.Service
+	audit    audit.Recorder
+	validate *validator.Validate
+}
+
+// NewJamService membuat instance baru dari JamService.
+func NewJamService(repo JamRepository, kalenderService kalender.Service, accessService access.Service, auditLog audit.Recorder, validate *validator.Validate) JamService {
+	return &jamService{repo: repo, kalender: kalenderService, access: accessService, audit: auditLog, validate: validate}
+}
+
+func (s *jamService) GetByPengajar(ctx context.Context, schemaName string, actor access.Actor, pengajarKelasID string, tanggal string) ([]PresensiJamSiswa, error) {
+	t, err := parseTanggalPresensi(tanggal)
+	if err != nil {
+		return nil, err
+	}
+	info, err := s.getPengajar(ctx, schemaName, pengajarKelasID)
+	if err != nil {
+		return nil, err
+	}
+	target := access.Target{PengajarKelasIDs: []string{pengajarKelasID}}
+	if err := s.access.AuthorizeRead(ctx, schemaName, actor, target); err != nil {
+		return nil, err
+	}
+
+	list, err := s.repo.GetSiswa(ctx, schemaName, info.KelasID, t)
+	if err != nil {
+		return nil, err
+	}
+	perJam, err := s.repo.GetByPengajar(ctx, schemaName, pengajarKelasID, t)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		for jamKe, hari := range perJam[list[i].AnggotaKelasID] {
+			list[i].PerJam[jamKe] = hari
+		}
+	}
+	return list, nil
+}
+
+func (s *jamService) GetByKelas(ctx context.Context, schemaName string, actor access.Actor, kelasID string, tanggal string) ([]PresensiJamKelas, error) {
+	t, err := parseTanggalPresensi(tanggal)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(kelasID); err != nil {
+		return nil, fmt.Errorf("%w: kelas_id tidak valid", ErrValidation)
+	}
+	if err := s.access.AuthorizeRead(ctx, schemaName, actor, access.Target{KelasIDs: []string{kelasID}}); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByKelas(ctx, schemaName, kelasID, t)
+}
+
+func (s *jamService) Upsert(ctx context.Context, schemaName string, actor access.Actor, input UpsertPresensiJamInput) error {
+	if err := s.validate.Struct(input); err != nil {
+		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	tanggal, _ := time.Parse("2006-01-02", input.Tanggal)
+
+	anggotaIDs := make([]string, len(input.Data))
+	for i, item := range input.Data {
+		anggotaIDs[i] = item.AnggotaKelasID
+	}
+	info, err := s.authorizeWrite(ctx, schemaName, actor, input.PengajarKelasID, anggotaIDs)
+	if err != nil {
+		return err
+	}
+	if err := cekHariSekolah(ctx, s.kalender, schemaName, info.KelasID, tanggal); err != nil {
+		return err
+	}
+	keluar, err := s.repo.GetAnggotaKeluar(ctx, schemaName, tanggal, anggotaIDs)
+	if err != nil {
+		return err
+	}
+	if err := cekAnggotaAktif(keluar); err != nil {
+		return err
+	}
+	terisi, err := s.repo.IsJamTerisi(ctx, schemaName, info.KelasID, input.PengajarKelasID, tanggal, input.JamKe)
+	if err != nil {
+		return err
+	}
+	if terisi {
+		return fmt.Errorf("%w: jam ke-%d pada tanggal %s sudah dicatat oleh mata pelajaran lain", ErrValidation, input.JamKe, input.Tanggal)
+	}
+
+	before, err := s.repo.GetByPengajar(ctx, schemaName, input.PengajarKelasID, tanggal)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Upsert(ctx, schemaName, input.PengajarKelasID, tanggal, input.JamKe, input.Data, actor.UserID); err != nil {
+		return err
+	}
+
+	// Hanya siswa yang status atau catatannya berubah yang dicatat.
+	var entries []audit.Entry
+	for _, item := range input.Data {
+		after := presensiJamAudit{PengajarKelasID: input.PengajarKelasID, Tanggal: input.Tanggal, JamKe: input.JamKe, Status: item.Status, Catatan: item.Catatan}
+		entry := audit.Entry{Action: audit.ActionCreate, Entity: "presensi_jam", EntityID: item.AnggotaKelasID, After: after}
+		if old, ok := before[item.AnggotaKelasID][input.JamKe]; ok {
+			if old.Status == item.Status && sameCatatan(old.Catatan, item.Catatan) {
+				continue
+			}
+			entry.Action = audit.ActionUpdate
+			entry.Before = presensiJamAudit{PengajarKelasID: input.PengajarKelasID, Tanggal: input.Tanggal, JamKe: input.JamKe, Status: old.Status, Catatan: old.Catatan}
+		}
+		entries = append(entries, entry)
+	}
+	s.audit.Record(ctx, schemaName, entries...)
+	return nil
+}
+
+func (s *jamService) Delete(ctx context.Context, schemaName string, actor access.Actor, input DeletePresensiJamInput) error {
+	if err := s.validate.Struct(input); err != nil {
+		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	tanggal, _ := time.Parse("2006-01-02", input.Tanggal)
+
+	if _, err := s.authorizeWrite(ctx, schemaName, actor, input.PengajarKelasID, input.AnggotaKelasIDs); err != nil {
+		return err
+	}
+	before, err := s.repo.GetByPengajar(ctx, schemaName, input.PengajarKelasID, tanggal)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, schemaName, input.PengajarKelasID, tanggal, input.JamKe, input.AnggotaKelasIDs); err != nil {
+		return err
+	}
+
+	var entries []audit.Entry
+	for _, anggotaID := range input.AnggotaKelasIDs {
+		old, ok := before[anggotaID][input.JamKe]
+		if !ok {
+			continue
+		}
+		entries = append(entries, audit.Entry{
+			Action:   audit.ActionDelete,
+			Entity:   "presensi_jam",
+			EntityID: anggotaID,
+			Before:   presensiJamAudit{PengajarKelasID: input.PengajarKelasID, Tanggal: input.Tanggal, JamKe: input.JamKe, Status: old.Status, Catatan: old.Catatan},
+		})
+	}
+	s.audit.Record(ctx, schemaName, entries...)
+	return nil
+}
+
+// authorizeWrite memastikan tahun ajaran memakai presensi per jam, actor adalah pengajar
+// mapel tersebut, dan semua siswa adalah anggota kelasnya.
+func (s *jamService) authorizeWrite(ctx context.Context, schemaName string, actor access.Actor, pengajarKelasID string, anggotaIDs []string) (*pengajarInfo, error) {
+	info, err := s.getPengajar(ctx, schemaName, pengajarKelasID)
+	if err != nil {
+		return nil, err
+	}
+	if info.MetodeAbsensi != MetodePerJamPelajaran {
+		return nil, fmt.Errorf("%w: tahun ajaran kelas ini memakai presensi harian", ErrValidation)
+	}
+	target := access.Target{PengajarKelasIDs: []string{pengajarKelasID}, KelasIDs: []string{info.KelasID}, AnggotaKelasIDs: anggotaIDs}
+	if err := s.access.AuthorizeWrite(ctx, schemaName, actor, target); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func (s *jamService) getPengajar(ctx context.Context, schemaName string, pengajarKelasID string) (*pengajarInfo, error) {
+	if _, err := uuid.Parse(pengajarKelasID); err != nil {
+		return nil, fmt.Errorf("%w: pengajar_kelas_id tidak valid", ErrValidation)
+	}
+	info, err := s.repo.GetPengajarInfo(ctx, schemaName, pengajarKelasID)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, sql.ErrNoRows
+	}
+	return info, nil
+}
+
+// presensiJamAudit adalah bentuk data presensi per jam satu siswa yang dicatat di audit log.
+type presensiJamAudit struct {
+	PengajarKelasID string  `json:"pengajar_kelas_id"`
+	Tanggal         string  `json:"tanggal"`
+	JamKe           int     `json:"jam_ke"`
+	Status          string  `json:"status"`
+	Catatan         *string `json:"catatan,omitempty"`
+}
+
+// rekapHarian menurunkan status harian dari presensi per jam. Siswa dianggap hadir (H) jika
+// hadir di sedikitnya satu jam. Jika tidak hadir di semua jam, status harian adalah status
+// terbanyak, dengan urutan S, I, lalu A bila jumlahnya sama. Jam yang statusnya berbeda dari
+// status harian dicantumkan di catatan.
+func rekapHarian(jam []statusJam) (string, *string) {
+	jumlah := make(map[string]int)
+	for _, j := range jam {
+		jumlah[j.Status]++
+	}
+	status := "H"
+	if jumlah["H"] == 0 {
+		status = ""
+		for _, s := range []string{"S", "I", "A"} {
+			if status == "" || jumlah[s] > jumlah[status] {
+				status = s
+			}
+		}
+	}
+
+	var rincian []string
+	for _, j := range jam {
+		if j.Status != status {
+			rincian = append(rincian, fmt.Sprintf("jam ke-%d (%s)", j.JamKe, j.Status))
+		}
+	}
+	if len(rincian) == 0 {
+		return status, nil
+	}
+	catatan := "Rincian per jam: " + strings.Join(rincian, ", ")
+	if status == "H" {
+		catatan = "Tidak hadir " + strings.Join(rincian, ", ")
+	}
+	return status, &catatan
+}
+
+func parseTanggalPresensi(value string) (time.Time, error) {
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: tanggal harus berformat YYYY-MM-DD", ErrValidation)
+	}
+	return t, nil
+}
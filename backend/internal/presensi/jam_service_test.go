@@ -0,0 +1,112 @@
+// file: backend/internal/presensi/jam_service_test.go
+package presensi
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRekapHarian(t *testing.T) {
+	jam := func(status ...string) []statusJam {
+		list := make([]statusJam, len(status))
+		for i, s := range status {
+			list[i] = statusJam{JamKe: i + 1, Status: s}
+		}
+		return list
+	}
+
+	tests := []struct {
+		name        string
+		jam         []statusJam
+		wantStatus  string
+		wantCatatan string // "" berarti nil
+	}{
+		{
+			name:       "hadir di semua jam",
+			jam:        jam("H", "H", "H"),
+			wantStatus: "H",
+		},
+		{
+			name:        "hadir di satu jam dianggap hadir",
+			jam:         jam("A", "H", "S"),
+			wantStatus:  "H",
+			wantCatatan: "Tidak hadir jam ke-1 (A), jam ke-3 (S)",
+		},
+		{
+			name:       "alpa di semua jam",
+			jam:        jam("A", "A"),
+			wantStatus: "A",
+		},
+		{
+			name:        "status terbanyak dipakai",
+			jam:         jam("A", "I", "A"),
+			wantStatus:  "A",
+			wantCatatan: "Rincian per jam: jam ke-2 (I)",
+		},
+		{
+			name:        "S dan I sama banyak memilih S",
+			jam:         jam("I", "S"),
+			wantStatus:  "S",
+			wantCatatan: "Rincian per jam: jam ke-1 (I)",
+		},
+		{
+			name:        "I dan A sama banyak memilih I",
+			jam:         jam("A", "I", "A", "I"),
+			wantStatus:  "I",
+			wantCatatan: "Rincian per jam: jam ke-1 (A), jam ke-3 (A)",
+		},
+		{
+			name:        "S dan A sama banyak memilih S",
+			jam:         jam("A", "S"),
+			wantStatus:  "S",
+			wantCatatan: "Rincian per jam: jam ke-1 (A)",
+		},
+		{
+			name:        "S, I, dan A sama banyak memilih S",
+			jam:         jam("A", "I", "S"),
+			wantStatus:  "S",
+			wantCatatan: "Rincian per jam: jam ke-1 (A), jam ke-2 (I)",
+		},
+		{
+			name:        "status terbanyak menang atas urutan",
+			jam:         jam("S", "A", "A"),
+			wantStatus:  "A",
+			wantCatatan: "Rincian per jam: jam ke-1 (S)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, catatan := rekapHarian(tt.jam)
+			if status != tt.wantStatus {
+				t.Errorf("status = %q, ingin %q", status, tt.wantStatus)
+			}
+			got := ""
+			if catatan != nil {
+				got = *catatan
+			}
+			if got != tt.wantCatatan {
+				t.Errorf("catatan = %q, ingin %q", got, tt.wantCatatan)
+			}
+		})
+	}
+}
+
+func TestCekAnggotaAktif(t *testing.T) {
+	if err := cekAnggotaAktif(nil); err != nil {
+		t.Fatalf("tanpa anggota keluar: error = %v", err)
+	}
+	keluar := map[string]time.Time{
+		"c": time.Date(2026, 9, 3, 0, 0, 0, 0, time.UTC),
+		"a": time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+		"b": time.Date(2026, 9, 2, 0, 0, 0, 0, time.UTC),
+	}
+	want := "validation failed: anggota kelas sudah keluar dari kelas: a (keluar 2026-09-01), b (keluar 2026-09-02), c (keluar 2026-09-03)"
+	for i := 0; i < 5; i++ {
+		err := cekAnggotaAktif(keluar)
+		if !errors.Is(err, ErrValidation) || err.Error() != want {
+			t.Fatalf("error = %v, ingin %q", err, want)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"database/sql"
 	"fmt"
 	"skoola/internal/rombel"
+	"skoola/pkg/database"
 	"time"
 
 	"github.com/lib/pq"
@@ -16,6 +17,10 @@ type Repository interface {
 	GetPresensiByKelasAndMonth(ctx context.Context, schemaName string, kelasID string, year int, month int) ([]*PresensiSiswa, error)
 	UpsertPresensiBulk(ctx context.Context, schemaName string, tanggal time.Time, data []PresensiData) error
 	DeletePresensiBulk(ctx context.Context, schemaName string, tanggal time.Time, anggotaKelasIDs []string) error // <-- TAMBAHKAN INI
+	GetPresensiByTanggal(ctx context.Context, schemaName string, tanggal time.Time, anggotaKelasIDs []string) (map[string]PresensiHari, error)
+	// GetAnggotaKeluar mengembalikan tanggal keluar anggota kelas yang sudah tidak aktif
+	// pada tanggal tersebut, dikelompokkan per anggota_kelas_id.
+	GetAnggotaKeluar(ctx context.Context, schemaName string, tanggal time.Time, anggotaKelasIDs []string) (map[string]time.Time, error)
 }
 
 type postgresRepository struct {
@@ -27,39 +32,121 @@ func NewRepository(db *sql.DB) Repository {
 	return &postgresRepository{db: db}
 }
 
-func (r *postgresRepository) setSchema(ctx context.Context, schemaName string) error {
-	_, err := r.db.ExecContext(ctx, fmt.Sprintf("SET search_path TO %q", schemaName))
-	return err
-}
-
 // --- FUNGSI BARU ---
 func (r *postgresRepository) DeletePresensiBulk(ctx context.Context, schemaName string, tanggal time.Time, anggotaKelasIDs []string) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 	query := `
 		DELETE FROM presensi 
 		WHERE tanggal = $1 AND anggota_kelas_id = ANY($2)
 	`
-	_, err := r.db.ExecContext(ctx, query, tanggal, pq.Array(anggotaKelasIDs))
+	_, err = tx.ExecContext(ctx, query, tanggal, pq.Array(anggotaKelasIDs))
 	if err != nil {
 		return fmt.Errorf("gagal mengeksekusi delete bulk: %w", err)
 	}
-	return nil
+	return tx.Commit()
+}
+
+// GetPresensiByTanggal mengambil presensi yang sudah tersimpan pada satu tanggal,
+// dikelompokkan per anggota_kelas_id.
+func (r *postgresRepository) GetPresensiByTanggal(ctx context.Context, schemaName string, tanggal time.Time, anggotaKelasIDs []string) (map[string]PresensiHari, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	query := `
+		SELECT anggota_kelas_id, status, catatan
+		FROM presensi
+		WHERE tanggal = $1 AND anggota_kelas_id = ANY($2)
+	`
+	rows, err := tx.QueryContext(ctx, query, tanggal, pq.Array(anggotaKelasIDs))
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil presensi per tanggal: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[string]PresensiHari)
+	for rows.Next() {
+		var anggotaID string
+		var hari PresensiHari
+		if err := rows.Scan(&anggotaID, &hari.Status, &hari.Catatan); err != nil {
+			return nil, fmt.Errorf("gagal memindai data presensi: %w", err)
+		}
+		result[anggotaID] = hari
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, tx.Commit()
 }
 
 // --- FUNGSI LAMA (TIDAK BERUBAH) ---
+func (r *postgresRepository) GetAnggotaKeluar(ctx context.Context, schemaName string, tanggal time.Time, anggotaKelasIDs []string) (map[string]time.Time, error) {
+	return getAnggotaKeluar(ctx, r.db, schemaName, tanggal, anggotaKelasIDs)
+}
+
+// getAnggotaKeluar dipakai bersama oleh repository presensi harian dan per jam.
+func getAnggotaKeluar(ctx context.Context, db *sql.DB, schemaName string, tanggal time.Time, anggotaKelasIDs []string) (map[string]time.Time, error) {
+	tx, err := database.BeginTenantTx(ctx, db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT id, tanggal_keluar
+		FROM anggota_kelas
+		WHERE id = ANY($1) AND tanggal_keluar <= $2
+	`
+	rows, err := tx.QueryContext(ctx, query, pq.Array(anggotaKelasIDs), tanggal)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memeriksa anggota kelas yang keluar: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[string]time.Time)
+	for rows.Next() {
+		var id string
+		var keluar time.Time
+		if err := rows.Scan(&id, &keluar); err != nil {
+			return nil, fmt.Errorf("gagal memindai anggota kelas yang keluar: %w", err)
+		}
+		result[id] = keluar
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, tx.Commit()
+}
+
 func (r *postgresRepository) GetPresensiByKelasAndMonth(ctx context.Context, schemaName string, kelasID string, year int, month int) ([]*PresensiSiswa, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 	anggotaRepo := rombel.NewRepository(r.db)
-	anggotaList, err := anggotaRepo.GetAllAnggotaByKelas(ctx, schemaName, kelasID)
+	anggotaList, err := anggotaRepo.GetAllAnggotaByKelasTx(ctx, tx, kelasID)
 	if err != nil {
 		return nil, fmt.Errorf("gagal mengambil anggota kelas: %w", err)
 	}
+	endDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1, 0)
+	startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+
+	// Siswa yang keluar sebelum bulan ini dimulai tidak lagi ditampilkan di grid.
+	aktif := anggotaList[:0]
+	for _, anggota := range anggotaList {
+		if anggota.TanggalKeluar == nil || anggota.TanggalKeluar.After(startDate) {
+			aktif = append(aktif, anggota)
+		}
+	}
+	anggotaList = aktif
 	if len(anggotaList) == 0 {
-		return []*PresensiSiswa{}, nil
+		return []*PresensiSiswa{}, tx.Commit()
 	}
 	resultMap := make(map[string]*PresensiSiswa)
 	anggotaIDs := make([]string, len(anggotaList))
@@ -72,15 +159,12 @@ func (r *postgresRepository) GetPresensiByKelasAndMonth(ctx context.Context, sch
 			PresensiPerHari: make(map[int]PresensiHari),
 		}
 	}
-	endDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1, 0)
-	startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
-
 	query := `
 		SELECT anggota_kelas_id, tanggal, status, catatan
 		FROM presensi
 		WHERE anggota_kelas_id = ANY($1) AND tanggal >= $2 AND tanggal < $3
 	`
-	rows, err := r.db.QueryContext(ctx, query, pq.Array(anggotaIDs), startDate, endDate)
+	rows, err := tx.QueryContext(ctx, query, pq.Array(anggotaIDs), startDate, endDate)
 	if err != nil {
 		return nil, fmt.Errorf("gagal query data presensi: %w", err)
 	}
@@ -107,16 +191,12 @@ func (r *postgresRepository) GetPresensiByKelasAndMonth(ctx context.Context, sch
 			finalResult = append(finalResult, val)
 		}
 	}
-	return finalResult, nil
+	return finalResult, tx.Commit()
 }
 func (r *postgresRepository) UpsertPresensiBulk(ctx context.Context, schemaName string, tanggal time.Time, data []PresensiData) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
-		return err
-	}
-
-	tx, err := r.db.BeginTx(ctx, nil)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
 	if err != nil {
-		return fmt.Errorf("gagal memulai transaksi: %w", err)
+		return err
 	}
 	defer tx.Rollback()
 
@@ -124,7 +204,7 @@ func (r *postgresRepository) UpsertPresensiBulk(ctx context.Context, schemaName
 		INSERT INTO presensi (anggota_kelas_id, tanggal, status, catatan)
 		VALUES ($1, $2, $3, $4)
 		ON CONFLICT (anggota_kelas_id, tanggal)
-		DO UPDATE SET status = EXCLUDED.status, catatan = EXCLUDED.catatan, updated_at = NOW()
+		DO UPDATE SET status = EXCLUDED.status, catatan = EXCLUDED.catatan, sumber = 'HARIAN', updated_at = NOW()
 	`)
 	if err != nil {
 		return fmt.Errorf("gagal mempersiapkan statement: %w", err)
@@ -5,6 +5,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"skoola/internal/audit"
+	"skoola/internal/kalender"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -21,12 +25,14 @@ type Service interface {
 
 type service struct {
 	repo     Repository
+	kalender kalender.Service
+	audit    audit.Recorder
 	validate *validator.Validate
 }
 
 // NewService membuat instance baru dari service presensi.
-func NewService(repo Repository, validate *validator.Validate) Service {
-	return &service{repo: repo, validate: validate}
+func NewService(repo Repository, kalenderService kalender.Service, auditLog audit.Recorder, validate *validator.Validate) Service {
+	return &service{repo: repo, kalender: kalenderService, audit: auditLog, validate: validate}
 }
 
 // --- FUNGSI BARU ---
@@ -40,7 +46,36 @@ func (s *service) DeletePresensi(ctx context.Context, schemaName string, input D
 		return fmt.Errorf("format tanggal tidak valid: %w", err)
 	}
 
-	return s.repo.DeletePresensiBulk(ctx, schemaName, tanggal, input.AnggotaKelasIDs)
+	before, err := s.repo.GetPresensiByTanggal(ctx, schemaName, tanggal, input.AnggotaKelasIDs)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeletePresensiBulk(ctx, schemaName, tanggal, input.AnggotaKelasIDs); err != nil {
+		return err
+	}
+
+	var entries []audit.Entry
+	for _, anggotaID := range input.AnggotaKelasIDs {
+		old, ok := before[anggotaID]
+		if !ok {
+			continue
+		}
+		entries = append(entries, audit.Entry{
+			Action:   audit.ActionDelete,
+			Entity:   "presensi",
+			EntityID: anggotaID,
+			Before:   presensiAudit{Tanggal: input.Tanggal, Status: old.Status, Catatan: old.Catatan},
+		})
+	}
+	s.audit.Record(ctx, schemaName, entries...)
+	return nil
+}
+
+// presensiAudit adalah bentuk data presensi satu siswa yang dicatat di audit log.
+type presensiAudit struct {
+	Tanggal string  `json:"tanggal"`
+	Status  string  `json:"status"`
+	Catatan *string `json:"catatan,omitempty"`
 }
 
 // --- FUNGSI LAMA (TIDAK BERUBAH) ---
@@ -62,6 +97,78 @@ func (s *service) UpsertPresensi(ctx context.Context, schemaName string, input U
 	if err != nil {
 		return fmt.Errorf("format tanggal tidak valid: %w", err)
 	}
+	if err := cekHariSekolah(ctx, s.kalender, schemaName, input.KelasID, tanggal); err != nil {
+		return err
+	}
+
+	anggotaIDs := make([]string, len(input.Data))
+	for i, item := range input.Data {
+		anggotaIDs[i] = item.AnggotaKelasID
+	}
+	keluar, err := s.repo.GetAnggotaKeluar(ctx, schemaName, tanggal, anggotaIDs)
+	if err != nil {
+		return err
+	}
+	if err := cekAnggotaAktif(keluar); err != nil {
+		return err
+	}
+	before, err := s.repo.GetPresensiByTanggal(ctx, schemaName, tanggal, anggotaIDs)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.UpsertPresensiBulk(ctx, schemaName, tanggal, input.Data); err != nil {
+		return err
+	}
+
+	// Hanya siswa yang status atau catatannya berubah yang dicatat.
+	var entries []audit.Entry
+	for _, item := range input.Data {
+		after := presensiAudit{Tanggal: input.Tanggal, Status: item.Status, Catatan: item.Catatan}
+		entry := audit.Entry{Action: audit.ActionCreate, Entity: "presensi", EntityID: item.AnggotaKelasID, After: after}
+		if old, ok := before[item.AnggotaKelasID]; ok {
+			if old.Status == item.Status && sameCatatan(old.Catatan, item.Catatan) {
+				continue
+			}
+			entry.Action = audit.ActionUpdate
+			entry.Before = presensiAudit{Tanggal: input.Tanggal, Status: old.Status, Catatan: old.Catatan}
+		}
+		entries = append(entries, entry)
+	}
+	s.audit.Record(ctx, schemaName, entries...)
+	return nil
+}
+
+func sameCatatan(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
 
-	return s.repo.UpsertPresensiBulk(ctx, schemaName, tanggal, input.Data)
+// cekAnggotaAktif menolak presensi untuk siswa yang sudah keluar dari kelas pada tanggal
+// presensi, yaitu pada atau setelah tanggal keluarnya. Semua anggota yang ditolak disebut,
+// diurutkan menurut id agar pesannya stabil.
+func cekAnggotaAktif(keluar map[string]time.Time) error {
+	if len(keluar) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(keluar))
+	for id := range keluar {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	rincian := make([]string, len(ids))
+	for i, id := range ids {
+		rincian[i] = fmt.Sprintf("%s (keluar %s)", id, keluar[id].Format("2006-01-02"))
+	}
+	return fmt.Errorf("%w: anggota kelas sudah keluar dari kelas: %s", ErrValidation, strings.Join(rincian, ", "))
+}
+
+// cekHariSekolah menolak presensi pada akhir pekan dan hari libur kalender akademik.
+func cekHariSekolah(ctx context.Context, k kalender.Service, schemaName string, kelasID string, tanggal time.Time) error {
+	err := k.CekHariSekolah(ctx, schemaName, kelasID, tanggal)
+	if errors.Is(err, kalender.ErrBukanHariSekolah) {
+		return fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+	return err
 }
@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"skoola/pkg/database"
 )
 
 // Repository mendefinisikan interface untuk interaksi database prestasi.
@@ -23,32 +24,31 @@ func NewRepository(db *sql.DB) Repository {
 	return &postgresRepository{db: db}
 }
 
-func (r *postgresRepository) setSchema(ctx context.Context, schemaName string) error {
-	_, err := r.db.ExecContext(ctx, fmt.Sprintf("SET search_path TO %q", schemaName))
-	return err
-}
-
 func (r *postgresRepository) Create(ctx context.Context, schemaName string, p *Prestasi) (*Prestasi, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 	query := `
 		INSERT INTO prestasi_siswa (id, tahun_ajaran_id, anggota_kelas_id, nama_prestasi, tingkat, peringkat, tanggal, deskripsi)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING id
 	`
-	err := r.db.QueryRowContext(ctx, query, p.ID, p.TahunAjaranID, p.AnggotaKelasID, p.NamaPrestasi, p.Tingkat, p.Peringkat, p.Tanggal, p.Deskripsi).Scan(&p.ID)
+	err = tx.QueryRowContext(ctx, query, p.ID, p.TahunAjaranID, p.AnggotaKelasID, p.NamaPrestasi, p.Tingkat, p.Peringkat, p.Tanggal, p.Deskripsi).Scan(&p.ID)
 	if err != nil {
 		return nil, fmt.Errorf("gagal insert prestasi: %w", err)
 	}
 
-	return p, nil
+	return p, tx.Commit()
 }
 
 func (r *postgresRepository) GetAllByTahunAjaran(ctx context.Context, schemaName string, tahunAjaranID string) ([]Prestasi, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `
 		SELECT 
@@ -63,7 +63,7 @@ func (r *postgresRepository) GetAllByTahunAjaran(ctx context.Context, schemaName
 		WHERE p.tahun_ajaran_id = $1
 		ORDER BY p.tanggal DESC, s.nama_lengkap ASC
 	`
-	rows, err := r.db.QueryContext(ctx, query, tahunAjaranID)
+	rows, err := tx.QueryContext(ctx, query, tahunAjaranID)
 	if err != nil {
 		return nil, fmt.Errorf("gagal query get all prestasi: %w", err)
 	}
@@ -81,15 +81,20 @@ func (r *postgresRepository) GetAllByTahunAjaran(ctx context.Context, schemaName
 		}
 		list = append(list, p)
 	}
-	return list, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
 }
 
 func (r *postgresRepository) Delete(ctx context.Context, schemaName string, id string) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 	query := `DELETE FROM prestasi_siswa WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := tx.ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("gagal mengeksekusi query delete: %w", err)
 	}
@@ -97,5 +102,5 @@ func (r *postgresRepository) Delete(ctx context.Context, schemaName string, id s
 	if rowsAffected == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+	return tx.Commit()
 }
@@ -0,0 +1,94 @@
+// file: backend/internal/rapor/handler.go
+package rapor
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"skoola/internal/access"
+	"skoola/internal/middleware"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{service: s}
+}
+
+// GetRapor adalah handler untuk GET /rapor/anggota/{anggotaKelasID}.
+func (h *Handler) GetRapor(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	anggotaKelasID := chi.URLParam(r, "anggotaKelasID")
+
+	result, err := h.service.GetRapor(r.Context(), schemaName, access.ActorFromContext(r.Context()), anggotaKelasID)
+	if err != nil {
+		writeError(w, "Gagal mengambil data rapor: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// GetRaporPDF adalah handler untuk GET /rapor/anggota/{anggotaKelasID}/pdf?paper_size_id=...
+func (h *Handler) GetRaporPDF(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	anggotaKelasID := chi.URLParam(r, "anggotaKelasID")
+
+	content, err := h.service.GenerateRaporPDF(r.Context(), schemaName, access.ActorFromContext(r.Context()), anggotaKelasID, r.URL.Query().Get("paper_size_id"))
+	if err != nil {
+		writeError(w, "Gagal membuat PDF rapor: ", err)
+		return
+	}
+
+	writeFile(w, fmt.Sprintf("rapor_%s.pdf", anggotaKelasID), "application/pdf", content)
+}
+
+// GetKelasRapor adalah handler untuk GET /rapor/kelas/{kelasID}?format=pdf|zip&paper_size_id=...
+func (h *Handler) GetKelasRapor(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	kelasID := chi.URLParam(r, "kelasID")
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = FormatPDF
+	}
+
+	content, err := h.service.GenerateKelasRapor(r.Context(), schemaName, access.ActorFromContext(r.Context()), kelasID, format, r.URL.Query().Get("paper_size_id"))
+	if err != nil {
+		writeError(w, "Gagal membuat rapor kelas: ", err)
+		return
+	}
+
+	contentType := "application/pdf"
+	if format == FormatZIP {
+		contentType = "application/zip"
+	}
+	writeFile(w, fmt.Sprintf("rapor_kelas_%s.%s", kelasID, format), contentType, content)
+}
+
+func writeFile(w http.ResponseWriter, filename string, contentType string, content []byte) {
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	w.Write(content)
+}
+
+func writeError(w http.ResponseWriter, prefix string, err error) {
+	switch {
+	case errors.Is(err, access.ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, ErrValidation):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Data rapor tidak ditemukan", http.StatusNotFound)
+	default:
+		http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+	}
+}
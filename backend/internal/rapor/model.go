@@ -0,0 +1,92 @@
+// file: backend/internal/rapor/model.go
+package rapor
+
+import (
+	"skoola/internal/profile"
+	"time"
+)
+
+// Format ekspor rapor satu kelas.
+const (
+	FormatPDF = "pdf"
+	FormatZIP = "zip"
+)
+
+// RaporSiswa adalah identitas siswa pada rapor.
+type RaporSiswa struct {
+	AnggotaKelasID string  `json:"anggota_kelas_id"`
+	StudentID      string  `json:"student_id"`
+	NamaLengkap    string  `json:"nama_lengkap"`
+	NIS            *string `json:"nis"`
+	NISN           *string `json:"nisn"`
+}
+
+// RaporKelas adalah identitas kelas, tahun ajaran, dan wali kelas pada rapor.
+type RaporKelas struct {
+	KelasID         string  `json:"kelas_id"`
+	NamaKelas       string  `json:"nama_kelas"`
+	NamaTingkatan   string  `json:"nama_tingkatan"`
+	TahunAjaranID   string  `json:"tahun_ajaran_id"`
+	NamaTahunAjaran string  `json:"nama_tahun_ajaran"`
+	Semester        string  `json:"semester"`
+	NamaWaliKelas   *string `json:"nama_wali_kelas"`
+	NIPWaliKelas    *string `json:"nip_wali_kelas"`
+}
+
+// NilaiMapel adalah nilai satu mata pelajaran. NilaiFormatif dan NilaiSumatif adalah
+// rata-rata nilai yang sudah diinput; NilaiAkhir dihitung dengan aturan nilai tahun ajaran
+// (paket nilaiakhir) dan merupakan nilai yang dicetak di rapor. Deskripsi adalah
+// capaian kompetensi dari paket deskripsi.
+type NilaiMapel struct {
+	PengajarKelasID string   `json:"pengajar_kelas_id"`
+	NamaMapel       string   `json:"nama_mapel"`
+	NilaiFormatif   *float64 `json:"nilai_formatif"`
+	NilaiSumatif    *float64 `json:"nilai_sumatif"`
+	NilaiAkhir      *float64 `json:"nilai_akhir"`
+	Deskripsi       string   `json:"deskripsi"`
+}
+
+// KelompokNilai mengelompokkan nilai mapel sesuai kelompok mata pelajaran.
+type KelompokNilai struct {
+	NamaKelompok string       `json:"nama_kelompok"`
+	Mapel        []NilaiMapel `json:"mapel"`
+}
+
+// Kehadiran adalah rekap ketidakhadiran siswa selama satu semester.
+type Kehadiran struct {
+	Sakit int `json:"sakit"`
+	Izin  int `json:"izin"`
+	Alpa  int `json:"alpa"`
+}
+
+// PrestasiRapor adalah satu prestasi yang dicetak di rapor.
+type PrestasiRapor struct {
+	NamaPrestasi string `json:"nama_prestasi"`
+	Tingkat      string `json:"tingkat"`
+	Peringkat    string `json:"peringkat"`
+}
+
+// Rapor adalah seluruh isi rapor satu anggota kelas pada satu tahun ajaran.
+type Rapor struct {
+	Sekolah         profile.ProfilSekolah `json:"sekolah"`
+	Siswa           RaporSiswa            `json:"siswa"`
+	Kelas           RaporKelas            `json:"kelas"`
+	KelompokNilai   []KelompokNilai       `json:"kelompok_nilai"`
+	Kehadiran       Kehadiran             `json:"kehadiran"`
+	Prestasi        []PrestasiRapor       `json:"prestasi"`
+	Ekstrakurikuler []string              `json:"ekstrakurikuler"`
+	TanggalRapor    time.Time             `json:"tanggal_rapor"`
+}
+
+// mapelKelas adalah mata pelajaran yang diajarkan di kelas beserta kelompoknya.
+type mapelKelas struct {
+	PengajarKelasID string
+	NamaMapel       string
+	NamaKelompok    *string
+}
+
+// nilaiRataRata adalah rata-rata nilai formatif dan sumatif satu siswa pada satu mapel.
+type nilaiRataRata struct {
+	Formatif *float64
+	Sumatif  *float64
+}
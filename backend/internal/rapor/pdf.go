@@ -0,0 +1,293 @@
+// file: backend/internal/rapor/pdf.go
+package rapor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"skoola/internal/papersize"
+	"strings"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+var namaBulan = []string{"", "Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember"}
+
+// tanggalIndonesia memformat tanggal seperti "17 Agustus 2025".
+func tanggalIndonesia(t time.Time) string {
+	return fmt.Sprintf("%d %s %d", t.Day(), namaBulan[t.Month()], t.Year())
+}
+
+// toMM mengubah ukuran dari satuan paper_size ke milimeter.
+func toMM(v float64, satuan string) float64 {
+	switch satuan {
+	case "cm":
+		return v * 10
+	case "in":
+		return v * 25.4
+	default:
+		return v
+	}
+}
+
+// newPDF membuat dokumen sesuai ukuran kertas. Nil berarti A4 dengan margin 20 mm.
+func newPDF(paper *papersize.PaperSize) *gofpdf.Fpdf {
+	width, height := 210.0, 297.0
+	top, bottom, left, right := 20.0, 20.0, 20.0, 20.0
+	if paper != nil {
+		width, height = toMM(paper.Lebar, paper.Satuan), toMM(paper.Panjang, paper.Satuan)
+		top, bottom = toMM(paper.MarginAtas, paper.Satuan), toMM(paper.MarginBawah, paper.Satuan)
+		left, right = toMM(paper.MarginKiri, paper.Satuan), toMM(paper.MarginKanan, paper.Satuan)
+	}
+
+	pdf := gofpdf.NewCustom(&gofpdf.InitType{
+		OrientationStr: "P",
+		UnitStr:        "mm",
+		Size:           gofpdf.SizeType{Wd: width, Ht: height},
+	})
+	pdf.SetMargins(left, top, right)
+	pdf.SetAutoPageBreak(true, bottom)
+	return pdf
+}
+
+// renderPDF mencetak setiap rapor mulai dari halaman baru di dalam satu dokumen.
+func renderPDF(paper *papersize.PaperSize, list []Rapor) ([]byte, error) {
+	pdf := newPDF(paper)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	for _, r := range list {
+		renderRapor(pdf, tr, r)
+		if pdf.Err() {
+			return nil, fmt.Errorf("gagal merender rapor %s: %w", r.Siswa.NamaLengkap, pdf.Error())
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("gagal menghasilkan file PDF: %w", err)
+	}
+	if buf.Len() == 0 {
+		return nil, errors.New("output PDF kosong")
+	}
+	return buf.Bytes(), nil
+}
+
+func renderRapor(pdf *gofpdf.Fpdf, tr func(string) string, r Rapor) {
+	pdf.AddPage()
+	left, _, right, _ := pdf.GetMargins()
+	pageWidth, _ := pdf.GetPageSize()
+	contentWidth := pageWidth - left - right
+
+	// 1. Kop sekolah
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(contentWidth, 7, tr(strings.ToUpper(r.Sekolah.NamaSekolah)), "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	if alamat := alamatSekolah(r); alamat != "" {
+		pdf.MultiCell(contentWidth, 4.5, tr(alamat), "", "C", false)
+	}
+	if r.Sekolah.NPSN != nil && *r.Sekolah.NPSN != "" {
+		pdf.CellFormat(contentWidth, 4.5, tr("NPSN: "+*r.Sekolah.NPSN), "", 1, "C", false, 0, "")
+	}
+	y := pdf.GetY() + 1
+	pdf.SetLineWidth(0.6)
+	pdf.Line(left, y, left+contentWidth, y)
+	pdf.SetLineWidth(0.2)
+	pdf.Ln(5)
+
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(contentWidth, 7, "LAPORAN HASIL BELAJAR", "", 1, "C", false, 0, "")
+	pdf.Ln(2)
+
+	// 2. Identitas siswa
+	pdf.SetFont("Helvetica", "", 10)
+	nisNisn := strings.TrimSpace(derefString(r.Siswa.NIS) + " / " + derefString(r.Siswa.NISN))
+	half := contentWidth / 2
+	identitas := [][2][2]string{
+		{{"Nama", r.Siswa.NamaLengkap}, {"Kelas", r.Kelas.NamaKelas}},
+		{{"NIS / NISN", nisNisn}, {"Semester", r.Kelas.Semester}},
+		{{"Sekolah", r.Sekolah.NamaSekolah}, {"Tahun Pelajaran", r.Kelas.NamaTahunAjaran}},
+	}
+	for _, row := range identitas {
+		for _, col := range row {
+			pdf.CellFormat(28, 6, tr(col[0]), "", 0, "L", false, 0, "")
+			pdf.CellFormat(half-28, 6, tr(": "+col[1]), "", 0, "L", false, 0, "")
+		}
+		pdf.Ln(6)
+	}
+	pdf.Ln(3)
+
+	// 3. Nilai per kelompok mapel
+	sectionTitle(pdf, tr, "A. Nilai Akademik")
+	colNo, colMapel, colNilai := 10.0, 42.0, 16.0
+	colCapaian := contentWidth - colNo - colMapel - 3*colNilai
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.SetFillColor(230, 230, 230)
+	for _, h := range []struct {
+		w     float64
+		label string
+	}{{colNo, "No"}, {colMapel, "Mata Pelajaran"}, {colNilai, "Formatif"}, {colNilai, "Sumatif"}, {colNilai, "Akhir"}, {colCapaian, "Capaian Kompetensi"}} {
+		pdf.CellFormat(h.w, 7, h.label, "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	no := 1
+	for _, k := range r.KelompokNilai {
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.SetFillColor(245, 245, 245)
+		pdf.CellFormat(contentWidth, 6, tr(k.NamaKelompok), "1", 1, "L", true, 0, "")
+		pdf.SetFont("Helvetica", "", 9)
+		for _, m := range k.Mapel {
+			// Tinggi baris mengikuti panjang deskripsi capaian.
+			pdf.SetFont("Helvetica", "", 8)
+			capaian := pdf.SplitText(tr(m.Deskripsi), colCapaian-2)
+			rowHeight := math.Max(6, float64(len(capaian))*4.5+1.5)
+			pdf.SetFont("Helvetica", "", 9)
+			_, pageHeight := pdf.GetPageSize()
+			_, _, _, bottom := pdf.GetMargins()
+			if pdf.GetY()+rowHeight > pageHeight-bottom {
+				pdf.AddPage()
+			}
+
+			x, y := pdf.GetXY()
+			pdf.CellFormat(colNo, rowHeight, fmt.Sprintf("%d", no), "1", 0, "C", false, 0, "")
+			pdf.CellFormat(colMapel, rowHeight, tr(m.NamaMapel), "1", 0, "L", false, 0, "")
+			pdf.CellFormat(colNilai, rowHeight, formatNilai(m.NilaiFormatif), "1", 0, "C", false, 0, "")
+			pdf.CellFormat(colNilai, rowHeight, formatNilai(m.NilaiSumatif), "1", 0, "C", false, 0, "")
+			pdf.SetFont("Helvetica", "B", 9)
+			pdf.CellFormat(colNilai, rowHeight, formatNilai(m.NilaiAkhir), "1", 0, "C", false, 0, "")
+			pdf.SetFont("Helvetica", "", 8)
+			capaianX := x + colNo + colMapel + 3*colNilai
+			pdf.Rect(capaianX, y, colCapaian, rowHeight, "D")
+			pdf.SetXY(capaianX, y+0.75)
+			pdf.MultiCell(colCapaian, 4.5, tr(m.Deskripsi), "", "L", false)
+			pdf.SetXY(x, y+rowHeight)
+			pdf.SetFont("Helvetica", "", 9)
+			no++
+		}
+	}
+	pdf.Ln(4)
+
+	// 4. Ekstrakurikuler
+	sectionTitle(pdf, tr, "B. Ekstrakurikuler")
+	pdf.SetFont("Helvetica", "", 9)
+	if len(r.Ekstrakurikuler) == 0 {
+		pdf.CellFormat(contentWidth, 6, "-", "1", 1, "C", false, 0, "")
+	}
+	for i, e := range r.Ekstrakurikuler {
+		pdf.CellFormat(colNo, 6, fmt.Sprintf("%d", i+1), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(contentWidth-colNo, 6, tr(e), "1", 1, "L", false, 0, "")
+	}
+	pdf.Ln(4)
+
+	// 5. Prestasi
+	sectionTitle(pdf, tr, "C. Prestasi")
+	pdf.SetFont("Helvetica", "", 9)
+	if len(r.Prestasi) == 0 {
+		pdf.CellFormat(contentWidth, 6, "-", "1", 1, "C", false, 0, "")
+	}
+	colTingkat := 40.0
+	for i, p := range r.Prestasi {
+		pdf.CellFormat(colNo, 6, fmt.Sprintf("%d", i+1), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(contentWidth-colNo-2*colTingkat, 6, tr(p.NamaPrestasi), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(colTingkat, 6, tr(p.Tingkat), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(colTingkat, 6, tr(p.Peringkat), "1", 1, "C", false, 0, "")
+	}
+	pdf.Ln(4)
+
+	// 6. Rekap ketidakhadiran
+	sectionTitle(pdf, tr, "D. Ketidakhadiran")
+	pdf.SetFont("Helvetica", "", 9)
+	for _, k := range []struct {
+		label  string
+		jumlah int
+	}{{"Sakit", r.Kehadiran.Sakit}, {"Izin", r.Kehadiran.Izin}, {"Tanpa Keterangan", r.Kehadiran.Alpa}} {
+		pdf.CellFormat(60, 6, k.label, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(30, 6, fmt.Sprintf("%d hari", k.jumlah), "1", 1, "C", false, 0, "")
+	}
+	pdf.Ln(6)
+
+	// 7. Tanda tangan. Pindah halaman jika ruang yang tersisa tidak cukup.
+	_, pageHeight := pdf.GetPageSize()
+	_, _, _, bottom := pdf.GetMargins()
+	if pdf.GetY()+60 > pageHeight-bottom {
+		pdf.AddPage()
+	}
+	renderSignatures(pdf, tr, r, left, contentWidth)
+}
+
+func renderSignatures(pdf *gofpdf.Fpdf, tr func(string) string, r Rapor, left float64, contentWidth float64) {
+	colWidth := contentWidth / 2
+	kota := derefString(r.Sekolah.KotaKabupaten)
+	tempatTanggal := tanggalIndonesia(r.TanggalRapor)
+	if kota != "" {
+		tempatTanggal = kota + ", " + tempatTanggal
+	}
+
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetX(left)
+	pdf.CellFormat(colWidth, 5, "", "", 0, "C", false, 0, "")
+	pdf.CellFormat(colWidth, 5, tr(tempatTanggal), "", 1, "C", false, 0, "")
+	pdf.SetX(left)
+	pdf.CellFormat(colWidth, 5, "Orang Tua/Wali", "", 0, "C", false, 0, "")
+	pdf.CellFormat(colWidth, 5, "Wali Kelas", "", 1, "C", false, 0, "")
+	pdf.Ln(18)
+
+	pdf.SetX(left)
+	pdf.CellFormat(colWidth, 5, "(....................................)", "", 0, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "BU", 10)
+	pdf.CellFormat(colWidth, 5, tr(orDots(r.Kelas.NamaWaliKelas)), "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.SetX(left + colWidth)
+	pdf.CellFormat(colWidth, 5, tr("NIP. "+derefString(r.Kelas.NIPWaliKelas)), "", 1, "C", false, 0, "")
+	pdf.Ln(6)
+
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetX(left)
+	pdf.CellFormat(contentWidth, 5, "Mengetahui,", "", 1, "C", false, 0, "")
+	pdf.CellFormat(contentWidth, 5, "Kepala Sekolah", "", 1, "C", false, 0, "")
+	pdf.Ln(18)
+	pdf.SetFont("Helvetica", "BU", 10)
+	pdf.CellFormat(contentWidth, 5, tr(orDots(r.Sekolah.KepalaSekolah)), "", 1, "C", false, 0, "")
+}
+
+func sectionTitle(pdf *gofpdf.Fpdf, tr func(string) string, title string) {
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(0, 7, tr(title), "", 1, "L", false, 0, "")
+}
+
+func alamatSekolah(r Rapor) string {
+	var parts []string
+	for _, p := range []*string{r.Sekolah.Alamat, r.Sekolah.Kelurahan, r.Sekolah.Kecamatan, r.Sekolah.KotaKabupaten, r.Sekolah.Provinsi} {
+		if v := derefString(p); v != "" {
+			parts = append(parts, v)
+		}
+	}
+	alamat := strings.Join(parts, ", ")
+	if telp := derefString(r.Sekolah.Telepon); telp != "" {
+		alamat += " - Telp. " + telp
+	}
+	return alamat
+}
+
+func formatNilai(v *float64) string {
+	if v == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f", math.Round(*v))
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return strings.TrimSpace(*s)
+}
+
+func orDots(s *string) string {
+	if v := derefString(s); v != "" {
+		return v
+	}
+	return "...................................."
+}
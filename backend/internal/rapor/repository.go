@@ -0,0 +1,349 @@
+// file: backend/internal/rapor/repository.go
+package rapor
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"skoola/pkg/database"
+)
+
+// Repository mendefinisikan query baca-saja untuk menyusun rapor satu kelas.
+type Repository interface {
+	GetKelasIDByAnggota(ctx context.Context, schemaName string, anggotaKelasID string) (string, error)
+	GetKelas(ctx context.Context, schemaName string, kelasID string) (*RaporKelas, error)
+	// GetSiswa dan GetPrestasi hanya memuat anggota kelas yang belum keluar ketika semester
+	// berakhir. Acuannya tanggal selesai kalender akademik, atau hari ini jika belum diisi,
+	// agar rapor semester lalu tetap bisa dicetak untuk siswa yang sudah mutasi.
+	GetSiswa(ctx context.Context, schemaName string, kelasID string) ([]RaporSiswa, error)
+	GetMapel(ctx context.Context, schemaName string, kelasID string) ([]mapelKelas, error)
+	GetNilai(ctx context.Context, schemaName string, kelasID string) (map[string]map[string]nilaiRataRata, error)
+	GetKehadiran(ctx context.Context, schemaName string, kelasID string) (map[string]Kehadiran, error)
+	GetPrestasi(ctx context.Context, schemaName string, kelasID string) (map[string][]PrestasiRapor, error)
+	GetEkstrakurikuler(ctx context.Context, schemaName string, kelasID string, tahunAjaranID string) (map[string][]string, error)
+}
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewRepository membuat instance baru dari postgresRepository.
+func NewRepository(db *sql.DB) Repository {
+	return &postgresRepository{db: db}
+}
+
+func (r *postgresRepository) GetKelasIDByAnggota(ctx context.Context, schemaName string, anggotaKelasID string) (string, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	var kelasID string
+	if err := tx.QueryRowContext(ctx, `SELECT kelas_id FROM anggota_kelas WHERE id = $1`, anggotaKelasID).Scan(&kelasID); err != nil {
+		return "", err
+	}
+	return kelasID, tx.Commit()
+}
+
+func (r *postgresRepository) GetKelas(ctx context.Context, schemaName string, kelasID string) (*RaporKelas, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT k.id, k.nama_kelas, t.nama_tingkatan, ta.id, ta.nama_tahun_ajaran, ta.semester,
+		       g.nama_lengkap, g.nip_nuptk
+		FROM kelas k
+		JOIN tingkatan t ON k.tingkatan_id = t.id
+		JOIN tahun_ajaran ta ON k.tahun_ajaran_id = ta.id
+		LEFT JOIN teachers g ON k.wali_kelas_id = g.id
+		WHERE k.id = $1
+	`
+	var k RaporKelas
+	err = tx.QueryRowContext(ctx, query, kelasID).Scan(
+		&k.KelasID, &k.NamaKelas, &k.NamaTingkatan, &k.TahunAjaranID, &k.NamaTahunAjaran, &k.Semester,
+		&k.NamaWaliKelas, &k.NIPWaliKelas,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("gagal mengambil data kelas rapor: %w", err)
+	}
+	return &k, tx.Commit()
+}
+
+func (r *postgresRepository) GetSiswa(ctx context.Context, schemaName string, kelasID string) ([]RaporSiswa, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT ak.id, s.id, s.nama_lengkap, s.nis, s.nisn
+		FROM anggota_kelas ak
+		JOIN students s ON ak.student_id = s.id
+		JOIN kelas k ON ak.kelas_id = k.id
+		LEFT JOIN kalender_pengaturan kp ON kp.tahun_ajaran_id = k.tahun_ajaran_id
+		WHERE ak.kelas_id = $1 AND (ak.tanggal_keluar IS NULL OR ak.tanggal_keluar > COALESCE(kp.tanggal_selesai, CURRENT_DATE))
+		ORDER BY ak.urutan ASC, s.nama_lengkap ASC
+	`
+	rows, err := tx.QueryContext(ctx, query, kelasID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil siswa rapor: %w", err)
+	}
+	defer rows.Close()
+
+	list := []RaporSiswa{}
+	for rows.Next() {
+		var s RaporSiswa
+		if err := rows.Scan(&s.AnggotaKelasID, &s.StudentID, &s.NamaLengkap, &s.NIS, &s.NISN); err != nil {
+			return nil, fmt.Errorf("gagal memindai siswa rapor: %w", err)
+		}
+		list = append(list, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+// GetMapel mengambil mapel kelas, diurutkan sesuai kelompok lalu urutan mapel.
+// Mapel tanpa kelompok diletakkan paling akhir.
+func (r *postgresRepository) GetMapel(ctx context.Context, schemaName string, kelasID string) ([]mapelKelas, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT pk.id, mp.nama_mapel, kmp.nama_kelompok
+		FROM pengajar_kelas pk
+		JOIN mata_pelajaran mp ON pk.mata_pelajaran_id = mp.id
+		LEFT JOIN kelompok_mata_pelajaran kmp ON mp.kelompok_id = kmp.id
+		WHERE pk.kelas_id = $1
+		ORDER BY kmp.urutan ASC NULLS LAST, kmp.nama_kelompok ASC, mp.urutan ASC, mp.nama_mapel ASC
+	`
+	rows, err := tx.QueryContext(ctx, query, kelasID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil mapel rapor: %w", err)
+	}
+	defer rows.Close()
+
+	list := []mapelKelas{}
+	for rows.Next() {
+		var m mapelKelas
+		if err := rows.Scan(&m.PengajarKelasID, &m.NamaMapel, &m.NamaKelompok); err != nil {
+			return nil, fmt.Errorf("gagal memindai mapel rapor: %w", err)
+		}
+		list = append(list, m)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+// GetNilai menghitung rata-rata nilai formatif dan sumatif setiap siswa per mapel.
+// Hasilnya map[anggota_kelas_id]map[pengajar_kelas_id]nilaiRataRata.
+func (r *postgresRepository) GetNilai(ctx context.Context, schemaName string, kelasID string) (map[string]map[string]nilaiRataRata, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result := make(map[string]map[string]nilaiRataRata)
+	get := func(anggotaID, pkID string) nilaiRataRata {
+		if result[anggotaID] == nil {
+			result[anggotaID] = make(map[string]nilaiRataRata)
+		}
+		return result[anggotaID][pkID]
+	}
+
+	formatifQuery := `
+		SELECT p.anggota_kelas_id, m.pengajar_kelas_id, AVG(p.nilai)::float8
+		FROM penilaian p
+		JOIN anggota_kelas ak ON p.anggota_kelas_id = ak.id
+		JOIN tujuan_pembelajaran tp ON p.tujuan_pembelajaran_id = tp.id
+		JOIN materi_pembelajaran m ON tp.materi_pembelajaran_id = m.id
+		WHERE ak.kelas_id = $1 AND p.nilai IS NOT NULL
+		GROUP BY p.anggota_kelas_id, m.pengajar_kelas_id
+	`
+	rows, err := tx.QueryContext(ctx, formatifQuery, kelasID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menghitung nilai formatif: %w", err)
+	}
+	for rows.Next() {
+		var anggotaID, pkID string
+		var avg float64
+		if err := rows.Scan(&anggotaID, &pkID, &avg); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("gagal memindai nilai formatif: %w", err)
+		}
+		n := get(anggotaID, pkID)
+		n.Formatif = &avg
+		result[anggotaID][pkID] = n
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sumatifQuery := `
+		SELECT ns.anggota_kelas_id, COALESCE(m.pengajar_kelas_id, u.pengajar_kelas_id) AS pk_id, AVG(ns.nilai)::float8
+		FROM nilai_sumatif_siswa ns
+		JOIN anggota_kelas ak ON ns.anggota_kelas_id = ak.id
+		JOIN penilaian_sumatif ps ON ns.penilaian_sumatif_id = ps.id
+		LEFT JOIN tujuan_pembelajaran tp ON ps.tujuan_pembelajaran_id = tp.id
+		LEFT JOIN materi_pembelajaran m ON tp.materi_pembelajaran_id = m.id
+		LEFT JOIN ujian u ON ps.ujian_id = u.id
+		WHERE ak.kelas_id = $1 AND ns.nilai IS NOT NULL
+		  AND COALESCE(m.pengajar_kelas_id, u.pengajar_kelas_id) IS NOT NULL
+		GROUP BY ns.anggota_kelas_id, pk_id
+	`
+	rows, err = tx.QueryContext(ctx, sumatifQuery, kelasID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menghitung nilai sumatif: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var anggotaID, pkID string
+		var avg float64
+		if err := rows.Scan(&anggotaID, &pkID, &avg); err != nil {
+			return nil, fmt.Errorf("gagal memindai nilai sumatif: %w", err)
+		}
+		n := get(anggotaID, pkID)
+		n.Sumatif = &avg
+		result[anggotaID][pkID] = n
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, tx.Commit()
+}
+
+// GetKehadiran menghitung jumlah hari sakit, izin, dan alpa setiap anggota kelas.
+func (r *postgresRepository) GetKehadiran(ctx context.Context, schemaName string, kelasID string) (map[string]Kehadiran, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT p.anggota_kelas_id, p.status::text, COUNT(*)
+		FROM presensi p
+		JOIN anggota_kelas ak ON p.anggota_kelas_id = ak.id
+		WHERE ak.kelas_id = $1 AND p.status IN ('S', 'I', 'A')
+		GROUP BY p.anggota_kelas_id, p.status
+	`
+	rows, err := tx.QueryContext(ctx, query, kelasID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menghitung kehadiran: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[string]Kehadiran)
+	for rows.Next() {
+		var anggotaID, status string
+		var jumlah int
+		if err := rows.Scan(&anggotaID, &status, &jumlah); err != nil {
+			return nil, fmt.Errorf("gagal memindai kehadiran: %w", err)
+		}
+		k := result[anggotaID]
+		switch status {
+		case "S":
+			k.Sakit = jumlah
+		case "I":
+			k.Izin = jumlah
+		case "A":
+			k.Alpa = jumlah
+		}
+		result[anggotaID] = k
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, tx.Commit()
+}
+
+func (r *postgresRepository) GetPrestasi(ctx context.Context, schemaName string, kelasID string) (map[string][]PrestasiRapor, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT ps.anggota_kelas_id, ps.nama_prestasi, ps.tingkat, ps.peringkat
+		FROM prestasi_siswa ps
+		JOIN anggota_kelas ak ON ps.anggota_kelas_id = ak.id
+		JOIN kelas k ON ak.kelas_id = k.id
+		LEFT JOIN kalender_pengaturan kp ON kp.tahun_ajaran_id = k.tahun_ajaran_id
+		WHERE ak.kelas_id = $1 AND (ak.tanggal_keluar IS NULL OR ak.tanggal_keluar > COALESCE(kp.tanggal_selesai, CURRENT_DATE))
+		ORDER BY ps.tanggal ASC
+	`
+	rows, err := tx.QueryContext(ctx, query, kelasID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil prestasi rapor: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[string][]PrestasiRapor)
+	for rows.Next() {
+		var anggotaID string
+		var p PrestasiRapor
+		if err := rows.Scan(&anggotaID, &p.NamaPrestasi, &p.Tingkat, &p.Peringkat); err != nil {
+			return nil, fmt.Errorf("gagal memindai prestasi rapor: %w", err)
+		}
+		result[anggotaID] = append(result[anggotaID], p)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, tx.Commit()
+}
+
+// GetEkstrakurikuler mengambil nama ekstrakurikuler yang diikuti setiap siswa kelas
+// pada tahun ajaran kelas tersebut. Hasilnya dikunci dengan student_id.
+func (r *postgresRepository) GetEkstrakurikuler(ctx context.Context, schemaName string, kelasID string, tahunAjaranID string) (map[string][]string, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT ea.student_id, e.nama_kegiatan
+		FROM ekstrakurikuler_anggota ea
+		JOIN ekstrakurikuler_sesi es ON ea.sesi_id = es.id
+		JOIN ekstrakurikuler e ON es.ekstrakurikuler_id = e.id
+		JOIN anggota_kelas ak ON ak.student_id = ea.student_id AND ak.kelas_id = $1
+		WHERE es.tahun_ajaran_id = $2
+		ORDER BY e.nama_kegiatan ASC
+	`
+	rows, err := tx.QueryContext(ctx, query, kelasID, tahunAjaranID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil ekstrakurikuler rapor: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[string][]string)
+	for rows.Next() {
+		var studentID, nama string
+		if err := rows.Scan(&studentID, &nama); err != nil {
+			return nil, fmt.Errorf("gagal memindai ekstrakurikuler rapor: %w", err)
+		}
+		result[studentID] = append(result[studentID], nama)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, tx.Commit()
+}
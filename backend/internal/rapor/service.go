@@ -0,0 +1,267 @@
+// file: backend/internal/rapor/service.go
+package rapor
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"skoola/internal/access"
+	"skoola/internal/deskripsi"
+	"skoola/internal/nilaiakhir"
+	"skoola/internal/papersize"
+	"skoola/internal/profile"
+	"strings"
+	"time"
+)
+
+var ErrValidation = errors.New("validation failed")
+
+// Service mendefinisikan logika bisnis penyusunan dan pencetakan rapor.
+type Service interface {
+	GetRapor(ctx context.Context, schemaName string, actor access.Actor, anggotaKelasID string) (*Rapor, error)
+	GenerateRaporPDF(ctx context.Context, schemaName string, actor access.Actor, anggotaKelasID string, paperSizeID string) ([]byte, error)
+	GenerateKelasRapor(ctx context.Context, schemaName string, actor access.Actor, kelasID string, format string, paperSizeID string) ([]byte, error)
+}
+
+type service struct {
+	repo          Repository
+	nilaiAkhir    nilaiakhir.Service
+	deskripsi     deskripsi.Service
+	profileRepo   profile.Repository
+	paperSizeRepo papersize.Repository
+	access        access.Service
+}
+
+// NewService membuat instance baru dari service rapor.
+func NewService(repo Repository, nilaiAkhirService nilaiakhir.Service, deskripsiService deskripsi.Service, profileRepo profile.Repository, paperSizeRepo papersize.Repository, accessService access.Service) Service {
+	return &service{repo: repo, nilaiAkhir: nilaiAkhirService, deskripsi: deskripsiService, profileRepo: profileRepo, paperSizeRepo: paperSizeRepo, access: accessService}
+}
+
+func (s *service) GetRapor(ctx context.Context, schemaName string, actor access.Actor, anggotaKelasID string) (*Rapor, error) {
+	kelasID, err := s.repo.GetKelasIDByAnggota(ctx, schemaName, anggotaKelasID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.access.AuthorizeRead(ctx, schemaName, actor, access.Target{KelasIDs: []string{kelasID}}); err != nil {
+		return nil, err
+	}
+
+	list, err := s.buildKelas(ctx, schemaName, kelasID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		if list[i].Siswa.AnggotaKelasID == anggotaKelasID {
+			return &list[i], nil
+		}
+	}
+	return nil, fmt.Errorf("anggota kelas %s tidak ditemukan di kelasnya", anggotaKelasID)
+}
+
+func (s *service) GenerateRaporPDF(ctx context.Context, schemaName string, actor access.Actor, anggotaKelasID string, paperSizeID string) ([]byte, error) {
+	paper, err := s.resolvePaper(ctx, schemaName, paperSizeID)
+	if err != nil {
+		return nil, err
+	}
+	r, err := s.GetRapor(ctx, schemaName, actor, anggotaKelasID)
+	if err != nil {
+		return nil, err
+	}
+	return renderPDF(paper, []Rapor{*r})
+}
+
+// GenerateKelasRapor mencetak rapor semua anggota kelas, sebagai satu PDF
+// (FormatPDF) atau satu file PDF per siswa di dalam arsip ZIP (FormatZIP).
+func (s *service) GenerateKelasRapor(ctx context.Context, schemaName string, actor access.Actor, kelasID string, format string, paperSizeID string) ([]byte, error) {
+	if format != FormatPDF && format != FormatZIP {
+		return nil, fmt.Errorf("%w: format harus 'pdf' atau 'zip'", ErrValidation)
+	}
+	if err := s.access.AuthorizeRead(ctx, schemaName, actor, access.Target{KelasIDs: []string{kelasID}}); err != nil {
+		return nil, err
+	}
+	paper, err := s.resolvePaper(ctx, schemaName, paperSizeID)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := s.buildKelas(ctx, schemaName, kelasID)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("%w: kelas belum memiliki anggota", ErrValidation)
+	}
+
+	if format == FormatPDF {
+		return renderPDF(paper, list)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for i, r := range list {
+		content, err := renderPDF(paper, []Rapor{r})
+		if err != nil {
+			return nil, err
+		}
+		f, err := zw.Create(fmt.Sprintf("%02d_%s.pdf", i+1, fileSafe(r.Siswa.NamaLengkap)))
+		if err != nil {
+			return nil, fmt.Errorf("gagal menambah file ke ZIP: %w", err)
+		}
+		if _, err := f.Write(content); err != nil {
+			return nil, fmt.Errorf("gagal menulis file ke ZIP: %w", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("gagal menutup arsip ZIP: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// buildKelas menyusun rapor semua anggota kelas dengan sekali ambil data per jenis.
+func (s *service) buildKelas(ctx context.Context, schemaName string, kelasID string) ([]Rapor, error) {
+	sekolah, err := s.profileRepo.GetProfile(ctx, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	kelas, err := s.repo.GetKelas(ctx, schemaName, kelasID)
+	if err != nil {
+		return nil, err
+	}
+	siswa, err := s.repo.GetSiswa(ctx, schemaName, kelasID)
+	if err != nil {
+		return nil, err
+	}
+	mapel, err := s.repo.GetMapel(ctx, schemaName, kelasID)
+	if err != nil {
+		return nil, err
+	}
+	nilai, err := s.repo.GetNilai(ctx, schemaName, kelasID)
+	if err != nil {
+		return nil, err
+	}
+	akhir, err := s.nilaiAkhir.HitungKelas(ctx, schemaName, kelasID)
+	if err != nil {
+		return nil, err
+	}
+	capaian, err := s.deskripsi.DeskripsiKelas(ctx, schemaName, kelasID)
+	if err != nil {
+		return nil, err
+	}
+	kehadiran, err := s.repo.GetKehadiran(ctx, schemaName, kelasID)
+	if err != nil {
+		return nil, err
+	}
+	prestasi, err := s.repo.GetPrestasi(ctx, schemaName, kelasID)
+	if err != nil {
+		return nil, err
+	}
+	ekskul, err := s.repo.GetEkstrakurikuler(ctx, schemaName, kelasID, kelas.TahunAjaranID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	list := make([]Rapor, 0, len(siswa))
+	for _, sw := range siswa {
+		r := Rapor{
+			Sekolah:         *sekolah,
+			Siswa:           sw,
+			Kelas:           *kelas,
+			KelompokNilai:   groupNilai(mapel, nilai[sw.AnggotaKelasID], akhir, capaian, sw.AnggotaKelasID),
+			Kehadiran:       kehadiran[sw.AnggotaKelasID],
+			Prestasi:        prestasi[sw.AnggotaKelasID],
+			Ekstrakurikuler: ekskul[sw.StudentID],
+			TanggalRapor:    now,
+		}
+		if r.Prestasi == nil {
+			r.Prestasi = []PrestasiRapor{}
+		}
+		if r.Ekstrakurikuler == nil {
+			r.Ekstrakurikuler = []string{}
+		}
+		list = append(list, r)
+	}
+	return list, nil
+}
+
+// groupNilai menyusun nilai siswa per kelompok mapel mengikuti urutan mapel kelas.
+func groupNilai(mapel []mapelKelas, nilai map[string]nilaiRataRata, akhir map[string]map[string]nilaiakhir.NilaiAkhirSiswa, capaian map[string]map[string]string, anggotaKelasID string) []KelompokNilai {
+	groups := []KelompokNilai{}
+	index := make(map[string]int)
+	for _, m := range mapel {
+		nama := "Lainnya"
+		if m.NamaKelompok != nil {
+			nama = *m.NamaKelompok
+		}
+		i, ok := index[nama]
+		if !ok {
+			i = len(groups)
+			index[nama] = i
+			groups = append(groups, KelompokNilai{NamaKelompok: nama, Mapel: []NilaiMapel{}})
+		}
+		n := nilai[m.PengajarKelasID]
+		groups[i].Mapel = append(groups[i].Mapel, NilaiMapel{
+			PengajarKelasID: m.PengajarKelasID,
+			NamaMapel:       m.NamaMapel,
+			NilaiFormatif:   round2(n.Formatif),
+			NilaiSumatif:    round2(n.Sumatif),
+			NilaiAkhir:      akhir[m.PengajarKelasID][anggotaKelasID].NilaiAkhir,
+			Deskripsi:       capaian[m.PengajarKelasID][anggotaKelasID],
+		})
+	}
+	return groups
+}
+
+func round2(v *float64) *float64 {
+	if v == nil {
+		return nil
+	}
+	r := math.Round(*v*100) / 100
+	return &r
+}
+
+// resolvePaper memakai ukuran kertas yang diminta, atau A4 (atau ukuran pertama yang
+// tersedia) dari pengaturan ukuran kertas sekolah. Nil berarti memakai A4 bawaan.
+func (s *service) resolvePaper(ctx context.Context, schemaName string, paperSizeID string) (*papersize.PaperSize, error) {
+	if paperSizeID != "" {
+		p, err := s.paperSizeRepo.GetByID(ctx, schemaName, paperSizeID)
+		if err != nil {
+			return nil, err
+		}
+		if p == nil {
+			return nil, fmt.Errorf("%w: ukuran kertas tidak ditemukan", ErrValidation)
+		}
+		return p, nil
+	}
+
+	list, err := s.paperSizeRepo.GetAll(ctx, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		if strings.EqualFold(list[i].NamaKertas, "A4") {
+			return &list[i], nil
+		}
+	}
+	if len(list) > 0 {
+		return &list[0], nil
+	}
+	return nil, nil
+}
+
+// fileSafe mengubah nama siswa menjadi nama file yang aman.
+func fileSafe(name string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
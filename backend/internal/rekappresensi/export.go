@@ -0,0 +1,118 @@
+// file: backend/internal/rekappresensi/export.go
+package rekappresensi
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	sheetSiswa = "Rekap Siswa"
+	sheetKelas = "Rekap Kelas"
+)
+
+// renderExcel menulis rincian per siswa ke satu sheet, ditambah sheet ringkasan per kelas
+// untuk cakupan tingkatan dan sekolah.
+func renderExcel(l *Laporan) ([]byte, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	kepalaStyle, _ := f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true},
+		Fill:      excelize.Fill{Type: "pattern", Color: []string{"#E6E6E6"}, Pattern: 1},
+		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center", WrapText: true},
+	})
+	tandaStyle, _ := f.NewStyle(&excelize.Style{
+		Fill: excelize.Fill{Type: "pattern", Color: []string{"#FCE4E4"}, Pattern: 1},
+	})
+
+	index, err := f.NewSheet(sheetSiswa)
+	if err != nil {
+		return nil, err
+	}
+	kepala := []string{"No", "NIS", "Nama Siswa", "Kelas", "Hadir", "Sakit", "Izin", "Alpa", "Total",
+		"Hari Efektif", "Tidak Tercatat", "% Hadir", "% Sakit", "% Izin", "% Alpa", "Keterangan"}
+	barisData := tulisJudul(f, sheetSiswa, l, kepala, kepalaStyle)
+	for i, r := range l.Siswa {
+		ket := ""
+		if r.DiBawahBatas {
+			ket = "Di bawah batas kehadiran"
+		}
+		row := []interface{}{i + 1, derefString(r.NIS), r.NamaSiswa, r.NamaKelas, r.Jumlah.Hadir, r.Jumlah.Sakit,
+			r.Jumlah.Izin, r.Jumlah.Alpa, r.Jumlah.Total, r.Jumlah.HariEfektif, r.Jumlah.TidakTercatat, r.Persentase.Hadir, r.Persentase.Sakit, r.Persentase.Izin,
+			r.Persentase.Alpa, ket}
+		cell, _ := excelize.CoordinatesToCellName(1, barisData+i)
+		if err := f.SetSheetRow(sheetSiswa, cell, &row); err != nil {
+			return nil, err
+		}
+		if r.DiBawahBatas {
+			ujung, _ := excelize.CoordinatesToCellName(len(kepala), barisData+i)
+			f.SetCellStyle(sheetSiswa, cell, ujung, tandaStyle)
+		}
+	}
+	f.SetColWidth(sheetSiswa, "C", "C", 30)
+	f.SetColWidth(sheetSiswa, "P", "P", 24)
+
+	if len(l.Kelas) > 0 {
+		if _, err := f.NewSheet(sheetKelas); err != nil {
+			return nil, err
+		}
+		kepala := []string{"No", "Tingkatan", "Kelas", "Jumlah Siswa", "Hadir", "Sakit", "Izin", "Alpa", "Total",
+			"Hari Efektif", "Tidak Tercatat", "% Hadir", "% Sakit", "% Izin", "% Alpa", "Siswa di Bawah Batas"}
+		barisData := tulisJudul(f, sheetKelas, l, kepala, kepalaStyle)
+		for i, k := range l.Kelas {
+			row := []interface{}{i + 1, k.NamaTingkatan, k.NamaKelas, k.JumlahSiswa, k.Jumlah.Hadir, k.Jumlah.Sakit,
+				k.Jumlah.Izin, k.Jumlah.Alpa, k.Jumlah.Total, k.Jumlah.HariEfektif, k.Jumlah.TidakTercatat, k.Persentase.Hadir, k.Persentase.Sakit, k.Persentase.Izin,
+				k.Persentase.Alpa, k.SiswaDiBawahBatas}
+			cell, _ := excelize.CoordinatesToCellName(1, barisData+i)
+			if err := f.SetSheetRow(sheetKelas, cell, &row); err != nil {
+				return nil, err
+			}
+		}
+		total := []interface{}{"", "", "Total", len(l.Siswa), l.Jumlah.Hadir, l.Jumlah.Sakit, l.Jumlah.Izin, l.Jumlah.Alpa,
+			l.Jumlah.Total, l.Jumlah.HariEfektif, l.Jumlah.TidakTercatat, l.Persentase.Hadir, l.Persentase.Sakit, l.Persentase.Izin, l.Persentase.Alpa, l.SiswaDiBawahBatas}
+		cell, _ := excelize.CoordinatesToCellName(1, barisData+len(l.Kelas))
+		if err := f.SetSheetRow(sheetKelas, cell, &total); err != nil {
+			return nil, err
+		}
+		f.SetColWidth(sheetKelas, "B", "C", 18)
+	}
+
+	f.SetActiveSheet(index)
+	f.DeleteSheet("Sheet1")
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, fmt.Errorf("gagal menulis file excel: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// tulisJudul menulis judul, keterangan, dan baris kepala, lalu mengembalikan nomor baris
+// data pertama.
+func tulisJudul(f *excelize.File, sheet string, l *Laporan, kepala []string, style int) int {
+	akhir, _ := excelize.CoordinatesToCellName(len(kepala), 1)
+	f.MergeCell(sheet, "A1", akhir)
+	f.SetCellValue(sheet, "A1", l.Judul)
+	f.SetCellStyle(sheet, "A1", "A1", style)
+	f.SetCellValue(sheet, "A2", strings.Join(keterangan(l), " | "))
+
+	barisKepala := 4
+	for i, h := range kepala {
+		cell, _ := excelize.CoordinatesToCellName(i+1, barisKepala)
+		f.SetCellValue(sheet, cell, h)
+	}
+	awal, _ := excelize.CoordinatesToCellName(1, barisKepala)
+	ujung, _ := excelize.CoordinatesToCellName(len(kepala), barisKepala)
+	f.SetCellStyle(sheet, awal, ujung, style)
+	f.SetPanes(sheet, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      barisKepala,
+		TopLeftCell: fmt.Sprintf("A%d", barisKepala+1),
+		ActivePane:  "bottomLeft",
+	})
+	return barisKepala + 1
+}
@@ -0,0 +1,133 @@
+// file: backend/internal/rekappresensi/handler.go
+package rekappresensi
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"skoola/internal/access"
+	"skoola/internal/middleware"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{service: s}
+}
+
+// filterFromRequest membaca ?tahun_ajaran_id=...&dari=...&sampai=...&batas_kehadiran=...
+func filterFromRequest(r *http.Request) (Filter, error) {
+	q := r.URL.Query()
+	filter := Filter{TahunAjaranID: q.Get("tahun_ajaran_id"), Dari: q.Get("dari"), Sampai: q.Get("sampai")}
+	if v := q.Get("batas_kehadiran"); v != "" {
+		batas, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return filter, fmt.Errorf("%w: batas_kehadiran harus berupa angka", ErrValidation)
+		}
+		filter.BatasKehadiran = batas
+	}
+	return filter, nil
+}
+
+// GetRekapSiswa adalah handler untuk GET /rekap-presensi/siswa/{studentID}.
+func (h *Handler) GetRekapSiswa(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	filter, err := filterFromRequest(r)
+	if err != nil {
+		writeError(w, "", err)
+		return
+	}
+	laporan, err := h.service.GetRekapSiswa(r.Context(), schemaName, access.ActorFromContext(r.Context()), chi.URLParam(r, "studentID"), filter)
+	h.writeLaporan(w, r, laporan, err)
+}
+
+// GetRekapKelas adalah handler untuk GET /rekap-presensi/kelas/{kelasID}.
+func (h *Handler) GetRekapKelas(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	filter, err := filterFromRequest(r)
+	if err != nil {
+		writeError(w, "", err)
+		return
+	}
+	laporan, err := h.service.GetRekapKelas(r.Context(), schemaName, access.ActorFromContext(r.Context()), chi.URLParam(r, "kelasID"), filter)
+	h.writeLaporan(w, r, laporan, err)
+}
+
+// GetRekapTingkatan adalah handler untuk GET /rekap-presensi/tingkatan/{tingkatanID}.
+func (h *Handler) GetRekapTingkatan(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	filter, err := filterFromRequest(r)
+	if err != nil {
+		writeError(w, "", err)
+		return
+	}
+	tingkatanID, err := strconv.Atoi(chi.URLParam(r, "tingkatanID"))
+	if err != nil {
+		http.Error(w, "ID tingkatan tidak valid", http.StatusBadRequest)
+		return
+	}
+	laporan, err := h.service.GetRekapTingkatan(r.Context(), schemaName, access.ActorFromContext(r.Context()), tingkatanID, filter)
+	h.writeLaporan(w, r, laporan, err)
+}
+
+// GetRekapSekolah adalah handler untuk GET /rekap-presensi/sekolah.
+func (h *Handler) GetRekapSekolah(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	filter, err := filterFromRequest(r)
+	if err != nil {
+		writeError(w, "", err)
+		return
+	}
+	laporan, err := h.service.GetRekapSekolah(r.Context(), schemaName, access.ActorFromContext(r.Context()), filter)
+	h.writeLaporan(w, r, laporan, err)
+}
+
+// writeLaporan mengirim laporan sebagai JSON, atau sebagai file jika ?format=xlsx|pdf diisi.
+// Ukuran kertas PDF dapat dipilih dengan ?paper_size_id=...
+func (h *Handler) writeLaporan(w http.ResponseWriter, r *http.Request, laporan *Laporan, err error) {
+	if err != nil {
+		writeError(w, "Gagal mengambil rekap presensi: ", err)
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(laporan)
+		return
+	}
+
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	content, filename, err := h.service.Export(r.Context(), schemaName, laporan, format, r.URL.Query().Get("paper_size_id"))
+	if err != nil {
+		writeError(w, "Gagal mengekspor rekap presensi: ", err)
+		return
+	}
+	if format == FormatPDF {
+		w.Header().Set("Content-Type", "application/pdf")
+	} else {
+		w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	w.Write(content)
+}
+
+func writeError(w http.ResponseWriter, prefix string, err error) {
+	switch {
+	case errors.Is(err, access.ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, ErrValidation):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Data tidak ditemukan", http.StatusNotFound)
+	default:
+		http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+	}
+}
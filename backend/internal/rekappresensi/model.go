@@ -0,0 +1,111 @@
+// file: backend/internal/rekappresensi/model.go
+package rekappresensi
+
+import "time"
+
+// Format ekspor rekap presensi.
+const (
+	FormatXLSX = "xlsx"
+	FormatPDF  = "pdf"
+)
+
+// Cakupan rekap presensi.
+const (
+	CakupanSiswa     = "SISWA"
+	CakupanKelas     = "KELAS"
+	CakupanTingkatan = "TINGKATAN"
+	CakupanSekolah   = "SEKOLAH"
+)
+
+// BatasKehadiranBawaan adalah persentase kehadiran minimum jika tidak diminta lain.
+const BatasKehadiranBawaan = 80.0
+
+// Filter adalah rentang rekap. TahunAjaranID membatasi rekap ke kelas di satu semester,
+// sedangkan Dari dan Sampai (YYYY-MM-DD, inklusif) membatasi tanggal presensi.
+type Filter struct {
+	TahunAjaranID  string
+	Dari           string
+	Sampai         string
+	BatasKehadiran float64
+}
+
+// Jumlah adalah banyaknya hari per status presensi. Total adalah hari yang tercatat.
+// HariEfektif adalah hari sekolah menurut kalender akademik (atau Total jika kalender
+// semester belum diisi), dan TidakTercatat adalah hari efektif tanpa presensi.
+type Jumlah struct {
+	Hadir         int `json:"hadir"`
+	Sakit         int `json:"sakit"`
+	Izin          int `json:"izin"`
+	Alpa          int `json:"alpa"`
+	Total         int `json:"total"`
+	HariEfektif   int `json:"hari_efektif"`
+	TidakTercatat int `json:"tidak_tercatat"`
+}
+
+// Persentase adalah Jumlah dibagi HariEfektif, dalam persen dengan dua angka desimal.
+type Persentase struct {
+	Hadir float64 `json:"hadir"`
+	Sakit float64 `json:"sakit"`
+	Izin  float64 `json:"izin"`
+	Alpa  float64 `json:"alpa"`
+}
+
+// RekapSiswa adalah rekap presensi satu anggota kelas. DiBawahBatas bernilai true jika
+// persentase kehadirannya kurang dari batas kehadiran.
+type RekapSiswa struct {
+	AnggotaKelasID string     `json:"anggota_kelas_id"`
+	StudentID      string     `json:"student_id"`
+	NamaSiswa      string     `json:"nama_siswa"`
+	NIS            *string    `json:"nis"`
+	KelasID        string     `json:"kelas_id"`
+	NamaKelas      string     `json:"nama_kelas"`
+	Jumlah         Jumlah     `json:"jumlah"`
+	Persentase     Persentase `json:"persentase"`
+	DiBawahBatas   bool       `json:"di_bawah_batas"`
+}
+
+// RekapKelas adalah gabungan rekap seluruh anggota satu kelas.
+type RekapKelas struct {
+	KelasID           string     `json:"kelas_id"`
+	NamaKelas         string     `json:"nama_kelas"`
+	NamaTingkatan     string     `json:"nama_tingkatan"`
+	JumlahSiswa       int        `json:"jumlah_siswa"`
+	Jumlah            Jumlah     `json:"jumlah"`
+	Persentase        Persentase `json:"persentase"`
+	SiswaDiBawahBatas int        `json:"siswa_di_bawah_batas"`
+}
+
+// Laporan adalah hasil rekap presensi untuk satu cakupan. Kelas hanya diisi untuk cakupan
+// tingkatan dan sekolah.
+type Laporan struct {
+	Cakupan           string       `json:"cakupan"`
+	Judul             string       `json:"judul"`
+	TahunAjaran       *string      `json:"tahun_ajaran"`
+	Dari              *string      `json:"dari"`
+	Sampai            *string      `json:"sampai"`
+	BatasKehadiran    float64      `json:"batas_kehadiran"`
+	HariEfektif       *int         `json:"hari_efektif"`
+	Jumlah            Jumlah       `json:"jumlah"`
+	Persentase        Persentase   `json:"persentase"`
+	SiswaDiBawahBatas int          `json:"siswa_di_bawah_batas"`
+	Kelas             []RekapKelas `json:"kelas,omitempty"`
+	Siswa             []RekapSiswa `json:"siswa"`
+}
+
+// barisRekap adalah hasil query jumlah presensi per anggota kelas. TanggalMasuk adalah
+// tanggal mutasi masuk ke kelas (nil jika sejak awal semester) dan TanggalKeluar adalah
+// tanggal keanggotaan ditutup (siswa tidak lagi aktif sejak tanggal itu).
+type barisRekap struct {
+	RekapSiswa
+	NamaTingkatan string
+	TahunAjaranID string
+	TanggalMasuk  *time.Time
+	TanggalKeluar *time.Time
+}
+
+// scope adalah kondisi pemilihan anggota kelas untuk satu cakupan rekap.
+type scope struct {
+	StudentID   string
+	KelasID     string
+	TingkatanID int
+}
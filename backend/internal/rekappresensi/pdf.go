@@ -0,0 +1,220 @@
+// file: backend/internal/rekappresensi/pdf.go
+package rekappresensi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"skoola/internal/papersize"
+	"skoola/internal/profile"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+const tinggiBaris = 5.5
+
+// kolom adalah satu kolom tabel PDF. Lebar nol berarti mengisi sisa lebar halaman.
+type kolom struct {
+	label string
+	w     float64
+	align string
+}
+
+// toMM mengubah ukuran dari satuan paper_size ke milimeter.
+func toMM(v float64, satuan string) float64 {
+	switch satuan {
+	case "cm":
+		return v * 10
+	case "in":
+		return v * 25.4
+	default:
+		return v
+	}
+}
+
+// newPDF membuat dokumen sesuai ukuran kertas. Nil berarti A4 dengan margin 20 mm.
+func newPDF(paper *papersize.PaperSize) *gofpdf.Fpdf {
+	width, height := 210.0, 297.0
+	top, bottom, left, right := 20.0, 20.0, 20.0, 20.0
+	if paper != nil {
+		width, height = toMM(paper.Lebar, paper.Satuan), toMM(paper.Panjang, paper.Satuan)
+		top, bottom = toMM(paper.MarginAtas, paper.Satuan), toMM(paper.MarginBawah, paper.Satuan)
+		left, right = toMM(paper.MarginKiri, paper.Satuan), toMM(paper.MarginKanan, paper.Satuan)
+	}
+
+	pdf := gofpdf.NewCustom(&gofpdf.InitType{
+		OrientationStr: "P",
+		UnitStr:        "mm",
+		Size:           gofpdf.SizeType{Wd: width, Ht: height},
+	})
+	pdf.SetMargins(left, top, right)
+	pdf.SetAutoPageBreak(true, bottom)
+	return pdf
+}
+
+func renderPDF(paper *papersize.PaperSize, sekolah *profile.ProfilSekolah, l *Laporan) ([]byte, error) {
+	pdf := newPDF(paper)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+	left, _, right, _ := pdf.GetMargins()
+	pageWidth, _ := pdf.GetPageSize()
+	contentWidth := pageWidth - left - right
+
+	// 1. Kop sekolah dan judul
+	pdf.SetFont("Helvetica", "B", 13)
+	pdf.CellFormat(contentWidth, 6, tr(strings.ToUpper(sekolah.NamaSekolah)), "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	if sekolah.Alamat != nil && *sekolah.Alamat != "" {
+		pdf.MultiCell(contentWidth, 4.5, tr(*sekolah.Alamat), "", "C", false)
+	}
+	y := pdf.GetY() + 1
+	pdf.SetLineWidth(0.6)
+	pdf.Line(left, y, left+contentWidth, y)
+	pdf.SetLineWidth(0.2)
+	pdf.Ln(4)
+
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(contentWidth, 6, tr(l.Judul), "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	for _, baris := range keterangan(l) {
+		pdf.CellFormat(contentWidth, 4.5, tr(baris), "", 1, "C", false, 0, "")
+	}
+	pdf.Ln(3)
+
+	// 2. Ringkasan per kelas (tingkatan dan sekolah)
+	if len(l.Kelas) > 0 {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(contentWidth, 6, "Ringkasan per Kelas", "", 1, "L", false, 0, "")
+		cols := []kolom{{"No", 8, "C"}, {"Kelas", 0, "L"}, {"Siswa", 14, "C"}, {"H", 12, "C"}, {"S", 12, "C"},
+			{"I", 12, "C"}, {"A", 12, "C"}, {"Total", 14, "C"}, {"Efektif", 14, "C"}, {"% Hadir", 16, "C"},
+			{"< Batas", 16, "C"}}
+		lebarKolom(cols, contentWidth)
+		tabelKepala(pdf, cols)
+		for i, k := range l.Kelas {
+			barisTabel(pdf, tr, cols, false, fmt.Sprintf("%d", i+1), k.NamaKelas, fmt.Sprintf("%d", k.JumlahSiswa),
+				fmt.Sprintf("%d", k.Jumlah.Hadir), fmt.Sprintf("%d", k.Jumlah.Sakit), fmt.Sprintf("%d", k.Jumlah.Izin),
+				fmt.Sprintf("%d", k.Jumlah.Alpa), fmt.Sprintf("%d", k.Jumlah.Total), fmt.Sprintf("%d", k.Jumlah.HariEfektif), formatPersen(k.Persentase.Hadir),
+				fmt.Sprintf("%d", k.SiswaDiBawahBatas))
+		}
+		pdf.Ln(4)
+	}
+
+	// 3. Rincian per siswa
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(contentWidth, 6, "Rincian per Siswa", "", 1, "L", false, 0, "")
+	denganKelas := l.Cakupan != CakupanKelas
+	cols := []kolom{{"No", 8, "C"}, {"NIS", 20, "L"}, {"Nama Siswa", 0, "L"}}
+	if denganKelas {
+		cols = append(cols, kolom{"Kelas", 20, "L"})
+	}
+	cols = append(cols, kolom{"H", 10, "C"}, kolom{"S", 10, "C"}, kolom{"I", 10, "C"}, kolom{"A", 10, "C"},
+		kolom{"Total", 12, "C"}, kolom{"Efektif", 14, "C"}, kolom{"% Hadir", 16, "C"})
+	lebarKolom(cols, contentWidth)
+	tabelKepala(pdf, cols)
+	for i, r := range l.Siswa {
+		nilai := []string{fmt.Sprintf("%d", i+1), derefString(r.NIS), r.NamaSiswa}
+		if denganKelas {
+			nilai = append(nilai, r.NamaKelas)
+		}
+		nilai = append(nilai, fmt.Sprintf("%d", r.Jumlah.Hadir), fmt.Sprintf("%d", r.Jumlah.Sakit),
+			fmt.Sprintf("%d", r.Jumlah.Izin), fmt.Sprintf("%d", r.Jumlah.Alpa), fmt.Sprintf("%d", r.Jumlah.Total),
+			fmt.Sprintf("%d", r.Jumlah.HariEfektif), formatPersen(r.Persentase.Hadir))
+		if pdf.GetY()+tinggiBaris > batasBawah(pdf) {
+			pdf.AddPage()
+			tabelKepala(pdf, cols)
+		}
+		barisTabel(pdf, tr, cols, r.DiBawahBatas, nilai...)
+	}
+	pdf.SetFont("Helvetica", "I", 8)
+	pdf.Ln(2)
+	pdf.MultiCell(contentWidth, 4, tr(fmt.Sprintf("Baris berwarna menandai siswa dengan kehadiran di bawah %s. "+
+		"Total: H %d, S %d, I %d, A %d dari %d hari efektif, %d di antaranya tanpa catatan (kehadiran %s).",
+		formatPersen(l.BatasKehadiran), l.Jumlah.Hadir, l.Jumlah.Sakit, l.Jumlah.Izin, l.Jumlah.Alpa, l.Jumlah.HariEfektif,
+		l.Jumlah.TidakTercatat, formatPersen(l.Persentase.Hadir))), "", "L", false)
+
+	if pdf.Err() {
+		return nil, fmt.Errorf("gagal merender rekap presensi: %w", pdf.Error())
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("gagal menghasilkan file PDF: %w", err)
+	}
+	if buf.Len() == 0 {
+		return nil, errors.New("output PDF kosong")
+	}
+	return buf.Bytes(), nil
+}
+
+// keterangan adalah baris-baris di bawah judul: semester, periode, hari efektif, dan batas kehadiran.
+func keterangan(l *Laporan) []string {
+	var list []string
+	if l.TahunAjaran != nil {
+		list = append(list, "Tahun Ajaran "+*l.TahunAjaran)
+	}
+	switch {
+	case l.Dari != nil && l.Sampai != nil:
+		list = append(list, fmt.Sprintf("Periode %s s.d. %s", *l.Dari, *l.Sampai))
+	case l.Dari != nil:
+		list = append(list, "Sejak "+*l.Dari)
+	case l.Sampai != nil:
+		list = append(list, "Sampai "+*l.Sampai)
+	}
+	if l.HariEfektif != nil {
+		list = append(list, fmt.Sprintf("%d hari efektif", *l.HariEfektif))
+	}
+	return append(list, "Batas kehadiran minimum "+formatPersen(l.BatasKehadiran))
+}
+
+// lebarKolom membagi sisa lebar halaman ke kolom yang lebarnya nol.
+func lebarKolom(cols []kolom, contentWidth float64) {
+	var tetap float64
+	for _, c := range cols {
+		tetap += c.w
+	}
+	for i := range cols {
+		if cols[i].w == 0 {
+			cols[i].w = contentWidth - tetap
+		}
+	}
+}
+
+func tabelKepala(pdf *gofpdf.Fpdf, cols []kolom) {
+	pdf.SetFont("Helvetica", "B", 8)
+	pdf.SetFillColor(230, 230, 230)
+	for _, c := range cols {
+		pdf.CellFormat(c.w, 6, c.label, "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(-1)
+	pdf.SetFont("Helvetica", "", 8)
+}
+
+func barisTabel(pdf *gofpdf.Fpdf, tr func(string) string, cols []kolom, tandai bool, nilai ...string) {
+	pdf.SetFillColor(252, 228, 228)
+	for i, c := range cols {
+		teks := tr(nilai[i])
+		// Potong teks yang melebihi lebar kolom agar baris tetap satu tinggi.
+		for len(teks) > 1 && pdf.GetStringWidth(teks) > c.w-2 {
+			teks = teks[:len(teks)-1]
+		}
+		pdf.CellFormat(c.w, tinggiBaris, teks, "1", 0, c.align, tandai, 0, "")
+	}
+	pdf.Ln(-1)
+}
+
+func batasBawah(pdf *gofpdf.Fpdf) float64 {
+	_, pageHeight := pdf.GetPageSize()
+	_, _, _, bottom := pdf.GetMargins()
+	return pageHeight - bottom
+}
+
+func formatPersen(v float64) string {
+	return fmt.Sprintf("%.2f%%", v)
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
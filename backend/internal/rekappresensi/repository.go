@@ -0,0 +1,141 @@
+// file: backend/internal/rekappresensi/repository.go
+package rekappresensi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"skoola/pkg/database"
+	"strings"
+	"time"
+)
+
+// Repository mendefinisikan interface untuk interaksi database rekap presensi.
+type Repository interface {
+	// GetRekap menghitung jumlah presensi per anggota kelas yang masuk cakupan, diurutkan
+	// per tingkatan, kelas, lalu nomor urut siswa.
+	GetRekap(ctx context.Context, schemaName string, sc scope, tahunAjaranID string, dari *time.Time, sampai *time.Time) ([]barisRekap, error)
+	// Fungsi GetNama* mengembalikan nil jika data tidak ditemukan.
+	GetNamaSiswa(ctx context.Context, schemaName string, studentID string) (*string, error)
+	GetNamaKelas(ctx context.Context, schemaName string, kelasID string) (*string, error)
+	GetNamaTingkatan(ctx context.Context, schemaName string, tingkatanID int) (*string, error)
+	GetNamaTahunAjaran(ctx context.Context, schemaName string, tahunAjaranID string) (*string, error)
+}
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewRepository membuat instance baru dari postgresRepository.
+func NewRepository(db *sql.DB) Repository {
+	return &postgresRepository{db: db}
+}
+
+func (r *postgresRepository) GetRekap(ctx context.Context, schemaName string, sc scope, tahunAjaranID string, dari *time.Time, sampai *time.Time) ([]barisRekap, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	args := []interface{}{dari, sampai}
+	// Siswa yang sudah keluar sebelum awal rentang (atau sebelum hari ini jika rentang tidak
+	// dibatasi) tidak lagi masuk rekap.
+	where := []string{"(ak.tanggal_keluar IS NULL OR ak.tanggal_keluar > COALESCE($1::date, CURRENT_DATE))"}
+	if sc.StudentID != "" {
+		args = append(args, sc.StudentID)
+		where = append(where, fmt.Sprintf("ak.student_id = $%d", len(args)))
+	}
+	if sc.KelasID != "" {
+		args = append(args, sc.KelasID)
+		where = append(where, fmt.Sprintf("k.id = $%d", len(args)))
+	}
+	if sc.TingkatanID != 0 {
+		args = append(args, sc.TingkatanID)
+		where = append(where, fmt.Sprintf("k.tingkatan_id = $%d", len(args)))
+	}
+	if tahunAjaranID != "" {
+		args = append(args, tahunAjaranID)
+		where = append(where, fmt.Sprintf("k.tahun_ajaran_id = $%d", len(args)))
+	}
+
+	query := `
+		SELECT ak.id, s.id, s.nama_lengkap, s.nis, k.id, k.nama_kelas, t.nama_tingkatan, k.tahun_ajaran_id,
+			(SELECT MAX(m.tanggal) FROM mutasi_siswa m
+				WHERE m.jenis = 'MASUK' AND m.student_id = ak.student_id AND m.kelas_id = ak.kelas_id),
+			ak.tanggal_keluar,
+			COUNT(p.id) FILTER (WHERE p.status = 'H'),
+			COUNT(p.id) FILTER (WHERE p.status = 'S'),
+			COUNT(p.id) FILTER (WHERE p.status = 'I'),
+			COUNT(p.id) FILTER (WHERE p.status = 'A'),
+			COUNT(p.id)
+		FROM anggota_kelas ak
+		JOIN students s ON ak.student_id = s.id
+		JOIN kelas k ON ak.kelas_id = k.id
+		JOIN tingkatan t ON k.tingkatan_id = t.id
+		JOIN tahun_ajaran ta ON k.tahun_ajaran_id = ta.id
+		LEFT JOIN presensi p ON p.anggota_kelas_id = ak.id
+			AND ($1::date IS NULL OR p.tanggal >= $1::date)
+			AND ($2::date IS NULL OR p.tanggal <= $2::date)
+	`
+	query += " WHERE " + strings.Join(where, " AND ")
+	query += `
+		GROUP BY ak.id, s.id, k.id, t.id, ta.id
+		ORDER BY ta.nama_tahun_ajaran ASC, ta.semester ASC, t.urutan ASC NULLS LAST, t.nama_tingkatan ASC,
+			k.nama_kelas ASC, ak.urutan ASC, s.nama_lengkap ASC
+	`
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menghitung rekap presensi: %w", err)
+	}
+	defer rows.Close()
+
+	var list []barisRekap
+	for rows.Next() {
+		var b barisRekap
+		j := &b.Jumlah
+		if err := rows.Scan(&b.AnggotaKelasID, &b.StudentID, &b.NamaSiswa, &b.NIS, &b.KelasID, &b.NamaKelas, &b.NamaTingkatan, &b.TahunAjaranID,
+			&b.TanggalMasuk, &b.TanggalKeluar, &j.Hadir, &j.Sakit, &j.Izin, &j.Alpa, &j.Total); err != nil {
+			return nil, fmt.Errorf("gagal memindai rekap presensi: %w", err)
+		}
+		list = append(list, b)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+func (r *postgresRepository) GetNamaSiswa(ctx context.Context, schemaName string, studentID string) (*string, error) {
+	return r.getNama(ctx, schemaName, "SELECT nama_lengkap FROM students WHERE id = $1", studentID)
+}
+
+func (r *postgresRepository) GetNamaKelas(ctx context.Context, schemaName string, kelasID string) (*string, error) {
+	return r.getNama(ctx, schemaName, "SELECT nama_kelas FROM kelas WHERE id = $1", kelasID)
+}
+
+func (r *postgresRepository) GetNamaTingkatan(ctx context.Context, schemaName string, tingkatanID int) (*string, error) {
+	return r.getNama(ctx, schemaName, "SELECT nama_tingkatan FROM tingkatan WHERE id = $1", tingkatanID)
+}
+
+func (r *postgresRepository) GetNamaTahunAjaran(ctx context.Context, schemaName string, tahunAjaranID string) (*string, error) {
+	query := "SELECT nama_tahun_ajaran || ' Semester ' || semester::text FROM tahun_ajaran WHERE id = $1"
+	return r.getNama(ctx, schemaName, query, tahunAjaranID)
+}
+
+func (r *postgresRepository) getNama(ctx context.Context, schemaName string, query string, arg interface{}) (*string, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var nama string
+	if err := tx.QueryRowContext(ctx, query, arg).Scan(&nama); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("gagal mengambil data: %w", err)
+	}
+	return &nama, tx.Commit()
+}
@@ -0,0 +1,370 @@
+// file: backend/internal/rekappresensi/service.go
+package rekappresensi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"skoola/internal/access"
+	"skoola/internal/kalender"
+	"skoola/internal/papersize"
+	"skoola/internal/profile"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrValidation = errors.New("validation failed")
+
+// Service mendefinisikan logika bisnis rekap presensi.
+type Service interface {
+	GetRekapSiswa(ctx context.Context, schemaName string, actor access.Actor, studentID string, filter Filter) (*Laporan, error)
+	GetRekapKelas(ctx context.Context, schemaName string, actor access.Actor, kelasID string, filter Filter) (*Laporan, error)
+	// GetRekapTingkatan dan GetRekapSekolah hanya untuk admin dan wajib memakai tahun ajaran.
+	GetRekapTingkatan(ctx context.Context, schemaName string, actor access.Actor, tingkatanID int, filter Filter) (*Laporan, error)
+	GetRekapSekolah(ctx context.Context, schemaName string, actor access.Actor, filter Filter) (*Laporan, error)
+	// Export merender laporan ke xlsx atau pdf dan mengembalikan isi serta nama filenya.
+	Export(ctx context.Context, schemaName string, laporan *Laporan, format string, paperSizeID string) ([]byte, string, error)
+}
+
+type service struct {
+	repo          Repository
+	kalender      kalender.Service
+	profileRepo   profile.Repository
+	paperSizeRepo papersize.Repository
+	access        access.Service
+}
+
+// NewService membuat instance baru dari service rekap presensi.
+func NewService(repo Repository, kalenderService kalender.Service, profileRepo profile.Repository, paperSizeRepo papersize.Repository, accessService access.Service) Service {
+	return &service{repo: repo, kalender: kalenderService, profileRepo: profileRepo, paperSizeRepo: paperSizeRepo, access: accessService}
+}
+
+func (s *service) GetRekapSiswa(ctx context.Context, schemaName string, actor access.Actor, studentID string, filter Filter) (*Laporan, error) {
+	if _, err := uuid.Parse(studentID); err != nil {
+		return nil, fmt.Errorf("%w: student_id tidak valid", ErrValidation)
+	}
+	nama, err := s.repo.GetNamaSiswa(ctx, schemaName, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if nama == nil {
+		return nil, sql.ErrNoRows
+	}
+	laporan, err := s.build(ctx, schemaName, CakupanSiswa, "REKAP PRESENSI "+strings.ToUpper(*nama), scope{StudentID: studentID}, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Wali kelas dan guru hanya boleh melihat keanggotaan di kelas yang mereka pegang.
+	anggotaIDs := make([]string, 0, len(laporan.Siswa))
+	for _, r := range laporan.Siswa {
+		anggotaIDs = append(anggotaIDs, r.AnggotaKelasID)
+	}
+	if !actor.IsAdmin() && len(anggotaIDs) == 0 {
+		return nil, access.ErrForbidden
+	}
+	if err := s.access.AuthorizeRead(ctx, schemaName, actor, access.Target{AnggotaKelasIDs: anggotaIDs}); err != nil {
+		return nil, err
+	}
+	return laporan, nil
+}
+
+func (s *service) GetRekapKelas(ctx context.Context, schemaName string, actor access.Actor, kelasID string, filter Filter) (*Laporan, error) {
+	if _, err := uuid.Parse(kelasID); err != nil {
+		return nil, fmt.Errorf("%w: kelas_id tidak valid", ErrValidation)
+	}
+	if err := s.access.AuthorizeRead(ctx, schemaName, actor, access.Target{KelasIDs: []string{kelasID}}); err != nil {
+		return nil, err
+	}
+	nama, err := s.repo.GetNamaKelas(ctx, schemaName, kelasID)
+	if err != nil {
+		return nil, err
+	}
+	if nama == nil {
+		return nil, sql.ErrNoRows
+	}
+	// Kelas sudah terikat pada satu tahun ajaran.
+	filter.TahunAjaranID = ""
+	return s.build(ctx, schemaName, CakupanKelas, "REKAP PRESENSI KELAS "+strings.ToUpper(*nama), scope{KelasID: kelasID}, filter)
+}
+
+func (s *service) GetRekapTingkatan(ctx context.Context, schemaName string, actor access.Actor, tingkatanID int, filter Filter) (*Laporan, error) {
+	if !actor.IsAdmin() {
+		return nil, access.ErrForbidden
+	}
+	if filter.TahunAjaranID == "" {
+		return nil, fmt.Errorf("%w: tahun_ajaran_id wajib diisi", ErrValidation)
+	}
+	nama, err := s.repo.GetNamaTingkatan(ctx, schemaName, tingkatanID)
+	if err != nil {
+		return nil, err
+	}
+	if nama == nil {
+		return nil, sql.ErrNoRows
+	}
+	return s.build(ctx, schemaName, CakupanTingkatan, "REKAP PRESENSI TINGKAT "+strings.ToUpper(*nama), scope{TingkatanID: tingkatanID}, filter)
+}
+
+func (s *service) GetRekapSekolah(ctx context.Context, schemaName string, actor access.Actor, filter Filter) (*Laporan, error) {
+	if !actor.IsAdmin() {
+		return nil, access.ErrForbidden
+	}
+	if filter.TahunAjaranID == "" {
+		return nil, fmt.Errorf("%w: tahun_ajaran_id wajib diisi", ErrValidation)
+	}
+	return s.build(ctx, schemaName, CakupanSekolah, "REKAP PRESENSI SEKOLAH", scope{}, filter)
+}
+
+func (s *service) Export(ctx context.Context, schemaName string, laporan *Laporan, format string, paperSizeID string) ([]byte, string, error) {
+	if format != FormatXLSX && format != FormatPDF {
+		return nil, "", fmt.Errorf("%w: format harus 'xlsx' atau 'pdf'", ErrValidation)
+	}
+	filename := fmt.Sprintf("%s_%s.%s", fileSafe(strings.ToLower(laporan.Judul)), time.Now().Format("20060102"), format)
+	if format == FormatXLSX {
+		content, err := renderExcel(laporan)
+		return content, filename, err
+	}
+
+	paper, err := s.resolvePaper(ctx, schemaName, paperSizeID)
+	if err != nil {
+		return nil, "", err
+	}
+	sekolah, err := s.profileRepo.GetProfile(ctx, schemaName)
+	if err != nil {
+		return nil, "", err
+	}
+	if sekolah == nil {
+		sekolah = &profile.ProfilSekolah{}
+	}
+	content, err := renderPDF(paper, sekolah, laporan)
+	return content, filename, err
+}
+
+func (s *service) build(ctx context.Context, schemaName string, cakupan string, judul string, sc scope, filter Filter) (*Laporan, error) {
+	dari, sampai, err := validateFilter(&filter)
+	if err != nil {
+		return nil, err
+	}
+	laporan := &Laporan{Cakupan: cakupan, Judul: judul, BatasKehadiran: filter.BatasKehadiran, Siswa: []RekapSiswa{}}
+	if filter.Dari != "" {
+		laporan.Dari = &filter.Dari
+	}
+	if filter.Sampai != "" {
+		laporan.Sampai = &filter.Sampai
+	}
+	if filter.TahunAjaranID != "" {
+		laporan.TahunAjaran, err = s.repo.GetNamaTahunAjaran(ctx, schemaName, filter.TahunAjaranID)
+		if err != nil {
+			return nil, err
+		}
+		if laporan.TahunAjaran == nil {
+			return nil, fmt.Errorf("%w: tahun ajaran tidak ditemukan", ErrValidation)
+		}
+	}
+
+	rows, err := s.repo.GetRekap(ctx, schemaName, sc, filter.TahunAjaranID, dari, sampai)
+	if err != nil {
+		return nil, err
+	}
+
+	// Hari efektif dihitung sekali per tahun ajaran dari kalender akademiknya. Siswa yang
+	// masuk atau keluar di tengah rentang memakai rentang keanggotaannya sendiri.
+	hariEfektif := make(map[string]*int)
+	perRentang := make(map[string]*int)
+	perKelas := make(map[string]int)
+	for _, b := range rows {
+		efektif, ok := hariEfektif[b.TahunAjaranID]
+		if !ok {
+			efektif, err = s.kalender.HitungHariEfektif(ctx, schemaName, b.TahunAjaranID, dari, sampai)
+			if err != nil {
+				return nil, err
+			}
+			hariEfektif[b.TahunAjaranID] = efektif
+		}
+		if d, sp, dipotong := rentangAnggota(dari, sampai, b.TanggalMasuk, b.TanggalKeluar); dipotong {
+			kunci := b.TahunAjaranID + "|" + formatTanggal(d) + "|" + formatTanggal(sp)
+			if efektif, ok = perRentang[kunci]; !ok {
+				efektif, err = s.kalender.HitungHariEfektif(ctx, schemaName, b.TahunAjaranID, d, sp)
+				if err != nil {
+					return nil, err
+				}
+				perRentang[kunci] = efektif
+			}
+		}
+
+		siswa := b.RekapSiswa
+		isiHariEfektif(&siswa.Jumlah, efektif)
+		siswa.Persentase = persentase(siswa.Jumlah)
+		siswa.DiBawahBatas = siswa.Jumlah.HariEfektif > 0 && siswa.Persentase.Hadir < filter.BatasKehadiran
+		laporan.Siswa = append(laporan.Siswa, siswa)
+
+		i, ok := perKelas[b.KelasID]
+		if !ok {
+			i = len(laporan.Kelas)
+			perKelas[b.KelasID] = i
+			laporan.Kelas = append(laporan.Kelas, RekapKelas{KelasID: b.KelasID, NamaKelas: b.NamaKelas, NamaTingkatan: b.NamaTingkatan})
+		}
+		k := &laporan.Kelas[i]
+		k.JumlahSiswa++
+		tambah(&k.Jumlah, siswa.Jumlah)
+		tambah(&laporan.Jumlah, siswa.Jumlah)
+		if siswa.DiBawahBatas {
+			k.SiswaDiBawahBatas++
+			laporan.SiswaDiBawahBatas++
+		}
+	}
+	for i := range laporan.Kelas {
+		laporan.Kelas[i].Persentase = persentase(laporan.Kelas[i].Jumlah)
+	}
+	laporan.Persentase = persentase(laporan.Jumlah)
+	if len(hariEfektif) == 1 {
+		for _, efektif := range hariEfektif {
+			laporan.HariEfektif = efektif
+		}
+	}
+
+	// Ringkasan per kelas hanya berguna jika laporan mencakup lebih dari satu kelas.
+	if cakupan != CakupanTingkatan && cakupan != CakupanSekolah {
+		laporan.Kelas = nil
+	}
+	return laporan, nil
+}
+
+// validateFilter mengisi batas kehadiran bawaan dan mengembalikan rentang tanggalnya.
+func validateFilter(filter *Filter) (*time.Time, *time.Time, error) {
+	if filter.BatasKehadiran == 0 {
+		filter.BatasKehadiran = BatasKehadiranBawaan
+	}
+	if filter.BatasKehadiran < 0 || filter.BatasKehadiran > 100 {
+		return nil, nil, fmt.Errorf("%w: batas_kehadiran harus antara 0 dan 100", ErrValidation)
+	}
+	if filter.TahunAjaranID != "" {
+		if _, err := uuid.Parse(filter.TahunAjaranID); err != nil {
+			return nil, nil, fmt.Errorf("%w: tahun_ajaran_id tidak valid", ErrValidation)
+		}
+	}
+	dari, err := parseTanggal(filter.Dari, "dari")
+	if err != nil {
+		return nil, nil, err
+	}
+	sampai, err := parseTanggal(filter.Sampai, "sampai")
+	if err != nil {
+		return nil, nil, err
+	}
+	if dari != nil && sampai != nil && sampai.Before(*dari) {
+		return nil, nil, fmt.Errorf("%w: tanggal sampai tidak boleh sebelum tanggal dari", ErrValidation)
+	}
+	return dari, sampai, nil
+}
+
+// rentangAnggota memotong rentang rekap ke masa keanggotaan siswa di kelas: sejak tanggal
+// masuk dan sampai sehari sebelum tanggal keluar. dipotong bernilai false jika rentangnya
+// tidak berubah.
+func rentangAnggota(dari *time.Time, sampai *time.Time, masuk *time.Time, keluar *time.Time) (*time.Time, *time.Time, bool) {
+	dipotong := false
+	if masuk != nil && (dari == nil || masuk.After(*dari)) {
+		dari, dipotong = masuk, true
+	}
+	if keluar != nil {
+		akhir := keluar.AddDate(0, 0, -1)
+		if sampai == nil || akhir.Before(*sampai) {
+			sampai, dipotong = &akhir, true
+		}
+	}
+	return dari, sampai, dipotong
+}
+
+func formatTanggal(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+func parseTanggal(value string, field string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s harus berformat YYYY-MM-DD", ErrValidation, field)
+	}
+	return &t, nil
+}
+
+// isiHariEfektif memakai hari efektif kalender sebagai penyebut persentase. Jika kalender
+// belum diisi, atau presensi tercatat melebihi hari efektif, penyebutnya adalah Total.
+func isiHariEfektif(j *Jumlah, efektif *int) {
+	j.HariEfektif = j.Total
+	if efektif != nil && *efektif > j.Total {
+		j.HariEfektif = *efektif
+	}
+	j.TidakTercatat = j.HariEfektif - j.Total
+}
+
+func tambah(total *Jumlah, j Jumlah) {
+	total.Hadir += j.Hadir
+	total.Sakit += j.Sakit
+	total.Izin += j.Izin
+	total.Alpa += j.Alpa
+	total.Total += j.Total
+	total.HariEfektif += j.HariEfektif
+	total.TidakTercatat += j.TidakTercatat
+}
+
+func persentase(j Jumlah) Persentase {
+	if j.HariEfektif == 0 {
+		return Persentase{}
+	}
+	hitung := func(n int) float64 {
+		return math.Round(float64(n)*10000/float64(j.HariEfektif)) / 100
+	}
+	return Persentase{Hadir: hitung(j.Hadir), Sakit: hitung(j.Sakit), Izin: hitung(j.Izin), Alpa: hitung(j.Alpa)}
+}
+
+// resolvePaper memakai ukuran kertas yang diminta, atau A4 (atau ukuran pertama yang
+// tersedia) dari pengaturan ukuran kertas sekolah. Nil berarti memakai A4 bawaan.
+func (s *service) resolvePaper(ctx context.Context, schemaName string, paperSizeID string) (*papersize.PaperSize, error) {
+	if paperSizeID != "" {
+		p, err := s.paperSizeRepo.GetByID(ctx, schemaName, paperSizeID)
+		if err != nil {
+			return nil, err
+		}
+		if p == nil {
+			return nil, fmt.Errorf("%w: ukuran kertas tidak ditemukan", ErrValidation)
+		}
+		return p, nil
+	}
+
+	list, err := s.paperSizeRepo.GetAll(ctx, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		if strings.EqualFold(list[i].NamaKertas, "A4") {
+			return &list[i], nil
+		}
+	}
+	if len(list) > 0 {
+		return &list[0], nil
+	}
+	return nil, nil
+}
+
+// fileSafe mengubah judul laporan menjadi nama file yang aman.
+func fileSafe(name string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
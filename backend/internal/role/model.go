@@ -0,0 +1,25 @@
+// file: backend/internal/role/model.go
+package role
+
+import "time"
+
+// Role merepresentasikan data dari tabel 'roles' beserta permission dan jabatan terkait.
+// Kode terisi untuk role sistem (misalnya 'teacher') yang tidak dapat dihapus.
+type Role struct {
+	ID          int       `json:"id"`
+	NamaRole    string    `json:"nama_role"`
+	Deskripsi   *string   `json:"deskripsi"`
+	Kode        *string   `json:"kode"`
+	Permissions []string  `json:"permissions"`
+	JabatanIDs  []int     `json:"jabatan_ids"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// UpsertRoleInput adalah DTO untuk membuat atau memperbarui role.
+type UpsertRoleInput struct {
+	NamaRole    string   `json:"nama_role" validate:"required,min=3,max=100"`
+	Deskripsi   *string  `json:"deskripsi" validate:"omitempty"`
+	Permissions []string `json:"permissions" validate:"omitempty,dive,required"`
+	JabatanIDs  []int    `json:"jabatan_ids" validate:"omitempty,dive,gt=0"`
+}
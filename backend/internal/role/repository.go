@@ -0,0 +1,255 @@
+// file: backend/internal/role/repository.go
+package role
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"skoola/pkg/database"
+
+	"github.com/lib/pq"
+)
+
+// Repository mendefinisikan interface untuk interaksi database.
+type Repository interface {
+	Create(ctx context.Context, schemaName string, input UpsertRoleInput) (*Role, error)
+	GetAll(ctx context.Context, schemaName string) ([]Role, error)
+	GetByID(ctx context.Context, schemaName string, id int) (*Role, error)
+	Update(ctx context.Context, schemaName string, id int, input UpsertRoleInput) error
+	Delete(ctx context.Context, schemaName string, id int) error
+	GetUserPermissions(ctx context.Context, schemaName string, userID string) ([]string, error)
+}
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewRepository membuat instance baru dari postgresRepository.
+func NewRepository(db *sql.DB) Repository {
+	return &postgresRepository{db: db}
+}
+
+func (r *postgresRepository) Create(ctx context.Context, schemaName string, input UpsertRoleInput) (*Role, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO roles (nama_role, deskripsi) VALUES ($1, $2) RETURNING id`
+	var id int
+	if err := tx.QueryRowContext(ctx, query, input.NamaRole, input.Deskripsi).Scan(&id); err != nil {
+		return nil, fmt.Errorf("gagal membuat role: %w", err)
+	}
+	if err := replaceRoleLinks(ctx, tx, id, input); err != nil {
+		return nil, err
+	}
+
+	role, err := getRoleByID(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	return role, tx.Commit()
+}
+
+func (r *postgresRepository) GetAll(ctx context.Context, schemaName string) ([]Role, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT r.id, r.nama_role, r.deskripsi, r.kode, r.created_at, r.updated_at,
+			COALESCE((SELECT array_agg(permission ORDER BY permission) FROM role_permissions WHERE role_id = r.id), '{}'),
+			COALESCE((SELECT array_agg(jabatan_id ORDER BY jabatan_id) FROM jabatan_roles WHERE role_id = r.id), '{}')
+		FROM roles r
+		ORDER BY r.kode IS NULL, r.nama_role ASC
+	`
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query get all role: %w", err)
+	}
+	defer rows.Close()
+
+	var roles []Role
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, *role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return roles, tx.Commit()
+}
+
+func (r *postgresRepository) GetByID(ctx context.Context, schemaName string, id int) (*Role, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	role, err := getRoleByID(ctx, tx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Data tidak ditemukan, bukan error
+		}
+		return nil, err
+	}
+	return role, tx.Commit()
+}
+
+func (r *postgresRepository) Update(ctx context.Context, schemaName string, id int, input UpsertRoleInput) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE roles SET nama_role = $1, deskripsi = $2, updated_at = NOW() WHERE id = $3`
+	result, err := tx.ExecContext(ctx, query, input.NamaRole, input.Deskripsi, id)
+	if err != nil {
+		return fmt.Errorf("gagal mengeksekusi query update: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("gagal memeriksa baris yang terpengaruh: %w", err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err := replaceRoleLinks(ctx, tx, id, input); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *postgresRepository) Delete(ctx context.Context, schemaName string, id int) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `DELETE FROM roles WHERE id = $1 AND kode IS NULL`
+	result, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("gagal mengeksekusi query delete: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("gagal memeriksa baris yang terpengaruh: %w", err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
+// GetUserPermissions mengumpulkan permission dari role sistem yang sesuai dengan users.role
+// dan dari semua role yang terhubung ke jabatan yang dipegang user tersebut.
+func (r *postgresRepository) GetUserPermissions(ctx context.Context, schemaName string, userID string) ([]string, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT rp.permission
+		FROM role_permissions rp
+		JOIN roles r ON r.id = rp.role_id
+		JOIN users u ON u.role = r.kode
+		WHERE u.id = $1
+		UNION
+		SELECT rp.permission
+		FROM role_permissions rp
+		JOIN jabatan_roles jr ON jr.role_id = rp.role_id
+		JOIN teacher_jabatan tj ON tj.jabatan_id = jr.jabatan_id
+		JOIN teachers t ON t.id = tj.teacher_id
+		WHERE t.user_id = $1
+	`
+	rows, err := tx.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query permission user: %w", err)
+	}
+	defer rows.Close()
+
+	var perms []string
+	for rows.Next() {
+		var p string
+		if err := rows.Scan(&p); err != nil {
+			return nil, fmt.Errorf("gagal memindai permission user: %w", err)
+		}
+		perms = append(perms, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return perms, tx.Commit()
+}
+
+// replaceRoleLinks mengganti seluruh permission dan jabatan milik role sesuai input.
+func replaceRoleLinks(ctx context.Context, tx *sql.Tx, roleID int, input UpsertRoleInput) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM role_permissions WHERE role_id = $1`, roleID); err != nil {
+		return fmt.Errorf("gagal menghapus permission lama: %w", err)
+	}
+	for _, p := range input.Permissions {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO role_permissions (role_id, permission) VALUES ($1, $2) ON CONFLICT DO NOTHING`, roleID, p); err != nil {
+			return fmt.Errorf("gagal menyimpan permission %s: %w", p, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM jabatan_roles WHERE role_id = $1`, roleID); err != nil {
+		return fmt.Errorf("gagal menghapus jabatan lama: %w", err)
+	}
+	for _, jabatanID := range input.JabatanIDs {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO jabatan_roles (jabatan_id, role_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, jabatanID, roleID); err != nil {
+			return fmt.Errorf("gagal menghubungkan jabatan %d: %w", jabatanID, err)
+		}
+	}
+	return nil
+}
+
+func getRoleByID(ctx context.Context, tx *sql.Tx, id int) (*Role, error) {
+	query := `
+		SELECT r.id, r.nama_role, r.deskripsi, r.kode, r.created_at, r.updated_at,
+			COALESCE((SELECT array_agg(permission ORDER BY permission) FROM role_permissions WHERE role_id = r.id), '{}'),
+			COALESCE((SELECT array_agg(jabatan_id ORDER BY jabatan_id) FROM jabatan_roles WHERE role_id = r.id), '{}')
+		FROM roles r
+		WHERE r.id = $1
+	`
+	role, err := scanRole(tx.QueryRowContext(ctx, query, id))
+	if err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRole(row scanner) (*Role, error) {
+	var role Role
+	var perms pq.StringArray
+	var jabatanIDs pq.Int64Array
+	err := row.Scan(&role.ID, &role.NamaRole, &role.Deskripsi, &role.Kode, &role.CreatedAt, &role.UpdatedAt, &perms, &jabatanIDs)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("gagal memindai data role: %w", err)
+	}
+	role.Permissions = []string(perms)
+	role.JabatanIDs = make([]int, len(jabatanIDs))
+	for i, id := range jabatanIDs {
+		role.JabatanIDs[i] = int(id)
+	}
+	return &role, nil
+}
@@ -0,0 +1,90 @@
+// file: backend/internal/role/service.go
+package role
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"skoola/internal/auth"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	ErrValidation = errors.New("validation failed")
+	ErrSystemRole = errors.New("role sistem tidak dapat dihapus")
+)
+
+// Service mendefinisikan interface untuk logika bisnis.
+type Service interface {
+	Create(ctx context.Context, schemaName string, input UpsertRoleInput) (*Role, error)
+	GetAll(ctx context.Context, schemaName string) ([]Role, error)
+	GetByID(ctx context.Context, schemaName string, id int) (*Role, error)
+	Update(ctx context.Context, schemaName string, id int, input UpsertRoleInput) error
+	Delete(ctx context.Context, schemaName string, id int) error
+	GetPermissionCatalog() []auth.Permission
+}
+
+type service struct {
+	repo     Repository
+	validate *validator.Validate
+}
+
+// NewService membuat instance baru dari service.
+func NewService(repo Repository, validate *validator.Validate) Service {
+	return &service{repo: repo, validate: validate}
+}
+
+func (s *service) Create(ctx context.Context, schemaName string, input UpsertRoleInput) (*Role, error) {
+	if err := s.validateInput(input); err != nil {
+		return nil, err
+	}
+	return s.repo.Create(ctx, schemaName, input)
+}
+
+func (s *service) GetAll(ctx context.Context, schemaName string) ([]Role, error) {
+	return s.repo.GetAll(ctx, schemaName)
+}
+
+func (s *service) GetByID(ctx context.Context, schemaName string, id int) (*Role, error) {
+	return s.repo.GetByID(ctx, schemaName, id)
+}
+
+func (s *service) Update(ctx context.Context, schemaName string, id int, input UpsertRoleInput) error {
+	if err := s.validateInput(input); err != nil {
+		return err
+	}
+	return s.repo.Update(ctx, schemaName, id, input)
+}
+
+func (s *service) Delete(ctx context.Context, schemaName string, id int) error {
+	role, err := s.repo.GetByID(ctx, schemaName, id)
+	if err != nil {
+		return fmt.Errorf("gagal mencari role untuk dihapus: %w", err)
+	}
+	if role == nil {
+		return sql.ErrNoRows
+	}
+	if role.Kode != nil {
+		return ErrSystemRole
+	}
+	return s.repo.Delete(ctx, schemaName, id)
+}
+
+// GetPermissionCatalog mengembalikan semua permission yang dapat dipilih saat menyusun role.
+func (s *service) GetPermissionCatalog() []auth.Permission {
+	return auth.AllPermissions
+}
+
+func (s *service) validateInput(input UpsertRoleInput) error {
+	if err := s.validate.Struct(input); err != nil {
+		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	for _, p := range input.Permissions {
+		if !auth.IsValidPermission(p) {
+			return fmt.Errorf("%w: permission '%s' tidak dikenal", ErrValidation, p)
+		}
+	}
+	return nil
+}
@@ -34,6 +34,9 @@ type AnggotaKelas struct {
 	NISN         *string   `json:"nisn,omitempty"`          // Untuk join
 	NamaLengkap  string    `json:"nama_lengkap,omitempty"`  // Untuk join
 	JenisKelamin *string   `json:"jenis_kelamin,omitempty"` // Untuk join
+
+	// TanggalKeluar terisi jika siswa sudah keluar dari kelas karena mutasi.
+	TanggalKeluar *time.Time `json:"tanggal_keluar,omitempty"`
 }
 
 // PengajarKelas merepresentasikan guru yang mengajar mapel di rombel.
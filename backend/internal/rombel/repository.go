@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"skoola/pkg/database"
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
@@ -23,6 +24,9 @@ type Repository interface {
 	AddAnggotaKelas(ctx context.Context, schemaName string, kelasID string, studentIDs []string) error
 	RemoveAnggotaKelas(ctx context.Context, schemaName string, anggotaID string) error
 	GetAllAnggotaByKelas(ctx context.Context, schemaName string, kelasID string) ([]AnggotaKelas, error)
+	// GetAllAnggotaByKelasTx sama dengan GetAllAnggotaByKelas, tetapi membaca di dalam
+	// transaksi tenant milik pemanggil.
+	GetAllAnggotaByKelasTx(ctx context.Context, tx *sql.Tx, kelasID string) ([]AnggotaKelas, error)
 	UpdateAnggotaKelasUrutan(ctx context.Context, schemaName string, orderedIDs []string) error
 	FindAnggotaKelasByPengajarKelasIDs(ctx context.Context, schemaName string, pengajarKelasIDs []uuid.UUID) ([]AnggotaKelas, error) // <-- BARU
 
@@ -41,37 +45,32 @@ func NewRepository(db *sql.DB) Repository {
 	return &postgresRepository{db: db}
 }
 
-// setSchema mengatur search_path untuk tenant yang benar.
-func (r *postgresRepository) setSchema(ctx context.Context, schemaName string) error {
-	_, err := r.db.ExecContext(ctx, fmt.Sprintf("SET search_path TO %q", schemaName))
-	if err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
-	}
-	return nil
-}
-
 // --- Implementasi Kelas (Rombel) ---
 
 func (r *postgresRepository) CreateKelas(ctx context.Context, schemaName string, k *Kelas) (*Kelas, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 	query := `
         INSERT INTO kelas (id, nama_kelas, tahun_ajaran_id, tingkatan_id, wali_kelas_id)
         VALUES ($1, $2, $3, $4, $5)
         RETURNING id, created_at, updated_at
     `
-	err := r.db.QueryRowContext(ctx, query, k.ID, k.NamaKelas, k.TahunAjaranID, k.TingkatanID, k.WaliKelasID).Scan(&k.ID, &k.CreatedAt, &k.UpdatedAt)
+	err = tx.QueryRowContext(ctx, query, k.ID, k.NamaKelas, k.TahunAjaranID, k.TingkatanID, k.WaliKelasID).Scan(&k.ID, &k.CreatedAt, &k.UpdatedAt)
 	if err != nil {
 		return nil, fmt.Errorf("gagal membuat kelas: %w", err)
 	}
-	return k, nil
+	return k, tx.Commit()
 }
 
 func (r *postgresRepository) UpdateKelas(ctx context.Context, schemaName string, k *Kelas) (*Kelas, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 	query := `
         UPDATE kelas SET
             nama_kelas = $1,
@@ -81,25 +80,32 @@ func (r *postgresRepository) UpdateKelas(ctx context.Context, schemaName string,
         WHERE id = $4
         RETURNING updated_at
     `
-	err := r.db.QueryRowContext(ctx, query, k.NamaKelas, k.TingkatanID, k.WaliKelasID, k.ID).Scan(&k.UpdatedAt)
+	err = tx.QueryRowContext(ctx, query, k.NamaKelas, k.TingkatanID, k.WaliKelasID, k.ID).Scan(&k.UpdatedAt)
 	if err != nil {
 		return nil, fmt.Errorf("gagal memperbarui kelas: %w", err)
 	}
-	return k, nil
+	return k, tx.Commit()
 }
 
 func (r *postgresRepository) DeleteKelas(ctx context.Context, schemaName string, kelasID string) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	_, err = tx.ExecContext(ctx, "DELETE FROM kelas WHERE id = $1", kelasID)
+	if err != nil {
 		return err
 	}
-	_, err := r.db.ExecContext(ctx, "DELETE FROM kelas WHERE id = $1", kelasID)
-	return err
+	return tx.Commit()
 }
 
 func (r *postgresRepository) GetKelasByID(ctx context.Context, schemaName string, kelasID string) (*Kelas, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 	query := `
         SELECT
             k.id, k.nama_kelas, k.tahun_ajaran_id, k.tingkatan_id, k.wali_kelas_id,
@@ -116,9 +122,9 @@ func (r *postgresRepository) GetKelasByID(ctx context.Context, schemaName string
         LEFT JOIN tahun_ajaran ta ON k.tahun_ajaran_id = ta.id
         WHERE k.id = $1
     `
-	row := r.db.QueryRowContext(ctx, query, kelasID)
+	row := tx.QueryRowContext(ctx, query, kelasID)
 	var k Kelas
-	err := row.Scan(
+	err = row.Scan(
 		&k.ID, &k.NamaKelas, &k.TahunAjaranID, &k.TingkatanID, &k.WaliKelasID,
 		&k.CreatedAt, &k.UpdatedAt,
 		&k.NamaTingkatan,
@@ -133,13 +139,15 @@ func (r *postgresRepository) GetKelasByID(ctx context.Context, schemaName string
 		}
 		return nil, err
 	}
-	return &k, nil
+	return &k, tx.Commit()
 }
 
 func (r *postgresRepository) GetAllKelasByTahunAjaran(ctx context.Context, schemaName string, tahunAjaranID string) ([]Kelas, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 	query := `
         SELECT
             k.id, k.nama_kelas, k.tahun_ajaran_id, k.tingkatan_id, k.wali_kelas_id,
@@ -157,7 +165,7 @@ func (r *postgresRepository) GetAllKelasByTahunAjaran(ctx context.Context, schem
         WHERE k.tahun_ajaran_id = $1
         ORDER BY t.urutan, k.nama_kelas
     `
-	rows, err := r.db.QueryContext(ctx, query, tahunAjaranID)
+	rows, err := tx.QueryContext(ctx, query, tahunAjaranID)
 	if err != nil {
 		return nil, err
 	}
@@ -180,17 +188,13 @@ func (r *postgresRepository) GetAllKelasByTahunAjaran(ctx context.Context, schem
 		}
 		list = append(list, k)
 	}
-	return list, nil
+	return list, tx.Commit()
 }
 
 // --- Implementasi Anggota Kelas ---
 
 func (r *postgresRepository) AddAnggotaKelas(ctx context.Context, schemaName string, kelasID string, studentIDs []string) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
-		return err
-	}
-
-	tx, err := r.db.BeginTx(ctx, nil)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
 	if err != nil {
 		return err
 	}
@@ -225,25 +229,40 @@ func (r *postgresRepository) AddAnggotaKelas(ctx context.Context, schemaName str
 }
 
 func (r *postgresRepository) RemoveAnggotaKelas(ctx context.Context, schemaName string, anggotaID string) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	_, err = tx.ExecContext(ctx, "DELETE FROM anggota_kelas WHERE id = $1", anggotaID)
+	if err != nil {
 		return err
 	}
-	_, err := r.db.ExecContext(ctx, "DELETE FROM anggota_kelas WHERE id = $1", anggotaID)
-	return err
+	return tx.Commit()
 }
 
 func (r *postgresRepository) GetAllAnggotaByKelas(ctx context.Context, schemaName string, kelasID string) ([]AnggotaKelas, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
+	list, err := r.GetAllAnggotaByKelasTx(ctx, tx, kelasID)
+	if err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+func (r *postgresRepository) GetAllAnggotaByKelasTx(ctx context.Context, tx *sql.Tx, kelasID string) ([]AnggotaKelas, error) {
 	query := `
-        SELECT ak.id, ak.student_id, ak.urutan, s.nis, s.nisn, s.nama_lengkap, s.jenis_kelamin
+        SELECT ak.id, ak.student_id, ak.urutan, ak.tanggal_keluar, s.nis, s.nisn, s.nama_lengkap, s.jenis_kelamin
         FROM anggota_kelas ak
         JOIN students s ON ak.student_id = s.id
         WHERE ak.kelas_id = $1
         ORDER BY ak.urutan ASC, s.nama_lengkap ASC
     `
-	rows, err := r.db.QueryContext(ctx, query, kelasID)
+	rows, err := tx.QueryContext(ctx, query, kelasID)
 	if err != nil {
 		return nil, err
 	}
@@ -252,23 +271,19 @@ func (r *postgresRepository) GetAllAnggotaByKelas(ctx context.Context, schemaNam
 	var list []AnggotaKelas
 	for rows.Next() {
 		var a AnggotaKelas
-		err := rows.Scan(&a.ID, &a.StudentID, &a.Urutan, &a.NIS, &a.NISN, &a.NamaLengkap, &a.JenisKelamin)
+		err := rows.Scan(&a.ID, &a.StudentID, &a.Urutan, &a.TanggalKeluar, &a.NIS, &a.NISN, &a.NamaLengkap, &a.JenisKelamin)
 		if err != nil {
 			return nil, err
 		}
 		list = append(list, a)
 	}
-	return list, nil
+	return list, rows.Err()
 }
 
 func (r *postgresRepository) UpdateAnggotaKelasUrutan(ctx context.Context, schemaName string, orderedIDs []string) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
-		return err
-	}
-
-	tx, err := r.db.BeginTx(ctx, nil)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
 	if err != nil {
-		return fmt.Errorf("gagal memulai transaksi: %w", err)
+		return err
 	}
 	defer tx.Rollback()
 
@@ -292,9 +307,11 @@ func (r *postgresRepository) UpdateAnggotaKelasUrutan(ctx context.Context, schem
 // FindAnggotaKelasByPengajarKelasIDs mengambil semua anggota kelas (siswa) yang termasuk dalam kelas
 // yang diajar oleh salah satu dari pengajar yang diberikan.
 func (r *postgresRepository) FindAnggotaKelasByPengajarKelasIDs(ctx context.Context, schemaName string, pengajarKelasIDs []uuid.UUID) ([]AnggotaKelas, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 
 	// Query ini mengambil semua data anggota kelas dan siswa yang terkait,
 	// dengan memfilter berdasarkan kelas_id yang didapat dari subquery.
@@ -311,7 +328,7 @@ func (r *postgresRepository) FindAnggotaKelasByPengajarKelasIDs(ctx context.Cont
     `
 
 	// Menggunakan pq.Array untuk mengirim slice Go sebagai array PostgreSQL
-	rows, err := r.db.QueryContext(ctx, query, pq.Array(pengajarKelasIDs))
+	rows, err := tx.QueryContext(ctx, query, pq.Array(pengajarKelasIDs))
 	if err != nil {
 		return nil, fmt.Errorf("gagal menjalankan query FindAnggotaKelasByPengajarKelasIDs: %w", err)
 	}
@@ -333,39 +350,48 @@ func (r *postgresRepository) FindAnggotaKelasByPengajarKelasIDs(ctx context.Cont
 		return nil, fmt.Errorf("error pada baris hasil query: %w", err)
 	}
 
-	return list, nil
+	return list, tx.Commit()
 }
 
 // --- Implementasi Pengajar Kelas ---
 
 func (r *postgresRepository) CreatePengajarKelas(ctx context.Context, schemaName string, p *PengajarKelas) (*PengajarKelas, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 	query := `
         INSERT INTO pengajar_kelas (id, kelas_id, teacher_id, mata_pelajaran_id)
         VALUES ($1, $2, $3, $4)
         RETURNING id, created_at
     `
-	err := r.db.QueryRowContext(ctx, query, uuid.New().String(), p.KelasID, p.TeacherID, p.MataPelajaranID).Scan(&p.ID, &p.CreatedAt)
+	err = tx.QueryRowContext(ctx, query, uuid.New().String(), p.KelasID, p.TeacherID, p.MataPelajaranID).Scan(&p.ID, &p.CreatedAt)
 	if err != nil {
 		return nil, fmt.Errorf("gagal membuat pengajar kelas: %w", err)
 	}
-	return p, nil
+	return p, tx.Commit()
 }
 
 func (r *postgresRepository) RemovePengajarKelas(ctx context.Context, schemaName string, pengajarID string) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return err
 	}
-	_, err := r.db.ExecContext(ctx, "DELETE FROM pengajar_kelas WHERE id = $1", pengajarID)
-	return err
+	defer tx.Rollback()
+	_, err = tx.ExecContext(ctx, "DELETE FROM pengajar_kelas WHERE id = $1", pengajarID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (r *postgresRepository) GetAllPengajarByKelas(ctx context.Context, schemaName string, kelasID string) ([]PengajarKelas, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 	query := `
         SELECT pk.id, pk.teacher_id, pk.mata_pelajaran_id, t.nama_lengkap, mp.nama_mapel, mp.kode_mapel
         FROM pengajar_kelas pk
@@ -374,7 +400,7 @@ func (r *postgresRepository) GetAllPengajarByKelas(ctx context.Context, schemaNa
         WHERE pk.kelas_id = $1
         ORDER BY mp.nama_mapel ASC
     `
-	rows, err := r.db.QueryContext(ctx, query, kelasID)
+	rows, err := tx.QueryContext(ctx, query, kelasID)
 	if err != nil {
 		return nil, err
 	}
@@ -389,5 +415,5 @@ func (r *postgresRepository) GetAllPengajarByKelas(ctx context.Context, schemaNa
 		}
 		list = append(list, p)
 	}
-	return list, nil
+	return list, tx.Commit()
 }
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"skoola/internal/audit"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
@@ -32,12 +33,13 @@ type Service interface {
 
 type service struct {
 	repo     Repository
+	audit    audit.Recorder
 	validate *validator.Validate
 }
 
 // NewService creates a new rombel service instance.
-func NewService(repo Repository, validate *validator.Validate) Service {
-	return &service{repo: repo, validate: validate}
+func NewService(repo Repository, auditLog audit.Recorder, validate *validator.Validate) Service {
+	return &service{repo: repo, audit: auditLog, validate: validate}
 }
 
 func (s *service) CreateKelas(ctx context.Context, schemaName string, input UpsertKelasInput) (*Kelas, error) {
@@ -57,6 +59,7 @@ func (s *service) CreateKelas(ctx context.Context, schemaName string, input Upse
 	if err != nil {
 		return nil, err
 	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionCreate, Entity: "kelas", EntityID: createdKelas.ID, After: createdKelas})
 	return s.repo.GetKelasByID(ctx, schemaName, createdKelas.ID)
 }
 
@@ -70,6 +73,7 @@ func (s *service) UpdateKelas(ctx context.Context, schemaName string, kelasID st
 		return nil, fmt.Errorf("kelas with ID %s not found", kelasID)
 	}
 
+	before := *kelas
 	kelas.NamaKelas = input.NamaKelas
 	kelas.TingkatanID = input.TingkatanID
 	kelas.WaliKelasID = input.WaliKelasID
@@ -77,11 +81,20 @@ func (s *service) UpdateKelas(ctx context.Context, schemaName string, kelasID st
 	if _, err := s.repo.UpdateKelas(ctx, schemaName, kelas); err != nil {
 		return nil, err
 	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionUpdate, Entity: "kelas", EntityID: kelasID, Before: before, After: kelas})
 	return s.repo.GetKelasByID(ctx, schemaName, kelasID)
 }
 
 func (s *service) DeleteKelas(ctx context.Context, schemaName string, kelasID string) error {
-	return s.repo.DeleteKelas(ctx, schemaName, kelasID)
+	before, err := s.repo.GetKelasByID(ctx, schemaName, kelasID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteKelas(ctx, schemaName, kelasID); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionDelete, Entity: "kelas", EntityID: kelasID, Before: before})
+	return nil
 }
 
 func (s *service) GetKelasByID(ctx context.Context, schemaName string, kelasID string) (*Kelas, error) {
@@ -99,11 +112,19 @@ func (s *service) AddAnggotaKelas(ctx context.Context, schemaName string, kelasI
 	if err := s.validate.Struct(input); err != nil {
 		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
 	}
-	return s.repo.AddAnggotaKelas(ctx, schemaName, kelasID, input.StudentIDs)
+	if err := s.repo.AddAnggotaKelas(ctx, schemaName, kelasID, input.StudentIDs); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionCreate, Entity: "anggota_kelas", EntityID: kelasID, After: input})
+	return nil
 }
 
 func (s *service) RemoveAnggotaKelas(ctx context.Context, schemaName string, anggotaID string) error {
-	return s.repo.RemoveAnggotaKelas(ctx, schemaName, anggotaID)
+	if err := s.repo.RemoveAnggotaKelas(ctx, schemaName, anggotaID); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionDelete, Entity: "anggota_kelas", EntityID: anggotaID})
+	return nil
 }
 
 func (s *service) GetAllAnggotaByKelas(ctx context.Context, schemaName string, kelasID string) ([]AnggotaKelas, error) {
@@ -132,6 +153,7 @@ func (s *service) CreatePengajarKelas(ctx context.Context, schemaName string, ke
 	if err != nil {
 		return nil, err
 	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionCreate, Entity: "pengajar_kelas", EntityID: created.ID, After: created})
 
 	list, err := s.repo.GetAllPengajarByKelas(ctx, schemaName, kelasID)
 	if err != nil {
@@ -146,7 +168,11 @@ func (s *service) CreatePengajarKelas(ctx context.Context, schemaName string, ke
 }
 
 func (s *service) RemovePengajarKelas(ctx context.Context, schemaName string, pengajarID string) error {
-	return s.repo.RemovePengajarKelas(ctx, schemaName, pengajarID)
+	if err := s.repo.RemovePengajarKelas(ctx, schemaName, pengajarID); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionDelete, Entity: "pengajar_kelas", EntityID: pengajarID})
+	return nil
 }
 
 func (s *service) GetAllPengajarByKelas(ctx context.Context, schemaName string, kelasID string) ([]PengajarKelas, error) {
@@ -0,0 +1,77 @@
+// file: backend/internal/student/account_handler.go
+package student
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"skoola/internal/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type AccountHandler struct {
+	service AccountService
+}
+
+func NewAccountHandler(s AccountService) *AccountHandler {
+	return &AccountHandler{service: s}
+}
+
+// GetAccounts adalah handler untuk GET /students/accounts.
+func (h *AccountHandler) GetAccounts(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	accounts, err := h.service.GetAccounts(r.Context(), schemaName)
+	if err != nil {
+		http.Error(w, "Gagal mengambil akun siswa: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(accounts)
+}
+
+// GenerateAccounts adalah handler untuk POST /students/accounts/generate.
+// Respons berisi password awal setiap akun dan hanya dikirim sekali.
+func (h *AccountHandler) GenerateAccounts(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	var input GenerateAccountsInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	generated, err := h.service.GenerateAccounts(r.Context(), schemaName, input)
+	if err != nil {
+		if errors.Is(err, ErrValidation) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Gagal membuat akun siswa: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(generated)
+}
+
+// DeleteAccount adalah handler untuk DELETE /students/{studentID}/account.
+func (h *AccountHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	studentID := chi.URLParam(r, "studentID")
+
+	if err := h.service.DeleteAccount(r.Context(), schemaName, studentID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Siswa tidak memiliki akun", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Gagal menghapus akun siswa: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
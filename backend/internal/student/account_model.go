@@ -0,0 +1,48 @@
+// file: backend/internal/student/account_model.go
+package student
+
+// RoleSiswa adalah nilai users.role untuk akun portal siswa.
+const RoleSiswa = "siswa"
+
+// StudentAccountDomain adalah domain email yang dipakai sebagai username akun siswa,
+// misalnya 0012345678@siswa.skoola.id.
+const StudentAccountDomain = "siswa.skoola.id"
+
+// StudentAccount menampilkan status akun portal seorang siswa.
+type StudentAccount struct {
+	StudentID   string  `json:"student_id"`
+	NamaLengkap string  `json:"nama_lengkap"`
+	NIS         *string `json:"nis"`
+	NISN        *string `json:"nisn"`
+	NamaKelas   *string `json:"nama_kelas"`
+	UserID      *string `json:"user_id"`
+	Email       *string `json:"email"`
+}
+
+// GeneratedAccount berisi kredensial yang baru dibuat. Password hanya dikembalikan
+// sekali pada saat pembuatan dan tidak dapat dilihat lagi.
+type GeneratedAccount struct {
+	StudentID   string  `json:"student_id"`
+	NamaLengkap string  `json:"nama_lengkap"`
+	NIS         *string `json:"nis"`
+	NamaKelas   *string `json:"nama_kelas"`
+	Email       string  `json:"email"`
+	Password    string  `json:"password"`
+}
+
+// GenerateAccountsInput adalah DTO untuk membuat akun siswa secara massal.
+// Jika StudentIDs kosong, akun dibuat untuk semua siswa yang belum memiliki akun.
+// ResetPassword membuat password baru untuk siswa yang sudah memiliki akun.
+type GenerateAccountsInput struct {
+	StudentIDs    []string `json:"student_ids" validate:"omitempty,dive,uuid"`
+	ResetPassword bool     `json:"reset_password"`
+}
+
+// accountCredential adalah data yang disimpan repository untuk satu akun siswa.
+type accountCredential struct {
+	StudentID    string
+	UserID       string
+	Email        string
+	PasswordHash string
+	IsNew        bool
+}
@@ -0,0 +1,132 @@
+// file: backend/internal/student/account_repository.go
+package student
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"skoola/pkg/database"
+)
+
+// AccountRepository mendefinisikan interface untuk akun portal siswa.
+type AccountRepository interface {
+	GetAccounts(ctx context.Context, schemaName string) ([]StudentAccount, error)
+	SaveCredentials(ctx context.Context, schemaName string, creds []accountCredential) error
+	DeleteAccount(ctx context.Context, schemaName string, studentID string) (string, error)
+}
+
+type accountPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewAccountRepository(db *sql.DB) AccountRepository {
+	return &accountPostgresRepository{db: db}
+}
+
+// GetAccounts mengambil status akun semua siswa beserta kelasnya di tahun ajaran aktif.
+func (r *accountPostgresRepository) GetAccounts(ctx context.Context, schemaName string) ([]StudentAccount, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT s.id, s.nama_lengkap, s.nis, s.nisn, ck.nama_kelas, u.id, u.email
+		FROM students s
+		LEFT JOIN users u ON u.id = s.user_id
+		LEFT JOIN LATERAL (
+			SELECT k.nama_kelas
+			FROM anggota_kelas ak
+			JOIN kelas k ON ak.kelas_id = k.id
+			JOIN tahun_ajaran ta ON k.tahun_ajaran_id = ta.id
+			WHERE ak.student_id = s.id AND ta.status = 'Aktif'
+			LIMIT 1
+		) ck ON true
+		ORDER BY ck.nama_kelas ASC NULLS LAST, s.nama_lengkap ASC
+	`
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil akun siswa: %w", err)
+	}
+	defer rows.Close()
+
+	accounts := []StudentAccount{}
+	for rows.Next() {
+		var a StudentAccount
+		if err := rows.Scan(&a.StudentID, &a.NamaLengkap, &a.NIS, &a.NISN, &a.NamaKelas, &a.UserID, &a.Email); err != nil {
+			return nil, fmt.Errorf("gagal memindai akun siswa: %w", err)
+		}
+		accounts = append(accounts, a)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return accounts, tx.Commit()
+}
+
+// SaveCredentials membuat akun baru atau mengganti password akun lama dalam satu transaksi.
+// Sesi login akun yang passwordnya diganti ikut dicabut.
+func (r *accountPostgresRepository) SaveCredentials(ctx context.Context, schemaName string, creds []accountCredential) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, c := range creds {
+		if c.IsNew {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO users (id, email, password_hash, role) VALUES ($1, $2, $3, $4)`,
+				c.UserID, c.Email, c.PasswordHash, RoleSiswa,
+			); err != nil {
+				return fmt.Errorf("gagal membuat akun %s: %w", c.Email, err)
+			}
+			if _, err := tx.ExecContext(ctx,
+				`UPDATE students SET user_id = $1, updated_at = NOW() WHERE id = $2`,
+				c.UserID, c.StudentID,
+			); err != nil {
+				return fmt.Errorf("gagal menghubungkan akun ke siswa: %w", err)
+			}
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE users SET password_hash = $1, updated_at = NOW() WHERE id = $2`,
+			c.PasswordHash, c.UserID,
+		); err != nil {
+			return fmt.Errorf("gagal mengganti password %s: %w", c.Email, err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`,
+			c.UserID,
+		); err != nil {
+			return fmt.Errorf("gagal mencabut sesi siswa: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// DeleteAccount menghapus akun portal seorang siswa dan mengembalikan ID user yang dihapus.
+func (r *accountPostgresRepository) DeleteAccount(ctx context.Context, schemaName string, studentID string) (string, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	var userID sql.NullString
+	err = tx.QueryRowContext(ctx, `SELECT user_id FROM students WHERE id = $1`, studentID).Scan(&userID)
+	if err != nil {
+		return "", err
+	}
+	if !userID.Valid {
+		return "", sql.ErrNoRows
+	}
+
+	// students.user_id otomatis menjadi NULL karena ON DELETE SET NULL.
+	if _, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, userID.String); err != nil {
+		return "", fmt.Errorf("gagal menghapus akun siswa: %w", err)
+	}
+	return userID.String, tx.Commit()
+}
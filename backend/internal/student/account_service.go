@@ -0,0 +1,157 @@
+// file: backend/internal/student/account_service.go
+package student
+
+import (
+	"context"
+	"fmt"
+	"skoola/internal/audit"
+	"skoola/pkg/credential"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// AccountService mendefinisikan logika bisnis untuk akun portal siswa.
+type AccountService interface {
+	GetAccounts(ctx context.Context, schemaName string) ([]StudentAccount, error)
+	GenerateAccounts(ctx context.Context, schemaName string, input GenerateAccountsInput) ([]GeneratedAccount, error)
+	DeleteAccount(ctx context.Context, schemaName string, studentID string) error
+}
+
+type accountService struct {
+	repo     AccountRepository
+	audit    audit.Recorder
+	validate *validator.Validate
+}
+
+func NewAccountService(repo AccountRepository, auditLog audit.Recorder, validate *validator.Validate) AccountService {
+	return &accountService{repo: repo, audit: auditLog, validate: validate}
+}
+
+func (s *accountService) GetAccounts(ctx context.Context, schemaName string) ([]StudentAccount, error) {
+	return s.repo.GetAccounts(ctx, schemaName)
+}
+
+// GenerateAccounts membuat akun untuk siswa yang belum memiliki akun. Username berupa
+// NISN (atau NIS jika NISN kosong) dengan domain StudentAccountDomain.
+func (s *accountService) GenerateAccounts(ctx context.Context, schemaName string, input GenerateAccountsInput) ([]GeneratedAccount, error) {
+	if err := s.validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+
+	accounts, err := s.repo.GetAccounts(ctx, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Email semua siswa dikumpulkan dulu agar username baru tidak bentrok.
+	usedEmails := make(map[string]bool)
+	for _, a := range accounts {
+		if a.Email != nil {
+			usedEmails[*a.Email] = true
+		}
+	}
+	selected := make(map[string]bool, len(input.StudentIDs))
+	for _, id := range input.StudentIDs {
+		selected[id] = true
+	}
+
+	var creds []accountCredential
+	var generated []GeneratedAccount
+	var entries []audit.Entry
+	for _, a := range accounts {
+		if len(selected) > 0 && !selected[a.StudentID] {
+			continue
+		}
+		hasAccount := a.UserID != nil
+		if hasAccount && !input.ResetPassword {
+			continue
+		}
+
+		password, err := credential.RandomPassword()
+		if err != nil {
+			return nil, err
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), 10)
+		if err != nil {
+			return nil, fmt.Errorf("gagal melakukan hash password: %w", err)
+		}
+
+		cred := accountCredential{StudentID: a.StudentID, PasswordHash: string(hash), IsNew: !hasAccount}
+		if hasAccount {
+			cred.UserID = *a.UserID
+			cred.Email = *a.Email
+		} else {
+			cred.UserID = uuid.New().String()
+			cred.Email = uniqueEmail(accountUsername(a), usedEmails)
+		}
+		creds = append(creds, cred)
+
+		generated = append(generated, GeneratedAccount{
+			StudentID:   a.StudentID,
+			NamaLengkap: a.NamaLengkap,
+			NIS:         a.NIS,
+			NamaKelas:   a.NamaKelas,
+			Email:       cred.Email,
+			Password:    password,
+		})
+
+		action := audit.ActionCreate
+		if hasAccount {
+			action = audit.ActionUpdate
+		}
+		entries = append(entries, audit.Entry{
+			Action:   action,
+			Entity:   "student_accounts",
+			EntityID: a.StudentID,
+			After:    map[string]string{"user_id": cred.UserID, "email": cred.Email},
+		})
+	}
+
+	if len(creds) == 0 {
+		return []GeneratedAccount{}, nil
+	}
+	if err := s.repo.SaveCredentials(ctx, schemaName, creds); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, schemaName, entries...)
+	return generated, nil
+}
+
+func (s *accountService) DeleteAccount(ctx context.Context, schemaName string, studentID string) error {
+	userID, err := s.repo.DeleteAccount(ctx, schemaName, studentID)
+	if err != nil {
+		return err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{
+		Action:   audit.ActionDelete,
+		Entity:   "student_accounts",
+		EntityID: studentID,
+		Before:   map[string]string{"user_id": userID},
+	})
+	return nil
+}
+
+// accountUsername memilih NISN, lalu NIS, lalu potongan ID siswa sebagai username.
+func accountUsername(a StudentAccount) string {
+	switch {
+	case a.NISN != nil && strings.TrimSpace(*a.NISN) != "":
+		return strings.TrimSpace(*a.NISN)
+	case a.NIS != nil && strings.TrimSpace(*a.NIS) != "":
+		return strings.TrimSpace(*a.NIS)
+	default:
+		return strings.ReplaceAll(a.StudentID, "-", "")[:12]
+	}
+}
+
+// uniqueEmail menambahkan akhiran angka jika username sudah dipakai siswa lain.
+func uniqueEmail(username string, used map[string]bool) string {
+	email := strings.ToLower(username) + "@" + StudentAccountDomain
+	for i := 2; used[email]; i++ {
+		email = fmt.Sprintf("%s.%d@%s", strings.ToLower(username), i, StudentAccountDomain)
+	}
+	used[email] = true
+	return email
+}
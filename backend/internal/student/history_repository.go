@@ -4,7 +4,7 @@ package student
 import (
 	"context"
 	"database/sql"
-	"fmt"
+	"skoola/pkg/database"
 )
 
 // HistoryRepository mendefinisikan interface untuk interaksi database riwayat akademik.
@@ -25,28 +25,32 @@ func NewHistoryRepository(db *sql.DB) HistoryRepository {
 }
 
 func (r *historyPostgresRepository) Create(ctx context.Context, schemaName string, h *RiwayatAkademik) error {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 	query := `
 		INSERT INTO riwayat_akademik (id, student_id, status, tanggal_kejadian, kelas_tingkat, keterangan)
 		VALUES ($1, $2, $3, $4, $5, $6)
 	`
-	_, err := r.db.ExecContext(ctx, query, h.ID, h.StudentID, h.Status, h.TanggalKejadian, h.KelasTingkat, h.Keterangan)
-	return err
+	if _, err := tx.ExecContext(ctx, query, h.ID, h.StudentID, h.Status, h.TanggalKejadian, h.KelasTingkat, h.Keterangan); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (r *historyPostgresRepository) GetByStudentID(ctx context.Context, schemaName string, studentID string) ([]RiwayatAkademik, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 	query := `
 		SELECT id, student_id, status, tanggal_kejadian, kelas_tingkat, keterangan, created_at, updated_at
 		FROM riwayat_akademik WHERE student_id = $1 ORDER BY tanggal_kejadian DESC, created_at DESC
 	`
-	rows, err := r.db.QueryContext(ctx, query, studentID)
+	rows, err := tx.QueryContext(ctx, query, studentID)
 	if err != nil {
 		return nil, err
 	}
@@ -60,48 +64,58 @@ func (r *historyPostgresRepository) GetByStudentID(ctx context.Context, schemaNa
 		}
 		histories = append(histories, h)
 	}
-	return histories, nil
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return histories, tx.Commit()
 }
 
 func (r *historyPostgresRepository) GetByID(ctx context.Context, schemaName string, historyID string) (*RiwayatAkademik, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `SELECT id, student_id, status, tanggal_kejadian, kelas_tingkat, keterangan, created_at, updated_at FROM riwayat_akademik WHERE id = $1`
-	row := r.db.QueryRowContext(ctx, query, historyID)
+	row := tx.QueryRowContext(ctx, query, historyID)
 
 	var h RiwayatAkademik
-	err := row.Scan(&h.ID, &h.StudentID, &h.Status, &h.TanggalKejadian, &h.KelasTingkat, &h.Keterangan, &h.CreatedAt, &h.UpdatedAt)
+	err = row.Scan(&h.ID, &h.StudentID, &h.Status, &h.TanggalKejadian, &h.KelasTingkat, &h.Keterangan, &h.CreatedAt, &h.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
 		return nil, err
 	}
-	return &h, nil
+	return &h, tx.Commit()
 }
 
 func (r *historyPostgresRepository) Update(ctx context.Context, schemaName string, h *RiwayatAkademik) error {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 	query := `
 		UPDATE riwayat_akademik SET status = $1, tanggal_kejadian = $2, kelas_tingkat = $3, keterangan = $4, updated_at = NOW()
 		WHERE id = $5
 	`
-	_, err := r.db.ExecContext(ctx, query, h.Status, h.TanggalKejadian, h.KelasTingkat, h.Keterangan, h.ID)
-	return err
+	if _, err := tx.ExecContext(ctx, query, h.Status, h.TanggalKejadian, h.KelasTingkat, h.Keterangan, h.ID); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (r *historyPostgresRepository) Delete(ctx context.Context, schemaName string, historyID string) error {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 	query := `DELETE FROM riwayat_akademik WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, historyID)
-	return err
+	if _, err := tx.ExecContext(ctx, query, historyID); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
@@ -3,7 +3,9 @@ package student
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"skoola/internal/audit"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -19,11 +21,12 @@ type HistoryService interface {
 
 type historyService struct {
 	repo     HistoryRepository
+	audit    audit.Recorder
 	validate *validator.Validate
 }
 
-func NewHistoryService(repo HistoryRepository, validate *validator.Validate) HistoryService {
-	return &historyService{repo: repo, validate: validate}
+func NewHistoryService(repo HistoryRepository, auditLog audit.Recorder, validate *validator.Validate) HistoryService {
+	return &historyService{repo: repo, audit: auditLog, validate: validate}
 }
 
 func (s *historyService) CreateHistory(ctx context.Context, schemaName string, studentID string, input UpsertHistoryInput) error {
@@ -41,7 +44,11 @@ func (s *historyService) CreateHistory(ctx context.Context, schemaName string, s
 		KelasTingkat:    stringToPtr(input.KelasTingkat),
 		Keterangan:      stringToPtr(input.Keterangan),
 	}
-	return s.repo.Create(ctx, schemaName, history)
+	if err := s.repo.Create(ctx, schemaName, history); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionCreate, Entity: "riwayat_akademik", EntityID: history.ID, After: history})
+	return nil
 }
 
 func (s *historyService) GetHistoryByStudentID(ctx context.Context, schemaName string, studentID string) ([]RiwayatAkademik, error) {
@@ -57,7 +64,11 @@ func (s *historyService) UpdateHistory(ctx context.Context, schemaName string, h
 	if err != nil {
 		return fmt.Errorf("gagal menemukan riwayat: %w", err)
 	}
+	if history == nil {
+		return sql.ErrNoRows
+	}
 
+	before := *history
 	eventDate, _ := time.Parse("2006-01-02", input.TanggalKejadian)
 
 	history.Status = input.Status
@@ -65,9 +76,21 @@ func (s *historyService) UpdateHistory(ctx context.Context, schemaName string, h
 	history.KelasTingkat = stringToPtr(input.KelasTingkat)
 	history.Keterangan = stringToPtr(input.Keterangan)
 
-	return s.repo.Update(ctx, schemaName, history)
+	if err := s.repo.Update(ctx, schemaName, history); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionUpdate, Entity: "riwayat_akademik", EntityID: historyID, Before: before, After: history})
+	return nil
 }
 
 func (s *historyService) DeleteHistory(ctx context.Context, schemaName string, historyID string) error {
-	return s.repo.Delete(ctx, schemaName, historyID)
+	history, err := s.repo.GetByID(ctx, schemaName, historyID)
+	if err != nil {
+		return fmt.Errorf("gagal menemukan riwayat: %w", err)
+	}
+	if err := s.repo.Delete(ctx, schemaName, historyID); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionDelete, Entity: "riwayat_akademik", EntityID: historyID, Before: history})
+	return nil
 }
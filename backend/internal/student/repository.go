@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"skoola/pkg/database"
 )
 
 // Repository mendefinisikan interface untuk interaksi database siswa.
@@ -87,9 +88,11 @@ func scanStudentDetail(row interface{ Scan(...interface{}) error }) (*Student, e
 }
 
 func (r *postgresRepository) GetAvailableStudentsByTahunAjaran(ctx context.Context, schemaName string, tahunAjaranID string) ([]Student, error) {
-	if _, err := r.db.ExecContext(ctx, fmt.Sprintf("SET search_path TO %q", schemaName)); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `
 		SELECT s.id, s.nama_lengkap, s.nis, s.nama_panggilan
@@ -112,7 +115,7 @@ func (r *postgresRepository) GetAvailableStudentsByTahunAjaran(ctx context.Conte
 			)
 		ORDER BY s.nama_lengkap ASC;
 	`
-	rows, err := r.db.QueryContext(ctx, query, tahunAjaranID)
+	rows, err := tx.QueryContext(ctx, query, tahunAjaranID)
 	if err != nil {
 		return nil, fmt.Errorf("gagal query get available students: %w", err)
 	}
@@ -126,13 +129,15 @@ func (r *postgresRepository) GetAvailableStudentsByTahunAjaran(ctx context.Conte
 		}
 		students = append(students, s)
 	}
-	return students, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return students, tx.Commit()
 }
 
 func (r *postgresRepository) Create(ctx context.Context, tx *sql.Tx, schemaName string, student *Student) error {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := tx.ExecContext(ctx, setSchemaQuery); err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	if err := database.SetTenant(ctx, tx, schemaName); err != nil {
+		return err
 	}
 
 	query := `
@@ -160,12 +165,14 @@ func (r *postgresRepository) Create(ctx context.Context, tx *sql.Tx, schemaName
 }
 
 func (r *postgresRepository) GetAll(ctx context.Context, schemaName string) ([]Student, error) {
-	if _, err := r.db.ExecContext(ctx, fmt.Sprintf("SET search_path TO %q", schemaName)); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := studentDetailQuery + " ORDER BY s.nama_lengkap ASC"
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := tx.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("gagal query get all students: %w", err)
 	}
@@ -179,22 +186,29 @@ func (r *postgresRepository) GetAll(ctx context.Context, schemaName string) ([]S
 		}
 		students = append(students, *s)
 	}
-	return students, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return students, tx.Commit()
 }
 
 func (r *postgresRepository) GetByID(ctx context.Context, schemaName string, id string) (*Student, error) {
-	if _, err := r.db.ExecContext(ctx, fmt.Sprintf("SET search_path TO %q", schemaName)); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 	query := studentDetailQuery + " WHERE s.id = $1"
-	row := r.db.QueryRowContext(ctx, query, id)
+	row := tx.QueryRowContext(ctx, query, id)
 	return scanStudentDetail(row)
 }
 
 func (r *postgresRepository) Update(ctx context.Context, schemaName string, student *Student) error {
-	if _, err := r.db.ExecContext(ctx, fmt.Sprintf("SET search_path TO %q", schemaName)); err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 
 	query := `
 		UPDATE students SET
@@ -207,7 +221,7 @@ func (r *postgresRepository) Update(ctx context.Context, schemaName string, stud
 			nama_wali = $22, pekerjaan_wali = $23, alamat_wali = $24, nomor_kontak_wali = $25
 		WHERE id = $26
 	`
-	result, err := r.db.ExecContext(ctx, query,
+	result, err := tx.ExecContext(ctx, query,
 		student.NIS, student.NISN,
 		student.NamaLengkap, student.NamaPanggilan, student.JenisKelamin, student.TempatLahir, student.TanggalLahir, student.Agama, student.Kewarganegaraan,
 		student.AlamatLengkap, student.DesaKelurahan, student.Kecamatan, student.KotaKabupaten, student.Provinsi, student.KodePos,
@@ -227,26 +241,30 @@ func (r *postgresRepository) Update(ctx context.Context, schemaName string, stud
 	if rowsAffected == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+	return tx.Commit()
 }
 
 func (r *postgresRepository) Delete(ctx context.Context, schemaName string, id string) error {
-	if _, err := r.db.ExecContext(ctx, fmt.Sprintf("SET search_path TO %q", schemaName)); err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
-	}
-
-	query := `DELETE FROM students WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, id)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
 	if err != nil {
-		return fmt.Errorf("gagal mengeksekusi query delete student: %w", err)
+		return err
 	}
+	defer tx.Rollback()
 
-	rowsAffected, err := result.RowsAffected()
+	// Akun portal siswa (jika ada) ikut dihapus sehingga sesi loginnya juga berakhir.
+	var userID sql.NullString
+	query := `DELETE FROM students WHERE id = $1 RETURNING user_id`
+	err = tx.QueryRowContext(ctx, query, id).Scan(&userID)
 	if err != nil {
-		return fmt.Errorf("gagal memeriksa baris yang terpengaruh: %w", err)
+		if err == sql.ErrNoRows {
+			return sql.ErrNoRows
+		}
+		return fmt.Errorf("gagal mengeksekusi query delete student: %w", err)
 	}
-	if rowsAffected == 0 {
-		return sql.ErrNoRows
+	if userID.Valid {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, userID.String); err != nil {
+			return fmt.Errorf("gagal menghapus akun siswa: %w", err)
+		}
 	}
-	return nil
+	return tx.Commit()
 }
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"skoola/internal/audit"
 	"strconv"
 	"strings"
 	"time"
@@ -89,14 +90,16 @@ type Service interface {
 type service struct {
 	repo        Repository
 	historyRepo HistoryRepository
+	audit       audit.Recorder
 	validate    *validator.Validate
 	db          *sql.DB
 }
 
-func NewService(repo Repository, historyRepo HistoryRepository, validate *validator.Validate, db *sql.DB) Service {
+func NewService(repo Repository, historyRepo HistoryRepository, auditLog audit.Recorder, validate *validator.Validate, db *sql.DB) Service {
 	return &service{
 		repo:        repo,
 		historyRepo: historyRepo,
+		audit:       auditLog,
 		validate:    validate,
 		db:          db,
 	}
@@ -171,6 +174,8 @@ func (s *service) ImportStudents(ctx context.Context, schemaName string, file io
 		return result, nil // File kosong atau hanya header
 	}
 
+	var imported []audit.Entry
+
 	for i, row := range rows[1:] {
 		rowIndex := i + 2
 
@@ -296,14 +301,6 @@ func (s *service) ImportStudents(ctx context.Context, schemaName string, file io
 			Keterangan:      stringToPtr("Siswa baru via impor Excel"),
 		}
 
-		setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-		if _, err := tx.ExecContext(ctx, setSchemaQuery); err != nil {
-			tx.Rollback()
-			result.ErrorCount++
-			result.Errors = append(result.Errors, ImportError{Row: rowIndex, Message: "Gagal mengatur skema DB."})
-			continue
-		}
-
 		historyQuery := `INSERT INTO riwayat_akademik (id, student_id, status, tanggal_kejadian, keterangan) VALUES ($1, $2, $3, $4, $5)`
 		_, err = tx.ExecContext(ctx, historyQuery, initialHistory.ID, initialHistory.StudentID, initialHistory.Status, initialHistory.TanggalKejadian, initialHistory.Keterangan)
 		if err != nil {
@@ -319,9 +316,11 @@ func (s *service) ImportStudents(ctx context.Context, schemaName string, file io
 			continue
 		}
 
+		imported = append(imported, audit.Entry{Action: audit.ActionCreate, Entity: "students", EntityID: student.ID, After: student})
 		result.SuccessCount++
 	}
 
+	s.audit.Record(ctx, schemaName, imported...)
 	return result, nil
 }
 
@@ -407,10 +406,6 @@ func (s *service) Create(ctx context.Context, schemaName string, input CreateStu
 		Keterangan:      stringToPtr("Siswa baru"),
 	}
 
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := tx.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema untuk riwayat: %w", err)
-	}
 	historyQuery := `
 		INSERT INTO riwayat_akademik (id, student_id, status, tanggal_kejadian, keterangan)
 		VALUES ($1, $2, $3, $4, $5)
@@ -428,6 +423,7 @@ func (s *service) Create(ctx context.Context, schemaName string, input CreateStu
 	if err != nil {
 		return nil, fmt.Errorf("gagal mengambil data siswa setelah dibuat: %w", err)
 	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionCreate, Entity: "students", EntityID: createdStudent.ID, After: createdStudent})
 
 	return createdStudent, nil
 }
@@ -443,6 +439,7 @@ func (s *service) Update(ctx context.Context, schemaName string, id string, inpu
 	if student == nil {
 		return sql.ErrNoRows
 	}
+	before := *student
 
 	student.NIS = stringToPtr(input.NIS)
 	student.NISN = stringToPtr(input.NISN)
@@ -473,6 +470,7 @@ func (s *service) Update(ctx context.Context, schemaName string, id string, inpu
 	if err := s.repo.Update(ctx, schemaName, student); err != nil {
 		return fmt.Errorf("gagal mengupdate siswa di service: %w", err)
 	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionUpdate, Entity: "students", EntityID: id, Before: before, After: student})
 
 	return nil
 }
@@ -499,5 +497,9 @@ func (s *service) Delete(ctx context.Context, schemaName string, id string) erro
 		return sql.ErrNoRows
 	}
 
-	return s.repo.Delete(ctx, schemaName, id)
+	if err := s.repo.Delete(ctx, schemaName, id); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionDelete, Entity: "students", EntityID: id, Before: student})
+	return nil
 }
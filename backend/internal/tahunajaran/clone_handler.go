@@ -0,0 +1,53 @@
+// file: backend/internal/tahunajaran/clone_handler.go
+package tahunajaran
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"skoola/internal/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type CloneHandler struct {
+	service CloneService
+}
+
+func NewCloneHandler(s CloneService) *CloneHandler {
+	return &CloneHandler{service: s}
+}
+
+// CloneFrom adalah handler untuk POST /tahun-ajaran/{id}/clone-from/{sourceID}.
+// Kirim "dry_run": true untuk melihat pratinjau tanpa menyimpan perubahan.
+func (h *CloneHandler) CloneFrom(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	id := chi.URLParam(r, "id")
+	sourceID := chi.URLParam(r, "sourceID")
+
+	var input CloneInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	hasil, err := h.service.CloneFrom(r.Context(), schemaName, id, sourceID, input)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrValidation):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, sql.ErrNoRows):
+			http.Error(w, "Data tidak ditemukan", http.StatusNotFound)
+		default:
+			http.Error(w, "Gagal menyalin tahun ajaran: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !hasil.DryRun {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(hasil)
+}
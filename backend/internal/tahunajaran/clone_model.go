@@ -0,0 +1,63 @@
+// file: backend/internal/tahunajaran/clone_model.go
+package tahunajaran
+
+// CloneInput memilih bagian struktur tahun ajaran sumber yang disalin. Selama DryRun
+// bernilai true, penyalinan dijalankan lalu dibatalkan sehingga hasilnya hanya pratinjau.
+type CloneInput struct {
+	Kelas               bool `json:"kelas"`
+	Pengajar            bool `json:"pengajar"`
+	Kurikulum           bool `json:"kurikulum"`
+	Ekstrakurikuler     bool `json:"ekstrakurikuler"`
+	RencanaPembelajaran bool `json:"rencana_pembelajaran"`
+	DryRun              bool `json:"dry_run"`
+}
+
+// RingkasanClone menghitung baris yang dibuat dan yang dilewati karena sudah ada.
+type RingkasanClone struct {
+	Dibuat   int `json:"dibuat"`
+	Dilewati int `json:"dilewati"`
+}
+
+// HasilClone adalah hasil (atau pratinjau) penyalinan struktur tahun ajaran.
+type HasilClone struct {
+	DryRun          bool           `json:"dry_run"`
+	Kelas           RingkasanClone `json:"kelas"`
+	KelasBaru       []string       `json:"kelas_baru"`
+	Pengajar        RingkasanClone `json:"pengajar"`
+	Kurikulum       RingkasanClone `json:"kurikulum"`
+	Pemetaan        RingkasanClone `json:"pemetaan"`
+	Ekstrakurikuler RingkasanClone `json:"ekstrakurikuler"`
+	Materi          RingkasanClone `json:"materi"`
+	Tujuan          RingkasanClone `json:"tujuan"`
+}
+
+// kelasSumber adalah kelas pada tahun ajaran sumber.
+type kelasSumber struct {
+	ID          string
+	NamaKelas   string
+	TingkatanID int
+	WaliKelasID *string
+}
+
+// pengajarSumber adalah penugasan guru pada tahun ajaran sumber.
+type pengajarSumber struct {
+	ID              string
+	KelasID         string
+	TeacherID       string
+	MataPelajaranID string
+}
+
+// materiSumber adalah materi pembelajaran beserta tujuan pembelajarannya.
+type materiSumber struct {
+	ID              int
+	PengajarKelasID string
+	NamaMateri      string
+	Deskripsi       *string
+	Urutan          int
+	Tujuan          []tujuanSumber
+}
+
+type tujuanSumber struct {
+	DeskripsiTujuan string
+	Urutan          int
+}
@@ -0,0 +1,371 @@
+// file: backend/internal/tahunajaran/clone_repository.go
+package tahunajaran
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"skoola/pkg/database"
+
+	"github.com/google/uuid"
+)
+
+// CloneRepository mendefinisikan interface penyalinan struktur antar tahun ajaran.
+type CloneRepository interface {
+	// Clone menyalin bagian yang dipilih dari tahun ajaran sumber ke tujuan dalam satu
+	// transaksi. Untuk dry run transaksi tidak di-commit.
+	Clone(ctx context.Context, schemaName string, sourceID string, targetID string, input CloneInput) (*HasilClone, error)
+}
+
+type postgresCloneRepository struct {
+	db *sql.DB
+}
+
+// NewCloneRepository membuat instance baru dari postgresCloneRepository.
+func NewCloneRepository(db *sql.DB) CloneRepository {
+	return &postgresCloneRepository{db: db}
+}
+
+func (r *postgresCloneRepository) Clone(ctx context.Context, schemaName string, sourceID string, targetID string, input CloneInput) (*HasilClone, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var jumlah int
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM tahun_ajaran WHERE id IN ($1, $2)", sourceID, targetID).Scan(&jumlah); err != nil {
+		return nil, fmt.Errorf("gagal memeriksa tahun ajaran: %w", err)
+	}
+	if jumlah != 2 {
+		return nil, sql.ErrNoRows
+	}
+
+	hasil := &HasilClone{DryRun: input.DryRun, KelasBaru: []string{}}
+
+	// Kelas selalu dipetakan berdasarkan nama agar penugasan dan rencana pembelajaran
+	// tetap dapat disalin ke kelas yang sudah dibuat manual di tahun ajaran tujuan.
+	kelasMap, err := cloneKelas(ctx, tx, sourceID, targetID, input.Kelas, hasil)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Pengajar || input.RencanaPembelajaran {
+		pengajarMap, err := clonePengajar(ctx, tx, sourceID, kelasMap, input.Pengajar, hasil)
+		if err != nil {
+			return nil, err
+		}
+		if input.RencanaPembelajaran {
+			if err := cloneRencana(ctx, tx, sourceID, targetID, pengajarMap, hasil); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if input.Kurikulum {
+		if err := cloneKurikulum(ctx, tx, sourceID, targetID, hasil); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.Ekstrakurikuler {
+		query := `
+			INSERT INTO ekstrakurikuler_sesi (ekstrakurikuler_id, tahun_ajaran_id, pembina_id)
+			SELECT ekstrakurikuler_id, $2, pembina_id FROM ekstrakurikuler_sesi WHERE tahun_ajaran_id = $1
+			ON CONFLICT (ekstrakurikuler_id, tahun_ajaran_id) DO NOTHING
+		`
+		ringkasan, err := salinSemua(ctx, tx, query, "SELECT COUNT(*) FROM ekstrakurikuler_sesi WHERE tahun_ajaran_id = $1", sourceID, targetID)
+		if err != nil {
+			return nil, fmt.Errorf("gagal menyalin sesi ekstrakurikuler: %w", err)
+		}
+		hasil.Ekstrakurikuler = ringkasan
+	}
+
+	if input.DryRun {
+		return hasil, nil
+	}
+	return hasil, tx.Commit()
+}
+
+// cloneKelas mengembalikan peta ID kelas sumber ke ID kelas tujuan.
+func cloneKelas(ctx context.Context, tx *sql.Tx, sourceID string, targetID string, buat bool, hasil *HasilClone) (map[string]string, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT id, nama_kelas, tingkatan_id, wali_kelas_id FROM kelas WHERE tahun_ajaran_id = $1 ORDER BY nama_kelas", sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil kelas sumber: %w", err)
+	}
+	var sumber []kelasSumber
+	for rows.Next() {
+		var k kelasSumber
+		if err := rows.Scan(&k.ID, &k.NamaKelas, &k.TingkatanID, &k.WaliKelasID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("gagal memindai kelas sumber: %w", err)
+		}
+		sumber = append(sumber, k)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = tx.QueryContext(ctx, "SELECT id, nama_kelas FROM kelas WHERE tahun_ajaran_id = $1", targetID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil kelas tujuan: %w", err)
+	}
+	ada := make(map[string]string)
+	for rows.Next() {
+		var id, nama string
+		if err := rows.Scan(&id, &nama); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("gagal memindai kelas tujuan: %w", err)
+		}
+		ada[nama] = id
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	kelasMap := make(map[string]string, len(sumber))
+	for _, k := range sumber {
+		if id, ok := ada[k.NamaKelas]; ok {
+			kelasMap[k.ID] = id
+			if buat {
+				hasil.Kelas.Dilewati++
+			}
+			continue
+		}
+		if !buat {
+			continue
+		}
+		id := uuid.New().String()
+		query := "INSERT INTO kelas (id, nama_kelas, tahun_ajaran_id, tingkatan_id, wali_kelas_id) VALUES ($1, $2, $3, $4, $5)"
+		if _, err := tx.ExecContext(ctx, query, id, k.NamaKelas, targetID, k.TingkatanID, k.WaliKelasID); err != nil {
+			return nil, fmt.Errorf("gagal menyalin kelas %s: %w", k.NamaKelas, err)
+		}
+		kelasMap[k.ID] = id
+		hasil.Kelas.Dibuat++
+		hasil.KelasBaru = append(hasil.KelasBaru, k.NamaKelas)
+	}
+	return kelasMap, nil
+}
+
+// clonePengajar mengembalikan peta ID pengajar_kelas sumber ke tujuan. Jika buat false,
+// hanya penugasan yang sudah ada di tahun ajaran tujuan yang dipetakan.
+func clonePengajar(ctx context.Context, tx *sql.Tx, sourceID string, kelasMap map[string]string, buat bool, hasil *HasilClone) (map[string]string, error) {
+	query := `
+		SELECT pk.id, pk.kelas_id, pk.teacher_id, pk.mata_pelajaran_id
+		FROM pengajar_kelas pk
+		JOIN kelas k ON pk.kelas_id = k.id
+		WHERE k.tahun_ajaran_id = $1
+	`
+	rows, err := tx.QueryContext(ctx, query, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil pengajar sumber: %w", err)
+	}
+	var sumber []pengajarSumber
+	for rows.Next() {
+		var p pengajarSumber
+		if err := rows.Scan(&p.ID, &p.KelasID, &p.TeacherID, &p.MataPelajaranID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("gagal memindai pengajar sumber: %w", err)
+		}
+		sumber = append(sumber, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	insertQuery := `
+		INSERT INTO pengajar_kelas (id, kelas_id, teacher_id, mata_pelajaran_id) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (kelas_id, teacher_id, mata_pelajaran_id) DO NOTHING
+	`
+	selectQuery := "SELECT id FROM pengajar_kelas WHERE kelas_id = $1 AND teacher_id = $2 AND mata_pelajaran_id = $3"
+	pengajarMap := make(map[string]string, len(sumber))
+	for _, p := range sumber {
+		kelasID, ok := kelasMap[p.KelasID]
+		if !ok {
+			continue
+		}
+		if buat {
+			id := uuid.New().String()
+			res, err := tx.ExecContext(ctx, insertQuery, id, kelasID, p.TeacherID, p.MataPelajaranID)
+			if err != nil {
+				return nil, fmt.Errorf("gagal menyalin pengajar kelas: %w", err)
+			}
+			if n, _ := res.RowsAffected(); n > 0 {
+				pengajarMap[p.ID] = id
+				hasil.Pengajar.Dibuat++
+				continue
+			}
+			hasil.Pengajar.Dilewati++
+		}
+		var id string
+		err := tx.QueryRowContext(ctx, selectQuery, kelasID, p.TeacherID, p.MataPelajaranID).Scan(&id)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("gagal mengambil pengajar tujuan: %w", err)
+		}
+		pengajarMap[p.ID] = id
+	}
+	return pengajarMap, nil
+}
+
+// cloneRencana menyalin materi dan tujuan pembelajaran. Penugasan tujuan yang sudah
+// memiliki materi dilewati agar rencana yang sudah disusun guru tidak tergandakan.
+func cloneRencana(ctx context.Context, tx *sql.Tx, sourceID string, targetID string, pengajarMap map[string]string, hasil *HasilClone) error {
+	query := `
+		SELECT mp.id, mp.pengajar_kelas_id, mp.nama_materi, mp.deskripsi, COALESCE(mp.urutan, 0)
+		FROM materi_pembelajaran mp
+		JOIN pengajar_kelas pk ON mp.pengajar_kelas_id = pk.id
+		JOIN kelas k ON pk.kelas_id = k.id
+		WHERE k.tahun_ajaran_id = $1
+		ORDER BY mp.pengajar_kelas_id, mp.urutan, mp.id
+	`
+	rows, err := tx.QueryContext(ctx, query, sourceID)
+	if err != nil {
+		return fmt.Errorf("gagal mengambil materi sumber: %w", err)
+	}
+	var materi []materiSumber
+	indeks := make(map[int]int)
+	for rows.Next() {
+		var m materiSumber
+		if err := rows.Scan(&m.ID, &m.PengajarKelasID, &m.NamaMateri, &m.Deskripsi, &m.Urutan); err != nil {
+			rows.Close()
+			return fmt.Errorf("gagal memindai materi sumber: %w", err)
+		}
+		indeks[m.ID] = len(materi)
+		materi = append(materi, m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	query = `
+		SELECT tp.materi_pembelajaran_id, tp.deskripsi_tujuan, COALESCE(tp.urutan, 0)
+		FROM tujuan_pembelajaran tp
+		JOIN materi_pembelajaran mp ON tp.materi_pembelajaran_id = mp.id
+		JOIN pengajar_kelas pk ON mp.pengajar_kelas_id = pk.id
+		JOIN kelas k ON pk.kelas_id = k.id
+		WHERE k.tahun_ajaran_id = $1
+		ORDER BY tp.urutan, tp.id
+	`
+	rows, err = tx.QueryContext(ctx, query, sourceID)
+	if err != nil {
+		return fmt.Errorf("gagal mengambil tujuan pembelajaran sumber: %w", err)
+	}
+	for rows.Next() {
+		var materiID int
+		var t tujuanSumber
+		if err := rows.Scan(&materiID, &t.DeskripsiTujuan, &t.Urutan); err != nil {
+			rows.Close()
+			return fmt.Errorf("gagal memindai tujuan pembelajaran sumber: %w", err)
+		}
+		if i, ok := indeks[materiID]; ok {
+			materi[i].Tujuan = append(materi[i].Tujuan, t)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	query = `
+		SELECT DISTINCT mp.pengajar_kelas_id
+		FROM materi_pembelajaran mp
+		JOIN pengajar_kelas pk ON mp.pengajar_kelas_id = pk.id
+		JOIN kelas k ON pk.kelas_id = k.id
+		WHERE k.tahun_ajaran_id = $1
+	`
+	rows, err = tx.QueryContext(ctx, query, targetID)
+	if err != nil {
+		return fmt.Errorf("gagal mengambil materi tujuan: %w", err)
+	}
+	sudahAda := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("gagal memindai materi tujuan: %w", err)
+		}
+		sudahAda[id] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	materiQuery := `
+		INSERT INTO materi_pembelajaran (pengajar_kelas_id, nama_materi, deskripsi, urutan)
+		VALUES ($1, $2, $3, $4) RETURNING id
+	`
+	tujuanQuery := "INSERT INTO tujuan_pembelajaran (materi_pembelajaran_id, deskripsi_tujuan, urutan) VALUES ($1, $2, $3)"
+	for _, m := range materi {
+		pengajarID, ok := pengajarMap[m.PengajarKelasID]
+		if !ok {
+			continue
+		}
+		if sudahAda[pengajarID] {
+			hasil.Materi.Dilewati++
+			hasil.Tujuan.Dilewati += len(m.Tujuan)
+			continue
+		}
+		var materiID int
+		if err := tx.QueryRowContext(ctx, materiQuery, pengajarID, m.NamaMateri, m.Deskripsi, m.Urutan).Scan(&materiID); err != nil {
+			return fmt.Errorf("gagal menyalin materi %s: %w", m.NamaMateri, err)
+		}
+		hasil.Materi.Dibuat++
+		for _, t := range m.Tujuan {
+			if _, err := tx.ExecContext(ctx, tujuanQuery, materiID, t.DeskripsiTujuan, t.Urutan); err != nil {
+				return fmt.Errorf("gagal menyalin tujuan pembelajaran: %w", err)
+			}
+			hasil.Tujuan.Dibuat++
+		}
+	}
+	return nil
+}
+
+func cloneKurikulum(ctx context.Context, tx *sql.Tx, sourceID string, targetID string, hasil *HasilClone) error {
+	query := `
+		INSERT INTO tahun_ajaran_kurikulum (tahun_ajaran_id, kurikulum_id)
+		SELECT $2, kurikulum_id FROM tahun_ajaran_kurikulum WHERE tahun_ajaran_id = $1
+		ON CONFLICT DO NOTHING
+	`
+	ringkasan, err := salinSemua(ctx, tx, query, "SELECT COUNT(*) FROM tahun_ajaran_kurikulum WHERE tahun_ajaran_id = $1", sourceID, targetID)
+	if err != nil {
+		return fmt.Errorf("gagal menyalin kurikulum: %w", err)
+	}
+	hasil.Kurikulum = ringkasan
+
+	query = `
+		INSERT INTO pemetaan_kurikulum (tahun_ajaran_id, kurikulum_id, tingkatan_id, fase_id)
+		SELECT $2, kurikulum_id, tingkatan_id, fase_id FROM pemetaan_kurikulum WHERE tahun_ajaran_id = $1
+		ON CONFLICT DO NOTHING
+	`
+	ringkasan, err = salinSemua(ctx, tx, query, "SELECT COUNT(*) FROM pemetaan_kurikulum WHERE tahun_ajaran_id = $1", sourceID, targetID)
+	if err != nil {
+		return fmt.Errorf("gagal menyalin pemetaan kurikulum: %w", err)
+	}
+	hasil.Pemetaan = ringkasan
+	return nil
+}
+
+// salinSemua menjalankan INSERT ... SELECT dan menghitung baris sumber yang dilewati.
+func salinSemua(ctx context.Context, tx *sql.Tx, insertQuery string, countQuery string, sourceID string, targetID string) (RingkasanClone, error) {
+	var total int
+	if err := tx.QueryRowContext(ctx, countQuery, sourceID).Scan(&total); err != nil {
+		return RingkasanClone{}, err
+	}
+	res, err := tx.ExecContext(ctx, insertQuery, sourceID, targetID)
+	if err != nil {
+		return RingkasanClone{}, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return RingkasanClone{}, err
+	}
+	return RingkasanClone{Dibuat: int(n), Dilewati: total - int(n)}, nil
+}
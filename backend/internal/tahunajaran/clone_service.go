@@ -0,0 +1,49 @@
+// file: backend/internal/tahunajaran/clone_service.go
+package tahunajaran
+
+import (
+	"context"
+	"fmt"
+	"skoola/internal/audit"
+
+	"github.com/google/uuid"
+)
+
+// CloneService mendefinisikan logika penyalinan struktur dari tahun ajaran sebelumnya.
+type CloneService interface {
+	CloneFrom(ctx context.Context, schemaName string, targetID string, sourceID string, input CloneInput) (*HasilClone, error)
+}
+
+type cloneService struct {
+	repo  CloneRepository
+	audit audit.Recorder
+}
+
+// NewCloneService membuat instance baru dari service penyalinan tahun ajaran.
+func NewCloneService(repo CloneRepository, auditLog audit.Recorder) CloneService {
+	return &cloneService{repo: repo, audit: auditLog}
+}
+
+func (s *cloneService) CloneFrom(ctx context.Context, schemaName string, targetID string, sourceID string, input CloneInput) (*HasilClone, error) {
+	if _, err := uuid.Parse(targetID); err != nil {
+		return nil, fmt.Errorf("%w: tahun ajaran tujuan tidak valid", ErrValidation)
+	}
+	if _, err := uuid.Parse(sourceID); err != nil {
+		return nil, fmt.Errorf("%w: tahun ajaran sumber tidak valid", ErrValidation)
+	}
+	if targetID == sourceID {
+		return nil, fmt.Errorf("%w: tahun ajaran sumber dan tujuan harus berbeda", ErrValidation)
+	}
+	if !input.Kelas && !input.Pengajar && !input.Kurikulum && !input.Ekstrakurikuler && !input.RencanaPembelajaran {
+		return nil, fmt.Errorf("%w: pilih minimal satu bagian yang akan disalin", ErrValidation)
+	}
+
+	hasil, err := s.repo.Clone(ctx, schemaName, sourceID, targetID, input)
+	if err != nil {
+		return nil, err
+	}
+	if !hasil.DryRun {
+		s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionCreate, Entity: "tahun_ajaran_clone", EntityID: targetID, After: map[string]interface{}{"sumber_id": sourceID, "opsi": input, "hasil": hasil}})
+	}
+	return hasil, nil
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"skoola/pkg/database"
 )
 
 // Repository mendefinisikan interface untuk interaksi database.
@@ -30,21 +31,22 @@ func NewRepository(db *sql.DB) Repository {
 
 // --- IMPLEMENTASI FUNGSI BARU ---
 func (r *postgresRepository) GetActiveTahunAjaranID(ctx context.Context, schemaName string) (string, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return "", fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return "", err
 	}
+	defer tx.Rollback()
 
 	var id string
 	query := `SELECT id FROM tahun_ajaran WHERE status = 'Aktif' LIMIT 1`
-	err := r.db.QueryRowContext(ctx, query).Scan(&id)
+	err = tx.QueryRowContext(ctx, query).Scan(&id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return "", nil // Tidak ada yang aktif, bukan error
 		}
 		return "", err
 	}
-	return id, nil
+	return id, tx.Commit()
 }
 
 const selectQuery = `
@@ -57,32 +59,37 @@ const selectQuery = `
 `
 
 func (r *postgresRepository) Create(ctx context.Context, schemaName string, ta *TahunAjaran) (*TahunAjaran, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `
         INSERT INTO tahun_ajaran (id, nama_tahun_ajaran, semester, status, metode_absensi, kepala_sekolah_id) 
         VALUES ($1, $2, $3, $4, $5, $6)
         RETURNING id
     `
-	err := r.db.QueryRowContext(ctx, query, ta.ID, ta.NamaTahunAjaran, ta.Semester, ta.Status, ta.MetodeAbsensi, ta.KepalaSekolahID).Scan(&ta.ID)
+	err = tx.QueryRowContext(ctx, query, ta.ID, ta.NamaTahunAjaran, ta.Semester, ta.Status, ta.MetodeAbsensi, ta.KepalaSekolahID).Scan(&ta.ID)
 	if err != nil {
 		return nil, fmt.Errorf("gagal insert tahun ajaran: %w", err)
 	}
 
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 	return r.GetByID(ctx, schemaName, ta.ID)
 }
 
 func (r *postgresRepository) GetAll(ctx context.Context, schemaName string) ([]TahunAjaran, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := selectQuery + " ORDER BY ta.nama_tahun_ajaran DESC, ta.semester DESC"
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := tx.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("gagal query get all tahun ajaran: %w", err)
 	}
@@ -101,41 +108,46 @@ func (r *postgresRepository) GetAll(ctx context.Context, schemaName string) ([]T
 		}
 		list = append(list, t)
 	}
-	return list, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
 }
 
 func (r *postgresRepository) GetByID(ctx context.Context, schemaName string, id string) (*TahunAjaran, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := selectQuery + " WHERE ta.id = $1"
-	row := r.db.QueryRowContext(ctx, query, id)
+	row := tx.QueryRowContext(ctx, query, id)
 
 	var t TahunAjaran
-	err := row.Scan(&t.ID, &t.NamaTahunAjaran, &t.Semester, &t.Status, &t.MetodeAbsensi, &t.KepalaSekolahID, &t.NamaKepalaSekolah, &t.CreatedAt, &t.UpdatedAt)
+	err = row.Scan(&t.ID, &t.NamaTahunAjaran, &t.Semester, &t.Status, &t.MetodeAbsensi, &t.KepalaSekolahID, &t.NamaKepalaSekolah, &t.CreatedAt, &t.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
 		return nil, fmt.Errorf("gagal memindai data tahun ajaran by id: %w", err)
 	}
-	return &t, nil
+	return &t, tx.Commit()
 }
 
 func (r *postgresRepository) Update(ctx context.Context, schemaName string, ta *TahunAjaran) error {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 
 	query := `
         UPDATE tahun_ajaran SET 
             nama_tahun_ajaran = $1, semester = $2, status = $3, metode_absensi = $4, kepala_sekolah_id = $5, updated_at = NOW() 
         WHERE id = $6
     `
-	result, err := r.db.ExecContext(ctx, query, ta.NamaTahunAjaran, ta.Semester, ta.Status, ta.MetodeAbsensi, ta.KepalaSekolahID, ta.ID)
+	result, err := tx.ExecContext(ctx, query, ta.NamaTahunAjaran, ta.Semester, ta.Status, ta.MetodeAbsensi, ta.KepalaSekolahID, ta.ID)
 	if err != nil {
 		return fmt.Errorf("gagal mengeksekusi query update: %w", err)
 	}
@@ -143,17 +155,18 @@ func (r *postgresRepository) Update(ctx context.Context, schemaName string, ta *
 	if rowsAffected == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+	return tx.Commit()
 }
 
 func (r *postgresRepository) Delete(ctx context.Context, schemaName string, id string) error {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 
 	query := `DELETE FROM tahun_ajaran WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := tx.ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("gagal mengeksekusi query delete: %w", err)
 	}
@@ -161,14 +174,13 @@ func (r *postgresRepository) Delete(ctx context.Context, schemaName string, id s
 	if rowsAffected == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+	return tx.Commit()
 }
 
 // DeactivateAllOthers menonaktifkan semua tahun ajaran lain jika ada yang di-set menjadi 'Aktif'.
 func (r *postgresRepository) DeactivateAllOthers(ctx context.Context, tx *sql.Tx, schemaName string, currentID string) error {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := tx.ExecContext(ctx, setSchemaQuery); err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	if err := database.SetTenant(ctx, tx, schemaName); err != nil {
+		return err
 	}
 
 	query := `UPDATE tahun_ajaran SET status = 'Tidak Aktif' WHERE id != $1 AND status = 'Aktif'`
@@ -332,3 +332,60 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func (h *Handler) GetJabatan(w http.ResponseWriter, r *http.Request) {
+	schemaName, ok := r.Context().Value(middleware.SchemaNameKey).(string)
+	if !ok || schemaName == "" {
+		http.Error(w, "Gagal mengidentifikasi tenant dari token", http.StatusUnauthorized)
+		return
+	}
+	teacherID := chi.URLParam(r, "teacherID")
+	if teacherID == "" {
+		http.Error(w, "ID guru tidak boleh kosong", http.StatusBadRequest)
+		return
+	}
+
+	ids, err := h.service.GetJabatan(r.Context(), schemaName, teacherID)
+	if err != nil {
+		http.Error(w, "Gagal mengambil data jabatan guru: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string][]int{"jabatan_ids": ids})
+}
+
+func (h *Handler) SetJabatan(w http.ResponseWriter, r *http.Request) {
+	schemaName, ok := r.Context().Value(middleware.SchemaNameKey).(string)
+	if !ok || schemaName == "" {
+		http.Error(w, "Gagal mengidentifikasi tenant dari token", http.StatusUnauthorized)
+		return
+	}
+	teacherID := chi.URLParam(r, "teacherID")
+	if teacherID == "" {
+		http.Error(w, "ID guru tidak boleh kosong", http.StatusBadRequest)
+		return
+	}
+
+	var input SetJabatanInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	err := h.service.SetJabatan(r.Context(), schemaName, teacherID, input)
+	if err != nil {
+		if errors.Is(err, ErrValidation) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Guru tidak ditemukan", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Gagal menyimpan jabatan guru: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -6,31 +6,30 @@ import (
 	"database/sql"
 	"fmt"
 	"skoola/internal/rombel"
+	"skoola/pkg/database"
 )
 
-type Querier interface {
-	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
-	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
-	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
-}
-
 type Repository interface {
-	Create(ctx context.Context, querier Querier, schemaName string, user *User, teacher *Teacher) error
+	Create(ctx context.Context, tx *sql.Tx, schemaName string, user *User, teacher *Teacher) error
 	GetAll(ctx context.Context, schemaName string) ([]Teacher, error)
 	GetByID(ctx context.Context, schemaName string, id string) (*Teacher, error)
 	Update(ctx context.Context, schemaName string, teacher *Teacher) error
 	Delete(ctx context.Context, schemaName string, teacherID string) error
 	GetByEmail(ctx context.Context, schemaName string, email string) (*User, error)
+	GetUserByID(ctx context.Context, schemaName string, userID string) (*User, error)
 	GetPublicUserByEmail(ctx context.Context, email string) (*User, error)
 	GetAdminBySchema(ctx context.Context, schemaName string) (*User, error)
 	GetTeacherByUserID(ctx context.Context, schemaName string, userID string) (*Teacher, error)
 	UpdateUserEmail(ctx context.Context, schemaName string, userID string, newEmail string) error
 	UpdateUserPassword(ctx context.Context, schemaName string, userID string, hashedPassword string) error
 	GetHistoryByTeacherID(ctx context.Context, schemaName string, teacherID string) ([]RiwayatKepegawaian, error)
+	GetHistoryByID(ctx context.Context, schemaName string, historyID string) (*RiwayatKepegawaian, error)
 	CreateHistory(ctx context.Context, schemaName string, history *RiwayatKepegawaian) error
 	UpdateHistory(ctx context.Context, schemaName string, history *RiwayatKepegawaian) error
 	DeleteHistory(ctx context.Context, schemaName string, historyID string) error
 	GetKelasByTeacherID(ctx context.Context, schemaName string, teacherID string, tahunAjaranID string) ([]rombel.Kelas, error)
+	GetJabatanIDs(ctx context.Context, schemaName string, teacherID string) ([]int, error)
+	SetJabatan(ctx context.Context, schemaName string, teacherID string, jabatanIDs []int) error
 }
 
 type postgresRepository struct {
@@ -45,12 +44,14 @@ func NewRepository(db *sql.DB) Repository {
 
 // --- FUNGSI UPDATE: MENGGUNAKAN STRING_AGG UNTUK MENGGABUNGKAN MAPEL ---
 func (r *postgresRepository) GetKelasByTeacherID(ctx context.Context, schemaName string, teacherID string, tahunAjaranID string) ([]rombel.Kelas, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
-	// Query diperbarui: Menggabungkan nama mapel dengan STRING_AGG dan GROUP BY kelas
+	// Query diperbarui: Menggabungkan nama mapel dengan STRING_AGG dan GROUP BY kelas.
+	// Kelas yang diwalikan ikut ditampilkan walaupun guru tidak mengajar di sana.
 	query := `
 		SELECT
 			k.id, k.nama_kelas, k.tahun_ajaran_id, k.tingkatan_id, k.wali_kelas_id,
@@ -63,16 +64,16 @@ func (r *postgresRepository) GetKelasByTeacherID(ctx context.Context, schemaName
 			(SELECT COUNT(DISTINCT pk.teacher_id) FROM pengajar_kelas pk WHERE pk.kelas_id = k.id) as jumlah_pengajar,
 			STRING_AGG(DISTINCT mp.nama_mapel, ', ') as mata_pelajaran_diajar
 		FROM kelas k
-		JOIN pengajar_kelas pk ON k.id = pk.kelas_id
-		JOIN mata_pelajaran mp ON pk.mata_pelajaran_id = mp.id
+		LEFT JOIN pengajar_kelas pk ON k.id = pk.kelas_id AND pk.teacher_id = $1
+		LEFT JOIN mata_pelajaran mp ON pk.mata_pelajaran_id = mp.id
 		LEFT JOIN tingkatan t ON k.tingkatan_id = t.id
 		LEFT JOIN teachers guru ON k.wali_kelas_id = guru.id
 		LEFT JOIN tahun_ajaran ta ON k.tahun_ajaran_id = ta.id
-		WHERE pk.teacher_id = $1 AND k.tahun_ajaran_id = $2
+		WHERE (pk.teacher_id = $1 OR k.wali_kelas_id = $1) AND k.tahun_ajaran_id = $2
 		GROUP BY k.id, t.id, guru.id, ta.id
 		ORDER BY t.urutan, k.nama_kelas
 	`
-	rows, err := r.db.QueryContext(ctx, query, teacherID, tahunAjaranID)
+	rows, err := tx.QueryContext(ctx, query, teacherID, tahunAjaranID)
 	if err != nil {
 		return nil, err
 	}
@@ -95,21 +96,22 @@ func (r *postgresRepository) GetKelasByTeacherID(ctx context.Context, schemaName
 		}
 		list = append(list, k)
 	}
-	return list, nil
+	return list, tx.Commit()
 }
 
 func (r *postgresRepository) UpdateHistory(ctx context.Context, schemaName string, history *RiwayatKepegawaian) error {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 
 	query := `
 		UPDATE riwayat_kepegawaian
 		SET status = $1, tanggal_mulai = $2, tanggal_selesai = $3, keterangan = $4, updated_at = NOW()
 		WHERE id = $5
 	`
-	result, err := r.db.ExecContext(ctx, query,
+	result, err := tx.ExecContext(ctx, query,
 		history.Status,
 		history.TanggalMulai,
 		history.TanggalSelesai,
@@ -126,17 +128,18 @@ func (r *postgresRepository) UpdateHistory(ctx context.Context, schemaName strin
 	if rowsAffected == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+	return tx.Commit()
 }
 
 func (r *postgresRepository) DeleteHistory(ctx context.Context, schemaName string, historyID string) error {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 
 	query := `DELETE FROM riwayat_kepegawaian WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, historyID)
+	result, err := tx.ExecContext(ctx, query, historyID)
 	if err != nil {
 		return fmt.Errorf("gagal mengeksekusi query delete history: %w", err)
 	}
@@ -147,19 +150,20 @@ func (r *postgresRepository) DeleteHistory(ctx context.Context, schemaName strin
 	if rowsAffected == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+	return tx.Commit()
 }
 
 func (r *postgresRepository) CreateHistory(ctx context.Context, schemaName string, history *RiwayatKepegawaian) error {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 	query := `
 		INSERT INTO riwayat_kepegawaian (id, teacher_id, status, tanggal_mulai, tanggal_selesai, keterangan)
 		VALUES ($1, $2, $3, $4, $5, $6)
 	`
-	_, err := r.db.ExecContext(ctx, query,
+	_, err = tx.ExecContext(ctx, query,
 		history.ID,
 		history.TeacherID,
 		history.Status,
@@ -170,21 +174,22 @@ func (r *postgresRepository) CreateHistory(ctx context.Context, schemaName strin
 	if err != nil {
 		return fmt.Errorf("gagal memasukkan data riwayat: %w", err)
 	}
-	return nil
+	return tx.Commit()
 }
 
 func (r *postgresRepository) GetHistoryByTeacherID(ctx context.Context, schemaName string, teacherID string) ([]RiwayatKepegawaian, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 	query := `
 		SELECT id, teacher_id, status, tanggal_mulai, tanggal_selesai, keterangan, created_at, updated_at
 		FROM riwayat_kepegawaian
 		WHERE teacher_id = $1
 		ORDER BY tanggal_mulai DESC
 	`
-	rows, err := r.db.QueryContext(ctx, query, teacherID)
+	rows, err := tx.QueryContext(ctx, query, teacherID)
 	if err != nil {
 		return nil, fmt.Errorf("gagal query get history by teacher id: %w", err)
 	}
@@ -200,16 +205,37 @@ func (r *postgresRepository) GetHistoryByTeacherID(ctx context.Context, schemaNa
 	if err = rows.Err(); err != nil {
 		return nil, fmt.Errorf("terjadi error saat iterasi baris riwayat: %w", err)
 	}
-	return histories, nil
+	return histories, tx.Commit()
+}
+
+func (r *postgresRepository) GetHistoryByID(ctx context.Context, schemaName string, historyID string) (*RiwayatKepegawaian, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	query := `
+		SELECT id, teacher_id, status, tanggal_mulai, tanggal_selesai, keterangan, created_at, updated_at
+		FROM riwayat_kepegawaian
+		WHERE id = $1
+	`
+	var h RiwayatKepegawaian
+	err = tx.QueryRowContext(ctx, query, historyID).Scan(&h.ID, &h.TeacherID, &h.Status, &h.TanggalMulai, &h.TanggalSelesai, &h.Keterangan, &h.CreatedAt, &h.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("gagal query get history by id: %w", err)
+	}
+	return &h, tx.Commit()
 }
 
-func (r *postgresRepository) Create(ctx context.Context, querier Querier, schemaName string, user *User, teacher *Teacher) error {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := querier.ExecContext(ctx, setSchemaQuery); err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
+func (r *postgresRepository) Create(ctx context.Context, tx *sql.Tx, schemaName string, user *User, teacher *Teacher) error {
+	if err := database.SetTenant(ctx, tx, schemaName); err != nil {
+		return err
 	}
 	userQuery := `INSERT INTO users (id, email, password_hash, role) VALUES ($1, $2, $3, $4)`
-	_, err := querier.ExecContext(ctx, userQuery, user.ID, user.Email, user.PasswordHash, user.Role)
+	_, err := tx.ExecContext(ctx, userQuery, user.ID, user.Email, user.PasswordHash, user.Role)
 	if err != nil {
 		return fmt.Errorf("gagal memasukkan ke tabel users: %w", err)
 	}
@@ -222,7 +248,7 @@ func (r *postgresRepository) Create(ctx context.Context, querier Querier, schema
 		) VALUES (
 			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18
 		)`
-	_, err = querier.ExecContext(ctx, teacherQuery,
+	_, err = tx.ExecContext(ctx, teacherQuery,
 		teacher.ID, user.ID, teacher.NamaLengkap, teacher.NipNuptk, teacher.NoHP, teacher.AlamatLengkap,
 		teacher.NamaPanggilan, teacher.GelarAkademik, teacher.JenisKelamin, teacher.TempatLahir, teacher.TanggalLahir,
 		teacher.Agama, teacher.Kewarganegaraan, teacher.Provinsi, teacher.KotaKabupaten, teacher.Kecamatan, teacher.DesaKelurahan,
@@ -235,7 +261,7 @@ func (r *postgresRepository) Create(ctx context.Context, querier Querier, schema
 		INSERT INTO riwayat_kepegawaian (teacher_id, status, tanggal_mulai)
 		VALUES ($1, 'Aktif', NOW())
 	`
-	_, err = querier.ExecContext(ctx, historyQuery, teacher.ID)
+	_, err = tx.ExecContext(ctx, historyQuery, teacher.ID)
 	if err != nil {
 		return fmt.Errorf("gagal membuat riwayat kepegawaian pertama: %w", err)
 	}
@@ -243,10 +269,11 @@ func (r *postgresRepository) Create(ctx context.Context, querier Querier, schema
 }
 
 func (r *postgresRepository) GetAll(ctx context.Context, schemaName string) ([]Teacher, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `
 		WITH LatestStatus AS (
@@ -284,7 +311,7 @@ func (r *postgresRepository) GetAll(ctx context.Context, schemaName string) ([]T
 		WHERE u.role = 'teacher'
 		ORDER BY t.nama_lengkap ASC
 	`
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := tx.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("gagal mengeksekusi query get all teachers: %w", err)
 	}
@@ -307,14 +334,15 @@ func (r *postgresRepository) GetAll(ctx context.Context, schemaName string) ([]T
 	if err = rows.Err(); err != nil {
 		return nil, fmt.Errorf("terjadi error saat iterasi baris data guru: %w", err)
 	}
-	return teachers, nil
+	return teachers, tx.Commit()
 }
 
 func (r *postgresRepository) getTeacherDetails(ctx context.Context, schemaName string, whereClause string, args ...interface{}) (*Teacher, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
 	baseQuery := `
 		WITH LatestStatus AS (
@@ -352,9 +380,9 @@ func (r *postgresRepository) getTeacherDetails(ctx context.Context, schemaName s
 	`
 
 	finalQuery := baseQuery + " " + whereClause
-	row := r.db.QueryRowContext(ctx, finalQuery, args...)
+	row := tx.QueryRowContext(ctx, finalQuery, args...)
 	var teacher Teacher
-	err := row.Scan(
+	err = row.Scan(
 		&teacher.ID, &teacher.UserID, &teacher.Email, &teacher.NamaLengkap, &teacher.CreatedAt, &teacher.UpdatedAt,
 		&teacher.NipNuptk, &teacher.NoHP, &teacher.AlamatLengkap, &teacher.NamaPanggilan, &teacher.GelarAkademik,
 		&teacher.JenisKelamin, &teacher.TempatLahir, &teacher.TanggalLahir, &teacher.Agama, &teacher.Kewarganegaraan,
@@ -367,7 +395,7 @@ func (r *postgresRepository) getTeacherDetails(ctx context.Context, schemaName s
 		}
 		return nil, fmt.Errorf("gagal memindai detail guru: %w", err)
 	}
-	return &teacher, nil
+	return &teacher, tx.Commit()
 }
 
 func (r *postgresRepository) GetByID(ctx context.Context, schemaName string, id string) (*Teacher, error) {
@@ -379,15 +407,11 @@ func (r *postgresRepository) GetTeacherByUserID(ctx context.Context, schemaName
 }
 
 func (r *postgresRepository) Update(ctx context.Context, schemaName string, teacher *Teacher) error {
-	tx, err := r.db.BeginTx(ctx, nil)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
 	if err != nil {
-		return fmt.Errorf("gagal memulai transaksi: %w", err)
+		return err
 	}
 	defer tx.Rollback()
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := tx.ExecContext(ctx, setSchemaQuery); err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
-	}
 	teacherQuery := `
 		UPDATE teachers
 		SET 
@@ -422,12 +446,13 @@ func (r *postgresRepository) Update(ctx context.Context, schemaName string, teac
 }
 
 func (r *postgresRepository) Delete(ctx context.Context, schemaName string, teacherID string) error {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 	query := `DELETE FROM users WHERE id = (SELECT user_id FROM teachers WHERE id = $1)`
-	result, err := r.db.ExecContext(ctx, query, teacherID)
+	result, err := tx.ExecContext(ctx, query, teacherID)
 	if err != nil {
 		return fmt.Errorf("gagal mengeksekusi query delete: %w", err)
 	}
@@ -438,25 +463,45 @@ func (r *postgresRepository) Delete(ctx context.Context, schemaName string, teac
 	if rowsAffected == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+	return tx.Commit()
 }
 
 func (r *postgresRepository) GetByEmail(ctx context.Context, schemaName string, email string) (*User, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 	query := `SELECT id, email, password_hash, role FROM users WHERE email = $1`
-	row := r.db.QueryRowContext(ctx, query, email)
+	row := tx.QueryRowContext(ctx, query, email)
 	var user User
-	err := row.Scan(&user.ID, &user.Email, &user.PasswordHash, &user.Role)
+	err = row.Scan(&user.ID, &user.Email, &user.PasswordHash, &user.Role)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
 		return nil, fmt.Errorf("gagal memindai data user by email: %w", err)
 	}
-	return &user, nil
+	return &user, tx.Commit()
+}
+
+func (r *postgresRepository) GetUserByID(ctx context.Context, schemaName string, userID string) (*User, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	query := `SELECT id, email, password_hash, role FROM users WHERE id = $1`
+	row := tx.QueryRowContext(ctx, query, userID)
+	var user User
+	err = row.Scan(&user.ID, &user.Email, &user.PasswordHash, &user.Role)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("gagal memindai data user by id: %w", err)
+	}
+	return &user, tx.Commit()
 }
 
 func (r *postgresRepository) GetPublicUserByEmail(ctx context.Context, email string) (*User, error) {
@@ -474,30 +519,32 @@ func (r *postgresRepository) GetPublicUserByEmail(ctx context.Context, email str
 }
 
 func (r *postgresRepository) GetAdminBySchema(ctx context.Context, schemaName string) (*User, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 	query := `SELECT id, email, password_hash, role FROM users WHERE role = 'admin' LIMIT 1`
-	row := r.db.QueryRowContext(ctx, query)
+	row := tx.QueryRowContext(ctx, query)
 	var user User
-	err := row.Scan(&user.ID, &user.Email, &user.PasswordHash, &user.Role)
+	err = row.Scan(&user.ID, &user.Email, &user.PasswordHash, &user.Role)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("tidak ada admin yang ditemukan di skema %s", schemaName)
 		}
 		return nil, fmt.Errorf("gagal memindai data admin: %w", err)
 	}
-	return &user, nil
+	return &user, tx.Commit()
 }
 
 func (r *postgresRepository) UpdateUserEmail(ctx context.Context, schemaName string, userID string, newEmail string) error {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 	query := `UPDATE users SET email = $1, updated_at = NOW() WHERE id = $2`
-	result, err := r.db.ExecContext(ctx, query, newEmail, userID)
+	result, err := tx.ExecContext(ctx, query, newEmail, userID)
 	if err != nil {
 		return fmt.Errorf("gagal mengeksekusi query update email: %w", err)
 	}
@@ -508,16 +555,17 @@ func (r *postgresRepository) UpdateUserEmail(ctx context.Context, schemaName str
 	if rowsAffected == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+	return tx.Commit()
 }
 
 func (r *postgresRepository) UpdateUserPassword(ctx context.Context, schemaName string, userID string, hashedPassword string) error {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 	query := `UPDATE users SET password_hash = $1, updated_at = NOW() WHERE id = $2`
-	result, err := r.db.ExecContext(ctx, query, hashedPassword, userID)
+	result, err := tx.ExecContext(ctx, query, hashedPassword, userID)
 	if err != nil {
 		return fmt.Errorf("gagal mengeksekusi query update password: %w", err)
 	}
@@ -528,5 +576,54 @@ func (r *postgresRepository) UpdateUserPassword(ctx context.Context, schemaName
 	if rowsAffected == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+	return tx.Commit()
+}
+
+// GetJabatanIDs mengambil ID jabatan yang dipegang oleh seorang guru.
+func (r *postgresRepository) GetJabatanIDs(ctx context.Context, schemaName string, teacherID string) ([]int, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT jabatan_id FROM teacher_jabatan WHERE teacher_id = $1 ORDER BY jabatan_id`
+	rows, err := tx.QueryContext(ctx, query, teacherID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query jabatan guru: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("gagal memindai jabatan guru: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return ids, tx.Commit()
+}
+
+// SetJabatan mengganti seluruh jabatan yang dipegang oleh seorang guru.
+func (r *postgresRepository) SetJabatan(ctx context.Context, schemaName string, teacherID string, jabatanIDs []int) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM teacher_jabatan WHERE teacher_id = $1`, teacherID); err != nil {
+		return fmt.Errorf("gagal menghapus jabatan lama guru: %w", err)
+	}
+	for _, jabatanID := range jabatanIDs {
+		query := `INSERT INTO teacher_jabatan (teacher_id, jabatan_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+		if _, err := tx.ExecContext(ctx, query, teacherID, jabatanID); err != nil {
+			return fmt.Errorf("gagal menyimpan jabatan %d untuk guru: %w", jabatanID, err)
+		}
+	}
+	return tx.Commit()
 }
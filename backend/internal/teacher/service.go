@@ -6,6 +6,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"skoola/internal/audit"
 	"skoola/internal/rombel"      // <-- Impor paket rombel
 	"skoola/internal/tahunajaran" // <-- Impor paket tahunajaran
 	"time"
@@ -73,6 +74,12 @@ type UpdateHistoryInput struct {
 	Keterangan     string `json:"keterangan" validate:"omitempty"`
 }
 
+// SetJabatanInput adalah DTO untuk mengatur jabatan yang dipegang guru.
+// Role yang terhubung ke jabatan tersebut otomatis berlaku untuk akun guru.
+type SetJabatanInput struct {
+	JabatanIDs []int `json:"jabatan_ids" validate:"omitempty,dive,gt=0"`
+}
+
 type Service interface {
 	Create(ctx context.Context, schemaName string, input CreateTeacherInput) error
 	GetAll(ctx context.Context, schemaName string) ([]Teacher, error)
@@ -87,19 +94,31 @@ type Service interface {
 	GetMyDetails(ctx context.Context, schemaName string, userID string) (*Teacher, error)
 	// --- FUNGSI BARU ---
 	GetMyKelas(ctx context.Context, schemaName string, userID string) ([]rombel.Kelas, error)
+	GetJabatan(ctx context.Context, schemaName string, teacherID string) ([]int, error)
+	SetJabatan(ctx context.Context, schemaName string, teacherID string, input SetJabatanInput) error
+}
+
+// SessionRevoker mencabut semua sesi login milik seorang user.
+// Diimplementasikan oleh auth.Repository; didefinisikan di sini agar tidak terjadi import cycle.
+type SessionRevoker interface {
+	RevokeAllForUser(ctx context.Context, schemaName string, userID string) error
 }
 
 type service struct {
 	repo            Repository
 	tahunAjaranRepo tahunajaran.Repository // <-- Tambahkan repo tahun ajaran
+	sessions        SessionRevoker
+	audit           audit.Recorder
 	validate        *validator.Validate
 	db              *sql.DB
 }
 
-func NewService(repo Repository, tahunAjaranRepo tahunajaran.Repository, validate *validator.Validate, db *sql.DB) Service {
+func NewService(repo Repository, tahunAjaranRepo tahunajaran.Repository, sessions SessionRevoker, auditLog audit.Recorder, validate *validator.Validate, db *sql.DB) Service {
 	return &service{
 		repo:            repo,
 		tahunAjaranRepo: tahunAjaranRepo, // <-- Tambahkan ini
+		sessions:        sessions,
+		audit:           auditLog,
 		validate:        validate,
 		db:              db,
 	}
@@ -154,18 +173,39 @@ func (s *service) UpdateHistory(ctx context.Context, schemaName string, historyI
 		}
 	}
 
+	before, err := s.repo.GetHistoryByID(ctx, schemaName, historyID)
+	if err != nil {
+		return fmt.Errorf("gagal menemukan riwayat: %w", err)
+	}
+	if before == nil {
+		return sql.ErrNoRows
+	}
+
 	history := &RiwayatKepegawaian{
 		ID:             historyID,
+		TeacherID:      before.TeacherID,
 		Status:         input.Status,
 		TanggalMulai:   startDate,
 		TanggalSelesai: endDate,
 		Keterangan:     stringToPtr(input.Keterangan),
 	}
 
-	return s.repo.UpdateHistory(ctx, schemaName, history)
+	if err := s.repo.UpdateHistory(ctx, schemaName, history); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionUpdate, Entity: "riwayat_kepegawaian", EntityID: historyID, Before: before, After: history})
+	return nil
 }
 func (s *service) DeleteHistory(ctx context.Context, schemaName string, historyID string) error {
-	return s.repo.DeleteHistory(ctx, schemaName, historyID)
+	before, err := s.repo.GetHistoryByID(ctx, schemaName, historyID)
+	if err != nil {
+		return fmt.Errorf("gagal menemukan riwayat: %w", err)
+	}
+	if err := s.repo.DeleteHistory(ctx, schemaName, historyID); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionDelete, Entity: "riwayat_kepegawaian", EntityID: historyID, Before: before})
+	return nil
 }
 func (s *service) CreateHistory(ctx context.Context, schemaName string, teacherID string, input CreateHistoryInput) error {
 	if err := s.validate.Struct(input); err != nil {
@@ -194,7 +234,11 @@ func (s *service) CreateHistory(ctx context.Context, schemaName string, teacherI
 		Keterangan:     stringToPtr(input.Keterangan),
 	}
 
-	return s.repo.CreateHistory(ctx, schemaName, history)
+	if err := s.repo.CreateHistory(ctx, schemaName, history); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionCreate, Entity: "riwayat_kepegawaian", EntityID: history.ID, After: history})
+	return nil
 }
 func (s *service) GetHistoryByTeacherID(ctx context.Context, schemaName string, teacherID string) ([]RiwayatKepegawaian, error) {
 	histories, err := s.repo.GetHistoryByTeacherID(ctx, schemaName, teacherID)
@@ -253,7 +297,12 @@ func (s *service) Create(ctx context.Context, schemaName string, input CreateTea
 	if err != nil {
 		return fmt.Errorf("gagal membuat guru di service: %w", err)
 	}
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	teacher.Email = user.Email
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionCreate, Entity: "teachers", EntityID: teacher.ID, After: teacher})
+	return nil
 }
 func (s *service) Update(ctx context.Context, schemaName string, id string, input UpdateTeacherInput) error {
 	if err := s.validate.Struct(input); err != nil {
@@ -266,6 +315,7 @@ func (s *service) Update(ctx context.Context, schemaName string, id string, inpu
 	if teacher == nil {
 		return sql.ErrNoRows
 	}
+	before := *teacher
 	var dob *time.Time
 	if input.TanggalLahir != "" {
 		parsedDate, err := time.Parse("2006-01-02", input.TanggalLahir)
@@ -294,6 +344,7 @@ func (s *service) Update(ctx context.Context, schemaName string, id string, inpu
 	if err != nil {
 		return fmt.Errorf("gagal mengupdate guru di service: %w", err)
 	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionUpdate, Entity: "teachers", EntityID: id, Before: before, After: teacher})
 	return nil
 }
 func (s *service) GetAdminDetails(ctx context.Context, schemaName string) (*Teacher, error) {
@@ -335,12 +386,46 @@ func (s *service) Delete(ctx context.Context, schemaName string, id string) erro
 	if teacher == nil {
 		return sql.ErrNoRows
 	}
+	if err := s.sessions.RevokeAllForUser(ctx, schemaName, teacher.UserID); err != nil {
+		return fmt.Errorf("gagal mencabut sesi guru: %w", err)
+	}
 	err = s.repo.Delete(ctx, schemaName, id)
 	if err != nil {
 		return fmt.Errorf("gagal menghapus guru di service: %w", err)
 	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionDelete, Entity: "teachers", EntityID: id, Before: teacher})
 	return nil
 }
+func (s *service) GetJabatan(ctx context.Context, schemaName string, teacherID string) ([]int, error) {
+	ids, err := s.repo.GetJabatanIDs(ctx, schemaName, teacherID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil jabatan guru di service: %w", err)
+	}
+	return ids, nil
+}
+
+func (s *service) SetJabatan(ctx context.Context, schemaName string, teacherID string, input SetJabatanInput) error {
+	if err := s.validate.Struct(input); err != nil {
+		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	teacher, err := s.repo.GetByID(ctx, schemaName, teacherID)
+	if err != nil {
+		return fmt.Errorf("gagal mencari guru: %w", err)
+	}
+	if teacher == nil {
+		return sql.ErrNoRows
+	}
+	before, err := s.repo.GetJabatanIDs(ctx, schemaName, teacherID)
+	if err != nil {
+		return fmt.Errorf("gagal mengambil jabatan guru: %w", err)
+	}
+	if err := s.repo.SetJabatan(ctx, schemaName, teacherID, input.JabatanIDs); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionUpdate, Entity: "teacher_jabatan", EntityID: teacherID, Before: before, After: input.JabatanIDs})
+	return nil
+}
+
 func stringToPtr(s string) *string {
 	if s == "" {
 		return nil
@@ -33,7 +33,7 @@ func (h *Handler) GetTenantsWithoutNaungan(w http.ResponseWriter, r *http.Reques
 
 // --- HANDLER BARU UNTUK MENJALANKAN MIGRASI ---
 func (h *Handler) RunMigrations(w http.ResponseWriter, r *http.Request) {
-	count, err := h.service.RunMigrationsForAllTenants(r.Context())
+	report, err := h.service.RunMigrationsForAllTenants(r.Context())
 	if err != nil {
 		http.Error(w, "Gagal menjalankan migrasi: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -41,8 +41,9 @@ func (h *Handler) RunMigrations(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{
-		"message": fmt.Sprintf("Migrasi berhasil dijalankan untuk %d sekolah.", count),
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": fmt.Sprintf("Migrasi berhasil untuk %d sekolah, gagal untuk %d sekolah.", report.SuccessCount, report.FailedCount),
+		"report":  report,
 	})
 }
 
@@ -5,8 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"os"
-	"path/filepath"
+	"skoola/pkg/database"
 )
 
 type Repository interface {
@@ -14,7 +13,7 @@ type Repository interface {
 	GetAll(ctx context.Context) ([]Tenant, error)
 	GetTenantsWithoutNaungan(ctx context.Context) ([]Tenant, error)
 	DeleteTenantBySchema(ctx context.Context, schemaName string) error
-	ApplyMigrationToSchema(ctx context.Context, schemaName string, migrationSQL []byte) error
+	InitSchoolProfile(ctx context.Context, tx *sql.Tx, schemaName string, namaSekolah string) error
 	CheckSchemaExists(ctx context.Context, schemaName string) (bool, error)
 }
 
@@ -89,9 +88,8 @@ func (r *postgresRepository) CreateTenantSchema(ctx context.Context, tx *sql.Tx,
 		return fmt.Errorf("gagal membuat schema baru: %w", err)
 	}
 
-	_, err = tx.ExecContext(ctx, fmt.Sprintf("SET search_path TO %q", input.SchemaName))
-	if err != nil {
-		return fmt.Errorf("gagal mengatur search_path ke schema baru: %w", err)
+	if err := database.SetTenant(ctx, tx, input.SchemaName); err != nil {
+		return err
 	}
 
 	// Create the enum type in public schema first
@@ -105,83 +103,7 @@ func (r *postgresRepository) CreateTenantSchema(ctx context.Context, tx *sql.Tx,
 		return fmt.Errorf("gagal membuat tipe enum status_presensi_enum: %w", err)
 	}
 
-	// Set search path to include both the tenant schema and public
-	_, err = tx.ExecContext(ctx, fmt.Sprintf("SET search_path TO %q, public", input.SchemaName))
-	if err != nil {
-		return fmt.Errorf("gagal mengatur search_path ke schema baru: %w", err)
-	}
-
-	// Set search path back to tenant schema
-	_, err = tx.ExecContext(ctx, fmt.Sprintf("SET search_path TO %q, public", input.SchemaName))
-	if err != nil {
-		return fmt.Errorf("gagal mengatur search_path ke schema baru: %w", err)
-	}
-
-	// Now run tenant-specific migrations
-	migrationPaths := []string{
-		"./db/migrations/001_initial_schema.sql",
-		"./db/migrations/002_add_school_profile.sql",
-		"./db/migrations/003_add_teacher_details.sql",
-		"./db/migrations/004_add_employment_history.sql",
-		"./db/migrations/006_enhance_students_table.sql",
-		"./db/migrations/007_add_academic_history.sql",
-		"./db/migrations/008_add_jenjang_pendidikan.sql",
-		"./db/migrations/009_add_jabatan.sql",
-		"./db/migrations/010_add_tingkatan.sql",
-		"./db/migrations/011_add_tahun_ajaran.sql",
-		"./db/migrations/012_add_mata_pelajaran.sql",
-		"./db/migrations/013_add_kurikulum.sql",
-		"./db/migrations/014_add_rombel.sql",
-		"./db/migrations/015_add_pembelajaran.sql",
-		"./db/migrations/016_add_penilaian.sql",
-		"./db/migrations/017_add_parent_to_mapel.sql",
-		"./db/migrations/018_add_order_to_mapel.sql",
-		"./db/migrations/019_add_mapel_groups.sql",
-		"./db/migrations/020_alter_students_parents_details.sql",
-		"./db/migrations/021_add_order_to_anggota_kelas.sql",
-		"./db/migrations/022_add_jenis_ujian.sql",
-		"./db/migrations/023_add_penilaian_sumatif.sql",
-		"./db/migrations/024_add_nilai_sumatif.sql",
-		"./db/migrations/025_add_ujian.sql",
-		"./db/migrations/026_alter_penilaian_sumatif_for_ujian.sql",
-		"./db/migrations/027_add_presensi.sql",
-		"./db/migrations/028_add_extracurricular.sql",
-		"./db/migrations/029_add_extracurricular_sessions.sql",
-		"./db/migrations/030_add_achievements.sql",
-		"./db/migrations/031_refactor_ujian_schema.sql",
-		"./db/migrations/032_add_peserta_ujian.sql",
-		"./db/migrations/033_add_kelas_id_to_peserta_ujian.sql",
-		"./db/migrations/034_add_exam_rooms.sql",
-		"./db/migrations/035_add_paper_size.sql",
-	}
-
-	// Jalankan migrasi satu per satu
-	for _, path := range migrationPaths {
-		absPath, err := filepath.Abs(path)
-		if err != nil {
-			return fmt.Errorf("gagal mendapatkan path absolut untuk %s: %w", path, err)
-		}
-		migrationSQL, err := os.ReadFile(absPath)
-		if err != nil {
-			return fmt.Errorf("gagal membaca file migrasi %s: %w", path, err)
-		}
-
-		// Jalankan migrasi satu per satu
-		if _, err := tx.ExecContext(ctx, string(migrationSQL)); err != nil {
-			return fmt.Errorf("gagal menjalankan migrasi %s: %w", path, err)
-		}
-	}
-
-	updateNameQuery := `UPDATE profil_sekolah SET nama_sekolah = $1 WHERE id = 1`
-	if _, err := tx.ExecContext(ctx, updateNameQuery, input.NamaSekolah); err != nil {
-		return fmt.Errorf("gagal update nama sekolah di profil: %w", err)
-	}
-
-	_, err = tx.ExecContext(ctx, "SET search_path TO public")
-	if err != nil {
-		return fmt.Errorf("gagal mereset search_path: %w", err)
-	}
-
+	// Tabel-tabel tenant dibuat oleh migration.Service setelah fungsi ini selesai.
 	return nil
 }
 
@@ -234,23 +156,13 @@ func (r *postgresRepository) CheckSchemaExists(ctx context.Context, schemaName s
 	return exists, nil
 }
 
-func (r *postgresRepository) ApplyMigrationToSchema(ctx context.Context, schemaName string, migrationSQL []byte) error {
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("gagal memulai transaksi untuk migrasi: %w", err)
-	}
-	defer tx.Rollback()
-
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := tx.ExecContext(ctx, setSchemaQuery); err != nil {
-		return fmt.Errorf("gagal mengatur search_path untuk migrasi: %w", err)
-	}
-
-	if _, err := tx.ExecContext(ctx, string(migrationSQL)); err != nil {
-		return fmt.Errorf("gagal menjalankan migrasi untuk skema %s: %w", schemaName, err)
+// InitSchoolProfile mengisi nama sekolah pada baris awal profil_sekolah yang dibuat oleh migrasi.
+func (r *postgresRepository) InitSchoolProfile(ctx context.Context, tx *sql.Tx, schemaName string, namaSekolah string) error {
+	query := fmt.Sprintf(`UPDATE %q.profil_sekolah SET nama_sekolah = $1 WHERE id = 1`, schemaName)
+	if _, err := tx.ExecContext(ctx, query, namaSekolah); err != nil {
+		return fmt.Errorf("gagal update nama sekolah di profil: %w", err)
 	}
-
-	return tx.Commit()
+	return nil
 }
 
 func (r *postgresRepository) DeleteTenantBySchema(ctx context.Context, schemaName string) error {
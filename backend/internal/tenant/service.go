@@ -6,8 +6,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
+	"skoola/internal/migration"
 	"skoola/internal/teacher"
 
 	"github.com/go-playground/validator/v10"
@@ -24,20 +23,24 @@ type Service interface {
 	UpdateAdminEmail(ctx context.Context, schemaName string, input UpdateAdminEmailInput) error
 	ResetAdminPassword(ctx context.Context, schemaName string, input ResetAdminPasswordInput) error
 	DeleteTenant(ctx context.Context, schemaName string) error
-	RunMigrationsForAllTenants(ctx context.Context) (int, error)
+	RunMigrationsForAllTenants(ctx context.Context) (*migration.Report, error)
 }
 
 type service struct {
 	repo        Repository
 	teacherRepo teacher.Repository
+	sessions    teacher.SessionRevoker
+	migrator    migration.Service
 	validate    *validator.Validate
 	db          *sql.DB
 }
 
-func NewService(repo Repository, teacherRepo teacher.Repository, validate *validator.Validate, db *sql.DB) Service {
+func NewService(repo Repository, teacherRepo teacher.Repository, sessions teacher.SessionRevoker, migrator migration.Service, validate *validator.Validate, db *sql.DB) Service {
 	return &service{
 		repo:        repo,
 		teacherRepo: teacherRepo,
+		sessions:    sessions,
+		migrator:    migrator,
 		validate:    validate,
 		db:          db,
 	}
@@ -52,37 +55,10 @@ func (s *service) GetTenantsWithoutNaungan(ctx context.Context) ([]Tenant, error
 	return tenants, nil
 }
 
-// --- FUNGSI BARU UNTUK MENJALANKAN MIGRASI KE SEMUA TENANT ---
-func (s *service) RunMigrationsForAllTenants(ctx context.Context) (int, error) {
-	// 1. Baca file migrasi baru
-	migrationPath, err := filepath.Abs("./db/migrations/002_add_school_profile.sql")
-	if err != nil {
-		return 0, fmt.Errorf("gagal mendapatkan path file migrasi: %w", err)
-	}
-	migrationSQL, err := os.ReadFile(migrationPath)
-	if err != nil {
-		return 0, fmt.Errorf("gagal membaca file migrasi 002: %w", err)
-	}
-
-	// 2. Dapatkan semua tenant yang terdaftar
-	tenants, err := s.repo.GetAll(ctx)
-	if err != nil {
-		return 0, fmt.Errorf("gagal mendapatkan daftar tenant: %w", err)
-	}
-
-	// 3. Looping dan jalankan migrasi untuk setiap tenant
-	migratedCount := 0
-	for _, tenant := range tenants {
-		err := s.repo.ApplyMigrationToSchema(ctx, tenant.SchemaName, migrationSQL)
-		if err != nil {
-			// Jika gagal di satu tenant, kita bisa memilih untuk berhenti atau lanjut.
-			// Untuk sekarang, kita kembalikan error dan berhenti.
-			return migratedCount, fmt.Errorf("gagal migrasi untuk sekolah %s: %w", tenant.NamaSekolah, err)
-		}
-		migratedCount++
-	}
-
-	return migratedCount, nil
+// RunMigrationsForAllTenants menjalankan semua migrasi tenant yang belum tercatat
+// pada setiap sekolah dan mengembalikan hasil per sekolah.
+func (s *service) RunMigrationsForAllTenants(ctx context.Context) (*migration.Report, error) {
+	return s.migrator.RunAllTenants(ctx)
 }
 
 // --- FUNGSI-FUNGSI LAMA DI BAWAH INI TETAP SAMA ---
@@ -114,7 +90,14 @@ func (s *service) ResetAdminPassword(ctx context.Context, schemaName string, inp
 	if err != nil {
 		return fmt.Errorf("gagal melakukan hash password baru: %w", err)
 	}
-	return s.teacherRepo.UpdateUserPassword(ctx, schemaName, admin.ID, string(hashedPassword))
+	if err := s.teacherRepo.UpdateUserPassword(ctx, schemaName, admin.ID, string(hashedPassword)); err != nil {
+		return err
+	}
+	// Paksa admin login ulang dengan password baru di semua perangkat.
+	if err := s.sessions.RevokeAllForUser(ctx, schemaName, admin.ID); err != nil {
+		return fmt.Errorf("gagal mencabut sesi admin: %w", err)
+	}
+	return nil
 }
 
 func (s *service) GetAll(ctx context.Context) ([]Tenant, error) {
@@ -140,6 +123,14 @@ func (s *service) Register(ctx context.Context, input RegisterTenantInput) error
 		return err
 	}
 
+	if _, err := s.migrator.MigrateTenantTx(ctx, tx, input.SchemaName); err != nil {
+		return fmt.Errorf("gagal menjalankan migrasi untuk sekolah baru: %w", err)
+	}
+
+	if err := s.repo.InitSchoolProfile(ctx, tx, input.SchemaName, input.NamaSekolah); err != nil {
+		return err
+	}
+
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.AdminPass), 10)
 	if err != nil {
 		return fmt.Errorf("gagal melakukan hash password admin: %w", err)
@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"skoola/pkg/database"
 )
 
 // Repository mendefinisikan interface untuk interaksi database.
@@ -26,29 +27,31 @@ func NewRepository(db *sql.DB) Repository {
 }
 
 func (r *postgresRepository) Create(ctx context.Context, schemaName string, input UpsertTingkatanInput) (*Tingkatan, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `INSERT INTO tingkatan (nama_tingkatan, urutan) VALUES ($1, $2) RETURNING id, nama_tingkatan, urutan, created_at, updated_at`
-	row := r.db.QueryRowContext(ctx, query, input.NamaTingkatan, input.Urutan)
+	row := tx.QueryRowContext(ctx, query, input.NamaTingkatan, input.Urutan)
 
 	var t Tingkatan
 	if err := row.Scan(&t.ID, &t.NamaTingkatan, &t.Urutan, &t.CreatedAt, &t.UpdatedAt); err != nil {
 		return nil, fmt.Errorf("gagal memindai data tingkatan setelah dibuat: %w", err)
 	}
-	return &t, nil
+	return &t, tx.Commit()
 }
 
 func (r *postgresRepository) GetAll(ctx context.Context, schemaName string) ([]Tingkatan, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `SELECT id, nama_tingkatan, urutan, created_at, updated_at FROM tingkatan ORDER BY urutan ASC, nama_tingkatan ASC`
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := tx.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("gagal query get all tingkatan: %w", err)
 	}
@@ -62,37 +65,42 @@ func (r *postgresRepository) GetAll(ctx context.Context, schemaName string) ([]T
 		}
 		tingkatanList = append(tingkatanList, t)
 	}
-	return tingkatanList, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return tingkatanList, tx.Commit()
 }
 
 func (r *postgresRepository) GetByID(ctx context.Context, schemaName string, id int) (*Tingkatan, error) {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return nil, fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `SELECT id, nama_tingkatan, urutan, created_at, updated_at FROM tingkatan WHERE id = $1`
-	row := r.db.QueryRowContext(ctx, query, id)
+	row := tx.QueryRowContext(ctx, query, id)
 
 	var t Tingkatan
-	err := row.Scan(&t.ID, &t.NamaTingkatan, &t.Urutan, &t.CreatedAt, &t.UpdatedAt)
+	err = row.Scan(&t.ID, &t.NamaTingkatan, &t.Urutan, &t.CreatedAt, &t.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // Data tidak ditemukan, bukan error
 		}
 		return nil, fmt.Errorf("gagal memindai data tingkatan by id: %w", err)
 	}
-	return &t, nil
+	return &t, tx.Commit()
 }
 
 func (r *postgresRepository) Update(ctx context.Context, schemaName string, id int, input UpsertTingkatanInput) error {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 
 	query := `UPDATE tingkatan SET nama_tingkatan = $1, urutan = $2, updated_at = NOW() WHERE id = $3`
-	result, err := r.db.ExecContext(ctx, query, input.NamaTingkatan, input.Urutan, id)
+	result, err := tx.ExecContext(ctx, query, input.NamaTingkatan, input.Urutan, id)
 	if err != nil {
 		return fmt.Errorf("gagal mengeksekusi query update: %w", err)
 	}
@@ -104,17 +112,18 @@ func (r *postgresRepository) Update(ctx context.Context, schemaName string, id i
 	if rowsAffected == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+	return tx.Commit()
 }
 
 func (r *postgresRepository) Delete(ctx context.Context, schemaName string, id int) error {
-	setSchemaQuery := fmt.Sprintf("SET search_path TO %q", schemaName)
-	if _, err := r.db.ExecContext(ctx, setSchemaQuery); err != nil {
-		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 
 	query := `DELETE FROM tingkatan WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := tx.ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("gagal mengeksekusi query delete: %w", err)
 	}
@@ -126,5 +135,5 @@ func (r *postgresRepository) Delete(ctx context.Context, schemaName string, id i
 	if rowsAffected == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+	return tx.Commit()
 }
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"skoola/pkg/database"
 	"time"
 
 	"github.com/google/uuid"
@@ -64,15 +65,12 @@ func NewRepository(db *sql.DB) Repository {
 	return &repository{db: db}
 }
 
-func (r *repository) setSchema(ctx context.Context, schemaName string) error {
-	_, err := r.db.ExecContext(ctx, fmt.Sprintf("SET search_path TO %q", schemaName))
-	return err
-}
-
 func (r *repository) Create(ctx context.Context, schemaName string, um UjianMaster) (UjianMaster, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return UjianMaster{}, err
 	}
+	defer tx.Rollback()
 	um.ID = uuid.New()
 	um.CreatedAt = time.Now()
 	um.UpdatedAt = time.Now()
@@ -81,17 +79,19 @@ func (r *repository) Create(ctx context.Context, schemaName string, um UjianMast
         INSERT INTO ujian_master (id, nama_paket_ujian, tahun_ajaran_id, created_at, updated_at)
         VALUES ($1, $2, $3, $4, $5)
     `
-	_, err := r.db.ExecContext(ctx, query, um.ID, um.NamaPaketUjian, um.TahunAjaranID, um.CreatedAt, um.UpdatedAt)
+	_, err = tx.ExecContext(ctx, query, um.ID, um.NamaPaketUjian, um.TahunAjaranID, um.CreatedAt, um.UpdatedAt)
 	if err != nil {
 		return UjianMaster{}, fmt.Errorf("gagal membuat paket ujian: %w", err)
 	}
-	return um, nil
+	return um, tx.Commit()
 }
 
 func (r *repository) GetAllByTahunAjaran(ctx context.Context, schemaName string, tahunAjaranID uuid.UUID) ([]UjianMaster, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 	var results []UjianMaster
 	query := `
         SELECT
@@ -104,7 +104,7 @@ func (r *repository) GetAllByTahunAjaran(ctx context.Context, schemaName string,
         WHERE tahun_ajaran_id = $1
         ORDER BY created_at DESC
     `
-	rows, err := r.db.QueryContext(ctx, query, tahunAjaranID)
+	rows, err := tx.QueryContext(ctx, query, tahunAjaranID)
 	if err != nil {
 		return nil, fmt.Errorf("gagal menjalankan query: %w", err)
 	}
@@ -124,13 +124,15 @@ func (r *repository) GetAllByTahunAjaran(ctx context.Context, schemaName string,
 		return nil, fmt.Errorf("error pada baris hasil: %w", err)
 	}
 
-	return results, nil
+	return results, tx.Commit()
 }
 
 func (r *repository) GetByID(ctx context.Context, schemaName string, id uuid.UUID) (UjianMaster, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return UjianMaster{}, err
 	}
+	defer tx.Rollback()
 	var um UjianMaster
 	query := `
         SELECT
@@ -139,7 +141,7 @@ func (r *repository) GetByID(ctx context.Context, schemaName string, id uuid.UUI
         FROM ujian_master
         WHERE id = $1
     `
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err = tx.QueryRowContext(ctx, query, id).Scan(
 		&um.ID, &um.NamaPaketUjian, &um.TahunAjaranID,
 		&um.CreatedAt, &um.UpdatedAt,
 	)
@@ -150,32 +152,36 @@ func (r *repository) GetByID(ctx context.Context, schemaName string, id uuid.UUI
 		}
 		return UjianMaster{}, fmt.Errorf("gagal mengambil paket ujian: %w", err)
 	}
-	return um, nil
+	return um, tx.Commit()
 }
 
 func (r *repository) Update(ctx context.Context, schemaName string, um UjianMaster) (UjianMaster, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return UjianMaster{}, err
 	}
+	defer tx.Rollback()
 	um.UpdatedAt = time.Now()
 	query := `
         UPDATE ujian_master SET
             nama_paket_ujian = $2, updated_at = $3
         WHERE id = $1
     `
-	_, err := r.db.ExecContext(ctx, query, um.ID, um.NamaPaketUjian, um.UpdatedAt)
+	_, err = tx.ExecContext(ctx, query, um.ID, um.NamaPaketUjian, um.UpdatedAt)
 	if err != nil {
 		return UjianMaster{}, fmt.Errorf("gagal memperbarui paket ujian: %w", err)
 	}
-	return um, nil
+	return um, tx.Commit()
 }
 
 func (r *repository) Delete(ctx context.Context, schemaName string, id uuid.UUID) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 	query := "DELETE FROM ujian_master WHERE id = $1"
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := tx.ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("gagal menghapus paket ujian: %w", err)
 	}
@@ -189,13 +195,15 @@ func (r *repository) Delete(ctx context.Context, schemaName string, id uuid.UUID
 		return errors.New("paket ujian tidak ditemukan untuk dihapus")
 	}
 
-	return nil
+	return tx.Commit()
 }
 
 func (r *repository) GetPenugasanByUjianMasterID(ctx context.Context, schemaName string, id uuid.UUID) ([]PenugasanUjian, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 	query := `
         SELECT
             pk.id as pengajar_kelas_id,
@@ -211,7 +219,7 @@ func (r *repository) GetPenugasanByUjianMasterID(ctx context.Context, schemaName
         WHERE u.ujian_master_id = $1
         ORDER BY k.nama_kelas, mp.nama_mapel
     `
-	rows, err := r.db.QueryContext(ctx, query, id)
+	rows, err := tx.QueryContext(ctx, query, id)
 	if err != nil {
 		return nil, err
 	}
@@ -225,13 +233,15 @@ func (r *repository) GetPenugasanByUjianMasterID(ctx context.Context, schemaName
 		}
 		penugasan = append(penugasan, p)
 	}
-	return penugasan, nil
+	return penugasan, tx.Commit()
 }
 
 func (r *repository) GetAvailableKelasForUjian(ctx context.Context, schemaName string, tahunAjaranID uuid.UUID, ujianMasterID uuid.UUID) ([]AvailableKelas, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 	query := `
         SELECT
             k.id as kelas_id,
@@ -249,7 +259,7 @@ func (r *repository) GetAvailableKelasForUjian(ctx context.Context, schemaName s
         ORDER BY k.nama_kelas, mp.nama_mapel
     `
 
-	rows, err := r.db.QueryContext(ctx, query, tahunAjaranID, ujianMasterID)
+	rows, err := tx.QueryContext(ctx, query, tahunAjaranID, ujianMasterID)
 	if err != nil {
 		return nil, err
 	}
@@ -276,14 +286,11 @@ func (r *repository) GetAvailableKelasForUjian(ctx context.Context, schemaName s
 		results = append(results, *kelasMap[kelasID])
 	}
 
-	return results, nil
+	return results, tx.Commit()
 }
 
 func (r *repository) AssignKelasToUjian(ctx context.Context, schemaName string, ujianMasterID uuid.UUID, pengajarKelasIDs []string) (int, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
-		return 0, err
-	}
-	tx, err := r.db.BeginTx(ctx, nil)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
 	if err != nil {
 		return 0, err
 	}
@@ -315,13 +322,9 @@ func (r *repository) AssignKelasToUjian(ctx context.Context, schemaName string,
 }
 
 func (r *repository) CreatePesertaUjianBatch(ctx context.Context, schemaName string, peserta []PesertaUjian) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
-		return err
-	}
-
-	tx, err := r.db.BeginTx(ctx, nil)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
 	if err != nil {
-		return fmt.Errorf("gagal memulai transaksi: %w", err)
+		return err
 	}
 	defer tx.Rollback()
 
@@ -349,9 +352,11 @@ func (r *repository) CreatePesertaUjianBatch(ctx context.Context, schemaName str
 }
 
 func (r *repository) FindPesertaByUjianID(ctx context.Context, schemaName string, ujianID uuid.UUID) ([]PesertaUjianDetail, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `
         SELECT
@@ -374,7 +379,7 @@ func (r *repository) FindPesertaByUjianID(ctx context.Context, schemaName string
         ORDER BY k.nama_kelas, pu.urutan
     `
 
-	rows, err := r.db.QueryContext(ctx, query, ujianID)
+	rows, err := tx.QueryContext(ctx, query, ujianID)
 	if err != nil {
 		return nil, fmt.Errorf("gagal query peserta ujian: %w", err)
 	}
@@ -426,7 +431,7 @@ func (r *repository) FindPesertaByUjianID(ctx context.Context, schemaName string
 		return nil, fmt.Errorf("error pada baris hasil peserta: %w", err)
 	}
 
-	return results, nil
+	return results, tx.Commit()
 }
 
 func (r *repository) FindPesertaDetailByUjianIDWithSeating(ctx context.Context, schemaName string, ujianID uuid.UUID) ([]PesertaUjianDetail, error) {
@@ -434,9 +439,11 @@ func (r *repository) FindPesertaDetailByUjianIDWithSeating(ctx context.Context,
 }
 
 func (r *repository) FindAllPesertaByUjianID(ctx context.Context, schemaName string, ujianMasterID uuid.UUID) ([]PesertaUjian, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `
         SELECT 
@@ -445,7 +452,7 @@ func (r *repository) FindAllPesertaByUjianID(ctx context.Context, schemaName str
         WHERE ujian_master_id = $1
         ORDER BY kelas_id, urutan
     `
-	rows, err := r.db.QueryContext(ctx, query, ujianMasterID)
+	rows, err := tx.QueryContext(ctx, query, ujianMasterID)
 	if err != nil {
 		return nil, fmt.Errorf("gagal query semua peserta ujian: %w", err)
 	}
@@ -472,13 +479,15 @@ func (r *repository) FindAllPesertaByUjianID(ctx context.Context, schemaName str
 		return nil, fmt.Errorf("error pada baris hasil PesertaUjian: %w", err)
 	}
 
-	return results, nil
+	return results, tx.Commit()
 }
 
 func (r *repository) UpdatePesertaSeating(ctx context.Context, schemaName string, pesertaID uuid.UUID, alokasiRuanganID uuid.UUID, nomorKursi string) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
 	var arIDParam interface{} = alokasiRuanganID
 	var nkParam interface{} = nomorKursi
@@ -495,11 +504,11 @@ func (r *repository) UpdatePesertaSeating(ctx context.Context, schemaName string
         SET alokasi_ruangan_id = $2, nomor_kursi = $3, updated_at = NOW()
         WHERE id = $1
     `
-	_, err := r.db.ExecContext(ctx, query, pesertaID, arIDParam, nkParam)
+	_, err = tx.ExecContext(ctx, query, pesertaID, arIDParam, nkParam)
 	if err != nil {
 		return fmt.Errorf("gagal memperbarui penempatan kursi peserta: %w", err)
 	}
-	return nil
+	return tx.Commit()
 }
 
 func (r *repository) UpdatePesertaSeatingBatch(ctx context.Context, schemaName string, assignments []struct {
@@ -507,13 +516,9 @@ func (r *repository) UpdatePesertaSeatingBatch(ctx context.Context, schemaName s
 	AlokasiRuanganID uuid.UUID
 	NomorKursi       string
 }) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
-		return err
-	}
-
-	tx, err := r.db.BeginTx(ctx, nil)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
 	if err != nil {
-		return fmt.Errorf("gagal memulai transaksi batch update seating: %w", err)
+		return err
 	}
 	defer tx.Rollback()
 
@@ -549,43 +554,49 @@ func (r *repository) UpdatePesertaSeatingBatch(ctx context.Context, schemaName s
 }
 
 func (r *repository) ClearAllSeatingByUjianMasterID(ctx context.Context, schemaName string, ujianMasterID uuid.UUID) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
 	query := `
         UPDATE peserta_ujian
         SET alokasi_ruangan_id = NULL, nomor_kursi = NULL, updated_at = NOW()
         WHERE ujian_master_id = $1
     `
-	_, err := r.db.ExecContext(ctx, query, ujianMasterID)
+	_, err = tx.ExecContext(ctx, query, ujianMasterID)
 	if err != nil {
 		return fmt.Errorf("gagal membersihkan semua penempatan kursi untuk ujian %s: %w", ujianMasterID, err)
 	}
-	return nil
+	return tx.Commit()
 }
 
 func (r *repository) ClearSeatingByAlokasiRuanganID(ctx context.Context, schemaName string, alokasiRuanganID uuid.UUID) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
 	query := `
         UPDATE peserta_ujian
         SET alokasi_ruangan_id = NULL, nomor_kursi = NULL, updated_at = NOW()
         WHERE alokasi_ruangan_id = $1
     `
-	_, err := r.db.ExecContext(ctx, query, alokasiRuanganID)
+	_, err = tx.ExecContext(ctx, query, alokasiRuanganID)
 	if err != nil {
 		return fmt.Errorf("gagal membersihkan penempatan kursi untuk alokasi ruangan %s: %w", alokasiRuanganID, err)
 	}
-	return nil
+	return tx.Commit()
 }
 
 func (r *repository) CreateRuangan(ctx context.Context, schemaName string, ruangan RuanganUjian) (RuanganUjian, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return RuanganUjian{}, err
 	}
+	defer tx.Rollback()
 	query := `
         INSERT INTO ruangan_ujian (id, nama_ruangan, kapasitas, layout_metadata, created_at, updated_at)
         VALUES ($1, $2, $3, $4, $5, $6)
@@ -595,19 +606,21 @@ func (r *repository) CreateRuangan(ctx context.Context, schemaName string, ruang
 	ruangan.CreatedAt = time.Now()
 	ruangan.UpdatedAt = time.Now()
 
-	_, err := r.db.ExecContext(ctx, query, ruangan.ID, ruangan.NamaRuangan, ruangan.Kapasitas, ruangan.LayoutMetadata, ruangan.CreatedAt, ruangan.UpdatedAt)
+	_, err = tx.ExecContext(ctx, query, ruangan.ID, ruangan.NamaRuangan, ruangan.Kapasitas, ruangan.LayoutMetadata, ruangan.CreatedAt, ruangan.UpdatedAt)
 	if err != nil {
 		return RuanganUjian{}, fmt.Errorf("gagal membuat ruangan: %w", err)
 	}
-	return ruangan, nil
+	return ruangan, tx.Commit()
 }
 
 func (r *repository) GetAllRuangan(ctx context.Context, schemaName string) ([]RuanganUjian, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 	query := `SELECT id, nama_ruangan, kapasitas, layout_metadata, created_at, updated_at FROM ruangan_ujian ORDER BY nama_ruangan`
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := tx.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("gagal mengambil semua ruangan: %w", err)
 	}
@@ -621,49 +634,49 @@ func (r *repository) GetAllRuangan(ctx context.Context, schemaName string) ([]Ru
 		}
 		results = append(results, ru)
 	}
-	return results, nil
+	return results, tx.Commit()
 }
 
 func (r *repository) UpdateRuangan(ctx context.Context, schemaName string, ruangan RuanganUjian) (RuanganUjian, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return RuanganUjian{}, err
 	}
+	defer tx.Rollback()
 	ruangan.UpdatedAt = time.Now()
 	query := `
         UPDATE ruangan_ujian SET
             nama_ruangan = $2, kapasitas = $3, layout_metadata = $4, updated_at = $5
         WHERE id = $1
     `
-	_, err := r.db.ExecContext(ctx, query, ruangan.ID, ruangan.NamaRuangan, ruangan.Kapasitas, ruangan.LayoutMetadata, ruangan.UpdatedAt)
+	_, err = tx.ExecContext(ctx, query, ruangan.ID, ruangan.NamaRuangan, ruangan.Kapasitas, ruangan.LayoutMetadata, ruangan.UpdatedAt)
 	if err != nil {
 		return RuanganUjian{}, fmt.Errorf("gagal memperbarui ruangan: %w", err)
 	}
-	return ruangan, nil
+	return ruangan, tx.Commit()
 }
 
 func (r *repository) DeleteRuangan(ctx context.Context, schemaName string, ruanganID uuid.UUID) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 	query := "DELETE FROM ruangan_ujian WHERE id = $1"
-	result, err := r.db.ExecContext(ctx, query, ruanganID)
+	result, err := tx.ExecContext(ctx, query, ruanganID)
 	if err != nil {
 		return fmt.Errorf("gagal menghapus ruangan: %w", err)
 	}
 	if rows, _ := result.RowsAffected(); rows == 0 {
 		return errors.New("ruangan tidak ditemukan")
 	}
-	return nil
+	return tx.Commit()
 }
 
 func (r *repository) CreateAlokasiRuanganBatch(ctx context.Context, schemaName string, ujianMasterID uuid.UUID, ruanganIDs []uuid.UUID) ([]AlokasiRuanganUjian, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
-		return nil, err
-	}
-
-	tx, err := r.db.BeginTx(ctx, nil)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
 	if err != nil {
-		return nil, fmt.Errorf("gagal memulai transaksi: %w", err)
+		return nil, err
 	}
 	defer tx.Rollback()
 
@@ -794,9 +807,11 @@ func (r *repository) CreateAlokasiRuanganBatch(ctx context.Context, schemaName s
 }
 
 func (r *repository) GetAlokasiRuanganByUjianMasterID(ctx context.Context, schemaName string, ujianMasterID uuid.UUID) ([]AlokasiRuanganUjian, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 	query := `
         SELECT
             aru.id, aru.ujian_master_id, aru.ruangan_id, aru.kode_ruangan, aru.jumlah_kursi_terpakai,
@@ -806,7 +821,7 @@ func (r *repository) GetAlokasiRuanganByUjianMasterID(ctx context.Context, schem
         WHERE aru.ujian_master_id = $1
         ORDER BY aru.kode_ruangan
     `
-	rows, err := r.db.QueryContext(ctx, query, ujianMasterID)
+	rows, err := tx.QueryContext(ctx, query, ujianMasterID)
 	if err != nil {
 		return nil, fmt.Errorf("gagal mengambil alokasi ruangan: %w", err)
 	}
@@ -823,32 +838,30 @@ func (r *repository) GetAlokasiRuanganByUjianMasterID(ctx context.Context, schem
 		}
 		results = append(results, ar)
 	}
-	return results, nil
+	return results, tx.Commit()
 }
 
 func (r *repository) DeleteAlokasiRuangan(ctx context.Context, schemaName string, alokasiRuanganID uuid.UUID) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 	query := "DELETE FROM alokasi_ruangan_ujian WHERE id = $1"
-	result, err := r.db.ExecContext(ctx, query, alokasiRuanganID)
+	result, err := tx.ExecContext(ctx, query, alokasiRuanganID)
 	if err != nil {
 		return fmt.Errorf("gagal menghapus alokasi ruangan: %w", err)
 	}
 	if rows, _ := result.RowsAffected(); rows == 0 {
 		return errors.New("alokasi ruangan tidak ditemukan")
 	}
-	return nil
+	return tx.Commit()
 }
 
 func (r *repository) RecalculateAlokasiKursiCount(ctx context.Context, schemaName string, ujianMasterID uuid.UUID) error {
-	if err := r.setSchema(ctx, schemaName); err != nil {
-		return err
-	}
-
-	tx, err := r.db.BeginTx(ctx, nil)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
 	if err != nil {
-		return fmt.Errorf("gagal memulai transaksi recalculate: %w", err)
+		return err
 	}
 	defer tx.Rollback()
 
@@ -882,16 +895,18 @@ func (r *repository) RecalculateAlokasiKursiCount(ctx context.Context, schemaNam
 }
 
 func (r *repository) DeletePesertaByMasterAndKelas(ctx context.Context, schemaName string, masterID uuid.UUID, kelasID uuid.UUID) (int64, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return 0, err
 	}
+	defer tx.Rollback()
 
 	query := `
         DELETE FROM peserta_ujian
         WHERE ujian_master_id = $1 AND kelas_id = $2
     `
 
-	result, err := r.db.ExecContext(ctx, query, masterID, kelasID)
+	result, err := tx.ExecContext(ctx, query, masterID, kelasID)
 	if err != nil {
 		return 0, fmt.Errorf("gagal menghapus peserta ujian: %w", err)
 	}
@@ -905,17 +920,13 @@ func (r *repository) DeletePesertaByMasterAndKelas(ctx context.Context, schemaNa
 		return 0, errors.New("tidak ada peserta ujian yang ditemukan untuk kelas ini")
 	}
 
-	return rowsAffected, nil
+	return rowsAffected, tx.Commit()
 }
 
 func (r *repository) GenerateNomorUjianForUjianMaster(ctx context.Context, schemaName string, ujianMasterID uuid.UUID, prefix string) (int, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
-		return 0, err
-	}
-
-	tx, err := r.db.BeginTx(ctx, nil)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
 	if err != nil {
-		return 0, fmt.Errorf("gagal memulai transaksi: %w", err)
+		return 0, err
 	}
 	defer tx.Rollback()
 
@@ -1001,13 +1012,9 @@ func (r *repository) UpdatePesertaNomorUjianFromExcel(ctx context.Context, schem
 	NamaLengkap string
 	NomorUjian  string
 }) (int, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
-		return 0, err
-	}
-
-	tx, err := r.db.BeginTx(ctx, nil)
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
 	if err != nil {
-		return 0, fmt.Errorf("gagal memulai transaksi: %w", err)
+		return 0, err
 	}
 	defer tx.Rollback()
 
@@ -1049,9 +1056,11 @@ func (r *repository) UpdatePesertaNomorUjianFromExcel(ctx context.Context, schem
 }
 
 func (r *repository) GetUniqueRombelIDs(ctx context.Context, schemaName string, ujianMasterID uuid.UUID) ([]KartuUjianKelasFilter, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 
 	type rombelResult struct {
 		RombelID uuid.UUID
@@ -1070,7 +1079,7 @@ func (r *repository) GetUniqueRombelIDs(ctx context.Context, schemaName string,
         ORDER BY k.nama_kelas
     `
 
-	rows, err := r.db.QueryContext(ctx, query, ujianMasterID)
+	rows, err := tx.QueryContext(ctx, query, ujianMasterID)
 	if err != nil {
 		return nil, fmt.Errorf("gagal mengambil rombel unik: %w", err)
 	}
@@ -1092,13 +1101,15 @@ func (r *repository) GetUniqueRombelIDs(ctx context.Context, schemaName string,
 		}
 	}
 
-	return finalFilters, nil
+	return finalFilters, tx.Commit()
 }
 
 func (r *repository) GetKartuUjianData(ctx context.Context, schemaName string, ujianMasterID uuid.UUID, rombelID uuid.UUID, pesertaIDs []uuid.UUID) ([]KartuUjianDetail, error) {
-	if err := r.setSchema(ctx, schemaName); err != nil {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 
 	type pesertaDetailRaw struct {
 		ID            string
@@ -1147,7 +1158,7 @@ func (r *repository) GetKartuUjianData(ctx context.Context, schemaName string, u
 
 	query += " ORDER BY k.nama_kelas ASC, s.nama_lengkap ASC"
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := tx.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("gagal mengambil data kartu ujian: %w", err)
 	}
@@ -1194,5 +1205,5 @@ func (r *repository) GetKartuUjianData(ctx context.Context, schemaName string, u
 		details[i] = detail
 	}
 
-	return details, nil
+	return details, tx.Commit()
 }
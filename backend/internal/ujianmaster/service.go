@@ -6,6 +6,7 @@ import (
 	"encoding/csv"
 	"errors"
 	"fmt"
+	"skoola/internal/audit"
 	"skoola/internal/rombel"
 	"strings"
 	"time"
@@ -69,13 +70,15 @@ type Service interface {
 type service struct {
 	repo          Repository
 	rombelService rombel.Service
+	audit         audit.Recorder
 }
 
 // NewService creates a new UjianMaster service.
-func NewService(repo Repository, rombelService rombel.Service) Service {
+func NewService(repo Repository, rombelService rombel.Service, auditLog audit.Recorder) Service {
 	return &service{
 		repo:          repo,
 		rombelService: rombelService,
+		audit:         auditLog,
 	}
 }
 
@@ -279,6 +282,7 @@ func (s *service) CreateRuangan(ctx context.Context, schemaName string, input Up
 		return RuanganUjian{}, fmt.Errorf("gagal membuat ruangan: %w", err)
 	}
 
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionCreate, Entity: "ruangan_ujian", EntityID: createdRuangan.ID.String(), After: createdRuangan})
 	return createdRuangan, nil
 }
 
@@ -311,6 +315,7 @@ func (s *service) UpdateRuangan(ctx context.Context, schemaName string, ruanganI
 		return RuanganUjian{}, fmt.Errorf("gagal memperbarui ruangan: %w", err)
 	}
 
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionUpdate, Entity: "ruangan_ujian", EntityID: ruanganID, After: updatedRuangan})
 	return updatedRuangan, nil
 }
 
@@ -323,7 +328,11 @@ func (s *service) DeleteRuangan(ctx context.Context, schemaName string, ruanganI
 	// Cek apakah ruangan sedang dialokasikan untuk ujian
 	// ... (Implementasi pengecekan di repository)
 
-	return s.repo.DeleteRuangan(ctx, schemaName, rID)
+	if err := s.repo.DeleteRuangan(ctx, schemaName, rID); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionDelete, Entity: "ruangan_ujian", EntityID: ruanganID})
+	return nil
 }
 
 // =================================================================================
@@ -354,6 +363,7 @@ func (s *service) AssignRuangan(ctx context.Context, schemaName string, ujianMas
 		return nil, fmt.Errorf("gagal mengalokasikan ruangan: %w", err)
 	}
 
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionCreate, Entity: "alokasi_ruangan_ujian", EntityID: ujianMasterID, After: alokasi})
 	return alokasi, nil
 }
 
@@ -385,7 +395,11 @@ func (s *service) RemoveAlokasiRuangan(ctx context.Context, schemaName string, a
 
 	// Namun, jika alokasi berhasil dihapus, kita tidak perlu Recalculate karena `jumlah_kursi_terpakai` akan otomatis tidak relevan atau harus di-0-kan untuk alokasi tersebut.
 	// Logic Recalculate lebih penting di DistributePesertaSmart.
-	return s.repo.DeleteAlokasiRuangan(ctx, schemaName, arID)
+	if err := s.repo.DeleteAlokasiRuangan(ctx, schemaName, arID); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionDelete, Entity: "alokasi_ruangan_ujian", EntityID: alokasiRuanganID})
+	return nil
 }
 
 func (s *service) GetAlokasiKursi(ctx context.Context, schemaName string, ujianMasterID string) ([]PesertaUjianDetail, []AlokasiRuanganUjian, error) {
@@ -438,6 +452,7 @@ func (s *service) UpdatePesertaSeating(ctx context.Context, schemaName string, u
 	if err := s.repo.UpdatePesertaSeating(ctx, schemaName, pID, arID, input.NomorKursi); err != nil {
 		return fmt.Errorf("gagal update seating di repo: %w", err)
 	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionUpdate, Entity: "peserta_ujian", EntityID: input.PesertaID, After: input})
 
 	// 2. [FIX BARU] Ambil ujianMasterID dan panggil recalculation
 	umID, err := uuid.Parse(ujianMasterID)
@@ -536,6 +551,12 @@ func (s *service) DistributePesertaSmart(ctx context.Context, schemaName string,
 	if err != nil {
 		return fmt.Errorf("gagal menyimpan penempatan kursi cerdas: %w", err)
 	}
+	s.audit.Record(ctx, schemaName, audit.Entry{
+		Action:   audit.ActionUpdate,
+		Entity:   "peserta_ujian",
+		EntityID: ujianMasterID,
+		After:    map[string]interface{}{"distribusi_kursi": len(assignments)},
+	})
 
 	// [BARU] 5. Hitung ulang counter alokasi ruangan setelah seating selesai
 	// umID sudah tersedia sebagai tipe uuid.UUID
@@ -774,6 +795,12 @@ func (s *service) ImportPesertaFromExcel(ctx context.Context, schemaName string,
 			return ExcelImportResponse{}, fmt.Errorf("gagal update database: %w", err)
 		}
 		updatedCount = count
+		s.audit.Record(ctx, schemaName, audit.Entry{
+			Action:   audit.ActionUpdate,
+			Entity:   "peserta_ujian",
+			EntityID: ujianMasterID,
+			After:    map[string]interface{}{"import_nomor_ujian": updatedCount},
+		})
 	}
 
 	response := ExcelImportResponse{
@@ -805,6 +832,12 @@ func (s *service) GenerateNomorUjianForUjianMaster(ctx context.Context, schemaNa
 	if err != nil {
 		return 0, fmt.Errorf("gagal generate nomor ujian: %w", err)
 	}
+	s.audit.Record(ctx, schemaName, audit.Entry{
+		Action:   audit.ActionUpdate,
+		Entity:   "peserta_ujian",
+		EntityID: ujianMasterID,
+		After:    map[string]interface{}{"generate_nomor_ujian": count, "prefix": prefix},
+	})
 
 	return count, nil
 }
@@ -832,6 +865,12 @@ func (s *service) RemovePesertaByKelas(ctx context.Context, schemaName string, u
 	if err != nil {
 		return 0, fmt.Errorf("gagal menghapus peserta ujian: %w", err)
 	}
+	s.audit.Record(ctx, schemaName, audit.Entry{
+		Action:   audit.ActionDelete,
+		Entity:   "peserta_ujian",
+		EntityID: ujianMasterID,
+		Before:   map[string]interface{}{"kelas_id": kelasID, "jumlah": rowsAffected},
+	})
 
 	return rowsAffected, nil
 }
@@ -874,6 +913,12 @@ func (s *service) AddPesertaFromKelas(ctx context.Context, schemaName string, uj
 	if err := s.repo.CreatePesertaUjianBatch(ctx, schemaName, peserta); err != nil {
 		return 0, fmt.Errorf("gagal membuat data peserta ujian: %w", err)
 	}
+	s.audit.Record(ctx, schemaName, audit.Entry{
+		Action:   audit.ActionCreate,
+		Entity:   "peserta_ujian",
+		EntityID: ujianMasterID,
+		After:    map[string]interface{}{"kelas_id": kelasID, "jumlah": len(peserta)},
+	})
 
 	return len(peserta), nil
 }
@@ -884,6 +929,7 @@ func (s *service) CreateUjianMaster(ctx context.Context, schemaName string, req
 	if err != nil {
 		return UjianMaster{}, fmt.Errorf("gagal membuat paket ujian di service: %w", err)
 	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionCreate, Entity: "ujian_master", EntityID: createdUM.ID.String(), After: createdUM})
 	return createdUM, nil
 }
 
@@ -946,6 +992,7 @@ func (s *service) UpdateUjianMaster(ctx context.Context, schemaName string, id s
 	if err != nil {
 		return UjianMaster{}, fmt.Errorf("gagal memperbarui paket ujian di service: %w", err)
 	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionUpdate, Entity: "ujian_master", EntityID: id, Before: existing, After: updatedUM})
 
 	return updatedUM, nil
 }
@@ -956,7 +1003,15 @@ func (s *service) DeleteUjianMaster(ctx context.Context, schemaName string, id s
 	if err != nil {
 		return errors.New("ID paket ujian tidak valid")
 	}
-	return s.repo.Delete(ctx, schemaName, umID)
+	existing, err := s.repo.GetByID(ctx, schemaName, umID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, schemaName, umID); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionDelete, Entity: "ujian_master", EntityID: id, Before: existing})
+	return nil
 }
 
 func (s *service) AssignKelasToUjian(ctx context.Context, schemaName string, ujianMasterID string, pengajarKelasIDs []string) (int, error) {
@@ -968,6 +1023,12 @@ func (s *service) AssignKelasToUjian(ctx context.Context, schemaName string, uji
 	if _, err := s.repo.AssignKelasToUjian(ctx, schemaName, umID, pengajarKelasIDs); err != nil {
 		return 0, fmt.Errorf("gagal menugaskan kelas ke ujian: %w", err)
 	}
+	s.audit.Record(ctx, schemaName, audit.Entry{
+		Action:   audit.ActionCreate,
+		Entity:   "ujian",
+		EntityID: ujianMasterID,
+		After:    map[string]interface{}{"pengajar_kelas_ids": pengajarKelasIDs},
+	})
 
 	return len(pengajarKelasIDs), nil
 }
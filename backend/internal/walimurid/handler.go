@@ -0,0 +1,171 @@
+// file: backend/internal/walimurid/handler.go
+package walimurid
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"skoola/internal/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{service: s}
+}
+
+func writeError(w http.ResponseWriter, prefix string, err error) {
+	switch {
+	case errors.Is(err, ErrValidation):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Wali murid tidak ditemukan", http.StatusNotFound)
+	default:
+		http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// GetAll adalah handler untuk GET /wali-murid.
+func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	result, err := h.service.GetAll(r.Context(), schemaName)
+	if err != nil {
+		http.Error(w, "Gagal mengambil wali murid: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// GetByID adalah handler untuk GET /wali-murid/{id}.
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	result, err := h.service.GetByID(r.Context(), schemaName, chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, "Gagal mengambil wali murid: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// Create adalah handler untuk POST /wali-murid.
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	var input UpsertWaliMuridInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.Create(r.Context(), schemaName, input)
+	if err != nil {
+		writeError(w, "Gagal membuat wali murid: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
+
+// Update adalah handler untuk PUT /wali-murid/{id}.
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	var input UpsertWaliMuridInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.Update(r.Context(), schemaName, chi.URLParam(r, "id"), input)
+	if err != nil {
+		writeError(w, "Gagal memperbarui wali murid: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// Delete adalah handler untuk DELETE /wali-murid/{id}.
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	if err := h.service.Delete(r.Context(), schemaName, chi.URLParam(r, "id")); err != nil {
+		writeError(w, "Gagal menghapus wali murid: ", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// LinkSiswa adalah handler untuk POST /wali-murid/{id}/siswa.
+func (h *Handler) LinkSiswa(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+	var input LinkSiswaInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Request body tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.LinkSiswa(r.Context(), schemaName, chi.URLParam(r, "id"), input); err != nil {
+		writeError(w, "Gagal menghubungkan siswa: ", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UnlinkSiswa adalah handler untuk DELETE /wali-murid/{id}/siswa/{studentID}.
+func (h *Handler) UnlinkSiswa(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	err := h.service.UnlinkSiswa(r.Context(), schemaName, chi.URLParam(r, "id"), chi.URLParam(r, "studentID"))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Siswa tidak terhubung dengan wali murid ini", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Gagal melepas siswa: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GenerateFromSiswa adalah handler untuk POST /wali-murid/generate.
+// Respons berisi password awal setiap akun baru dan hanya dikirim sekali.
+func (h *Handler) GenerateFromSiswa(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	result, err := h.service.GenerateFromSiswa(r.Context(), schemaName)
+	if err != nil {
+		http.Error(w, "Gagal membuat wali murid dari data siswa: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
+
+// ResetPassword adalah handler untuk POST /wali-murid/{id}/reset-password.
+func (h *Handler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	schemaName := r.Context().Value(middleware.SchemaNameKey).(string)
+
+	result, err := h.service.ResetPassword(r.Context(), schemaName, chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, "Gagal membuat password wali murid: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,103 @@
+// file: backend/internal/walimurid/model.go
+package walimurid
+
+import "time"
+
+// RoleWaliMurid adalah nilai users.role untuk akun portal wali murid.
+const RoleWaliMurid = "wali_murid"
+
+// AccountDomain adalah domain email yang dipakai sebagai username akun wali murid,
+// misalnya 081234567890@wali.skoola.id.
+const AccountDomain = "wali.skoola.id"
+
+// Hubungan wali murid dengan siswa.
+const (
+	HubunganAyah = "AYAH"
+	HubunganIbu  = "IBU"
+	HubunganWali = "WALI"
+)
+
+// Anak adalah siswa yang terhubung dengan seorang wali murid.
+type Anak struct {
+	StudentID   string  `json:"student_id"`
+	NamaLengkap string  `json:"nama_lengkap"`
+	NIS         *string `json:"nis"`
+	NamaKelas   *string `json:"nama_kelas"`
+	Hubungan    string  `json:"hubungan"`
+}
+
+// WaliMurid merepresentasikan orang tua/wali beserta anak-anaknya di sekolah.
+type WaliMurid struct {
+	ID           string    `json:"id"`
+	UserID       *string   `json:"user_id"`
+	Email        *string   `json:"email"`
+	NamaLengkap  string    `json:"nama_lengkap"`
+	NomorTelepon *string   `json:"nomor_telepon"`
+	Anak         []Anak    `json:"anak"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// UpsertWaliMuridInput adalah DTO untuk membuat atau memperbarui wali murid.
+type UpsertWaliMuridInput struct {
+	NamaLengkap  string `json:"nama_lengkap" validate:"required,min=3,max=255"`
+	NomorTelepon string `json:"nomor_telepon" validate:"omitempty,max=30"`
+}
+
+// LinkSiswaInput adalah DTO untuk menghubungkan wali murid dengan seorang siswa.
+type LinkSiswaInput struct {
+	StudentID string `json:"student_id" validate:"required,uuid"`
+	Hubungan  string `json:"hubungan" validate:"required,oneof=AYAH IBU WALI"`
+}
+
+// GeneratedAccount berisi kredensial wali murid yang baru dibuat. Password hanya
+// dikembalikan sekali pada saat pembuatan dan tidak dapat dilihat lagi.
+type GeneratedAccount struct {
+	WaliMuridID string   `json:"wali_murid_id"`
+	NamaLengkap string   `json:"nama_lengkap"`
+	Email       string   `json:"email"`
+	Password    string   `json:"password"`
+	NamaAnak    []string `json:"nama_anak"`
+}
+
+// SkippedSiswa adalah siswa yang tidak dapat dibuatkan wali murid secara otomatis.
+type SkippedSiswa struct {
+	StudentID   string `json:"student_id"`
+	NamaLengkap string `json:"nama_lengkap"`
+	Alasan      string `json:"alasan"`
+}
+
+// GenerateResult adalah hasil pembuatan wali murid massal dari data orang tua siswa.
+type GenerateResult struct {
+	Akun         []GeneratedAccount `json:"akun"`
+	JumlahTautan int                `json:"jumlah_tautan"`
+	Dilewati     []SkippedSiswa     `json:"dilewati"`
+}
+
+// parentSource adalah data orang tua/wali yang tersimpan di tabel students.
+type parentSource struct {
+	StudentID       string
+	NamaLengkap     string
+	NamaAyah        *string
+	NamaIbu         *string
+	NamaWali        *string
+	NomorKontakWali *string
+	HasWali         bool
+}
+
+// newWali adalah wali murid baru beserta akun dan tautan anak yang disimpan sekaligus.
+type newWali struct {
+	WaliMurid    WaliMurid
+	Email        string
+	PasswordHash string
+	Links        []LinkSiswaInput
+}
+
+// accountCredential adalah akun yang dibuat atau passwordnya diganti untuk wali yang sudah ada.
+type accountCredential struct {
+	WaliMuridID  string
+	UserID       string
+	Email        string
+	PasswordHash string
+	IsNew        bool
+}
@@ -0,0 +1,417 @@
+// file: backend/internal/walimurid/repository.go
+package walimurid
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"skoola/pkg/database"
+)
+
+// Repository mendefinisikan interface untuk interaksi database wali murid.
+type Repository interface {
+	GetAll(ctx context.Context, schemaName string) ([]WaliMurid, error)
+	GetByID(ctx context.Context, schemaName string, id string) (*WaliMurid, error)
+	GetByUserID(ctx context.Context, schemaName string, userID string) (*WaliMurid, error)
+	Create(ctx context.Context, schemaName string, w *WaliMurid) error
+	Update(ctx context.Context, schemaName string, w *WaliMurid) error
+	Delete(ctx context.Context, schemaName string, id string) error
+	LinkSiswa(ctx context.Context, schemaName string, waliMuridID string, input LinkSiswaInput) error
+	UnlinkSiswa(ctx context.Context, schemaName string, waliMuridID string, studentID string) error
+	GetParentSources(ctx context.Context, schemaName string) ([]parentSource, error)
+	GetIDsByNomorTelepon(ctx context.Context, schemaName string) (map[string]string, error)
+	SaveGenerated(ctx context.Context, schemaName string, created []newWali, links map[string][]LinkSiswaInput) error
+	SaveCredential(ctx context.Context, schemaName string, cred accountCredential) error
+}
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewRepository membuat instance baru dari postgresRepository.
+func NewRepository(db *sql.DB) Repository {
+	return &postgresRepository{db: db}
+}
+
+const selectWaliMurid = `
+	SELECT w.id, w.user_id, u.email, w.nama_lengkap, w.nomor_telepon, w.created_at, w.updated_at
+	FROM wali_murid w
+	LEFT JOIN users u ON u.id = w.user_id
+`
+
+func scanWaliMurid(row interface{ Scan(...interface{}) error }, w *WaliMurid) error {
+	return row.Scan(&w.ID, &w.UserID, &w.Email, &w.NamaLengkap, &w.NomorTelepon, &w.CreatedAt, &w.UpdatedAt)
+}
+
+func (r *postgresRepository) GetAll(ctx context.Context, schemaName string) ([]WaliMurid, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, selectWaliMurid+` ORDER BY w.nama_lengkap ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil wali murid: %w", err)
+	}
+	defer rows.Close()
+
+	list := []WaliMurid{}
+	index := make(map[string]int)
+	for rows.Next() {
+		var w WaliMurid
+		if err := scanWaliMurid(rows, &w); err != nil {
+			return nil, fmt.Errorf("gagal memindai wali murid: %w", err)
+		}
+		w.Anak = []Anak{}
+		index[w.ID] = len(list)
+		list = append(list, w)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	anak, err := getAnak(ctx, tx, "")
+	if err != nil {
+		return nil, err
+	}
+	for waliID, items := range anak {
+		if i, ok := index[waliID]; ok {
+			list[i].Anak = items
+		}
+	}
+	return list, tx.Commit()
+}
+
+func (r *postgresRepository) GetByID(ctx context.Context, schemaName string, id string) (*WaliMurid, error) {
+	return r.getOne(ctx, schemaName, `WHERE w.id = $1`, id)
+}
+
+func (r *postgresRepository) GetByUserID(ctx context.Context, schemaName string, userID string) (*WaliMurid, error) {
+	return r.getOne(ctx, schemaName, `WHERE w.user_id = $1`, userID)
+}
+
+func (r *postgresRepository) getOne(ctx context.Context, schemaName string, where string, arg string) (*WaliMurid, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var w WaliMurid
+	if err := scanWaliMurid(tx.QueryRowContext(ctx, selectWaliMurid+where, arg), &w); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("gagal mengambil wali murid: %w", err)
+	}
+
+	anak, err := getAnak(ctx, tx, w.ID)
+	if err != nil {
+		return nil, err
+	}
+	w.Anak = anak[w.ID]
+	if w.Anak == nil {
+		w.Anak = []Anak{}
+	}
+	return &w, tx.Commit()
+}
+
+// getAnak mengambil anak per wali murid beserta kelasnya di tahun ajaran aktif.
+// waliMuridID kosong berarti semua wali murid.
+func getAnak(ctx context.Context, tx *sql.Tx, waliMuridID string) (map[string][]Anak, error) {
+	query := `
+		SELECT ws.wali_murid_id, s.id, s.nama_lengkap, s.nis, ck.nama_kelas, ws.hubungan
+		FROM wali_murid_siswa ws
+		JOIN students s ON ws.student_id = s.id
+		LEFT JOIN LATERAL (
+			SELECT k.nama_kelas
+			FROM anggota_kelas ak
+			JOIN kelas k ON ak.kelas_id = k.id
+			JOIN tahun_ajaran ta ON k.tahun_ajaran_id = ta.id
+			WHERE ak.student_id = s.id AND ta.status = 'Aktif'
+			LIMIT 1
+		) ck ON true
+		WHERE $1 = '' OR ws.wali_murid_id::text = $1
+		ORDER BY s.nama_lengkap ASC
+	`
+	rows, err := tx.QueryContext(ctx, query, waliMuridID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil anak wali murid: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[string][]Anak)
+	for rows.Next() {
+		var waliID string
+		var a Anak
+		if err := rows.Scan(&waliID, &a.StudentID, &a.NamaLengkap, &a.NIS, &a.NamaKelas, &a.Hubungan); err != nil {
+			return nil, fmt.Errorf("gagal memindai anak wali murid: %w", err)
+		}
+		result[waliID] = append(result[waliID], a)
+	}
+	return result, rows.Err()
+}
+
+func (r *postgresRepository) Create(ctx context.Context, schemaName string, w *WaliMurid) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertWaliMurid(ctx, tx, w); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertWaliMurid(ctx context.Context, tx *sql.Tx, w *WaliMurid) error {
+	query := `
+		INSERT INTO wali_murid (id, user_id, nama_lengkap, nomor_telepon)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at, updated_at
+	`
+	err := tx.QueryRowContext(ctx, query, w.ID, w.UserID, w.NamaLengkap, w.NomorTelepon).Scan(&w.CreatedAt, &w.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("gagal insert wali murid: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresRepository) Update(ctx context.Context, schemaName string, w *WaliMurid) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE wali_murid SET nama_lengkap = $1, nomor_telepon = $2, updated_at = NOW()
+		WHERE id = $3
+		RETURNING updated_at
+	`
+	err = tx.QueryRowContext(ctx, query, w.NamaLengkap, w.NomorTelepon, w.ID).Scan(&w.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return sql.ErrNoRows
+		}
+		return fmt.Errorf("gagal update wali murid: %w", err)
+	}
+	return tx.Commit()
+}
+
+// Delete menghapus wali murid beserta akun loginnya.
+func (r *postgresRepository) Delete(ctx context.Context, schemaName string, id string) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var userID sql.NullString
+	err = tx.QueryRowContext(ctx, `DELETE FROM wali_murid WHERE id = $1 RETURNING user_id`, id).Scan(&userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return sql.ErrNoRows
+		}
+		return fmt.Errorf("gagal menghapus wali murid: %w", err)
+	}
+	if userID.Valid {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, userID.String); err != nil {
+			return fmt.Errorf("gagal menghapus akun wali murid: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *postgresRepository) LinkSiswa(ctx context.Context, schemaName string, waliMuridID string, input LinkSiswaInput) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := linkSiswa(ctx, tx, waliMuridID, input); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func linkSiswa(ctx context.Context, tx *sql.Tx, waliMuridID string, input LinkSiswaInput) error {
+	query := `
+		INSERT INTO wali_murid_siswa (wali_murid_id, student_id, hubungan)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (wali_murid_id, student_id) DO UPDATE SET hubungan = EXCLUDED.hubungan
+	`
+	if _, err := tx.ExecContext(ctx, query, waliMuridID, input.StudentID, input.Hubungan); err != nil {
+		return fmt.Errorf("gagal menghubungkan siswa dengan wali murid: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresRepository) UnlinkSiswa(ctx context.Context, schemaName string, waliMuridID string, studentID string) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM wali_murid_siswa WHERE wali_murid_id = $1 AND student_id = $2`, waliMuridID, studentID)
+	if err != nil {
+		return fmt.Errorf("gagal melepas siswa dari wali murid: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
+// GetParentSources mengambil data orang tua/wali yang tercatat di setiap siswa aktif,
+// beserta penanda apakah siswa sudah terhubung dengan wali murid.
+func (r *postgresRepository) GetParentSources(ctx context.Context, schemaName string) ([]parentSource, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT s.id, s.nama_lengkap, s.nama_ayah, s.nama_ibu, s.nama_wali, s.nomor_kontak_wali,
+		       EXISTS (SELECT 1 FROM wali_murid_siswa ws WHERE ws.student_id = s.id)
+		FROM students s
+		WHERE COALESCE((
+			SELECT ra.status::text FROM riwayat_akademik ra
+			WHERE ra.student_id = s.id
+			ORDER BY ra.tanggal_kejadian DESC, ra.created_at DESC
+			LIMIT 1
+		), 'Aktif') = 'Aktif'
+		ORDER BY s.nama_lengkap ASC
+	`
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil data orang tua siswa: %w", err)
+	}
+	defer rows.Close()
+
+	list := []parentSource{}
+	for rows.Next() {
+		var p parentSource
+		if err := rows.Scan(&p.StudentID, &p.NamaLengkap, &p.NamaAyah, &p.NamaIbu, &p.NamaWali, &p.NomorKontakWali, &p.HasWali); err != nil {
+			return nil, fmt.Errorf("gagal memindai data orang tua siswa: %w", err)
+		}
+		list = append(list, p)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, tx.Commit()
+}
+
+// GetIDsByNomorTelepon memetakan nomor telepon ke ID wali murid yang sudah ada.
+func (r *postgresRepository) GetIDsByNomorTelepon(ctx context.Context, schemaName string) (map[string]string, error) {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `SELECT id, nomor_telepon FROM wali_murid WHERE nomor_telepon IS NOT NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil nomor telepon wali murid: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[string]string)
+	for rows.Next() {
+		var id, nomor string
+		if err := rows.Scan(&id, &nomor); err != nil {
+			return nil, fmt.Errorf("gagal memindai nomor telepon wali murid: %w", err)
+		}
+		result[nomor] = id
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, tx.Commit()
+}
+
+// SaveGenerated menyimpan wali murid baru beserta akunnya dan tautan siswa ke wali
+// yang sudah ada dalam satu transaksi.
+func (r *postgresRepository) SaveGenerated(ctx context.Context, schemaName string, created []newWali, links map[string][]LinkSiswaInput) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i := range created {
+		nw := &created[i]
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO users (id, email, password_hash, role) VALUES ($1, $2, $3, $4)`,
+			*nw.WaliMurid.UserID, nw.Email, nw.PasswordHash, RoleWaliMurid,
+		); err != nil {
+			return fmt.Errorf("gagal membuat akun %s: %w", nw.Email, err)
+		}
+		if err := insertWaliMurid(ctx, tx, &nw.WaliMurid); err != nil {
+			return err
+		}
+		for _, l := range nw.Links {
+			if err := linkSiswa(ctx, tx, nw.WaliMurid.ID, l); err != nil {
+				return err
+			}
+		}
+	}
+	for waliID, items := range links {
+		for _, l := range items {
+			if err := linkSiswa(ctx, tx, waliID, l); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// SaveCredential membuat akun untuk wali murid yang belum memiliki akun, atau
+// mengganti passwordnya dan mencabut sesi login yang masih aktif.
+func (r *postgresRepository) SaveCredential(ctx context.Context, schemaName string, c accountCredential) error {
+	tx, err := database.BeginTenantTx(ctx, r.db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if c.IsNew {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO users (id, email, password_hash, role) VALUES ($1, $2, $3, $4)`,
+			c.UserID, c.Email, c.PasswordHash, RoleWaliMurid,
+		); err != nil {
+			return fmt.Errorf("gagal membuat akun %s: %w", c.Email, err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE wali_murid SET user_id = $1, updated_at = NOW() WHERE id = $2`,
+			c.UserID, c.WaliMuridID,
+		); err != nil {
+			return fmt.Errorf("gagal menghubungkan akun ke wali murid: %w", err)
+		}
+		return tx.Commit()
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE users SET password_hash = $1, updated_at = NOW() WHERE id = $2`,
+		c.PasswordHash, c.UserID,
+	); err != nil {
+		return fmt.Errorf("gagal mengganti password %s: %w", c.Email, err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`,
+		c.UserID,
+	); err != nil {
+		return fmt.Errorf("gagal mencabut sesi wali murid: %w", err)
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,359 @@
+// file: backend/internal/walimurid/service.go
+package walimurid
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"skoola/internal/audit"
+	"skoola/pkg/credential"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var ErrValidation = errors.New("validation failed")
+
+// Service mendefinisikan interface untuk logika bisnis wali murid.
+type Service interface {
+	GetAll(ctx context.Context, schemaName string) ([]WaliMurid, error)
+	GetByID(ctx context.Context, schemaName string, id string) (*WaliMurid, error)
+	GetByUserID(ctx context.Context, schemaName string, userID string) (*WaliMurid, error)
+	Create(ctx context.Context, schemaName string, input UpsertWaliMuridInput) (*WaliMurid, error)
+	Update(ctx context.Context, schemaName string, id string, input UpsertWaliMuridInput) (*WaliMurid, error)
+	Delete(ctx context.Context, schemaName string, id string) error
+	LinkSiswa(ctx context.Context, schemaName string, id string, input LinkSiswaInput) error
+	UnlinkSiswa(ctx context.Context, schemaName string, id string, studentID string) error
+	GenerateFromSiswa(ctx context.Context, schemaName string) (*GenerateResult, error)
+	ResetPassword(ctx context.Context, schemaName string, id string) (*GeneratedAccount, error)
+}
+
+type service struct {
+	repo     Repository
+	audit    audit.Recorder
+	validate *validator.Validate
+}
+
+// NewService membuat instance baru dari service wali murid.
+func NewService(repo Repository, auditLog audit.Recorder, validate *validator.Validate) Service {
+	return &service{repo: repo, audit: auditLog, validate: validate}
+}
+
+func (s *service) GetAll(ctx context.Context, schemaName string) ([]WaliMurid, error) {
+	return s.repo.GetAll(ctx, schemaName)
+}
+
+func (s *service) GetByID(ctx context.Context, schemaName string, id string) (*WaliMurid, error) {
+	w, err := s.repo.GetByID(ctx, schemaName, id)
+	if err != nil {
+		return nil, err
+	}
+	if w == nil {
+		return nil, sql.ErrNoRows
+	}
+	return w, nil
+}
+
+// GetByUserID mengambil wali murid milik akun login. Mengembalikan nil jika akun
+// tersebut tidak terhubung dengan wali murid.
+func (s *service) GetByUserID(ctx context.Context, schemaName string, userID string) (*WaliMurid, error) {
+	return s.repo.GetByUserID(ctx, schemaName, userID)
+}
+
+func (s *service) Create(ctx context.Context, schemaName string, input UpsertWaliMuridInput) (*WaliMurid, error) {
+	if err := s.validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+
+	w := &WaliMurid{ID: uuid.New().String(), NamaLengkap: strings.TrimSpace(input.NamaLengkap), Anak: []Anak{}}
+	if err := s.applyNomorTelepon(ctx, schemaName, w, input.NomorTelepon); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, schemaName, w); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionCreate, Entity: "wali_murid", EntityID: w.ID, After: w})
+	return w, nil
+}
+
+func (s *service) Update(ctx context.Context, schemaName string, id string, input UpsertWaliMuridInput) (*WaliMurid, error) {
+	if err := s.validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+
+	existing, err := s.GetByID(ctx, schemaName, id)
+	if err != nil {
+		return nil, err
+	}
+	updated := *existing
+	updated.NamaLengkap = strings.TrimSpace(input.NamaLengkap)
+	if err := s.applyNomorTelepon(ctx, schemaName, &updated, input.NomorTelepon); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, schemaName, &updated); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionUpdate, Entity: "wali_murid", EntityID: id, Before: existing, After: updated})
+	return &updated, nil
+}
+
+// applyNomorTelepon menormalkan nomor telepon dan memastikan belum dipakai wali lain.
+func (s *service) applyNomorTelepon(ctx context.Context, schemaName string, w *WaliMurid, nomor string) error {
+	w.NomorTelepon = nil
+	normalized := normalizePhone(nomor)
+	if normalized == "" {
+		return nil
+	}
+	existing, err := s.repo.GetIDsByNomorTelepon(ctx, schemaName)
+	if err != nil {
+		return err
+	}
+	if id, ok := existing[normalized]; ok && id != w.ID {
+		return fmt.Errorf("%w: nomor telepon %s sudah dipakai wali murid lain", ErrValidation, normalized)
+	}
+	w.NomorTelepon = &normalized
+	return nil
+}
+
+func (s *service) Delete(ctx context.Context, schemaName string, id string) error {
+	existing, err := s.GetByID(ctx, schemaName, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, schemaName, id); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionDelete, Entity: "wali_murid", EntityID: id, Before: existing})
+	return nil
+}
+
+func (s *service) LinkSiswa(ctx context.Context, schemaName string, id string, input LinkSiswaInput) error {
+	if err := s.validate.Struct(input); err != nil {
+		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	if _, err := s.GetByID(ctx, schemaName, id); err != nil {
+		return err
+	}
+	if err := s.repo.LinkSiswa(ctx, schemaName, id, input); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{Action: audit.ActionCreate, Entity: "wali_murid_siswa", EntityID: id, After: input})
+	return nil
+}
+
+func (s *service) UnlinkSiswa(ctx context.Context, schemaName string, id string, studentID string) error {
+	if err := s.repo.UnlinkSiswa(ctx, schemaName, id, studentID); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{
+		Action:   audit.ActionDelete,
+		Entity:   "wali_murid_siswa",
+		EntityID: id,
+		Before:   map[string]string{"student_id": studentID},
+	})
+	return nil
+}
+
+// GenerateFromSiswa membuat wali murid beserta akunnya dari kolom orang tua/wali di
+// data siswa aktif yang belum memiliki wali murid. Siswa dengan nomor kontak wali
+// yang sama (kakak-adik) dihubungkan ke satu wali murid. Jika nomor tersebut sudah
+// dimiliki wali murid lain, siswa hanya ditautkan ke wali tersebut.
+func (s *service) GenerateFromSiswa(ctx context.Context, schemaName string) (*GenerateResult, error) {
+	sources, err := s.repo.GetParentSources(ctx, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	existingByPhone, err := s.repo.GetIDsByNomorTelepon(ctx, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GenerateResult{Akun: []GeneratedAccount{}, Dilewati: []SkippedSiswa{}}
+	var created []newWali
+	createdByPhone := make(map[string]int)
+	passwords := make(map[string]string)
+	links := make(map[string][]LinkSiswaInput)
+
+	for _, src := range sources {
+		if src.HasWali {
+			continue
+		}
+		phone := ""
+		if src.NomorKontakWali != nil {
+			phone = normalizePhone(*src.NomorKontakWali)
+		}
+		if phone == "" {
+			result.Dilewati = append(result.Dilewati, SkippedSiswa{StudentID: src.StudentID, NamaLengkap: src.NamaLengkap, Alasan: "nomor kontak wali kosong"})
+			continue
+		}
+		nama, hubungan := parentName(src)
+		if nama == "" {
+			result.Dilewati = append(result.Dilewati, SkippedSiswa{StudentID: src.StudentID, NamaLengkap: src.NamaLengkap, Alasan: "nama orang tua/wali kosong"})
+			continue
+		}
+		link := LinkSiswaInput{StudentID: src.StudentID, Hubungan: hubungan}
+
+		if waliID, ok := existingByPhone[phone]; ok {
+			links[waliID] = append(links[waliID], link)
+			result.JumlahTautan++
+			continue
+		}
+		if i, ok := createdByPhone[phone]; ok {
+			created[i].Links = append(created[i].Links, link)
+			result.JumlahTautan++
+			continue
+		}
+
+		password, err := credential.RandomPassword()
+		if err != nil {
+			return nil, err
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), 10)
+		if err != nil {
+			return nil, fmt.Errorf("gagal melakukan hash password: %w", err)
+		}
+		userID := uuid.New().String()
+		nomor := phone
+		nw := newWali{
+			WaliMurid: WaliMurid{
+				ID:           uuid.New().String(),
+				UserID:       &userID,
+				NamaLengkap:  nama,
+				NomorTelepon: &nomor,
+			},
+			Email:        phone + "@" + AccountDomain,
+			PasswordHash: string(hash),
+			Links:        []LinkSiswaInput{link},
+		}
+		createdByPhone[phone] = len(created)
+		passwords[nw.WaliMurid.ID] = password
+		created = append(created, nw)
+		result.JumlahTautan++
+	}
+
+	if len(created) == 0 && len(links) == 0 {
+		return result, nil
+	}
+	if err := s.repo.SaveGenerated(ctx, schemaName, created, links); err != nil {
+		return nil, err
+	}
+
+	namaSiswa := make(map[string]string, len(sources))
+	for _, src := range sources {
+		namaSiswa[src.StudentID] = src.NamaLengkap
+	}
+	var entries []audit.Entry
+	for _, nw := range created {
+		acc := GeneratedAccount{
+			WaliMuridID: nw.WaliMurid.ID,
+			NamaLengkap: nw.WaliMurid.NamaLengkap,
+			Email:       nw.Email,
+			Password:    passwords[nw.WaliMurid.ID],
+		}
+		for _, l := range nw.Links {
+			acc.NamaAnak = append(acc.NamaAnak, namaSiswa[l.StudentID])
+		}
+		result.Akun = append(result.Akun, acc)
+		entries = append(entries, audit.Entry{
+			Action:   audit.ActionCreate,
+			Entity:   "wali_murid",
+			EntityID: nw.WaliMurid.ID,
+			After:    map[string]interface{}{"nama_lengkap": nw.WaliMurid.NamaLengkap, "email": nw.Email, "anak": nw.Links},
+		})
+	}
+	for waliID, items := range links {
+		for _, l := range items {
+			entries = append(entries, audit.Entry{Action: audit.ActionCreate, Entity: "wali_murid_siswa", EntityID: waliID, After: l})
+		}
+	}
+	s.audit.Record(ctx, schemaName, entries...)
+	return result, nil
+}
+
+// ResetPassword membuat akun untuk wali murid yang belum memiliki akun, atau membuat
+// password baru untuk akun yang sudah ada.
+func (s *service) ResetPassword(ctx context.Context, schemaName string, id string) (*GeneratedAccount, error) {
+	w, err := s.GetByID(ctx, schemaName, id)
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := credential.RandomPassword()
+	if err != nil {
+		return nil, err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), 10)
+	if err != nil {
+		return nil, fmt.Errorf("gagal melakukan hash password: %w", err)
+	}
+
+	cred := accountCredential{WaliMuridID: w.ID, PasswordHash: string(hash), IsNew: w.UserID == nil}
+	if cred.IsNew {
+		cred.UserID = uuid.New().String()
+		username := strings.ReplaceAll(w.ID, "-", "")[:12]
+		if w.NomorTelepon != nil {
+			username = *w.NomorTelepon
+		}
+		cred.Email = username + "@" + AccountDomain
+	} else {
+		cred.UserID = *w.UserID
+		cred.Email = *w.Email
+	}
+	if err := s.repo.SaveCredential(ctx, schemaName, cred); err != nil {
+		return nil, err
+	}
+
+	action := audit.ActionUpdate
+	if cred.IsNew {
+		action = audit.ActionCreate
+	}
+	s.audit.Record(ctx, schemaName, audit.Entry{
+		Action:   action,
+		Entity:   "wali_murid_accounts",
+		EntityID: w.ID,
+		After:    map[string]string{"user_id": cred.UserID, "email": cred.Email},
+	})
+
+	acc := &GeneratedAccount{WaliMuridID: w.ID, NamaLengkap: w.NamaLengkap, Email: cred.Email, Password: password}
+	for _, a := range w.Anak {
+		acc.NamaAnak = append(acc.NamaAnak, a.NamaLengkap)
+	}
+	return acc, nil
+}
+
+// parentName memilih nama wali, lalu ibu, lalu ayah sebagai nama wali murid.
+func parentName(src parentSource) (string, string) {
+	candidates := []struct {
+		nama     *string
+		hubungan string
+	}{
+		{src.NamaWali, HubunganWali},
+		{src.NamaIbu, HubunganIbu},
+		{src.NamaAyah, HubunganAyah},
+	}
+	for _, c := range candidates {
+		if c.nama != nil && strings.TrimSpace(*c.nama) != "" {
+			return strings.TrimSpace(*c.nama), c.hubungan
+		}
+	}
+	return "", ""
+}
+
+// normalizePhone menyisakan digit saja dan mengubah awalan 62 menjadi 0,
+// sehingga "+62 812-3456" dan "08123456" dianggap nomor yang sama.
+func normalizePhone(nomor string) string {
+	var b strings.Builder
+	for _, r := range nomor {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	digits := b.String()
+	if strings.HasPrefix(digits, "62") {
+		digits = "0" + digits[2:]
+	}
+	return digits
+}
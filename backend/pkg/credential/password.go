@@ -0,0 +1,30 @@
+// file: backend/pkg/credential/password.go
+package credential
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// passwordAlphabet tidak memuat karakter yang mudah tertukar saat dicetak (0/O, 1/l/I).
+const (
+	passwordAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+	passwordLength   = 8
+)
+
+// RandomPassword membuat password awal acak untuk akun yang dibuat secara massal
+// (siswa, wali murid). Password dicetak dan dibagikan, sehingga dibuat pendek.
+func RandomPassword() (string, error) {
+	var b strings.Builder
+	max := big.NewInt(int64(len(passwordAlphabet)))
+	for i := 0; i < passwordLength; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("gagal membuat password acak: %w", err)
+		}
+		b.WriteByte(passwordAlphabet[n.Int64()])
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// BeginTenantTx memulai transaksi baru yang search_path-nya diatur ke skema tenant
+// dengan SET LOCAL. Pengaturan tersebut hanya berlaku di dalam transaksi ini, sehingga
+// koneksi yang dikembalikan ke pool tidak pernah membawa skema milik tenant lain.
+// Pemanggil wajib memanggil Commit atau Rollback.
+func BeginTenantTx(ctx context.Context, db *sql.DB, schemaName string) (*sql.Tx, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memulai transaksi: %w", err)
+	}
+	if err := SetTenant(ctx, tx, schemaName); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return tx, nil
+}
+
+// SetTenant mengatur search_path transaksi yang sudah berjalan ke skema tenant.
+func SetTenant(ctx context.Context, tx *sql.Tx, schemaName string) error {
+	if schemaName == "" {
+		return errors.New("gagal mengatur skema tenant: nama skema kosong")
+	}
+	if _, err := tx.ExecContext(ctx, "SET LOCAL search_path TO "+pq.QuoteIdentifier(schemaName)); err != nil {
+		return fmt.Errorf("gagal mengatur skema tenant: %w", err)
+	}
+	return nil
+}
+
+// WithTenant menjalankan fn di dalam satu transaksi milik skema tenant.
+// Transaksi di-commit jika fn selesai tanpa error dan di-rollback jika sebaliknya.
+// Error dari fn dikembalikan apa adanya agar sql.ErrNoRows tetap dapat diperiksa.
+func WithTenant(ctx context.Context, db *sql.DB, schemaName string, fn func(tx *sql.Tx) error) error {
+	tx, err := BeginTenantTx(ctx, db, schemaName)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,42 @@
+// file: backend/pkg/mailer/file.go
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type fileMailer struct {
+	dir  string
+	from string
+}
+
+// NewFileMailer membuat Mailer untuk pengembangan lokal yang tidak mengirim email
+// sungguhan, melainkan menulis setiap email sebagai file .eml di dir dan mencatatnya ke log.
+func NewFileMailer(dir string, from string) Mailer {
+	return &fileMailer{dir: dir, from: from}
+}
+
+func (m *fileMailer) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return fmt.Errorf("gagal membuat direktori email: %w", err)
+	}
+
+	name := fmt.Sprintf("%s_%s.eml", time.Now().Format("20060102T150405"), uuid.New().String()[:8])
+	path := filepath.Join(m.dir, name)
+	if err := os.WriteFile(path, buildMessage(m.from, msg), 0o600); err != nil {
+		return fmt.Errorf("gagal menulis file email: %w", err)
+	}
+
+	log.Printf("Email untuk %s (%s) ditulis ke %s", msg.To, msg.Subject, path)
+	return nil
+}
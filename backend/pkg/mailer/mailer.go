@@ -0,0 +1,17 @@
+// file: backend/pkg/mailer/mailer.go
+package mailer
+
+import "context"
+
+// Message adalah satu email teks biasa yang akan dikirim.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer mendefinisikan interface pengiriman email. Implementasinya dapat
+// diganti tanpa mengubah service yang memakainya.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
@@ -22,4 +22,15 @@ export const loginUser = async (credentials: LoginInput, tenantId: string) => {
   } catch (error) {
     throw error;
   }
-};
\ No newline at end of file
+};
+// Fungsi untuk mencabut sesi login di server
+export const logoutUser = async (refreshToken: string, tenantId: string) => {
+  const config: AxiosRequestConfig = {
+    headers: {},
+  };
+  if (tenantId && config.headers) {
+    config.headers['X-Tenant-ID'] = tenantId;
+  }
+  const response = await apiClient.post('/auth/logout', { refresh_token: refreshToken }, config);
+  return response.data;
+};
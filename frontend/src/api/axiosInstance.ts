@@ -44,4 +44,58 @@ apiClient.interceptors.request.use(
   }
 );
 
+// Satu request refresh dipakai bersama oleh semua request yang gagal 401,
+// karena refresh token hanya bisa dipakai sekali (dirotasi oleh server).
+let refreshPromise: Promise<string> | null = null;
+
+const refreshAccessToken = async (): Promise<string> => {
+  const refreshToken = localStorage.getItem('refreshToken');
+  if (!refreshToken) {
+    throw new Error('Refresh token tidak ditemukan');
+  }
+  const tenantId = localStorage.getItem('tenantId');
+  const headers: Record<string, string> = {};
+  if (tenantId) {
+    headers['X-Tenant-ID'] = tenantId;
+  }
+  const response = await axios.post(
+    `${getApiBaseUrl()}/auth/refresh`,
+    { refresh_token: refreshToken },
+    { headers },
+  );
+  localStorage.setItem('authToken', response.data.token);
+  localStorage.setItem('refreshToken', response.data.refresh_token);
+  return response.data.token;
+};
+
+// Interceptor untuk memperbarui access token yang kedaluwarsa
+apiClient.interceptors.response.use(
+  (response) => response,
+  async (error) => {
+    const originalRequest = error.config;
+    const isAuthRequest = originalRequest?.url?.includes('/login') || originalRequest?.url?.includes('/auth/');
+    if (error.response?.status !== 401 || !originalRequest || originalRequest._retry || isAuthRequest) {
+      return Promise.reject(error);
+    }
+    originalRequest._retry = true;
+
+    try {
+      if (!refreshPromise) {
+        refreshPromise = refreshAccessToken().finally(() => {
+          refreshPromise = null;
+        });
+      }
+      const token = await refreshPromise;
+      originalRequest.headers.Authorization = `Bearer ${token}`;
+      return apiClient(originalRequest);
+    } catch (refreshError) {
+      localStorage.removeItem('authToken');
+      localStorage.removeItem('refreshToken');
+      localStorage.removeItem('tenantId');
+      window.location.href = '/login';
+      return Promise.reject(refreshError);
+    }
+  }
+);
+
 export default apiClient;
\ No newline at end of file
@@ -3,6 +3,7 @@ import { createContext, useContext, useState, useEffect, type ReactNode, useCall
 import { jwtDecode } from 'jwt-decode';
 import type { AuthUser, TahunAjaran } from '../types';
 import { getAllTahunAjaran } from '../api/tahunAjaran';
+import { logoutUser } from '../api/auth';
 
 interface DecodedToken {
   sub: string;
@@ -15,7 +16,7 @@ interface DecodedToken {
 interface AuthContextType {
   isAuthenticated: boolean;
   loading: boolean;
-  login: (token: string) => void;
+  login: (token: string, refreshToken: string, tenantId: string) => void;
   logout: () => void;
   user: AuthUser | null;
   activeTahunAjaran: TahunAjaran | null;
@@ -46,8 +47,15 @@ export const AuthProvider = ({ children }: { children: ReactNode }) => {
     }
   };
 
-  const logout = useCallback(() => {
+  const logout = useCallback(async () => {
+    const refreshToken = localStorage.getItem('refreshToken');
+    if (refreshToken) {
+      // Cabut sesi di server; kegagalan diabaikan karena token lokal tetap dihapus.
+      await logoutUser(refreshToken, localStorage.getItem('tenantId') || '').catch(() => {});
+    }
     localStorage.removeItem('authToken');
+    localStorage.removeItem('refreshToken');
+    localStorage.removeItem('tenantId');
     localStorage.removeItem('activeTahunAjaran');
     setUser(null);
     setActiveTahunAjaranState(null);
@@ -100,9 +108,11 @@ export const AuthProvider = ({ children }: { children: ReactNode }) => {
     }
   }, [initializeSession]);
 
-  const login = (token: string) => {
+  const login = (token: string, refreshToken: string, tenantId: string) => {
     setLoading(true);
     localStorage.setItem('authToken', token);
+    localStorage.setItem('refreshToken', refreshToken);
+    localStorage.setItem('tenantId', tenantId);
     initializeSession(token);
   };
 
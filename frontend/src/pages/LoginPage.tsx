@@ -36,7 +36,7 @@ const LoginPage = () => {
 
       const response = await loginUser(credentials, tenantId);
       const token = response.token;
-      login(token);
+      login(token, response.refresh_token, tenantId);
 
       message.success('Login berhasil!');
       const role = getRoleFromToken(token);